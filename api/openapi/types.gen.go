@@ -71,7 +71,13 @@ const (
 
 // Error defines model for Error.
 type Error struct {
-	Message string `json:"message"`
+	// Code Стабильный код ошибки, по которому клиент может выбрать реакцию
+	Code     string  `json:"code"`
+	Detail   *string `json:"detail,omitempty"`
+	Instance *string `json:"instance,omitempty"`
+	Status   int     `json:"status"`
+	Title    string  `json:"title"`
+	Type     string  `json:"type"`
 }
 
 // PVZ defines model for PVZ.
@@ -406,34 +412,38 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/9xZ724TSRJ/lVHffeCkASfHffK3u+M4cUK6iONYCRShwe44A54/9LSza5Cl2N6FRWSX",
-	"1QoJCS1iWV5gcGJinHjyCtVvtKrqGXvGHsdOYmUNn2zPVHdXVf+q6lflx6zkOb7nclcGrPiYBaVN7lj0",
-	"9V9CeAK/+MLzuZA2p8cODwKrwvGrrPucFVkghe1WWKNhMsEf1mzBy6x4Zyi4biaC3r37vCRZw2Rrt25P",
-	"7lyyZR0/uVtzcAP4BSLVhD50IGQmg3cQwgD6qnUR3kJXtaCrtuGDaqtt2MX3ryGEfZRRO6lDE+1MZpdx",
-	"9w1POJZkRVar2WWWIyZ4xQ6ksKTtuVcsyTOLypbkF6Xt8MmVY+aTNbm2C69cK8lJ+3Hvm7j1nAeewKIS",
-	"99Gca/PJ6weji1A/wAF00fNqGyIYQA/6+koi2IMufIS95OcH1YZOrv/H3ENvs6rlOetG8v4c3eVvPZrT",
-	"UYG0ZC1Iu8p27/rCqwgeBMxkpaoX8Nm+GFqSnD3cOc8lN70H3M0JP5P9P+A5Acsdy65mzNFPzoAnr5rB",
-	"B3f8qlfnqL/jlbmwpCdmW51oQbtNGoru5aWasGX9f5iUtDH3uCW4+HtNbo5+XU30/c9XN9F1JM2K8duR",
-	"AZtS+qyBG9vuhkcQ4kFJ2DG+MMFgRulATzUN2IMD9cJQbThS2xBCh0JgAD31woC38DO8MqBn0MsedOEQ",
-	"+hDBJ0O1IMJ8RYHSwbNtWSVlrNID7paNgIstu4Su2uIi0AevXlq5tIKO9XzuWr7NiuwyPTKZb8lNMrxQ",
-	"rjlO/bpXsXUoeAFlELxoKwlttuYF8spITvubB/IfXpkya8lzJXdpoeX7VbtESwv3Ax1fOvlPImgx9z3t",
-	"njNiUtQ4PQh8zw308X9dWTmR8n8WfIMV2Z8Ko9JWiOtaQQcPHTp2+e9VE46gq76HAYR4ySF08Dbpgvch",
-	"VE/w7vGW/rZAfXSRzdPnDXShQ4AcqOfwyaDShnCLVFNHR81xLFFH2bcQwYFqq6caotA1qDo2YzRGsAuR",
-	"hmafJELaoFCdjabFAukEqci3guBrT5Rn84xki+GKLwNjq+eOsa6hIaRa8U8s6zDQP8Yh91Oe5gYcERJ3",
-	"YD9Ogy3oYh7VePM18wmOh9xaIrUo1M1fz8+R+GilTgfVxUEj4aJ54PgtqWSIgwg+jIrgciRBA3pwgDV4",
-	"gJDFWOqrFvSgAwMqxZna3NM6Xz4HnV+icqqFzGGkb1c9055L0RpWvJMlNHfWG+uZIHuZ9XuS2ROGERrQ",
-	"oUwPfdVWz1Rb/ZixWrWNCySOEdmHaEhqmhAhpFUb9mJQR9CJac1f4ljdeoQuqPCcKP03l2tbjyjlCsvh",
-	"kouAbJm4vFA9hZBOj/PdHqWEEL/00DPUsUVUkZCVsSJ7WOOizkzmWo4OIktI6sLM1MXM145NKPSajuqq",
-	"p6dWh7vlRSnzBiI4RGgbhJZtSrU99UQ9n3K2j810+uAy37BqVcmKqyZzbNd2MGmtDs+2XckrXEz1xAH0",
-	"KNsjS+ggP9DJ7hCRpkGGoRWOqQfdKepVbceWU/RbMZljfaMVvLwyQ9v1M5ZmW3InyK0CM7PhrduZdjk4",
-	"brtULRuKzJVqhyZbQlj1zIGz9hh1w41GTmeY3XcOicnk9Q6OsP1BphjngxOmrBwq2oz37GOk6dZJNQ31",
-	"LeZvtaPBhfwBupS1dZ4i6jCWw3XHBSHsQo9COF5EfHE6n6BUdVoqMRMv51ywkyPH7i1xK0Swr2nbsnQq",
-	"n2HZfTfyIiE49m5uLYVDzQUJxJoQR9AZFdHCY2J6jQLNgu5WrUDezcT7scBdw7X/xJXXrUCOwn+i9FJG",
-	"9i25maoX8SgpC87cypXPh8+ciedNZTlwToV9qK+zr7bVc6zWS8Y+jzKqqjZ8RMTkaPyZBcGrlAUUBEc0",
-	"jUeKgKSRcnWkWkOZSco9Ngwjsoo8gjylvkvXl0yklHmVyzhU/NSwfGagXKGFGClJsf1D42RqP0W8O1ym",
-	"Xsqcs4sa67nGL3g4Mx2aN5pnfGbwf5+2IQ/+u7oGZBu0QXrWNmzSerCfatNimpNx64Xr167+1zRO26xl",
-	"Gev0QLkxkjvv4crYFGQ5xh8nKUJpbrV0RYi6OLVDcZmtPTSUSxvyZTCyQTzYnlVzLpw+pCp2IOP/044J",
-	"qFhquSbki/6LbniUeZZ/cRYXt/RHZ34blDd+3lnKxig7T/+VSkovGbbMnqc3Gr8HAAD//82HIsO8IQAA",
+	"H4sIAAAAAAAC/9xZbW8bxxH+K4dtPzjoJaTqACn4rYnrIoWBCq7rAjYEYcVbyxfzXry3VEMbBPjSxgms",
+	"RkURIEDQ1FXzB06ULmIo8fQXZv9RMbt3xzvyKFIvUGh/kni3u/Oyz8wzM/eS1D3H91zmioDUXpKg/pQ5",
+	"VP37O849jv9YLKhz2xe255Iagf/AGQxlF0IYwxAiA2L5JQzhAEYwNGBgyL9BLDtwCqHsQWTcv/uJ8dFv",
+	"qh8Zt6jvN+w6xXMqPve2Gsz51WeB575HTOJzz2dc2EyJrnsWK5G8L3sQwgEM4UTuwli+hp8MGEEMRwUl",
+	"TAPOIFZvZE/pEsOp7OODE9QYxrJnwCnE8CNE+O9AvoYD2UGF5a4hOxBBCCP5BQzl18Qk7HPq+A1GaoSz",
+	"OlPabNIGZ9RqbXo+c4lJRMvH94HgtrtN2iaxmKB2A22Y7EYV3sC/4FtD9lG0AZHsKpFK0ZHsyNdoodwz",
+	"4Ex2lKqnMIKwTIDtBoK6deWmmZeBoKIZFKR/WK1mp9iuYNuM40phiwYrqvkxtYz77HmTBaJMsH6Q30C3",
+	"vKaobTWo+2x2Q9sknD1v2pxZpPZYv03FZoqa+sY3st3e1mesLlDc+sNHKG0KH7Zo4V/mNh08Ff4NsezC",
+	"CAbKWbCvwDmSvffhDV4xRLIDB7IvO3CI77+DEI5xjdwlGzMqm8S28PQnHneoIDXSbNpWmSs427YDwRWi",
+	"71DBCpssKtj7wnbYQp8oa0pt557VrItZ+/HsB7aztMALWJQA/NPl1mdgSC5C/gNOIELPq6jDBKHxi0EK",
+	"EfwIR+nPA9mHQan/yyGTV63MWffT9zfoLn/nxZKOykVk4irb3fS5t81ZoPDf8AK22BeZJans7OQylzzw",
+	"njG3NEH8OWB81k/MSXJWZo5+cgU8eY0CPpjjN7wWQ/0dz2KcCo8vtjrVQp02ayi6l9Wb3BatPyF9aWO2",
+	"GOWM/7Ypnk5+3U31/cNfHqDr1GpSS95ODHgqhE/abZVmn3hlTKQyygB50IAjOJF7mNMxaYcwUCEwhqHc",
+	"S/M9EmM+o8fwk4HUhPlKBcogy4k1skXrz5hrGQHjO3YdXbXDeKAFr31Q/aCKjkXeob5NauS2emQSn4qn",
+	"yvCK1XSc1j1v29ah4AUqg+BF0zS0yboXiDuTddrfLBAfe1ZLM7ArmKs25mkb6XpSJswi6Hrue949F5YJ",
+	"3mTqQeB7bqDF/7pavZDyv+TsCamRX1QmRVBFvw0qOniU0KnL/0F24Qwi+SWMNVmHMEgKjSHSiioc9vCW",
+	"PjxXn3wNtLxeuiwr0+t7iGCggJmURkhxCLtYdnWUNB2H8haufQMxnMi+fKWhCpGhWLKboDKGQ4g1REdq",
+	"RagOqDQWo+p6AXWBlOTTIPirx62ShDcnn2Q73g2srf1sWIsMDSXZS34izcNY/5iG3j/LLFAluyrrj5O0",
+	"2IMI86rGna8roeB86K2nq64Lfcvz+w0WQlqpy0F27dogm/i6FBz/S5kNcRDDwYQUVyspGthJIjePdR+I",
+	"TV8PhjCAsaLoAmcPte63b1D3b1BJ2cPKYqJ3JL/SnsyVPaT2uFjwPN5obxSC7pviPaQZP61AQtW49xRi",
+	"+/Ir2ZdfF6yXfeOW7CUROoI4K3q62LrKjuzDUQLyGAZJ2fNeErs7L9AF26wkan/PxPrOC5WKOXWYYDxQ",
+	"tsxcYihfQajbfp0Hj1SKCPGfIXpGdXQYaBhVyFHkeZPxFjGJSx0dVJQL1aWZuYtZrl2bUeg7JSqSry6t",
+	"DnOt61Lme5xvIMQNhZaOnszIL+TrObJ9ul0UbLEntNkQpLZmEsd2bQeT2NrsvGCOJ05gKF8l1cMA6wad",
+	"/E71qEUjYgzhlHoQzVGvYTu2mKNf1SQO/VwreLu6QNuNK1K2LZgTlLLCwuz48FGhnQ7OOy7HbdmSpVJv",
+	"ZjLlnLYKAhedMemW2+2SzrF47hIrZpPXfjImjGGU5IMLpqySErWbnDmCMDnTkF0cOKqJoAYX1hMQqewN",
+	"cRqY0VQu1x0ZhHAIQxhPNqk6cn59oVLVZUuLhXi5YQJ/+Kj03lK3QgzHuoxbtU7mLabh/YlX9exce7uU",
+	"W9WE+kh5Iczm2IMJqVZeqkqwXVGzo80GDcRmIf7PBfI67v0Ed96jgZikgxkqVhkahws5/khGT0WwljJZ",
+	"eb185cy8bGorgXd+sK6vMxu8r2h1WvgWkH07KNH8LQ2Kb3OWqKA4w7NVCYFFpcrl+Q8ks6X51DBNFbNY",
+	"ZyiPyb/n+acQORZrMJGEjp8bti8MnDtqI0ZOSsY/a9zM7b9UXR6uYu9lLtl1TfVo0xedzV4zMyfzkLc0",
+	"HH7I21IWDoeaI4oN3Tg/s8uauiEc59o6iGbde+vep3f/aBqXbe6KFe78wLk/WXfTw5mpKcpqjE8uQlL5",
+	"WmxlSSr7lI0ILXDT7Ofsd6qCGycD80WcdOvyIYZfmRlfFGDJqtWavF/3J8BMlHmVr0TXF8fqQ2p5G1U2",
+	"zt5d6caqOKf/r6KaYTq0WTynb7f/PwAxB8lDTiQAAA==",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
@@ -165,6 +165,383 @@ func (x *PVZ) GetRegistrationDate() *timestamppb.Timestamp {
 	return nil
 }
 
+// GetPVZWithReceptionsRequest содержит период и параметры пагинации.
+// Если задан page и не задан cursor, используется постраничная пагинация,
+// иначе keyset-пагинация по курсору (пустой cursor - первая страница).
+type GetPVZWithReceptionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StartDate     *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate       *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	Page          int32                  `protobuf:"varint,3,opt,name=page,proto3" json:"page,omitempty"`
+	Limit         int32                  `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	Cursor        string                 `protobuf:"bytes,5,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPVZWithReceptionsRequest) Reset() {
+	*x = GetPVZWithReceptionsRequest{}
+	mi := &file_api_proto_pvz_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPVZWithReceptionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPVZWithReceptionsRequest) ProtoMessage() {}
+
+func (x *GetPVZWithReceptionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_pvz_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPVZWithReceptionsRequest.ProtoReflect.Descriptor instead.
+func (*GetPVZWithReceptionsRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_pvz_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetPVZWithReceptionsRequest) GetStartDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartDate
+	}
+	return nil
+}
+
+func (x *GetPVZWithReceptionsRequest) GetEndDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndDate
+	}
+	return nil
+}
+
+func (x *GetPVZWithReceptionsRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *GetPVZWithReceptionsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *GetPVZWithReceptionsRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+// GetPVZWithReceptionsResponse содержит страницу ПВЗ и курсор следующей страницы
+type GetPVZWithReceptionsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Pvzs  []*PVZWithReceptions   `protobuf:"bytes,1,rep,name=pvzs,proto3" json:"pvzs,omitempty"`
+	// next_cursor пуст, если страница последняя или используется page
+	NextCursor    string `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPVZWithReceptionsResponse) Reset() {
+	*x = GetPVZWithReceptionsResponse{}
+	mi := &file_api_proto_pvz_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPVZWithReceptionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPVZWithReceptionsResponse) ProtoMessage() {}
+
+func (x *GetPVZWithReceptionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_pvz_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPVZWithReceptionsResponse.ProtoReflect.Descriptor instead.
+func (*GetPVZWithReceptionsResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_pvz_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetPVZWithReceptionsResponse) GetPvzs() []*PVZWithReceptions {
+	if x != nil {
+		return x.Pvzs
+	}
+	return nil
+}
+
+func (x *GetPVZWithReceptionsResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+// PVZWithReceptions представляет ПВЗ с его приемками
+type PVZWithReceptions struct {
+	state         protoimpl.MessageState   `protogen:"open.v1"`
+	Pvz           *PVZ                     `protobuf:"bytes,1,opt,name=pvz,proto3" json:"pvz,omitempty"`
+	Receptions    []*ReceptionWithProducts `protobuf:"bytes,2,rep,name=receptions,proto3" json:"receptions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PVZWithReceptions) Reset() {
+	*x = PVZWithReceptions{}
+	mi := &file_api_proto_pvz_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PVZWithReceptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PVZWithReceptions) ProtoMessage() {}
+
+func (x *PVZWithReceptions) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_pvz_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PVZWithReceptions.ProtoReflect.Descriptor instead.
+func (*PVZWithReceptions) Descriptor() ([]byte, []int) {
+	return file_api_proto_pvz_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *PVZWithReceptions) GetPvz() *PVZ {
+	if x != nil {
+		return x.Pvz
+	}
+	return nil
+}
+
+func (x *PVZWithReceptions) GetReceptions() []*ReceptionWithProducts {
+	if x != nil {
+		return x.Receptions
+	}
+	return nil
+}
+
+// ReceptionWithProducts представляет приемку с товарами
+type ReceptionWithProducts struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reception     *Reception             `protobuf:"bytes,1,opt,name=reception,proto3" json:"reception,omitempty"`
+	Products      []*Product             `protobuf:"bytes,2,rep,name=products,proto3" json:"products,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReceptionWithProducts) Reset() {
+	*x = ReceptionWithProducts{}
+	mi := &file_api_proto_pvz_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReceptionWithProducts) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReceptionWithProducts) ProtoMessage() {}
+
+func (x *ReceptionWithProducts) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_pvz_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReceptionWithProducts.ProtoReflect.Descriptor instead.
+func (*ReceptionWithProducts) Descriptor() ([]byte, []int) {
+	return file_api_proto_pvz_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ReceptionWithProducts) GetReception() *Reception {
+	if x != nil {
+		return x.Reception
+	}
+	return nil
+}
+
+func (x *ReceptionWithProducts) GetProducts() []*Product {
+	if x != nil {
+		return x.Products
+	}
+	return nil
+}
+
+// Reception представляет приемку товаров
+type Reception struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	DateTime      *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=date_time,json=dateTime,proto3" json:"date_time,omitempty"`
+	PvzId         string                 `protobuf:"bytes,3,opt,name=pvz_id,json=pvzId,proto3" json:"pvz_id,omitempty"`
+	Status        string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Reception) Reset() {
+	*x = Reception{}
+	mi := &file_api_proto_pvz_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Reception) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Reception) ProtoMessage() {}
+
+func (x *Reception) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_pvz_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Reception.ProtoReflect.Descriptor instead.
+func (*Reception) Descriptor() ([]byte, []int) {
+	return file_api_proto_pvz_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Reception) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Reception) GetDateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DateTime
+	}
+	return nil
+}
+
+func (x *Reception) GetPvzId() string {
+	if x != nil {
+		return x.PvzId
+	}
+	return ""
+}
+
+func (x *Reception) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+// Product представляет товар
+type Product struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	DateTime      *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=date_time,json=dateTime,proto3" json:"date_time,omitempty"`
+	Type          string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	ReceptionId   string                 `protobuf:"bytes,4,opt,name=reception_id,json=receptionId,proto3" json:"reception_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Product) Reset() {
+	*x = Product{}
+	mi := &file_api_proto_pvz_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Product) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Product) ProtoMessage() {}
+
+func (x *Product) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_pvz_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Product.ProtoReflect.Descriptor instead.
+func (*Product) Descriptor() ([]byte, []int) {
+	return file_api_proto_pvz_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Product) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Product) GetDateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DateTime
+	}
+	return nil
+}
+
+func (x *Product) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Product) GetReceptionId() string {
+	if x != nil {
+		return x.ReceptionId
+	}
+	return ""
+}
+
 var File_api_proto_pvz_proto protoreflect.FileDescriptor
 
 const file_api_proto_pvz_proto_rawDesc = "" +
@@ -176,10 +553,40 @@ const file_api_proto_pvz_proto_rawDesc = "" +
 	"\x03PVZ\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
 	"\x04city\x18\x02 \x01(\tR\x04city\x12G\n" +
-	"\x11registration_date\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x10registrationDate2J\n" +
+	"\x11registration_date\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x10registrationDate\"\xd1\x01\n" +
+	"\x1bGetPVZWithReceptionsRequest\x129\n" +
+	"\n" +
+	"start_date\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\tstartDate\x125\n" +
+	"\bend_date\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\aendDate\x12\x12\n" +
+	"\x04page\x18\x03 \x01(\x05R\x04page\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06cursor\x18\x05 \x01(\tR\x06cursor\"k\n" +
+	"\x1cGetPVZWithReceptionsResponse\x12*\n" +
+	"\x04pvzs\x18\x01 \x03(\v2\x16.pvz.PVZWithReceptionsR\x04pvzs\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\"k\n" +
+	"\x11PVZWithReceptions\x12\x1a\n" +
+	"\x03pvz\x18\x01 \x01(\v2\b.pvz.PVZR\x03pvz\x12:\n" +
+	"\n" +
+	"receptions\x18\x02 \x03(\v2\x1a.pvz.ReceptionWithProductsR\n" +
+	"receptions\"o\n" +
+	"\x15ReceptionWithProducts\x12,\n" +
+	"\treception\x18\x01 \x01(\v2\x0e.pvz.ReceptionR\treception\x12(\n" +
+	"\bproducts\x18\x02 \x03(\v2\f.pvz.ProductR\bproducts\"\x83\x01\n" +
+	"\tReception\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x127\n" +
+	"\tdate_time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\bdateTime\x12\x15\n" +
+	"\x06pvz_id\x18\x03 \x01(\tR\x05pvzId\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\"\x89\x01\n" +
+	"\aProduct\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x127\n" +
+	"\tdate_time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\bdateTime\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\x12!\n" +
+	"\freception_id\x18\x04 \x01(\tR\vreceptionId2\xa9\x01\n" +
 	"\n" +
 	"PVZService\x12<\n" +
-	"\tGetAllPVZ\x12\x15.pvz.GetAllPVZRequest\x1a\x16.pvz.GetAllPVZResponse\"\x00B\x1aZ\x18avito-pvz-test/api/protob\x06proto3"
+	"\tGetAllPVZ\x12\x15.pvz.GetAllPVZRequest\x1a\x16.pvz.GetAllPVZResponse\"\x00\x12]\n" +
+	"\x14GetPVZWithReceptions\x12 .pvz.GetPVZWithReceptionsRequest\x1a!.pvz.GetPVZWithReceptionsResponse\"\x00B\x1aZ\x18avito-pvz-test/api/protob\x06proto3"
 
 var (
 	file_api_proto_pvz_proto_rawDescOnce sync.Once
@@ -193,23 +600,40 @@ func file_api_proto_pvz_proto_rawDescGZIP() []byte {
 	return file_api_proto_pvz_proto_rawDescData
 }
 
-var file_api_proto_pvz_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_api_proto_pvz_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
 var file_api_proto_pvz_proto_goTypes = []any{
-	(*GetAllPVZRequest)(nil),      // 0: pvz.GetAllPVZRequest
-	(*GetAllPVZResponse)(nil),     // 1: pvz.GetAllPVZResponse
-	(*PVZ)(nil),                   // 2: pvz.PVZ
-	(*timestamppb.Timestamp)(nil), // 3: google.protobuf.Timestamp
+	(*GetAllPVZRequest)(nil),             // 0: pvz.GetAllPVZRequest
+	(*GetAllPVZResponse)(nil),            // 1: pvz.GetAllPVZResponse
+	(*PVZ)(nil),                          // 2: pvz.PVZ
+	(*GetPVZWithReceptionsRequest)(nil),  // 3: pvz.GetPVZWithReceptionsRequest
+	(*GetPVZWithReceptionsResponse)(nil), // 4: pvz.GetPVZWithReceptionsResponse
+	(*PVZWithReceptions)(nil),            // 5: pvz.PVZWithReceptions
+	(*ReceptionWithProducts)(nil),        // 6: pvz.ReceptionWithProducts
+	(*Reception)(nil),                    // 7: pvz.Reception
+	(*Product)(nil),                      // 8: pvz.Product
+	(*timestamppb.Timestamp)(nil),        // 9: google.protobuf.Timestamp
 }
 var file_api_proto_pvz_proto_depIdxs = []int32{
-	2, // 0: pvz.GetAllPVZResponse.pvzs:type_name -> pvz.PVZ
-	3, // 1: pvz.PVZ.registration_date:type_name -> google.protobuf.Timestamp
-	0, // 2: pvz.PVZService.GetAllPVZ:input_type -> pvz.GetAllPVZRequest
-	1, // 3: pvz.PVZService.GetAllPVZ:output_type -> pvz.GetAllPVZResponse
-	3, // [3:4] is the sub-list for method output_type
-	2, // [2:3] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	2,  // 0: pvz.GetAllPVZResponse.pvzs:type_name -> pvz.PVZ
+	9,  // 1: pvz.PVZ.registration_date:type_name -> google.protobuf.Timestamp
+	9,  // 2: pvz.GetPVZWithReceptionsRequest.start_date:type_name -> google.protobuf.Timestamp
+	9,  // 3: pvz.GetPVZWithReceptionsRequest.end_date:type_name -> google.protobuf.Timestamp
+	5,  // 4: pvz.GetPVZWithReceptionsResponse.pvzs:type_name -> pvz.PVZWithReceptions
+	2,  // 5: pvz.PVZWithReceptions.pvz:type_name -> pvz.PVZ
+	6,  // 6: pvz.PVZWithReceptions.receptions:type_name -> pvz.ReceptionWithProducts
+	7,  // 7: pvz.ReceptionWithProducts.reception:type_name -> pvz.Reception
+	8,  // 8: pvz.ReceptionWithProducts.products:type_name -> pvz.Product
+	9,  // 9: pvz.Reception.date_time:type_name -> google.protobuf.Timestamp
+	9,  // 10: pvz.Product.date_time:type_name -> google.protobuf.Timestamp
+	0,  // 11: pvz.PVZService.GetAllPVZ:input_type -> pvz.GetAllPVZRequest
+	3,  // 12: pvz.PVZService.GetPVZWithReceptions:input_type -> pvz.GetPVZWithReceptionsRequest
+	1,  // 13: pvz.PVZService.GetAllPVZ:output_type -> pvz.GetAllPVZResponse
+	4,  // 14: pvz.PVZService.GetPVZWithReceptions:output_type -> pvz.GetPVZWithReceptionsResponse
+	13, // [13:15] is the sub-list for method output_type
+	11, // [11:13] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
 }
 
 func init() { file_api_proto_pvz_proto_init() }
@@ -223,7 +647,7 @@ func file_api_proto_pvz_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_proto_pvz_proto_rawDesc), len(file_api_proto_pvz_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   3,
+			NumMessages:   9,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,7 +19,8 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	PVZService_GetAllPVZ_FullMethodName = "/pvz.PVZService/GetAllPVZ"
+	PVZService_GetAllPVZ_FullMethodName            = "/pvz.PVZService/GetAllPVZ"
+	PVZService_GetPVZWithReceptions_FullMethodName = "/pvz.PVZService/GetPVZWithReceptions"
 )
 
 // PVZServiceClient is the client API for PVZService service.
@@ -30,6 +31,8 @@ const (
 type PVZServiceClient interface {
 	// GetAllPVZ возвращает список всех ПВЗ
 	GetAllPVZ(ctx context.Context, in *GetAllPVZRequest, opts ...grpc.CallOption) (*GetAllPVZResponse, error)
+	// GetPVZWithReceptions возвращает ПВЗ с приемками и товарами за период
+	GetPVZWithReceptions(ctx context.Context, in *GetPVZWithReceptionsRequest, opts ...grpc.CallOption) (*GetPVZWithReceptionsResponse, error)
 }
 
 type pVZServiceClient struct {
@@ -50,6 +53,16 @@ func (c *pVZServiceClient) GetAllPVZ(ctx context.Context, in *GetAllPVZRequest,
 	return out, nil
 }
 
+func (c *pVZServiceClient) GetPVZWithReceptions(ctx context.Context, in *GetPVZWithReceptionsRequest, opts ...grpc.CallOption) (*GetPVZWithReceptionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetPVZWithReceptionsResponse)
+	err := c.cc.Invoke(ctx, PVZService_GetPVZWithReceptions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // PVZServiceServer is the server API for PVZService service.
 // All implementations must embed UnimplementedPVZServiceServer
 // for forward compatibility.
@@ -58,6 +71,8 @@ func (c *pVZServiceClient) GetAllPVZ(ctx context.Context, in *GetAllPVZRequest,
 type PVZServiceServer interface {
 	// GetAllPVZ возвращает список всех ПВЗ
 	GetAllPVZ(context.Context, *GetAllPVZRequest) (*GetAllPVZResponse, error)
+	// GetPVZWithReceptions возвращает ПВЗ с приемками и товарами за период
+	GetPVZWithReceptions(context.Context, *GetPVZWithReceptionsRequest) (*GetPVZWithReceptionsResponse, error)
 	mustEmbedUnimplementedPVZServiceServer()
 }
 
@@ -71,6 +86,9 @@ type UnimplementedPVZServiceServer struct{}
 func (UnimplementedPVZServiceServer) GetAllPVZ(context.Context, *GetAllPVZRequest) (*GetAllPVZResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetAllPVZ not implemented")
 }
+func (UnimplementedPVZServiceServer) GetPVZWithReceptions(context.Context, *GetPVZWithReceptionsRequest) (*GetPVZWithReceptionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPVZWithReceptions not implemented")
+}
 func (UnimplementedPVZServiceServer) mustEmbedUnimplementedPVZServiceServer() {}
 func (UnimplementedPVZServiceServer) testEmbeddedByValue()                    {}
 
@@ -110,6 +128,24 @@ func _PVZService_GetAllPVZ_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PVZService_GetPVZWithReceptions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPVZWithReceptionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PVZServiceServer).GetPVZWithReceptions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PVZService_GetPVZWithReceptions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PVZServiceServer).GetPVZWithReceptions(ctx, req.(*GetPVZWithReceptionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // PVZService_ServiceDesc is the grpc.ServiceDesc for PVZService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -121,6 +157,10 @@ var PVZService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetAllPVZ",
 			Handler:    _PVZService_GetAllPVZ_Handler,
 		},
+		{
+			MethodName: "GetPVZWithReceptions",
+			Handler:    _PVZService_GetPVZWithReceptions_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "api/proto/pvz.proto",
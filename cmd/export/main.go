@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/avito/pvz/internal/config"
+	"github.com/avito/pvz/internal/repository/postgres"
+	"github.com/avito/pvz/internal/service/export"
+	"github.com/jmoiron/sqlx"
+)
+
+// Утилита выгрузки приемок с товарами за период в CSV или NDJSON.
+//
+//	go run ./cmd/export -start 2024-01-01T00:00:00Z -end 2024-02-01T00:00:00Z -format ndjson -out receptions.ndjson
+func main() {
+	start := flag.String("start", "", "начало периода в формате RFC3339")
+	end := flag.String("end", "", "конец периода в формате RFC3339")
+	formatName := flag.String("format", string(export.FormatCSV), "формат выгрузки: csv или ndjson")
+	out := flag.String("out", "", "файл для выгрузки, по умолчанию stdout")
+	flag.Parse()
+
+	startDate, err := time.Parse(time.RFC3339, *start)
+	if err != nil {
+		log.Fatalf("Invalid -start: %v", err)
+	}
+	endDate, err := time.Parse(time.RFC3339, *end)
+	if err != nil {
+		log.Fatalf("Invalid -end: %v", err)
+	}
+	format, err := export.ParseFormat(*formatName)
+	if err != nil {
+		log.Fatalf("Invalid -format: %v", err)
+	}
+
+	// Загружаем конфигурацию
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	db, err := postgres.New(cfg.Database)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	w := os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			log.Fatalf("Failed to create output file: %v", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	// Прерываем выгрузку по сигналу
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	receptionRepo := postgres.NewReceptionRepository(sqlx.NewDb(db.DB, "postgres"))
+	if err := export.New(receptionRepo).Export(ctx, startDate, endDate, format, w); err != nil {
+		log.Fatalf("Failed to export receptions: %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"fmt"
 	"log"
 	"net"
 	"os"
@@ -17,27 +18,9 @@ func main() {
 		log.Printf("Warning: .env file not found")
 	}
 
-	// Создаем конфигурацию
-	cfg := &app.Config{
-		Server: struct {
-			HTTP struct {
-				Host string
-				Port int
-			}
-			GRPC struct {
-				Host string
-				Port int
-			}
-		}{
-			GRPC: struct {
-				Host string
-				Port int
-			}{
-				Host: "localhost",
-				Port: 3000,
-			},
-		},
-	}
+	// Создаем конфигурацию: gRPC-сервер проверяет те же токены, что и HTTP-сервер,
+	// поэтому читает те же переменные окружения
+	cfg := app.LoadConfig()
 
 	// Создаем gRPC сервер
 	server, err := app.NewGRPCServer(cfg)
@@ -47,7 +30,7 @@ func main() {
 
 	// Запускаем сервер в горутине
 	go func() {
-		listener, err := net.Listen("tcp", ":3000")
+		listener, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.Server.GRPC.Port))
 		if err != nil {
 			log.Fatalf("Failed to listen: %v", err)
 		}
@@ -5,7 +5,6 @@ import (
 	"log"
 	"os"
 	"os/signal"
-	"strconv"
 	"syscall"
 
 	"github.com/avito/pvz/internal/app"
@@ -19,62 +18,7 @@ func main() {
 	}
 
 	// Создаем конфигурацию
-	cfg := &app.Config{
-		Server: struct {
-			HTTP struct {
-				Host string
-				Port int
-			}
-			GRPC struct {
-				Host string
-				Port int
-			}
-		}{
-			HTTP: struct {
-				Host string
-				Port int
-			}{
-				Host: getEnv("HTTP_HOST", "localhost"),
-				Port: getEnvAsInt("HTTP_PORT", 8080),
-			},
-			GRPC: struct {
-				Host string
-				Port int
-			}{
-				Host: getEnv("GRPC_HOST", "localhost"),
-				Port: getEnvAsInt("GRPC_PORT", 9090),
-			},
-		},
-		Database: struct {
-			Host     string
-			Port     int
-			User     string
-			Password string
-			DBName   string
-			SSLMode  string
-		}{
-			Host:     getEnv("DB_HOST", "localhost"),
-			Port:     getEnvAsInt("DB_PORT", 5432),
-			User:     getEnv("DB_USER", "postgres"),
-			Password: getEnv("DB_PASSWORD", "postgres"),
-			DBName:   getEnv("DB_NAME", "avito_pvz"),
-			SSLMode:  getEnv("DB_SSLMODE", "disable"),
-		},
-		JWT: struct {
-			Secret     string
-			Expiration string
-		}{
-			Secret:     getEnv("JWT_SECRET", "your-secret-key"),
-			Expiration: getEnv("JWT_EXPIRATION", "24h"),
-		},
-		Logging: struct {
-			Level  string
-			Format string
-		}{
-			Level:  getEnv("LOG_LEVEL", "info"),
-			Format: getEnv("LOG_FORMAT", "json"),
-		},
-	}
+	cfg := app.LoadConfig()
 
 	// Создаем HTTP-сервер
 	server, err := app.NewHTTPServer(cfg)
@@ -102,21 +46,3 @@ func main() {
 		log.Fatalf("Failed to stop HTTP server: %v", err)
 	}
 }
-
-// getEnv получает значение переменной окружения или возвращает значение по умолчанию
-func getEnv(key, defaultValue string) string {
-	if value, exists := os.LookupEnv(key); exists {
-		return value
-	}
-	return defaultValue
-}
-
-// getEnvAsInt получает значение переменной окружения как int или возвращает значение по умолчанию
-func getEnvAsInt(key string, defaultValue int) int {
-	if value, exists := os.LookupEnv(key); exists {
-		if intValue, err := strconv.Atoi(value); err == nil {
-			return intValue
-		}
-	}
-	return defaultValue
-}
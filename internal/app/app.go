@@ -6,15 +6,20 @@ import (
 	"net/http"
 
 	"github.com/avito/pvz/internal/config"
+	"github.com/avito/pvz/internal/domain/assignment"
 	"github.com/avito/pvz/internal/domain/audit"
+	"github.com/avito/pvz/internal/domain/outbox"
 	"github.com/avito/pvz/internal/domain/pvz"
+	"github.com/avito/pvz/internal/domain/rbac"
 	"github.com/avito/pvz/internal/domain/transaction"
 	"github.com/avito/pvz/internal/domain/user"
 	httphandler "github.com/avito/pvz/internal/handler/http"
+	httpmiddleware "github.com/avito/pvz/internal/handler/http/middleware"
 	"github.com/avito/pvz/internal/middleware"
 	"github.com/avito/pvz/internal/repository/postgres"
 	servicePVZ "github.com/avito/pvz/internal/service/pvz"
 	"github.com/avito/pvz/internal/service/reception"
+	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"github.com/jmoiron/sqlx"
 )
@@ -43,19 +48,24 @@ func New(cfg *config.Config) (*App, error) {
 	receptionRepo := postgres.NewReceptionRepository(sqlxDB)
 	productRepo := postgres.NewProductRepository(sqlxDB)
 	auditLog := postgres.NewAuditLog(sqlxDB)
+	auditRepo := postgres.NewAuditRepository(sqlxDB)
 
 	// Инициализация менеджера транзакций
 	txManager := transaction.NewManager(sqlxDB)
 
 	// Создание сервисов
-	pvzService := servicePVZ.New(pvzRepo, userRepo, txManager, auditLog, nil)
-	receptionService := reception.New(receptionRepo, pvzRepo, txManager, productRepo)
+	bus := newEventBus(auditLog, nil)
+	pvzService := servicePVZ.New(pvzRepo, userRepo, txManager, bus, auditRepo, nil)
+	// Устаревшее приложение работает без авторизации, закрепления за ПВЗ не проверяются
+	receptionService := reception.New(receptionRepo, pvzRepo, txManager, productRepo, bus, outbox.Discard, auditRepo, assignment.AllowAll)
 
 	// Создаем роутер
 	router := mux.NewRouter()
 
 	// Добавляем middleware
 	router.Use(middleware.MetricsMiddleware)
+	router.Use(httpmiddleware.Language)
+	router.Use(moderatorPermissions(userRepo, rbac.MustNewAuthorizer(rbac.DefaultPolicy)))
 
 	// Регистрируем обработчики
 	httphandler.RegisterPVZHandlers(router, pvzService)
@@ -91,5 +101,21 @@ func (a *App) Stop(ctx context.Context) error {
 
 // NewPVZService создает новый экземпляр сервиса PVZ
 func (a *App) NewPVZService(pvzRepo pvz.Repository, userRepo user.Repository, txManager transaction.Manager, auditLog audit.AuditLog) *servicePVZ.Service {
-	return servicePVZ.New(pvzRepo, userRepo, txManager, auditLog, nil)
+	return servicePVZ.New(pvzRepo, userRepo, txManager, newEventBus(auditLog, nil), audit.Discard, nil)
+}
+
+// moderatorPermissions добавляет в контекст разрешения роли пользователя из
+// заголовка X-Moderator-ID. Устаревшее приложение не выпускает токены, а сервис
+// ПВЗ проверяет права по разрешениям вызывающего из контекста.
+func moderatorPermissions(users user.Repository, authz *rbac.Authorizer) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if id, err := uuid.Parse(r.Header.Get("X-Moderator-ID")); err == nil {
+				if u, err := users.GetByID(r.Context(), id); err == nil {
+					r = r.WithContext(rbac.WithPermissions(r.Context(), authz.Permissions(u.Role)))
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
 }
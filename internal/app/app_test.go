@@ -4,11 +4,13 @@ import (
 	"context"
 	"database/sql"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
 	"github.com/avito/pvz/internal/config"
 	"github.com/avito/pvz/internal/domain/pvz"
+	"github.com/avito/pvz/internal/domain/rbac"
 	"github.com/avito/pvz/internal/domain/user"
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
@@ -87,6 +89,18 @@ func (m *MockPVZRepository) GetWithReceptions(ctx context.Context, from, to time
 	return args.Get(0).([]*pvz.PVZWithReceptions), args.Error(1)
 }
 
+func (m *MockPVZRepository) GetWithReceptionsByCursor(ctx context.Context, startDate, endDate time.Time, cursor *pvz.Cursor, limit int) ([]*pvz.PVZWithReceptions, *pvz.Cursor, error) {
+	args := m.Called(ctx, startDate, endDate, cursor, limit)
+	var next *pvz.Cursor
+	if c, ok := args.Get(1).(*pvz.Cursor); ok {
+		next = c
+	}
+	if args.Get(0) == nil {
+		return nil, next, args.Error(2)
+	}
+	return args.Get(0).([]*pvz.PVZWithReceptions), next, args.Error(2)
+}
+
 func (m *MockPVZRepository) GetByCity(ctx context.Context, city string) (*pvz.PVZ, error) {
 	args := m.Called(ctx, city)
 	return args.Get(0).(*pvz.PVZ), args.Error(1)
@@ -97,6 +111,14 @@ func (m *MockPVZRepository) List(ctx context.Context, offset, limit int) ([]*pvz
 	return args.Get(0).([]*pvz.PVZ), args.Error(1)
 }
 
+func (m *MockPVZRepository) ListByFilter(ctx context.Context, filter pvz.ListFilter) ([]*pvz.PVZ, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*pvz.PVZ), args.Error(1)
+}
+
 // MockUserRepository представляет мок репозитория пользователей
 type MockUserRepository struct {
 	mock.Mock
@@ -147,8 +169,13 @@ type MockAuditLog struct {
 	mock.Mock
 }
 
-func (m *MockAuditLog) LogPVZCreation(ctx context.Context, pvzID uuid.UUID, userID uuid.UUID) error {
-	args := m.Called(ctx, pvzID, userID)
+func (m *MockAuditLog) LogLoginLockout(ctx context.Context, key string, userID uuid.UUID, lockedUntil time.Time) error {
+	args := m.Called(ctx, key, userID, lockedUntil)
+	return args.Error(0)
+}
+
+func (m *MockAuditLog) LogLoginUnlock(ctx context.Context, key string, userID, unlockedBy uuid.UUID) error {
+	args := m.Called(ctx, key, userID, unlockedBy)
 	return args.Error(0)
 }
 
@@ -256,6 +283,26 @@ func TestNewPVZService(t *testing.T) {
 	assert.NotNil(t, service)
 }
 
+func TestModeratorPermissions(t *testing.T) {
+	moderatorID := uuid.New()
+	users := new(MockUserRepository)
+	users.On("GetByID", mock.Anything, moderatorID).Return(&user.User{ID: moderatorID, Role: user.RoleAdmin}, nil)
+
+	var permissions rbac.Set
+	handler := moderatorPermissions(users, rbac.MustNewAuthorizer(rbac.DefaultPolicy))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		permissions = rbac.FromContext(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/pvz", nil)
+	req.Header.Set("X-Moderator-ID", moderatorID.String())
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	assert.True(t, permissions.Has(rbac.PVZCreate))
+
+	// Без заголовка запрос остается без разрешений
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/api/v1/pvz", nil))
+	assert.False(t, permissions.Has(rbac.PVZCreate))
+}
+
 func TestApp_StartStop(t *testing.T) {
 	cfg := &config.Config{
 		HTTP: struct{ Port int }{
@@ -0,0 +1,107 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/rbac"
+	sessionservice "github.com/avito/pvz/internal/service/session"
+	"github.com/avito/pvz/pkg/auth"
+)
+
+// envProduction окружение, в котором запрещены небезопасные настройки по умолчанию
+const envProduction = "production"
+
+// ErrDefaultJWTSecret возвращается при запуске в production с секретом из примеров конфигурации
+var ErrDefaultJWTSecret = errors.New("default jwt secret is not allowed in production, set JWT_SECRET")
+
+// newTokenService создает сервис токенов доступа по конфигурации.
+// Незаданные параметры берутся из auth.DefaultTokenConfig.
+func newTokenService(cfg *Config) (*auth.TokenService, error) {
+	tokenCfg := auth.DefaultTokenConfig
+	if cfg.JWT.Secret != "" {
+		tokenCfg.Secret = cfg.JWT.Secret
+	}
+	if cfg.JWT.Issuer != "" {
+		tokenCfg.Issuer = cfg.JWT.Issuer
+	}
+	if cfg.JWT.Audience != "" {
+		tokenCfg.Audience = cfg.JWT.Audience
+	}
+	if cfg.JWT.Expiration != "" {
+		ttl, err := time.ParseDuration(cfg.JWT.Expiration)
+		if err != nil {
+			return nil, fmt.Errorf("invalid jwt expiration: %w", err)
+		}
+		tokenCfg.TTL = ttl
+	}
+	if cfg.JWT.KeysDir != "" {
+		tokenCfg.KeysDir = cfg.JWT.KeysDir
+	}
+	if cfg.JWT.KeyGracePeriod != "" {
+		grace, err := time.ParseDuration(cfg.JWT.KeyGracePeriod)
+		if err != nil {
+			return nil, fmt.Errorf("invalid jwt key grace period: %w", err)
+		}
+		tokenCfg.KeyGracePeriod = grace
+	}
+	if cfg.JWT.ClockSkew != "" {
+		skew, err := time.ParseDuration(cfg.JWT.ClockSkew)
+		if err != nil {
+			return nil, fmt.Errorf("invalid jwt clock skew: %w", err)
+		}
+		tokenCfg.ClockSkew = skew
+	}
+
+	// При подписи ключами из каталога секрет не участвует в проверке токенов
+	if tokenCfg.KeysDir == "" && tokenCfg.Secret == auth.DefaultSecret {
+		if cfg.Env == envProduction {
+			return nil, ErrDefaultJWTSecret
+		}
+		log.Printf("Warning: using the default JWT secret, set JWT_SECRET before running in production")
+	}
+
+	tokens, err := auth.NewTokenService(tokenCfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token service: %w", err)
+	}
+	return tokens, nil
+}
+
+// newAuthorizer создает проверку прав по политике из cfg.RBAC.PolicyFile
+// или по rbac.DefaultPolicy, если файл не задан
+func newAuthorizer(cfg *Config) (*rbac.Authorizer, error) {
+	policy := rbac.DefaultPolicy
+	if cfg.RBAC.PolicyFile != "" {
+		var err error
+		policy, err = rbac.LoadPolicy(cfg.RBAC.PolicyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load rbac policy: %w", err)
+		}
+	}
+
+	authz, err := rbac.NewAuthorizer(policy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create authorizer: %w", err)
+	}
+	return authz, nil
+}
+
+// newSessionConfig возвращает параметры сессий по конфигурации.
+// Незаданные параметры берутся из sessionservice.DefaultConfig.
+func newSessionConfig(cfg *Config) (sessionservice.Config, error) {
+	sessionCfg := sessionservice.DefaultConfig
+	if cfg.JWT.RefreshExpiration != "" {
+		ttl, err := time.ParseDuration(cfg.JWT.RefreshExpiration)
+		if err != nil {
+			return sessionCfg, fmt.Errorf("invalid jwt refresh expiration: %w", err)
+		}
+		if ttl <= 0 {
+			return sessionCfg, fmt.Errorf("invalid jwt refresh expiration: %w", auth.ErrInvalidTTL)
+		}
+		sessionCfg.RefreshTTL = ttl
+	}
+	return sessionCfg, nil
+}
@@ -0,0 +1,92 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/rbac"
+	domainuser "github.com/avito/pvz/internal/domain/user"
+	sessionservice "github.com/avito/pvz/internal/service/session"
+	"github.com/avito/pvz/pkg/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTokenService(t *testing.T) {
+	tests := []struct {
+		name       string
+		env        string
+		secret     string
+		expiration string
+		clockSkew  string
+		wantErr    error
+		wantAnyErr bool
+	}{
+		{name: "секрет по умолчанию при разработке", env: "development"},
+		{name: "свой секрет в production", env: envProduction, secret: "prod-secret", expiration: "1h", clockSkew: "5s"},
+		{name: "секрет по умолчанию в production", env: envProduction, wantErr: ErrDefaultJWTSecret},
+		{name: "явно заданный секрет по умолчанию в production", env: envProduction, secret: auth.DefaultSecret, wantErr: ErrDefaultJWTSecret},
+		{name: "неверное время жизни", secret: "secret", expiration: "day", wantAnyErr: true},
+		{name: "отрицательное время жизни", secret: "secret", expiration: "-1h", wantErr: auth.ErrInvalidTTL},
+		{name: "неверное расхождение часов", secret: "secret", clockSkew: "soon", wantAnyErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{Env: tt.env}
+			cfg.JWT.Secret = tt.secret
+			cfg.JWT.Expiration = tt.expiration
+			cfg.JWT.ClockSkew = tt.clockSkew
+
+			tokens, err := newTokenService(cfg)
+			switch {
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
+			case tt.wantAnyErr:
+				assert.Error(t, err)
+			default:
+				require.NoError(t, err)
+				assert.NotNil(t, tokens)
+			}
+		})
+	}
+}
+
+func TestNewSessionConfig(t *testing.T) {
+	cfg := &Config{}
+	sessionCfg, err := newSessionConfig(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, sessionservice.DefaultConfig, sessionCfg)
+
+	cfg.JWT.RefreshExpiration = "168h"
+	sessionCfg, err = newSessionConfig(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, 168*time.Hour, sessionCfg.RefreshTTL)
+
+	cfg.JWT.RefreshExpiration = "week"
+	_, err = newSessionConfig(cfg)
+	assert.Error(t, err)
+
+	cfg.JWT.RefreshExpiration = "0s"
+	_, err = newSessionConfig(cfg)
+	assert.ErrorIs(t, err, auth.ErrInvalidTTL)
+}
+
+func TestNewAuthorizer(t *testing.T) {
+	cfg := &Config{}
+	authz, err := newAuthorizer(cfg)
+	require.NoError(t, err)
+	assert.True(t, authz.Can(domainuser.RoleAdmin, rbac.PVZCreate))
+
+	// Политика из configs/rbac.json совпадает с политикой по умолчанию
+	cfg.RBAC.PolicyFile = "../../configs/rbac.json"
+	authz, err = newAuthorizer(cfg)
+	require.NoError(t, err)
+	for _, role := range []domainuser.Role{domainuser.RoleAdmin, domainuser.RoleEmployee, "moderator"} {
+		assert.Equal(t, rbac.MustNewAuthorizer(rbac.DefaultPolicy).Permissions(role), authz.Permissions(role), role)
+	}
+
+	cfg.RBAC.PolicyFile = "missing.json"
+	_, err = newAuthorizer(cfg)
+	assert.Error(t, err)
+}
@@ -1,7 +1,14 @@
 package app
 
+import (
+	"os"
+	"strconv"
+)
+
 // Config представляет конфигурацию приложения
 type Config struct {
+	// Env окружение запуска: development или production
+	Env    string
 	Server struct {
 		HTTP struct {
 			Host string
@@ -20,12 +27,113 @@ type Config struct {
 		DBName   string
 		SSLMode  string
 	}
+	// JWT параметры токенов доступа и обновления. Expiration, ClockSkew,
+	// RefreshExpiration и KeyGracePeriod задаются в формате time.ParseDuration,
+	// пустые значения заменяются значениями по умолчанию. Если задан KeysDir,
+	// токены подписываются ключами RS256/EdDSA из каталога вместо Secret.
 	JWT struct {
-		Secret     string
-		Expiration string
+		Secret            string
+		Issuer            string
+		Audience          string
+		Expiration        string
+		ClockSkew         string
+		RefreshExpiration string
+		KeysDir           string
+		KeyGracePeriod    string
 	}
 	Logging struct {
 		Level  string
 		Format string
 	}
+	// Outbox задает, куда публикуются сообщения outbox:
+	// memory, file (NDJSON в FilePath) или http (POST на URL)
+	Outbox struct {
+		Publisher string
+		FilePath  string
+		URL       string
+	}
+	// RBAC задает политику прав: PolicyFile — JSON-файл с разрешениями ролей и
+	// псевдонимами ролей, пустое значение — rbac.DefaultPolicy
+	RBAC struct {
+		PolicyFile string
+	}
+	// RateLimit задает ограничение частоты запросов: Store — хранилище корзин
+	// memory (в памяти экземпляра), postgres (общее для экземпляров) или off,
+	// PolicyFile — JSON-файл с лимитами, пустое значение — ratelimit.DefaultPolicy
+	RateLimit struct {
+		Store      string
+		PolicyFile string
+	}
+	// Notifier задает, куда отправляются письма со ссылками сброса пароля и
+	// подтверждения email: log (в лог сервиса) или file (NDJSON в FilePath)
+	Notifier struct {
+		Kind     string
+		FilePath string
+	}
+}
+
+// LoadConfig загружает конфигурацию HTTP- и gRPC-серверов из переменных окружения.
+// Оба сервера проверяют одни и те же токены, поэтому читают одинаковые
+// параметры JWT, базы данных и политик.
+func LoadConfig() *Config {
+	cfg := &Config{}
+	cfg.Env = getEnv("APP_ENV", "development")
+
+	cfg.Server.HTTP.Host = getEnv("HTTP_HOST", "localhost")
+	cfg.Server.HTTP.Port = getEnvAsInt("HTTP_PORT", 8080)
+	cfg.Server.GRPC.Host = getEnv("GRPC_HOST", "localhost")
+	cfg.Server.GRPC.Port = getEnvAsInt("GRPC_PORT", 9090)
+
+	cfg.Database.Host = getEnv("DB_HOST", "localhost")
+	cfg.Database.Port = getEnvAsInt("DB_PORT", 5432)
+	cfg.Database.User = getEnv("DB_USER", "postgres")
+	cfg.Database.Password = getEnv("DB_PASSWORD", "postgres")
+	cfg.Database.DBName = getEnv("DB_NAME", "avito_pvz")
+	cfg.Database.SSLMode = getEnv("DB_SSLMODE", "disable")
+
+	cfg.JWT.Secret = getEnv("JWT_SECRET", "your-secret-key")
+	cfg.JWT.Issuer = getEnv("JWT_ISSUER", "avito-pvz")
+	cfg.JWT.Audience = getEnv("JWT_AUDIENCE", "avito-pvz")
+	cfg.JWT.Expiration = getEnv("JWT_EXPIRATION", "15m")
+	cfg.JWT.ClockSkew = getEnv("JWT_CLOCK_SKEW", "30s")
+	cfg.JWT.RefreshExpiration = getEnv("JWT_REFRESH_EXPIRATION", "720h")
+	cfg.JWT.KeysDir = getEnv("JWT_KEYS_DIR", "")
+	cfg.JWT.KeyGracePeriod = getEnv("JWT_KEY_GRACE_PERIOD", "1h")
+
+	cfg.Logging.Level = getEnv("LOG_LEVEL", "info")
+	cfg.Logging.Format = getEnv("LOG_FORMAT", "json")
+
+	cfg.Outbox.Publisher = getEnv("OUTBOX_PUBLISHER", "file")
+	cfg.Outbox.FilePath = getEnv("OUTBOX_FILE", "outbox.ndjson")
+	cfg.Outbox.URL = getEnv("OUTBOX_URL", "")
+
+	// Без файла политики действуют встроенные политики; образ Docker задает
+	// файлы из configs, чтобы их можно было изменить без пересборки
+	cfg.RBAC.PolicyFile = getEnv("RBAC_POLICY_FILE", "")
+
+	cfg.RateLimit.Store = getEnv("RATE_LIMIT_STORE", "memory")
+	cfg.RateLimit.PolicyFile = getEnv("RATE_LIMIT_POLICY_FILE", "")
+
+	cfg.Notifier.Kind = getEnv("NOTIFIER", "log")
+	cfg.Notifier.FilePath = getEnv("NOTIFIER_FILE", "notifications.ndjson")
+
+	return cfg
+}
+
+// getEnv получает значение переменной окружения или возвращает значение по умолчанию
+func getEnv(key, defaultValue string) string {
+	if value, exists := os.LookupEnv(key); exists {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvAsInt получает значение переменной окружения как int или возвращает значение по умолчанию
+func getEnvAsInt(key string, defaultValue int) int {
+	if value, exists := os.LookupEnv(key); exists {
+		if intValue, err := strconv.Atoi(value); err == nil {
+			return intValue
+		}
+	}
+	return defaultValue
 }
@@ -0,0 +1,37 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfig(t *testing.T) {
+	t.Setenv("APP_ENV", "production")
+	t.Setenv("GRPC_PORT", "3000")
+	t.Setenv("JWT_SECRET", "prod-secret")
+	t.Setenv("JWT_KEYS_DIR", "/etc/pvz/keys")
+	t.Setenv("JWT_ISSUER", "pvz")
+
+	cfg := LoadConfig()
+
+	assert.Equal(t, "production", cfg.Env)
+	assert.Equal(t, 3000, cfg.Server.GRPC.Port)
+	assert.Equal(t, 8080, cfg.Server.HTTP.Port)
+	assert.Equal(t, "prod-secret", cfg.JWT.Secret)
+	assert.Equal(t, "/etc/pvz/keys", cfg.JWT.KeysDir)
+	assert.Equal(t, "pvz", cfg.JWT.Issuer)
+	assert.Equal(t, "avito-pvz", cfg.JWT.Audience)
+	assert.Equal(t, "avito_pvz", cfg.Database.DBName)
+}
+
+func TestNewGRPCServer_DefaultSecretInProduction(t *testing.T) {
+	t.Setenv("APP_ENV", "production")
+
+	// gRPC-сервер читает ту же конфигурацию, что и HTTP-сервер, и тоже
+	// отказывается запускаться с секретом из примеров конфигурации
+	_, err := NewGRPCServer(LoadConfig())
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrDefaultJWTSecret)
+}
@@ -0,0 +1,19 @@
+package app
+
+import (
+	"github.com/avito/pvz/internal/domain/audit"
+	"github.com/avito/pvz/internal/domain/event"
+	"github.com/avito/pvz/internal/service/eventbus"
+)
+
+// newEventBus создает шину событий с подписчиками метрик и аудита.
+// Если publisher задан, события приемок и товаров передаются и в него.
+func newEventBus(auditLog audit.AuditLog, publisher event.Publisher) *eventbus.Bus {
+	bus := eventbus.New(eventbus.DefaultBufferSize)
+	eventbus.SubscribeMetrics(bus)
+	eventbus.SubscribeAudit(bus, auditLog)
+	if publisher != nil {
+		eventbus.SubscribeNotifications(bus, publisher)
+	}
+	return bus
+}
@@ -1,6 +1,7 @@
 package app
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/avito/pvz/api/proto"
@@ -8,13 +9,28 @@ import (
 	"github.com/avito/pvz/internal/domain/user"
 	"github.com/avito/pvz/internal/handler/grpc"
 	"github.com/avito/pvz/internal/repository/postgres"
+	apikeyservice "github.com/avito/pvz/internal/service/apikey"
 	"github.com/avito/pvz/internal/service/pvz"
+	sessionservice "github.com/avito/pvz/internal/service/session"
 	"github.com/jmoiron/sqlx"
 	grpcserver "google.golang.org/grpc"
 )
 
 // NewGRPCServer создает новый экземпляр gRPC сервера
 func NewGRPCServer(cfg *Config) (*grpcserver.Server, error) {
+	tokens, err := newTokenService(cfg)
+	if err != nil {
+		return nil, err
+	}
+	authz, err := newAuthorizer(cfg)
+	if err != nil {
+		return nil, err
+	}
+	sessionCfg, err := newSessionConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	// Инициализация репозиториев
 	db, err := postgres.New(cfg.Database)
 	if err != nil {
@@ -29,16 +45,38 @@ func NewGRPCServer(cfg *Config) (*grpcserver.Server, error) {
 	userRepo := postgres.NewUserRepository(sqlxDB)
 	txManager := transaction.NewManager(sqlxDB)
 	auditLog := postgres.NewAuditLog(sqlxDB)
+	// Сервисные аккаунты вызывают gRPC API с теми же ключами, что и HTTP API
+	apiKeyService := apikeyservice.New(postgres.NewAPIKeyRepository(sqlxDB), apikeyservice.DefaultConfig)
+	// Токены доступа проверяются по тому же списку отзыва, что и в HTTP API:
+	// после выхода или завершения сессий токен отклоняется и здесь
+	sessionService := sessionservice.New(postgres.NewSessionRepository(sqlxDB), userRepo, txManager, tokens, sessionCfg)
+	go sessionService.Run(context.Background())
 
 	// Создаем модель пользователя по умолчанию
 	defaultUser := &user.User{
 		Role: user.RoleAdmin,
 	}
 
-	pvzService := pvz.New(pvzRepo, userRepo, txManager, auditLog, defaultUser)
+	pvzService := pvz.New(pvzRepo, userRepo, txManager, newEventBus(auditLog, nil), postgres.NewAuditRepository(sqlxDB), defaultUser)
 
 	// Создание gRPC сервера
-	server := grpcserver.NewServer()
+	// Язык выбирается первым, чтобы ошибки авторизации были локализованы,
+	// а лимит проверяется после авторизации, чтобы учитывать роль вызывающего
+	interceptors := []grpcserver.UnaryServerInterceptor{
+		grpc.LanguageInterceptor,
+		grpc.RequestIDInterceptor,
+		grpc.AuthInterceptor(sessionService, apiKeyService, authz, grpc.MethodPermissions),
+	}
+	limiter, rateLimitWorker, err := newRateLimiter(cfg, postgres.NewRateLimitRepository(sqlxDB), txManager)
+	if err != nil {
+		return nil, err
+	}
+	if limiter != nil {
+		interceptors = append(interceptors, grpc.RateLimitInterceptor(limiter))
+		// Корзины удаляются, пока работает процесс: у gRPC-сервера нет фоновых обработчиков
+		go rateLimitWorker(context.Background())
+	}
+	server := grpcserver.NewServer(grpcserver.ChainUnaryInterceptor(interceptors...))
 
 	// Регистрация сервисов
 	pvzHandler := grpc.NewPVZHandler(pvzService)
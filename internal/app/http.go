@@ -3,28 +3,78 @@ package app
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"time"
 
+	"github.com/avito/pvz/internal/domain/outbox"
 	domainuser "github.com/avito/pvz/internal/domain/user"
+	gqlhandler "github.com/avito/pvz/internal/handler/graphql"
 	httphandler "github.com/avito/pvz/internal/handler/http"
+	"github.com/avito/pvz/internal/handler/http/middleware"
+	httpv2 "github.com/avito/pvz/internal/handler/http/v2"
 	"github.com/avito/pvz/internal/repository/postgres"
+	accountservice "github.com/avito/pvz/internal/service/account"
+	apikeyservice "github.com/avito/pvz/internal/service/apikey"
+	assignmentservice "github.com/avito/pvz/internal/service/assignment"
+	auditservice "github.com/avito/pvz/internal/service/audit"
+	"github.com/avito/pvz/internal/service/events"
+	"github.com/avito/pvz/internal/service/export"
+	jobservice "github.com/avito/pvz/internal/service/job"
+	lockoutservice "github.com/avito/pvz/internal/service/lockout"
+	outboxservice "github.com/avito/pvz/internal/service/outbox"
 	"github.com/avito/pvz/internal/service/product"
 	"github.com/avito/pvz/internal/service/pvz"
 	"github.com/avito/pvz/internal/service/reception"
+	sessionservice "github.com/avito/pvz/internal/service/session"
 	userservice "github.com/avito/pvz/internal/service/user"
+	webhookservice "github.com/avito/pvz/internal/service/webhook"
+	"github.com/avito/pvz/pkg/auth"
 	"github.com/go-chi/chi/v5"
 	"github.com/jmoiron/sqlx"
 )
 
+const (
+	// apiV2Prefix префикс маршрутов второй версии API
+	apiV2Prefix = "/api/v2"
+)
+
+var (
+	// apiV1Deprecation дата, с которой маршруты первой версии API считаются устаревшими
+	apiV1Deprecation = time.Date(2026, time.April, 1, 0, 0, 0, 0, time.UTC)
+	// apiV1Sunset дата, после которой устаревшие маршруты первой версии API будут удалены
+	apiV1Sunset = time.Date(2027, time.April, 1, 0, 0, 0, 0, time.UTC)
+)
+
 // HTTPServer представляет HTTP-сервер приложения
 type HTTPServer struct {
 	server *http.Server
 	router chi.Router
+	// workers фоновые обработчики, работающие до остановки сервера
+	workers     []func(ctx context.Context)
+	workerCtx   context.Context
+	stopWorkers context.CancelFunc
+	// closers ресурсы, закрываемые после остановки фоновых обработчиков
+	closers []io.Closer
 }
 
 // NewHTTPServer создает новый экземпляр HTTP-сервера
 func NewHTTPServer(cfg *Config) (*HTTPServer, error) {
+	// Сервис токенов проверяется до подключения к базе: в production
+	// сервер не запускается с секретом по умолчанию
+	tokens, err := newTokenService(cfg)
+	if err != nil {
+		return nil, err
+	}
+	sessionCfg, err := newSessionConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	authz, err := newAuthorizer(cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	// Инициализация репозиториев
 	db, err := postgres.New(cfg.Database)
 	if err != nil {
@@ -39,30 +89,161 @@ func NewHTTPServer(cfg *Config) (*HTTPServer, error) {
 	receptionRepo := postgres.NewReceptionRepository(sqlxDB)
 	productRepo := postgres.NewProductRepository(sqlxDB)
 	userRepo := postgres.NewUserRepository(sqlxDB)
+	idempotencyRepo := postgres.NewIdempotencyRepository(sqlxDB)
+	webhookRepo := postgres.NewWebhookRepository(sqlxDB)
+	outboxRepo := postgres.NewOutboxRepository(sqlxDB)
+	jobRepo := postgres.NewJobRepository(sqlxDB)
+	sessionRepo := postgres.NewSessionRepository(sqlxDB)
+	assignmentRepo := postgres.NewAssignmentRepository(sqlxDB)
+	rateLimitRepo := postgres.NewRateLimitRepository(sqlxDB)
+	lockoutRepo := postgres.NewLockoutRepository(sqlxDB)
+	userTokenRepo := postgres.NewUserTokenRepository(sqlxDB)
+	apiKeyRepo := postgres.NewAPIKeyRepository(sqlxDB)
 
 	// Инициализация менеджера транзакций
 	txManager := postgres.NewTransactionManager(db.DB)
 
 	// Создаем реализацию аудита
 	auditLog := postgres.NewAuditLog(sqlxDB)
+	// Изменения данных записываются в журнал аудита в транзакциях сервисов
+	auditRepo := postgres.NewAuditRepository(sqlxDB)
 
 	// Создаем модель пользователя по умолчанию
 	defaultUser := &domainuser.User{
 		Role: domainuser.RoleAdmin,
 	}
 
+	// Брокер ленты событий приемок и товаров
+	eventBroker := events.NewBroker(events.DefaultBufferSize)
+	// Метрики, аудит и уведомления подписаны на события сервисов
+	bus := newEventBus(auditLog, eventBroker)
+
+	// Сообщения outbox записываются в транзакциях сервисов и публикуются фоновым обработчиком.
+	// Доставки вебхуков создаются из тех же сообщений в транзакции обработчика.
+	webhookService := webhookservice.New(webhookRepo, nil, webhookservice.DefaultConfig)
+	outboxPublisher, outboxCloser, err := newOutboxPublisher(cfg)
+	if err != nil {
+		return nil, err
+	}
+	outboxRelay := outboxservice.NewRelay(outboxRepo, txManager, outbox.Fanout{outboxPublisher, webhookService}, outboxservice.DefaultConfig)
+
+	// Письма со ссылками сброса пароля и подтверждения email
+	notifier, notifierCloser, err := newNotifier(cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	// Инициализация сервисов
-	pvzService := pvz.New(pvzRepo, userRepo, txManager, auditLog, defaultUser)
-	receptionService := reception.New(receptionRepo, pvzRepo, txManager, productRepo)
-	productService := product.New(productRepo, receptionRepo, txManager)
-	userService := userservice.New(userRepo, txManager)
+	pvzService := pvz.New(pvzRepo, userRepo, txManager, bus, auditRepo, defaultUser)
+	// Сотрудники изменяют приемки и товары только закрепленных за ними ПВЗ
+	assignmentService := assignmentservice.New(assignmentRepo, userRepo, pvzRepo)
+	receptionService := reception.New(receptionRepo, pvzRepo, txManager, productRepo, bus, outboxRepo, auditRepo, assignmentService)
+	productService := product.New(productRepo, receptionRepo, txManager, bus, outboxRepo, auditRepo, assignmentService)
+	// Неудачные входы считаются по учетной записи и по IP-адресу
+	lockoutService := lockoutservice.New(lockoutRepo, userRepo, txManager, bus, lockoutservice.DefaultConfig)
+	userService := userservice.New(userRepo, txManager, tokens, authz, lockoutService, auditRepo)
+	exportService := export.New(receptionRepo)
+	// Сессии выдают токены обновления и проверяют токены доступа по списку отзыва
+	sessionService := sessionservice.New(sessionRepo, userRepo, txManager, tokens, sessionCfg)
+	// Сброс пароля завершает все сессии пользователя
+	accountService := accountservice.New(userTokenRepo, userRepo, txManager, notifier, sessionService, auditRepo, accountservice.DefaultConfig)
+	apiKeyService := apikeyservice.New(apiKeyRepo, apikeyservice.DefaultConfig)
+	auditService := auditservice.New(auditRepo)
+	limiter, rateLimitWorker, err := newRateLimiter(cfg, rateLimitRepo, txManager)
+	if err != nil {
+		return nil, err
+	}
+
+	// Долгие операции выполняются фоновыми задачами из очереди в Postgres
+	jobService := jobservice.New(jobRepo, txManager, jobservice.DefaultConfig)
+	jobService.Register(jobservice.TypeExportReceptions, jobservice.ExportReceptions(exportService))
+	jobService.Register(jobservice.TypeImportProducts, jobservice.ImportProducts(productService))
 
 	// Инициализация обработчиков
-	handler := httphandler.New(pvzService, receptionService, productService, userService)
+	authHandler := httphandler.New(pvzService, receptionService, productService, userService, sessionService)
+	sessionHandler := httphandler.NewSessionHandler(sessionService)
+	handlers := httphandler.NewHandlers(pvzService, receptionService, productService, userService)
+	exportHandler := httphandler.NewExportHandler(exportService)
+	webhookHandler := httphandler.NewWebhookHandler(webhookService)
+	jobHandler := httphandler.NewJobHandler(jobService)
+	assignmentHandler := httphandler.NewAssignmentHandler(assignmentService)
+	lockoutHandler := httphandler.NewLockoutHandler(lockoutService)
+	accountHandler := httphandler.NewAccountHandler(accountService)
+	serviceAccountHandler := httphandler.NewServiceAccountHandler(apiKeyService)
+	auditHandler := httphandler.NewAuditHandler(auditService)
+	// Сотрудник получает события ПВЗ, за которыми закреплен
+	eventsHandler := httphandler.NewEventsHandler(eventBroker, httphandler.PVZAccessFunc(assignmentService.ActivePVZs))
+	v2Handler := httpv2.New(pvzService, receptionService, productService)
+	gqlSchema, err := gqlhandler.NewSchema(pvzService, receptionService, productService)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create graphql schema: %w", err)
+	}
+	gqlHandler := gqlhandler.NewHandler(gqlSchema, pvzRepo, receptionRepo, productRepo, gqlhandler.DefaultLimits)
+
+	// Первая версия API: маршруты ПВЗ, приемок и товаров заменены во второй версии
+	// и отдают заголовки Deprecation и Sunset
+	v1 := func(r chi.Router) {
+		authHandler.RegisterRoutes(r)
+		sessionHandler.RegisterRoutes(r)
+		exportHandler.RegisterRoutes(r)
+		eventsHandler.RegisterRoutes(r)
+		webhookHandler.RegisterRoutes(r)
+		jobHandler.RegisterRoutes(r)
+		assignmentHandler.RegisterRoutes(r)
+		lockoutHandler.RegisterRoutes(r)
+		accountHandler.RegisterRoutes(r)
+		serviceAccountHandler.RegisterRoutes(r)
+		auditHandler.RegisterRoutes(r)
+		handlers.User.RegisterRoutes(r)
+
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.Deprecated(apiV1Deprecation, apiV1Sunset, apiV2Prefix))
+			handlers.PVZ.RegisterRoutes(r)
+			handlers.Reception.RegisterRoutes(r)
+			handlers.Product.RegisterRoutes(r)
+		})
+	}
 
 	// Настройка маршрутизатора
 	router := chi.NewRouter()
-	handler.RegisterRoutes(router)
+	router.Use(middleware.Language)
+	router.Use(middleware.ClientIP)
+	// ID запроса связывает записи журнала аудита с запросом и возвращается клиенту
+	router.Use(middleware.RequestID)
+	// Токен проверяется один раз для всех маршрутов, до ключей идемпотентности,
+	// которые принадлежат пользователю. Сервис сессий сверяет токен со списком отзыва,
+	// сервисные аккаунты предъявляют ключ API вместо токена, а разрешения роли по
+	// политике RBAC и областям ключа проверяются на маршрутах.
+	router.Use(middleware.Authenticate(sessionService, apiKeyService, authz))
+	// Лимит выбирается по роли из токена, поэтому проверяется после Authenticate,
+	// но до ключей идемпотентности, чтобы отклоненный запрос не занимал ключ
+	if limiter != nil {
+		router.Use(middleware.RateLimit(limiter, router))
+	}
+	router.Use(middleware.Idempotency(idempotencyRepo, middleware.DefaultIdempotencyConfig))
+	// Открытые ключи для сервисов, проверяющих токены самостоятельно
+	router.Get(auth.JWKSPath, tokens.JWKSHandler)
+	router.Route("/api/v1", v1)
+	router.Route(apiV2Prefix, func(r chi.Router) {
+		authHandler.RegisterRoutes(r)
+		sessionHandler.RegisterRoutes(r)
+		exportHandler.RegisterRoutes(r)
+		eventsHandler.RegisterRoutes(r)
+		webhookHandler.RegisterRoutes(r)
+		jobHandler.RegisterRoutes(r)
+		assignmentHandler.RegisterRoutes(r)
+		lockoutHandler.RegisterRoutes(r)
+		accountHandler.RegisterRoutes(r)
+		serviceAccountHandler.RegisterRoutes(r)
+		auditHandler.RegisterRoutes(r)
+		v2Handler.RegisterRoutes(r)
+	})
+	router.Group(func(r chi.Router) {
+		r.Use(middleware.AuthMiddleware)
+		r.Handle("/graphql", gqlHandler)
+	})
+	// Пути без версии остаются для существующих клиентов и совпадают с первой версией
+	router.Group(v1)
 
 	// Создание HTTP-сервера
 	server := &http.Server{
@@ -72,18 +253,51 @@ func NewHTTPServer(cfg *Config) (*HTTPServer, error) {
 		WriteTimeout: time.Second * 10,
 	}
 
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+
+	// Шина закрывается первой, чтобы асинхронные подписчики успели обработать события
+	closers := []io.Closer{bus}
+	if outboxCloser != nil {
+		closers = append(closers, outboxCloser)
+	}
+	if notifierCloser != nil {
+		closers = append(closers, notifierCloser)
+	}
+
+	workers := []func(ctx context.Context){
+		webhookService.Run, outboxRelay.Run, jobService.Run, sessionService.Run, tokens.Run, lockoutService.Run, accountService.Run,
+		middleware.IdempotencyCleanup(idempotencyRepo, middleware.DefaultIdempotencyConfig),
+	}
+	if rateLimitWorker != nil {
+		workers = append(workers, rateLimitWorker)
+	}
+
 	return &HTTPServer{
-		server: server,
-		router: router,
+		server:      server,
+		router:      router,
+		workers:     workers,
+		workerCtx:   workerCtx,
+		stopWorkers: stopWorkers,
+		closers:     closers,
 	}, nil
 }
 
-// Start запускает HTTP-сервер
+// Start запускает фоновые обработчики и HTTP-сервер
 func (s *HTTPServer) Start() error {
+	for _, worker := range s.workers {
+		go worker(s.workerCtx)
+	}
 	return s.server.ListenAndServe()
 }
 
-// Stop останавливает HTTP-сервер
+// Stop останавливает фоновые обработчики и HTTP-сервер
 func (s *HTTPServer) Stop(ctx context.Context) error {
-	return s.server.Shutdown(ctx)
+	if s.stopWorkers != nil {
+		s.stopWorkers()
+	}
+	err := s.server.Shutdown(ctx)
+	for _, c := range s.closers {
+		c.Close()
+	}
+	return err
 }
@@ -86,11 +86,18 @@ func TestNewHTTPServer(t *testing.T) {
 			SSLMode:  "disable",
 		},
 		JWT: struct {
-			Secret     string
-			Expiration string
+			Secret            string
+			Issuer            string
+			Audience          string
+			Expiration        string
+			ClockSkew         string
+			RefreshExpiration string
+			KeysDir           string
+			KeyGracePeriod    string
 		}{
-			Secret:     "test-secret",
-			Expiration: "24h",
+			Secret:            "test-secret",
+			Expiration:        "15m",
+			RefreshExpiration: "720h",
 		},
 		Logging: struct {
 			Level  string
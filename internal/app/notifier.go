@@ -0,0 +1,31 @@
+package app
+
+import (
+	"fmt"
+	"io"
+
+	accountservice "github.com/avito/pvz/internal/service/account"
+)
+
+// Способы отправки писем пользователям
+const (
+	notifierLog  = "log"
+	notifierFile = "file"
+)
+
+// newNotifier создает отправителя писем по конфигурации.
+// Возвращаемый io.Closer, если он не nil, нужно закрыть при остановке сервера.
+func newNotifier(cfg *Config) (accountservice.Notifier, io.Closer, error) {
+	switch cfg.Notifier.Kind {
+	case "", notifierLog:
+		return accountservice.LogNotifier{}, nil, nil
+	case notifierFile:
+		notifier, f, err := accountservice.OpenNDJSONFile(cfg.Notifier.FilePath)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to open notifier file: %w", err)
+		}
+		return notifier, f, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown notifier %q", cfg.Notifier.Kind)
+	}
+}
@@ -0,0 +1,30 @@
+package app
+
+import (
+	"path/filepath"
+	"testing"
+
+	accountservice "github.com/avito/pvz/internal/service/account"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewNotifier(t *testing.T) {
+	cfg := &Config{}
+	notifier, closer, err := newNotifier(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, accountservice.LogNotifier{}, notifier)
+	assert.Nil(t, closer)
+
+	cfg.Notifier.Kind = notifierFile
+	cfg.Notifier.FilePath = filepath.Join(t.TempDir(), "notifications.ndjson")
+	notifier, closer, err = newNotifier(cfg)
+	require.NoError(t, err)
+	assert.IsType(t, &accountservice.NDJSONNotifier{}, notifier)
+	require.NotNil(t, closer)
+	assert.NoError(t, closer.Close())
+
+	cfg.Notifier.Kind = "smtp"
+	_, _, err = newNotifier(cfg)
+	assert.Error(t, err)
+}
@@ -0,0 +1,38 @@
+package app
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/avito/pvz/internal/domain/outbox"
+	outboxservice "github.com/avito/pvz/internal/service/outbox"
+)
+
+// Способы публикации сообщений outbox
+const (
+	outboxPublisherMemory = "memory"
+	outboxPublisherFile   = "file"
+	outboxPublisherHTTP   = "http"
+)
+
+// newOutboxPublisher создает публикатор сообщений outbox по конфигурации.
+// Возвращаемый io.Closer, если он не nil, нужно закрыть при остановке сервера.
+func newOutboxPublisher(cfg *Config) (outbox.Publisher, io.Closer, error) {
+	switch cfg.Outbox.Publisher {
+	case "", outboxPublisherMemory:
+		return outboxservice.NewMemoryPublisher(), nil, nil
+	case outboxPublisherFile:
+		publisher, f, err := outboxservice.OpenNDJSONFile(cfg.Outbox.FilePath)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to open outbox file: %w", err)
+		}
+		return publisher, f, nil
+	case outboxPublisherHTTP:
+		if cfg.Outbox.URL == "" {
+			return nil, nil, fmt.Errorf("outbox url is required for http publisher")
+		}
+		return outboxservice.NewHTTPPublisher(cfg.Outbox.URL, nil), nil, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown outbox publisher %q", cfg.Outbox.Publisher)
+	}
+}
@@ -0,0 +1,48 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/avito/pvz/internal/domain/ratelimit"
+	"github.com/avito/pvz/internal/domain/transaction"
+	ratelimitservice "github.com/avito/pvz/internal/service/ratelimit"
+)
+
+// Хранилища корзин ограничения частоты запросов
+const (
+	rateLimitStoreMemory   = "memory"
+	rateLimitStorePostgres = "postgres"
+	rateLimitStoreOff      = "off"
+)
+
+// newRateLimiter создает ограничение частоты запросов по политике из
+// cfg.RateLimit.PolicyFile или ratelimit.DefaultPolicy. Возвращаемый фоновый
+// обработчик удаляет неиспользуемые корзины. Если ограничение выключено,
+// возвращается nil.
+func newRateLimiter(cfg *Config, repo ratelimit.Repository, txManager transaction.Manager) (*ratelimitservice.Limiter, func(ctx context.Context), error) {
+	policy := ratelimit.DefaultPolicy
+	if cfg.RateLimit.PolicyFile != "" {
+		var err error
+		policy, err = ratelimit.LoadPolicy(cfg.RateLimit.PolicyFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load rate limit policy: %w", err)
+		}
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	switch cfg.RateLimit.Store {
+	case "", rateLimitStoreMemory:
+		store := ratelimitservice.NewMemoryStore()
+		return ratelimitservice.New(policy, store), store.Run, nil
+	case rateLimitStorePostgres:
+		store := ratelimitservice.NewSharedStore(repo, txManager)
+		return ratelimitservice.New(policy, store), store.Run, nil
+	case rateLimitStoreOff:
+		return nil, nil, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown rate limit store %q", cfg.RateLimit.Store)
+	}
+}
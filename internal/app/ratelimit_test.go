@@ -0,0 +1,38 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/avito/pvz/internal/domain/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRateLimiter(t *testing.T) {
+	cfg := &Config{}
+	limiter, worker, err := newRateLimiter(cfg, nil, nil)
+	require.NoError(t, err)
+	assert.NotNil(t, limiter)
+	assert.NotNil(t, worker)
+
+	cfg.RateLimit.Store = rateLimitStoreOff
+	limiter, _, err = newRateLimiter(cfg, nil, nil)
+	require.NoError(t, err)
+	assert.Nil(t, limiter)
+
+	cfg.RateLimit.Store = "redis"
+	_, _, err = newRateLimiter(cfg, nil, nil)
+	assert.Error(t, err)
+
+	cfg.RateLimit.Store = rateLimitStoreMemory
+	cfg.RateLimit.PolicyFile = "missing.json"
+	_, _, err = newRateLimiter(cfg, nil, nil)
+	assert.Error(t, err)
+}
+
+func TestRateLimitPolicyFile(t *testing.T) {
+	// Политика из configs/ratelimit.json совпадает с политикой по умолчанию
+	policy, err := ratelimit.LoadPolicy("../../configs/ratelimit.json")
+	require.NoError(t, err)
+	assert.Equal(t, ratelimit.DefaultPolicy, policy)
+}
@@ -0,0 +1,108 @@
+// Package apikey описывает сервисные аккаунты внешних систем и их ключи API.
+// Ключ действует ограниченное время, несет набор областей — разрешений из
+// каталога rbac — и может быть отозван. В хранилище попадают только хеш
+// ключа и его префикс, по которому ключ узнают в списках и журналах.
+package apikey
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/rbac"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrAccountNotFound возвращается, когда сервисный аккаунт не найден
+	ErrAccountNotFound = errors.New("service account not found")
+	// ErrKeyNotFound возвращается, когда ключ не найден
+	ErrKeyNotFound = errors.New("api key not found")
+)
+
+// KeyPrefix начало каждого ключа API. По нему ключ отличается от токена доступа.
+const KeyPrefix = "pvz_"
+
+// prefixLen длина префикса ключа: KeyPrefix и 8 hex-символов
+const prefixLen = len(KeyPrefix) + 8
+
+// IsKey сообщает, похожа ли строка на ключ API
+func IsKey(token string) bool {
+	return len(token) > prefixLen+1 && strings.HasPrefix(token, KeyPrefix) && token[prefixLen] == '_'
+}
+
+// Prefix возвращает префикс ключа, например "pvz_1a2b3c4d". Для строки,
+// не похожей на ключ, возвращает пустую строку.
+func Prefix(token string) string {
+	if !IsKey(token) {
+		return ""
+	}
+	return token[:prefixLen]
+}
+
+// ServiceAccount учетная запись внешней системы, например складской интеграции
+type ServiceAccount struct {
+	ID          uuid.UUID
+	Name        string
+	Description string
+	CreatedBy   uuid.UUID
+	CreatedAt   time.Time
+}
+
+// Key ключ API сервисного аккаунта
+type Key struct {
+	ID               uuid.UUID
+	ServiceAccountID uuid.UUID
+	Name             string
+	// Prefix начало ключа, которое показывается в списках вместо самого ключа
+	Prefix string
+	// KeyHash SHA-256 ключа, сам ключ не хранится
+	KeyHash string
+	// Scopes разрешения, которые получает вызывающий с этим ключом
+	Scopes    []rbac.Permission
+	ExpiresAt time.Time
+	// LastUsedAt время последнего запроса с ключом, обновляется не чаще
+	// интервала из настроек сервиса
+	LastUsedAt *time.Time
+	CreatedBy  uuid.UUID
+	CreatedAt  time.Time
+	// RevokedAt время отзыва ключа, nil — ключ не отозван
+	RevokedAt *time.Time
+}
+
+// Active сообщает, действует ли ключ в момент now
+func (k *Key) Active(now time.Time) bool {
+	return k.RevokedAt == nil && now.Before(k.ExpiresAt)
+}
+
+// Principal вызывающий, предъявивший действующий ключ
+type Principal struct {
+	ServiceAccountID uuid.UUID
+	KeyID            uuid.UUID
+	Permissions      rbac.Set
+}
+
+// Repository определяет методы хранения сервисных аккаунтов и ключей
+type Repository interface {
+	// CreateAccount сохраняет сервисный аккаунт
+	CreateAccount(ctx context.Context, account *ServiceAccount) error
+	// GetAccount получает сервисный аккаунт по ID
+	GetAccount(ctx context.Context, id uuid.UUID) (*ServiceAccount, error)
+	// ListAccounts возвращает все сервисные аккаунты в порядке создания
+	ListAccounts(ctx context.Context) ([]*ServiceAccount, error)
+	// DeleteAccount удаляет сервисный аккаунт вместе с его ключами
+	DeleteAccount(ctx context.Context, id uuid.UUID) error
+
+	// CreateKey сохраняет ключ
+	CreateKey(ctx context.Context, key *Key) error
+	// GetKeyByHash получает ключ по хешу
+	GetKeyByHash(ctx context.Context, hash string) (*Key, error)
+	// ListKeys возвращает ключи сервисного аккаунта, новые первыми
+	ListKeys(ctx context.Context, accountID uuid.UUID) ([]*Key, error)
+	// RevokeKey отзывает ключ сервисного аккаунта. Возвращает ErrKeyNotFound,
+	// если ключа нет или он уже отозван.
+	RevokeKey(ctx context.Context, accountID, keyID uuid.UUID, at time.Time) error
+	// TouchKey обновляет время последнего использования ключа
+	TouchKey(ctx context.Context, id uuid.UUID, at time.Time) error
+}
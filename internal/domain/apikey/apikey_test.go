@@ -0,0 +1,29 @@
+package apikey
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsKey(t *testing.T) {
+	assert.True(t, IsKey("pvz_1a2b3c4d_secret"))
+	assert.Equal(t, "pvz_1a2b3c4d", Prefix("pvz_1a2b3c4d_se_cret"))
+
+	for _, token := range []string{"", "pvz_1a2b3c4d_", "pvz_1a2b3c4dsecret", "eyJhbGciOiJIUzI1NiJ9.e30.sig"} {
+		assert.False(t, IsKey(token), token)
+		assert.Empty(t, Prefix(token), token)
+	}
+}
+
+func TestKey_Active(t *testing.T) {
+	now := time.Date(2026, time.October, 1, 12, 0, 0, 0, time.UTC)
+	key := &Key{ExpiresAt: now.Add(time.Hour)}
+
+	assert.True(t, key.Active(now))
+	assert.False(t, key.Active(key.ExpiresAt))
+
+	key.RevokedAt = &now
+	assert.False(t, key.Active(now))
+}
@@ -0,0 +1,75 @@
+// Package assignment описывает закрепление сотрудников за ПВЗ.
+package assignment
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrNotFound возвращается, когда закрепление не найдено
+	ErrNotFound = errors.New("assignment not found")
+	// ErrNotAssigned возвращается, когда вызывающий не закреплен за ПВЗ
+	ErrNotAssigned = errors.New("user is not assigned to pvz")
+)
+
+// Assignment закрепление сотрудника за ПВЗ на период
+type Assignment struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+	PVZID  uuid.UUID
+	// ValidFrom начало действия закрепления
+	ValidFrom time.Time
+	// ValidTo окончание действия закрепления (не включительно), nil — бессрочно
+	ValidTo   *time.Time
+	CreatedBy uuid.UUID
+	CreatedAt time.Time
+}
+
+// Active сообщает, действует ли закрепление в момент at
+func (a *Assignment) Active(at time.Time) bool {
+	if at.Before(a.ValidFrom) {
+		return false
+	}
+	return a.ValidTo == nil || at.Before(*a.ValidTo)
+}
+
+// Filter параметры выборки закреплений. Нулевой ID не ограничивает выборку.
+type Filter struct {
+	UserID uuid.UUID
+	PVZID  uuid.UUID
+}
+
+// Repository определяет методы для хранения закреплений
+type Repository interface {
+	// Create сохраняет новое закрепление
+	Create(ctx context.Context, a *Assignment) error
+	// Delete удаляет закрепление
+	Delete(ctx context.Context, id uuid.UUID) error
+	// List возвращает закрепления по фильтру, новые первыми
+	List(ctx context.Context, filter Filter) ([]*Assignment, error)
+	// ActivePVZIDs возвращает ПВЗ, за которыми пользователь закреплен в момент at
+	ActivePVZIDs(ctx context.Context, userID uuid.UUID, at time.Time) ([]uuid.UUID, error)
+	// IsAssigned сообщает, закреплен ли пользователь за ПВЗ в момент at
+	IsAssigned(ctx context.Context, userID, pvzID uuid.UUID, at time.Time) (bool, error)
+}
+
+// Access проверяет, может ли вызывающий из контекста работать с ПВЗ
+type Access interface {
+	CheckPVZ(ctx context.Context, pvzID uuid.UUID) error
+}
+
+// AccessFunc адаптер функции к интерфейсу Access
+type AccessFunc func(ctx context.Context, pvzID uuid.UUID) error
+
+// CheckPVZ вызывает f
+func (f AccessFunc) CheckPVZ(ctx context.Context, pvzID uuid.UUID) error {
+	return f(ctx, pvzID)
+}
+
+// AllowAll разрешает работу с любым ПВЗ. Используется там, где закрепления
+// не проверяются, например в тестах и внутренних вызовах.
+var AllowAll Access = AccessFunc(func(context.Context, uuid.UUID) error { return nil })
@@ -0,0 +1,33 @@
+package assignment
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssignment_Active(t *testing.T) {
+	from := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+
+	tests := []struct {
+		name    string
+		validTo *time.Time
+		at      time.Time
+		want    bool
+	}{
+		{name: "до начала", validTo: &to, at: from.Add(-time.Second), want: false},
+		{name: "в момент начала", validTo: &to, at: from, want: true},
+		{name: "в периоде", validTo: &to, at: from.Add(time.Hour), want: true},
+		{name: "в момент окончания", validTo: &to, at: to, want: false},
+		{name: "бессрочно", validTo: nil, at: from.AddDate(1, 0, 0), want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &Assignment{ValidFrom: from, ValidTo: tt.validTo}
+			assert.Equal(t, tt.want, a.Active(tt.at))
+		})
+	}
+}
@@ -1,13 +1,277 @@
+// Package audit описывает журнал изменений ПВЗ, приемок, товаров и
+// пользователей. Записи журнала сервисы сохраняют в той же транзакции, что и
+// само изменение, поэтому в журнал попадают только зафиксированные изменения.
 package audit
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"time"
 
+	"github.com/avito/pvz/internal/domain/listing"
 	"github.com/google/uuid"
 )
 
-// AuditLog определяет интерфейс для аудита операций
+// EntityType тип сущности, изменение которой записано в журнал
+type EntityType string
+
+const (
+	EntityPVZ       EntityType = "pvz"
+	EntityReception EntityType = "reception"
+	EntityProduct   EntityType = "product"
+	EntityUser      EntityType = "user"
+)
+
+// Valid сообщает, что тип сущности известен
+func (t EntityType) Valid() bool {
+	switch t {
+	case EntityPVZ, EntityReception, EntityProduct, EntityUser:
+		return true
+	}
+	return false
+}
+
+// Action действие над сущностью
+type Action string
+
+const (
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+	ActionClose  Action = "close"
+	// ActionLock и ActionUnlock блокировка входа пользователя и ее снятие
+	ActionLock   Action = "lock"
+	ActionUnlock Action = "unlock"
+)
+
+// Valid сообщает, что действие известно
+func (a Action) Valid() bool {
+	switch a {
+	case ActionCreate, ActionUpdate, ActionDelete, ActionClose, ActionLock, ActionUnlock:
+		return true
+	}
+	return false
+}
+
+// Source канал, через который пришел запрос на изменение
+type Source string
+
+const (
+	SourceHTTP Source = "http"
+	SourceGRPC Source = "grpc"
+	// SourceJob изменение выполнила фоновая задача, ID запроса — ID задачи
+	SourceJob Source = "job"
+	// SourceSystem изменение без внешнего запроса, например по событию
+	SourceSystem Source = "system"
+)
+
+// Valid сообщает, что источник известен
+func (s Source) Valid() bool {
+	switch s {
+	case SourceHTTP, SourceGRPC, SourceJob, SourceSystem:
+		return true
+	}
+	return false
+}
+
+// Origin источник изменения: канал и ID запроса
+type Origin struct {
+	Source    Source
+	RequestID string
+}
+
+type originKey struct{}
+
+// MaxRequestIDLength наибольшая длина ID запроса, принятого от клиента
+const MaxRequestIDLength = 128
+
+// RequestIDOrNew возвращает id, если клиент передал допустимый ID запроса:
+// непустую строку из печатных символов ASCII без пробелов длиной не больше
+// MaxRequestIDLength. Иначе возвращает новый случайный ID.
+func RequestIDOrNew(id string) string {
+	if id == "" || len(id) > MaxRequestIDLength {
+		return uuid.NewString()
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return uuid.NewString()
+		}
+	}
+	return id
+}
+
+// WithOrigin добавляет источник изменения в контекст
+func WithOrigin(ctx context.Context, o Origin) context.Context {
+	return context.WithValue(ctx, originKey{}, o)
+}
+
+// OriginFrom получает источник изменения из контекста.
+// Без источника в контексте изменение считается системным.
+func OriginFrom(ctx context.Context) Origin {
+	if o, ok := ctx.Value(originKey{}).(Origin); ok {
+		return o
+	}
+	return Origin{Source: SourceSystem}
+}
+
+// State снимок полей сущности до или после изменения.
+// Секреты, например хеш пароля, в снимок не включаются.
+type State map[string]interface{}
+
+// Entry запись журнала аудита
+type Entry struct {
+	ID uuid.UUID
+	// ActorID пользователь или сервисный аккаунт, выполнивший изменение,
+	// uuid.Nil — изменение выполнила система
+	ActorID    uuid.UUID
+	EntityType EntityType
+	EntityID   uuid.UUID
+	Action     Action
+	// Before и After измененные поля сущности до и после изменения в формате
+	// JSON. При создании Before пуст, при удалении пуст After.
+	Before    json.RawMessage
+	After     json.RawMessage
+	RequestID string
+	Source    Source
+	CreatedAt time.Time
+}
+
+// NewEntry создает запись журнала об изменении сущности. Если заданы оба
+// снимка, в записи остаются только поля, значения которых различаются.
+// Источник и ID запроса берутся из ctx.
+func NewEntry(ctx context.Context, actorID uuid.UUID, entityType EntityType, entityID uuid.UUID, action Action, before, after State) (*Entry, error) {
+	if before != nil && after != nil {
+		var err error
+		if before, after, err = diff(before, after); err != nil {
+			return nil, err
+		}
+	}
+
+	beforeJSON, err := marshalState(before)
+	if err != nil {
+		return nil, err
+	}
+	afterJSON, err := marshalState(after)
+	if err != nil {
+		return nil, err
+	}
+
+	origin := OriginFrom(ctx)
+	return &Entry{
+		ID:         uuid.New(),
+		ActorID:    actorID,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Action:     action,
+		Before:     beforeJSON,
+		After:      afterJSON,
+		RequestID:  origin.RequestID,
+		Source:     origin.Source,
+		CreatedAt:  time.Now(),
+	}, nil
+}
+
+// diff оставляет в снимках только различающиеся поля. Значения сравниваются
+// в JSON, чтобы, например, одинаковые время и UUID разных типов считались равными.
+func diff(before, after State) (State, State, error) {
+	changedBefore, changedAfter := State{}, State{}
+
+	for key, old := range before {
+		equal, err := equalJSON(old, after[key])
+		if err != nil {
+			return nil, nil, err
+		}
+		if equal {
+			continue
+		}
+		changedBefore[key] = old
+		if value, ok := after[key]; ok {
+			changedAfter[key] = value
+		}
+	}
+	for key, value := range after {
+		if _, ok := before[key]; !ok {
+			changedAfter[key] = value
+		}
+	}
+
+	return changedBefore, changedAfter, nil
+}
+
+func equalJSON(a, b interface{}) (bool, error) {
+	aJSON, err := json.Marshal(a)
+	if err != nil {
+		return false, err
+	}
+	bJSON, err := json.Marshal(b)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(aJSON, bJSON), nil
+}
+
+func marshalState(s State) (json.RawMessage, error) {
+	if s == nil {
+		return nil, nil
+	}
+	return json.Marshal(s)
+}
+
+// Writer записывает записи журнала в транзакции, открытой в ctx
+type Writer interface {
+	Record(ctx context.Context, entries ...*Entry) error
+}
+
+// Discard не сохраняет записи
+var Discard Writer = discard{}
+
+type discard struct{}
+
+func (discard) Record(context.Context, ...*Entry) error { return nil }
+
+// Filter параметры выборки журнала. Нулевые значения полей не ограничивают выборку.
+type Filter struct {
+	EntityType EntityType
+	EntityID   uuid.UUID
+	ActorID    uuid.UUID
+	Action     Action
+	Source     Source
+	RequestID  string
+	CreatedAt  listing.DateRange
+	Page       listing.Page
+}
+
+// Validate проверяет параметры выборки
+func (f Filter) Validate() error {
+	if f.EntityType != "" && !f.EntityType.Valid() {
+		return listing.ErrInvalidFilter
+	}
+	if f.Action != "" && !f.Action.Valid() {
+		return listing.ErrInvalidFilter
+	}
+	if f.Source != "" && !f.Source.Valid() {
+		return listing.ErrInvalidFilter
+	}
+	if err := f.CreatedAt.Validate(); err != nil {
+		return err
+	}
+	return f.Page.Validate()
+}
+
+// Repository определяет методы хранения журнала аудита
+type Repository interface {
+	Writer
+	// List возвращает записи журнала по фильтру, новые первыми
+	List(ctx context.Context, filter Filter) ([]*Entry, error)
+}
+
+// AuditLog определяет интерфейс для аудита событий безопасности, которые
+// не связаны с изменением данных в транзакции сервиса
 type AuditLog interface {
-	// LogPVZCreation логирует создание ПВЗ
-	LogPVZCreation(ctx context.Context, pvzID, userID uuid.UUID) error
+	// LogLoginLockout логирует блокировку входа по ключу счетчика попыток.
+	// userID — пользователь заблокированной учетной записи или uuid.Nil.
+	LogLoginLockout(ctx context.Context, key string, userID uuid.UUID, lockedUntil time.Time) error
+	// LogLoginUnlock логирует снятие блокировки входа модератором
+	LogLoginUnlock(ctx context.Context, key string, userID, unlockedBy uuid.UUID) error
 }
@@ -2,72 +2,136 @@ package audit
 
 import (
 	"context"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/avito/pvz/internal/domain/listing"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // MockAuditLog реализует интерфейс AuditLog для тестирования
-type MockAuditLog struct {
-	OnLogPVZCreation func(ctx context.Context, pvzID, userID uuid.UUID) error
+type MockAuditLog struct{}
+
+func (m *MockAuditLog) LogLoginLockout(ctx context.Context, key string, userID uuid.UUID, lockedUntil time.Time) error {
+	return nil
 }
 
-func (m *MockAuditLog) LogPVZCreation(ctx context.Context, pvzID, userID uuid.UUID) error {
-	if m.OnLogPVZCreation != nil {
-		return m.OnLogPVZCreation(ctx, pvzID, userID)
-	}
+func (m *MockAuditLog) LogLoginUnlock(ctx context.Context, key string, userID, unlockedBy uuid.UUID) error {
 	return nil
 }
 
 func TestAuditLog_Interface(t *testing.T) {
 	// Проверяем, что MockAuditLog реализует интерфейс AuditLog
 	var _ AuditLog = &MockAuditLog{}
+	var _ Writer = Discard
 }
 
-func TestMockAuditLog_LogPVZCreation(t *testing.T) {
+func TestNewEntry(t *testing.T) {
+	actorID, entityID := uuid.New(), uuid.New()
+	ctx := WithOrigin(context.Background(), Origin{Source: SourceHTTP, RequestID: "req-1"})
+
 	tests := []struct {
-		name    string
-		setup   func() (*MockAuditLog, context.Context, uuid.UUID, uuid.UUID)
-		wantErr bool
+		name       string
+		action     Action
+		before     State
+		after      State
+		wantBefore string
+		wantAfter  string
 	}{
 		{
-			name: "успешное логирование создания ПВЗ",
-			setup: func() (*MockAuditLog, context.Context, uuid.UUID, uuid.UUID) {
-				auditLog := &MockAuditLog{
-					OnLogPVZCreation: func(ctx context.Context, pvzID, userID uuid.UUID) error {
-						return nil
-					},
-				}
-				return auditLog, context.Background(), uuid.New(), uuid.New()
-			},
-			wantErr: false,
+			name:      "создание",
+			action:    ActionCreate,
+			after:     State{"city": "Москва", "version": 1},
+			wantAfter: `{"city":"Москва","version":1}`,
 		},
 		{
-			name: "ошибка при логировании",
-			setup: func() (*MockAuditLog, context.Context, uuid.UUID, uuid.UUID) {
-				expectedErr := assert.AnError
-				auditLog := &MockAuditLog{
-					OnLogPVZCreation: func(ctx context.Context, pvzID, userID uuid.UUID) error {
-						return expectedErr
-					},
-				}
-				return auditLog, context.Background(), uuid.New(), uuid.New()
-			},
-			wantErr: true,
+			name:       "изменение сохраняет только различия",
+			action:     ActionUpdate,
+			before:     State{"city": "Москва", "version": 1, "createdAt": "2026-01-01T00:00:00Z"},
+			after:      State{"city": "Казань", "version": 2, "createdAt": "2026-01-01T00:00:00Z"},
+			wantBefore: `{"city":"Москва","version":1}`,
+			wantAfter:  `{"city":"Казань","version":2}`,
+		},
+		{
+			name:       "удаление",
+			action:     ActionDelete,
+			before:     State{"city": "Москва"},
+			wantBefore: `{"city":"Москва"}`,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			auditLog, ctx, pvzID, userID := tt.setup()
-			err := auditLog.LogPVZCreation(ctx, pvzID, userID)
+			entry, err := NewEntry(ctx, actorID, EntityPVZ, entityID, tt.action, tt.before, tt.after)
+			require.NoError(t, err)
 
-			if tt.wantErr {
-				assert.Error(t, err)
+			assert.Equal(t, actorID, entry.ActorID)
+			assert.Equal(t, EntityPVZ, entry.EntityType)
+			assert.Equal(t, entityID, entry.EntityID)
+			assert.Equal(t, tt.action, entry.Action)
+			assert.Equal(t, SourceHTTP, entry.Source)
+			assert.Equal(t, "req-1", entry.RequestID)
+			if tt.wantBefore == "" {
+				assert.Nil(t, entry.Before)
 			} else {
-				assert.NoError(t, err)
+				assert.JSONEq(t, tt.wantBefore, string(entry.Before))
 			}
+			if tt.wantAfter == "" {
+				assert.Nil(t, entry.After)
+			} else {
+				assert.JSONEq(t, tt.wantAfter, string(entry.After))
+			}
+		})
+	}
+}
+
+func TestOriginFrom(t *testing.T) {
+	assert.Equal(t, Origin{Source: SourceSystem}, OriginFrom(context.Background()))
+
+	origin := Origin{Source: SourceGRPC, RequestID: "req-2"}
+	assert.Equal(t, origin, OriginFrom(WithOrigin(context.Background(), origin)))
+}
+
+func TestRequestIDOrNew(t *testing.T) {
+	assert.Equal(t, "req-1", RequestIDOrNew("req-1"))
+
+	for _, id := range []string{"", "bad id", "id\n", strings.Repeat("a", MaxRequestIDLength+1)} {
+		generated := RequestIDOrNew(id)
+		_, err := uuid.Parse(generated)
+		assert.NoError(t, err, "ID %q заменяется новым", id)
+	}
+}
+
+func TestFilter_Validate(t *testing.T) {
+	page := listing.Page{Limit: listing.DefaultLimit}
+
+	tests := []struct {
+		name    string
+		filter  Filter
+		wantErr error
+	}{
+		{name: "пустой фильтр", filter: Filter{Page: page}},
+		{name: "все поля", filter: Filter{EntityType: EntityReception, Action: ActionClose, Source: SourceGRPC, Page: page}},
+		{name: "неизвестный тип сущности", filter: Filter{EntityType: "order", Page: page}, wantErr: listing.ErrInvalidFilter},
+		{name: "неизвестное действие", filter: Filter{Action: "purge", Page: page}, wantErr: listing.ErrInvalidFilter},
+		{name: "неизвестный источник", filter: Filter{Source: "smtp", Page: page}, wantErr: listing.ErrInvalidFilter},
+		{
+			name: "неверный диапазон дат",
+			filter: Filter{
+				CreatedAt: listing.DateRange{From: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), To: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+				Page:      page,
+			},
+			wantErr: listing.ErrInvalidDateRange,
+		},
+		{name: "неверная страница", filter: Filter{Page: listing.Page{Limit: listing.MaxLimit + 1}}, wantErr: listing.ErrInvalidPage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.filter.Validate(), tt.wantErr)
 		})
 	}
 }
@@ -0,0 +1,169 @@
+package event
+
+import (
+	"context"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/product"
+	"github.com/google/uuid"
+)
+
+const (
+	TypePVZCreated Type = "pvz.created"
+	TypePVZUpdated Type = "pvz.updated"
+	TypePVZDeleted Type = "pvz.deleted"
+
+	TypeLoginLocked   Type = "login.locked"
+	TypeLoginUnlocked Type = "login.unlocked"
+)
+
+// Domain типизированное событие, которое сервис сообщает после фиксации транзакции
+type Domain interface {
+	EventType() Type
+}
+
+// Feed событие приемки или товара, которое также передается в ленту,
+// вебхуки и outbox в виде Event
+type Feed interface {
+	Domain
+	Event() Event
+}
+
+// Bus передает события подписчикам. Сервисы вызывают Raise только после
+// успешной фиксации транзакции, поэтому подписчики не видят отмененных изменений.
+type Bus interface {
+	Raise(ctx context.Context, events ...Domain)
+}
+
+func (discard) Raise(context.Context, ...Domain) {}
+
+// PVZCreated ПВЗ создан
+type PVZCreated struct {
+	PVZID      uuid.UUID
+	City       string
+	CreatedBy  uuid.UUID
+	OccurredAt time.Time
+}
+
+// EventType реализует интерфейс Domain
+func (PVZCreated) EventType() Type { return TypePVZCreated }
+
+// PVZUpdated данные ПВЗ изменены
+type PVZUpdated struct {
+	PVZID      uuid.UUID
+	City       string
+	Version    int64
+	UpdatedBy  uuid.UUID
+	OccurredAt time.Time
+}
+
+// EventType реализует интерфейс Domain
+func (PVZUpdated) EventType() Type { return TypePVZUpdated }
+
+// PVZDeleted ПВЗ удален
+type PVZDeleted struct {
+	PVZID      uuid.UUID
+	DeletedBy  uuid.UUID
+	OccurredAt time.Time
+}
+
+// EventType реализует интерфейс Domain
+func (PVZDeleted) EventType() Type { return TypePVZDeleted }
+
+// ReceptionOpened открыта приемка
+type ReceptionOpened struct {
+	PVZID       uuid.UUID
+	ReceptionID uuid.UUID
+	OccurredAt  time.Time
+}
+
+// EventType реализует интерфейс Domain
+func (ReceptionOpened) EventType() Type { return TypeReceptionOpened }
+
+// Event реализует интерфейс Feed
+func (e ReceptionOpened) Event() Event {
+	return Event{Type: TypeReceptionOpened, PVZID: e.PVZID, ReceptionID: e.ReceptionID, OccurredAt: e.OccurredAt}
+}
+
+// ReceptionClosed приемка закрыта
+type ReceptionClosed struct {
+	PVZID       uuid.UUID
+	ReceptionID uuid.UUID
+	OccurredAt  time.Time
+}
+
+// EventType реализует интерфейс Domain
+func (ReceptionClosed) EventType() Type { return TypeReceptionClosed }
+
+// Event реализует интерфейс Feed
+func (e ReceptionClosed) Event() Event {
+	return Event{Type: TypeReceptionClosed, PVZID: e.PVZID, ReceptionID: e.ReceptionID, OccurredAt: e.OccurredAt}
+}
+
+// ProductAdded в приемку добавлен товар
+type ProductAdded struct {
+	PVZID       uuid.UUID
+	ReceptionID uuid.UUID
+	ProductID   uuid.UUID
+	ProductType product.Type
+	OccurredAt  time.Time
+}
+
+// EventType реализует интерфейс Domain
+func (ProductAdded) EventType() Type { return TypeProductAdded }
+
+// Event реализует интерфейс Feed
+func (e ProductAdded) Event() Event {
+	productID := e.ProductID
+	return Event{
+		Type:        TypeProductAdded,
+		PVZID:       e.PVZID,
+		ReceptionID: e.ReceptionID,
+		ProductID:   &productID,
+		ProductType: e.ProductType,
+		OccurredAt:  e.OccurredAt,
+	}
+}
+
+// ProductRemoved из приемки удален последний товар
+type ProductRemoved struct {
+	PVZID       uuid.UUID
+	ReceptionID uuid.UUID
+	OccurredAt  time.Time
+}
+
+// EventType реализует интерфейс Domain
+func (ProductRemoved) EventType() Type { return TypeProductRemoved }
+
+// Event реализует интерфейс Feed
+func (e ProductRemoved) Event() Event {
+	return Event{Type: TypeProductRemoved, PVZID: e.PVZID, ReceptionID: e.ReceptionID, OccurredAt: e.OccurredAt}
+}
+
+// LoginLocked вход по учетной записи или с IP-адреса заблокирован после
+// серии неудачных попыток
+type LoginLocked struct {
+	// Key ключ счетчика: lockout.AccountKey или lockout.IPKey
+	Key string
+	// UserID пользователь заблокированной учетной записи; uuid.Nil для IP-адреса
+	// и несуществующего email
+	UserID      uuid.UUID
+	Failures    int
+	LockedUntil time.Time
+	OccurredAt  time.Time
+}
+
+// EventType реализует интерфейс Domain
+func (LoginLocked) EventType() Type { return TypeLoginLocked }
+
+// LoginUnlocked модератор снял блокировку входа
+type LoginUnlocked struct {
+	Key string
+	// UserID пользователь разблокированной учетной записи; uuid.Nil для IP-адреса
+	UserID     uuid.UUID
+	UnlockedBy uuid.UUID
+	OccurredAt time.Time
+}
+
+// EventType реализует интерфейс Domain
+func (LoginUnlocked) EventType() Type { return TypeLoginUnlocked }
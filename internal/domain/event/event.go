@@ -0,0 +1,55 @@
+// Package event описывает события предметной области, которые сервисы
+// сообщают после успешного изменения ПВЗ, приемок и товаров.
+package event
+
+import (
+	"context"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/product"
+	"github.com/google/uuid"
+)
+
+// Type тип события
+type Type string
+
+const (
+	TypeReceptionOpened Type = "reception.opened"
+	TypeReceptionClosed Type = "reception.closed"
+	TypeProductAdded    Type = "product.added"
+	TypeProductRemoved  Type = "product.removed"
+)
+
+// Event событие предметной области. Поля, не относящиеся к типу события,
+// остаются нулевыми и не попадают в JSON.
+type Event struct {
+	Type        Type         `json:"type"`
+	PVZID       uuid.UUID    `json:"pvzId"`
+	ReceptionID uuid.UUID    `json:"receptionId"`
+	ProductID   *uuid.UUID   `json:"productId,omitempty"`
+	ProductType product.Type `json:"productType,omitempty"`
+	OccurredAt  time.Time    `json:"occurredAt"`
+}
+
+// Publisher принимает события после фиксации изменений.
+// Publish не должен блокировать вызывающий сервис.
+type Publisher interface {
+	Publish(ctx context.Context, e Event)
+}
+
+// Discard отбрасывает события. Подходит и как Publisher, и как Bus.
+var Discard = discard{}
+
+type discard struct{}
+
+func (discard) Publish(context.Context, Event) {}
+
+// Fanout передает каждое событие всем издателям по порядку
+type Fanout []Publisher
+
+// Publish реализует интерфейс Publisher
+func (f Fanout) Publish(ctx context.Context, e Event) {
+	for _, p := range f {
+		p.Publish(ctx, e)
+	}
+}
@@ -0,0 +1,55 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrNotFound возвращается, когда запись для ключа не найдена
+var ErrNotFound = errors.New("idempotency key not found")
+
+// Record хранит первый ответ на запрос с ключом идемпотентности.
+// Пока запрос выполняется, CompletedAt пуст и ответ не сохранен.
+type Record struct {
+	// UserID пользователь, которому принадлежит ключ, пустая строка для анонимных запросов
+	UserID string
+	Key    string
+	// RequestHash отпечаток метода, пути и тела запроса
+	RequestHash string
+
+	StatusCode int
+	Header     map[string][]string
+	Body       []byte
+	// CreatedAt время, когда запрос занял ключ. По нему запрос отличает свою
+	// запись от записи повтора, занявшего ключ заново.
+	CreatedAt   time.Time
+	CompletedAt *time.Time
+}
+
+// Completed сообщает, что ответ на запрос уже сохранен
+func (r *Record) Completed() bool {
+	return r.CompletedAt != nil
+}
+
+// Repository определяет методы для хранения ключей идемпотентности
+type Repository interface {
+	// Acquire занимает ключ для выполнения запроса. Если ключ занят, возвращает
+	// существующую запись и false. Незавершенные записи, созданные раньше staleBefore,
+	// и любые записи, созданные раньше expiredBefore, занимаются заново.
+	Acquire(ctx context.Context, record *Record, staleBefore, expiredBefore time.Time) (*Record, bool, error)
+
+	// Get получает запись по пользователю и ключу
+	Get(ctx context.Context, userID, key string) (*Record, error)
+
+	// Complete сохраняет ответ на запрос. Если ключ после LockTimeout занял
+	// другой запрос, запись не меняется и возвращается ErrNotFound.
+	Complete(ctx context.Context, record *Record) error
+
+	// Release освобождает незавершенный ключ, чтобы запрос можно было повторить.
+	// Ключ, который после LockTimeout занял другой запрос, не освобождается.
+	Release(ctx context.Context, record *Record) error
+
+	// DeleteExpired удаляет записи, созданные раньше before, и возвращает их число
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+}
@@ -0,0 +1,114 @@
+// Package job описывает фоновые задачи для долгих операций: выгрузок,
+// импортов и отчетов, которые не укладываются в таймаут HTTP-запроса.
+package job
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/rbac"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrNotFound возвращается, когда задача не найдена
+	ErrNotFound = errors.New("job not found")
+	// ErrNotCancellable возвращается при отмене уже завершенной задачи
+	ErrNotCancellable = errors.New("job already finished")
+	// ErrLeaseLost возвращается обработчику, аренда задачи которого истекла
+	// и перешла к другому обработчику
+	ErrLeaseLost = errors.New("job lease lost")
+)
+
+// Type тип задачи, по которому выбирается обработчик
+type Type string
+
+// Status статус задачи
+type Status string
+
+const (
+	// StatusQueued задача ожидает свободного обработчика
+	StatusQueued Status = "queued"
+	// StatusRunning задача выполняется
+	StatusRunning Status = "running"
+	// StatusSucceeded задача выполнена, результат доступен для скачивания
+	StatusSucceeded Status = "succeeded"
+	// StatusFailed задача завершилась ошибкой
+	StatusFailed Status = "failed"
+	// StatusCancelled задача отменена пользователем
+	StatusCancelled Status = "cancelled"
+)
+
+// Finished сообщает, завершена ли задача
+func (s Status) Finished() bool {
+	return s == StatusSucceeded || s == StatusFailed || s == StatusCancelled
+}
+
+// Job фоновая задача пользователя
+type Job struct {
+	ID     uuid.UUID
+	Type   Type
+	UserID uuid.UUID
+	Status Status
+	// Params параметры задачи в формате JSON
+	Params []byte
+	// Permissions разрешения автора на момент постановки задачи, с которыми она выполняется
+	Permissions []rbac.Permission
+	// Progress процент выполнения от 0 до 100
+	Progress int
+	// Result результат успешно выполненной задачи
+	Result            []byte
+	ResultContentType string
+	Error             string
+	// CancelRequested пользователь запросил отмену выполняющейся задачи
+	CancelRequested bool
+	// LeaseID идентификатор аренды, выдаваемый при каждом выборе задачи. Прогресс
+	// и итог сохраняются, только пока аренда принадлежит обработчику.
+	LeaseID uuid.UUID
+	// LeaseUntil время, до которого задачу не заберет другой обработчик
+	LeaseUntil *time.Time
+	CreatedAt  time.Time
+	StartedAt  *time.Time
+	FinishedAt *time.Time
+}
+
+// New создает задачу в очереди
+func New(jobType Type, userID uuid.UUID, params []byte) *Job {
+	return &Job{
+		ID:        uuid.New(),
+		Type:      jobType,
+		UserID:    userID,
+		Status:    StatusQueued,
+		Params:    params,
+		CreatedAt: time.Now(),
+	}
+}
+
+// Repository определяет методы хранения очереди задач
+type Repository interface {
+	// Create ставит задачу в очередь
+	Create(ctx context.Context, j *Job) error
+	// GetByID получает задачу вместе с результатом
+	GetByID(ctx context.Context, id uuid.UUID) (*Job, error)
+	// CountActive возвращает число незавершенных задач пользователя
+	CountActive(ctx context.Context, userID uuid.UUID) (int, error)
+	// LockClaims захватывает блокировку выбора задач до конца транзакции в ctx,
+	// чтобы ограничение на число выполняющихся задач не нарушали параллельные обработчики
+	LockClaims(ctx context.Context) error
+	// ClaimNext переводит в выполнение самую старую задачу пользователя, у которого
+	// выполняется меньше maxRunning задач, и выдает ей новую аренду. Задачи с истекшей
+	// арендой выбираются повторно. Возвращает nil, если подходящих задач нет.
+	ClaimNext(ctx context.Context, now, leaseUntil time.Time, maxRunning int) (*Job, error)
+	// Heartbeat сохраняет прогресс, продлевает аренду leaseID и сообщает, запрошена ли
+	// отмена. Возвращает ErrLeaseLost, если аренда перешла к другому обработчику.
+	Heartbeat(ctx context.Context, id, leaseID uuid.UUID, progress int, leaseUntil time.Time) (bool, error)
+	// Finish сохраняет итоговый статус, результат и ошибку задачи. Возвращает
+	// ErrLeaseLost, если аренда j.LeaseID перешла к другому обработчику.
+	Finish(ctx context.Context, j *Job) error
+	// RequestCancel отменяет задачу в очереди или запрашивает отмену выполняющейся.
+	// Возвращает ErrNotCancellable, если задача уже завершена.
+	RequestCancel(ctx context.Context, id uuid.UUID, at time.Time) error
+	// DeleteFinishedBefore удаляет задачи, завершенные раньше before
+	DeleteFinishedBefore(ctx context.Context, before time.Time) (int64, error)
+}
@@ -0,0 +1,117 @@
+// Package listing описывает общую грамматику фильтрации, сортировки
+// и пагинации списков ПВЗ, приемок и товаров.
+package listing
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultLimit размер страницы по умолчанию
+	DefaultLimit = 10
+	// MaxLimit максимально допустимый размер страницы
+	MaxLimit = 100
+)
+
+var (
+	// ErrInvalidSort ошибка, когда указано неизвестное поле или направление сортировки
+	ErrInvalidSort = errors.New("invalid sort")
+
+	// ErrInvalidDateRange ошибка, когда начало диапазона дат позже его конца
+	ErrInvalidDateRange = errors.New("invalid date range")
+
+	// ErrInvalidFilter ошибка, когда указано недопустимое значение фильтра
+	ErrInvalidFilter = errors.New("invalid filter")
+
+	// ErrInvalidPage ошибка, когда указаны неверные параметры пагинации
+	ErrInvalidPage = errors.New("invalid page")
+)
+
+// Direction направление сортировки
+type Direction string
+
+const (
+	DirectionAsc  Direction = "asc"
+	DirectionDesc Direction = "desc"
+)
+
+// Sort задает поле и направление сортировки.
+// Нулевое значение означает сортировку по умолчанию для списка.
+type Sort struct {
+	Field     string
+	Direction Direction
+}
+
+// ParseSort разбирает параметр сортировки вида "field" (по возрастанию)
+// или "-field" (по убыванию). Пустая строка дает нулевую сортировку.
+func ParseSort(s string) (Sort, error) {
+	if s == "" {
+		return Sort{}, nil
+	}
+
+	direction := DirectionAsc
+	if strings.HasPrefix(s, "-") {
+		direction = DirectionDesc
+		s = s[1:]
+	}
+
+	if s == "" {
+		return Sort{}, ErrInvalidSort
+	}
+
+	return Sort{Field: s, Direction: direction}, nil
+}
+
+// IsZero сообщает, что сортировка не задана
+func (s Sort) IsZero() bool {
+	return s.Field == ""
+}
+
+// Validate проверяет, что поле сортировки входит в список разрешенных
+func (s Sort) Validate(allowed ...string) error {
+	if s.IsZero() {
+		return nil
+	}
+
+	if s.Direction != DirectionAsc && s.Direction != DirectionDesc {
+		return ErrInvalidSort
+	}
+
+	for _, field := range allowed {
+		if s.Field == field {
+			return nil
+		}
+	}
+
+	return ErrInvalidSort
+}
+
+// DateRange задает диапазон дат включительно. Нулевая граница не ограничивает выборку.
+type DateRange struct {
+	From time.Time
+	To   time.Time
+}
+
+// Validate проверяет, что начало диапазона не позже его конца
+func (r DateRange) Validate() error {
+	if !r.From.IsZero() && !r.To.IsZero() && r.To.Before(r.From) {
+		return ErrInvalidDateRange
+	}
+	return nil
+}
+
+// Page задает смещение и размер страницы
+type Page struct {
+	Offset int
+	Limit  int
+}
+
+// Validate проверяет параметры пагинации
+func (p Page) Validate() error {
+	if p.Offset < 0 || p.Limit <= 0 || p.Limit > MaxLimit {
+		return ErrInvalidPage
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package listing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSort(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    Sort
+		wantErr error
+	}{
+		{
+			name:  "пустая строка",
+			input: "",
+			want:  Sort{},
+		},
+		{
+			name:  "по возрастанию",
+			input: "city",
+			want:  Sort{Field: "city", Direction: DirectionAsc},
+		},
+		{
+			name:  "по убыванию",
+			input: "-created_at",
+			want:  Sort{Field: "created_at", Direction: DirectionDesc},
+		},
+		{
+			name:    "только знак минуса",
+			input:   "-",
+			wantErr: ErrInvalidSort,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSort(tt.input)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSort_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		sort    Sort
+		wantErr error
+	}{
+		{
+			name: "сортировка не задана",
+			sort: Sort{},
+		},
+		{
+			name: "разрешенное поле",
+			sort: Sort{Field: "city", Direction: DirectionAsc},
+		},
+		{
+			name:    "неизвестное поле",
+			sort:    Sort{Field: "city; DROP TABLE pvzs", Direction: DirectionAsc},
+			wantErr: ErrInvalidSort,
+		},
+		{
+			name:    "неизвестное направление",
+			sort:    Sort{Field: "city", Direction: "sideways"},
+			wantErr: ErrInvalidSort,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.sort.Validate("created_at", "city")
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestDateRange_Validate(t *testing.T) {
+	now := time.Now()
+
+	assert.NoError(t, DateRange{}.Validate())
+	assert.NoError(t, DateRange{From: now}.Validate())
+	assert.NoError(t, DateRange{From: now, To: now.Add(time.Hour)}.Validate())
+	assert.ErrorIs(t, DateRange{From: now, To: now.Add(-time.Hour)}.Validate(), ErrInvalidDateRange)
+}
+
+func TestPage_Validate(t *testing.T) {
+	assert.NoError(t, Page{Offset: 0, Limit: DefaultLimit}.Validate())
+	assert.ErrorIs(t, Page{Offset: -1, Limit: 10}.Validate(), ErrInvalidPage)
+	assert.ErrorIs(t, Page{Offset: 0, Limit: 0}.Validate(), ErrInvalidPage)
+	assert.ErrorIs(t, Page{Offset: 0, Limit: MaxLimit + 1}.Validate(), ErrInvalidPage)
+}
@@ -0,0 +1,78 @@
+// Package lockout описывает защиту входа от подбора паролей: неудачные попытки
+// считаются отдельно для учетной записи и для IP-адреса, а после нескольких
+// неудач подряд вход с этим ключом временно блокируется.
+package lockout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrNotFound счетчик попыток не найден
+	ErrNotFound = errors.New("login attempt counter not found")
+	// ErrLocked вход временно заблокирован
+	ErrLocked = errors.New("login temporarily locked")
+)
+
+// LockedError ошибка заблокированного входа со временем окончания блокировки
+type LockedError struct {
+	Until time.Time
+}
+
+// Error реализует интерфейс error
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("%v until %s", ErrLocked, e.Until.Format(time.RFC3339))
+}
+
+// Is позволяет сравнивать ошибку с ErrLocked через errors.Is
+func (e *LockedError) Is(target error) bool {
+	return target == ErrLocked
+}
+
+// Вид ключа счетчика
+const (
+	KindAccount = "account"
+	KindIP      = "ip"
+)
+
+// AccountKey ключ счетчика учетной записи. Ключ строится по email, а не по ID
+// пользователя, чтобы несуществующие адреса блокировались так же, как существующие.
+func AccountKey(email string) string {
+	return KindAccount + ":" + strings.ToLower(strings.TrimSpace(email))
+}
+
+// IPKey ключ счетчика IP-адреса
+func IPKey(ip string) string {
+	return KindIP + ":" + ip
+}
+
+// Counter счетчик неудачных попыток входа подряд
+type Counter struct {
+	Key           string
+	Failures      int
+	LastFailureAt time.Time
+	LockedUntil   *time.Time
+}
+
+// Locked сообщает, действует ли блокировка в момент now
+func (c *Counter) Locked(now time.Time) bool {
+	return c.LockedUntil != nil && now.Before(*c.LockedUntil)
+}
+
+// Repository хранит счетчики попыток входа
+type Repository interface {
+	// Get возвращает счетчик или ErrNotFound
+	Get(ctx context.Context, key string) (*Counter, error)
+	// Lock блокирует счетчик до конца транзакции, создавая пустой при отсутствии
+	Lock(ctx context.Context, key string, now time.Time) (*Counter, error)
+	// Save сохраняет счетчик
+	Save(ctx context.Context, c *Counter) error
+	// Delete удаляет счетчик; отсутствие счетчика не ошибка
+	Delete(ctx context.Context, key string) error
+	// DeleteStale удаляет счетчики без неудач и блокировок после before
+	DeleteStale(ctx context.Context, before time.Time) (int64, error)
+}
@@ -0,0 +1,31 @@
+package lockout
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccountKey(t *testing.T) {
+	assert.Equal(t, "account:user@example.com", AccountKey("  User@Example.com "))
+	assert.Equal(t, "ip:10.0.0.1", IPKey("10.0.0.1"))
+}
+
+func TestCounter_Locked(t *testing.T) {
+	now := time.Date(2026, time.October, 1, 12, 0, 0, 0, time.UTC)
+	until := now.Add(time.Minute)
+
+	assert.False(t, (&Counter{}).Locked(now))
+	assert.True(t, (&Counter{LockedUntil: &until}).Locked(now))
+	assert.False(t, (&Counter{LockedUntil: &until}).Locked(until))
+}
+
+func TestLockedError(t *testing.T) {
+	var err error = &LockedError{Until: time.Now()}
+
+	assert.True(t, errors.Is(err, ErrLocked))
+	var locked *LockedError
+	assert.True(t, errors.As(err, &locked))
+}
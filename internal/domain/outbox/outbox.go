@@ -0,0 +1,123 @@
+// Package outbox описывает сообщения, которые сервисы записывают в той же
+// транзакции, что и изменение данных, чтобы затем опубликовать их во внешние
+// системы хотя бы один раз и в порядке изменений каждого агрегата.
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/event"
+	"github.com/google/uuid"
+)
+
+// AggregateReception тип агрегата приемки. События товаров относятся
+// к приемке, поэтому публикуются в одном порядке с ее открытием и закрытием.
+const AggregateReception = "reception"
+
+// Message сообщение, ожидающее публикации
+type Message struct {
+	ID uuid.UUID
+	// Sequence порядковый номер, который присваивает хранилище при записи.
+	// Сообщения одного агрегата публикуются по возрастанию номера.
+	Sequence      int64
+	AggregateType string
+	AggregateID   uuid.UUID
+	EventType     string
+	Payload       []byte
+	CreatedAt     time.Time
+	// Attempts число неудачных попыток публикации
+	Attempts  int
+	LastError string
+	// NextAttemptAt время, раньше которого сообщение после неудачи не публикуется
+	NextAttemptAt *time.Time
+	PublishedAt   *time.Time
+	// DeadAt время, когда сообщение исчерпало попытки и перестало публиковаться
+	DeadAt *time.Time
+}
+
+// NewMessage создает сообщение с телом payload в формате JSON
+func NewMessage(aggregateType string, aggregateID uuid.UUID, eventType string, payload interface{}) (*Message, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Message{
+		ID:            uuid.New(),
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		EventType:     eventType,
+		Payload:       data,
+		CreatedAt:     time.Now(),
+	}, nil
+}
+
+// FromFeed создает сообщения для событий приемок и товаров
+func FromFeed(events ...event.Feed) ([]*Message, error) {
+	messages := make([]*Message, len(events))
+	for i, f := range events {
+		e := f.Event()
+		m, err := NewMessage(AggregateReception, e.ReceptionID, string(e.Type), e)
+		if err != nil {
+			return nil, err
+		}
+		messages[i] = m
+	}
+	return messages, nil
+}
+
+// Writer записывает сообщения в транзакции, открытой в ctx
+type Writer interface {
+	Add(ctx context.Context, messages ...*Message) error
+}
+
+// Discard не сохраняет сообщения
+var Discard Writer = discard{}
+
+type discard struct{}
+
+func (discard) Add(context.Context, ...*Message) error { return nil }
+
+// Repository определяет методы хранения сообщений для публикации
+type Repository interface {
+	Writer
+	// TryLock захватывает блокировку публикации до конца транзакции в ctx.
+	// Возвращает false, если сообщения уже публикует другой экземпляр.
+	TryLock(ctx context.Context) (bool, error)
+	// ListPending возвращает до limit неопубликованных сообщений по возрастанию номера,
+	// время публикации которых наступило к now. Сообщения агрегата, более раннее
+	// сообщение которого ждет повторной попытки, не возвращаются.
+	ListPending(ctx context.Context, now time.Time, limit int) ([]*Message, error)
+	// MarkPublished отмечает сообщение опубликованным
+	MarkPublished(ctx context.Context, id uuid.UUID, at time.Time) error
+	// MarkFailed увеличивает счетчик попыток, сохраняет причину неудачи
+	// и откладывает следующую попытку до nextAttemptAt
+	MarkFailed(ctx context.Context, id uuid.UUID, reason string, nextAttemptAt time.Time) error
+	// MarkDead увеличивает счетчик попыток, сохраняет причину неудачи
+	// и исключает сообщение из публикации
+	MarkDead(ctx context.Context, id uuid.UUID, reason string, at time.Time) error
+	// DeletePublishedBefore удаляет сообщения, опубликованные раньше before
+	DeletePublishedBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// Publisher публикует сообщение во внешнюю систему. Публикация может
+// повториться, поэтому получатель должен отбрасывать повторы по ID сообщения.
+type Publisher interface {
+	Publish(ctx context.Context, m *Message) error
+}
+
+// Fanout публикует сообщение всеми издателями по порядку. Первая ошибка
+// прерывает публикацию, и сообщение повторяется целиком при следующем проходе.
+type Fanout []Publisher
+
+// Publish реализует интерфейс Publisher
+func (f Fanout) Publish(ctx context.Context, m *Message) error {
+	for _, p := range f {
+		if err := p.Publish(ctx, m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// publisherFunc адаптирует функцию к интерфейсу Publisher
+type publisherFunc func(ctx context.Context, m *Message) error
+
+func (f publisherFunc) Publish(ctx context.Context, m *Message) error { return f(ctx, m) }
+
+func TestFanout_Publish(t *testing.T) {
+	var calls []string
+	publisher := func(name string, err error) Publisher {
+		return publisherFunc(func(context.Context, *Message) error {
+			calls = append(calls, name)
+			return err
+		})
+	}
+
+	m := &Message{EventType: "reception.closed"}
+
+	assert.NoError(t, Fanout{publisher("first", nil), publisher("second", nil)}.Publish(context.Background(), m))
+	assert.Equal(t, []string{"first", "second"}, calls)
+
+	// Ошибка первого издателя прерывает публикацию
+	calls = nil
+	err := Fanout{publisher("first", errors.New("unavailable")), publisher("second", nil)}.Publish(context.Background(), m)
+	assert.EqualError(t, err, "unavailable")
+	assert.Equal(t, []string{"first"}, calls)
+}
@@ -0,0 +1,37 @@
+package product
+
+import (
+	"github.com/avito/pvz/internal/domain/listing"
+	"github.com/google/uuid"
+)
+
+// Поля, по которым разрешена сортировка списка товаров
+const (
+	SortByDateTime = "date_time"
+	SortByType     = "type"
+)
+
+// ListFilter задает условия выборки списка товаров
+type ListFilter struct {
+	// ReceptionID ограничивает выборку одной приемкой, uuid.Nil — любая приемка
+	ReceptionID uuid.UUID
+	// Type ограничивает выборку типом товара, пустая строка — любой тип
+	Type     Type
+	DateTime listing.DateRange
+	Sort     listing.Sort
+	Page     listing.Page
+}
+
+// Validate проверяет корректность фильтра
+func (f ListFilter) Validate() error {
+	if f.Type != "" && !f.Type.Valid() {
+		return listing.ErrInvalidFilter
+	}
+	if err := f.Sort.Validate(SortByDateTime, SortByType); err != nil {
+		return err
+	}
+	if err := f.DateTime.Validate(); err != nil {
+		return err
+	}
+	return f.Page.Validate()
+}
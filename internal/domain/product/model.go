@@ -16,12 +16,31 @@ const (
 	TypeOther       Type = "other"
 )
 
+// Types возвращает все допустимые типы товаров
+func Types() []Type {
+	return []Type{TypeElectronics, TypeClothing, TypeFood, TypeOther}
+}
+
+// Valid сообщает, является ли тип товара допустимым
+func (t Type) Valid() bool {
+	switch t {
+	case TypeElectronics, TypeClothing, TypeFood, TypeOther:
+		return true
+	default:
+		return false
+	}
+}
+
 // Product представляет собой товар
 type Product struct {
 	ID          uuid.UUID `db:"id"`
 	DateTime    time.Time `db:"date_time"`
 	Type        Type      `db:"type"`
 	ReceptionID uuid.UUID `db:"reception_id"`
+	// Barcode штрихкод товара из файла поставщика, пустой для товаров, добавленных вручную
+	Barcode string `db:"barcode"`
+	// Metadata произвольные данные поставщика о товаре
+	Metadata string `db:"metadata"`
 }
 
 // New создает новый экземпляр Product
@@ -26,6 +26,8 @@ type Repository interface {
 
 	// List возвращает список товаров с пагинацией
 	List(ctx context.Context, offset, limit int) ([]*Product, error)
+	// ListByFilter возвращает список товаров, отобранных и отсортированных по фильтру
+	ListByFilter(ctx context.Context, filter ListFilter) ([]*Product, error)
 
 	// GetLast получает последний добавленный товар из приемки
 	GetLast(ctx context.Context, receptionID uuid.UUID) (*Product, error)
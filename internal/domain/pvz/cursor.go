@@ -0,0 +1,53 @@
+package pvz
+
+import (
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Cursor указывает позицию в списке ПВЗ для keyset-пагинации.
+// Список упорядочен по (CreatedAt, ID) по убыванию, курсор ссылается
+// на последний ПВЗ предыдущей страницы.
+type Cursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// Encode возвращает непрозрачное строковое представление курсора
+func (c Cursor) Encode() string {
+	raw := c.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + c.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor разбирает курсор, полученный от клиента.
+// Пустая строка означает первую страницу и возвращает nil.
+func DecodeCursor(s string) (*Cursor, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	parts := strings.SplitN(string(raw), "|", 2)
+	if len(parts) != 2 {
+		return nil, ErrInvalidCursor
+	}
+
+	createdAt, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	id, err := uuid.Parse(parts[1])
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	return &Cursor{CreatedAt: createdAt, ID: id}, nil
+}
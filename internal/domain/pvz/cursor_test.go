@@ -0,0 +1,74 @@
+package pvz
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursor_EncodeDecode(t *testing.T) {
+	cursor := Cursor{
+		CreatedAt: time.Date(2025, 4, 10, 12, 30, 15, 123456789, time.UTC),
+		ID:        uuid.New(),
+	}
+
+	decoded, err := DecodeCursor(cursor.Encode())
+	require.NoError(t, err)
+	require.NotNil(t, decoded)
+
+	assert.True(t, cursor.CreatedAt.Equal(decoded.CreatedAt))
+	assert.Equal(t, cursor.ID, decoded.ID)
+}
+
+func TestDecodeCursor(t *testing.T) {
+	tests := []struct {
+		name    string
+		cursor  string
+		wantNil bool
+		wantErr error
+	}{
+		{
+			name:    "пустой курсор - первая страница",
+			cursor:  "",
+			wantNil: true,
+		},
+		{
+			name:    "не base64",
+			cursor:  "!!!",
+			wantNil: true,
+			wantErr: ErrInvalidCursor,
+		},
+		{
+			name:    "нет разделителя",
+			cursor:  base64.RawURLEncoding.EncodeToString([]byte("2025-04-10T12:30:15Z")),
+			wantNil: true,
+			wantErr: ErrInvalidCursor,
+		},
+		{
+			name:    "неверная дата",
+			cursor:  base64.RawURLEncoding.EncodeToString([]byte("yesterday|" + uuid.New().String())),
+			wantNil: true,
+			wantErr: ErrInvalidCursor,
+		},
+		{
+			name:    "неверный ID",
+			cursor:  base64.RawURLEncoding.EncodeToString([]byte("2025-04-10T12:30:15Z|not-a-uuid")),
+			wantNil: true,
+			wantErr: ErrInvalidCursor,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeCursor(tt.cursor)
+			assert.Equal(t, tt.wantErr, err)
+			if tt.wantNil {
+				assert.Nil(t, got)
+			}
+		})
+	}
+}
@@ -14,4 +14,10 @@ var (
 
 	// ErrInvalidPagination ошибка, когда указаны неверные параметры пагинации
 	ErrInvalidPagination = errors.New("invalid pagination parameters")
+
+	// ErrInvalidCursor ошибка, когда передан неверный курсор пагинации
+	ErrInvalidCursor = errors.New("invalid cursor")
+
+	// ErrVersionConflict ошибка, когда ПВЗ был изменен после чтения
+	ErrVersionConflict = errors.New("pvz version conflict")
 )
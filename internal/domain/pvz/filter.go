@@ -0,0 +1,30 @@
+package pvz
+
+import "github.com/avito/pvz/internal/domain/listing"
+
+// Поля, по которым разрешена сортировка списка ПВЗ
+const (
+	SortByCreatedAt = "created_at"
+	SortByCity      = "city"
+)
+
+// ListFilter задает условия выборки списка ПВЗ
+type ListFilter struct {
+	// City ограничивает выборку одним городом, пустая строка — любой город
+	City string
+	// CreatedAt ограничивает дату регистрации ПВЗ
+	CreatedAt listing.DateRange
+	Sort      listing.Sort
+	Page      listing.Page
+}
+
+// Validate проверяет корректность фильтра
+func (f ListFilter) Validate() error {
+	if err := f.Sort.Validate(SortByCreatedAt, SortByCity); err != nil {
+		return err
+	}
+	if err := f.CreatedAt.Validate(); err != nil {
+		return err
+	}
+	return f.Page.Validate()
+}
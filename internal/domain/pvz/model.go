@@ -11,6 +11,8 @@ type PVZ struct {
 	ID        uuid.UUID `db:"id"`
 	CreatedAt time.Time `db:"created_at"`
 	City      string    `db:"city"`
+	// Version увеличивается при каждом изменении ПВЗ
+	Version int64 `db:"version"`
 }
 
 // New создает новый экземпляр PVZ
@@ -19,5 +21,6 @@ func New(city string) *PVZ {
 		ID:        uuid.New(),
 		CreatedAt: time.Now(),
 		City:      city,
+		Version:   1,
 	}
 }
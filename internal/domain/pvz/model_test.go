@@ -41,6 +41,9 @@ func TestNew(t *testing.T) {
 
 			// Проверяем город
 			assert.Equal(t, tt.want.City, got.City)
+
+			// Новый ПВЗ начинается с первой версии
+			assert.Equal(t, int64(1), got.Version)
 		})
 	}
 }
@@ -20,7 +20,8 @@ type Repository interface {
 	// GetByCity получает ПВЗ по городу
 	GetByCity(ctx context.Context, city string) (*PVZ, error)
 
-	// Update обновляет данные ПВЗ
+	// Update обновляет данные ПВЗ, если его версия совпадает с pvz.Version,
+	// и увеличивает версию. Иначе возвращает ErrVersionConflict.
 	Update(ctx context.Context, pvz *PVZ) error
 
 	// Delete удаляет ПВЗ по ID
@@ -29,9 +30,17 @@ type Repository interface {
 	// List возвращает список ПВЗ с пагинацией
 	List(ctx context.Context, offset, limit int) ([]*PVZ, error)
 
+	// ListByFilter возвращает список ПВЗ, отобранных и отсортированных по фильтру
+	ListByFilter(ctx context.Context, filter ListFilter) ([]*PVZ, error)
+
 	// GetWithReceptions получает список ПВЗ с приемками за период
 	GetWithReceptions(ctx context.Context, startDate, endDate time.Time, page, limit int) ([]*PVZWithReceptions, error)
 
+	// GetWithReceptionsByCursor получает страницу ПВЗ с приемками за период,
+	// начиная после курсора. Возвращает курсор следующей страницы или nil,
+	// если страница последняя.
+	GetWithReceptionsByCursor(ctx context.Context, startDate, endDate time.Time, cursor *Cursor, limit int) ([]*PVZWithReceptions, *Cursor, error)
+
 	// GetAll возвращает список всех ПВЗ
 	GetAll(ctx context.Context) ([]*PVZ, error)
 }
@@ -0,0 +1,217 @@
+// Package ratelimit описывает ограничение частоты запросов алгоритмом token bucket:
+// корзина вмещает Burst токенов и пополняется со скоростью Requests за Period,
+// каждый запрос забирает один токен, а запрос к пустой корзине отклоняется.
+package ratelimit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/user"
+)
+
+// ErrInvalidPolicy возвращается для политики с неверными лимитами
+var ErrInvalidPolicy = errors.New("invalid rate limit policy")
+
+// Duration длительность, которая в JSON записывается строкой time.ParseDuration, например "1m"
+type Duration time.Duration
+
+// UnmarshalJSON разбирает длительность из строки
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// MarshalJSON записывает длительность строкой
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// Limit лимит запросов: Requests за Period с запасом Burst.
+// Нулевой Requests снимает ограничение.
+type Limit struct {
+	Requests int      `json:"requests"`
+	Period   Duration `json:"period"`
+	// Burst емкость корзины — сколько запросов можно сделать подряд.
+	// По умолчанию равна Requests.
+	Burst int `json:"burst,omitempty"`
+}
+
+// Unlimited сообщает, что лимит не ограничивает запросы
+func (l Limit) Unlimited() bool {
+	return l.Requests <= 0
+}
+
+// Capacity возвращает емкость корзины
+func (l Limit) Capacity() float64 {
+	if l.Burst > 0 {
+		return float64(l.Burst)
+	}
+	return float64(l.Requests)
+}
+
+// rate возвращает скорость пополнения корзины в токенах в секунду
+func (l Limit) rate() float64 {
+	return float64(l.Requests) / time.Duration(l.Period).Seconds()
+}
+
+// validate проверяет, что лимит задан корректно
+func (l Limit) validate() error {
+	if l.Requests < 0 || l.Burst < 0 {
+		return fmt.Errorf("%w: negative limit", ErrInvalidPolicy)
+	}
+	if l.Requests > 0 && l.Period <= 0 {
+		return fmt.Errorf("%w: period must be positive", ErrInvalidPolicy)
+	}
+	return nil
+}
+
+// Bucket состояние корзины токенов
+type Bucket struct {
+	Tokens    float64
+	UpdatedAt time.Time
+}
+
+// NewBucket создает полную корзину
+func NewBucket(limit Limit, now time.Time) *Bucket {
+	return &Bucket{Tokens: limit.Capacity(), UpdatedAt: now}
+}
+
+// Decision результат проверки запроса
+type Decision struct {
+	Allowed bool
+	// Remaining число запросов, которые еще можно сделать без ожидания
+	Remaining int
+	// RetryAfter время, через которое в корзине появится токен для отклоненного запроса
+	RetryAfter time.Duration
+}
+
+// Take пополняет корзину за прошедшее время и забирает токен, если он есть
+func (b *Bucket) Take(limit Limit, now time.Time) Decision {
+	capacity := limit.Capacity()
+	if elapsed := now.Sub(b.UpdatedAt); elapsed > 0 {
+		b.Tokens = math.Min(capacity, b.Tokens+elapsed.Seconds()*limit.rate())
+		b.UpdatedAt = now
+	}
+
+	if b.Tokens >= 1 {
+		b.Tokens--
+		return Decision{Allowed: true, Remaining: int(b.Tokens)}
+	}
+
+	wait := (1 - b.Tokens) / limit.rate()
+	return Decision{RetryAfter: time.Duration(math.Ceil(wait * float64(time.Second)))}
+}
+
+// Rule лимит для маршрута и роли
+type Rule struct {
+	// Route маршрут HTTP в виде "МЕТОД /шаблон" (без префикса версии API)
+	// или полное имя метода gRPC; "*" подходит для любого маршрута
+	Route string `json:"route"`
+	// Role основная роль вызывающего; пустое значение подходит для любого
+	// вызывающего, в том числе анонимного
+	Role  user.Role `json:"role,omitempty"`
+	Limit Limit     `json:"limit"`
+}
+
+// Policy лимиты по маршрутам и ролям. Для запроса действует первое подходящее
+// правило Rules, а если такого нет — Default.
+type Policy struct {
+	Default Limit  `json:"default"`
+	Rules   []Rule `json:"rules"`
+}
+
+// DefaultPolicy политика по умолчанию: вход и регистрация ограничены строже
+// остальных маршрутов, чтобы усложнить перебор паролей, а добавление товаров —
+// чтобы сбойный сканер не заполнял приемку. Администраторам лимит выше.
+var DefaultPolicy = Policy{
+	Default: Limit{Requests: 300, Period: Duration(time.Minute), Burst: 60},
+	Rules: []Rule{
+		{Route: "POST /login", Limit: authLimit},
+		{Route: "POST /register", Limit: authLimit},
+		{Route: "POST /user/login", Limit: authLimit},
+		{Route: "POST /user/register", Limit: authLimit},
+		{Route: "POST /password/reset/request", Limit: authLimit},
+		{Route: "POST /email/verify/request", Limit: authLimit},
+		{Route: "POST /product", Limit: productLimit},
+		{Route: "POST /products", Limit: productLimit},
+		{Route: "POST /product/batch", Limit: productLimit},
+		{Route: "POST /reception/{id}/products/import", Limit: productLimit},
+		{Route: "*", Role: user.RoleAdmin, Limit: Limit{Requests: 1200, Period: Duration(time.Minute), Burst: 200}},
+	},
+}
+
+var (
+	// authLimit лимит входа, регистрации и запросов писем со ссылками
+	authLimit = Limit{Requests: 10, Period: Duration(time.Minute), Burst: 5}
+	// productLimit лимит добавления товаров
+	productLimit = Limit{Requests: 120, Period: Duration(time.Minute), Burst: 20}
+)
+
+// LoadPolicy читает политику из JSON-файла
+func LoadPolicy(path string) (Policy, error) {
+	var policy Policy
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return policy, err
+	}
+	if err := json.Unmarshal(data, &policy); err != nil {
+		return policy, fmt.Errorf("%w: %v", ErrInvalidPolicy, err)
+	}
+	return policy, nil
+}
+
+// Validate проверяет лимиты политики
+func (p Policy) Validate() error {
+	if err := p.Default.validate(); err != nil {
+		return err
+	}
+	for _, rule := range p.Rules {
+		if rule.Route == "" {
+			return fmt.Errorf("%w: rule without route", ErrInvalidPolicy)
+		}
+		if err := rule.Limit.validate(); err != nil {
+			return fmt.Errorf("%w (route %q)", err, rule.Route)
+		}
+	}
+	return nil
+}
+
+// Match возвращает лимит для маршрута и основной роли вызывающего
+func (p Policy) Match(route string, role user.Role) Limit {
+	for _, rule := range p.Rules {
+		if rule.Role != "" && rule.Role != role {
+			continue
+		}
+		if rule.Route == "*" || strings.EqualFold(rule.Route, route) {
+			return rule.Limit
+		}
+	}
+	return p.Default
+}
+
+// Repository хранит корзины, общие для всех экземпляров сервиса.
+// Методы вызываются в транзакции.
+type Repository interface {
+	// Lock блокирует корзину key до конца транзакции. Если корзины нет,
+	// она создается из initial.
+	Lock(ctx context.Context, key string, initial *Bucket) (*Bucket, error)
+	// Save сохраняет состояние корзины
+	Save(ctx context.Context, key string, bucket *Bucket) error
+	// DeleteIdle удаляет корзины, не менявшиеся с before
+	DeleteIdle(ctx context.Context, before time.Time) (int64, error)
+}
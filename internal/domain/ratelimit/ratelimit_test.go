@@ -0,0 +1,104 @@
+package ratelimit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/user"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBucket_Take(t *testing.T) {
+	limit := Limit{Requests: 60, Period: Duration(time.Minute), Burst: 2}
+	now := time.Date(2026, time.October, 1, 12, 0, 0, 0, time.UTC)
+	b := NewBucket(limit, now)
+
+	// Запас позволяет сделать Burst запросов подряд
+	first := b.Take(limit, now)
+	assert.True(t, first.Allowed)
+	assert.Equal(t, 1, first.Remaining)
+	assert.True(t, b.Take(limit, now).Allowed)
+
+	denied := b.Take(limit, now)
+	assert.False(t, denied.Allowed)
+	assert.Equal(t, time.Second, denied.RetryAfter)
+
+	// Через полсекунды токен еще не восполнен
+	denied = b.Take(limit, now.Add(500*time.Millisecond))
+	assert.False(t, denied.Allowed)
+	assert.Equal(t, 500*time.Millisecond, denied.RetryAfter)
+
+	assert.True(t, b.Take(limit, now.Add(time.Second)).Allowed)
+
+	// Корзина не наполняется сверх емкости
+	b.Take(limit, now.Add(time.Hour))
+	assert.Equal(t, 1.0, b.Tokens)
+}
+
+func TestPolicy_Match(t *testing.T) {
+	login := Limit{Requests: 5, Period: Duration(time.Minute)}
+	admin := Limit{Requests: 1000, Period: Duration(time.Minute)}
+	policy := Policy{
+		Default: Limit{Requests: 100, Period: Duration(time.Minute)},
+		Rules: []Rule{
+			{Route: "POST /login", Limit: login},
+			{Route: "*", Role: user.RoleAdmin, Limit: admin},
+		},
+	}
+
+	assert.Equal(t, login, policy.Match("POST /login", ""))
+	assert.Equal(t, login, policy.Match("post /login", user.RoleAdmin))
+	assert.Equal(t, admin, policy.Match("GET /pvz", user.RoleAdmin))
+	assert.Equal(t, policy.Default, policy.Match("GET /pvz", user.RoleEmployee))
+}
+
+func TestPolicy_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  Policy
+		wantErr bool
+	}{
+		{name: "политика по умолчанию", policy: DefaultPolicy},
+		{name: "без ограничений", policy: Policy{}},
+		{
+			name:    "лимит без периода",
+			policy:  Policy{Default: Limit{Requests: 10}},
+			wantErr: true,
+		},
+		{
+			name:    "правило без маршрута",
+			policy:  Policy{Rules: []Rule{{Limit: Limit{Requests: 1, Period: Duration(time.Second)}}}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Validate()
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidPolicy)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestLoadPolicy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ratelimit.json")
+	data := `{"default":{"requests":100,"period":"1m"},"rules":[{"route":"POST /login","limit":{"requests":5,"period":"1m","burst":2}}]}`
+	require.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+
+	policy, err := LoadPolicy(path)
+	require.NoError(t, err)
+	assert.Equal(t, Duration(time.Minute), policy.Default.Period)
+	require.Len(t, policy.Rules, 1)
+	assert.Equal(t, Limit{Requests: 5, Period: Duration(time.Minute), Burst: 2}, policy.Rules[0].Limit)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"default":{"requests":1,"period":"minute"}}`), 0o600))
+	_, err = LoadPolicy(path)
+	assert.ErrorIs(t, err, ErrInvalidPolicy)
+}
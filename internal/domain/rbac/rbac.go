@@ -0,0 +1,230 @@
+package rbac
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/avito/pvz/internal/domain/user"
+)
+
+// Permission разрешение на действие в формате ресурс:действие
+type Permission string
+
+const (
+	PVZCreate            Permission = "pvz:create"
+	PVZUpdate            Permission = "pvz:update"
+	PVZDelete            Permission = "pvz:delete"
+	PVZAccessAll         Permission = "pvz:access_all"
+	ReceptionCreate      Permission = "reception:create"
+	ReceptionClose       Permission = "reception:close"
+	ReceptionExport      Permission = "reception:export"
+	ProductCreate        Permission = "product:create"
+	ProductDelete        Permission = "product:delete"
+	UserManage           Permission = "user:manage"
+	SessionRevoke        Permission = "session:revoke"
+	WebhookManage        Permission = "webhook:manage"
+	EventsReadAll        Permission = "events:read_all"
+	EventsReadAssigned   Permission = "events:read_assigned"
+	JobReadAll           Permission = "job:read_all"
+	AssignmentManage     Permission = "assignment:manage"
+	ServiceAccountManage Permission = "service_account:manage"
+	AuditRead            Permission = "audit:read"
+)
+
+// Catalogue все известные разрешения. Политика может ссылаться только на них.
+var Catalogue = []Permission{
+	PVZCreate, PVZUpdate, PVZDelete, PVZAccessAll,
+	ReceptionCreate, ReceptionClose, ReceptionExport,
+	ProductCreate, ProductDelete,
+	UserManage, SessionRevoke, WebhookManage,
+	EventsReadAll, EventsReadAssigned,
+	JobReadAll,
+	AssignmentManage,
+	ServiceAccountManage,
+	AuditRead,
+}
+
+var (
+	// ErrForbidden возвращается, если у роли нет разрешения
+	ErrForbidden = errors.New("permission denied")
+	// ErrInvalidPolicy возвращается для политики с неизвестными разрешениями или ролями
+	ErrInvalidPolicy = errors.New("invalid rbac policy")
+)
+
+// Policy сопоставляет ролям разрешения. Aliases задает другие имена ролей:
+// токены и пользователи со старым именем получают права основной роли.
+type Policy struct {
+	Roles   map[user.Role][]Permission `json:"roles"`
+	Aliases map[user.Role]user.Role    `json:"aliases"`
+}
+
+// DefaultPolicy политика по умолчанию. Модератор из спецификации API —
+// это администратор, поэтому moderator является его псевдонимом.
+var DefaultPolicy = Policy{
+	Roles: map[user.Role][]Permission{
+		user.RoleAdmin: {
+			PVZCreate, PVZUpdate, PVZDelete, PVZAccessAll,
+			ReceptionExport,
+			UserManage, SessionRevoke, WebhookManage, AssignmentManage,
+			ServiceAccountManage,
+			EventsReadAll, JobReadAll, AuditRead,
+		},
+		user.RoleEmployee: {
+			ReceptionCreate, ReceptionClose,
+			ProductCreate, ProductDelete,
+			EventsReadAssigned,
+		},
+	},
+	Aliases: map[user.Role]user.Role{
+		"moderator": user.RoleAdmin,
+	},
+}
+
+// LoadPolicy читает политику из JSON-файла
+func LoadPolicy(path string) (Policy, error) {
+	var policy Policy
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return policy, err
+	}
+	if err := json.Unmarshal(data, &policy); err != nil {
+		return policy, fmt.Errorf("%w: %v", ErrInvalidPolicy, err)
+	}
+	return policy, nil
+}
+
+// Set набор разрешений
+type Set map[Permission]struct{}
+
+// NewSet создает набор из перечисленных разрешений
+func NewSet(permissions ...Permission) Set {
+	set := make(Set, len(permissions))
+	for _, p := range permissions {
+		set[p] = struct{}{}
+	}
+	return set
+}
+
+// Has сообщает, входит ли разрешение в набор
+func (s Set) Has(p Permission) bool {
+	_, ok := s[p]
+	return ok
+}
+
+// List возвращает разрешения набора в алфавитном порядке
+func (s Set) List() []Permission {
+	list := make([]Permission, 0, len(s))
+	for p := range s {
+		list = append(list, p)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i] < list[j] })
+	return list
+}
+
+// Authorizer проверяет разрешения ролей по политике. Один экземпляр используется
+// HTTP-middleware, gRPC-перехватчиками и сервисами.
+type Authorizer struct {
+	roles   map[user.Role]Set
+	aliases map[user.Role]user.Role
+}
+
+// NewAuthorizer создает Authorizer, проверяя, что политика ссылается только на
+// разрешения из Catalogue, а псевдонимы — на существующие роли
+func NewAuthorizer(policy Policy) (*Authorizer, error) {
+	known := NewSet(Catalogue...)
+
+	a := &Authorizer{
+		roles:   make(map[user.Role]Set, len(policy.Roles)),
+		aliases: make(map[user.Role]user.Role, len(policy.Aliases)),
+	}
+	for role, permissions := range policy.Roles {
+		for _, p := range permissions {
+			if !known.Has(p) {
+				return nil, fmt.Errorf("%w: unknown permission %q for role %q", ErrInvalidPolicy, p, role)
+			}
+		}
+		a.roles[role] = NewSet(permissions...)
+	}
+	for alias, role := range policy.Aliases {
+		if _, ok := a.roles[alias]; ok {
+			return nil, fmt.Errorf("%w: alias %q shadows a role", ErrInvalidPolicy, alias)
+		}
+		if _, ok := a.roles[role]; !ok {
+			return nil, fmt.Errorf("%w: alias %q refers to unknown role %q", ErrInvalidPolicy, alias, role)
+		}
+		a.aliases[alias] = role
+	}
+	return a, nil
+}
+
+// MustNewAuthorizer создает Authorizer и паникует при ошибке. Предназначен для
+// политик, заданных в коде, например DefaultPolicy.
+func MustNewAuthorizer(policy Policy) *Authorizer {
+	a, err := NewAuthorizer(policy)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+// Role возвращает основное имя роли: для псевдонима — роль, на которую он указывает
+func (a *Authorizer) Role(role user.Role) user.Role {
+	if canonical, ok := a.aliases[role]; ok {
+		return canonical
+	}
+	return role
+}
+
+// Known сообщает, описана ли роль или ее псевдоним в политике
+func (a *Authorizer) Known(role user.Role) bool {
+	_, ok := a.roles[a.Role(role)]
+	return ok
+}
+
+// Permissions возвращает разрешения роли. Для неизвестной роли набор пуст.
+func (a *Authorizer) Permissions(role user.Role) Set {
+	if set, ok := a.roles[a.Role(role)]; ok {
+		return set
+	}
+	return Set{}
+}
+
+// Can сообщает, есть ли у роли разрешение
+func (a *Authorizer) Can(role user.Role, p Permission) bool {
+	return a.Permissions(role).Has(p)
+}
+
+// Authorize возвращает ErrForbidden, если у роли нет разрешения
+func (a *Authorizer) Authorize(role user.Role, p Permission) error {
+	if !a.Can(role, p) {
+		return fmt.Errorf("%w: %s", ErrForbidden, p)
+	}
+	return nil
+}
+
+type contextKey struct{}
+
+// WithPermissions добавляет в контекст разрешения вызывающего
+func WithPermissions(ctx context.Context, permissions Set) context.Context {
+	return context.WithValue(ctx, contextKey{}, permissions)
+}
+
+// FromContext возвращает разрешения вызывающего. Для анонимного вызова набор пуст.
+func FromContext(ctx context.Context) Set {
+	if set, ok := ctx.Value(contextKey{}).(Set); ok {
+		return set
+	}
+	return Set{}
+}
+
+// Check возвращает ErrForbidden, если у вызывающего нет разрешения
+func Check(ctx context.Context, p Permission) error {
+	if !FromContext(ctx).Has(p) {
+		return fmt.Errorf("%w: %s", ErrForbidden, p)
+	}
+	return nil
+}
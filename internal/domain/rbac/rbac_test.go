@@ -0,0 +1,106 @@
+package rbac
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/avito/pvz/internal/domain/user"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthorizer_Can(t *testing.T) {
+	authz := MustNewAuthorizer(DefaultPolicy)
+
+	tests := []struct {
+		name       string
+		role       user.Role
+		permission Permission
+		want       bool
+	}{
+		{name: "администратор может создавать ПВЗ", role: user.RoleAdmin, permission: PVZCreate, want: true},
+		{name: "сотрудник не может создавать ПВЗ", role: user.RoleEmployee, permission: PVZCreate, want: false},
+		{name: "обычный пользователь не может создавать ПВЗ", role: user.RoleUser, permission: PVZCreate, want: false},
+		{name: "сотрудник закрывает приемку", role: user.RoleEmployee, permission: ReceptionClose, want: true},
+		{name: "модератор как псевдоним администратора", role: "moderator", permission: PVZCreate, want: true},
+		{name: "неизвестная роль", role: "guest", permission: PVZCreate, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, authz.Can(tt.role, tt.permission))
+		})
+	}
+}
+
+func TestAuthorizer_Role(t *testing.T) {
+	authz := MustNewAuthorizer(DefaultPolicy)
+
+	assert.Equal(t, user.RoleAdmin, authz.Role("moderator"))
+	assert.Equal(t, user.RoleEmployee, authz.Role(user.RoleEmployee))
+	assert.True(t, authz.Known("moderator"))
+	assert.False(t, authz.Known(user.RoleUser))
+}
+
+func TestNewAuthorizer(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  Policy
+		wantErr bool
+	}{
+		{name: "политика по умолчанию", policy: DefaultPolicy},
+		{
+			name:    "неизвестное разрешение",
+			policy:  Policy{Roles: map[user.Role][]Permission{user.RoleAdmin: {"pvz:burn"}}},
+			wantErr: true,
+		},
+		{
+			name:    "псевдоним неизвестной роли",
+			policy:  Policy{Roles: map[user.Role][]Permission{user.RoleAdmin: {}}, Aliases: map[user.Role]user.Role{"moderator": "root"}},
+			wantErr: true,
+		},
+		{
+			name: "псевдоним совпадает с ролью",
+			policy: Policy{
+				Roles:   map[user.Role][]Permission{user.RoleAdmin: {}, user.RoleEmployee: {}},
+				Aliases: map[user.Role]user.Role{user.RoleEmployee: user.RoleAdmin},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewAuthorizer(tt.policy)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidPolicy)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestLoadPolicy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rbac.json")
+	data := `{"roles":{"moderator":["pvz:create"]},"aliases":{"admin":"moderator"}}`
+	require.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+
+	policy, err := LoadPolicy(path)
+	require.NoError(t, err)
+
+	authz, err := NewAuthorizer(policy)
+	require.NoError(t, err)
+	// Токены со старым именем роли продолжают работать
+	assert.True(t, authz.Can(user.RoleAdmin, PVZCreate))
+}
+
+func TestCheck(t *testing.T) {
+	ctx := WithPermissions(context.Background(), NewSet(ReceptionCreate))
+
+	assert.NoError(t, Check(ctx, ReceptionCreate))
+	assert.ErrorIs(t, Check(ctx, ReceptionClose), ErrForbidden)
+	assert.ErrorIs(t, Check(context.Background(), ReceptionCreate), ErrForbidden)
+}
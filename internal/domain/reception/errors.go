@@ -11,4 +11,7 @@ var (
 
 	// ErrReceptionAlreadyOpen возвращается, когда для ПВЗ уже есть открытая приёмка
 	ErrReceptionAlreadyOpen = errors.New("reception already open")
+
+	// ErrVersionConflict возвращается, когда приёмка была изменена после чтения
+	ErrVersionConflict = errors.New("reception version conflict")
 )
@@ -0,0 +1,31 @@
+package reception
+
+import (
+	"context"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/product"
+	"github.com/google/uuid"
+)
+
+// ExportRow строка выгрузки приемок: приемка вместе с одним из ее товаров.
+// Приемка без товаров выгружается одной строкой с пустыми полями товара.
+type ExportRow struct {
+	PVZID             uuid.UUID
+	PVZCity           string
+	ReceptionID       uuid.UUID
+	ReceptionDateTime time.Time
+	Status            Status
+	ProductID         uuid.NullUUID
+	ProductDateTime   *time.Time
+	ProductType       product.Type
+	Barcode           string
+}
+
+// Exporter построчно выгружает приемки с товарами
+type Exporter interface {
+	// ExportWithProducts передает в fn приемки за период вместе с товарами по мере
+	// чтения из хранилища, не загружая выборку в память целиком.
+	// Ошибка, возвращенная fn, прерывает выгрузку.
+	ExportWithProducts(ctx context.Context, startDate, endDate time.Time, fn func(*ExportRow) error) error
+}
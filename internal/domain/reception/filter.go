@@ -0,0 +1,37 @@
+package reception
+
+import (
+	"github.com/avito/pvz/internal/domain/listing"
+	"github.com/google/uuid"
+)
+
+// Поля, по которым разрешена сортировка списка приемок
+const (
+	SortByDateTime = "date_time"
+	SortByStatus   = "status"
+)
+
+// ListFilter задает условия выборки списка приемок
+type ListFilter struct {
+	// PVZID ограничивает выборку одним ПВЗ, uuid.Nil — любой ПВЗ
+	PVZID uuid.UUID
+	// Status ограничивает выборку статусом, пустая строка — любой статус
+	Status   Status
+	DateTime listing.DateRange
+	Sort     listing.Sort
+	Page     listing.Page
+}
+
+// Validate проверяет корректность фильтра
+func (f ListFilter) Validate() error {
+	if f.Status != "" && !f.Status.Valid() {
+		return listing.ErrInvalidFilter
+	}
+	if err := f.Sort.Validate(SortByDateTime, SortByStatus); err != nil {
+		return err
+	}
+	if err := f.DateTime.Validate(); err != nil {
+		return err
+	}
+	return f.Page.Validate()
+}
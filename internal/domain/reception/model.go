@@ -14,12 +14,19 @@ const (
 	StatusClose      Status = "close"
 )
 
+// Valid сообщает, является ли статус допустимым
+func (s Status) Valid() bool {
+	return s == StatusInProgress || s == StatusClose
+}
+
 // Reception представляет собой приемку товаров
 type Reception struct {
 	ID       uuid.UUID `db:"id"`
 	DateTime time.Time `db:"date_time"`
 	PVZID    uuid.UUID `db:"pvz_id"`
 	Status   Status    `db:"status"`
+	// Version увеличивается при каждом изменении приемки
+	Version int64 `db:"version"`
 }
 
 // New создает новый экземпляр Reception
@@ -29,6 +36,7 @@ func New(pvzID uuid.UUID) *Reception {
 		DateTime: time.Now(),
 		PVZID:    pvzID,
 		Status:   StatusInProgress,
+		Version:  1,
 	}
 }
 
@@ -39,6 +39,7 @@ func TestNew(t *testing.T) {
 			// Проверяем остальные поля
 			assert.Equal(t, tt.want.PVZID, got.PVZID)
 			assert.Equal(t, tt.want.Status, got.Status)
+			assert.Equal(t, int64(1), got.Version)
 		})
 	}
 }
@@ -15,7 +15,13 @@ type Repository interface {
 	// GetByID получает приемку по ID
 	GetByID(ctx context.Context, id uuid.UUID) (*Reception, error)
 
-	// Update обновляет данные приемки
+	// GetByIDForUpdate получает приемку по ID и блокирует ее до конца транзакции
+	// из ctx. Изменения приемки и ее товаров под блокировкой фиксируются в порядке
+	// записи их событий в outbox.
+	GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*Reception, error)
+
+	// Update обновляет данные приемки, если ее версия совпадает с reception.Version,
+	// и увеличивает версию. Иначе возвращает ErrVersionConflict.
 	Update(ctx context.Context, reception *Reception) error
 
 	// Delete удаляет приемку по ID
@@ -23,6 +29,8 @@ type Repository interface {
 
 	// List возвращает список приемок с пагинацией
 	List(ctx context.Context, offset, limit int) ([]*Reception, error)
+	// ListByFilter возвращает список приемок, отобранных и отсортированных по фильтру
+	ListByFilter(ctx context.Context, filter ListFilter) ([]*Reception, error)
 
 	// GetOpenByPVZID получает открытую приемку для ПВЗ
 	GetOpenByPVZID(ctx context.Context, pvzID uuid.UUID) (*Reception, error)
@@ -0,0 +1,92 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrNotFound возвращается, когда токен обновления не найден
+var ErrNotFound = errors.New("refresh token not found")
+
+// RefreshToken токен обновления. Токены одной сессии образуют цепочку:
+// при обновлении текущий токен помечается использованным и заменяется новым.
+type RefreshToken struct {
+	ID        uuid.UUID
+	SessionID uuid.UUID
+	UserID    uuid.UUID
+	// TokenHash SHA-256 токена, сам токен не хранится
+	TokenHash string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	// UsedAt время обмена токена на новый
+	UsedAt *time.Time
+	// ReplacedBy ID токена, выданного взамен
+	ReplacedBy *uuid.UUID
+	// RevokedAt время отзыва сессии
+	RevokedAt *time.Time
+}
+
+// Expired сообщает, истек ли срок действия токена к моменту now
+func (t *RefreshToken) Expired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
+
+// Active сообщает, можно ли обменять токен на новый
+func (t *RefreshToken) Active(now time.Time) bool {
+	return t.UsedAt == nil && t.RevokedAt == nil && !t.Expired(now)
+}
+
+// RevocationKind тип записи в списке отзыва
+type RevocationKind string
+
+const (
+	// RevocationSession отзывает токены доступа одной сессии
+	RevocationSession RevocationKind = "session"
+	// RevocationUser отзывает все токены доступа пользователя, выпущенные до RevokedAt
+	RevocationUser RevocationKind = "user"
+)
+
+// Revocation запись в списке отзыва токенов доступа. Запись нужна, пока
+// могут действовать выпущенные до отзыва токены, и удаляется после ExpiresAt.
+type Revocation struct {
+	Kind RevocationKind
+	// SubjectID ID сессии или пользователя
+	SubjectID uuid.UUID
+	RevokedAt time.Time
+	ExpiresAt time.Time
+}
+
+// Repository определяет методы хранения токенов обновления и списка отзыва
+type Repository interface {
+	// CreateRefreshToken сохраняет токен обновления
+	CreateRefreshToken(ctx context.Context, token *RefreshToken) error
+
+	// GetRefreshTokenByHash получает токен обновления по хешу
+	GetRefreshTokenByHash(ctx context.Context, hash string) (*RefreshToken, error)
+
+	// UseRefreshToken помечает токен использованным и замененным на replacedBy.
+	// Возвращает false, если токен уже использован или отозван.
+	UseRefreshToken(ctx context.Context, id, replacedBy uuid.UUID, at time.Time) (bool, error)
+
+	// RevokeSession отзывает все токены обновления сессии
+	RevokeSession(ctx context.Context, sessionID uuid.UUID, at time.Time) error
+
+	// RevokeUserSessions отзывает все токены обновления пользователя и
+	// возвращает ID сессий, в которых были неотозванные токены
+	RevokeUserSessions(ctx context.Context, userID uuid.UUID, at time.Time) ([]uuid.UUID, error)
+
+	// DeleteExpiredRefreshTokens удаляет токены обновления, истекшие раньше before
+	DeleteExpiredRefreshTokens(ctx context.Context, before time.Time) (int64, error)
+
+	// AddRevocation добавляет запись в список отзыва или продлевает существующую
+	AddRevocation(ctx context.Context, revocation *Revocation) error
+
+	// ListRevocations возвращает записи списка отзыва, действующие после now
+	ListRevocations(ctx context.Context, now time.Time) ([]*Revocation, error)
+
+	// DeleteExpiredRevocations удаляет записи списка отзыва, истекшие раньше before
+	DeleteExpiredRevocations(ctx context.Context, before time.Time) (int64, error)
+}
@@ -13,6 +13,9 @@ const (
 	RoleAdmin    Role = "admin"
 	RoleEmployee Role = "employee"
 	RoleUser     Role = "user"
+	// RoleService роль сервисного аккаунта, вызывающего API по ключу.
+	// Права сервисного аккаунта задаются областями ключа, а не политикой ролей.
+	RoleService Role = "service"
 )
 
 // User представляет собой пользователя системы
@@ -22,6 +25,8 @@ type User struct {
 	Password  string    `db:"password"`
 	Role      Role      `db:"role"`
 	CreatedAt time.Time `db:"created_at"`
+	// EmailVerifiedAt время подтверждения email, nil — email не подтвержден
+	EmailVerifiedAt *time.Time `db:"email_verified_at"`
 }
 
 // New создает нового пользователя
@@ -34,8 +39,3 @@ func New(email, password string, role Role) *User {
 		CreatedAt: time.Now(),
 	}
 }
-
-// CanCreatePVZ проверяет, может ли пользователь создавать ПВЗ
-func (u *User) CanCreatePVZ() bool {
-	return u.Role == RoleAdmin
-}
@@ -68,40 +68,10 @@ func TestNew(t *testing.T) {
 	}
 }
 
-func TestUser_CanCreatePVZ(t *testing.T) {
-	tests := []struct {
-		name string
-		user *User
-		want bool
-	}{
-		{
-			name: "администратор может создавать ПВЗ",
-			user: &User{Role: RoleAdmin},
-			want: true,
-		},
-		{
-			name: "сотрудник не может создавать ПВЗ",
-			user: &User{Role: RoleEmployee},
-			want: false,
-		},
-		{
-			name: "обычный пользователь не может создавать ПВЗ",
-			user: &User{Role: RoleUser},
-			want: false,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got := tt.user.CanCreatePVZ()
-			assert.Equal(t, tt.want, got)
-		})
-	}
-}
-
 func TestRole_Constants(t *testing.T) {
 	// Проверяем константы ролей
 	assert.Equal(t, Role("admin"), RoleAdmin)
 	assert.Equal(t, Role("employee"), RoleEmployee)
 	assert.Equal(t, Role("user"), RoleUser)
+	assert.Equal(t, Role("service"), RoleService)
 }
@@ -0,0 +1,73 @@
+// Package usertoken описывает одноразовые токены из писем пользователю:
+// сброс пароля и подтверждение email. Токен действует ограниченное время,
+// может быть использован один раз, а в хранилище попадает только его хеш.
+package usertoken
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrNotFound возвращается, когда действующий токен не найден: его нет,
+// он истек или уже использован
+var ErrNotFound = errors.New("user token not found")
+
+// Purpose назначение токена
+type Purpose string
+
+const (
+	// PurposePasswordReset сброс забытого пароля
+	PurposePasswordReset Purpose = "password_reset"
+	// PurposeEmailVerification подтверждение email
+	PurposeEmailVerification Purpose = "email_verification"
+)
+
+// Token одноразовый токен пользователя
+type Token struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+	// Email адрес, на который отправлен токен. Токен не действует,
+	// если пользователь успел сменить email.
+	Email   string
+	Purpose Purpose
+	// TokenHash SHA-256 токена, сам токен не хранится
+	TokenHash string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	// UsedAt время использования токена
+	UsedAt *time.Time
+}
+
+// New создает токен с назначением purpose, действующий ttl с момента now
+func New(userID uuid.UUID, email string, purpose Purpose, hash string, now time.Time, ttl time.Duration) *Token {
+	return &Token{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Email:     email,
+		Purpose:   purpose,
+		TokenHash: hash,
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}
+}
+
+// Repository определяет методы хранения одноразовых токенов
+type Repository interface {
+	// Create сохраняет токен
+	Create(ctx context.Context, token *Token) error
+
+	// Use помечает действующий токен с хешем hash и назначением purpose
+	// использованным и возвращает его. Возвращает ErrNotFound, если токен
+	// не найден, истек к моменту at или уже использован.
+	Use(ctx context.Context, hash string, purpose Purpose, at time.Time) (*Token, error)
+
+	// Invalidate помечает использованными все неиспользованные токены
+	// пользователя с назначением purpose
+	Invalidate(ctx context.Context, userID uuid.UUID, purpose Purpose, at time.Time) error
+
+	// DeleteExpired удаляет токены, истекшие раньше before
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+}
@@ -0,0 +1,108 @@
+// Package webhook описывает подписки внешних систем на события
+// и доставки событий по этим подпискам.
+package webhook
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/event"
+	"github.com/avito/pvz/internal/domain/listing"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrNotFound возвращается, когда подписка не найдена
+	ErrNotFound = errors.New("webhook subscription not found")
+	// ErrDeliveryNotFound возвращается, когда доставка не найдена
+	ErrDeliveryNotFound = errors.New("webhook delivery not found")
+)
+
+// Subscription подписка внешней системы на события
+type Subscription struct {
+	ID         uuid.UUID
+	URL        string
+	EventTypes []event.Type
+	// Secret ключ подписи HMAC-SHA256, известный получателю
+	Secret    string
+	CreatedBy uuid.UUID
+	CreatedAt time.Time
+}
+
+// Matches сообщает, подписана ли подписка на события типа t
+func (s *Subscription) Matches(t event.Type) bool {
+	for _, et := range s.EventTypes {
+		if et == t {
+			return true
+		}
+	}
+	return false
+}
+
+// DeliveryStatus статус доставки события
+type DeliveryStatus string
+
+const (
+	// DeliveryPending доставка ожидает очередной попытки
+	DeliveryPending DeliveryStatus = "pending"
+	// DeliverySucceeded получатель подтвердил доставку ответом 2xx
+	DeliverySucceeded DeliveryStatus = "succeeded"
+	// DeliveryDead попытки исчерпаны, доставка перенесена в dead-letter
+	DeliveryDead DeliveryStatus = "dead"
+)
+
+// Valid сообщает, является ли статус допустимым
+func (s DeliveryStatus) Valid() bool {
+	return s == DeliveryPending || s == DeliverySucceeded || s == DeliveryDead
+}
+
+// Delivery доставка одного события по одной подписке
+type Delivery struct {
+	ID             uuid.UUID
+	SubscriptionID uuid.UUID
+	EventType      event.Type
+	// Payload тело запроса к получателю, одинаковое во всех попытках
+	Payload       []byte
+	Status        DeliveryStatus
+	Attempts      int
+	NextAttemptAt time.Time
+	// LastStatusCode код ответа последней попытки, 0 — ответа не было
+	LastStatusCode int
+	LastError      string
+	CreatedAt      time.Time
+	DeliveredAt    *time.Time
+}
+
+// DeliveryFilter параметры выборки истории доставок
+type DeliveryFilter struct {
+	SubscriptionID uuid.UUID
+	// Status ограничивает выборку статусом, пустая строка — любой статус
+	Status DeliveryStatus
+	Page   listing.Page
+}
+
+// Repository определяет методы для хранения подписок и доставок
+type Repository interface {
+	// CreateSubscription сохраняет новую подписку
+	CreateSubscription(ctx context.Context, s *Subscription) error
+	// GetSubscription получает подписку по ID
+	GetSubscription(ctx context.Context, id uuid.UUID) (*Subscription, error)
+	// ListSubscriptions возвращает все подписки
+	ListSubscriptions(ctx context.Context) ([]*Subscription, error)
+	// DeleteSubscription удаляет подписку вместе с ее доставками
+	DeleteSubscription(ctx context.Context, id uuid.UUID) error
+
+	// CreateDeliveries сохраняет новые доставки
+	CreateDeliveries(ctx context.Context, deliveries []*Delivery) error
+	// ClaimDueDeliveries выбирает до limit ожидающих доставок, время попытки которых
+	// наступило к now, и откладывает их следующую попытку на lease, чтобы другой
+	// обработчик не отправил их одновременно
+	ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*Delivery, error)
+	// GetDelivery получает доставку по ID
+	GetDelivery(ctx context.Context, id uuid.UUID) (*Delivery, error)
+	// UpdateDelivery сохраняет результат попытки доставки
+	UpdateDelivery(ctx context.Context, d *Delivery) error
+	// ListDeliveries возвращает историю доставок подписки, новые первыми
+	ListDeliveries(ctx context.Context, filter DeliveryFilter) ([]*Delivery, error)
+}
@@ -0,0 +1,279 @@
+// Package apperror сопоставляет ошибки сервисов и домена с HTTP-статусами,
+// gRPC-кодами и стабильными кодами ошибок для клиентов.
+package apperror
+
+import (
+	"errors"
+	"net/http"
+
+	domainAPIKey "github.com/avito/pvz/internal/domain/apikey"
+	domainAssignment "github.com/avito/pvz/internal/domain/assignment"
+	domainJob "github.com/avito/pvz/internal/domain/job"
+	"github.com/avito/pvz/internal/domain/listing"
+	"github.com/avito/pvz/internal/domain/lockout"
+	domainProduct "github.com/avito/pvz/internal/domain/product"
+	domainPVZ "github.com/avito/pvz/internal/domain/pvz"
+	"github.com/avito/pvz/internal/domain/rbac"
+	domainReception "github.com/avito/pvz/internal/domain/reception"
+	domainUser "github.com/avito/pvz/internal/domain/user"
+	domainWebhook "github.com/avito/pvz/internal/domain/webhook"
+	"github.com/avito/pvz/internal/handler/i18n"
+	accountService "github.com/avito/pvz/internal/service/account"
+	apikeyService "github.com/avito/pvz/internal/service/apikey"
+	assignmentService "github.com/avito/pvz/internal/service/assignment"
+	exportService "github.com/avito/pvz/internal/service/export"
+	jobService "github.com/avito/pvz/internal/service/job"
+	lockoutService "github.com/avito/pvz/internal/service/lockout"
+	productService "github.com/avito/pvz/internal/service/product"
+	pvzService "github.com/avito/pvz/internal/service/pvz"
+	receptionService "github.com/avito/pvz/internal/service/reception"
+	sessionService "github.com/avito/pvz/internal/service/session"
+	userService "github.com/avito/pvz/internal/service/user"
+	webhookService "github.com/avito/pvz/internal/service/webhook"
+	"google.golang.org/grpc/codes"
+)
+
+// Code стабильный машиночитаемый код ошибки
+type Code string
+
+// Общие коды ошибок
+const (
+	CodeInvalidRequest  Code = "invalid_request"
+	CodeUnauthorized    Code = "unauthorized"
+	CodeInvalidToken    Code = "invalid_token"
+	CodeAccessDenied    Code = "access_denied"
+	CodePayloadTooLarge Code = "payload_too_large"
+	CodeInternal        Code = "internal_error"
+)
+
+// Коды ошибок домена
+const (
+	CodeInvalidCity              Code = "invalid_city"
+	CodeInvalidPVZData           Code = "invalid_pvz_data"
+	CodePVZNotFound              Code = "pvz_not_found"
+	CodePVZAlreadyExists         Code = "pvz_already_exists"
+	CodePVZVersionConflict       Code = "pvz_version_conflict"
+	CodeInvalidPagination        Code = "invalid_pagination"
+	CodeInvalidCursor            Code = "invalid_cursor"
+	CodeReceptionNotFound        Code = "reception_not_found"
+	CodeReceptionAlreadyOpen     Code = "reception_already_open"
+	CodeReceptionAlreadyClosed   Code = "reception_already_closed"
+	CodeReceptionVersionConflict Code = "reception_version_conflict"
+	CodeNoOpenReception          Code = "no_open_reception"
+	CodeProductNotFound          Code = "product_not_found"
+	CodeInvalidProductType       Code = "invalid_product_type"
+	CodeImportEmpty              Code = "import_empty"
+	CodeImportTooLarge           Code = "import_too_large"
+	CodeInvalidEmail             Code = "invalid_email"
+	CodeInvalidPassword          Code = "invalid_password"
+	CodeInvalidRole              Code = "invalid_role"
+	CodeInvalidCredentials       Code = "invalid_credentials"
+	CodeUserNotFound             Code = "user_not_found"
+	CodeUserAlreadyExists        Code = "user_already_exists"
+	CodeInvalidExportFormat      Code = "invalid_export_format"
+	CodeInvalidDateRange         Code = "invalid_date_range"
+	CodeInvalidSort              Code = "invalid_sort"
+	CodeInvalidFilter            Code = "invalid_filter"
+	CodeInvalidPage              Code = "invalid_page"
+	CodeIdempotencyKeyReused     Code = "idempotency_key_reused"
+	CodeIdempotencyInProgress    Code = "idempotency_request_in_progress"
+	CodeQueryTooDeep             Code = "query_too_deep"
+	CodeQueryTooComplex          Code = "query_too_complex"
+	CodeWebhookNotFound          Code = "webhook_not_found"
+	CodeWebhookDeliveryNotFound  Code = "webhook_delivery_not_found"
+	CodeInvalidWebhookURL        Code = "invalid_webhook_url"
+	CodeInvalidWebhookEvents     Code = "invalid_webhook_event_types"
+	CodeInvalidDeliveryStatus    Code = "invalid_webhook_delivery_status"
+	CodeWebhookDeliveryNotDead   Code = "webhook_delivery_not_dead"
+	CodeJobNotFound              Code = "job_not_found"
+	CodeUnknownJobType           Code = "unknown_job_type"
+	CodeInvalidJobParams         Code = "invalid_job_params"
+	CodeJobTypeForbidden         Code = "job_type_forbidden"
+	CodeTooManyJobs              Code = "too_many_jobs"
+	CodeJobNotCancellable        Code = "job_not_cancellable"
+	CodeJobResultNotReady        Code = "job_result_not_ready"
+	CodeInvalidRefreshToken      Code = "invalid_refresh_token"
+	CodeRefreshTokenReused       Code = "refresh_token_reused"
+	CodeAssignmentNotFound       Code = "assignment_not_found"
+	CodePVZNotAssigned           Code = "pvz_not_assigned"
+	CodeInvalidAssignmentPeriod  Code = "invalid_assignment_period"
+	CodeRateLimited              Code = "rate_limited"
+	CodeLoginLocked              Code = "login_locked"
+	CodeInvalidAccountToken      Code = "invalid_account_token"
+	CodeEmailAlreadyVerified     Code = "email_already_verified"
+	CodeServiceAccountNotFound   Code = "service_account_not_found"
+	CodeAPIKeyNotFound           Code = "api_key_not_found"
+	CodeInvalidServiceAccount    Code = "invalid_service_account_name"
+	CodeInvalidAPIKeyScopes      Code = "invalid_api_key_scopes"
+	CodeInvalidAPIKeyExpiry      Code = "invalid_api_key_expiry"
+)
+
+// Ошибки уровня обработчиков, для которых нет ошибки сервиса
+var (
+	// ErrUnauthorized запрос без авторизации
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrInvalidToken недействительный токен или заголовок авторизации
+	ErrInvalidToken = errors.New("invalid token")
+	// ErrAccessDenied у пользователя нет нужного разрешения
+	ErrAccessDenied = errors.New("access denied")
+	// ErrInvalidCredentials неверная пара email и пароль при входе
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	// ErrIdempotencyKeyReused ключ идемпотентности использован с другим запросом
+	ErrIdempotencyKeyReused = errors.New("idempotency key reused")
+	// ErrIdempotencyInProgress исходный запрос с ключом идемпотентности еще выполняется
+	ErrIdempotencyInProgress = errors.New("idempotency request in progress")
+	// ErrQueryTooDeep GraphQL-запрос превышает допустимую глубину вложенности
+	ErrQueryTooDeep = errors.New("query too deep")
+	// ErrQueryTooComplex GraphQL-запрос превышает допустимую сложность
+	ErrQueryTooComplex = errors.New("query too complex")
+	// ErrRateLimited вызывающий превысил лимит частоты запросов
+	ErrRateLimited = errors.New("rate limited")
+)
+
+// Error описание ошибки для клиента
+type Error struct {
+	Code       Code
+	HTTPStatus int
+	GRPCCode   codes.Code
+	// MessageKey ключ сообщения в каталоге i18n, по умолчанию совпадает с кодом
+	MessageKey string
+}
+
+// Key возвращает ключ сообщения об ошибке в каталоге i18n
+func (e Error) Key() string {
+	if e.MessageKey != "" {
+		return e.MessageKey
+	}
+	return string(e.Code)
+}
+
+// Message возвращает сообщение об ошибке на языке lang
+func (e Error) Message(lang i18n.Lang) string {
+	return i18n.Message(lang, e.Key())
+}
+
+// Error реализует интерфейс error
+func (e Error) Error() string {
+	return string(e.Code) + ": " + e.Message(i18n.English)
+}
+
+// InvalidRequest описывает ошибку в параметрах запроса с сообщением по ключу key
+func InvalidRequest(key string) Error {
+	return Error{Code: CodeInvalidRequest, HTTPStatus: http.StatusBadRequest, GRPCCode: codes.InvalidArgument, MessageKey: key}
+}
+
+// PayloadTooLarge описывает слишком большой запрос с сообщением по ключу key
+func PayloadTooLarge(key string) Error {
+	return Error{Code: CodePayloadTooLarge, HTTPStatus: http.StatusRequestEntityTooLarge, GRPCCode: codes.ResourceExhausted, MessageKey: key}
+}
+
+// Internal описывает непредвиденную ошибку с сообщением по ключу key
+func Internal(key string) Error {
+	return Error{Code: CodeInternal, HTTPStatus: http.StatusInternalServerError, GRPCCode: codes.Internal, MessageKey: key}
+}
+
+// mapping сопоставление ошибок с их описанием для клиента.
+// Ошибки сервисов и домена с одинаковым смыслом получают один код.
+var mapping = []struct {
+	errs []error
+	info Error
+}{
+	{[]error{ErrUnauthorized, assignmentService.ErrUnauthorized, lockoutService.ErrUnauthorized, accountService.ErrUnauthorized}, Error{Code: CodeUnauthorized, HTTPStatus: http.StatusUnauthorized, GRPCCode: codes.Unauthenticated}},
+	{[]error{ErrInvalidToken, sessionService.ErrTokenRevoked, apikeyService.ErrInvalidKey}, Error{Code: CodeInvalidToken, HTTPStatus: http.StatusUnauthorized, GRPCCode: codes.Unauthenticated}},
+	{[]error{ErrAccessDenied, rbac.ErrForbidden, pvzService.ErrAccessDenied, pvzService.ErrUnauthorized}, Error{Code: CodeAccessDenied, HTTPStatus: http.StatusForbidden, GRPCCode: codes.PermissionDenied}},
+	{[]error{ErrInvalidCredentials}, Error{Code: CodeInvalidCredentials, HTTPStatus: http.StatusUnauthorized, GRPCCode: codes.Unauthenticated}},
+	{[]error{ErrIdempotencyKeyReused}, Error{Code: CodeIdempotencyKeyReused, HTTPStatus: http.StatusUnprocessableEntity, GRPCCode: codes.FailedPrecondition}},
+	{[]error{ErrIdempotencyInProgress}, Error{Code: CodeIdempotencyInProgress, HTTPStatus: http.StatusConflict, GRPCCode: codes.Aborted}},
+	{[]error{ErrQueryTooDeep}, Error{Code: CodeQueryTooDeep, HTTPStatus: http.StatusBadRequest, GRPCCode: codes.InvalidArgument}},
+	{[]error{ErrQueryTooComplex}, Error{Code: CodeQueryTooComplex, HTTPStatus: http.StatusBadRequest, GRPCCode: codes.InvalidArgument}},
+	{[]error{ErrRateLimited}, Error{Code: CodeRateLimited, HTTPStatus: http.StatusTooManyRequests, GRPCCode: codes.ResourceExhausted}},
+	{[]error{lockout.ErrLocked}, Error{Code: CodeLoginLocked, HTTPStatus: http.StatusTooManyRequests, GRPCCode: codes.ResourceExhausted}},
+
+	{[]error{pvzService.ErrInvalidCity, domainPVZ.ErrInvalidCity}, Error{Code: CodeInvalidCity, HTTPStatus: http.StatusBadRequest, GRPCCode: codes.InvalidArgument}},
+	{[]error{pvzService.ErrInvalidPVZData}, Error{Code: CodeInvalidPVZData, HTTPStatus: http.StatusBadRequest, GRPCCode: codes.InvalidArgument}},
+	{[]error{pvzService.ErrPVZNotFound, receptionService.ErrPVZNotFound, domainPVZ.ErrNotFound}, Error{Code: CodePVZNotFound, HTTPStatus: http.StatusNotFound, GRPCCode: codes.NotFound}},
+	{[]error{pvzService.ErrPVZAlreadyExists, pvzService.ErrDuplicatePVZ}, Error{Code: CodePVZAlreadyExists, HTTPStatus: http.StatusConflict, GRPCCode: codes.AlreadyExists}},
+	{[]error{pvzService.ErrVersionConflict, domainPVZ.ErrVersionConflict}, Error{Code: CodePVZVersionConflict, HTTPStatus: http.StatusPreconditionFailed, GRPCCode: codes.FailedPrecondition}},
+	{[]error{pvzService.ErrInvalidPagination, domainPVZ.ErrInvalidPagination}, Error{Code: CodeInvalidPagination, HTTPStatus: http.StatusBadRequest, GRPCCode: codes.InvalidArgument}},
+	{[]error{pvzService.ErrInvalidCursor, domainPVZ.ErrInvalidCursor}, Error{Code: CodeInvalidCursor, HTTPStatus: http.StatusBadRequest, GRPCCode: codes.InvalidArgument}},
+
+	{[]error{receptionService.ErrReceptionNotFound, productService.ErrReceptionNotFound, domainReception.ErrNotFound}, Error{Code: CodeReceptionNotFound, HTTPStatus: http.StatusNotFound, GRPCCode: codes.NotFound}},
+	{[]error{receptionService.ErrReceptionAlreadyOpen, domainReception.ErrReceptionAlreadyOpen}, Error{Code: CodeReceptionAlreadyOpen, HTTPStatus: http.StatusBadRequest, GRPCCode: codes.FailedPrecondition}},
+	{[]error{receptionService.ErrReceptionAlreadyClose, productService.ErrReceptionAlreadyClose}, Error{Code: CodeReceptionAlreadyClosed, HTTPStatus: http.StatusBadRequest, GRPCCode: codes.FailedPrecondition}},
+	{[]error{receptionService.ErrVersionConflict, domainReception.ErrVersionConflict}, Error{Code: CodeReceptionVersionConflict, HTTPStatus: http.StatusConflict, GRPCCode: codes.Aborted}},
+	{[]error{domainReception.ErrNoOpenReception}, Error{Code: CodeNoOpenReception, HTTPStatus: http.StatusBadRequest, GRPCCode: codes.FailedPrecondition}},
+
+	{[]error{productService.ErrProductNotFound, domainProduct.ErrNotFound, domainProduct.ErrProductNotFound}, Error{Code: CodeProductNotFound, HTTPStatus: http.StatusNotFound, GRPCCode: codes.NotFound}},
+	{[]error{productService.ErrInvalidProductType}, Error{Code: CodeInvalidProductType, HTTPStatus: http.StatusBadRequest, GRPCCode: codes.InvalidArgument}},
+	{[]error{productService.ErrEmptyImport}, Error{Code: CodeImportEmpty, HTTPStatus: http.StatusBadRequest, GRPCCode: codes.InvalidArgument}},
+	{[]error{productService.ErrImportTooLarge}, Error{Code: CodeImportTooLarge, HTTPStatus: http.StatusRequestEntityTooLarge, GRPCCode: codes.InvalidArgument}},
+
+	{[]error{userService.ErrInvalidEmail}, Error{Code: CodeInvalidEmail, HTTPStatus: http.StatusBadRequest, GRPCCode: codes.InvalidArgument}},
+	{[]error{userService.ErrInvalidPassword}, Error{Code: CodeInvalidPassword, HTTPStatus: http.StatusBadRequest, GRPCCode: codes.InvalidArgument}},
+	{[]error{userService.ErrInvalidRole}, Error{Code: CodeInvalidRole, HTTPStatus: http.StatusBadRequest, GRPCCode: codes.InvalidArgument}},
+	{[]error{userService.ErrUserAlreadyExists, domainUser.ErrUserAlreadyExists}, Error{Code: CodeUserAlreadyExists, HTTPStatus: http.StatusConflict, GRPCCode: codes.AlreadyExists}},
+	{[]error{userService.ErrUserNotFound, domainUser.ErrNotFound, domainUser.ErrUserNotFound}, Error{Code: CodeUserNotFound, HTTPStatus: http.StatusNotFound, GRPCCode: codes.NotFound}},
+	{[]error{accountService.ErrInvalidToken}, Error{Code: CodeInvalidAccountToken, HTTPStatus: http.StatusBadRequest, GRPCCode: codes.InvalidArgument}},
+	{[]error{accountService.ErrEmailAlreadyVerified}, Error{Code: CodeEmailAlreadyVerified, HTTPStatus: http.StatusConflict, GRPCCode: codes.FailedPrecondition}},
+
+	{[]error{exportService.ErrInvalidFormat}, Error{Code: CodeInvalidExportFormat, HTTPStatus: http.StatusBadRequest, GRPCCode: codes.InvalidArgument}},
+	{[]error{exportService.ErrInvalidDateRange, listing.ErrInvalidDateRange, domainPVZ.ErrInvalidDateRange}, Error{Code: CodeInvalidDateRange, HTTPStatus: http.StatusBadRequest, GRPCCode: codes.InvalidArgument}},
+	{[]error{listing.ErrInvalidSort}, Error{Code: CodeInvalidSort, HTTPStatus: http.StatusBadRequest, GRPCCode: codes.InvalidArgument}},
+	{[]error{listing.ErrInvalidFilter}, Error{Code: CodeInvalidFilter, HTTPStatus: http.StatusBadRequest, GRPCCode: codes.InvalidArgument}},
+	{[]error{listing.ErrInvalidPage}, Error{Code: CodeInvalidPage, HTTPStatus: http.StatusBadRequest, GRPCCode: codes.InvalidArgument}},
+
+	{[]error{domainWebhook.ErrNotFound}, Error{Code: CodeWebhookNotFound, HTTPStatus: http.StatusNotFound, GRPCCode: codes.NotFound}},
+	{[]error{domainWebhook.ErrDeliveryNotFound}, Error{Code: CodeWebhookDeliveryNotFound, HTTPStatus: http.StatusNotFound, GRPCCode: codes.NotFound}},
+	{[]error{webhookService.ErrInvalidURL}, Error{Code: CodeInvalidWebhookURL, HTTPStatus: http.StatusBadRequest, GRPCCode: codes.InvalidArgument}},
+	{[]error{webhookService.ErrInvalidEventTypes}, Error{Code: CodeInvalidWebhookEvents, HTTPStatus: http.StatusBadRequest, GRPCCode: codes.InvalidArgument}},
+	{[]error{webhookService.ErrInvalidStatus}, Error{Code: CodeInvalidDeliveryStatus, HTTPStatus: http.StatusBadRequest, GRPCCode: codes.InvalidArgument}},
+	{[]error{webhookService.ErrDeliveryNotRetrying}, Error{Code: CodeWebhookDeliveryNotDead, HTTPStatus: http.StatusConflict, GRPCCode: codes.FailedPrecondition}},
+
+	{[]error{domainJob.ErrNotFound}, Error{Code: CodeJobNotFound, HTTPStatus: http.StatusNotFound, GRPCCode: codes.NotFound}},
+	{[]error{jobService.ErrUnknownType}, Error{Code: CodeUnknownJobType, HTTPStatus: http.StatusBadRequest, GRPCCode: codes.InvalidArgument}},
+	{[]error{jobService.ErrInvalidParams}, Error{Code: CodeInvalidJobParams, HTTPStatus: http.StatusBadRequest, GRPCCode: codes.InvalidArgument}},
+	{[]error{jobService.ErrForbidden}, Error{Code: CodeJobTypeForbidden, HTTPStatus: http.StatusForbidden, GRPCCode: codes.PermissionDenied}},
+	{[]error{jobService.ErrTooManyJobs}, Error{Code: CodeTooManyJobs, HTTPStatus: http.StatusTooManyRequests, GRPCCode: codes.ResourceExhausted}},
+	{[]error{domainJob.ErrNotCancellable}, Error{Code: CodeJobNotCancellable, HTTPStatus: http.StatusConflict, GRPCCode: codes.FailedPrecondition}},
+	{[]error{jobService.ErrResultNotReady}, Error{Code: CodeJobResultNotReady, HTTPStatus: http.StatusConflict, GRPCCode: codes.FailedPrecondition}},
+
+	{[]error{sessionService.ErrInvalidRefreshToken}, Error{Code: CodeInvalidRefreshToken, HTTPStatus: http.StatusUnauthorized, GRPCCode: codes.Unauthenticated}},
+	{[]error{sessionService.ErrRefreshTokenReused}, Error{Code: CodeRefreshTokenReused, HTTPStatus: http.StatusUnauthorized, GRPCCode: codes.Unauthenticated}},
+
+	{[]error{domainAssignment.ErrNotFound}, Error{Code: CodeAssignmentNotFound, HTTPStatus: http.StatusNotFound, GRPCCode: codes.NotFound}},
+	{[]error{domainAssignment.ErrNotAssigned}, Error{Code: CodePVZNotAssigned, HTTPStatus: http.StatusForbidden, GRPCCode: codes.PermissionDenied}},
+	{[]error{assignmentService.ErrInvalidPeriod}, Error{Code: CodeInvalidAssignmentPeriod, HTTPStatus: http.StatusBadRequest, GRPCCode: codes.InvalidArgument}},
+
+	{[]error{domainAPIKey.ErrAccountNotFound}, Error{Code: CodeServiceAccountNotFound, HTTPStatus: http.StatusNotFound, GRPCCode: codes.NotFound}},
+	{[]error{domainAPIKey.ErrKeyNotFound}, Error{Code: CodeAPIKeyNotFound, HTTPStatus: http.StatusNotFound, GRPCCode: codes.NotFound}},
+	{[]error{apikeyService.ErrInvalidName}, Error{Code: CodeInvalidServiceAccount, HTTPStatus: http.StatusBadRequest, GRPCCode: codes.InvalidArgument}},
+	{[]error{apikeyService.ErrInvalidScopes}, Error{Code: CodeInvalidAPIKeyScopes, HTTPStatus: http.StatusBadRequest, GRPCCode: codes.InvalidArgument}},
+	{[]error{apikeyService.ErrInvalidExpiry}, Error{Code: CodeInvalidAPIKeyExpiry, HTTPStatus: http.StatusBadRequest, GRPCCode: codes.InvalidArgument}},
+}
+
+// Lookup возвращает описание ошибки для клиента.
+// Второе значение false, если ошибка не входит в сопоставление.
+func Lookup(err error) (Error, bool) {
+	var e Error
+	if errors.As(err, &e) {
+		return e, true
+	}
+
+	for _, m := range mapping {
+		for _, target := range m.errs {
+			if errors.Is(err, target) {
+				return m.info, true
+			}
+		}
+	}
+	return Error{}, false
+}
+
+// Resolve возвращает описание ошибки, а для неизвестных ошибок —
+// внутреннюю ошибку с сообщением по ключу fallbackKey
+func Resolve(err error, fallbackKey string) Error {
+	if e, ok := Lookup(err); ok {
+		return e
+	}
+	return Internal(fallbackKey)
+}
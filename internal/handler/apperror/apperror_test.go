@@ -0,0 +1,169 @@
+package apperror
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	domainAssignment "github.com/avito/pvz/internal/domain/assignment"
+	domainReception "github.com/avito/pvz/internal/domain/reception"
+	"github.com/avito/pvz/internal/handler/i18n"
+	pvzService "github.com/avito/pvz/internal/service/pvz"
+	receptionService "github.com/avito/pvz/internal/service/reception"
+	"github.com/avito/pvz/pkg/httpresponse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		expectedCode   Code
+		expectedStatus int
+	}{
+		{
+			name:           "ошибка сервиса",
+			err:            receptionService.ErrReceptionAlreadyOpen,
+			expectedCode:   CodeReceptionAlreadyOpen,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "ошибка домена с тем же смыслом",
+			err:            domainReception.ErrReceptionAlreadyOpen,
+			expectedCode:   CodeReceptionAlreadyOpen,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "обернутая ошибка",
+			err:            fmt.Errorf("update pvz: %w", pvzService.ErrVersionConflict),
+			expectedCode:   CodePVZVersionConflict,
+			expectedStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name:           "готовое описание ошибки",
+			err:            InvalidRequest("invalid_body"),
+			expectedCode:   CodeInvalidRequest,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "сотрудник не закреплен за ПВЗ",
+			err:            fmt.Errorf("%w: %s", domainAssignment.ErrNotAssigned, "pvz"),
+			expectedCode:   CodePVZNotAssigned,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "неизвестная ошибка",
+			err:            errors.New("database error"),
+			expectedCode:   CodeInternal,
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := Resolve(tt.err, "pvz_create_failed")
+			assert.Equal(t, tt.expectedCode, e.Code)
+			assert.Equal(t, tt.expectedStatus, e.HTTPStatus)
+			assert.NotEqual(t, e.Key(), e.Message(i18n.Russian), "нет перевода для %s", e.Key())
+		})
+	}
+}
+
+func TestMappingCodesAreUnique(t *testing.T) {
+	seen := make(map[Code]bool)
+	for _, m := range mapping {
+		assert.False(t, seen[m.info.Code], "код %s описан дважды", m.info.Code)
+		seen[m.info.Code] = true
+	}
+}
+
+func TestMappingCodesAreTranslated(t *testing.T) {
+	for _, m := range mapping {
+		for _, lang := range []i18n.Lang{i18n.Russian, i18n.English} {
+			assert.NotEqual(t, m.info.Key(), m.info.Message(lang), "нет перевода для %s на %s", m.info.Code, lang)
+		}
+	}
+}
+
+func TestWriteHTTP(t *testing.T) {
+	tests := []struct {
+		name           string
+		lang           i18n.Lang
+		err            error
+		expectedStatus int
+		expectedCode   Code
+		expectedDetail string
+	}{
+		{
+			name:           "неизвестная ошибка на русском",
+			lang:           i18n.Russian,
+			err:            errors.New("database error"),
+			expectedStatus: http.StatusInternalServerError,
+			expectedCode:   CodeInternal,
+			expectedDetail: "ошибка при создании ПВЗ",
+		},
+		{
+			name:           "ошибка сервиса на английском",
+			lang:           i18n.English,
+			err:            pvzService.ErrPVZNotFound,
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   CodePVZNotFound,
+			expectedDetail: "PVZ not found",
+		},
+		{
+			name:           "ошибка валидации на английском",
+			lang:           i18n.English,
+			err:            InvalidRequest("city_required"),
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   CodeInvalidRequest,
+			expectedDetail: "city is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/pvz", nil)
+			req = req.WithContext(i18n.WithLang(req.Context(), tt.lang))
+
+			rec := httptest.NewRecorder()
+			WriteHTTP(rec, req, tt.err, "pvz_create_failed")
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.Equal(t, httpresponse.ContentTypeProblem, rec.Header().Get("Content-Type"))
+
+			var problem httpresponse.ProblemDetails
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&problem))
+			assert.Equal(t, string(tt.expectedCode), problem.Code)
+			assert.Equal(t, tt.expectedDetail, problem.Detail)
+		})
+	}
+}
+
+func TestGRPCError(t *testing.T) {
+	ctx := i18n.WithLang(context.Background(), i18n.Russian)
+	err := GRPCError(ctx, pvzService.ErrInvalidCursor, "pvz_list_failed")
+
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	assert.Equal(t, i18n.Message(i18n.Russian, string(CodeInvalidCursor)), st.Message())
+
+	require.Len(t, st.Details(), 2)
+	info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+	require.True(t, ok)
+	assert.Equal(t, string(CodeInvalidCursor), info.Reason)
+	assert.Equal(t, ErrorDomain, info.Domain)
+
+	localized, ok := st.Details()[1].(*errdetails.LocalizedMessage)
+	require.True(t, ok)
+	assert.Equal(t, "ru", localized.Locale)
+	assert.Equal(t, st.Message(), localized.Message)
+}
@@ -0,0 +1,39 @@
+package apperror
+
+import (
+	"context"
+
+	"github.com/avito/pvz/internal/handler/i18n"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorDomain домен ошибок в деталях gRPC-статуса
+const ErrorDomain = "pvz.avito"
+
+// Status возвращает gRPC-статус с сообщением на языке lang.
+// Код ошибки передается в деталях ErrorInfo, сообщение — еще и в LocalizedMessage.
+func (e Error) Status(lang i18n.Lang) *status.Status {
+	msg := e.Message(lang)
+	st := status.New(e.GRPCCode, msg)
+	detailed, err := st.WithDetails(
+		&errdetails.ErrorInfo{
+			Reason: string(e.Code),
+			Domain: ErrorDomain,
+		},
+		&errdetails.LocalizedMessage{
+			Locale:  string(lang),
+			Message: msg,
+		},
+	)
+	if err != nil {
+		return st
+	}
+	return detailed
+}
+
+// GRPCError преобразует ошибку в gRPC-ошибку на языке из контекста.
+// Для неизвестных ошибок возвращается codes.Internal с сообщением по ключу fallbackKey.
+func GRPCError(ctx context.Context, err error, fallbackKey string) error {
+	return Resolve(err, fallbackKey).Status(i18n.LangFromContext(ctx)).Err()
+}
@@ -0,0 +1,20 @@
+package apperror
+
+import (
+	"net/http"
+
+	"github.com/avito/pvz/internal/handler/i18n"
+	"github.com/avito/pvz/pkg/httpresponse"
+)
+
+// WriteHTTP отправляет ошибку в формате problem+json на языке запроса.
+// Для неизвестных ошибок отправляется 500 с сообщением по ключу fallbackKey.
+func WriteHTTP(w http.ResponseWriter, r *http.Request, err error, fallbackKey string) {
+	e := Resolve(err, fallbackKey)
+	httpresponse.Problem(w, e.HTTPStatus, string(e.Code), e.Message(i18n.LangFromContext(r.Context())))
+}
+
+// WriteInvalidRequest отправляет 400 с сообщением по ключу key
+func WriteInvalidRequest(w http.ResponseWriter, r *http.Request, key string) {
+	WriteHTTP(w, r, InvalidRequest(key), "")
+}
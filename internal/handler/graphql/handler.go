@@ -0,0 +1,131 @@
+// Package graphql содержит GraphQL API только для чтения над графом
+// ПВЗ → приемки → товары. Вложенные поля загружаются пачками через
+// загрузчики над репозиториями, поэтому запрос делает одно обращение
+// к базе на каждый уровень вложенности, а не на каждый объект.
+package graphql
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/avito/pvz/internal/handler/apperror"
+	"github.com/avito/pvz/pkg/httpresponse"
+	gql "github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/parser"
+)
+
+// maxBodySize максимальный размер тела POST-запроса
+const maxBodySize = 64 << 10
+
+// Request тело GraphQL-запроса
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Handler обрабатывает GraphQL-запросы
+type Handler struct {
+	schema        gql.Schema
+	pvzRepo       PVZRepository
+	receptionRepo ReceptionRepository
+	productRepo   ProductRepository
+	limits        Limits
+}
+
+// NewHandler создает новый экземпляр Handler
+func NewHandler(schema gql.Schema, pvzRepo PVZRepository, receptionRepo ReceptionRepository, productRepo ProductRepository, limits Limits) *Handler {
+	return &Handler{
+		schema:        schema,
+		pvzRepo:       pvzRepo,
+		receptionRepo: receptionRepo,
+		productRepo:   productRepo,
+		limits:        limits,
+	}
+}
+
+// ServeHTTP выполняет запрос из параметров GET или JSON-тела POST.
+// Ошибки разбора, проверки и выполнения возвращаются в поле errors ответа
+// с кодом в extensions.code, как принято в GraphQL.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req Request
+	switch r.Method {
+	case http.MethodGet:
+		req.Query = r.URL.Query().Get("query")
+		req.OperationName = r.URL.Query().Get("operationName")
+		if vars := r.URL.Query().Get("variables"); vars != "" {
+			if err := json.Unmarshal([]byte(vars), &req.Variables); err != nil {
+				apperror.WriteInvalidRequest(w, r, "invalid_body")
+				return
+			}
+		}
+	case http.MethodPost:
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize)).Decode(&req); err != nil {
+			apperror.WriteInvalidRequest(w, r, "invalid_body")
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	if req.Query == "" {
+		apperror.WriteInvalidRequest(w, r, "query_required")
+		return
+	}
+
+	httpresponse.JSON(w, http.StatusOK, h.execute(r, req))
+}
+
+// execute разбирает, проверяет и выполняет запрос
+func (h *Handler) execute(r *http.Request, req Request) *gql.Result {
+	doc, err := parser.Parse(parser.ParseParams{Source: req.Query})
+	if err != nil {
+		return &gql.Result{Errors: gqlerrors.FormatErrors(err)}
+	}
+
+	if result := gql.ValidateDocument(&h.schema, doc, nil); !result.IsValid {
+		return &gql.Result{Errors: result.Errors}
+	}
+
+	if err := h.limits.Check(&h.schema, doc, req.OperationName, req.Variables); err != nil {
+		located := gqlerrors.NewLocatedError(newFieldError(r.Context(), err, ""), nil)
+		return &gql.Result{Errors: gqlerrors.FormatErrors(located)}
+	}
+
+	ctx := withLoaders(r.Context(), newLoaders(h.pvzRepo, h.receptionRepo, h.productRepo))
+	result := gql.Execute(gql.ExecuteParams{
+		Schema:        h.schema,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       ctx,
+	})
+	for i := range result.Errors {
+		if result.Errors[i].Extensions == nil {
+			result.Errors[i].Extensions = errorExtensions(result.Errors[i].OriginalError())
+		}
+	}
+	return result
+}
+
+// errorExtensions возвращает extensions исходной ошибки поля.
+// Ошибки отложенных резолверов библиотека заворачивает без extensions,
+// поэтому код восстанавливается по цепочке исходных ошибок.
+func errorExtensions(err error) map[string]interface{} {
+	for err != nil {
+		switch e := err.(type) {
+		case fieldError:
+			return e.Extensions()
+		case gqlerrors.FormattedError:
+			err = e.OriginalError()
+		case *gqlerrors.Error:
+			err = e.OriginalError
+		default:
+			return nil
+		}
+	}
+	return nil
+}
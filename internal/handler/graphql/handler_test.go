@@ -0,0 +1,444 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/listing"
+	"github.com/avito/pvz/internal/domain/product"
+	domainPVZ "github.com/avito/pvz/internal/domain/pvz"
+	"github.com/avito/pvz/internal/domain/reception"
+	"github.com/avito/pvz/internal/handler/apperror"
+	"github.com/avito/pvz/internal/handler/i18n"
+	"github.com/avito/pvz/pkg/httpresponse"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockPVZService struct {
+	mock.Mock
+}
+
+func (m *mockPVZService) GetByID(ctx context.Context, id uuid.UUID) (*domainPVZ.PVZ, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domainPVZ.PVZ), args.Error(1)
+}
+
+func (m *mockPVZService) ListByFilter(ctx context.Context, filter domainPVZ.ListFilter) ([]*domainPVZ.PVZ, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domainPVZ.PVZ), args.Error(1)
+}
+
+type mockReceptionService struct {
+	mock.Mock
+}
+
+func (m *mockReceptionService) ListByFilter(ctx context.Context, filter reception.ListFilter) ([]*reception.Reception, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*reception.Reception), args.Error(1)
+}
+
+type mockProductService struct {
+	mock.Mock
+}
+
+func (m *mockProductService) ListByFilter(ctx context.Context, filter product.ListFilter) ([]*product.Product, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*product.Product), args.Error(1)
+}
+
+type mockPVZRepository struct {
+	mock.Mock
+}
+
+func (m *mockPVZRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*domainPVZ.PVZ, error) {
+	args := m.Called(ctx, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domainPVZ.PVZ), args.Error(1)
+}
+
+type mockReceptionRepository struct {
+	mock.Mock
+}
+
+func (m *mockReceptionRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*reception.Reception, error) {
+	args := m.Called(ctx, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*reception.Reception), args.Error(1)
+}
+
+func (m *mockReceptionRepository) ListByPVZIDs(ctx context.Context, pvzIDs []uuid.UUID, limit int) ([]*reception.Reception, error) {
+	args := m.Called(ctx, pvzIDs, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*reception.Reception), args.Error(1)
+}
+
+type mockProductRepository struct {
+	mock.Mock
+}
+
+func (m *mockProductRepository) ListByReceptionIDs(ctx context.Context, receptionIDs []uuid.UUID, limit int) ([]*product.Product, error) {
+	args := m.Called(ctx, receptionIDs, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*product.Product), args.Error(1)
+}
+
+// testDeps набор моков сервисов и репозиториев для одного теста
+type testDeps struct {
+	pvzService       *mockPVZService
+	receptionService *mockReceptionService
+	productService   *mockProductService
+	pvzRepo          *mockPVZRepository
+	receptionRepo    *mockReceptionRepository
+	productRepo      *mockProductRepository
+}
+
+func newTestDeps() *testDeps {
+	return &testDeps{
+		pvzService:       new(mockPVZService),
+		receptionService: new(mockReceptionService),
+		productService:   new(mockProductService),
+		pvzRepo:          new(mockPVZRepository),
+		receptionRepo:    new(mockReceptionRepository),
+		productRepo:      new(mockProductRepository),
+	}
+}
+
+func (d *testDeps) handler(t *testing.T, limits Limits) *Handler {
+	schema, err := NewSchema(d.pvzService, d.receptionService, d.productService)
+	require.NoError(t, err)
+	return NewHandler(schema, d.pvzRepo, d.receptionRepo, d.productRepo, limits)
+}
+
+func (d *testDeps) assertExpectations(t *testing.T) {
+	d.pvzService.AssertExpectations(t)
+	d.receptionService.AssertExpectations(t)
+	d.productService.AssertExpectations(t)
+	d.pvzRepo.AssertExpectations(t)
+	d.receptionRepo.AssertExpectations(t)
+	d.productRepo.AssertExpectations(t)
+}
+
+// response ответ GraphQL с сырыми данными
+type response struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string                 `json:"message"`
+		Extensions map[string]interface{} `json:"extensions"`
+	} `json:"errors"`
+}
+
+// post выполняет POST-запрос с телом body на языке lang
+func post(h *Handler, body string, lang i18n.Lang) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(body))
+	req = req.WithContext(i18n.WithLang(req.Context(), lang))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func decodeResponse(t *testing.T, rec *httptest.ResponseRecorder) response {
+	var resp response
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	return resp
+}
+
+func TestHandler_NestedQueryBatchesLoads(t *testing.T) {
+	d := newTestDeps()
+	date := time.Date(2024, time.March, 1, 10, 0, 0, 0, time.UTC)
+
+	pvz1 := &domainPVZ.PVZ{ID: uuid.New(), CreatedAt: date, City: "Москва"}
+	pvz2 := &domainPVZ.PVZ{ID: uuid.New(), CreatedAt: date, City: "Казань"}
+	rec1 := &reception.Reception{ID: uuid.New(), DateTime: date, PVZID: pvz1.ID, Status: reception.StatusClose}
+	rec2 := &reception.Reception{ID: uuid.New(), DateTime: date, PVZID: pvz1.ID, Status: reception.StatusInProgress}
+	rec3 := &reception.Reception{ID: uuid.New(), DateTime: date, PVZID: pvz2.ID, Status: reception.StatusInProgress}
+	prod1 := &product.Product{ID: uuid.New(), DateTime: date, Type: product.TypeElectronics, ReceptionID: rec1.ID}
+	prod2 := &product.Product{ID: uuid.New(), DateTime: date, Type: product.TypeFood, ReceptionID: rec3.ID}
+
+	d.pvzService.On("ListByFilter", mock.Anything, domainPVZ.ListFilter{
+		City: "Москва",
+		Page: listing.Page{Offset: 0, Limit: 2},
+	}).Return([]*domainPVZ.PVZ{pvz1, pvz2}, nil).Once()
+	d.receptionRepo.On("ListByPVZIDs", mock.Anything, []uuid.UUID{pvz1.ID, pvz2.ID}, listing.DefaultLimit).
+		Return([]*reception.Reception{rec1, rec2, rec3}, nil).Once()
+	d.productRepo.On("ListByReceptionIDs", mock.Anything, []uuid.UUID{rec1.ID, rec2.ID, rec3.ID}, 5).
+		Return([]*product.Product{prod1, prod2}, nil).Once()
+
+	rec := post(d.handler(t, DefaultLimits),
+		`{"query":"{ pvzs(city: \"Москва\", limit: 2) { id city receptions { id status products(limit: 5) { id type typeName } } } }"}`,
+		i18n.Russian)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	resp := decodeResponse(t, rec)
+	require.Empty(t, resp.Errors)
+
+	var data struct {
+		PVZs []struct {
+			ID         string `json:"id"`
+			City       string `json:"city"`
+			Receptions []struct {
+				ID       string `json:"id"`
+				Status   string `json:"status"`
+				Products []struct {
+					ID       string `json:"id"`
+					Type     string `json:"type"`
+					TypeName string `json:"typeName"`
+				} `json:"products"`
+			} `json:"receptions"`
+		} `json:"pvzs"`
+	}
+	require.NoError(t, json.Unmarshal(resp.Data, &data))
+
+	require.Len(t, data.PVZs, 2)
+	assert.Equal(t, pvz1.ID.String(), data.PVZs[0].ID)
+	require.Len(t, data.PVZs[0].Receptions, 2)
+	assert.Equal(t, "CLOSE", data.PVZs[0].Receptions[0].Status)
+	require.Len(t, data.PVZs[0].Receptions[0].Products, 1)
+	assert.Equal(t, "ELECTRONICS", data.PVZs[0].Receptions[0].Products[0].Type)
+	assert.Equal(t, i18n.ProductTypeName(i18n.Russian, product.TypeElectronics), data.PVZs[0].Receptions[0].Products[0].TypeName)
+	assert.Empty(t, data.PVZs[0].Receptions[1].Products)
+	require.Len(t, data.PVZs[1].Receptions, 1)
+	assert.Equal(t, prod2.ID.String(), data.PVZs[1].Receptions[0].Products[0].ID)
+
+	d.assertExpectations(t)
+}
+
+func TestHandler_NestedListLimitPerAlias(t *testing.T) {
+	d := newTestDeps()
+	date := time.Date(2024, time.March, 1, 10, 0, 0, 0, time.UTC)
+
+	pvz1 := &domainPVZ.PVZ{ID: uuid.New(), CreatedAt: date, City: "Москва"}
+	rec1 := &reception.Reception{ID: uuid.New(), DateTime: date.Add(time.Hour), PVZID: pvz1.ID, Status: reception.StatusInProgress}
+	rec2 := &reception.Reception{ID: uuid.New(), DateTime: date, PVZID: pvz1.ID, Status: reception.StatusClose}
+
+	// Каждый размер списка загружается отдельным запросом с ограничением на ПВЗ
+	d.pvzService.On("GetByID", mock.Anything, pvz1.ID).Return(pvz1, nil).Once()
+	d.receptionRepo.On("ListByPVZIDs", mock.Anything, []uuid.UUID{pvz1.ID}, 1).
+		Return([]*reception.Reception{rec1}, nil).Once()
+	d.receptionRepo.On("ListByPVZIDs", mock.Anything, []uuid.UUID{pvz1.ID}, listing.DefaultLimit).
+		Return([]*reception.Reception{rec1, rec2}, nil).Once()
+
+	rec := post(d.handler(t, DefaultLimits),
+		`{"query":"{ pvz(id: \"`+pvz1.ID.String()+`\") { latest: receptions(limit: 1) { id } all: receptions { id } } }"}`,
+		i18n.Russian)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	resp := decodeResponse(t, rec)
+	require.Empty(t, resp.Errors)
+
+	var data struct {
+		PVZ struct {
+			Latest []struct{ ID string } `json:"latest"`
+			All    []struct{ ID string } `json:"all"`
+		} `json:"pvz"`
+	}
+	require.NoError(t, json.Unmarshal(resp.Data, &data))
+	require.Len(t, data.PVZ.Latest, 1)
+	assert.Equal(t, rec1.ID.String(), data.PVZ.Latest[0].ID)
+	assert.Len(t, data.PVZ.All, 2)
+
+	d.assertExpectations(t)
+}
+
+func TestHandler_ProductsWithParents(t *testing.T) {
+	d := newTestDeps()
+	date := time.Date(2024, time.March, 1, 10, 0, 0, 0, time.UTC)
+
+	pvz1 := &domainPVZ.PVZ{ID: uuid.New(), CreatedAt: date, City: "Москва"}
+	rec1 := &reception.Reception{ID: uuid.New(), DateTime: date, PVZID: pvz1.ID, Status: reception.StatusInProgress}
+	prod1 := &product.Product{ID: uuid.New(), DateTime: date, Type: product.TypeClothing, ReceptionID: rec1.ID}
+	prod2 := &product.Product{ID: uuid.New(), DateTime: date, Type: product.TypeFood, ReceptionID: rec1.ID}
+
+	d.productService.On("ListByFilter", mock.Anything, product.ListFilter{
+		Type: product.TypeClothing,
+		Sort: listing.Sort{Field: product.SortByDateTime, Direction: listing.DirectionDesc},
+		Page: listing.Page{Offset: 0, Limit: listing.DefaultLimit},
+	}).Return([]*product.Product{prod1, prod2}, nil).Once()
+	d.receptionRepo.On("GetByIDs", mock.Anything, []uuid.UUID{rec1.ID}).
+		Return([]*reception.Reception{rec1}, nil).Once()
+	d.pvzRepo.On("GetByIDs", mock.Anything, []uuid.UUID{pvz1.ID}).
+		Return([]*domainPVZ.PVZ{pvz1}, nil).Once()
+
+	rec := post(d.handler(t, DefaultLimits),
+		`{"query":"query P($t: ProductType) { products(type: $t, sort: \"-date_time\") { id reception { id pvz { city } } } }","variables":{"t":"CLOTHING"}}`,
+		i18n.Russian)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	resp := decodeResponse(t, rec)
+	require.Empty(t, resp.Errors)
+	assert.Contains(t, string(resp.Data), `"city":"Москва"`)
+
+	d.assertExpectations(t)
+}
+
+func TestHandler_Errors(t *testing.T) {
+	pvzID := uuid.New()
+
+	tests := []struct {
+		name           string
+		body           string
+		lang           i18n.Lang
+		limits         Limits
+		setupMocks     func(*testDeps)
+		expectedStatus int
+		expectedCode   string
+		expectedMsg    string
+	}{
+		{
+			name:           "неверное тело запроса",
+			body:           `{`,
+			lang:           i18n.Russian,
+			setupMocks:     func(d *testDeps) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   string(apperror.CodeInvalidRequest),
+		},
+		{
+			name:           "пустой запрос",
+			body:           `{"query":""}`,
+			lang:           i18n.English,
+			setupMocks:     func(d *testDeps) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   string(apperror.CodeInvalidRequest),
+			expectedMsg:    i18n.Message(i18n.English, "query_required"),
+		},
+		{
+			name:           "слишком глубокий запрос",
+			body:           `{"query":"{ pvzs { receptions { products { id } } } }"}`,
+			lang:           i18n.English,
+			limits:         Limits{MaxDepth: 2},
+			setupMocks:     func(d *testDeps) {},
+			expectedStatus: http.StatusOK,
+			expectedCode:   string(apperror.CodeQueryTooDeep),
+			expectedMsg:    i18n.Message(i18n.English, "query_too_deep"),
+		},
+		{
+			name:           "слишком сложный запрос",
+			body:           `{"query":"{ pvzs(limit: 100) { receptions { id } } }"}`,
+			lang:           i18n.Russian,
+			limits:         Limits{MaxComplexity: 100},
+			setupMocks:     func(d *testDeps) {},
+			expectedStatus: http.StatusOK,
+			expectedCode:   string(apperror.CodeQueryTooComplex),
+			expectedMsg:    i18n.Message(i18n.Russian, "query_too_complex"),
+		},
+		{
+			name: "ПВЗ не найден",
+			body: `{"query":"{ pvz(id: \"` + pvzID.String() + `\") { id } }"}`,
+			lang: i18n.Russian,
+			setupMocks: func(d *testDeps) {
+				d.pvzService.On("GetByID", mock.Anything, pvzID).Return(nil, domainPVZ.ErrNotFound)
+			},
+			expectedStatus: http.StatusOK,
+			expectedCode:   string(apperror.CodePVZNotFound),
+		},
+		{
+			name:           "неверная сортировка",
+			body:           `{"query":"{ receptions(sort: \"-\") { id } }"}`,
+			lang:           i18n.Russian,
+			setupMocks:     func(d *testDeps) {},
+			expectedStatus: http.StatusOK,
+			expectedCode:   string(apperror.CodeInvalidSort),
+		},
+		{
+			name: "слишком большой размер вложенного списка",
+			body: `{"query":"{ pvz(id: \"` + pvzID.String() + `\") { receptions(limit: 1000) { id } } }"}`,
+			lang: i18n.Russian,
+			setupMocks: func(d *testDeps) {
+				d.pvzService.On("GetByID", mock.Anything, pvzID).Return(&domainPVZ.PVZ{ID: pvzID}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedCode:   string(apperror.CodeInvalidPage),
+		},
+		{
+			name: "ошибка загрузки вложенных приемок",
+			body: `{"query":"{ pvz(id: \"` + pvzID.String() + `\") { receptions { id } } }"}`,
+			lang: i18n.Russian,
+			setupMocks: func(d *testDeps) {
+				d.pvzService.On("GetByID", mock.Anything, pvzID).Return(&domainPVZ.PVZ{ID: pvzID}, nil)
+				d.receptionRepo.On("ListByPVZIDs", mock.Anything, []uuid.UUID{pvzID}, listing.DefaultLimit).Return(nil, errors.New("db error"))
+			},
+			expectedStatus: http.StatusOK,
+			expectedCode:   string(apperror.CodeInternal),
+			expectedMsg:    i18n.Message(i18n.Russian, "reception_list_failed"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestDeps()
+			tt.setupMocks(d)
+
+			rec := post(d.handler(t, tt.limits), tt.body, tt.lang)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			code, msg := "", ""
+			if tt.expectedStatus != http.StatusOK {
+				var problem httpresponse.ProblemDetails
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&problem))
+				code, msg = problem.Code, problem.Detail
+			} else {
+				resp := decodeResponse(t, rec)
+				require.Len(t, resp.Errors, 1)
+				code, _ = resp.Errors[0].Extensions["code"].(string)
+				msg = resp.Errors[0].Message
+			}
+			assert.Equal(t, tt.expectedCode, code)
+			if tt.expectedMsg != "" {
+				assert.Equal(t, tt.expectedMsg, msg)
+			}
+
+			d.assertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_Get(t *testing.T) {
+	d := newTestDeps()
+	pvzID := uuid.New()
+	d.pvzService.On("GetByID", mock.Anything, pvzID).
+		Return(&domainPVZ.PVZ{ID: pvzID, City: "Казань"}, nil).Once()
+
+	query := url.Values{
+		"query":     {`query P($id: ID!) { pvz(id: $id) { city } }`},
+		"variables": {`{"id":"` + pvzID.String() + `"}`},
+	}
+	req := httptest.NewRequest(http.MethodGet, "/graphql?"+query.Encode(), nil)
+	rec := httptest.NewRecorder()
+	d.handler(t, DefaultLimits).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	resp := decodeResponse(t, rec)
+	require.Empty(t, resp.Errors)
+	assert.JSONEq(t, `{"pvz":{"city":"Казань"}}`, string(resp.Data))
+
+	d.assertExpectations(t)
+}
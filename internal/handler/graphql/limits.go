@@ -0,0 +1,163 @@
+package graphql
+
+import (
+	"strconv"
+
+	"github.com/avito/pvz/internal/domain/listing"
+	"github.com/avito/pvz/internal/handler/apperror"
+	gql "github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// Limits ограничения на форму GraphQL-запроса
+type Limits struct {
+	// MaxDepth максимальная вложенность полей, корневые поля имеют глубину 1
+	MaxDepth int
+	// MaxComplexity максимальная оценка числа значений в ответе
+	MaxComplexity int
+}
+
+// DefaultLimits ограничения по умолчанию: ПВЗ → приемки → товары → приемка → ПВЗ
+// укладываются в глубину, а полная выгрузка страниц по умолчанию — в сложность
+var DefaultLimits = Limits{
+	MaxDepth:      6,
+	MaxComplexity: 5000,
+}
+
+// Check проверяет глубину и сложность операции.
+// Поле-список умножает стоимость вложенных полей на свой аргумент limit
+// или на listing.DefaultLimit, если аргумент не задан. Вложенные списки
+// PVZ.receptions и Reception.products ограничивают этим же limit каждого
+// родителя, поэтому оценка не меньше числа значений в ответе.
+func (l Limits) Check(schema *gql.Schema, doc *ast.Document, operationName string, variables map[string]interface{}) error {
+	op, fragments := splitDocument(doc, operationName)
+	if op == nil {
+		return nil
+	}
+
+	a := analyzer{fragments: fragments, variables: variables}
+	depth, complexity := a.selectionSet(schema.QueryType(), op.SelectionSet, nil)
+	if l.MaxDepth > 0 && depth > l.MaxDepth {
+		return apperror.ErrQueryTooDeep
+	}
+	if l.MaxComplexity > 0 && complexity > l.MaxComplexity {
+		return apperror.ErrQueryTooComplex
+	}
+	return nil
+}
+
+// splitDocument находит выполняемую операцию и фрагменты документа
+func splitDocument(doc *ast.Document, operationName string) (*ast.OperationDefinition, map[string]*ast.FragmentDefinition) {
+	var op *ast.OperationDefinition
+	fragments := make(map[string]*ast.FragmentDefinition)
+	for _, def := range doc.Definitions {
+		switch def := def.(type) {
+		case *ast.OperationDefinition:
+			if operationName == "" && op == nil || def.Name != nil && def.Name.Value == operationName {
+				op = def
+			}
+		case *ast.FragmentDefinition:
+			fragments[def.Name.Value] = def
+		}
+	}
+	return op, fragments
+}
+
+// analyzer обходит выборку полей и считает глубину и сложность
+type analyzer struct {
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]interface{}
+}
+
+// selectionSet возвращает глубину и сложность выборки в типе parent.
+// visited защищает от циклических ссылок между фрагментами.
+func (a analyzer) selectionSet(parent *gql.Object, set *ast.SelectionSet, visited map[string]bool) (int, int) {
+	if set == nil || parent == nil {
+		return 0, 0
+	}
+
+	depth, complexity := 0, 0
+	for _, selection := range set.Selections {
+		var d, c int
+		switch s := selection.(type) {
+		case *ast.Field:
+			d, c = a.field(parent, s, visited)
+		case *ast.InlineFragment:
+			d, c = a.selectionSet(parent, s.SelectionSet, visited)
+		case *ast.FragmentSpread:
+			name := s.Name.Value
+			fragment, ok := a.fragments[name]
+			if !ok || visited[name] {
+				continue
+			}
+			next := make(map[string]bool, len(visited)+1)
+			for k := range visited {
+				next[k] = true
+			}
+			next[name] = true
+			d, c = a.selectionSet(parent, fragment.SelectionSet, next)
+		}
+		depth = max(depth, d)
+		complexity += c
+	}
+	return depth, complexity
+}
+
+// field возвращает глубину и сложность поля вместе с вложенной выборкой
+func (a analyzer) field(parent *gql.Object, f *ast.Field, visited map[string]bool) (int, int) {
+	def, ok := parent.Fields()[f.Name.Value]
+	if !ok {
+		return 1, 1
+	}
+
+	fieldType, isList := unwrap(def.Type)
+	object, _ := fieldType.(*gql.Object)
+	depth, complexity := a.selectionSet(object, f.SelectionSet, visited)
+	if isList {
+		complexity *= a.limit(f)
+	}
+	return depth + 1, complexity + 1
+}
+
+// limit возвращает размер страницы поля-списка
+func (a analyzer) limit(f *ast.Field) int {
+	for _, arg := range f.Arguments {
+		if arg.Name.Value != "limit" {
+			continue
+		}
+		switch v := arg.Value.(type) {
+		case *ast.IntValue:
+			if n, err := strconv.Atoi(v.Value); err == nil && n > 0 {
+				return n
+			}
+		case *ast.Variable:
+			switch n := a.variables[v.Name.Value].(type) {
+			case int:
+				if n > 0 {
+					return n
+				}
+			case float64:
+				if n > 0 {
+					return int(n)
+				}
+			}
+		}
+	}
+	return listing.DefaultLimit
+}
+
+// unwrap снимает обертки NonNull и List и сообщает, был ли тип списком
+func unwrap(t gql.Output) (gql.Output, bool) {
+	isList := false
+	for {
+		switch w := t.(type) {
+		case *gql.NonNull:
+			t = w.OfType
+		case *gql.List:
+			isList = true
+			t = w.OfType
+		default:
+			return t, isList
+		}
+	}
+}
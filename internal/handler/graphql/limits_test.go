@@ -0,0 +1,99 @@
+package graphql
+
+import (
+	"testing"
+
+	"github.com/avito/pvz/internal/handler/apperror"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimits_Check(t *testing.T) {
+	schema, err := NewSchema(new(mockPVZService), new(mockReceptionService), new(mockProductService))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		limits        Limits
+		query         string
+		operationName string
+		variables     map[string]interface{}
+		expectedErr   error
+	}{
+		{
+			name:   "запрос в пределах ограничений",
+			limits: DefaultLimits,
+			query:  `{ pvzs { id receptions { id products { id } } } }`,
+		},
+		{
+			name:        "слишком глубокий запрос",
+			limits:      Limits{MaxDepth: 3},
+			query:       `{ pvzs { receptions { products { reception { id } } } } }`,
+			expectedErr: apperror.ErrQueryTooDeep,
+		},
+		{
+			name:        "глубина через фрагмент",
+			limits:      Limits{MaxDepth: 3},
+			query:       `{ pvzs { ...R } } fragment R on PVZ { receptions { products { id } } }`,
+			expectedErr: apperror.ErrQueryTooDeep,
+		},
+		{
+			// pvzs(limit: 100) × receptions(10) × products(10) × 2 поля
+			name:        "слишком сложный запрос",
+			limits:      Limits{MaxComplexity: 10000},
+			query:       `{ pvzs(limit: 100) { receptions { products { id type } } } }`,
+			expectedErr: apperror.ErrQueryTooComplex,
+		},
+		{
+			// pvzs(limit: 100) × receptions(limit: 100) × 1 поле
+			name:        "размер вложенного списка учитывается в сложности",
+			limits:      DefaultLimits,
+			query:       `{ pvzs(limit: 100) { receptions(limit: 100) { id } } }`,
+			expectedErr: apperror.ErrQueryTooComplex,
+		},
+		{
+			name:   "небольшой вложенный список укладывается в сложность",
+			limits: DefaultLimits,
+			query:  `{ pvzs(limit: 100) { receptions(limit: 5) { products(limit: 5) { id } } } }`,
+		},
+		{
+			name:      "размер страницы из переменной",
+			limits:    Limits{MaxComplexity: 50},
+			query:     `query Q($n: Int) { products(limit: $n) { id type } }`,
+			variables: map[string]interface{}{"n": float64(20)},
+		},
+		{
+			name:        "размер страницы из переменной превышает сложность",
+			limits:      Limits{MaxComplexity: 50},
+			query:       `query Q($n: Int) { products(limit: $n) { id type } }`,
+			variables:   map[string]interface{}{"n": float64(30)},
+			expectedErr: apperror.ErrQueryTooComplex,
+		},
+		{
+			name:          "проверяется только выбранная операция",
+			limits:        Limits{MaxDepth: 2},
+			query:         `query A { pvzs { id } } query B { pvzs { receptions { products { id } } } }`,
+			operationName: "A",
+		},
+		{
+			name:   "циклические фрагменты не зацикливают обход",
+			limits: DefaultLimits,
+			query:  `{ pvzs { ...P } } fragment P on PVZ { id ...P }`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := parser.Parse(parser.ParseParams{Source: tt.query})
+			require.NoError(t, err)
+
+			err = tt.limits.Check(&schema, doc, tt.operationName, tt.variables)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
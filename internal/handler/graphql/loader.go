@@ -0,0 +1,204 @@
+package graphql
+
+import (
+	"context"
+	"sync"
+
+	"github.com/avito/pvz/internal/domain/product"
+	domainPVZ "github.com/avito/pvz/internal/domain/pvz"
+	"github.com/avito/pvz/internal/domain/reception"
+	"github.com/google/uuid"
+)
+
+// PVZRepository загружает ПВЗ пачкой по списку ID
+type PVZRepository interface {
+	GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*domainPVZ.PVZ, error)
+}
+
+// ReceptionRepository загружает приемки пачкой по ID приемок или ПВЗ.
+// ListByPVZIDs возвращает не больше limit приемок каждого ПВЗ.
+type ReceptionRepository interface {
+	GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*reception.Reception, error)
+	ListByPVZIDs(ctx context.Context, pvzIDs []uuid.UUID, limit int) ([]*reception.Reception, error)
+}
+
+// ProductRepository загружает товары пачкой по ID приемок, не больше limit товаров каждой
+type ProductRepository interface {
+	ListByReceptionIDs(ctx context.Context, receptionIDs []uuid.UUID, limit int) ([]*product.Product, error)
+}
+
+// Loader собирает ключи, которые запросили резолверы одного уровня запроса,
+// и загружает их одним обращением к репозиторию. Результаты кешируются
+// на время запроса, поэтому повторный ключ не приводит к новой загрузке.
+type Loader[K comparable, V any] struct {
+	fetch func(ctx context.Context, keys []K) (map[K]V, error)
+
+	mu      sync.Mutex
+	pending []K
+	loaded  map[K]V
+	errs    map[K]error
+}
+
+// NewLoader создает загрузчик с функцией пакетной загрузки fetch.
+// Ключи, которых нет в результате fetch, получают нулевое значение.
+func NewLoader[K comparable, V any](fetch func(ctx context.Context, keys []K) (map[K]V, error)) *Loader[K, V] {
+	return &Loader[K, V]{
+		fetch:  fetch,
+		loaded: make(map[K]V),
+		errs:   make(map[K]error),
+	}
+}
+
+// Load откладывает загрузку ключа и возвращает функцию, которая вернет значение.
+// Первый вызов такой функции загружает все отложенные к этому моменту ключи.
+func (l *Loader[K, V]) Load(ctx context.Context, key K) func() (V, error) {
+	l.mu.Lock()
+	if !l.known(key) && !l.isPending(key) {
+		l.pending = append(l.pending, key)
+	}
+	l.mu.Unlock()
+
+	return func() (V, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		if !l.known(key) {
+			l.flush(ctx, key)
+		}
+		return l.loaded[key], l.errs[key]
+	}
+}
+
+// known сообщает, загружен ли ключ
+func (l *Loader[K, V]) known(key K) bool {
+	if _, ok := l.loaded[key]; ok {
+		return true
+	}
+	_, ok := l.errs[key]
+	return ok
+}
+
+// isPending сообщает, ожидает ли ключ загрузки
+func (l *Loader[K, V]) isPending(key K) bool {
+	for _, k := range l.pending {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// flush загружает все отложенные ключи, включая key
+func (l *Loader[K, V]) flush(ctx context.Context, key K) {
+	keys := l.pending
+	l.pending = nil
+	if len(keys) == 0 {
+		keys = []K{key}
+	}
+
+	values, err := l.fetch(ctx, keys)
+	for _, k := range keys {
+		if err != nil {
+			l.errs[k] = err
+			continue
+		}
+		l.loaded[k] = values[k]
+	}
+}
+
+// childrenKey ключ загрузки вложенного списка: родитель и размер списка.
+// Одно поле может запрашиваться с разными limit под разными псевдонимами.
+type childrenKey struct {
+	parentID uuid.UUID
+	limit    int
+}
+
+// groupByLimit группирует ID родителей по размеру списка, сохраняя порядок
+func groupByLimit(keys []childrenKey) ([]int, map[int][]uuid.UUID) {
+	var limits []int
+	ids := make(map[int][]uuid.UUID)
+	for _, k := range keys {
+		if _, ok := ids[k.limit]; !ok {
+			limits = append(limits, k.limit)
+		}
+		ids[k.limit] = append(ids[k.limit], k.parentID)
+	}
+	return limits, ids
+}
+
+// loaders загрузчики связанных сущностей одного GraphQL-запроса
+type loaders struct {
+	pvz               *Loader[uuid.UUID, *domainPVZ.PVZ]
+	reception         *Loader[uuid.UUID, *reception.Reception]
+	pvzReceptions     *Loader[childrenKey, []*reception.Reception]
+	receptionProducts *Loader[childrenKey, []*product.Product]
+}
+
+func newLoaders(pvzRepo PVZRepository, receptionRepo ReceptionRepository, productRepo ProductRepository) *loaders {
+	return &loaders{
+		pvz: NewLoader(func(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*domainPVZ.PVZ, error) {
+			pvzs, err := pvzRepo.GetByIDs(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			result := make(map[uuid.UUID]*domainPVZ.PVZ, len(pvzs))
+			for _, p := range pvzs {
+				result[p.ID] = p
+			}
+			return result, nil
+		}),
+		reception: NewLoader(func(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*reception.Reception, error) {
+			receptions, err := receptionRepo.GetByIDs(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			result := make(map[uuid.UUID]*reception.Reception, len(receptions))
+			for _, r := range receptions {
+				result[r.ID] = r
+			}
+			return result, nil
+		}),
+		pvzReceptions: NewLoader(func(ctx context.Context, keys []childrenKey) (map[childrenKey][]*reception.Reception, error) {
+			result := make(map[childrenKey][]*reception.Reception, len(keys))
+			limits, pvzIDs := groupByLimit(keys)
+			for _, limit := range limits {
+				receptions, err := receptionRepo.ListByPVZIDs(ctx, pvzIDs[limit], limit)
+				if err != nil {
+					return nil, err
+				}
+				for _, r := range receptions {
+					key := childrenKey{parentID: r.PVZID, limit: limit}
+					result[key] = append(result[key], r)
+				}
+			}
+			return result, nil
+		}),
+		receptionProducts: NewLoader(func(ctx context.Context, keys []childrenKey) (map[childrenKey][]*product.Product, error) {
+			result := make(map[childrenKey][]*product.Product, len(keys))
+			limits, receptionIDs := groupByLimit(keys)
+			for _, limit := range limits {
+				products, err := productRepo.ListByReceptionIDs(ctx, receptionIDs[limit], limit)
+				if err != nil {
+					return nil, err
+				}
+				for _, p := range products {
+					key := childrenKey{parentID: p.ReceptionID, limit: limit}
+					result[key] = append(result[key], p)
+				}
+			}
+			return result, nil
+		}),
+	}
+}
+
+type loadersKey struct{}
+
+// withLoaders сохраняет загрузчики запроса в контексте
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+// loadersFromContext возвращает загрузчики запроса из контекста
+func loadersFromContext(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
@@ -0,0 +1,76 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoader_Load(t *testing.T) {
+	var calls [][]int
+	loader := NewLoader(func(ctx context.Context, keys []int) (map[int]string, error) {
+		calls = append(calls, keys)
+		result := make(map[int]string, len(keys))
+		for _, k := range keys {
+			if k != 3 {
+				result[k] = string(rune('a' + k))
+			}
+		}
+		return result, nil
+	})
+
+	ctx := context.Background()
+	first := loader.Load(ctx, 0)
+	second := loader.Load(ctx, 1)
+	duplicate := loader.Load(ctx, 0)
+	missing := loader.Load(ctx, 3)
+
+	v, err := second()
+	require.NoError(t, err)
+	assert.Equal(t, "b", v)
+
+	v, err = first()
+	require.NoError(t, err)
+	assert.Equal(t, "a", v)
+
+	v, err = duplicate()
+	require.NoError(t, err)
+	assert.Equal(t, "a", v)
+
+	v, err = missing()
+	require.NoError(t, err)
+	assert.Empty(t, v)
+
+	// Загруженный ключ берется из кеша, новый ключ загружается отдельно
+	v, err = loader.Load(ctx, 1)()
+	require.NoError(t, err)
+	assert.Equal(t, "b", v)
+
+	v, err = loader.Load(ctx, 2)()
+	require.NoError(t, err)
+	assert.Equal(t, "c", v)
+
+	assert.Equal(t, [][]int{{0, 1, 3}, {2}}, calls)
+}
+
+func TestLoader_LoadError(t *testing.T) {
+	fetchErr := errors.New("db error")
+	calls := 0
+	loader := NewLoader(func(ctx context.Context, keys []int) (map[int]string, error) {
+		calls++
+		return nil, fetchErr
+	})
+
+	ctx := context.Background()
+	first := loader.Load(ctx, 1)
+	second := loader.Load(ctx, 2)
+
+	_, err := first()
+	assert.ErrorIs(t, err, fetchErr)
+	_, err = second()
+	assert.ErrorIs(t, err, fetchErr)
+	assert.Equal(t, 1, calls)
+}
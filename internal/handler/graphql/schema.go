@@ -0,0 +1,368 @@
+package graphql
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/listing"
+	"github.com/avito/pvz/internal/domain/product"
+	domainPVZ "github.com/avito/pvz/internal/domain/pvz"
+	"github.com/avito/pvz/internal/domain/reception"
+	"github.com/avito/pvz/internal/handler/apperror"
+	"github.com/avito/pvz/internal/handler/i18n"
+	"github.com/google/uuid"
+	gql "github.com/graphql-go/graphql"
+)
+
+// PVZService определяет методы сервиса ПВЗ, нужные корневым полям схемы
+type PVZService interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*domainPVZ.PVZ, error)
+	ListByFilter(ctx context.Context, filter domainPVZ.ListFilter) ([]*domainPVZ.PVZ, error)
+}
+
+// ReceptionService определяет методы сервиса приемок, нужные корневым полям схемы
+type ReceptionService interface {
+	ListByFilter(ctx context.Context, filter reception.ListFilter) ([]*reception.Reception, error)
+}
+
+// ProductService определяет методы сервиса товаров, нужные корневым полям схемы
+type ProductService interface {
+	ListByFilter(ctx context.Context, filter product.ListFilter) ([]*product.Product, error)
+}
+
+// fieldError ошибка поля GraphQL со стабильным кодом в extensions.code
+type fieldError struct {
+	code    apperror.Code
+	message string
+}
+
+// newFieldError описывает ошибку err на языке запроса.
+// Неизвестные ошибки получают внутренний код с сообщением по ключу fallbackKey.
+func newFieldError(ctx context.Context, err error, fallbackKey string) error {
+	e := apperror.Resolve(err, fallbackKey)
+	return fieldError{code: e.Code, message: e.Message(i18n.LangFromContext(ctx))}
+}
+
+// Error реализует интерфейс error
+func (e fieldError) Error() string {
+	return e.message
+}
+
+// Extensions реализует gqlerrors.ExtendedError
+func (e fieldError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": string(e.code)}
+}
+
+// NewSchema строит схему GraphQL только для чтения над графом ПВЗ → приемки → товары.
+// Вложенные поля загружаются через загрузчики из контекста запроса.
+func NewSchema(pvzService PVZService, receptionService ReceptionService, productService ProductService) (gql.Schema, error) {
+	statusEnum := gql.NewEnum(gql.EnumConfig{
+		Name: "ReceptionStatus",
+		Values: gql.EnumValueConfigMap{
+			"IN_PROGRESS": {Value: reception.StatusInProgress},
+			"CLOSE":       {Value: reception.StatusClose},
+		},
+	})
+
+	typeValues := gql.EnumValueConfigMap{}
+	for _, t := range product.Types() {
+		typeValues[strings.ToUpper(string(t))] = &gql.EnumValueConfig{Value: t}
+	}
+	typeEnum := gql.NewEnum(gql.EnumConfig{Name: "ProductType", Values: typeValues})
+
+	// Вложенные списки возвращают последние limit элементов каждого родителя
+	childListArgs := gql.FieldConfigArgument{
+		"limit": {Type: gql.Int, DefaultValue: listing.DefaultLimit},
+	}
+
+	var pvzType, receptionType, productType *gql.Object
+
+	pvzType = gql.NewObject(gql.ObjectConfig{
+		Name: "PVZ",
+		Fields: gql.FieldsThunk(func() gql.Fields {
+			return gql.Fields{
+				"id":               {Type: gql.NewNonNull(gql.ID), Resolve: resolveID},
+				"registrationDate": {Type: gql.NewNonNull(gql.DateTime), Resolve: resolvePVZRegistrationDate},
+				"city":             {Type: gql.NewNonNull(gql.String)},
+				"receptions": {
+					Type:    gql.NewNonNull(gql.NewList(gql.NewNonNull(receptionType))),
+					Args:    childListArgs,
+					Resolve: resolvePVZReceptions,
+				},
+			}
+		}),
+	})
+
+	receptionType = gql.NewObject(gql.ObjectConfig{
+		Name: "Reception",
+		Fields: gql.FieldsThunk(func() gql.Fields {
+			return gql.Fields{
+				"id":       {Type: gql.NewNonNull(gql.ID), Resolve: resolveID},
+				"dateTime": {Type: gql.NewNonNull(gql.DateTime)},
+				"status":   {Type: gql.NewNonNull(statusEnum)},
+				"pvzId":    {Type: gql.NewNonNull(gql.ID), Resolve: resolveReceptionPVZID},
+				"pvz":      {Type: pvzType, Resolve: resolveReceptionPVZ},
+				"products": {
+					Type:    gql.NewNonNull(gql.NewList(gql.NewNonNull(productType))),
+					Args:    childListArgs,
+					Resolve: resolveReceptionProducts,
+				},
+			}
+		}),
+	})
+
+	productType = gql.NewObject(gql.ObjectConfig{
+		Name: "Product",
+		Fields: gql.FieldsThunk(func() gql.Fields {
+			return gql.Fields{
+				"id":          {Type: gql.NewNonNull(gql.ID), Resolve: resolveID},
+				"dateTime":    {Type: gql.NewNonNull(gql.DateTime)},
+				"type":        {Type: gql.NewNonNull(typeEnum)},
+				"typeName":    {Type: gql.NewNonNull(gql.String), Resolve: resolveProductTypeName},
+				"receptionId": {Type: gql.NewNonNull(gql.ID), Resolve: resolveProductReceptionID},
+				"reception":   {Type: receptionType, Resolve: resolveProductReception},
+			}
+		}),
+	})
+
+	listArgs := func(extra gql.FieldConfigArgument) gql.FieldConfigArgument {
+		args := gql.FieldConfigArgument{
+			"from":   {Type: gql.DateTime},
+			"to":     {Type: gql.DateTime},
+			"sort":   {Type: gql.String, Description: "поле сортировки, префикс «-» — по убыванию"},
+			"offset": {Type: gql.Int, DefaultValue: 0},
+			"limit":  {Type: gql.Int, DefaultValue: listing.DefaultLimit},
+		}
+		for name, arg := range extra {
+			args[name] = arg
+		}
+		return args
+	}
+
+	query := gql.NewObject(gql.ObjectConfig{
+		Name: "Query",
+		Fields: gql.Fields{
+			"pvz": {
+				Type: pvzType,
+				Args: gql.FieldConfigArgument{"id": {Type: gql.NewNonNull(gql.ID)}},
+				Resolve: func(p gql.ResolveParams) (interface{}, error) {
+					id, err := uuid.Parse(p.Args["id"].(string))
+					if err != nil {
+						return nil, newFieldError(p.Context, apperror.InvalidRequest("invalid_pvz_id"), "")
+					}
+					result, err := pvzService.GetByID(p.Context, id)
+					if err != nil {
+						return nil, newFieldError(p.Context, err, "pvz_get_failed")
+					}
+					return result, nil
+				},
+			},
+			"pvzs": {
+				Type: gql.NewNonNull(gql.NewList(gql.NewNonNull(pvzType))),
+				Args: listArgs(gql.FieldConfigArgument{"city": {Type: gql.String}}),
+				Resolve: func(p gql.ResolveParams) (interface{}, error) {
+					filter := domainPVZ.ListFilter{City: stringArg(p.Args, "city")}
+					if err := parseListArgs(p.Args, &filter.CreatedAt, &filter.Sort, &filter.Page); err != nil {
+						return nil, newFieldError(p.Context, err, "")
+					}
+					result, err := pvzService.ListByFilter(p.Context, filter)
+					if err != nil {
+						return nil, newFieldError(p.Context, err, "pvz_list_failed")
+					}
+					return result, nil
+				},
+			},
+			"receptions": {
+				Type: gql.NewNonNull(gql.NewList(gql.NewNonNull(receptionType))),
+				Args: listArgs(gql.FieldConfigArgument{
+					"pvzId":  {Type: gql.ID},
+					"status": {Type: statusEnum},
+				}),
+				Resolve: func(p gql.ResolveParams) (interface{}, error) {
+					var filter reception.ListFilter
+					if status, ok := p.Args["status"].(reception.Status); ok {
+						filter.Status = status
+					}
+					var err error
+					if filter.PVZID, err = uuidArg(p.Args, "pvzId"); err != nil {
+						return nil, newFieldError(p.Context, apperror.InvalidRequest("invalid_pvz_id"), "")
+					}
+					if err := parseListArgs(p.Args, &filter.DateTime, &filter.Sort, &filter.Page); err != nil {
+						return nil, newFieldError(p.Context, err, "")
+					}
+					result, err := receptionService.ListByFilter(p.Context, filter)
+					if err != nil {
+						return nil, newFieldError(p.Context, err, "reception_list_failed")
+					}
+					return result, nil
+				},
+			},
+			"products": {
+				Type: gql.NewNonNull(gql.NewList(gql.NewNonNull(productType))),
+				Args: listArgs(gql.FieldConfigArgument{
+					"receptionId": {Type: gql.ID},
+					"type":        {Type: typeEnum},
+				}),
+				Resolve: func(p gql.ResolveParams) (interface{}, error) {
+					var filter product.ListFilter
+					if t, ok := p.Args["type"].(product.Type); ok {
+						filter.Type = t
+					}
+					var err error
+					if filter.ReceptionID, err = uuidArg(p.Args, "receptionId"); err != nil {
+						return nil, newFieldError(p.Context, apperror.InvalidRequest("invalid_reception_id"), "")
+					}
+					if err := parseListArgs(p.Args, &filter.DateTime, &filter.Sort, &filter.Page); err != nil {
+						return nil, newFieldError(p.Context, err, "")
+					}
+					result, err := productService.ListByFilter(p.Context, filter)
+					if err != nil {
+						return nil, newFieldError(p.Context, err, "product_list_failed")
+					}
+					return result, nil
+				},
+			},
+		},
+	})
+
+	return gql.NewSchema(gql.SchemaConfig{Query: query})
+}
+
+// parseListArgs разбирает общие аргументы списков: диапазон дат, сортировку и страницу
+func parseListArgs(args map[string]interface{}, dates *listing.DateRange, sort *listing.Sort, page *listing.Page) error {
+	if from, ok := args["from"].(time.Time); ok {
+		dates.From = from
+	}
+	if to, ok := args["to"].(time.Time); ok {
+		dates.To = to
+	}
+
+	var err error
+	if *sort, err = listing.ParseSort(stringArg(args, "sort")); err != nil {
+		return err
+	}
+
+	page.Offset, _ = args["offset"].(int)
+	page.Limit, _ = args["limit"].(int)
+	return nil
+}
+
+// stringArg возвращает строковый аргумент или пустую строку, если он не задан
+func stringArg(args map[string]interface{}, name string) string {
+	s, _ := args[name].(string)
+	return s
+}
+
+// uuidArg возвращает аргумент-идентификатор или uuid.Nil, если он не задан
+func uuidArg(args map[string]interface{}, name string) (uuid.UUID, error) {
+	s := stringArg(args, name)
+	if s == "" {
+		return uuid.Nil, nil
+	}
+	return uuid.Parse(s)
+}
+
+// resolveID возвращает идентификатор ПВЗ, приемки или товара
+func resolveID(p gql.ResolveParams) (interface{}, error) {
+	switch source := p.Source.(type) {
+	case *domainPVZ.PVZ:
+		return source.ID.String(), nil
+	case *reception.Reception:
+		return source.ID.String(), nil
+	case *product.Product:
+		return source.ID.String(), nil
+	}
+	return nil, nil
+}
+
+func resolvePVZRegistrationDate(p gql.ResolveParams) (interface{}, error) {
+	return p.Source.(*domainPVZ.PVZ).CreatedAt, nil
+}
+
+// childrenLimit возвращает размер вложенного списка из аргумента limit
+func childrenLimit(p gql.ResolveParams) (int, error) {
+	limit, _ := p.Args["limit"].(int)
+	if err := (listing.Page{Limit: limit}).Validate(); err != nil {
+		return 0, newFieldError(p.Context, err, "")
+	}
+	return limit, nil
+}
+
+func resolvePVZReceptions(p gql.ResolveParams) (interface{}, error) {
+	limit, err := childrenLimit(p)
+	if err != nil {
+		return nil, err
+	}
+	key := childrenKey{parentID: p.Source.(*domainPVZ.PVZ).ID, limit: limit}
+	load := loadersFromContext(p.Context).pvzReceptions.Load(p.Context, key)
+	return func() (interface{}, error) {
+		receptions, err := load()
+		if err != nil {
+			return nil, newFieldError(p.Context, err, "reception_list_failed")
+		}
+		if receptions == nil {
+			receptions = []*reception.Reception{}
+		}
+		return receptions, nil
+	}, nil
+}
+
+func resolveReceptionPVZID(p gql.ResolveParams) (interface{}, error) {
+	return p.Source.(*reception.Reception).PVZID.String(), nil
+}
+
+func resolveReceptionPVZ(p gql.ResolveParams) (interface{}, error) {
+	load := loadersFromContext(p.Context).pvz.Load(p.Context, p.Source.(*reception.Reception).PVZID)
+	return func() (interface{}, error) {
+		result, err := load()
+		if err != nil {
+			return nil, newFieldError(p.Context, err, "pvz_get_failed")
+		}
+		if result == nil {
+			return nil, nil
+		}
+		return result, nil
+	}, nil
+}
+
+func resolveReceptionProducts(p gql.ResolveParams) (interface{}, error) {
+	limit, err := childrenLimit(p)
+	if err != nil {
+		return nil, err
+	}
+	key := childrenKey{parentID: p.Source.(*reception.Reception).ID, limit: limit}
+	load := loadersFromContext(p.Context).receptionProducts.Load(p.Context, key)
+	return func() (interface{}, error) {
+		products, err := load()
+		if err != nil {
+			return nil, newFieldError(p.Context, err, "product_list_failed")
+		}
+		if products == nil {
+			products = []*product.Product{}
+		}
+		return products, nil
+	}, nil
+}
+
+func resolveProductTypeName(p gql.ResolveParams) (interface{}, error) {
+	return i18n.ProductTypeName(i18n.LangFromContext(p.Context), p.Source.(*product.Product).Type), nil
+}
+
+func resolveProductReceptionID(p gql.ResolveParams) (interface{}, error) {
+	return p.Source.(*product.Product).ReceptionID.String(), nil
+}
+
+func resolveProductReception(p gql.ResolveParams) (interface{}, error) {
+	load := loadersFromContext(p.Context).reception.Load(p.Context, p.Source.(*product.Product).ReceptionID)
+	return func() (interface{}, error) {
+		result, err := load()
+		if err != nil {
+			return nil, newFieldError(p.Context, err, "reception_get_failed")
+		}
+		if result == nil {
+			return nil, nil
+		}
+		return result, nil
+	}, nil
+}
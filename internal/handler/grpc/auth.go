@@ -0,0 +1,88 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+
+	"github.com/avito/pvz/internal/domain/apikey"
+	"github.com/avito/pvz/internal/domain/rbac"
+	"github.com/avito/pvz/internal/domain/user"
+	"github.com/avito/pvz/internal/handler/apperror"
+	"github.com/avito/pvz/pkg/auth"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// AuthorizationKey ключ метаданных с токеном доступа в формате "Bearer <token>"
+const AuthorizationKey = "authorization"
+
+// MethodPermissions разрешения, необходимые для вызова методов gRPC API.
+// Методы без записи доступны без токена, как и соответствующие маршруты HTTP API.
+var MethodPermissions = map[string]rbac.Permission{}
+
+// TokenValidator проверяет токены доступа
+type TokenValidator interface {
+	ValidateToken(token string) (*auth.Claims, error)
+}
+
+// APIKeyValidator проверяет ключи API сервисных аккаунтов
+type APIKeyValidator interface {
+	ValidateAPIKey(ctx context.Context, key string) (*apikey.Principal, error)
+}
+
+// AuthInterceptor проверяет токен из метаданных authorization и добавляет
+// пользователя, основное имя его роли и разрешения в контекст. Если задан
+// apiKeys, вместо токена принимается ключ API сервисного аккаунта, как и в
+// HTTP API. Для методов из permissions требуется токен с нужным разрешением,
+// остальные методы вызываются и анонимно.
+func AuthInterceptor(tokens TokenValidator, apiKeys APIKeyValidator, authz *rbac.Authorizer, permissions map[string]rbac.Permission) grpclib.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpclib.UnaryServerInfo, handler grpclib.UnaryHandler) (interface{}, error) {
+		ctx, authErr := authenticate(ctx, tokens, apiKeys, authz)
+
+		permission, ok := permissions[info.FullMethod]
+		if !ok {
+			return handler(ctx, req)
+		}
+		if authErr != nil {
+			return nil, apperror.GRPCError(ctx, authErr, "")
+		}
+		if err := rbac.Check(ctx, permission); err != nil {
+			return nil, apperror.GRPCError(ctx, err, "")
+		}
+		return handler(ctx, req)
+	}
+}
+
+// authenticate проверяет токен из метаданных. При ошибке возвращает исходный контекст.
+func authenticate(ctx context.Context, tokens TokenValidator, apiKeys APIKeyValidator, authz *rbac.Authorizer) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(AuthorizationKey)
+	if len(values) == 0 {
+		return ctx, apperror.ErrUnauthorized
+	}
+
+	token, ok := strings.CutPrefix(values[0], "Bearer ")
+	if !ok {
+		return ctx, apperror.ErrInvalidToken
+	}
+
+	// Сервисный аккаунт получает разрешения из областей ключа
+	if apiKeys != nil && apikey.IsKey(token) {
+		principal, err := apiKeys.ValidateAPIKey(ctx, token)
+		if err != nil {
+			return ctx, apperror.ErrInvalidToken
+		}
+		ctx = auth.WithUserID(ctx, principal.ServiceAccountID)
+		ctx = auth.WithUserRole(ctx, user.RoleService)
+		return rbac.WithPermissions(ctx, principal.Permissions), nil
+	}
+
+	claims, err := tokens.ValidateToken(token)
+	if err != nil {
+		return ctx, apperror.ErrInvalidToken
+	}
+
+	ctx = auth.WithUserID(ctx, claims.UserID)
+	ctx = auth.WithUserRole(ctx, authz.Role(claims.Role))
+	return rbac.WithPermissions(ctx, authz.Permissions(claims.Role)), nil
+}
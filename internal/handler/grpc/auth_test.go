@@ -0,0 +1,139 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/avito/pvz/internal/domain/apikey"
+	"github.com/avito/pvz/internal/domain/rbac"
+	"github.com/avito/pvz/internal/domain/user"
+	sessionService "github.com/avito/pvz/internal/service/session"
+	"github.com/avito/pvz/pkg/auth"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// fakeAPIKeys принимает ключи из списка
+type fakeAPIKeys map[string]*apikey.Principal
+
+func (f fakeAPIKeys) ValidateAPIKey(_ context.Context, key string) (*apikey.Principal, error) {
+	if principal, ok := f[key]; ok {
+		return principal, nil
+	}
+	return nil, errors.New("invalid api key")
+}
+
+func TestAuthInterceptor(t *testing.T) {
+	tokens, err := auth.NewTokenService(auth.DefaultTokenConfig)
+	require.NoError(t, err)
+	authz := rbac.MustNewAuthorizer(rbac.DefaultPolicy)
+
+	const protected = "/pvz.PVZService/CreatePVZ"
+	const public = "/pvz.PVZService/GetAllPVZ"
+	accountID := uuid.New()
+	apiKeys := fakeAPIKeys{
+		"pvz_00000001_create": {ServiceAccountID: accountID, Permissions: rbac.NewSet(rbac.PVZCreate)},
+		"pvz_00000002_read":   {ServiceAccountID: accountID, Permissions: rbac.NewSet(rbac.EventsReadAll)},
+	}
+	interceptor := AuthInterceptor(tokens, apiKeys, authz, map[string]rbac.Permission{protected: rbac.PVZCreate})
+
+	withToken := func(role user.Role) context.Context {
+		token, err := tokens.GenerateToken(uuid.New(), role)
+		require.NoError(t, err)
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs(AuthorizationKey, "Bearer "+token))
+	}
+	withKey := func(key string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs(AuthorizationKey, "Bearer "+key))
+	}
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		method   string
+		wantCode codes.Code
+	}{
+		{name: "открытый метод без токена", ctx: context.Background(), method: public, wantCode: codes.OK},
+		{name: "без токена", ctx: context.Background(), method: protected, wantCode: codes.Unauthenticated},
+		{
+			name:     "неверный токен",
+			ctx:      metadata.NewIncomingContext(context.Background(), metadata.Pairs(AuthorizationKey, "Bearer invalid")),
+			method:   protected,
+			wantCode: codes.Unauthenticated,
+		},
+		{name: "нет разрешения", ctx: withToken(user.RoleEmployee), method: protected, wantCode: codes.PermissionDenied},
+		{name: "есть разрешение", ctx: withToken(user.RoleAdmin), method: protected, wantCode: codes.OK},
+		{name: "псевдоним роли", ctx: withToken("moderator"), method: protected, wantCode: codes.OK},
+		{name: "ключ API с областью", ctx: withKey("pvz_00000001_create"), method: protected, wantCode: codes.OK},
+		{name: "ключ API без области", ctx: withKey("pvz_00000002_read"), method: protected, wantCode: codes.PermissionDenied},
+		{name: "неизвестный ключ API", ctx: withKey("pvz_00000003_unknown"), method: protected, wantCode: codes.Unauthenticated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := interceptor(tt.ctx, nil, &grpclib.UnaryServerInfo{FullMethod: tt.method}, func(ctx context.Context, req interface{}) (interface{}, error) {
+				return nil, nil
+			})
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+
+	t.Run("роль в контексте", func(t *testing.T) {
+		_, err := interceptor(withToken("moderator"), nil, &grpclib.UnaryServerInfo{FullMethod: public}, func(ctx context.Context, req interface{}) (interface{}, error) {
+			role, ok := auth.GetUserRole(ctx)
+			assert.True(t, ok)
+			assert.Equal(t, user.RoleAdmin, role)
+			return nil, nil
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("сервисный аккаунт в контексте", func(t *testing.T) {
+		_, err := interceptor(withKey("pvz_00000001_create"), nil, &grpclib.UnaryServerInfo{FullMethod: public}, func(ctx context.Context, req interface{}) (interface{}, error) {
+			id, ok := auth.GetUserID(ctx)
+			assert.True(t, ok)
+			assert.Equal(t, accountID, id)
+			role, ok := auth.GetUserRole(ctx)
+			assert.True(t, ok)
+			assert.Equal(t, user.RoleService, role)
+			return nil, nil
+		})
+		require.NoError(t, err)
+	})
+}
+
+// revokingTokens отклоняет отозванный токен, как сервис сессий
+type revokingTokens struct {
+	TokenValidator
+	revoked string
+}
+
+func (r revokingTokens) ValidateToken(token string) (*auth.Claims, error) {
+	if token == r.revoked {
+		return nil, sessionService.ErrTokenRevoked
+	}
+	return r.TokenValidator.ValidateToken(token)
+}
+
+func TestAuthInterceptor_RevokedToken(t *testing.T) {
+	tokens, err := auth.NewTokenService(auth.DefaultTokenConfig)
+	require.NoError(t, err)
+	token, err := tokens.GenerateToken(uuid.New(), user.RoleAdmin)
+	require.NoError(t, err)
+
+	const method = "/pvz.PVZService/CreatePVZ"
+	interceptor := AuthInterceptor(revokingTokens{TokenValidator: tokens, revoked: token}, fakeAPIKeys{},
+		rbac.MustNewAuthorizer(rbac.DefaultPolicy), map[string]rbac.Permission{method: rbac.PVZCreate})
+
+	// Подпись и срок действия токена верны, но сессия завершена
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(AuthorizationKey, "Bearer "+token))
+	_, err = interceptor(ctx, nil, &grpclib.UnaryServerInfo{FullMethod: method}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, nil
+	})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
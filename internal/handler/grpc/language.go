@@ -0,0 +1,24 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/avito/pvz/internal/handler/i18n"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// AcceptLanguageKey ключ метаданных с предпочитаемым языком сообщений
+const AcceptLanguageKey = "accept-language"
+
+// LanguageInterceptor выбирает язык сообщений по метаданным accept-language.
+// По умолчанию gRPC API отвечает на английском.
+func LanguageInterceptor(ctx context.Context, req interface{}, _ *grpclib.UnaryServerInfo, handler grpclib.UnaryHandler) (interface{}, error) {
+	lang := i18n.English
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(AcceptLanguageKey); len(values) > 0 {
+			lang = i18n.ParseAcceptLanguage(values[0], i18n.English)
+		}
+	}
+	return handler(i18n.WithLang(ctx, lang), req)
+}
@@ -0,0 +1,47 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/avito/pvz/internal/handler/i18n"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestLanguageInterceptor(t *testing.T) {
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		expected i18n.Lang
+	}{
+		{
+			name:     "без метаданных",
+			ctx:      context.Background(),
+			expected: i18n.English,
+		},
+		{
+			name:     "русский в метаданных",
+			ctx:      metadata.NewIncomingContext(context.Background(), metadata.Pairs(AcceptLanguageKey, "ru-RU")),
+			expected: i18n.Russian,
+		},
+		{
+			name:     "неподдерживаемый язык",
+			ctx:      metadata.NewIncomingContext(context.Background(), metadata.Pairs(AcceptLanguageKey, "de")),
+			expected: i18n.English,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got i18n.Lang
+			_, err := LanguageInterceptor(tt.ctx, nil, nil, func(ctx context.Context, req interface{}) (interface{}, error) {
+				got = i18n.LangFromContext(ctx)
+				return nil, nil
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
@@ -4,9 +4,9 @@ import (
 	"context"
 
 	"github.com/avito/pvz/api/proto"
+	domainPVZ "github.com/avito/pvz/internal/domain/pvz"
+	"github.com/avito/pvz/internal/handler/apperror"
 	servicePVZ "github.com/avito/pvz/internal/service/pvz"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -27,7 +27,7 @@ func NewPVZHandler(pvzService *servicePVZ.Service) *PVZHandler {
 func (h *PVZHandler) GetAllPVZ(ctx context.Context, req *proto.GetAllPVZRequest) (*proto.GetAllPVZResponse, error) {
 	pvzs, err := h.pvzService.GetAll(ctx)
 	if err != nil {
-		return nil, status.Error(codes.Internal, "failed to get PVZs")
+		return nil, apperror.GRPCError(ctx, err, "pvz_list_failed")
 	}
 
 	response := &proto.GetAllPVZResponse{
@@ -44,3 +44,75 @@ func (h *PVZHandler) GetAllPVZ(ctx context.Context, req *proto.GetAllPVZRequest)
 
 	return response, nil
 }
+
+// GetPVZWithReceptions возвращает ПВЗ с приемками и товарами за период
+func (h *PVZHandler) GetPVZWithReceptions(ctx context.Context, req *proto.GetPVZWithReceptionsRequest) (*proto.GetPVZWithReceptionsResponse, error) {
+	if req.GetStartDate() == nil || req.GetEndDate() == nil {
+		return nil, apperror.GRPCError(ctx, apperror.InvalidRequest("dates_required"), "")
+	}
+	startDate := req.GetStartDate().AsTime()
+	endDate := req.GetEndDate().AsTime()
+
+	limit := int(req.GetLimit())
+	if limit == 0 {
+		limit = 10
+	}
+
+	var pvzs []*domainPVZ.PVZWithReceptions
+	var nextCursor string
+	var err error
+	if req.GetPage() > 0 && req.GetCursor() == "" {
+		pvzs, err = h.pvzService.GetWithReceptions(ctx, startDate, endDate, int(req.GetPage()), limit)
+	} else {
+		pvzs, nextCursor, err = h.pvzService.GetWithReceptionsByCursor(ctx, startDate, endDate, req.GetCursor(), limit)
+	}
+	if err != nil {
+		return nil, apperror.GRPCError(ctx, err, "pvz_with_receptions_failed")
+	}
+
+	response := &proto.GetPVZWithReceptionsResponse{
+		Pvzs:       make([]*proto.PVZWithReceptions, len(pvzs)),
+		NextCursor: nextCursor,
+	}
+
+	for i, p := range pvzs {
+		response.Pvzs[i] = toProtoPVZWithReceptions(p)
+	}
+
+	return response, nil
+}
+
+// toProtoPVZWithReceptions преобразует ПВЗ с приемками в protobuf-сообщение
+func toProtoPVZWithReceptions(p *domainPVZ.PVZWithReceptions) *proto.PVZWithReceptions {
+	result := &proto.PVZWithReceptions{
+		Pvz: &proto.PVZ{
+			Id:               p.PVZ.ID.String(),
+			City:             p.PVZ.City,
+			RegistrationDate: timestamppb.New(p.PVZ.CreatedAt),
+		},
+		Receptions: make([]*proto.ReceptionWithProducts, len(p.Receptions)),
+	}
+
+	for i, r := range p.Receptions {
+		item := &proto.ReceptionWithProducts{
+			Reception: &proto.Reception{
+				Id:       r.Reception.ID.String(),
+				DateTime: timestamppb.New(r.Reception.DateTime),
+				PvzId:    r.Reception.PVZID.String(),
+				Status:   string(r.Reception.Status),
+			},
+			Products: make([]*proto.Product, len(r.Products)),
+		}
+		for j, pr := range r.Products {
+			item.Products[j] = &proto.Product{
+				Id:          pr.ID.String(),
+				DateTime:    timestamppb.New(pr.DateTime),
+				Type:        string(pr.Type),
+				ReceptionId: pr.ReceptionID.String(),
+			}
+		}
+		result.Receptions[i] = item
+	}
+
+	return result
+}
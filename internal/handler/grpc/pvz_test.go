@@ -53,6 +53,14 @@ func (m *MockPVZService) GetWithReceptions(ctx context.Context, startDate, endDa
 	return args.Get(0).([]*domainPVZ.PVZWithReceptions), args.Error(1)
 }
 
+func (m *MockPVZService) GetWithReceptionsByCursor(ctx context.Context, startDate, endDate time.Time, cursor string, limit int) ([]*domainPVZ.PVZWithReceptions, string, error) {
+	args := m.Called(ctx, startDate, endDate, cursor, limit)
+	if args.Get(0) == nil {
+		return nil, args.String(1), args.Error(2)
+	}
+	return args.Get(0).([]*domainPVZ.PVZWithReceptions), args.String(1), args.Error(2)
+}
+
 func (m *MockPVZService) GetAll(ctx context.Context) ([]*domainPVZ.PVZ, error) {
 	args := m.Called(ctx)
 	return args.Get(0).([]*domainPVZ.PVZ), args.Error(1)
@@ -73,6 +81,14 @@ func (m *MockPVZService) List(ctx context.Context, offset, limit int) ([]*domain
 	return args.Get(0).([]*domainPVZ.PVZ), args.Error(1)
 }
 
+func (m *MockPVZService) ListByFilter(ctx context.Context, filter domainPVZ.ListFilter) ([]*domainPVZ.PVZ, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domainPVZ.PVZ), args.Error(1)
+}
+
 // testPVZHandler - тестовая версия PVZHandler
 type testPVZHandler struct {
 	proto.UnimplementedPVZServiceServer
@@ -0,0 +1,61 @@
+package grpc
+
+import (
+	"context"
+	"math"
+	"net"
+	"strconv"
+
+	"github.com/avito/pvz/internal/domain/ratelimit"
+	"github.com/avito/pvz/internal/domain/user"
+	"github.com/avito/pvz/internal/handler/apperror"
+	"github.com/avito/pvz/internal/metrics"
+	"github.com/avito/pvz/pkg/auth"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// RetryAfterKey ключ метаданных ответа с числом секунд до повтора отклоненного вызова
+const RetryAfterKey = "retry-after"
+
+// RateLimiter проверяет частоту запросов
+type RateLimiter interface {
+	Allow(ctx context.Context, route string, role user.Role, subject string) ratelimit.Decision
+}
+
+// RateLimitInterceptor ограничивает частоту вызовов по полному имени метода,
+// роли и вызывающему: пользователю из токена или IP-адресу клиента.
+// Превысившему лимит возвращается ResourceExhausted с метаданными retry-after.
+// Подключается после AuthInterceptor.
+func RateLimitInterceptor(limiter RateLimiter) grpclib.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpclib.UnaryServerInfo, handler grpclib.UnaryHandler) (interface{}, error) {
+		role, _ := auth.GetUserRole(ctx)
+
+		decision := limiter.Allow(ctx, info.FullMethod, role, rateLimitSubject(ctx))
+		if !decision.Allowed {
+			metrics.RateLimitRejected.WithLabelValues("grpc", info.FullMethod, string(role)).Inc()
+			retryAfter := strconv.Itoa(int(math.Ceil(decision.RetryAfter.Seconds())))
+			_ = grpclib.SetHeader(ctx, metadata.Pairs(RetryAfterKey, retryAfter))
+			return nil, apperror.GRPCError(ctx, apperror.ErrRateLimited, "")
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// rateLimitSubject возвращает вызывающего: пользователя из токена или IP-адрес
+func rateLimitSubject(ctx context.Context) string {
+	if userID, ok := auth.GetUserID(ctx); ok {
+		return "user:" + userID.String()
+	}
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return "ip:unknown"
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		host = p.Addr.String()
+	}
+	return "ip:" + host
+}
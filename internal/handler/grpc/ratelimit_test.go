@@ -0,0 +1,68 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/ratelimit"
+	"github.com/avito/pvz/internal/domain/user"
+	"github.com/avito/pvz/pkg/auth"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// stubLimiter запоминает последний проверенный вызов и отвечает заданным решением
+type stubLimiter struct {
+	decision ratelimit.Decision
+	route    string
+	role     user.Role
+	subject  string
+}
+
+func (l *stubLimiter) Allow(_ context.Context, route string, role user.Role, subject string) ratelimit.Decision {
+	l.route, l.role, l.subject = route, role, subject
+	return l.decision
+}
+
+func TestRateLimitInterceptor(t *testing.T) {
+	const method = "/pvz.PVZService/GetAllPVZ"
+	info := &grpclib.UnaryServerInfo{FullMethod: method}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+
+	t.Run("анонимный вызов ограничивается по IP-адресу", func(t *testing.T) {
+		limiter := &stubLimiter{decision: ratelimit.Decision{Allowed: true}}
+		ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 5000}})
+
+		resp, err := RateLimitInterceptor(limiter)(ctx, nil, info, handler)
+		assert.NoError(t, err)
+		assert.Equal(t, "ok", resp)
+		assert.Equal(t, method, limiter.route)
+		assert.Equal(t, "ip:10.0.0.1", limiter.subject)
+	})
+
+	t.Run("пользователь из токена", func(t *testing.T) {
+		limiter := &stubLimiter{decision: ratelimit.Decision{Allowed: true}}
+		userID := uuid.New()
+		ctx := auth.WithUserRole(auth.WithUserID(context.Background(), userID), user.RoleEmployee)
+
+		_, err := RateLimitInterceptor(limiter)(ctx, nil, info, handler)
+		assert.NoError(t, err)
+		assert.Equal(t, "user:"+userID.String(), limiter.subject)
+		assert.Equal(t, user.RoleEmployee, limiter.role)
+	})
+
+	t.Run("превышение лимита", func(t *testing.T) {
+		limiter := &stubLimiter{decision: ratelimit.Decision{RetryAfter: time.Second}}
+
+		_, err := RateLimitInterceptor(limiter)(context.Background(), nil, info, handler)
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	})
+}
@@ -0,0 +1,31 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/avito/pvz/internal/domain/audit"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// RequestIDKey ключ метаданных с ID запроса
+const RequestIDKey = "x-request-id"
+
+// RequestIDInterceptor берет ID запроса из метаданных x-request-id или создает
+// новый, возвращает его в заголовке ответа и сохраняет в контексте вместе
+// с источником gRPC для журнала аудита.
+func RequestIDInterceptor(ctx context.Context, req interface{}, _ *grpclib.UnaryServerInfo, handler grpclib.UnaryHandler) (interface{}, error) {
+	var id string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(RequestIDKey); len(values) > 0 {
+			id = values[0]
+		}
+	}
+	id = audit.RequestIDOrNew(id)
+
+	// Вне серверного потока, например в тестах, заголовок не отправить,
+	// но ID запроса все равно попадает в журнал
+	_ = grpclib.SetHeader(ctx, metadata.Pairs(RequestIDKey, id))
+
+	return handler(audit.WithOrigin(ctx, audit.Origin{Source: audit.SourceGRPC, RequestID: id}), req)
+}
@@ -0,0 +1,38 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/avito/pvz/internal/domain/audit"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestRequestIDInterceptor(t *testing.T) {
+	t.Run("ID из метаданных", func(t *testing.T) {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(RequestIDKey, "req-1"))
+
+		var got audit.Origin
+		_, err := RequestIDInterceptor(ctx, nil, nil, func(ctx context.Context, req interface{}) (interface{}, error) {
+			got = audit.OriginFrom(ctx)
+			return nil, nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, audit.Origin{Source: audit.SourceGRPC, RequestID: "req-1"}, got)
+	})
+
+	t.Run("новый ID без метаданных", func(t *testing.T) {
+		var got audit.Origin
+		_, err := RequestIDInterceptor(context.Background(), nil, nil, func(ctx context.Context, req interface{}) (interface{}, error) {
+			got = audit.OriginFrom(ctx)
+			return nil, nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, audit.SourceGRPC, got.Source)
+		_, err = uuid.Parse(got.RequestID)
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,125 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/avito/pvz/internal/handler/apperror"
+	"github.com/avito/pvz/internal/handler/http/middleware"
+	"github.com/go-chi/chi/v5"
+)
+
+// AccountServiceInterface определяет интерфейс для сервиса восстановления доступа
+type AccountServiceInterface interface {
+	RequestPasswordReset(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token, password string) error
+	RequestEmailVerification(ctx context.Context) error
+	VerifyEmail(ctx context.Context, token string) error
+}
+
+// AccountHandler обрабатывает HTTP-запросы сброса пароля и подтверждения email
+type AccountHandler struct {
+	service AccountServiceInterface
+}
+
+// NewAccountHandler создает новый экземпляр AccountHandler
+func NewAccountHandler(service AccountServiceInterface) *AccountHandler {
+	return &AccountHandler{
+		service: service,
+	}
+}
+
+// RegisterRoutes регистрирует маршруты сброса пароля и подтверждения email
+func (h *AccountHandler) RegisterRoutes(r chi.Router) {
+	r.Post("/password/reset/request", h.RequestPasswordReset)
+	r.Post("/password/reset", h.ResetPassword)
+	r.Post("/email/verify", h.VerifyEmail)
+
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.AuthMiddleware)
+
+		r.Post("/email/verify/request", h.RequestEmailVerification)
+	})
+}
+
+// RequestPasswordReset отправляет ссылку сброса пароля. Ответ одинаков для
+// зарегистрированных и неизвестных email.
+func (h *AccountHandler) RequestPasswordReset(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Email string `json:"email"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apperror.WriteInvalidRequest(w, r, "invalid_body")
+		return
+	}
+	if req.Email == "" {
+		apperror.WriteInvalidRequest(w, r, "email_required")
+		return
+	}
+
+	if err := h.service.RequestPasswordReset(r.Context(), req.Email); err != nil {
+		apperror.WriteHTTP(w, r, err, "password_reset_failed")
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// ResetPassword заменяет пароль по токену из письма
+func (h *AccountHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Token    string `json:"token"`
+		Password string `json:"password"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apperror.WriteInvalidRequest(w, r, "invalid_body")
+		return
+	}
+	if req.Token == "" {
+		apperror.WriteInvalidRequest(w, r, "token_required")
+		return
+	}
+
+	if err := h.service.ResetPassword(r.Context(), req.Token, req.Password); err != nil {
+		apperror.WriteHTTP(w, r, err, "password_reset_failed")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RequestEmailVerification отправляет ссылку подтверждения на email текущего пользователя
+func (h *AccountHandler) RequestEmailVerification(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.RequestEmailVerification(r.Context()); err != nil {
+		apperror.WriteHTTP(w, r, err, "email_verification_failed")
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// VerifyEmail подтверждает email по токену из письма
+func (h *AccountHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Token string `json:"token"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apperror.WriteInvalidRequest(w, r, "invalid_body")
+		return
+	}
+	if req.Token == "" {
+		apperror.WriteInvalidRequest(w, r, "token_required")
+		return
+	}
+
+	if err := h.service.VerifyEmail(r.Context(), req.Token); err != nil {
+		apperror.WriteHTTP(w, r, err, "email_verification_failed")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
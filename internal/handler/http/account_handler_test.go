@@ -0,0 +1,147 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/avito/pvz/internal/handler/apperror"
+	accountService "github.com/avito/pvz/internal/service/account"
+	"github.com/avito/pvz/pkg/httpresponse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockAccountService struct {
+	mock.Mock
+}
+
+func (m *mockAccountService) RequestPasswordReset(ctx context.Context, email string) error {
+	args := m.Called(ctx, email)
+	return args.Error(0)
+}
+
+func (m *mockAccountService) ResetPassword(ctx context.Context, token, password string) error {
+	args := m.Called(ctx, token, password)
+	return args.Error(0)
+}
+
+func (m *mockAccountService) RequestEmailVerification(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func (m *mockAccountService) VerifyEmail(ctx context.Context, token string) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+func TestAccountHandler_RequestPasswordReset(t *testing.T) {
+	t.Run("письмо отправлено", func(t *testing.T) {
+		service := new(mockAccountService)
+		service.On("RequestPasswordReset", mock.Anything, "user@example.com").Return(nil)
+		handler := NewAccountHandler(service)
+
+		req := httptest.NewRequest(http.MethodPost, "/password/reset/request", strings.NewReader(`{"email":"user@example.com"}`))
+		rec := httptest.NewRecorder()
+
+		handler.RequestPasswordReset(rec, req)
+
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+		service.AssertExpectations(t)
+	})
+
+	t.Run("не указан email", func(t *testing.T) {
+		handler := NewAccountHandler(new(mockAccountService))
+
+		req := httptest.NewRequest(http.MethodPost, "/password/reset/request", strings.NewReader(`{}`))
+		rec := httptest.NewRecorder()
+
+		handler.RequestPasswordReset(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestAccountHandler_ResetPassword(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		err            error
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			name:           "пароль заменен",
+			body:           `{"token":"token","password":"NewPassw0rd!"}`,
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "ссылка недействительна",
+			body:           `{"token":"token","password":"NewPassw0rd!"}`,
+			err:            accountService.ErrInvalidToken,
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   string(apperror.CodeInvalidAccountToken),
+		},
+		{
+			name:           "не указан токен",
+			body:           `{"password":"NewPassw0rd!"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   string(apperror.CodeInvalidRequest),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(mockAccountService)
+			service.On("ResetPassword", mock.Anything, "token", "NewPassw0rd!").Return(tt.err).Maybe()
+			handler := NewAccountHandler(service)
+
+			req := httptest.NewRequest(http.MethodPost, "/password/reset", strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+
+			handler.ResetPassword(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedCode != "" {
+				var problem httpresponse.ProblemDetails
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&problem))
+				assert.Equal(t, tt.expectedCode, problem.Code)
+			}
+		})
+	}
+}
+
+func TestAccountHandler_VerifyEmail(t *testing.T) {
+	t.Run("email подтвержден", func(t *testing.T) {
+		service := new(mockAccountService)
+		service.On("VerifyEmail", mock.Anything, "token").Return(nil)
+		handler := NewAccountHandler(service)
+
+		req := httptest.NewRequest(http.MethodPost, "/email/verify", strings.NewReader(`{"token":"token"}`))
+		rec := httptest.NewRecorder()
+
+		handler.VerifyEmail(rec, req)
+
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		service.AssertExpectations(t)
+	})
+
+	t.Run("email уже подтвержден", func(t *testing.T) {
+		service := new(mockAccountService)
+		service.On("RequestEmailVerification", mock.Anything).Return(accountService.ErrEmailAlreadyVerified)
+		handler := NewAccountHandler(service)
+
+		req := httptest.NewRequest(http.MethodPost, "/email/verify/request", nil)
+		rec := httptest.NewRecorder()
+
+		handler.RequestEmailVerification(rec, req)
+
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		service.AssertExpectations(t)
+	})
+}
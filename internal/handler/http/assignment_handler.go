@@ -0,0 +1,170 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/assignment"
+	"github.com/avito/pvz/internal/domain/rbac"
+	"github.com/avito/pvz/internal/handler/apperror"
+	"github.com/avito/pvz/internal/handler/http/middleware"
+	"github.com/avito/pvz/pkg/httpresponse"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// AssignmentServiceInterface определяет интерфейс для сервиса закреплений за ПВЗ
+type AssignmentServiceInterface interface {
+	Create(ctx context.Context, userID, pvzID uuid.UUID, validFrom time.Time, validTo *time.Time, createdBy uuid.UUID) (*assignment.Assignment, error)
+	List(ctx context.Context, filter assignment.Filter) ([]*assignment.Assignment, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// AssignmentHandler обрабатывает HTTP-запросы управления закреплениями сотрудников за ПВЗ
+type AssignmentHandler struct {
+	service AssignmentServiceInterface
+}
+
+// NewAssignmentHandler создает новый экземпляр AssignmentHandler
+func NewAssignmentHandler(service AssignmentServiceInterface) *AssignmentHandler {
+	return &AssignmentHandler{
+		service: service,
+	}
+}
+
+// RegisterRoutes регистрирует маршруты закреплений, доступные только модераторам
+func (h *AssignmentHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.AuthMiddleware)
+		r.Use(middleware.RequirePermission(rbac.AssignmentManage))
+
+		r.Post("/assignments", h.Create)
+		r.Get("/assignments", h.List)
+		r.Delete("/assignments/{assignmentId}", h.Delete)
+	})
+}
+
+// assignmentResponse закрепление в ответе API
+type assignmentResponse struct {
+	ID        string     `json:"id"`
+	UserID    string     `json:"userId"`
+	PVZID     string     `json:"pvzId"`
+	ValidFrom time.Time  `json:"validFrom"`
+	ValidTo   *time.Time `json:"validTo,omitempty"`
+	CreatedBy string     `json:"createdBy"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
+
+func newAssignmentResponse(a *assignment.Assignment) assignmentResponse {
+	return assignmentResponse{
+		ID:        a.ID.String(),
+		UserID:    a.UserID.String(),
+		PVZID:     a.PVZID.String(),
+		ValidFrom: a.ValidFrom,
+		ValidTo:   a.ValidTo,
+		CreatedBy: a.CreatedBy.String(),
+		CreatedAt: a.CreatedAt,
+	}
+}
+
+// Create обрабатывает закрепление сотрудника за ПВЗ
+func (h *AssignmentHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		UserID    uuid.UUID  `json:"userId"`
+		PVZID     uuid.UUID  `json:"pvzId"`
+		ValidFrom *time.Time `json:"validFrom"`
+		ValidTo   *time.Time `json:"validTo"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apperror.WriteInvalidRequest(w, r, "invalid_body")
+		return
+	}
+	if req.UserID == uuid.Nil {
+		apperror.WriteInvalidRequest(w, r, "user_id_required")
+		return
+	}
+	if req.PVZID == uuid.Nil {
+		apperror.WriteInvalidRequest(w, r, "pvz_id_required")
+		return
+	}
+
+	userIDStr, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		apperror.WriteHTTP(w, r, apperror.ErrUnauthorized, "")
+		return
+	}
+	createdBy, err := uuid.Parse(userIDStr)
+	if err != nil {
+		apperror.WriteHTTP(w, r, apperror.ErrUnauthorized, "")
+		return
+	}
+
+	var validFrom time.Time
+	if req.ValidFrom != nil {
+		validFrom = *req.ValidFrom
+	}
+
+	a, err := h.service.Create(r.Context(), req.UserID, req.PVZID, validFrom, req.ValidTo, createdBy)
+	if err != nil {
+		apperror.WriteHTTP(w, r, err, "assignment_create_failed")
+		return
+	}
+
+	httpresponse.JSON(w, http.StatusCreated, newAssignmentResponse(a))
+}
+
+// List обрабатывает получение закреплений. Параметры userId и pvzId
+// ограничивают выборку сотрудником и ПВЗ.
+func (h *AssignmentHandler) List(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	var filter assignment.Filter
+
+	if v := q.Get("userId"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			apperror.WriteInvalidRequest(w, r, "invalid_user_id")
+			return
+		}
+		filter.UserID = id
+	}
+	if v := q.Get("pvzId"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			apperror.WriteInvalidRequest(w, r, "invalid_pvz_id")
+			return
+		}
+		filter.PVZID = id
+	}
+
+	assignments, err := h.service.List(r.Context(), filter)
+	if err != nil {
+		apperror.WriteHTTP(w, r, err, "assignment_list_failed")
+		return
+	}
+
+	resp := make([]assignmentResponse, len(assignments))
+	for i, a := range assignments {
+		resp[i] = newAssignmentResponse(a)
+	}
+
+	httpresponse.JSON(w, http.StatusOK, resp)
+}
+
+// Delete обрабатывает удаление закрепления
+func (h *AssignmentHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "assignmentId"))
+	if err != nil {
+		apperror.WriteInvalidRequest(w, r, "invalid_assignment_id")
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), id); err != nil {
+		apperror.WriteHTTP(w, r, err, "assignment_delete_failed")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
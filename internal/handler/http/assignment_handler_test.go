@@ -0,0 +1,182 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/assignment"
+	"github.com/avito/pvz/internal/handler/apperror"
+	"github.com/avito/pvz/internal/handler/http/middleware"
+	assignmentService "github.com/avito/pvz/internal/service/assignment"
+	"github.com/avito/pvz/pkg/httpresponse"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockAssignmentService struct {
+	mock.Mock
+}
+
+func (m *mockAssignmentService) Create(ctx context.Context, userID, pvzID uuid.UUID, validFrom time.Time, validTo *time.Time, createdBy uuid.UUID) (*assignment.Assignment, error) {
+	args := m.Called(ctx, userID, pvzID, validFrom, validTo, createdBy)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*assignment.Assignment), args.Error(1)
+}
+
+func (m *mockAssignmentService) List(ctx context.Context, filter assignment.Filter) ([]*assignment.Assignment, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*assignment.Assignment), args.Error(1)
+}
+
+func (m *mockAssignmentService) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func TestAssignmentHandler_Create(t *testing.T) {
+	moderatorID, userID, pvzID := uuid.New(), uuid.New(), uuid.New()
+	validTo := time.Date(2026, time.December, 31, 0, 0, 0, 0, time.UTC)
+	created := &assignment.Assignment{
+		ID:        uuid.New(),
+		UserID:    userID,
+		PVZID:     pvzID,
+		ValidFrom: time.Now(),
+		ValidTo:   &validTo,
+		CreatedBy: moderatorID,
+		CreatedAt: time.Now(),
+	}
+
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(*mockAssignmentService)
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			name: "успешное закрепление",
+			body: `{"userId":"` + userID.String() + `","pvzId":"` + pvzID.String() + `","validTo":"2026-12-31T00:00:00Z"}`,
+			setupMock: func(m *mockAssignmentService) {
+				m.On("Create", mock.Anything, userID, pvzID, time.Time{}, &validTo, moderatorID).Return(created, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "не указан ПВЗ",
+			body:           `{"userId":"` + userID.String() + `"}`,
+			setupMock:      func(m *mockAssignmentService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   string(apperror.CodeInvalidRequest),
+		},
+		{
+			name: "неверный период",
+			body: `{"userId":"` + userID.String() + `","pvzId":"` + pvzID.String() + `","validFrom":"2026-12-31T00:00:00Z","validTo":"2026-12-31T00:00:00Z"}`,
+			setupMock: func(m *mockAssignmentService) {
+				m.On("Create", mock.Anything, userID, pvzID, validTo, &validTo, moderatorID).Return(nil, assignmentService.ErrInvalidPeriod)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   string(apperror.CodeInvalidAssignmentPeriod),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(mockAssignmentService)
+			tt.setupMock(service)
+			handler := NewAssignmentHandler(service)
+
+			req := httptest.NewRequest(http.MethodPost, "/assignments", strings.NewReader(tt.body))
+			req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, moderatorID.String()))
+			rec := httptest.NewRecorder()
+
+			handler.Create(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedCode != "" {
+				var problem httpresponse.ProblemDetails
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&problem))
+				assert.Equal(t, tt.expectedCode, problem.Code)
+			} else {
+				var resp assignmentResponse
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+				assert.Equal(t, created.ID.String(), resp.ID)
+				assert.Equal(t, pvzID.String(), resp.PVZID)
+			}
+			service.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAssignmentHandler_List(t *testing.T) {
+	userID := uuid.New()
+
+	t.Run("фильтр по сотруднику", func(t *testing.T) {
+		service := new(mockAssignmentService)
+		service.On("List", mock.Anything, assignment.Filter{UserID: userID}).
+			Return([]*assignment.Assignment{{ID: uuid.New(), UserID: userID, PVZID: uuid.New()}}, nil)
+		handler := NewAssignmentHandler(service)
+
+		req := httptest.NewRequest(http.MethodGet, "/assignments?userId="+userID.String(), nil)
+		rec := httptest.NewRecorder()
+
+		handler.List(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var resp []assignmentResponse
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+		require.Len(t, resp, 1)
+		assert.Equal(t, userID.String(), resp[0].UserID)
+	})
+
+	t.Run("неверный ID ПВЗ", func(t *testing.T) {
+		handler := NewAssignmentHandler(new(mockAssignmentService))
+
+		req := httptest.NewRequest(http.MethodGet, "/assignments?pvzId=bad", nil)
+		rec := httptest.NewRecorder()
+
+		handler.List(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestAssignmentHandler_Delete(t *testing.T) {
+	id := uuid.New()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+	}{
+		{name: "успешное удаление", err: nil, expectedStatus: http.StatusNoContent},
+		{name: "закрепление не найдено", err: assignment.ErrNotFound, expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(mockAssignmentService)
+			service.On("Delete", mock.Anything, id).Return(tt.err)
+			handler := NewAssignmentHandler(service)
+
+			req := withRouteParam(httptest.NewRequest(http.MethodDelete, "/assignments/"+id.String(), nil), "assignmentId", id.String())
+			rec := httptest.NewRecorder()
+
+			handler.Delete(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			service.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/audit"
+	"github.com/avito/pvz/internal/domain/rbac"
+	"github.com/avito/pvz/internal/handler/apperror"
+	"github.com/avito/pvz/internal/handler/http/middleware"
+	"github.com/avito/pvz/pkg/httpresponse"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// AuditServiceInterface определяет интерфейс для сервиса журнала аудита
+type AuditServiceInterface interface {
+	List(ctx context.Context, filter audit.Filter) ([]*audit.Entry, error)
+}
+
+// AuditHandler обрабатывает HTTP-запросы к журналу аудита
+type AuditHandler struct {
+	service AuditServiceInterface
+}
+
+// NewAuditHandler создает новый экземпляр AuditHandler
+func NewAuditHandler(service AuditServiceInterface) *AuditHandler {
+	return &AuditHandler{
+		service: service,
+	}
+}
+
+// RegisterRoutes регистрирует маршрут журнала аудита, доступный только модераторам
+func (h *AuditHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.AuthMiddleware)
+		r.Use(middleware.RequirePermission(rbac.AuditRead))
+
+		r.Get("/audit", h.List)
+	})
+}
+
+// auditEntryResponse запись журнала в ответе API
+type auditEntryResponse struct {
+	ID         string          `json:"id"`
+	ActorID    string          `json:"actorId,omitempty"`
+	EntityType string          `json:"entityType"`
+	EntityID   string          `json:"entityId"`
+	Action     string          `json:"action"`
+	Before     json.RawMessage `json:"before,omitempty"`
+	After      json.RawMessage `json:"after,omitempty"`
+	RequestID  string          `json:"requestId,omitempty"`
+	Source     string          `json:"source"`
+	CreatedAt  time.Time       `json:"createdAt"`
+}
+
+func newAuditEntryResponse(e *audit.Entry) auditEntryResponse {
+	resp := auditEntryResponse{
+		ID:         e.ID.String(),
+		EntityType: string(e.EntityType),
+		EntityID:   e.EntityID.String(),
+		Action:     string(e.Action),
+		Before:     e.Before,
+		After:      e.After,
+		RequestID:  e.RequestID,
+		Source:     string(e.Source),
+		CreatedAt:  e.CreatedAt,
+	}
+	// Изменения без автора выполнила система
+	if e.ActorID != uuid.Nil {
+		resp.ActorID = e.ActorID.String()
+	}
+	return resp
+}
+
+// parseOptionalUUID разбирает необязательный параметр с UUID
+func parseOptionalUUID(q url.Values, key string) (uuid.UUID, error) {
+	value := q.Get(key)
+	if value == "" {
+		return uuid.Nil, nil
+	}
+	return uuid.Parse(value)
+}
+
+// List обрабатывает получение записей журнала, новые первыми.
+// Поддерживает фильтры entity_type, entity_id, actor_id, action, source,
+// request_id, from и to (время изменения) и пагинацию offset и limit.
+func (h *AuditHandler) List(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	entityID, err := parseOptionalUUID(q, "entity_id")
+	if err != nil {
+		apperror.WriteInvalidRequest(w, r, "invalid_entity_id")
+		return
+	}
+
+	actorID, err := parseOptionalUUID(q, "actor_id")
+	if err != nil {
+		apperror.WriteInvalidRequest(w, r, "invalid_actor_id")
+		return
+	}
+
+	createdAt, err := parseListDateRange(q)
+	if err != nil {
+		apperror.WriteInvalidRequest(w, r, "invalid_date")
+		return
+	}
+
+	entries, err := h.service.List(r.Context(), audit.Filter{
+		EntityType: audit.EntityType(q.Get("entity_type")),
+		EntityID:   entityID,
+		ActorID:    actorID,
+		Action:     audit.Action(q.Get("action")),
+		Source:     audit.Source(q.Get("source")),
+		RequestID:  q.Get("request_id"),
+		CreatedAt:  createdAt,
+		Page:       parseListPage(q),
+	})
+	if err != nil {
+		apperror.WriteHTTP(w, r, err, "audit_list_failed")
+		return
+	}
+
+	resp := make([]auditEntryResponse, len(entries))
+	for i, e := range entries {
+		resp[i] = newAuditEntryResponse(e)
+	}
+
+	httpresponse.JSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,143 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/audit"
+	"github.com/avito/pvz/internal/domain/listing"
+	"github.com/avito/pvz/internal/handler/apperror"
+	"github.com/avito/pvz/pkg/httpresponse"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockAuditService struct {
+	mock.Mock
+}
+
+func (m *mockAuditService) List(ctx context.Context, filter audit.Filter) ([]*audit.Entry, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*audit.Entry), args.Error(1)
+}
+
+func TestAuditHandler_List(t *testing.T) {
+	entityID, actorID := uuid.New(), uuid.New()
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	entry := &audit.Entry{
+		ID:         uuid.New(),
+		ActorID:    actorID,
+		EntityType: audit.EntityPVZ,
+		EntityID:   entityID,
+		Action:     audit.ActionUpdate,
+		Before:     json.RawMessage(`{"city":"Москва"}`),
+		After:      json.RawMessage(`{"city":"Казань"}`),
+		RequestID:  "req-1",
+		Source:     audit.SourceHTTP,
+		CreatedAt:  from,
+	}
+
+	tests := []struct {
+		name           string
+		query          string
+		setupMock      func(*mockAuditService)
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			name:  "выборка по фильтру",
+			query: "?entity_type=pvz&entity_id=" + entityID.String() + "&actor_id=" + actorID.String() + "&action=update&source=http&request_id=req-1&from=2026-01-01T00:00:00Z&offset=10&limit=5",
+			setupMock: func(m *mockAuditService) {
+				m.On("List", mock.Anything, audit.Filter{
+					EntityType: audit.EntityPVZ,
+					EntityID:   entityID,
+					ActorID:    actorID,
+					Action:     audit.ActionUpdate,
+					Source:     audit.SourceHTTP,
+					RequestID:  "req-1",
+					CreatedAt:  listing.DateRange{From: from},
+					Page:       listing.Page{Offset: 10, Limit: 5},
+				}).Return([]*audit.Entry{entry}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "неверный ID сущности",
+			query:          "?entity_id=bad",
+			setupMock:      func(m *mockAuditService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   string(apperror.CodeInvalidRequest),
+		},
+		{
+			name:           "неверный ID автора",
+			query:          "?actor_id=bad",
+			setupMock:      func(m *mockAuditService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   string(apperror.CodeInvalidRequest),
+		},
+		{
+			name:           "неверная дата",
+			query:          "?from=yesterday",
+			setupMock:      func(m *mockAuditService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   string(apperror.CodeInvalidRequest),
+		},
+		{
+			name:  "неизвестное действие",
+			query: "?action=purge",
+			setupMock: func(m *mockAuditService) {
+				m.On("List", mock.Anything, mock.Anything).Return(nil, listing.ErrInvalidFilter)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   string(apperror.CodeInvalidFilter),
+		},
+		{
+			name:  "ошибка сервиса",
+			query: "",
+			setupMock: func(m *mockAuditService) {
+				m.On("List", mock.Anything, mock.Anything).Return(nil, errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedCode:   string(apperror.CodeInternal),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(mockAuditService)
+			tt.setupMock(service)
+			handler := NewAuditHandler(service)
+
+			req := httptest.NewRequest(http.MethodGet, "/audit"+tt.query, nil)
+			rec := httptest.NewRecorder()
+
+			handler.List(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedCode != "" {
+				var problem httpresponse.ProblemDetails
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&problem))
+				assert.Equal(t, tt.expectedCode, problem.Code)
+				return
+			}
+
+			var resp []auditEntryResponse
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+			require.Len(t, resp, 1)
+			assert.Equal(t, actorID.String(), resp[0].ActorID)
+			assert.JSONEq(t, `{"city":"Москва"}`, string(resp[0].Before))
+			assert.JSONEq(t, `{"city":"Казань"}`, string(resp[0].After))
+			service.AssertExpectations(t)
+		})
+	}
+}
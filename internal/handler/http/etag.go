@@ -0,0 +1,48 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// errInvalidIfMatch возвращается, когда заголовок If-Match не содержит версию сущности
+var errInvalidIfMatch = errors.New("invalid If-Match header")
+
+// formatETag возвращает ETag для версии сущности
+func formatETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// setETag добавляет в ответ ETag с версией сущности
+func setETag(w http.ResponseWriter, version int64) {
+	if version > 0 {
+		w.Header().Set("ETag", formatETag(version))
+	}
+}
+
+// parseIfMatch возвращает версию сущности из заголовка If-Match.
+// Без заголовка или со значением "*" возвращает 0, то есть изменение без проверки версии.
+func parseIfMatch(r *http.Request) (int64, error) {
+	value := strings.TrimSpace(r.Header.Get("If-Match"))
+	if value == "" || value == "*" {
+		return 0, nil
+	}
+
+	// If-Match требует строгого сравнения, поэтому слабые ETag и списки не принимаются
+	unquoted, ok := strings.CutPrefix(value, `"`)
+	if !ok {
+		return 0, errInvalidIfMatch
+	}
+	unquoted, ok = strings.CutSuffix(unquoted, `"`)
+	if !ok {
+		return 0, errInvalidIfMatch
+	}
+
+	version, err := strconv.ParseInt(unquoted, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, errInvalidIfMatch
+	}
+	return version, nil
+}
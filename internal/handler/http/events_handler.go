@@ -0,0 +1,197 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/rbac"
+	"github.com/avito/pvz/internal/handler/apperror"
+	"github.com/avito/pvz/internal/handler/http/middleware"
+	"github.com/avito/pvz/internal/service/events"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// eventsHeartbeat интервал комментариев, которые не дают прокси закрыть простаивающее соединение
+const eventsHeartbeat = 15 * time.Second
+
+// EventStream определяет подписку на ленту событий
+type EventStream interface {
+	Subscribe(lastID uint64) (backlog []events.Record, updates <-chan events.Record, cancel func())
+}
+
+// PVZAccess определяет ПВЗ, события которых доступны сотруднику
+type PVZAccess interface {
+	EmployeePVZs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error)
+}
+
+// PVZAccessFunc позволяет использовать функцию как PVZAccess
+type PVZAccessFunc func(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error)
+
+// EmployeePVZs вызывает f(ctx, userID)
+func (f PVZAccessFunc) EmployeePVZs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	return f(ctx, userID)
+}
+
+// EventsHandler отдает ленту событий в формате Server-Sent Events
+type EventsHandler struct {
+	stream    EventStream
+	access    PVZAccess
+	heartbeat time.Duration
+}
+
+// NewEventsHandler создает новый экземпляр EventsHandler
+func NewEventsHandler(stream EventStream, access PVZAccess) *EventsHandler {
+	return &EventsHandler{
+		stream:    stream,
+		access:    access,
+		heartbeat: eventsHeartbeat,
+	}
+}
+
+// RegisterRoutes регистрирует маршрут ленты событий
+func (h *EventsHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.AuthMiddleware)
+
+		r.Get("/events", h.Stream)
+	})
+}
+
+// Stream отправляет события приемок и товаров, пока клиент не отключится.
+// Параметр pvzId ограничивает ленту одним ПВЗ. Администратор видит события
+// всех ПВЗ, сотрудник — только закрепленных за ним. Заголовок Last-Event-ID
+// возобновляет ленту с события, следующего за указанным, если оно еще в буфере.
+func (h *EventsHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	pvzFilter, err := h.pvzFilter(r)
+	if err != nil {
+		apperror.WriteHTTP(w, r, err, "events_subscribe_failed")
+		return
+	}
+
+	var lastID uint64
+	if header := r.Header.Get("Last-Event-ID"); header != "" {
+		lastID, err = strconv.ParseUint(header, 10, 64)
+		if err != nil {
+			apperror.WriteInvalidRequest(w, r, "invalid_last_event_id")
+			return
+		}
+	}
+
+	rc := http.NewResponseController(w)
+	// Лента открыта дольше общего таймаута записи сервера
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	backlog, updates, cancel := h.stream.Subscribe(lastID)
+	defer cancel()
+
+	for _, rec := range backlog {
+		if err := writeEvent(w, rec, pvzFilter); err != nil {
+			return
+		}
+	}
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	ticker := time.NewTicker(h.heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case rec, ok := <-updates:
+			if !ok {
+				// Клиент не успевал читать события; он переподключится с Last-Event-ID
+				return
+			}
+			if err := writeEvent(w, rec, pvzFilter); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// pvzFilter возвращает ПВЗ, события которых нужно отправлять; nil — все ПВЗ
+func (h *EventsHandler) pvzFilter(r *http.Request) (map[uuid.UUID]bool, error) {
+	var requested uuid.UUID
+	if s := r.URL.Query().Get("pvzId"); s != "" {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			return nil, apperror.InvalidRequest("invalid_pvz_id")
+		}
+		requested = id
+	}
+
+	if _, err := middleware.GetUserRole(r.Context()); err != nil {
+		return nil, apperror.ErrUnauthorized
+	}
+
+	permissions := rbac.FromContext(r.Context())
+	switch {
+	case permissions.Has(rbac.EventsReadAll):
+		if requested == uuid.Nil {
+			return nil, nil
+		}
+		return map[uuid.UUID]bool{requested: true}, nil
+	case permissions.Has(rbac.EventsReadAssigned):
+	default:
+		return nil, apperror.ErrAccessDenied
+	}
+
+	userIDStr, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		return nil, apperror.ErrUnauthorized
+	}
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return nil, apperror.ErrUnauthorized
+	}
+
+	assigned, err := h.access.EmployeePVZs(r.Context(), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := make(map[uuid.UUID]bool, len(assigned))
+	for _, id := range assigned {
+		if requested == uuid.Nil || id == requested {
+			filter[id] = true
+		}
+	}
+	if len(filter) == 0 {
+		return nil, apperror.ErrAccessDenied
+	}
+	return filter, nil
+}
+
+// writeEvent отправляет событие, если оно проходит фильтр по ПВЗ
+func writeEvent(w http.ResponseWriter, rec events.Record, pvzFilter map[uuid.UUID]bool) error {
+	if pvzFilter != nil && !pvzFilter[rec.Event.PVZID] {
+		return nil
+	}
+
+	data, err := json.Marshal(rec.Event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", rec.ID, rec.Event.Type, data)
+	return err
+}
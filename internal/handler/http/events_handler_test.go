@@ -0,0 +1,217 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/event"
+	"github.com/avito/pvz/internal/domain/rbac"
+	domainUser "github.com/avito/pvz/internal/domain/user"
+	"github.com/avito/pvz/internal/handler/apperror"
+	"github.com/avito/pvz/internal/handler/http/middleware"
+	"github.com/avito/pvz/internal/service/events"
+	"github.com/avito/pvz/pkg/httpresponse"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testAuthorizer проверяет права по политике по умолчанию
+var testAuthorizer = rbac.MustNewAuthorizer(rbac.DefaultPolicy)
+
+// fakeEventStream отдает заданный буфер и канал новых событий
+type fakeEventStream struct {
+	backlog   []events.Record
+	updates   chan events.Record
+	lastID    uint64
+	cancelled bool
+}
+
+func (s *fakeEventStream) Subscribe(lastID uint64) ([]events.Record, <-chan events.Record, func()) {
+	s.lastID = lastID
+	return s.backlog, s.updates, func() { s.cancelled = true }
+}
+
+// eventIDs возвращает номера событий из тела ответа SSE
+func eventIDs(body string) []string {
+	var ids []string
+	for _, line := range strings.Split(body, "\n") {
+		if id, ok := strings.CutPrefix(line, "id: "); ok {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func TestEventsHandler_Stream(t *testing.T) {
+	pvzA := uuid.New()
+	pvzB := uuid.New()
+	employeeID := uuid.New()
+
+	backlog := []events.Record{
+		{ID: 1, Event: event.Event{Type: event.TypeReceptionOpened, PVZID: pvzA}},
+		{ID: 2, Event: event.Event{Type: event.TypeProductAdded, PVZID: pvzB}},
+		{ID: 3, Event: event.Event{Type: event.TypeReceptionClosed, PVZID: pvzA}},
+	}
+
+	tests := []struct {
+		name           string
+		role           domainUser.Role
+		query          string
+		lastEventID    string
+		assigned       []uuid.UUID
+		accessErr      error
+		expectedStatus int
+		expectedCode   string
+		expectedIDs    []string
+		expectedLastID uint64
+	}{
+		{
+			name:           "администратор видит все ПВЗ",
+			role:           domainUser.RoleAdmin,
+			expectedStatus: http.StatusOK,
+			expectedIDs:    []string{"1", "2", "3"},
+		},
+		{
+			name:           "администратор с фильтром по ПВЗ",
+			role:           domainUser.RoleAdmin,
+			query:          "?pvzId=" + pvzB.String(),
+			expectedStatus: http.StatusOK,
+			expectedIDs:    []string{"2"},
+		},
+		{
+			name:           "сотрудник видит только закрепленные ПВЗ",
+			role:           domainUser.RoleEmployee,
+			assigned:       []uuid.UUID{pvzA},
+			expectedStatus: http.StatusOK,
+			expectedIDs:    []string{"1", "3"},
+		},
+		{
+			name:           "возобновление по Last-Event-ID",
+			role:           domainUser.RoleAdmin,
+			lastEventID:    "2",
+			expectedStatus: http.StatusOK,
+			expectedIDs:    []string{"1", "2", "3"},
+			expectedLastID: 2,
+		},
+		{
+			name:           "сотрудник запрашивает чужой ПВЗ",
+			role:           domainUser.RoleEmployee,
+			query:          "?pvzId=" + pvzB.String(),
+			assigned:       []uuid.UUID{pvzA},
+			expectedStatus: http.StatusForbidden,
+			expectedCode:   string(apperror.CodeAccessDenied),
+		},
+		{
+			name:           "сотрудник без закрепленных ПВЗ",
+			role:           domainUser.RoleEmployee,
+			expectedStatus: http.StatusForbidden,
+			expectedCode:   string(apperror.CodeAccessDenied),
+		},
+		{
+			name:           "роль без доступа к ленте",
+			role:           domainUser.RoleUser,
+			expectedStatus: http.StatusForbidden,
+			expectedCode:   string(apperror.CodeAccessDenied),
+		},
+		{
+			name:           "ошибка получения закрепленных ПВЗ",
+			role:           domainUser.RoleEmployee,
+			accessErr:      errors.New("db error"),
+			expectedStatus: http.StatusInternalServerError,
+			expectedCode:   string(apperror.CodeInternal),
+		},
+		{
+			name:           "неверный ID ПВЗ",
+			role:           domainUser.RoleAdmin,
+			query:          "?pvzId=bad",
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   string(apperror.CodeInvalidRequest),
+		},
+		{
+			name:           "неверный Last-Event-ID",
+			role:           domainUser.RoleAdmin,
+			lastEventID:    "abc",
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   string(apperror.CodeInvalidRequest),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Закрытый канал завершает ленту сразу после буфера
+			stream := &fakeEventStream{backlog: backlog, updates: make(chan events.Record)}
+			close(stream.updates)
+
+			access := PVZAccessFunc(func(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+				assert.Equal(t, employeeID, userID)
+				return tt.assigned, tt.accessErr
+			})
+			handler := NewEventsHandler(stream, access)
+
+			req := httptest.NewRequest(http.MethodGet, "/events"+tt.query, nil)
+			if tt.lastEventID != "" {
+				req.Header.Set("Last-Event-ID", tt.lastEventID)
+			}
+			ctx := context.WithValue(req.Context(), middleware.UserIDKey, employeeID.String())
+			ctx = context.WithValue(ctx, middleware.UserRoleKey, tt.role)
+			ctx = rbac.WithPermissions(ctx, testAuthorizer.Permissions(tt.role))
+			rec := httptest.NewRecorder()
+
+			handler.Stream(rec, req.WithContext(ctx))
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedCode != "" {
+				var problem httpresponse.ProblemDetails
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&problem))
+				assert.Equal(t, tt.expectedCode, problem.Code)
+				return
+			}
+
+			assert.Equal(t, "text/event-stream", rec.Header().Get("Content-Type"))
+			assert.Equal(t, tt.expectedIDs, eventIDs(rec.Body.String()))
+			assert.Equal(t, tt.expectedLastID, stream.lastID)
+			assert.True(t, stream.cancelled)
+		})
+	}
+}
+
+func TestEventsHandler_StreamLive(t *testing.T) {
+	pvzID := uuid.New()
+	receptionID := uuid.New()
+	stream := &fakeEventStream{updates: make(chan events.Record)}
+	handler := NewEventsHandler(stream, nil)
+	handler.heartbeat = time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ctx = context.WithValue(ctx, middleware.UserRoleKey, domainUser.RoleAdmin)
+	ctx = rbac.WithPermissions(ctx, testAuthorizer.Permissions(domainUser.RoleAdmin))
+	req := httptest.NewRequest(http.MethodGet, "/events", nil).WithContext(ctx)
+	rec := httptest.NewRecorder()
+
+	done := make(chan struct{})
+	go func() {
+		handler.Stream(rec, req)
+		close(done)
+	}()
+
+	stream.updates <- events.Record{ID: 7, Event: event.Event{
+		Type:        event.TypeReceptionOpened,
+		PVZID:       pvzID,
+		ReceptionID: receptionID,
+	}}
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	<-done
+
+	body := rec.Body.String()
+	assert.Contains(t, body, "id: 7\nevent: reception.opened\ndata: {")
+	assert.Contains(t, body, `"receptionId":"`+receptionID.String()+`"`)
+	assert.Contains(t, body, ": ping\n\n")
+}
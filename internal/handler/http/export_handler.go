@@ -0,0 +1,120 @@
+package http
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/rbac"
+	"github.com/avito/pvz/internal/handler/apperror"
+	"github.com/avito/pvz/internal/handler/http/middleware"
+	exportService "github.com/avito/pvz/internal/service/export"
+	"github.com/go-chi/chi/v5"
+)
+
+// ExportServiceInterface определяет интерфейс для сервиса выгрузки
+type ExportServiceInterface interface {
+	Export(ctx context.Context, startDate, endDate time.Time, format exportService.Format, w io.Writer) error
+}
+
+// ExportHandler обрабатывает HTTP-запросы выгрузки данных
+type ExportHandler struct {
+	service ExportServiceInterface
+}
+
+// NewExportHandler создает новый экземпляр ExportHandler
+func NewExportHandler(service ExportServiceInterface) *ExportHandler {
+	return &ExportHandler{
+		service: service,
+	}
+}
+
+// RegisterRoutes регистрирует маршруты выгрузки
+func (h *ExportHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.AuthMiddleware)
+		r.Use(middleware.RequirePermission(rbac.ReceptionExport))
+
+		r.Get("/export/receptions", h.ExportReceptions)
+	})
+}
+
+// ExportReceptions выгружает приемки с товарами за период, как GET /pvz, в CSV или NDJSON.
+// Формат задается параметром format, иначе выбирается по заголовку Accept, по умолчанию CSV.
+func (h *ExportHandler) ExportReceptions(w http.ResponseWriter, r *http.Request) {
+	startDate, err := time.Parse(time.RFC3339, r.URL.Query().Get("start_date"))
+	if err != nil {
+		apperror.WriteInvalidRequest(w, r, "invalid_start_date")
+		return
+	}
+
+	endDate, err := time.Parse(time.RFC3339, r.URL.Query().Get("end_date"))
+	if err != nil {
+		apperror.WriteInvalidRequest(w, r, "invalid_end_date")
+		return
+	}
+
+	format, err := negotiateExportFormat(r)
+	if err != nil {
+		apperror.WriteHTTP(w, r, err, "invalid_export_format")
+		return
+	}
+
+	// Выгрузка может идти дольше общего таймаута записи сервера
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+	sw := &startedWriter{ResponseWriter: w, format: format}
+	if err := h.service.Export(r.Context(), startDate, endDate, format, sw); err != nil {
+		if !sw.started {
+			apperror.WriteHTTP(w, r, err, "export_failed")
+			return
+		}
+
+		// Часть выгрузки уже отправлена, поэтому обрываем соединение,
+		// чтобы клиент не принял неполный файл за целый
+		log.Printf("export receptions aborted: %v", err)
+		panic(http.ErrAbortHandler)
+	}
+
+	if !sw.started {
+		sw.writeHeader()
+	}
+}
+
+// negotiateExportFormat выбирает формат выгрузки по параметру format или заголовку Accept
+func negotiateExportFormat(r *http.Request) (exportService.Format, error) {
+	if f := r.URL.Query().Get("format"); f != "" {
+		return exportService.ParseFormat(f)
+	}
+
+	accept := r.Header.Get("Accept")
+	if strings.Contains(accept, "application/x-ndjson") || strings.Contains(accept, "application/ndjson") {
+		return exportService.FormatNDJSON, nil
+	}
+	return exportService.FormatCSV, nil
+}
+
+// startedWriter отправляет заголовки выгрузки перед первой записью
+// и запоминает, что ответ уже начат
+type startedWriter struct {
+	http.ResponseWriter
+	format  exportService.Format
+	started bool
+}
+
+func (w *startedWriter) writeHeader() {
+	w.started = true
+	w.Header().Set("Content-Type", w.format.ContentType())
+	w.Header().Set("Content-Disposition", `attachment; filename="receptions.`+string(w.format)+`"`)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (w *startedWriter) Write(p []byte) (int, error) {
+	if !w.started {
+		w.writeHeader()
+	}
+	return w.ResponseWriter.Write(p)
+}
@@ -0,0 +1,157 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	exportService "github.com/avito/pvz/internal/service/export"
+	"github.com/avito/pvz/pkg/httpresponse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockExportService struct {
+	mock.Mock
+}
+
+func (m *mockExportService) Export(ctx context.Context, startDate, endDate time.Time, format exportService.Format, w io.Writer) error {
+	args := m.Called(ctx, startDate, endDate, format)
+	if body := args.String(1); body != "" {
+		io.WriteString(w, body)
+	}
+	return args.Error(0)
+}
+
+func TestExportHandler_ExportReceptions(t *testing.T) {
+	startDate := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	period := "?start_date=2024-01-01T00:00:00Z&end_date=2024-02-01T00:00:00Z"
+
+	tests := []struct {
+		name                string
+		query               string
+		accept              string
+		setupMocks          func(*mockExportService)
+		expectedStatus      int
+		expectedContentType string
+		expectedBody        string
+		expectedError       string
+	}{
+		{
+			name:  "CSV по умолчанию",
+			query: period,
+			setupMocks: func(m *mockExportService) {
+				m.On("Export", mock.Anything, startDate, endDate, exportService.FormatCSV).Return(nil, "pvz_id\n")
+			},
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "text/csv; charset=utf-8",
+			expectedBody:        "pvz_id\n",
+		},
+		{
+			name:   "NDJSON по заголовку Accept",
+			query:  period,
+			accept: "application/x-ndjson",
+			setupMocks: func(m *mockExportService) {
+				m.On("Export", mock.Anything, startDate, endDate, exportService.FormatNDJSON).Return(nil, "{}\n")
+			},
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/x-ndjson",
+			expectedBody:        "{}\n",
+		},
+		{
+			name:   "параметр format важнее Accept",
+			query:  period + "&format=csv",
+			accept: "application/x-ndjson",
+			setupMocks: func(m *mockExportService) {
+				m.On("Export", mock.Anything, startDate, endDate, exportService.FormatCSV).Return(nil, "")
+			},
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "text/csv; charset=utf-8",
+		},
+		{
+			name:           "неподдерживаемый формат",
+			query:          period + "&format=xml",
+			setupMocks:     func(m *mockExportService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "неподдерживаемый формат выгрузки",
+		},
+		{
+			name:           "неверная дата начала",
+			query:          "?start_date=invalid&end_date=2024-02-01T00:00:00Z",
+			setupMocks:     func(m *mockExportService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "неверный формат даты начала",
+		},
+		{
+			name:  "неверный диапазон дат",
+			query: period,
+			setupMocks: func(m *mockExportService) {
+				m.On("Export", mock.Anything, startDate, endDate, exportService.FormatCSV).Return(exportService.ErrInvalidDateRange, "")
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "неверный диапазон дат",
+		},
+		{
+			name:  "ошибка до начала выгрузки",
+			query: period,
+			setupMocks: func(m *mockExportService) {
+				m.On("Export", mock.Anything, startDate, endDate, exportService.FormatCSV).Return(errors.New("database error"), "")
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedError:  "ошибка при выгрузке приемок",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(mockExportService)
+			tt.setupMocks(service)
+
+			handler := NewExportHandler(service)
+
+			req := httptest.NewRequest(http.MethodGet, "/export/receptions"+tt.query, nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			rec := httptest.NewRecorder()
+
+			handler.ExportReceptions(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedContentType != "" {
+				assert.Equal(t, tt.expectedContentType, rec.Header().Get("Content-Type"))
+				assert.Equal(t, tt.expectedBody, rec.Body.String())
+			}
+			if tt.expectedError != "" {
+				var response httpresponse.ProblemDetails
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+				assert.Equal(t, tt.expectedError, response.Detail)
+			}
+
+			service.AssertExpectations(t)
+		})
+	}
+}
+
+func TestExportHandler_ExportReceptions_AbortsStartedResponse(t *testing.T) {
+	service := new(mockExportService)
+	service.On("Export", mock.Anything, mock.Anything, mock.Anything, exportService.FormatCSV).
+		Return(errors.New("connection lost"), "pvz_id\n")
+
+	handler := NewExportHandler(service)
+
+	req := httptest.NewRequest(http.MethodGet, "/export/receptions?start_date=2024-01-01T00:00:00Z&end_date=2024-02-01T00:00:00Z", nil)
+	rec := httptest.NewRecorder()
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		handler.ExportReceptions(rec, req)
+	})
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
@@ -5,13 +5,20 @@ package http
 
 import (
 	"encoding/json"
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 
+	"github.com/avito/pvz/internal/domain/lockout"
 	domainUser "github.com/avito/pvz/internal/domain/user"
+	"github.com/avito/pvz/internal/handler/apperror"
 	"github.com/avito/pvz/internal/handler/http/middleware"
 	serviceProduct "github.com/avito/pvz/internal/service/product"
 	servicePVZ "github.com/avito/pvz/internal/service/pvz"
 	serviceReception "github.com/avito/pvz/internal/service/reception"
+	serviceSession "github.com/avito/pvz/internal/service/session"
 	serviceUser "github.com/avito/pvz/internal/service/user"
 	"github.com/avito/pvz/pkg/httpresponse"
 	"github.com/go-chi/chi/v5"
@@ -23,15 +30,17 @@ type Handler struct {
 	receptionService *serviceReception.Service
 	productService   *serviceProduct.Service
 	userService      *serviceUser.Service
+	sessionService   *serviceSession.Service
 }
 
 // New создает новый экземпляр Handler
-func New(pvzService *servicePVZ.Service, receptionService *serviceReception.Service, productService *serviceProduct.Service, userService *serviceUser.Service) *Handler {
+func New(pvzService *servicePVZ.Service, receptionService *serviceReception.Service, productService *serviceProduct.Service, userService *serviceUser.Service, sessionService *serviceSession.Service) *Handler {
 	return &Handler{
 		pvzService:       pvzService,
 		receptionService: receptionService,
 		productService:   productService,
 		userService:      userService,
+		sessionService:   sessionService,
 	}
 }
 
@@ -57,31 +66,20 @@ func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		httpresponse.Error(w, http.StatusBadRequest, "неверный формат запроса")
+		apperror.WriteInvalidRequest(w, r, "invalid_body")
 		return
 	}
 
 	user, err := h.userService.Register(r.Context(), req.Email, req.Password, req.Role)
 	if err != nil {
-		switch err {
-		case serviceUser.ErrInvalidEmail:
-			httpresponse.Error(w, http.StatusBadRequest, "неверный формат email")
-		case serviceUser.ErrInvalidPassword:
-			httpresponse.Error(w, http.StatusBadRequest, "неверный формат пароля")
-		case serviceUser.ErrInvalidRole:
-			httpresponse.Error(w, http.StatusBadRequest, "неверная роль")
-		case serviceUser.ErrUserAlreadyExists:
-			httpresponse.Error(w, http.StatusConflict, "пользователь уже существует")
-		default:
-			httpresponse.Error(w, http.StatusInternalServerError, "ошибка при регистрации")
-		}
+		apperror.WriteHTTP(w, r, err, "register_failed")
 		return
 	}
 
 	httpresponse.JSON(w, http.StatusCreated, user)
 }
 
-// Login обрабатывает авторизацию пользователя
+// Login обрабатывает авторизацию пользователя и выдает токены новой сессии
 func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 	var req struct {
 		Email    string `json:"email"`
@@ -89,26 +87,44 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		httpresponse.Error(w, http.StatusBadRequest, "неверный формат запроса")
+		apperror.WriteInvalidRequest(w, r, "invalid_body")
 		return
 	}
 
-	token, err := h.userService.LoginUser(r.Context(), req.Email, req.Password)
+	user, err := h.userService.Login(r.Context(), req.Email, req.Password)
 	if err != nil {
-		switch err {
-		case serviceUser.ErrUserNotFound:
-			httpresponse.Error(w, http.StatusUnauthorized, "неверный email или пароль")
-		case serviceUser.ErrInvalidPassword:
-			httpresponse.Error(w, http.StatusUnauthorized, "неверный email или пароль")
-		default:
-			httpresponse.Error(w, http.StatusInternalServerError, "ошибка при авторизации")
-		}
+		writeLoginError(w, r, err)
 		return
 	}
 
-	httpresponse.JSON(w, http.StatusOK, map[string]string{
-		"token": token,
-	})
+	// Каждый вход открывает отдельную сессию со своим токеном обновления
+	tokens, err := h.sessionService.Start(r.Context(), user)
+	if err != nil {
+		apperror.WriteHTTP(w, r, err, "login_failed")
+		return
+	}
+
+	httpresponse.NoStore(w)
+	httpresponse.JSON(w, http.StatusOK, newTokenResponse(tokens))
+}
+
+// writeLoginError отвечает на неудачный вход. Неизвестный email и неверный
+// пароль дают одинаковый ответ, а при блокировке входа клиент получает
+// Retry-After до ее окончания.
+func writeLoginError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, serviceUser.ErrUserNotFound), errors.Is(err, serviceUser.ErrInvalidPassword):
+		// Не раскрываем, что именно неверно: email или пароль
+		err = ErrInvalidCredentials
+	}
+
+	var locked *lockout.LockedError
+	if errors.As(err, &locked) {
+		retryAfter := math.Ceil(time.Until(locked.Until).Seconds())
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Max(retryAfter, 1))))
+	}
+
+	apperror.WriteHTTP(w, r, err, "login_failed")
 }
 
 // Handlers содержит все HTTP-хендлеры
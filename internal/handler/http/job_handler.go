@@ -0,0 +1,217 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/job"
+	"github.com/avito/pvz/internal/handler/apperror"
+	"github.com/avito/pvz/internal/handler/http/middleware"
+	"github.com/avito/pvz/pkg/httpresponse"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// JobServiceInterface определяет интерфейс для сервиса фоновых задач
+type JobServiceInterface interface {
+	Submit(ctx context.Context, jobType job.Type, params []byte, userID uuid.UUID) (*job.Job, error)
+	Get(ctx context.Context, id, userID uuid.UUID) (*job.Job, error)
+	GetResult(ctx context.Context, id, userID uuid.UUID) (*job.Job, error)
+	Cancel(ctx context.Context, id, userID uuid.UUID) (*job.Job, error)
+}
+
+// JobHandler обрабатывает HTTP-запросы фоновых задач
+type JobHandler struct {
+	service JobServiceInterface
+}
+
+// NewJobHandler создает новый экземпляр JobHandler
+func NewJobHandler(service JobServiceInterface) *JobHandler {
+	return &JobHandler{
+		service: service,
+	}
+}
+
+// RegisterRoutes регистрирует маршруты фоновых задач. Права на тип задачи
+// проверяет сервис, задачи доступны своим владельцам и администраторам.
+func (h *JobHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.AuthMiddleware)
+
+		r.Post("/jobs", h.Submit)
+		r.Get("/jobs/{jobId}", h.Get)
+		r.Get("/jobs/{jobId}/result", h.Result)
+		r.Post("/jobs/{jobId}/cancel", h.Cancel)
+	})
+}
+
+// jobLinks ссылки на операции с задачей
+type jobLinks struct {
+	Self   string `json:"self"`
+	Result string `json:"result,omitempty"`
+	Cancel string `json:"cancel,omitempty"`
+}
+
+// jobResponse задача в ответе API. Результат скачивается по ссылке links.result.
+type jobResponse struct {
+	ID         string     `json:"id"`
+	Type       job.Type   `json:"type"`
+	Status     job.Status `json:"status"`
+	Progress   int        `json:"progress"`
+	Error      string     `json:"error,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	StartedAt  *time.Time `json:"startedAt,omitempty"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+	Links      jobLinks   `json:"links"`
+}
+
+// newJobResponse собирает ответ, строя ссылки от базового пути задачи
+func newJobResponse(j *job.Job, self string) jobResponse {
+	resp := jobResponse{
+		ID:         j.ID.String(),
+		Type:       j.Type,
+		Status:     j.Status,
+		Progress:   j.Progress,
+		Error:      j.Error,
+		CreatedAt:  j.CreatedAt,
+		StartedAt:  j.StartedAt,
+		FinishedAt: j.FinishedAt,
+		Links:      jobLinks{Self: self},
+	}
+	if j.Status == job.StatusSucceeded {
+		resp.Links.Result = self + "/result"
+	}
+	if !j.Status.Finished() {
+		resp.Links.Cancel = self + "/cancel"
+	}
+	return resp
+}
+
+// jobPath возвращает путь задачи с учетом префикса версии API запроса
+func jobPath(r *http.Request, id uuid.UUID) string {
+	prefix := r.URL.Path[:strings.LastIndex(r.URL.Path, "/jobs")]
+	return prefix + "/jobs/" + id.String()
+}
+
+// caller возвращает ID пользователя или сервисного аккаунта запроса.
+// Разрешения вызывающего сервисы берут из контекста запроса.
+func caller(r *http.Request) (uuid.UUID, error) {
+	userIDStr, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		return uuid.Nil, apperror.ErrUnauthorized
+	}
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return uuid.Nil, apperror.ErrUnauthorized
+	}
+	return userID, nil
+}
+
+// Submit обрабатывает постановку задачи в очередь.
+// Отвечает 202 со ссылкой на задачу в заголовке Location.
+func (h *JobHandler) Submit(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Type   job.Type        `json:"type"`
+		Params json.RawMessage `json:"params"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apperror.WriteInvalidRequest(w, r, "invalid_body")
+		return
+	}
+
+	userID, err := caller(r)
+	if err != nil {
+		apperror.WriteHTTP(w, r, err, "")
+		return
+	}
+
+	j, err := h.service.Submit(r.Context(), req.Type, req.Params, userID)
+	if err != nil {
+		apperror.WriteHTTP(w, r, err, "job_submit_failed")
+		return
+	}
+
+	self := jobPath(r, j.ID)
+	w.Header().Set("Location", self)
+	httpresponse.JSON(w, http.StatusAccepted, newJobResponse(j, self))
+}
+
+// Get обрабатывает получение статуса и прогресса задачи
+func (h *JobHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "jobId"))
+	if err != nil {
+		apperror.WriteInvalidRequest(w, r, "invalid_job_id")
+		return
+	}
+
+	userID, err := caller(r)
+	if err != nil {
+		apperror.WriteHTTP(w, r, err, "")
+		return
+	}
+
+	j, err := h.service.Get(r.Context(), id, userID)
+	if err != nil {
+		apperror.WriteHTTP(w, r, err, "job_get_failed")
+		return
+	}
+
+	httpresponse.JSON(w, http.StatusOK, newJobResponse(j, jobPath(r, j.ID)))
+}
+
+// Result обрабатывает скачивание результата успешно выполненной задачи
+func (h *JobHandler) Result(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "jobId"))
+	if err != nil {
+		apperror.WriteInvalidRequest(w, r, "invalid_job_id")
+		return
+	}
+
+	userID, err := caller(r)
+	if err != nil {
+		apperror.WriteHTTP(w, r, err, "")
+		return
+	}
+
+	j, err := h.service.GetResult(r.Context(), id, userID)
+	if err != nil {
+		apperror.WriteHTTP(w, r, err, "job_get_failed")
+		return
+	}
+
+	contentType := j.ResultContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	w.Write(j.Result)
+}
+
+// Cancel обрабатывает отмену задачи. Задача в очереди отменяется сразу,
+// выполняющаяся — после того как обработчик заметит запрос отмены.
+func (h *JobHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "jobId"))
+	if err != nil {
+		apperror.WriteInvalidRequest(w, r, "invalid_job_id")
+		return
+	}
+
+	userID, err := caller(r)
+	if err != nil {
+		apperror.WriteHTTP(w, r, err, "")
+		return
+	}
+
+	j, err := h.service.Cancel(r.Context(), id, userID)
+	if err != nil {
+		apperror.WriteHTTP(w, r, err, "job_cancel_failed")
+		return
+	}
+
+	httpresponse.JSON(w, http.StatusAccepted, newJobResponse(j, jobPath(r, j.ID)))
+}
@@ -0,0 +1,296 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/job"
+	"github.com/avito/pvz/internal/handler/apperror"
+	"github.com/avito/pvz/internal/handler/http/middleware"
+	jobService "github.com/avito/pvz/internal/service/job"
+	"github.com/avito/pvz/pkg/httpresponse"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockJobService struct {
+	mock.Mock
+}
+
+func (m *mockJobService) Submit(ctx context.Context, jobType job.Type, params []byte, userID uuid.UUID) (*job.Job, error) {
+	args := m.Called(ctx, jobType, params, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*job.Job), args.Error(1)
+}
+
+func (m *mockJobService) Get(ctx context.Context, id, userID uuid.UUID) (*job.Job, error) {
+	args := m.Called(ctx, id, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*job.Job), args.Error(1)
+}
+
+func (m *mockJobService) GetResult(ctx context.Context, id, userID uuid.UUID) (*job.Job, error) {
+	args := m.Called(ctx, id, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*job.Job), args.Error(1)
+}
+
+func (m *mockJobService) Cancel(ctx context.Context, id, userID uuid.UUID) (*job.Job, error) {
+	args := m.Called(ctx, id, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*job.Job), args.Error(1)
+}
+
+// withCaller добавляет в запрос ID пользователя
+func withCaller(req *http.Request, userID uuid.UUID) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, userID.String()))
+}
+
+func TestJobHandler_Submit(t *testing.T) {
+	userID := uuid.New()
+	j := &job.Job{ID: uuid.New(), Type: "export.receptions", UserID: userID, Status: job.StatusQueued, CreatedAt: time.Now()}
+	params := []byte(`{"format":"csv"}`)
+
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(*mockJobService)
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			name: "задача поставлена в очередь",
+			body: `{"type":"export.receptions","params":{"format":"csv"}}`,
+			setupMock: func(m *mockJobService) {
+				m.On("Submit", mock.Anything, j.Type, params, userID).Return(j, nil)
+			},
+			expectedStatus: http.StatusAccepted,
+		},
+		{
+			name:           "неверное тело запроса",
+			body:           `{`,
+			setupMock:      func(m *mockJobService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   string(apperror.CodeInvalidRequest),
+		},
+		{
+			name: "превышен лимит задач",
+			body: `{"type":"export.receptions","params":{"format":"csv"}}`,
+			setupMock: func(m *mockJobService) {
+				m.On("Submit", mock.Anything, j.Type, params, userID).Return(nil, jobService.ErrTooManyJobs)
+			},
+			expectedStatus: http.StatusTooManyRequests,
+			expectedCode:   string(apperror.CodeTooManyJobs),
+		},
+		{
+			name: "неизвестный тип задачи",
+			body: `{"type":"unknown","params":{"format":"csv"}}`,
+			setupMock: func(m *mockJobService) {
+				m.On("Submit", mock.Anything, job.Type("unknown"), params, userID).Return(nil, jobService.ErrUnknownType)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   string(apperror.CodeUnknownJobType),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(mockJobService)
+			tt.setupMock(service)
+			handler := NewJobHandler(service)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v2/jobs", strings.NewReader(tt.body))
+			req = withCaller(req, userID)
+			rec := httptest.NewRecorder()
+
+			handler.Submit(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedCode != "" {
+				var problem httpresponse.ProblemDetails
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&problem))
+				assert.Equal(t, tt.expectedCode, problem.Code)
+			} else {
+				self := "/api/v2/jobs/" + j.ID.String()
+				assert.Equal(t, self, rec.Header().Get("Location"))
+
+				var resp jobResponse
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+				assert.Equal(t, j.ID.String(), resp.ID)
+				assert.Equal(t, jobLinks{Self: self, Cancel: self + "/cancel"}, resp.Links)
+			}
+			service.AssertExpectations(t)
+		})
+	}
+}
+
+func TestJobHandler_Get(t *testing.T) {
+	userID := uuid.New()
+	succeeded := &job.Job{ID: uuid.New(), UserID: userID, Status: job.StatusSucceeded, Progress: 100}
+
+	tests := []struct {
+		name           string
+		jobID          string
+		setupMock      func(*mockJobService)
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			name:  "выполненная задача со ссылкой на результат",
+			jobID: succeeded.ID.String(),
+			setupMock: func(m *mockJobService) {
+				m.On("Get", mock.Anything, succeeded.ID, userID).Return(succeeded, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "неверный ID",
+			jobID:          "not-a-uuid",
+			setupMock:      func(m *mockJobService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   string(apperror.CodeInvalidRequest),
+		},
+		{
+			name:  "задача не найдена",
+			jobID: succeeded.ID.String(),
+			setupMock: func(m *mockJobService) {
+				m.On("Get", mock.Anything, succeeded.ID, userID).Return(nil, job.ErrNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   string(apperror.CodeJobNotFound),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(mockJobService)
+			tt.setupMock(service)
+			handler := NewJobHandler(service)
+
+			req := httptest.NewRequest(http.MethodGet, "/jobs/"+tt.jobID, nil)
+			req = withRouteParam(withCaller(req, userID), "jobId", tt.jobID)
+			rec := httptest.NewRecorder()
+
+			handler.Get(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedCode != "" {
+				var problem httpresponse.ProblemDetails
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&problem))
+				assert.Equal(t, tt.expectedCode, problem.Code)
+			} else {
+				var resp jobResponse
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+				assert.Equal(t, job.StatusSucceeded, resp.Status)
+				assert.Equal(t, 100, resp.Progress)
+				assert.Equal(t, "/jobs/"+tt.jobID+"/result", resp.Links.Result)
+				assert.Empty(t, resp.Links.Cancel)
+			}
+			service.AssertExpectations(t)
+		})
+	}
+}
+
+func TestJobHandler_Result(t *testing.T) {
+	userID := uuid.New()
+	j := &job.Job{ID: uuid.New(), UserID: userID, Status: job.StatusSucceeded, Result: []byte("id\n"), ResultContentType: "text/csv"}
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+	}{
+		{name: "результат отдан", expectedStatus: http.StatusOK},
+		{name: "результат не готов", err: jobService.ErrResultNotReady, expectedStatus: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(mockJobService)
+			if tt.err != nil {
+				service.On("GetResult", mock.Anything, j.ID, userID).Return(nil, tt.err)
+			} else {
+				service.On("GetResult", mock.Anything, j.ID, userID).Return(j, nil)
+			}
+			handler := NewJobHandler(service)
+
+			req := httptest.NewRequest(http.MethodGet, "/jobs/"+j.ID.String()+"/result", nil)
+			req = withRouteParam(withCaller(req, userID), "jobId", j.ID.String())
+			rec := httptest.NewRecorder()
+
+			handler.Result(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.err == nil {
+				assert.Equal(t, "text/csv", rec.Header().Get("Content-Type"))
+				assert.Equal(t, "id\n", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestJobHandler_Cancel(t *testing.T) {
+	userID := uuid.New()
+	jobID := uuid.New()
+
+	tests := []struct {
+		name           string
+		setupMock      func(*mockJobService)
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			name: "отмена запрошена",
+			setupMock: func(m *mockJobService) {
+				m.On("Cancel", mock.Anything, jobID, userID).
+					Return(&job.Job{ID: jobID, Status: job.StatusCancelled}, nil)
+			},
+			expectedStatus: http.StatusAccepted,
+		},
+		{
+			name: "задача уже завершена",
+			setupMock: func(m *mockJobService) {
+				m.On("Cancel", mock.Anything, jobID, userID).Return(nil, job.ErrNotCancellable)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedCode:   string(apperror.CodeJobNotCancellable),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(mockJobService)
+			tt.setupMock(service)
+			handler := NewJobHandler(service)
+
+			req := httptest.NewRequest(http.MethodPost, "/jobs/"+jobID.String()+"/cancel", nil)
+			req = withRouteParam(withCaller(req, userID), "jobId", jobID.String())
+			rec := httptest.NewRecorder()
+
+			handler.Cancel(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedCode != "" {
+				var problem httpresponse.ProblemDetails
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&problem))
+				assert.Equal(t, tt.expectedCode, problem.Code)
+			}
+			service.AssertExpectations(t)
+		})
+	}
+}
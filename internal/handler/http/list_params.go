@@ -0,0 +1,46 @@
+package http
+
+import (
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/listing"
+)
+
+// parseListPage разбирает параметры offset и limit.
+// Нечисловые значения, как и раньше, заменяются значениями по умолчанию.
+func parseListPage(q url.Values) listing.Page {
+	offset, _ := strconv.Atoi(q.Get("offset"))
+	limit, _ := strconv.Atoi(q.Get("limit"))
+
+	if limit <= 0 {
+		limit = listing.DefaultLimit
+	}
+
+	return listing.Page{Offset: offset, Limit: limit}
+}
+
+// parseListDateRange разбирает границы диапазона from и to в формате RFC3339.
+// Отсутствующая граница не ограничивает выборку.
+func parseListDateRange(q url.Values) (listing.DateRange, error) {
+	var r listing.DateRange
+
+	if from := q.Get("from"); from != "" {
+		t, err := time.Parse(time.RFC3339, from)
+		if err != nil {
+			return r, err
+		}
+		r.From = t
+	}
+
+	if to := q.Get("to"); to != "" {
+		t, err := time.Parse(time.RFC3339, to)
+		if err != nil {
+			return r, err
+		}
+		r.To = t
+	}
+
+	return r, nil
+}
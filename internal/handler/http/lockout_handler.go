@@ -0,0 +1,74 @@
+package http
+
+import (
+	"context"
+	"net"
+	"net/http"
+
+	"github.com/avito/pvz/internal/domain/rbac"
+	"github.com/avito/pvz/internal/handler/apperror"
+	"github.com/avito/pvz/internal/handler/http/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// LockoutServiceInterface определяет интерфейс для сервиса защиты входа
+type LockoutServiceInterface interface {
+	Unlock(ctx context.Context, userID uuid.UUID) error
+	UnlockIP(ctx context.Context, ip string) error
+}
+
+// LockoutHandler обрабатывает HTTP-запросы снятия блокировки входа
+type LockoutHandler struct {
+	service LockoutServiceInterface
+}
+
+// NewLockoutHandler создает новый экземпляр LockoutHandler
+func NewLockoutHandler(service LockoutServiceInterface) *LockoutHandler {
+	return &LockoutHandler{
+		service: service,
+	}
+}
+
+// RegisterRoutes регистрирует маршруты снятия блокировки, доступные только модераторам
+func (h *LockoutHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.AuthMiddleware)
+		r.Use(middleware.RequirePermission(rbac.UserManage))
+
+		r.Post("/user/{id}/unlock", h.Unlock)
+		r.Post("/lockouts/ip/{ip}/unlock", h.UnlockIP)
+	})
+}
+
+// Unlock снимает блокировку входа с учетной записи пользователя
+func (h *LockoutHandler) Unlock(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		apperror.WriteInvalidRequest(w, r, "invalid_user_id")
+		return
+	}
+
+	if err := h.service.Unlock(r.Context(), userID); err != nil {
+		apperror.WriteHTTP(w, r, err, "login_unlock_failed")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// UnlockIP снимает блокировку входа с IP-адреса
+func (h *LockoutHandler) UnlockIP(w http.ResponseWriter, r *http.Request) {
+	ip := net.ParseIP(chi.URLParam(r, "ip"))
+	if ip == nil {
+		apperror.WriteInvalidRequest(w, r, "invalid_ip")
+		return
+	}
+
+	if err := h.service.UnlockIP(r.Context(), ip.String()); err != nil {
+		apperror.WriteHTTP(w, r, err, "login_unlock_failed")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,83 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/avito/pvz/internal/domain/user"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockLockoutService struct {
+	mock.Mock
+}
+
+func (m *mockLockoutService) Unlock(ctx context.Context, userID uuid.UUID) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *mockLockoutService) UnlockIP(ctx context.Context, ip string) error {
+	args := m.Called(ctx, ip)
+	return args.Error(0)
+}
+
+func TestLockoutHandler_Unlock(t *testing.T) {
+	id := uuid.New()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+	}{
+		{name: "успешное снятие блокировки", err: nil, expectedStatus: http.StatusNoContent},
+		{name: "пользователь не найден", err: user.ErrNotFound, expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(mockLockoutService)
+			service.On("Unlock", mock.Anything, id).Return(tt.err)
+			handler := NewLockoutHandler(service)
+
+			req := withRouteParam(httptest.NewRequest(http.MethodPost, "/user/"+id.String()+"/unlock", nil), "id", id.String())
+			rec := httptest.NewRecorder()
+
+			handler.Unlock(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			service.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLockoutHandler_UnlockIP(t *testing.T) {
+	t.Run("успешное снятие блокировки", func(t *testing.T) {
+		service := new(mockLockoutService)
+		service.On("UnlockIP", mock.Anything, "10.0.0.1").Return(nil)
+		handler := NewLockoutHandler(service)
+
+		req := withRouteParam(httptest.NewRequest(http.MethodPost, "/lockouts/ip/10.0.0.1/unlock", nil), "ip", "10.0.0.1")
+		rec := httptest.NewRecorder()
+
+		handler.UnlockIP(rec, req)
+
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		service.AssertExpectations(t)
+	})
+
+	t.Run("неверный IP-адрес", func(t *testing.T) {
+		handler := NewLockoutHandler(new(mockLockoutService))
+
+		req := withRouteParam(httptest.NewRequest(http.MethodPost, "/lockouts/ip/bad/unlock", nil), "ip", "bad")
+		rec := httptest.NewRecorder()
+
+		handler.UnlockIP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
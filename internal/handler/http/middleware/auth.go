@@ -2,67 +2,137 @@ package middleware
 
 import (
 	"context"
-	"errors"
+	"fmt"
 	"net/http"
 	"strings"
 
+	"github.com/avito/pvz/internal/domain/apikey"
+	"github.com/avito/pvz/internal/domain/rbac"
 	"github.com/avito/pvz/internal/domain/user"
+	"github.com/avito/pvz/internal/handler/apperror"
 	"github.com/avito/pvz/pkg/auth"
+	"github.com/google/uuid"
 )
 
+// Ошибки авторизации сводятся к ошибкам apperror, чтобы клиент получал стабильный код
 var (
-	ErrNoAuthHeader      = errors.New("no authorization header")
-	ErrInvalidAuthHeader = errors.New("invalid authorization header")
-	ErrInvalidToken      = errors.New("invalid token")
-	ErrAccessDenied      = errors.New("access denied")
+	ErrNoAuthHeader      = fmt.Errorf("%w: no authorization header", apperror.ErrUnauthorized)
+	ErrInvalidAuthHeader = fmt.Errorf("%w: invalid authorization header", apperror.ErrInvalidToken)
+	ErrInvalidToken      = apperror.ErrInvalidToken
+	ErrAccessDenied      = apperror.ErrAccessDenied
 )
 
 type contextKey string
 
 const (
-	UserIDKey   contextKey = "user_id"
-	UserRoleKey contextKey = "user_role"
+	UserIDKey    contextKey = "user_id"
+	UserRoleKey  contextKey = "user_role"
+	SessionIDKey contextKey = "session_id"
 )
 
-// AuthMiddleware проверяет JWT токен и добавляет информацию о пользователе в контекст
+// authErrorKey ошибка проверки токена, сохраненная Authenticate для AuthMiddleware
+const authErrorKey contextKey = "auth_error"
+
+// TokenValidator проверяет токены доступа
+type TokenValidator interface {
+	ValidateToken(token string) (*auth.Claims, error)
+}
+
+// APIKeyValidator проверяет ключи API сервисных аккаунтов
+type APIKeyValidator interface {
+	ValidateAPIKey(ctx context.Context, key string) (*apikey.Principal, error)
+}
+
+// Authenticate проверяет токен из заголовка Authorization и добавляет информацию
+// о пользователе в контекст: основное имя роли и ее разрешения по политике authz.
+// Вместо токена доступа можно предъявить ключ API сервисного аккаунта, если
+// задан apiKeys. Запрос без действительного токена проходит дальше анонимным:
+// маршруты, требующие авторизации, отклоняет AuthMiddleware.
+func Authenticate(tokens TokenValidator, apiKeys APIKeyValidator, authz *rbac.Authorizer) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Получаем токен из заголовка
+			authHeader := r.Header.Get("Authorization")
+			if authHeader == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// Проверяем формат заголовка
+			parts := strings.Split(authHeader, " ")
+			if len(parts) != 2 || parts[0] != "Bearer" {
+				next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), authErrorKey, ErrInvalidAuthHeader)))
+				return
+			}
+
+			// Ключ API отличается от токена доступа префиксом
+			if apiKeys != nil && apikey.IsKey(parts[1]) {
+				principal, err := apiKeys.ValidateAPIKey(r.Context(), parts[1])
+				if err != nil {
+					next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), authErrorKey, ErrInvalidToken)))
+					return
+				}
+				next.ServeHTTP(w, r.WithContext(withServiceAccount(r.Context(), principal)))
+				return
+			}
+
+			// Проверяем токен
+			claims, err := tokens.ValidateToken(parts[1])
+			if err != nil {
+				next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), authErrorKey, ErrInvalidToken)))
+				return
+			}
+
+			// Добавляем информацию в контекст. Токены с псевдонимом роли
+			// получают основную роль и ее разрешения. Сервисы получают
+			// вызывающего через pkg/auth, как и в gRPC API.
+			role := authz.Role(claims.Role)
+			ctx := context.WithValue(r.Context(), UserIDKey, claims.UserID.String())
+			ctx = context.WithValue(ctx, UserRoleKey, role)
+			ctx = context.WithValue(ctx, SessionIDKey, claims.SessionID)
+			ctx = auth.WithUserID(ctx, claims.UserID)
+			ctx = auth.WithUserRole(ctx, role)
+			ctx = rbac.WithPermissions(ctx, authz.Permissions(claims.Role))
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// withServiceAccount добавляет в контекст сервисный аккаунт ключа API. Он
+// вызывает API с ролью user.RoleService и разрешениями из областей ключа,
+// сессии у него нет.
+func withServiceAccount(ctx context.Context, principal *apikey.Principal) context.Context {
+	ctx = context.WithValue(ctx, UserIDKey, principal.ServiceAccountID.String())
+	ctx = context.WithValue(ctx, UserRoleKey, user.RoleService)
+	ctx = auth.WithUserID(ctx, principal.ServiceAccountID)
+	ctx = auth.WithUserRole(ctx, user.RoleService)
+	return rbac.WithPermissions(ctx, principal.Permissions)
+}
+
+// AuthMiddleware пропускает только запросы, для которых Authenticate
+// установил пользователя, и отвечает 401 с причиной отказа остальным
 func AuthMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Получаем токен из заголовка
-		authHeader := r.Header.Get("Authorization")
-		if authHeader == "" {
-			http.Error(w, ErrNoAuthHeader.Error(), http.StatusUnauthorized)
-			return
-		}
-
-		// Проверяем формат заголовка
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			http.Error(w, ErrInvalidAuthHeader.Error(), http.StatusUnauthorized)
+		if _, err := GetUserID(r.Context()); err == nil {
+			next.ServeHTTP(w, r)
 			return
 		}
 
-		// Проверяем токен
-		claims, err := auth.ValidateToken(parts[1])
-		if err != nil {
-			http.Error(w, ErrInvalidToken.Error(), http.StatusUnauthorized)
+		if err, ok := r.Context().Value(authErrorKey).(error); ok {
+			apperror.WriteHTTP(w, r, err, "")
 			return
 		}
-
-		// Добавляем информацию в контекст
-		ctx := context.WithValue(r.Context(), UserIDKey, claims.UserID.String())
-		ctx = context.WithValue(ctx, UserRoleKey, claims.Role)
-
-		next.ServeHTTP(w, r.WithContext(ctx))
+		apperror.WriteHTTP(w, r, ErrNoAuthHeader, "")
 	})
 }
 
-// RequireRole проверяет, что у пользователя есть необходимая роль
-func RequireRole(role user.Role) func(http.Handler) http.Handler {
+// RequirePermission проверяет, что у пользователя есть разрешение
+func RequirePermission(permission rbac.Permission) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			userRole, ok := r.Context().Value(UserRoleKey).(user.Role)
-			if !ok || userRole != role {
-				http.Error(w, ErrAccessDenied.Error(), http.StatusForbidden)
+			if err := rbac.Check(r.Context(), permission); err != nil {
+				apperror.WriteHTTP(w, r, ErrAccessDenied, "")
 				return
 			}
 
@@ -88,3 +158,9 @@ func GetUserRole(ctx context.Context) (user.Role, error) {
 	}
 	return userRole, nil
 }
+
+// GetSessionID получает ID сессии токена из контекста. uuid.Nil, если токен выпущен без сессии.
+func GetSessionID(ctx context.Context) uuid.UUID {
+	sessionID, _ := ctx.Value(SessionIDKey).(uuid.UUID)
+	return sessionID
+}
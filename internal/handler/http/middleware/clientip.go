@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/avito/pvz/pkg/auth"
+)
+
+// ClientIP сохраняет IP-адрес клиента в контексте для ограничения частоты
+// запросов и защиты входа. Адрес берется из соединения: если сервис стоит
+// за прокси, RemoteAddr должен подставлять middleware прокси.
+func ClientIP(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			host = r.RemoteAddr
+		}
+
+		next.ServeHTTP(w, r.WithContext(auth.WithClientIP(r.Context(), host)))
+	})
+}
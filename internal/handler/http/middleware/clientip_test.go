@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/avito/pvz/pkg/auth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientIP(t *testing.T) {
+	tests := []struct {
+		name       string
+		remoteAddr string
+		expected   string
+	}{
+		{name: "адрес с портом", remoteAddr: "10.0.0.1:5000", expected: "10.0.0.1"},
+		{name: "IPv6 с портом", remoteAddr: "[::1]:5000", expected: "::1"},
+		{name: "адрес без порта", remoteAddr: "10.0.0.2", expected: "10.0.0.2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ip string
+			handler := ClientIP(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ip, _ = auth.GetClientIP(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/pvz", nil)
+			req.RemoteAddr = tt.remoteAddr
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, tt.expected, ip)
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Deprecated помечает ответы маршрутов, которые планируется удалить:
+// заголовок Deprecation (RFC 9745) сообщает дату вывода из эксплуатации
+// в формате структурированного поля (@<Unix-время>), Sunset (RFC 8594) —
+// дату удаления, Link — версию API, которая их заменяет.
+func Deprecated(deprecation, sunset time.Time, successor string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Deprecation", "@"+strconv.FormatInt(deprecation.Unix(), 10))
+			if !sunset.IsZero() {
+				w.Header().Set("Sunset", sunset.UTC().Format(http.TimeFormat))
+			}
+			if successor != "" {
+				w.Header().Add("Link", "<"+successor+`>; rel="successor-version"`)
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeprecated(t *testing.T) {
+	deprecation := time.Date(2026, time.April, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		sunset         time.Time
+		successor      string
+		expectedSunset string
+		expectedLink   string
+	}{
+		{
+			name:           "с датой удаления и новой версией",
+			sunset:         time.Date(2027, time.April, 1, 0, 0, 0, 0, time.UTC),
+			successor:      "/api/v2",
+			expectedSunset: "Thu, 01 Apr 2027 00:00:00 GMT",
+			expectedLink:   `</api/v2>; rel="successor-version"`,
+		},
+		{
+			name: "без даты удаления",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			handler := Deprecated(deprecation, tt.sunset, tt.successor)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+			}))
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/pvz", nil))
+
+			assert.True(t, called)
+			assert.Equal(t, "@1775001600", rec.Header().Get("Deprecation"))
+			assert.Equal(t, tt.expectedSunset, rec.Header().Get("Sunset"))
+			assert.Equal(t, tt.expectedLink, rec.Header().Get("Link"))
+		})
+	}
+}
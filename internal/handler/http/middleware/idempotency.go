@@ -0,0 +1,285 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/idempotency"
+	"github.com/avito/pvz/internal/handler/apperror"
+)
+
+const (
+	// IdempotencyKeyHeader заголовок с ключом идемпотентности запроса
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader отмечает ответ, повторенный из сохраненного
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	// maxIdempotencyKeyLength максимальная длина ключа идемпотентности
+	maxIdempotencyKeyLength = 255
+	// maxIdempotentBodySize максимальный размер тела запроса с ключом идемпотентности
+	maxIdempotentBodySize = 10 << 20
+)
+
+// IdempotencyConfig настройки обработки ключей идемпотентности
+type IdempotencyConfig struct {
+	// TTL срок, в течение которого сохраненный ответ повторяется
+	TTL time.Duration
+	// LockTimeout время, после которого незавершенный запрос считается прерванным
+	LockTimeout time.Duration
+	// WaitTimeout время ожидания повтором завершения исходного запроса
+	WaitTimeout time.Duration
+	// PollInterval интервал проверки завершения исходного запроса
+	PollInterval time.Duration
+	// CleanupInterval период удаления записей старше TTL
+	CleanupInterval time.Duration
+}
+
+// DefaultIdempotencyConfig настройки ключей идемпотентности по умолчанию
+var DefaultIdempotencyConfig = IdempotencyConfig{
+	TTL:             24 * time.Hour,
+	LockTimeout:     time.Minute,
+	WaitTimeout:     5 * time.Second,
+	PollInterval:    100 * time.Millisecond,
+	CleanupInterval: time.Hour,
+}
+
+// Idempotency повторяет сохраненный ответ на POST-запросы с заголовком Idempotency-Key.
+// Ключи разделяются по пользователям, поэтому запросы без пользователя выполняются
+// без учета ключа. Первый ответ сохраняется, повтор с тем же телом
+// получает его без повторного выполнения, повтор с другим телом отклоняется с 422.
+// Если исходный запрос еще выполняется, повтор ждет его завершения, а затем получает 409.
+// Ответы с кодом 5xx не сохраняются, чтобы запрос можно было повторить.
+// Ответы с Cache-Control: no-store содержат токены или секреты и тоже не сохраняются:
+// повтор выполняет запрос заново, например проходит ротацию токена обновления.
+func Idempotency(repo idempotency.Repository, cfg IdempotencyConfig) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if r.Method != http.MethodPost || key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// Анонимные клиенты неотличимы друг от друга, и общий ключ отдал бы одному
+			// из них ответ другого
+			userID, err := GetUserID(r.Context())
+			if err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if len(key) > maxIdempotencyKeyLength {
+				apperror.WriteInvalidRequest(w, r, "idempotency_key_too_long")
+				return
+			}
+
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIdempotentBodySize))
+			if err != nil {
+				var maxBytesErr *http.MaxBytesError
+				if errors.As(err, &maxBytesErr) {
+					apperror.WriteHTTP(w, r, apperror.PayloadTooLarge("request_too_large"), "")
+					return
+				}
+				apperror.WriteInvalidRequest(w, r, "unreadable_body")
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			now := time.Now()
+			record := &idempotency.Record{
+				UserID:      userID,
+				Key:         key,
+				RequestHash: requestHash(r, body),
+				CreatedAt:   now,
+			}
+
+			existing, acquired, err := repo.Acquire(r.Context(), record, now.Add(-cfg.LockTimeout), now.Add(-cfg.TTL))
+			if err != nil {
+				apperror.WriteHTTP(w, r, err, "idempotency_check_failed")
+				return
+			}
+
+			if !acquired {
+				handleDuplicate(w, r, repo, cfg, record, existing)
+				return
+			}
+
+			rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+			defer func() {
+				if p := recover(); p != nil {
+					releaseIdempotencyKey(repo, record)
+					panic(p)
+				}
+			}()
+
+			next.ServeHTTP(rec, r)
+
+			if rec.status >= http.StatusInternalServerError || noStore(rec.Header()) {
+				releaseIdempotencyKey(repo, record)
+				return
+			}
+
+			completedAt := time.Now()
+			record.StatusCode = rec.status
+			record.Header = rec.Header().Clone()
+			record.Body = rec.body.Bytes()
+			record.CompletedAt = &completedAt
+
+			// Ответ уже отправлен клиенту, поэтому сохраняем его даже после отмены запроса
+			if err := repo.Complete(context.WithoutCancel(r.Context()), record); err != nil {
+				log.Printf("failed to save idempotent response for key %q: %v", key, err)
+			}
+		})
+	}
+}
+
+// IdempotencyCleanup возвращает фоновую задачу, которая до отмены ctx
+// периодически удаляет записи ключей идемпотентности старше TTL
+func IdempotencyCleanup(repo idempotency.Repository, cfg IdempotencyConfig) func(ctx context.Context) {
+	return func(ctx context.Context) {
+		ticker := time.NewTicker(cfg.CleanupInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := repo.DeleteExpired(ctx, time.Now().Add(-cfg.TTL)); err != nil && ctx.Err() == nil {
+					log.Printf("Failed to delete expired idempotency keys: %v", err)
+				}
+			}
+		}
+	}
+}
+
+// handleDuplicate отвечает на повтор запроса с уже занятым ключом
+func handleDuplicate(w http.ResponseWriter, r *http.Request, repo idempotency.Repository, cfg IdempotencyConfig, record, existing *idempotency.Record) {
+	if existing.RequestHash != record.RequestHash {
+		apperror.WriteHTTP(w, r, apperror.ErrIdempotencyKeyReused, "")
+		return
+	}
+
+	if !existing.Completed() {
+		completed, err := waitCompleted(r.Context(), repo, cfg, existing)
+		if err != nil && err != idempotency.ErrNotFound {
+			apperror.WriteHTTP(w, r, err, "idempotency_check_failed")
+			return
+		}
+		if completed == nil {
+			apperror.WriteHTTP(w, r, apperror.ErrIdempotencyInProgress, "")
+			return
+		}
+		existing = completed
+	}
+
+	replay(w, existing)
+}
+
+// waitCompleted ждет завершения исходного запроса не дольше WaitTimeout.
+// Возвращает nil, если запрос не завершился за это время.
+func waitCompleted(ctx context.Context, repo idempotency.Repository, cfg IdempotencyConfig, existing *idempotency.Record) (*idempotency.Record, error) {
+	if cfg.WaitTimeout <= 0 || cfg.PollInterval <= 0 {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.WaitTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, nil
+		case <-ticker.C:
+		}
+
+		current, err := repo.Get(ctx, existing.UserID, existing.Key)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, nil
+			}
+			return nil, err
+		}
+		if current.Completed() {
+			return current, nil
+		}
+	}
+}
+
+// replay отправляет сохраненный ответ
+func replay(w http.ResponseWriter, record *idempotency.Record) {
+	for name, values := range record.Header {
+		w.Header()[name] = values
+	}
+	w.Header().Set(IdempotentReplayedHeader, "true")
+	w.WriteHeader(record.StatusCode)
+	w.Write(record.Body)
+}
+
+// releaseIdempotencyKey освобождает ключ после неудачного выполнения запроса
+func releaseIdempotencyKey(repo idempotency.Repository, record *idempotency.Record) {
+	if err := repo.Release(context.Background(), record); err != nil {
+		log.Printf("failed to release idempotency key %q: %v", record.Key, err)
+	}
+}
+
+// noStore сообщает, что ответ нельзя сохранять
+func noStore(header http.Header) bool {
+	for _, directive := range strings.Split(header.Get("Cache-Control"), ",") {
+		if strings.EqualFold(strings.TrimSpace(directive), "no-store") {
+			return true
+		}
+	}
+	return false
+}
+
+// requestHash вычисляет отпечаток метода, пути и тела запроса
+func requestHash(r *http.Request, body []byte) string {
+	h := sha256.New()
+	io.WriteString(h, r.Method)
+	h.Write([]byte{0})
+	io.WriteString(h, r.URL.RequestURI())
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// responseRecorder передает ответ клиенту и запоминает его для сохранения
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	body        bytes.Buffer
+	wroteHeader bool
+}
+
+func (w *responseRecorder) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseRecorder) Write(p []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	w.body.Write(p)
+	return w.ResponseWriter.Write(p)
+}
+
+// Unwrap позволяет http.ResponseController добраться до исходного ResponseWriter
+func (w *responseRecorder) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,353 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/idempotency"
+	"github.com/avito/pvz/internal/domain/user"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockIdempotencyRepository мок для idempotency.Repository
+type MockIdempotencyRepository struct {
+	mock.Mock
+}
+
+func (m *MockIdempotencyRepository) Acquire(ctx context.Context, record *idempotency.Record, staleBefore, expiredBefore time.Time) (*idempotency.Record, bool, error) {
+	args := m.Called(ctx, record, staleBefore, expiredBefore)
+	if args.Get(0) == nil {
+		return nil, args.Bool(1), args.Error(2)
+	}
+	return args.Get(0).(*idempotency.Record), args.Bool(1), args.Error(2)
+}
+
+func (m *MockIdempotencyRepository) Get(ctx context.Context, userID, key string) (*idempotency.Record, error) {
+	args := m.Called(ctx, userID, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*idempotency.Record), args.Error(1)
+}
+
+func (m *MockIdempotencyRepository) Complete(ctx context.Context, record *idempotency.Record) error {
+	args := m.Called(ctx, record)
+	return args.Error(0)
+}
+
+func (m *MockIdempotencyRepository) Release(ctx context.Context, record *idempotency.Record) error {
+	args := m.Called(ctx, record)
+	return args.Error(0)
+}
+
+func (m *MockIdempotencyRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func TestIdempotency(t *testing.T) {
+	const (
+		key    = "3f1c2a9e-key"
+		path   = "/receptions"
+		body   = `{"pvzId":"1"}`
+		userID = "c54e392f-75b1-4e33-9858-e1810bd9549f"
+	)
+
+	hash := requestHash(httptest.NewRequest(http.MethodPost, path, nil), []byte(body))
+	completedAt := time.Now()
+	completed := &idempotency.Record{
+		UserID:      userID,
+		Key:         key,
+		RequestHash: hash,
+		StatusCode:  http.StatusCreated,
+		Header:      map[string][]string{"Content-Type": {"application/json"}},
+		Body:        []byte(`{"id":"first"}`),
+		CompletedAt: &completedAt,
+	}
+	inProgress := &idempotency.Record{UserID: userID, Key: key, RequestHash: hash}
+
+	cfg := IdempotencyConfig{
+		TTL:          time.Hour,
+		LockTimeout:  time.Minute,
+		WaitTimeout:  50 * time.Millisecond,
+		PollInterval: 10 * time.Millisecond,
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		key            string
+		body           string
+		handlerStatus  int
+		cacheControl   string
+		anonymous      bool
+		setupMocks     func(*MockIdempotencyRepository)
+		expectedStatus int
+		expectedBody   string
+		expectedCalls  int
+		expectedReplay bool
+	}{
+		{
+			name:           "запрос без ключа",
+			method:         http.MethodPost,
+			body:           body,
+			handlerStatus:  http.StatusCreated,
+			setupMocks:     func(m *MockIdempotencyRepository) {},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"id":"new"}`,
+			expectedCalls:  1,
+		},
+		{
+			name:           "анонимный запрос с ключом",
+			method:         http.MethodPost,
+			key:            key,
+			body:           body,
+			anonymous:      true,
+			handlerStatus:  http.StatusCreated,
+			setupMocks:     func(m *MockIdempotencyRepository) {},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"id":"new"}`,
+			expectedCalls:  1,
+		},
+		{
+			name:           "GET-запрос с ключом",
+			method:         http.MethodGet,
+			key:            key,
+			handlerStatus:  http.StatusOK,
+			setupMocks:     func(m *MockIdempotencyRepository) {},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"id":"new"}`,
+			expectedCalls:  1,
+		},
+		{
+			name:           "слишком длинный ключ",
+			method:         http.MethodPost,
+			key:            strings.Repeat("k", maxIdempotencyKeyLength+1),
+			body:           body,
+			setupMocks:     func(m *MockIdempotencyRepository) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"type":"about:blank","title":"Bad Request","status":400,"detail":"слишком длинный Idempotency-Key","code":"invalid_request"}`,
+		},
+		{
+			name:          "первый запрос сохраняет ответ",
+			method:        http.MethodPost,
+			key:           key,
+			body:          body,
+			handlerStatus: http.StatusCreated,
+			setupMocks: func(m *MockIdempotencyRepository) {
+				m.On("Acquire", mock.Anything, mock.MatchedBy(func(r *idempotency.Record) bool {
+					return r.Key == key && r.UserID == userID && r.RequestHash == hash
+				}), mock.Anything, mock.Anything).Return(nil, true, nil)
+				m.On("Complete", mock.Anything, mock.MatchedBy(func(r *idempotency.Record) bool {
+					return r.StatusCode == http.StatusCreated && string(r.Body) == `{"id":"new"}` &&
+						r.Header["Content-Type"][0] == "application/json" && r.Completed()
+				})).Return(nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"id":"new"}`,
+			expectedCalls:  1,
+		},
+		{
+			name:   "повтор получает сохраненный ответ",
+			method: http.MethodPost,
+			key:    key,
+			body:   body,
+			setupMocks: func(m *MockIdempotencyRepository) {
+				m.On("Acquire", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(completed, false, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"id":"first"}`,
+			expectedReplay: true,
+		},
+		{
+			name:   "повтор с другим телом",
+			method: http.MethodPost,
+			key:    key,
+			body:   `{"pvzId":"2"}`,
+			setupMocks: func(m *MockIdempotencyRepository) {
+				m.On("Acquire", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(completed, false, nil)
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"type":"about:blank","title":"Unprocessable Entity","status":422,"detail":"Idempotency-Key уже использован с другим запросом","code":"idempotency_key_reused"}`,
+		},
+		{
+			name:   "повтор дожидается исходного запроса",
+			method: http.MethodPost,
+			key:    key,
+			body:   body,
+			setupMocks: func(m *MockIdempotencyRepository) {
+				m.On("Acquire", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(inProgress, false, nil)
+				m.On("Get", mock.Anything, userID, key).Return(inProgress, nil).Once()
+				m.On("Get", mock.Anything, userID, key).Return(completed, nil).Once()
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"id":"first"}`,
+			expectedReplay: true,
+		},
+		{
+			name:   "исходный запрос еще выполняется",
+			method: http.MethodPost,
+			key:    key,
+			body:   body,
+			setupMocks: func(m *MockIdempotencyRepository) {
+				m.On("Acquire", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(inProgress, false, nil)
+				m.On("Get", mock.Anything, userID, key).Return(inProgress, nil)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"type":"about:blank","title":"Conflict","status":409,"detail":"запрос с этим Idempotency-Key еще выполняется","code":"idempotency_request_in_progress"}`,
+		},
+		{
+			name:          "ошибка сервера освобождает ключ",
+			method:        http.MethodPost,
+			key:           key,
+			body:          body,
+			handlerStatus: http.StatusInternalServerError,
+			setupMocks: func(m *MockIdempotencyRepository) {
+				m.On("Acquire", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, true, nil)
+				m.On("Release", mock.Anything, mock.MatchedBy(func(r *idempotency.Record) bool {
+					return r.UserID == userID && r.Key == key && !r.CreatedAt.IsZero()
+				})).Return(nil)
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"id":"new"}`,
+			expectedCalls:  1,
+		},
+		{
+			name:          "ответ с токенами не сохраняется",
+			method:        http.MethodPost,
+			key:           key,
+			body:          body,
+			handlerStatus: http.StatusOK,
+			cacheControl:  "no-store",
+			setupMocks: func(m *MockIdempotencyRepository) {
+				m.On("Acquire", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, true, nil)
+				m.On("Release", mock.Anything, mock.MatchedBy(func(r *idempotency.Record) bool {
+					return r.UserID == userID && r.Key == key && !r.CreatedAt.IsZero()
+				})).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"id":"new"}`,
+			expectedCalls:  1,
+		},
+		{
+			name:   "ошибка хранилища",
+			method: http.MethodPost,
+			key:    key,
+			body:   body,
+			setupMocks: func(m *MockIdempotencyRepository) {
+				m.On("Acquire", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, false, errors.New("database error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"ошибка при проверке Idempotency-Key","code":"internal_error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockIdempotencyRepository)
+			tt.setupMocks(repo)
+
+			calls := 0
+			handler := Idempotency(repo, cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				w.Header().Set("Content-Type", "application/json")
+				if tt.cacheControl != "" {
+					w.Header().Set("Cache-Control", tt.cacheControl)
+				}
+				w.WriteHeader(tt.handlerStatus)
+				w.Write([]byte(`{"id":"new"}`))
+			}))
+
+			req := httptest.NewRequest(tt.method, path, strings.NewReader(tt.body))
+			if !tt.anonymous {
+				req = req.WithContext(context.WithValue(req.Context(), UserIDKey, userID))
+			}
+			if tt.key != "" {
+				req.Header.Set(IdempotencyKeyHeader, tt.key)
+			}
+			rr := httptest.NewRecorder()
+
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			assert.JSONEq(t, tt.expectedBody, rr.Body.String())
+			assert.Equal(t, tt.expectedCalls, calls)
+			if tt.expectedReplay {
+				assert.Equal(t, "true", rr.Header().Get(IdempotentReplayedHeader))
+			} else {
+				assert.Empty(t, rr.Header().Get(IdempotentReplayedHeader))
+			}
+
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestIdempotency_ScopesKeysByUser(t *testing.T) {
+	userID := uuid.MustParse("c54e392f-75b1-4e33-9858-e1810bd9549f")
+	tokens := newTestTokens(t)
+	token, err := tokens.GenerateToken(userID, user.RoleEmployee)
+	require.NoError(t, err)
+
+	repo := new(MockIdempotencyRepository)
+	repo.On("Acquire", mock.Anything, mock.MatchedBy(func(r *idempotency.Record) bool {
+		return r.UserID == userID.String()
+	}), mock.Anything, mock.Anything).Return(nil, true, nil)
+	repo.On("Complete", mock.Anything, mock.Anything).Return(nil)
+
+	// Пользователя в контекст добавляет Authenticate, подключенный перед Idempotency
+	handler := Authenticate(tokens, nil, testAuthorizer)(Idempotency(repo, DefaultIdempotencyConfig)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := make([]byte, 4)
+		n, _ := r.Body.Read(body)
+		assert.Equal(t, "data", string(body[:n]))
+		w.WriteHeader(http.StatusCreated)
+	})))
+
+	req := httptest.NewRequest(http.MethodPost, "/pvz", strings.NewReader("data"))
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set(IdempotencyKeyHeader, "key")
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	repo.AssertExpectations(t)
+}
+
+func TestIdempotencyCleanup(t *testing.T) {
+	ttl := time.Hour
+	deleted := make(chan time.Time, 1)
+
+	repo := new(MockIdempotencyRepository)
+	repo.On("DeleteExpired", mock.Anything, mock.AnythingOfType("time.Time")).Run(func(args mock.Arguments) {
+		select {
+		case deleted <- args.Get(1).(time.Time):
+		default:
+		}
+	}).Return(int64(1), nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		IdempotencyCleanup(repo, IdempotencyConfig{TTL: ttl, CleanupInterval: 10 * time.Millisecond})(ctx)
+		close(done)
+	}()
+
+	select {
+	case before := <-deleted:
+		assert.WithinDuration(t, time.Now().Add(-ttl), before, time.Second)
+	case <-time.After(time.Second):
+		t.Fatal("записи ключей идемпотентности не удалены")
+	}
+
+	cancel()
+	<-done
+}
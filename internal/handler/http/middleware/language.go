@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/avito/pvz/internal/handler/i18n"
+)
+
+// Language выбирает язык сообщений по заголовку Accept-Language и сохраняет его в контексте.
+// Если клиент не указал поддерживаемый язык, используется русский.
+func Language(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lang := i18n.ParseAcceptLanguage(r.Header.Get("Accept-Language"), i18n.Russian)
+
+		w.Header().Set("Content-Language", string(lang))
+		w.Header().Add("Vary", "Accept-Language")
+
+		next.ServeHTTP(w, r.WithContext(i18n.WithLang(r.Context(), lang)))
+	})
+}
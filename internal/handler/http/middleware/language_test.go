@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/avito/pvz/internal/handler/i18n"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLanguage(t *testing.T) {
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		expected       i18n.Lang
+	}{
+		{name: "без заголовка", expected: i18n.Russian},
+		{name: "английский", acceptLanguage: "en-GB,en;q=0.9", expected: i18n.English},
+		{name: "неподдерживаемый язык", acceptLanguage: "de", expected: i18n.Russian},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got i18n.Lang
+			handler := Language(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = i18n.LangFromContext(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/pvz", nil)
+			if tt.acceptLanguage != "" {
+				req.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expected, got)
+			assert.Equal(t, string(tt.expected), rec.Header().Get("Content-Language"))
+			assert.Equal(t, "Accept-Language", rec.Header().Get("Vary"))
+		})
+	}
+}
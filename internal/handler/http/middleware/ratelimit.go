@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/avito/pvz/internal/domain/ratelimit"
+	"github.com/avito/pvz/internal/domain/user"
+	"github.com/avito/pvz/internal/handler/apperror"
+	"github.com/avito/pvz/internal/metrics"
+	"github.com/avito/pvz/pkg/auth"
+	"github.com/go-chi/chi/v5"
+)
+
+// unmatchedRoute маршрут запросов, для которых в маршрутизаторе нет обработчика
+const unmatchedRoute = "unmatched"
+
+// versionPrefixes префиксы версий API. Один маршрут разных версий
+// расходует общий лимит и совпадает с одними правилами политики.
+var versionPrefixes = []string{"/api/v1", "/api/v2"}
+
+// RateLimiter проверяет частоту запросов
+type RateLimiter interface {
+	Allow(ctx context.Context, route string, role user.Role, subject string) ratelimit.Decision
+}
+
+// RateLimit ограничивает частоту запросов по маршруту, роли и вызывающему.
+// Маршрут определяется шаблоном routes, например "POST /reception/{id}/products/import",
+// поэтому у всех приемок общий лимит. Вызывающий — пользователь из токена,
+// а для анонимных запросов — IP-адрес. Превысившему лимит отвечает 429
+// с заголовком Retry-After. Подключается после ClientIP и Authenticate.
+func RateLimit(limiter RateLimiter, routes chi.Routes) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route := r.Method + " " + routePattern(routes, r)
+			role, _ := GetUserRole(r.Context())
+
+			decision := limiter.Allow(r.Context(), route, role, rateLimitSubject(r))
+			if !decision.Allowed {
+				metrics.RateLimitRejected.WithLabelValues("http", route, string(role)).Inc()
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(decision.RetryAfter.Seconds()))))
+				apperror.WriteHTTP(w, r, apperror.ErrRateLimited, "")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// routePattern возвращает шаблон маршрута запроса без префикса версии API
+func routePattern(routes chi.Routes, r *http.Request) string {
+	pattern := routes.Find(chi.NewRouteContext(), r.Method, r.URL.Path)
+	if pattern == "" {
+		return unmatchedRoute
+	}
+	for _, prefix := range versionPrefixes {
+		if trimmed, ok := strings.CutPrefix(pattern, prefix); ok && strings.HasPrefix(trimmed, "/") {
+			return trimmed
+		}
+	}
+	return pattern
+}
+
+// rateLimitSubject возвращает вызывающего: пользователя из токена или IP-адрес
+func rateLimitSubject(r *http.Request) string {
+	if userID, err := GetUserID(r.Context()); err == nil {
+		return "user:" + userID
+	}
+	ip, _ := auth.GetClientIP(r.Context())
+	return "ip:" + ip
+}
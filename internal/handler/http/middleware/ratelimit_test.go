@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/ratelimit"
+	"github.com/avito/pvz/internal/domain/user"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingLimiter запоминает проверенные запросы и отвечает заданным решением
+type recordingLimiter struct {
+	decision ratelimit.Decision
+	routes   []string
+	roles    []user.Role
+	subjects []string
+}
+
+func (l *recordingLimiter) Allow(_ context.Context, route string, role user.Role, subject string) ratelimit.Decision {
+	l.routes = append(l.routes, route)
+	l.roles = append(l.roles, role)
+	l.subjects = append(l.subjects, subject)
+	return l.decision
+}
+
+// newRateLimitRouter создает маршрутизатор с версиями API, как в приложении
+func newRateLimitRouter(limiter RateLimiter) chi.Router {
+	ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }
+	routes := func(r chi.Router) {
+		r.Group(func(r chi.Router) {
+			r.Post("/reception/{id}/products/import", ok)
+		})
+	}
+
+	router := chi.NewRouter()
+	router.Use(ClientIP)
+	router.Use(RateLimit(limiter, router))
+	router.Route("/api/v1", routes)
+	router.Group(routes)
+	return router
+}
+
+func TestRateLimit(t *testing.T) {
+	t.Run("маршрут по шаблону без версии и IP анонимного вызывающего", func(t *testing.T) {
+		limiter := &recordingLimiter{decision: ratelimit.Decision{Allowed: true}}
+		router := newRateLimitRouter(limiter)
+
+		for _, path := range []string{"/api/v1/reception/1/products/import", "/reception/2/products/import", "/missing"} {
+			req := httptest.NewRequest(http.MethodPost, path, nil)
+			req.RemoteAddr = "10.0.0.1:5000"
+			router.ServeHTTP(httptest.NewRecorder(), req)
+		}
+
+		assert.Equal(t, []string{
+			"POST /reception/{id}/products/import",
+			"POST /reception/{id}/products/import",
+			"POST unmatched",
+		}, limiter.routes)
+		assert.Equal(t, []string{"ip:10.0.0.1", "ip:10.0.0.1", "ip:10.0.0.1"}, limiter.subjects)
+	})
+
+	t.Run("пользователь из токена", func(t *testing.T) {
+		limiter := &recordingLimiter{decision: ratelimit.Decision{Allowed: true}}
+		router := newRateLimitRouter(limiter)
+
+		req := httptest.NewRequest(http.MethodPost, "/reception/1/products/import", nil)
+		ctx := context.WithValue(req.Context(), UserIDKey, "user-1")
+		ctx = context.WithValue(ctx, UserRoleKey, user.RoleEmployee)
+		router.ServeHTTP(httptest.NewRecorder(), req.WithContext(ctx))
+
+		assert.Equal(t, []string{"user:user-1"}, limiter.subjects)
+		assert.Equal(t, []user.Role{user.RoleEmployee}, limiter.roles)
+	})
+
+	t.Run("превышение лимита", func(t *testing.T) {
+		limiter := &recordingLimiter{decision: ratelimit.Decision{RetryAfter: 1500 * time.Millisecond}}
+		router := newRateLimitRouter(limiter)
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/reception/1/products/import", nil))
+
+		assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+		assert.Equal(t, "2", rec.Header().Get("Retry-After"))
+		assert.Contains(t, rec.Body.String(), `"rate_limited"`)
+	})
+}
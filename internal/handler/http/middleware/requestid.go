@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/avito/pvz/internal/domain/audit"
+)
+
+// RequestIDHeader заголовок с ID запроса
+const RequestIDHeader = "X-Request-ID"
+
+// RequestID берет ID запроса из заголовка X-Request-ID или создает новый,
+// возвращает его в том же заголовке ответа и сохраняет в контексте вместе
+// с источником HTTP, чтобы записи журнала аудита можно было связать с запросом.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := audit.RequestIDOrNew(r.Header.Get(RequestIDHeader))
+		w.Header().Set(RequestIDHeader, id)
+
+		ctx := audit.WithOrigin(r.Context(), audit.Origin{Source: audit.SourceHTTP, RequestID: id})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
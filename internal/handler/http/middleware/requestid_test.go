@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/avito/pvz/internal/domain/audit"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestID(t *testing.T) {
+	t.Run("ID из заголовка", func(t *testing.T) {
+		var origin audit.Origin
+		handler := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin = audit.OriginFrom(r.Context())
+		}))
+
+		req := httptest.NewRequest(http.MethodGet, "/pvz", nil)
+		req.Header.Set(RequestIDHeader, "req-1")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, audit.Origin{Source: audit.SourceHTTP, RequestID: "req-1"}, origin)
+		assert.Equal(t, "req-1", rec.Header().Get(RequestIDHeader))
+	})
+
+	t.Run("новый ID без заголовка", func(t *testing.T) {
+		var origin audit.Origin
+		handler := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin = audit.OriginFrom(r.Context())
+		}))
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/pvz", nil))
+
+		_, err := uuid.Parse(origin.RequestID)
+		assert.NoError(t, err)
+		assert.Equal(t, audit.SourceHTTP, origin.Source)
+		assert.Equal(t, origin.RequestID, rec.Header().Get(RequestIDHeader))
+	})
+}
@@ -3,11 +3,13 @@ package http
 import (
 	"encoding/json"
 	"net/http"
-	"strconv"
 
+	"github.com/avito/pvz/internal/domain/listing"
 	"github.com/avito/pvz/internal/domain/product"
-	domainUser "github.com/avito/pvz/internal/domain/user"
+	"github.com/avito/pvz/internal/domain/rbac"
+	"github.com/avito/pvz/internal/handler/apperror"
 	"github.com/avito/pvz/internal/handler/http/middleware"
+	"github.com/avito/pvz/internal/handler/i18n"
 	productService "github.com/avito/pvz/internal/service/product"
 	"github.com/avito/pvz/pkg/httpresponse"
 	"github.com/go-chi/chi/v5"
@@ -30,16 +32,17 @@ func NewProductHandler(service *productService.Service) *ProductHandler {
 func (h *ProductHandler) RegisterRoutes(r chi.Router) {
 	r.Group(func(r chi.Router) {
 		r.Use(middleware.AuthMiddleware)
-		r.Use(middleware.RequireRole(domainUser.RoleEmployee))
 
-		r.Post("/product", h.Create)
-		r.Post("/product/batch", h.CreateBatch)
-		r.Delete("/product/last/{reception_id}", h.DeleteLast)
+		r.With(middleware.RequirePermission(rbac.ProductCreate)).Post("/product", h.Create)
+		r.With(middleware.RequirePermission(rbac.ProductCreate)).Post("/product/batch", h.CreateBatch)
+		r.With(middleware.RequirePermission(rbac.ProductDelete)).Delete("/product/last/{reception_id}", h.DeleteLast)
+		r.With(middleware.RequirePermission(rbac.ProductCreate)).Post("/reception/{id}/products/import", h.Import)
 	})
 
 	r.Group(func(r chi.Router) {
 		r.Use(middleware.AuthMiddleware)
 
+		r.Get("/product/types", h.ListTypes)
 		r.Get("/product/{id}", h.GetByID)
 		r.Get("/product/reception/{reception_id}", h.GetByReceptionID)
 		r.Get("/product", h.List)
@@ -54,28 +57,19 @@ func (h *ProductHandler) Create(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		httpresponse.Error(w, http.StatusBadRequest, "неверный формат запроса")
+		apperror.WriteInvalidRequest(w, r, "invalid_body")
 		return
 	}
 
 	receptionID, err := uuid.Parse(req.ReceptionID)
 	if err != nil {
-		httpresponse.Error(w, http.StatusBadRequest, "неверный формат ID приемки")
+		apperror.WriteInvalidRequest(w, r, "invalid_reception_id")
 		return
 	}
 
 	product, err := h.service.Create(r.Context(), receptionID, req.Type)
 	if err != nil {
-		switch err {
-		case productService.ErrReceptionNotFound:
-			httpresponse.Error(w, http.StatusNotFound, "приемка не найдена")
-		case productService.ErrReceptionAlreadyClose:
-			httpresponse.Error(w, http.StatusBadRequest, "приемка уже закрыта")
-		case productService.ErrInvalidProductType:
-			httpresponse.Error(w, http.StatusBadRequest, "неверный тип товара")
-		default:
-			httpresponse.Error(w, http.StatusInternalServerError, "ошибка при добавлении товара")
-		}
+		apperror.WriteHTTP(w, r, err, "product_create_failed")
 		return
 	}
 
@@ -90,27 +84,18 @@ func (h *ProductHandler) CreateBatch(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		httpresponse.Error(w, http.StatusBadRequest, "неверный формат запроса")
+		apperror.WriteInvalidRequest(w, r, "invalid_body")
 		return
 	}
 
 	receptionID, err := uuid.Parse(req.ReceptionID)
 	if err != nil {
-		httpresponse.Error(w, http.StatusBadRequest, "неверный формат ID приемки")
+		apperror.WriteInvalidRequest(w, r, "invalid_reception_id")
 		return
 	}
 
 	if err := h.service.CreateBatch(r.Context(), receptionID, req.Types); err != nil {
-		switch err {
-		case productService.ErrReceptionNotFound:
-			httpresponse.Error(w, http.StatusNotFound, "приемка не найдена")
-		case productService.ErrReceptionAlreadyClose:
-			httpresponse.Error(w, http.StatusBadRequest, "приемка уже закрыта")
-		case productService.ErrInvalidProductType:
-			httpresponse.Error(w, http.StatusBadRequest, "неверный тип товара")
-		default:
-			httpresponse.Error(w, http.StatusInternalServerError, "ошибка при добавлении товаров")
-		}
+		apperror.WriteHTTP(w, r, err, "products_create_failed")
 		return
 	}
 
@@ -121,21 +106,12 @@ func (h *ProductHandler) CreateBatch(w http.ResponseWriter, r *http.Request) {
 func (h *ProductHandler) DeleteLast(w http.ResponseWriter, r *http.Request) {
 	receptionID, err := uuid.Parse(chi.URLParam(r, "reception_id"))
 	if err != nil {
-		httpresponse.Error(w, http.StatusBadRequest, "неверный формат ID приемки")
+		apperror.WriteInvalidRequest(w, r, "invalid_reception_id")
 		return
 	}
 
 	if err := h.service.DeleteLast(r.Context(), receptionID); err != nil {
-		switch err {
-		case productService.ErrReceptionNotFound:
-			httpresponse.Error(w, http.StatusNotFound, "приемка не найдена")
-		case productService.ErrReceptionAlreadyClose:
-			httpresponse.Error(w, http.StatusBadRequest, "приемка уже закрыта")
-		case productService.ErrProductNotFound:
-			httpresponse.Error(w, http.StatusNotFound, "товар не найден")
-		default:
-			httpresponse.Error(w, http.StatusInternalServerError, "ошибка при удалении товара")
-		}
+		apperror.WriteHTTP(w, r, err, "product_delete_failed")
 		return
 	}
 
@@ -147,13 +123,13 @@ func (h *ProductHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	productID, err := uuid.Parse(id)
 	if err != nil {
-		httpresponse.Error(w, http.StatusBadRequest, "неверный формат ID товара")
+		apperror.WriteInvalidRequest(w, r, "invalid_product_id")
 		return
 	}
 
 	p, err := h.service.GetByID(r.Context(), productID)
 	if err != nil {
-		httpresponse.Error(w, http.StatusNotFound, "товар не найден")
+		apperror.WriteHTTP(w, r, err, "product_get_failed")
 		return
 	}
 
@@ -165,33 +141,77 @@ func (h *ProductHandler) GetByReceptionID(w http.ResponseWriter, r *http.Request
 	receptionID := chi.URLParam(r, "reception_id")
 	receptionUUID, err := uuid.Parse(receptionID)
 	if err != nil {
-		httpresponse.Error(w, http.StatusBadRequest, "неверный формат ID приемки")
+		apperror.WriteInvalidRequest(w, r, "invalid_reception_id")
 		return
 	}
 
 	products, err := h.service.GetByReceptionID(r.Context(), receptionUUID)
 	if err != nil {
-		httpresponse.Error(w, http.StatusInternalServerError, "ошибка при получении товаров")
+		apperror.WriteHTTP(w, r, err, "reception_products_get_failed")
 		return
 	}
 
 	httpresponse.JSON(w, http.StatusOK, products)
 }
 
-// List возвращает список товаров
+// List возвращает список товаров.
+// Поддерживает фильтры reception_id, type, from и to и сортировку
+// sort=date_time|type, по убыванию с префиксом "-".
 func (h *ProductHandler) List(w http.ResponseWriter, r *http.Request) {
-	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
-	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	q := r.URL.Query()
+
+	var receptionID uuid.UUID
+	if id := q.Get("reception_id"); id != "" {
+		parsed, err := uuid.Parse(id)
+		if err != nil {
+			apperror.WriteInvalidRequest(w, r, "invalid_reception_id")
+			return
+		}
+		receptionID = parsed
+	}
 
-	if limit <= 0 {
-		limit = 10
+	sort, err := listing.ParseSort(q.Get("sort"))
+	if err != nil {
+		apperror.WriteHTTP(w, r, err, "invalid_sort")
+		return
 	}
 
-	products, err := h.service.List(r.Context(), offset, limit)
+	dateTime, err := parseListDateRange(q)
 	if err != nil {
-		httpresponse.Error(w, http.StatusInternalServerError, "ошибка при получении списка товаров")
+		apperror.WriteInvalidRequest(w, r, "invalid_date")
+		return
+	}
+
+	products, err := h.service.ListByFilter(r.Context(), product.ListFilter{
+		ReceptionID: receptionID,
+		Type:        product.Type(q.Get("type")),
+		DateTime:    dateTime,
+		Sort:        sort,
+		Page:        parseListPage(q),
+	})
+	if err != nil {
+		apperror.WriteHTTP(w, r, err, "product_list_failed")
 		return
 	}
 
 	httpresponse.JSON(w, http.StatusOK, products)
 }
+
+// productTypeInfo тип товара с названием на языке запроса
+type productTypeInfo struct {
+	Type product.Type `json:"type"`
+	Name string       `json:"name"`
+}
+
+// ListTypes возвращает допустимые типы товаров с названиями на языке из Accept-Language
+func (h *ProductHandler) ListTypes(w http.ResponseWriter, r *http.Request) {
+	lang := i18n.LangFromContext(r.Context())
+
+	types := product.Types()
+	result := make([]productTypeInfo, len(types))
+	for i, t := range types {
+		result[i] = productTypeInfo{Type: t, Name: i18n.ProductTypeName(lang, t)}
+	}
+
+	httpresponse.JSON(w, http.StatusOK, result)
+}
@@ -8,11 +8,18 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/avito/pvz/internal/domain/assignment"
+	"github.com/avito/pvz/internal/domain/audit"
+	"github.com/avito/pvz/internal/domain/event"
+	"github.com/avito/pvz/internal/domain/listing"
+	"github.com/avito/pvz/internal/domain/outbox"
 	"github.com/avito/pvz/internal/domain/product"
 	"github.com/avito/pvz/internal/domain/reception"
 	"github.com/avito/pvz/internal/domain/user"
+	"github.com/avito/pvz/internal/handler/i18n"
 	productService "github.com/avito/pvz/internal/service/product"
 	"github.com/avito/pvz/pkg/auth"
+	"github.com/avito/pvz/pkg/httpresponse"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -63,6 +70,14 @@ func (m *mockProductRepo) List(ctx context.Context, offset, limit int) ([]*produ
 	return args.Get(0).([]*product.Product), args.Error(1)
 }
 
+func (m *mockProductRepo) ListByFilter(ctx context.Context, filter product.ListFilter) ([]*product.Product, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*product.Product), args.Error(1)
+}
+
 func (m *mockProductRepo) Delete(ctx context.Context, id uuid.UUID) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
@@ -93,6 +108,14 @@ func (m *mockReceptionRepo) GetByID(ctx context.Context, id uuid.UUID) (*recepti
 	return args.Get(0).(*reception.Reception), args.Error(1)
 }
 
+func (m *mockReceptionRepo) GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*reception.Reception, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*reception.Reception), args.Error(1)
+}
+
 func (m *mockReceptionRepo) Create(ctx context.Context, reception *reception.Reception) error {
 	args := m.Called(ctx, reception)
 	return args.Error(0)
@@ -135,6 +158,14 @@ func (m *mockReceptionRepo) List(ctx context.Context, offset, limit int) ([]*rec
 	return args.Get(0).([]*reception.Reception), args.Error(1)
 }
 
+func (m *mockReceptionRepo) ListByFilter(ctx context.Context, filter reception.ListFilter) ([]*reception.Reception, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*reception.Reception), args.Error(1)
+}
+
 func (m *mockReceptionRepo) Update(ctx context.Context, reception *reception.Reception) error {
 	args := m.Called(ctx, reception)
 	return args.Error(0)
@@ -169,7 +200,7 @@ func TestProductHandler_Create(t *testing.T) {
 			},
 			setupMocks: func(pr *mockProductRepo, rr *mockReceptionRepo, tm *mockTxManager) {
 				receptionID := uuid.New()
-				rr.On("GetByID", mock.Anything, mock.AnythingOfType("uuid.UUID")).
+				rr.On("GetByIDForUpdate", mock.Anything, mock.AnythingOfType("uuid.UUID")).
 					Return(&reception.Reception{
 						ID:     receptionID,
 						Status: reception.StatusInProgress,
@@ -209,7 +240,7 @@ func TestProductHandler_Create(t *testing.T) {
 				return auth.WithUserRole(ctx, user.RoleEmployee)
 			},
 			setupMocks: func(pr *mockProductRepo, rr *mockReceptionRepo, tm *mockTxManager) {
-				rr.On("GetByID", mock.Anything, mock.AnythingOfType("uuid.UUID")).
+				rr.On("GetByIDForUpdate", mock.Anything, mock.AnythingOfType("uuid.UUID")).
 					Return(nil, productService.ErrReceptionNotFound).Maybe()
 
 				tm.On("WithinTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).
@@ -229,7 +260,7 @@ func TestProductHandler_Create(t *testing.T) {
 			},
 			setupMocks: func(pr *mockProductRepo, rr *mockReceptionRepo, tm *mockTxManager) {
 				receptionID := uuid.New()
-				rr.On("GetByID", mock.Anything, mock.AnythingOfType("uuid.UUID")).
+				rr.On("GetByIDForUpdate", mock.Anything, mock.AnythingOfType("uuid.UUID")).
 					Return(&reception.Reception{
 						ID:     receptionID,
 						Status: reception.StatusClose,
@@ -250,7 +281,7 @@ func TestProductHandler_Create(t *testing.T) {
 			txManager := new(mockTxManager)
 			tt.setupMocks(productRepo, receptionRepo, txManager)
 
-			service := productService.New(productRepo, receptionRepo, txManager)
+			service := productService.New(productRepo, receptionRepo, txManager, event.Discard, outbox.Discard, audit.Discard, assignment.AllowAll)
 			handler := NewProductHandler(service)
 
 			body, err := json.Marshal(tt.requestBody)
@@ -264,10 +295,10 @@ func TestProductHandler_Create(t *testing.T) {
 
 			assert.Equal(t, tt.expectedStatus, rec.Code)
 			if tt.expectedBody != "" {
-				var response map[string]string
+				var response httpresponse.ProblemDetails
 				err := json.NewDecoder(rec.Body).Decode(&response)
 				require.NoError(t, err)
-				assert.Equal(t, tt.expectedBody, response["error"])
+				assert.Equal(t, tt.expectedBody, response.Detail)
 			}
 
 			productRepo.AssertExpectations(t)
@@ -297,7 +328,7 @@ func TestProductHandler_CreateBatch(t *testing.T) {
 			},
 			setupMocks: func(pr *mockProductRepo, rr *mockReceptionRepo, tm *mockTxManager) {
 				receptionID := uuid.New()
-				rr.On("GetByID", mock.Anything, mock.AnythingOfType("uuid.UUID")).
+				rr.On("GetByIDForUpdate", mock.Anything, mock.AnythingOfType("uuid.UUID")).
 					Return(&reception.Reception{
 						ID:     receptionID,
 						Status: reception.StatusInProgress,
@@ -336,7 +367,7 @@ func TestProductHandler_CreateBatch(t *testing.T) {
 			txManager := new(mockTxManager)
 			tt.setupMocks(productRepo, receptionRepo, txManager)
 
-			service := productService.New(productRepo, receptionRepo, txManager)
+			service := productService.New(productRepo, receptionRepo, txManager, event.Discard, outbox.Discard, audit.Discard, assignment.AllowAll)
 			handler := NewProductHandler(service)
 
 			body, err := json.Marshal(tt.requestBody)
@@ -350,10 +381,10 @@ func TestProductHandler_CreateBatch(t *testing.T) {
 
 			assert.Equal(t, tt.expectedStatus, rec.Code)
 			if tt.expectedBody != "" {
-				var response map[string]string
+				var response httpresponse.ProblemDetails
 				err := json.NewDecoder(rec.Body).Decode(&response)
 				require.NoError(t, err)
-				assert.Equal(t, tt.expectedBody, response["error"])
+				assert.Equal(t, tt.expectedBody, response.Detail)
 			}
 
 			productRepo.AssertExpectations(t)
@@ -380,12 +411,14 @@ func TestProductHandler_DeleteLast(t *testing.T) {
 			},
 			setupMocks: func(pr *mockProductRepo, rr *mockReceptionRepo, tm *mockTxManager) {
 				receptionID := uuid.New()
-				rr.On("GetByID", mock.Anything, mock.AnythingOfType("uuid.UUID")).
+				rr.On("GetByIDForUpdate", mock.Anything, mock.AnythingOfType("uuid.UUID")).
 					Return(&reception.Reception{
 						ID:     receptionID,
 						Status: reception.StatusInProgress,
 					}, nil)
 
+				pr.On("GetLast", mock.Anything, mock.AnythingOfType("uuid.UUID")).
+					Return(&product.Product{ID: uuid.New()}, nil).Maybe()
 				pr.On("DeleteLast", mock.Anything, mock.AnythingOfType("uuid.UUID")).
 					Return(nil).Maybe()
 
@@ -416,7 +449,7 @@ func TestProductHandler_DeleteLast(t *testing.T) {
 			txManager := new(mockTxManager)
 			tt.setupMocks(productRepo, receptionRepo, txManager)
 
-			service := productService.New(productRepo, receptionRepo, txManager)
+			service := productService.New(productRepo, receptionRepo, txManager, event.Discard, outbox.Discard, audit.Discard, assignment.AllowAll)
 			handler := NewProductHandler(service)
 
 			req := httptest.NewRequest(http.MethodDelete, "/product/last/"+tt.receptionID, nil)
@@ -430,10 +463,10 @@ func TestProductHandler_DeleteLast(t *testing.T) {
 
 			assert.Equal(t, tt.expectedStatus, rec.Code)
 			if tt.expectedBody != "" {
-				var response map[string]string
+				var response httpresponse.ProblemDetails
 				err := json.NewDecoder(rec.Body).Decode(&response)
 				require.NoError(t, err)
-				assert.Equal(t, tt.expectedBody, response["error"])
+				assert.Equal(t, tt.expectedBody, response.Detail)
 			}
 
 			productRepo.AssertExpectations(t)
@@ -487,7 +520,7 @@ func TestProductHandler_GetByID(t *testing.T) {
 			txManager := new(mockTxManager)
 			tt.setupMocks(productRepo, receptionRepo, txManager)
 
-			service := productService.New(productRepo, receptionRepo, txManager)
+			service := productService.New(productRepo, receptionRepo, txManager, event.Discard, outbox.Discard, audit.Discard, assignment.AllowAll)
 			handler := NewProductHandler(service)
 
 			req := httptest.NewRequest(http.MethodGet, "/product/"+tt.productID, nil)
@@ -501,10 +534,10 @@ func TestProductHandler_GetByID(t *testing.T) {
 
 			assert.Equal(t, tt.expectedStatus, rec.Code)
 			if tt.expectedBody != "" {
-				var response map[string]string
+				var response httpresponse.ProblemDetails
 				err := json.NewDecoder(rec.Body).Decode(&response)
 				require.NoError(t, err)
-				assert.Equal(t, tt.expectedBody, response["error"])
+				assert.Equal(t, tt.expectedBody, response.Detail)
 			}
 
 			productRepo.AssertExpectations(t)
@@ -560,7 +593,7 @@ func TestProductHandler_GetByReceptionID(t *testing.T) {
 			txManager := new(mockTxManager)
 			tt.setupMocks(productRepo, receptionRepo, txManager)
 
-			service := productService.New(productRepo, receptionRepo, txManager)
+			service := productService.New(productRepo, receptionRepo, txManager, event.Discard, outbox.Discard, audit.Discard, assignment.AllowAll)
 			handler := NewProductHandler(service)
 
 			req := httptest.NewRequest(http.MethodGet, "/product/reception/"+tt.receptionID, nil)
@@ -574,10 +607,10 @@ func TestProductHandler_GetByReceptionID(t *testing.T) {
 
 			assert.Equal(t, tt.expectedStatus, rec.Code)
 			if tt.expectedBody != "" {
-				var response map[string]string
+				var response httpresponse.ProblemDetails
 				err := json.NewDecoder(rec.Body).Decode(&response)
 				require.NoError(t, err)
-				assert.Equal(t, tt.expectedBody, response["error"])
+				assert.Equal(t, tt.expectedBody, response.Detail)
 			}
 
 			productRepo.AssertExpectations(t)
@@ -606,7 +639,7 @@ func TestProductHandler_List(t *testing.T) {
 				return auth.WithUserRole(ctx, user.RoleEmployee)
 			},
 			setupMocks: func(pr *mockProductRepo, rr *mockReceptionRepo, tm *mockTxManager) {
-				pr.On("List", mock.Anything, 0, 10).
+				pr.On("ListByFilter", mock.Anything, product.ListFilter{Page: listing.Page{Offset: 0, Limit: 10}}).
 					Return([]*product.Product{
 						{
 							ID:          uuid.New(),
@@ -623,11 +656,53 @@ func TestProductHandler_List(t *testing.T) {
 				return auth.WithUserRole(ctx, user.RoleEmployee)
 			},
 			setupMocks: func(pr *mockProductRepo, rr *mockReceptionRepo, tm *mockTxManager) {
-				pr.On("List", mock.Anything, 0, 10).
+				pr.On("ListByFilter", mock.Anything, product.ListFilter{Page: listing.Page{Offset: 0, Limit: 10}}).
 					Return([]*product.Product{}, nil)
 			},
 			expectedStatus: http.StatusOK,
 		},
+		{
+			name: "фильтр по типу с сортировкой",
+			queryParams: map[string]string{
+				"type": "food",
+				"sort": "-type",
+			},
+			setupAuth: func(ctx context.Context) context.Context {
+				return auth.WithUserRole(ctx, user.RoleEmployee)
+			},
+			setupMocks: func(pr *mockProductRepo, rr *mockReceptionRepo, tm *mockTxManager) {
+				pr.On("ListByFilter", mock.Anything, product.ListFilter{
+					Type: product.TypeFood,
+					Sort: listing.Sort{Field: "type", Direction: listing.DirectionDesc},
+					Page: listing.Page{Offset: 0, Limit: 10},
+				}).Return([]*product.Product{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "неверный тип товара",
+			queryParams: map[string]string{
+				"type": "weapons",
+			},
+			setupAuth: func(ctx context.Context) context.Context {
+				return auth.WithUserRole(ctx, user.RoleEmployee)
+			},
+			setupMocks:     func(pr *mockProductRepo, rr *mockReceptionRepo, tm *mockTxManager) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "неверное значение фильтра",
+		},
+		{
+			name: "слишком большой лимит",
+			queryParams: map[string]string{
+				"limit": "1000",
+			},
+			setupAuth: func(ctx context.Context) context.Context {
+				return auth.WithUserRole(ctx, user.RoleEmployee)
+			},
+			setupMocks:     func(pr *mockProductRepo, rr *mockReceptionRepo, tm *mockTxManager) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "неверные параметры пагинации",
+		},
 	}
 
 	for _, tt := range tests {
@@ -637,7 +712,7 @@ func TestProductHandler_List(t *testing.T) {
 			txManager := new(mockTxManager)
 			tt.setupMocks(productRepo, receptionRepo, txManager)
 
-			service := productService.New(productRepo, receptionRepo, txManager)
+			service := productService.New(productRepo, receptionRepo, txManager, event.Discard, outbox.Discard, audit.Discard, assignment.AllowAll)
 			handler := NewProductHandler(service)
 
 			req := httptest.NewRequest(http.MethodGet, "/product", nil)
@@ -655,10 +730,10 @@ func TestProductHandler_List(t *testing.T) {
 
 			assert.Equal(t, tt.expectedStatus, rec.Code)
 			if tt.expectedBody != "" {
-				var response map[string]string
+				var response httpresponse.ProblemDetails
 				err := json.NewDecoder(rec.Body).Decode(&response)
 				require.NoError(t, err)
-				assert.Equal(t, tt.expectedBody, response["error"])
+				assert.Equal(t, tt.expectedBody, response.Detail)
 			}
 
 			productRepo.AssertExpectations(t)
@@ -667,3 +742,49 @@ func TestProductHandler_List(t *testing.T) {
 		})
 	}
 }
+
+func TestProductHandler_ListTypes(t *testing.T) {
+	tests := []struct {
+		name     string
+		lang     i18n.Lang
+		expected []productTypeInfo
+	}{
+		{
+			name: "названия на русском",
+			lang: i18n.Russian,
+			expected: []productTypeInfo{
+				{Type: product.TypeElectronics, Name: "электроника"},
+				{Type: product.TypeClothing, Name: "одежда"},
+				{Type: product.TypeFood, Name: "продукты"},
+				{Type: product.TypeOther, Name: "другое"},
+			},
+		},
+		{
+			name: "названия на английском",
+			lang: i18n.English,
+			expected: []productTypeInfo{
+				{Type: product.TypeElectronics, Name: "electronics"},
+				{Type: product.TypeClothing, Name: "clothing"},
+				{Type: product.TypeFood, Name: "food"},
+				{Type: product.TypeOther, Name: "other"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewProductHandler(productService.New(new(mockProductRepo), new(mockReceptionRepo), new(mockTxManager), event.Discard, outbox.Discard, audit.Discard, assignment.AllowAll))
+
+			req := httptest.NewRequest(http.MethodGet, "/product/types", nil)
+			req = req.WithContext(i18n.WithLang(req.Context(), tt.lang))
+
+			rec := httptest.NewRecorder()
+			handler.ListTypes(rec, req)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			var result []productTypeInfo
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&result))
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
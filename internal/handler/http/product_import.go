@@ -0,0 +1,138 @@
+package http
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/avito/pvz/internal/handler/apperror"
+	"github.com/avito/pvz/internal/handler/i18n"
+	productService "github.com/avito/pvz/internal/service/product"
+	"github.com/avito/pvz/pkg/httpresponse"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// maxImportBodySize максимальный размер CSV-файла импорта
+const maxImportBodySize = 5 << 20
+
+// errMissingImportColumns возвращается, когда в заголовке CSV нет обязательных колонок
+var errMissingImportColumns = errors.New("csv header must contain type and barcode columns")
+
+// Import обрабатывает импорт товаров в приемку из CSV-файла поставщика.
+// Первая строка файла - заголовок с колонками type, barcode и необязательной metadata.
+// С параметром dry_run=true файл только проверяется, товары не сохраняются.
+func (h *ProductHandler) Import(w http.ResponseWriter, r *http.Request) {
+	receptionID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		apperror.WriteInvalidRequest(w, r, "invalid_reception_id")
+		return
+	}
+
+	dryRun := false
+	if v := r.URL.Query().Get("dry_run"); v != "" {
+		dryRun, err = strconv.ParseBool(v)
+		if err != nil {
+			apperror.WriteInvalidRequest(w, r, "invalid_dry_run")
+			return
+		}
+	}
+
+	rows, err := parseImportCSV(http.MaxBytesReader(w, r.Body, maxImportBodySize))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		switch {
+		case errors.As(err, &maxBytesErr):
+			apperror.WriteHTTP(w, r, apperror.PayloadTooLarge("import_file_too_large"), "")
+		case errors.Is(err, errMissingImportColumns):
+			apperror.WriteInvalidRequest(w, r, "import_missing_columns")
+		default:
+			apperror.WriteInvalidRequest(w, r, "invalid_csv")
+		}
+		return
+	}
+
+	result, err := h.service.Import(r.Context(), receptionID, rows, dryRun)
+	if err != nil {
+		apperror.WriteHTTP(w, r, err, "product_import_failed")
+		return
+	}
+
+	lang := i18n.LangFromContext(r.Context())
+	for i := range result.Errors {
+		result.Errors[i].Message = i18n.Message(lang, result.Errors[i].Code)
+	}
+
+	switch {
+	case len(result.Errors) > 0:
+		httpresponse.JSON(w, http.StatusUnprocessableEntity, result)
+	case dryRun:
+		httpresponse.JSON(w, http.StatusOK, result)
+	default:
+		httpresponse.JSON(w, http.StatusCreated, result)
+	}
+}
+
+// parseImportCSV читает строки файла импорта. Колонки определяются по заголовку,
+// поэтому их порядок не важен, а лишние колонки игнорируются.
+func parseImportCSV(body io.Reader) ([]productService.ImportRow, error) {
+	reader := csv.NewReader(body)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.TrimPrefix(name, "\ufeff")
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	typeIdx, hasType := columns["type"]
+	barcodeIdx, hasBarcode := columns["barcode"]
+	if !hasType || !hasBarcode {
+		return nil, errMissingImportColumns
+	}
+	metadataIdx, hasMetadata := columns["metadata"]
+
+	var rows []productService.ImportRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		line, _ := reader.FieldPos(0)
+		row := productService.ImportRow{
+			Line:    line,
+			Type:    csvField(record, typeIdx),
+			Barcode: csvField(record, barcodeIdx),
+		}
+		if hasMetadata {
+			row.Metadata = csvField(record, metadataIdx)
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// csvField возвращает значение колонки или пустую строку, если в записи ее нет
+func csvField(record []string, idx int) string {
+	if idx >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[idx])
+}
@@ -0,0 +1,168 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/avito/pvz/internal/domain/assignment"
+	"github.com/avito/pvz/internal/domain/audit"
+	"github.com/avito/pvz/internal/domain/event"
+	"github.com/avito/pvz/internal/domain/outbox"
+	"github.com/avito/pvz/internal/domain/product"
+	"github.com/avito/pvz/internal/domain/reception"
+	"github.com/avito/pvz/internal/handler/i18n"
+	productService "github.com/avito/pvz/internal/service/product"
+	"github.com/avito/pvz/pkg/httpresponse"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProductHandler_Import(t *testing.T) {
+	receptionID := uuid.New()
+
+	openReception := func(rr *mockReceptionRepo, tm *mockTxManager) {
+		rr.On("GetByIDForUpdate", mock.Anything, receptionID).Return(&reception.Reception{ID: receptionID, Status: reception.StatusInProgress}, nil)
+		tm.On("WithinTransaction", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			fn := args.Get(1).(func(context.Context) error)
+			fn(context.Background())
+		}).Return(nil)
+	}
+
+	tests := []struct {
+		name           string
+		receptionID    string
+		query          string
+		lang           i18n.Lang
+		body           string
+		setupMocks     func(*mockProductRepo, *mockReceptionRepo, *mockTxManager)
+		expectedStatus int
+		expectedError  string
+		expectedResult *productService.ImportResult
+	}{
+		{
+			name:        "успешный импорт",
+			receptionID: receptionID.String(),
+			body:        "barcode,type,metadata\n4600000000001,electronics,\"партия 7, коробка 1\"\n4600000000002,food,\n",
+			setupMocks: func(pr *mockProductRepo, rr *mockReceptionRepo, tm *mockTxManager) {
+				openReception(rr, tm)
+				pr.On("CreateBatch", mock.Anything, mock.MatchedBy(func(products []*product.Product) bool {
+					return len(products) == 2 &&
+						products[0].Barcode == "4600000000001" &&
+						products[0].Metadata == "партия 7, коробка 1" &&
+						products[1].Type == product.TypeFood
+				})).Return(nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedResult: &productService.ImportResult{Total: 2, Imported: 2},
+		},
+		{
+			name:        "проверка без сохранения",
+			receptionID: receptionID.String(),
+			query:       "?dry_run=true",
+			body:        "type,barcode\nclothing,4600000000003\n",
+			setupMocks: func(pr *mockProductRepo, rr *mockReceptionRepo, tm *mockTxManager) {
+				openReception(rr, tm)
+			},
+			expectedStatus: http.StatusOK,
+			expectedResult: &productService.ImportResult{Total: 1, DryRun: true},
+		},
+		{
+			name:        "ошибки в строках",
+			receptionID: receptionID.String(),
+			body:        "type,barcode\nclothing,4600000000003\nweapons,4600000000004\n",
+			setupMocks: func(pr *mockProductRepo, rr *mockReceptionRepo, tm *mockTxManager) {
+				openReception(rr, tm)
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedResult: &productService.ImportResult{
+				Total:  2,
+				Errors: []productService.RowError{{Line: 3, Field: "type", Code: "invalid_product_type", Message: "неверный тип товара"}},
+			},
+		},
+		{
+			name:        "ошибки в строках на английском",
+			receptionID: receptionID.String(),
+			lang:        i18n.English,
+			body:        "type,barcode\nclothing,\n",
+			setupMocks: func(pr *mockProductRepo, rr *mockReceptionRepo, tm *mockTxManager) {
+				openReception(rr, tm)
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedResult: &productService.ImportResult{
+				Total:  1,
+				Errors: []productService.RowError{{Line: 2, Field: "barcode", Code: "empty_barcode", Message: "barcode is required"}},
+			},
+		},
+		{
+			name:           "нет обязательных колонок",
+			receptionID:    receptionID.String(),
+			body:           "type,metadata\nclothing,\n",
+			setupMocks:     func(pr *mockProductRepo, rr *mockReceptionRepo, tm *mockTxManager) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "в заголовке файла нет колонок type и barcode",
+		},
+		{
+			name:           "пустой файл",
+			receptionID:    receptionID.String(),
+			body:           "type,barcode\n",
+			setupMocks:     func(pr *mockProductRepo, rr *mockReceptionRepo, tm *mockTxManager) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "файл не содержит товаров",
+		},
+		{
+			name:           "неверный ID приемки",
+			receptionID:    "invalid",
+			body:           "type,barcode\nclothing,4600000000003\n",
+			setupMocks:     func(pr *mockProductRepo, rr *mockReceptionRepo, tm *mockTxManager) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "неверный формат ID приемки",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			productRepo := new(mockProductRepo)
+			receptionRepo := new(mockReceptionRepo)
+			txManager := new(mockTxManager)
+			tt.setupMocks(productRepo, receptionRepo, txManager)
+
+			service := productService.New(productRepo, receptionRepo, txManager, event.Discard, outbox.Discard, audit.Discard, assignment.AllowAll)
+			handler := NewProductHandler(service)
+
+			req := httptest.NewRequest(http.MethodPost, "/reception/"+tt.receptionID+"/products/import"+tt.query, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "text/csv")
+			if tt.lang != "" {
+				req = req.WithContext(i18n.WithLang(req.Context(), tt.lang))
+			}
+
+			r := chi.NewRouter()
+			r.Post("/reception/{id}/products/import", handler.Import)
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedError != "" {
+				var response httpresponse.ProblemDetails
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+				assert.Equal(t, tt.expectedError, response.Detail)
+			}
+			if tt.expectedResult != nil {
+				var result productService.ImportResult
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&result))
+				assert.Equal(t, *tt.expectedResult, result)
+			}
+
+			productRepo.AssertExpectations(t)
+			receptionRepo.AssertExpectations(t)
+			txManager.AssertExpectations(t)
+		})
+	}
+}
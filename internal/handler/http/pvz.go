@@ -2,10 +2,9 @@ package http
 
 import (
 	"encoding/json"
-	"errors"
 	"net/http"
 
-	domainpvz "github.com/avito/pvz/internal/domain/pvz"
+	"github.com/avito/pvz/internal/handler/apperror"
 	pvzservice "github.com/avito/pvz/internal/service/pvz"
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
@@ -25,24 +24,24 @@ func createPVZ(service *pvzservice.Service) http.HandlerFunc {
 		}
 
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			apperror.WriteInvalidRequest(w, r, "invalid_body")
 			return
 		}
 
 		if req.City == "" {
-			http.Error(w, "City is required", http.StatusBadRequest)
+			apperror.WriteInvalidRequest(w, r, "city_required")
 			return
 		}
 
 		moderatorID, err := getModeratorID(r)
 		if err != nil {
-			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			apperror.WriteHTTP(w, r, apperror.ErrUnauthorized, "")
 			return
 		}
 
 		pvz, err := service.Create(r.Context(), req.City, moderatorID)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			apperror.WriteHTTP(w, r, err, "pvz_create_failed")
 			return
 		}
 
@@ -57,17 +56,13 @@ func getPVZ(service *pvzservice.Service) http.HandlerFunc {
 		vars := mux.Vars(r)
 		id, err := uuid.Parse(vars["id"])
 		if err != nil {
-			http.Error(w, "Invalid PVZ ID", http.StatusBadRequest)
+			apperror.WriteInvalidRequest(w, r, "invalid_pvz_id")
 			return
 		}
 
 		pvz, err := service.GetByID(r.Context(), id)
 		if err != nil {
-			if errors.Is(err, domainpvz.ErrNotFound) {
-				http.Error(w, "PVZ not found", http.StatusNotFound)
-				return
-			}
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			apperror.WriteHTTP(w, r, err, "pvz_get_failed")
 			return
 		}
 
@@ -80,7 +75,7 @@ func getAllPVZ(service *pvzservice.Service) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		pvzs, err := service.GetAll(r.Context())
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			apperror.WriteHTTP(w, r, err, "pvz_list_failed")
 			return
 		}
 
@@ -5,12 +5,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"strconv"
 	"time"
 
+	"github.com/avito/pvz/internal/domain/listing"
 	domainPVZ "github.com/avito/pvz/internal/domain/pvz"
+	"github.com/avito/pvz/internal/domain/rbac"
+	"github.com/avito/pvz/internal/handler/apperror"
 	"github.com/avito/pvz/internal/handler/http/middleware"
-	servicePVZ "github.com/avito/pvz/internal/service/pvz"
 	"github.com/avito/pvz/pkg/auth"
 	"github.com/avito/pvz/pkg/httpresponse"
 	"github.com/go-chi/chi/v5"
@@ -22,10 +23,12 @@ type PVZServiceInterface interface {
 	Create(ctx context.Context, city string, userID uuid.UUID) (*domainPVZ.PVZ, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*domainPVZ.PVZ, error)
 	GetWithReceptions(ctx context.Context, startDate, endDate time.Time, page, limit int) ([]*domainPVZ.PVZWithReceptions, error)
+	GetWithReceptionsByCursor(ctx context.Context, startDate, endDate time.Time, cursor string, limit int) ([]*domainPVZ.PVZWithReceptions, string, error)
 	GetAll(ctx context.Context) ([]*domainPVZ.PVZ, error)
 	Update(ctx context.Context, pvz *domainPVZ.PVZ, moderatorID uuid.UUID) error
 	Delete(ctx context.Context, id uuid.UUID, moderatorID uuid.UUID) error
 	List(ctx context.Context, offset, limit int) ([]*domainPVZ.PVZ, error)
+	ListByFilter(ctx context.Context, filter domainPVZ.ListFilter) ([]*domainPVZ.PVZ, error)
 }
 
 // PVZHandler обрабатывает HTTP-запросы для ПВЗ
@@ -40,11 +43,17 @@ func NewPVZHandler(service PVZServiceInterface) *PVZHandler {
 	}
 }
 
-// RegisterRoutes регистрирует маршруты для ПВЗ
+// RegisterRoutes регистрирует маршруты для ПВЗ, доступные только
+// аутентифицированным пользователям. Изменения требуют разрешений роли.
 func (h *PVZHandler) RegisterRoutes(r chi.Router) {
-	r.Post("/pvz", h.Create)
-	r.Get("/pvz/{id}", h.GetByID)
-	r.Get("/pvz", h.GetWithReceptions)
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.AuthMiddleware)
+
+		r.With(middleware.RequirePermission(rbac.PVZCreate)).Post("/pvz", h.Create)
+		r.Get("/pvz/{id}", h.GetByID)
+		r.With(middleware.RequirePermission(rbac.PVZUpdate)).Put("/pvz/{id}", h.UpdatePVZ)
+		r.Get("/pvz", h.GetWithReceptions)
+	})
 }
 
 // Create обрабатывает создание ПВЗ
@@ -54,34 +63,25 @@ func (h *PVZHandler) Create(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		httpresponse.Error(w, http.StatusBadRequest, "неверный формат запроса")
+		apperror.WriteInvalidRequest(w, r, "invalid_body")
 		return
 	}
 
 	if req.City == "" {
-		httpresponse.Error(w, http.StatusBadRequest, "город не может быть пустым")
+		apperror.WriteInvalidRequest(w, r, "city_required")
 		return
 	}
 
 	// Получаем ID пользователя из контекста
 	userID, ok := auth.GetUserID(r.Context())
 	if !ok || userID == uuid.Nil {
-		httpresponse.Error(w, http.StatusUnauthorized, "требуется авторизация")
+		apperror.WriteHTTP(w, r, apperror.ErrUnauthorized, "")
 		return
 	}
 
 	pvz, err := h.service.Create(r.Context(), req.City, userID)
 	if err != nil {
-		switch err {
-		case servicePVZ.ErrInvalidCity:
-			httpresponse.Error(w, http.StatusBadRequest, "неверное название города")
-		case servicePVZ.ErrPVZAlreadyExists:
-			httpresponse.Error(w, http.StatusConflict, "ПВЗ уже существует")
-		case servicePVZ.ErrAccessDenied:
-			httpresponse.Error(w, http.StatusForbidden, "доступ запрещен")
-		default:
-			httpresponse.Error(w, http.StatusInternalServerError, "ошибка при создании ПВЗ")
-		}
+		apperror.WriteHTTP(w, r, err, "pvz_create_failed")
 		return
 	}
 
@@ -102,37 +102,44 @@ func (h *PVZHandler) Create(w http.ResponseWriter, r *http.Request) {
 func (h *PVZHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	id, err := uuid.Parse(chi.URLParam(r, "id"))
 	if err != nil {
-		httpresponse.Error(w, http.StatusBadRequest, "неверный формат ID ПВЗ")
+		apperror.WriteInvalidRequest(w, r, "invalid_pvz_id")
 		return
 	}
 
 	pvz, err := h.service.GetByID(r.Context(), id)
 	if err != nil {
-		httpresponse.Error(w, http.StatusNotFound, "ПВЗ не найден")
+		apperror.WriteHTTP(w, r, err, "pvz_get_failed")
 		return
 	}
 
+	setETag(w, pvz.Version)
 	httpresponse.JSON(w, http.StatusOK, pvz)
 }
 
-// GetWithReceptions обрабатывает получение списка ПВЗ с приемками
+// GetWithReceptions обрабатывает получение списка ПВЗ с приемками.
+// Без start_date и end_date отдает простой список ПВЗ с фильтрами ListPVZ.
 func (h *PVZHandler) GetWithReceptions(w http.ResponseWriter, r *http.Request) {
+	if !r.URL.Query().Has("start_date") && !r.URL.Query().Has("end_date") {
+		h.ListPVZ(w, r)
+		return
+	}
+
 	startDate, err := time.Parse(time.RFC3339, r.URL.Query().Get("start_date"))
 	if err != nil {
-		httpresponse.Error(w, http.StatusBadRequest, "неверный формат даты начала")
+		apperror.WriteInvalidRequest(w, r, "invalid_start_date")
 		return
 	}
 
 	endDate, err := time.Parse(time.RFC3339, r.URL.Query().Get("end_date"))
 	if err != nil {
-		httpresponse.Error(w, http.StatusBadRequest, "неверный формат даты окончания")
+		apperror.WriteInvalidRequest(w, r, "invalid_end_date")
 		return
 	}
 
 	page := 1
 	if p := r.URL.Query().Get("page"); p != "" {
 		if _, err := fmt.Sscanf(p, "%d", &page); err != nil {
-			httpresponse.Error(w, http.StatusBadRequest, "неверный формат страницы")
+			apperror.WriteInvalidRequest(w, r, "invalid_page_number")
 			return
 		}
 	}
@@ -140,20 +147,46 @@ func (h *PVZHandler) GetWithReceptions(w http.ResponseWriter, r *http.Request) {
 	limit := 10
 	if l := r.URL.Query().Get("limit"); l != "" {
 		if _, err := fmt.Sscanf(l, "%d", &limit); err != nil {
-			httpresponse.Error(w, http.StatusBadRequest, "неверный формат лимита")
+			apperror.WriteInvalidRequest(w, r, "invalid_limit")
 			return
 		}
 	}
 
+	// Параметр cursor (пустой для первой страницы) включает keyset-пагинацию,
+	// без него сохраняется постраничный режим page/limit
+	if r.URL.Query().Has("cursor") {
+		h.getWithReceptionsByCursor(w, r, startDate, endDate, limit)
+		return
+	}
+
 	pvzs, err := h.service.GetWithReceptions(r.Context(), startDate, endDate, page, limit)
 	if err != nil {
-		httpresponse.Error(w, http.StatusInternalServerError, "ошибка при получении списка ПВЗ")
+		apperror.WriteHTTP(w, r, err, "pvz_list_failed")
 		return
 	}
 
 	httpresponse.JSON(w, http.StatusOK, pvzs)
 }
 
+// getWithReceptionsByCursor отдает страницу ПВЗ с приемками вместе с курсором следующей страницы
+func (h *PVZHandler) getWithReceptionsByCursor(w http.ResponseWriter, r *http.Request, startDate, endDate time.Time, limit int) {
+	pvzs, nextCursor, err := h.service.GetWithReceptionsByCursor(r.Context(), startDate, endDate, r.URL.Query().Get("cursor"), limit)
+	if err != nil {
+		apperror.WriteHTTP(w, r, err, "pvz_list_failed")
+		return
+	}
+
+	response := struct {
+		Items      []*domainPVZ.PVZWithReceptions `json:"items"`
+		NextCursor string                         `json:"next_cursor"`
+	}{
+		Items:      pvzs,
+		NextCursor: nextCursor,
+	}
+
+	httpresponse.JSON(w, http.StatusOK, response)
+}
+
 // CreatePVZ создает новый ПВЗ
 func (h *PVZHandler) CreatePVZ(w http.ResponseWriter, r *http.Request) {
 	var req struct {
@@ -161,27 +194,27 @@ func (h *PVZHandler) CreatePVZ(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "invalid request body", http.StatusBadRequest)
+		apperror.WriteInvalidRequest(w, r, "invalid_body")
 		return
 	}
 
 	// Получаем ID модератора из контекста
 	moderatorID, ok := r.Context().Value(middleware.UserIDKey).(string)
 	if !ok {
-		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		apperror.WriteHTTP(w, r, apperror.ErrUnauthorized, "")
 		return
 	}
 
 	moderatorUUID, err := uuid.Parse(moderatorID)
 	if err != nil {
-		http.Error(w, "invalid moderator ID", http.StatusBadRequest)
+		apperror.WriteInvalidRequest(w, r, "invalid_moderator_id")
 		return
 	}
 
 	// Создаем ПВЗ
 	newPVZ, err := h.service.Create(r.Context(), req.City, moderatorUUID)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		apperror.WriteHTTP(w, r, err, "pvz_create_failed")
 		return
 	}
 
@@ -206,13 +239,13 @@ func (h *PVZHandler) GetPVZ(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	pvzID, err := uuid.Parse(id)
 	if err != nil {
-		http.Error(w, "invalid PVZ ID", http.StatusBadRequest)
+		apperror.WriteInvalidRequest(w, r, "invalid_pvz_id")
 		return
 	}
 
 	pvz, err := h.service.GetByID(r.Context(), pvzID)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusNotFound)
+		apperror.WriteHTTP(w, r, err, "pvz_get_failed")
 		return
 	}
 
@@ -230,30 +263,37 @@ func (h *PVZHandler) GetPVZ(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
-// UpdatePVZ обновляет данные ПВЗ
+// UpdatePVZ обновляет данные ПВЗ. С заголовком If-Match изменение применяется
+// только к версии из ETag, иначе возвращается 412.
 func (h *PVZHandler) UpdatePVZ(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	if id == "" {
-		http.Error(w, "invalid PVZ ID", http.StatusBadRequest)
+		apperror.WriteInvalidRequest(w, r, "invalid_pvz_id")
 		return
 	}
 
 	pvzID, err := uuid.Parse(id)
 	if err != nil {
-		http.Error(w, "invalid PVZ ID", http.StatusBadRequest)
+		apperror.WriteInvalidRequest(w, r, "invalid_pvz_id")
 		return
 	}
 
 	// Получаем ID модератора из контекста
 	moderatorID, ok := r.Context().Value(middleware.UserIDKey).(string)
 	if !ok {
-		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		apperror.WriteHTTP(w, r, apperror.ErrUnauthorized, "")
 		return
 	}
 
 	moderatorUUID, err := uuid.Parse(moderatorID)
 	if err != nil {
-		http.Error(w, "invalid moderator ID", http.StatusBadRequest)
+		apperror.WriteInvalidRequest(w, r, "invalid_moderator_id")
+		return
+	}
+
+	version, err := parseIfMatch(r)
+	if err != nil {
+		apperror.WriteInvalidRequest(w, r, "invalid_if_match")
 		return
 	}
 
@@ -262,28 +302,23 @@ func (h *PVZHandler) UpdatePVZ(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "invalid request body", http.StatusBadRequest)
+		apperror.WriteInvalidRequest(w, r, "invalid_body")
 		return
 	}
 
 	// Обновляем ПВЗ
 	pvz := &domainPVZ.PVZ{
-		ID:   pvzID,
-		City: req.City,
+		ID:      pvzID,
+		City:    req.City,
+		Version: version,
 	}
 
 	if err := h.service.Update(r.Context(), pvz, moderatorUUID); err != nil {
-		switch err {
-		case servicePVZ.ErrPVZNotFound:
-			http.Error(w, err.Error(), http.StatusNotFound)
-		case servicePVZ.ErrAccessDenied:
-			http.Error(w, err.Error(), http.StatusForbidden)
-		default:
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-		}
+		apperror.WriteHTTP(w, r, err, "pvz_update_failed")
 		return
 	}
 
+	setETag(w, pvz.Version)
 	w.WriteHeader(http.StatusOK)
 }
 
@@ -291,56 +326,63 @@ func (h *PVZHandler) UpdatePVZ(w http.ResponseWriter, r *http.Request) {
 func (h *PVZHandler) DeletePVZ(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	if id == "" {
-		http.Error(w, "invalid PVZ ID", http.StatusBadRequest)
+		apperror.WriteInvalidRequest(w, r, "invalid_pvz_id")
 		return
 	}
 
 	pvzID, err := uuid.Parse(id)
 	if err != nil {
-		http.Error(w, "invalid PVZ ID", http.StatusBadRequest)
+		apperror.WriteInvalidRequest(w, r, "invalid_pvz_id")
 		return
 	}
 
 	// Получаем ID модератора из контекста
 	moderatorID, ok := r.Context().Value(middleware.UserIDKey).(string)
 	if !ok {
-		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		apperror.WriteHTTP(w, r, apperror.ErrUnauthorized, "")
 		return
 	}
 
 	moderatorUUID, err := uuid.Parse(moderatorID)
 	if err != nil {
-		http.Error(w, "invalid moderator ID", http.StatusBadRequest)
+		apperror.WriteInvalidRequest(w, r, "invalid_moderator_id")
 		return
 	}
 
 	if err := h.service.Delete(r.Context(), pvzID, moderatorUUID); err != nil {
-		switch err {
-		case servicePVZ.ErrPVZNotFound:
-			http.Error(w, err.Error(), http.StatusNotFound)
-		case servicePVZ.ErrAccessDenied:
-			http.Error(w, err.Error(), http.StatusForbidden)
-		default:
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-		}
+		apperror.WriteHTTP(w, r, err, "pvz_delete_failed")
 		return
 	}
 
 	w.WriteHeader(http.StatusOK)
 }
 
-// ListPVZ возвращает список ПВЗ
+// ListPVZ возвращает список ПВЗ.
+// Поддерживает фильтры city, from и to (дата регистрации) и сортировку
+// sort=created_at|city, по убыванию с префиксом "-".
 func (h *PVZHandler) ListPVZ(w http.ResponseWriter, r *http.Request) {
-	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
-	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	q := r.URL.Query()
 
-	if limit <= 0 {
-		limit = 10
+	sort, err := listing.ParseSort(q.Get("sort"))
+	if err != nil {
+		apperror.WriteHTTP(w, r, err, "invalid_sort")
+		return
+	}
+
+	createdAt, err := parseListDateRange(q)
+	if err != nil {
+		apperror.WriteInvalidRequest(w, r, "invalid_date")
+		return
 	}
 
-	pvzs, err := h.service.List(r.Context(), offset, limit)
+	pvzs, err := h.service.ListByFilter(r.Context(), domainPVZ.ListFilter{
+		City:      q.Get("city"),
+		CreatedAt: createdAt,
+		Sort:      sort,
+		Page:      parseListPage(q),
+	})
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		apperror.WriteHTTP(w, r, err, "pvz_list_failed")
 		return
 	}
 
@@ -10,10 +10,14 @@ import (
 	"testing"
 	"time"
 
+	"github.com/avito/pvz/internal/domain/listing"
 	domainPVZ "github.com/avito/pvz/internal/domain/pvz"
+	"github.com/avito/pvz/internal/domain/rbac"
+	domainUser "github.com/avito/pvz/internal/domain/user"
 	"github.com/avito/pvz/internal/handler/http/middleware"
 	servicePVZ "github.com/avito/pvz/internal/service/pvz"
 	"github.com/avito/pvz/pkg/auth"
+	"github.com/avito/pvz/pkg/httpresponse"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -50,6 +54,14 @@ func (m *MockPVZService) GetWithReceptions(ctx context.Context, startDate, endDa
 	return args.Get(0).([]*domainPVZ.PVZWithReceptions), args.Error(1)
 }
 
+func (m *MockPVZService) GetWithReceptionsByCursor(ctx context.Context, startDate, endDate time.Time, cursor string, limit int) ([]*domainPVZ.PVZWithReceptions, string, error) {
+	args := m.Called(ctx, startDate, endDate, cursor, limit)
+	if args.Get(0) == nil {
+		return nil, args.String(1), args.Error(2)
+	}
+	return args.Get(0).([]*domainPVZ.PVZWithReceptions), args.String(1), args.Error(2)
+}
+
 func (m *MockPVZService) GetAll(ctx context.Context) ([]*domainPVZ.PVZ, error) {
 	args := m.Called(ctx)
 	if args.Get(0) == nil {
@@ -76,6 +88,14 @@ func (m *MockPVZService) List(ctx context.Context, offset, limit int) ([]*domain
 	return args.Get(0).([]*domainPVZ.PVZ), args.Error(1)
 }
 
+func (m *MockPVZService) ListByFilter(ctx context.Context, filter domainPVZ.ListFilter) ([]*domainPVZ.PVZ, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domainPVZ.PVZ), args.Error(1)
+}
+
 func TestPVZHandler_Create(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -187,10 +207,10 @@ func TestPVZHandler_Create(t *testing.T) {
 
 			assert.Equal(t, tt.expectedStatus, rec.Code)
 			if tt.expectedBody != "" {
-				var response map[string]string
+				var response httpresponse.ProblemDetails
 				err := json.NewDecoder(rec.Body).Decode(&response)
 				require.NoError(t, err)
-				assert.Equal(t, tt.expectedBody, response["error"])
+				assert.Equal(t, tt.expectedBody, response.Detail)
 			}
 
 			mockService.AssertExpectations(t)
@@ -205,6 +225,7 @@ func TestPVZHandler_GetByID(t *testing.T) {
 		setupMock      func(*MockPVZService)
 		expectedStatus int
 		expectedBody   string
+		expectedETag   string
 	}{
 		{
 			name:  "успешное получение",
@@ -215,9 +236,11 @@ func TestPVZHandler_GetByID(t *testing.T) {
 						ID:        uuid.New(),
 						City:      "Москва",
 						CreatedAt: time.Now(),
+						Version:   5,
 					}, nil)
 			},
 			expectedStatus: http.StatusOK,
+			expectedETag:   `"5"`,
 		},
 		{
 			name:           "неверный формат ID",
@@ -254,11 +277,12 @@ func TestPVZHandler_GetByID(t *testing.T) {
 			r.ServeHTTP(rec, req)
 
 			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.Equal(t, tt.expectedETag, rec.Header().Get("ETag"))
 			if tt.expectedBody != "" {
-				var response map[string]string
+				var response httpresponse.ProblemDetails
 				err := json.NewDecoder(rec.Body).Decode(&response)
 				require.NoError(t, err)
-				assert.Equal(t, tt.expectedBody, response["error"])
+				assert.Equal(t, tt.expectedBody, response.Detail)
 			}
 
 			mockService.AssertExpectations(t)
@@ -357,10 +381,91 @@ func TestPVZHandler_GetWithReceptions(t *testing.T) {
 
 			assert.Equal(t, tt.expectedStatus, rec.Code)
 			if tt.expectedBody != "" {
-				var response map[string]string
+				var response httpresponse.ProblemDetails
 				err := json.NewDecoder(rec.Body).Decode(&response)
 				require.NoError(t, err)
-				assert.Equal(t, tt.expectedBody, response["error"])
+				assert.Equal(t, tt.expectedBody, response.Detail)
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPVZHandler_GetWithReceptionsByCursor(t *testing.T) {
+	startDate := time.Now().Format(time.RFC3339)
+	endDate := time.Now().Add(24 * time.Hour).Format(time.RFC3339)
+
+	tests := []struct {
+		name           string
+		rawQuery       string
+		setupMock      func(*MockPVZService)
+		expectedStatus int
+		expectedCursor string
+		expectedError  string
+	}{
+		{
+			name:     "первая страница с пустым курсором",
+			rawQuery: "cursor=&limit=2",
+			setupMock: func(m *MockPVZService) {
+				m.On("GetWithReceptionsByCursor", mock.Anything, mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time"), "", 2).
+					Return([]*domainPVZ.PVZWithReceptions{}, "next-page", nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedCursor: "next-page",
+		},
+		{
+			name:     "последняя страница",
+			rawQuery: "cursor=abc",
+			setupMock: func(m *MockPVZService) {
+				m.On("GetWithReceptionsByCursor", mock.Anything, mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time"), "abc", 10).
+					Return([]*domainPVZ.PVZWithReceptions{}, "", nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedCursor: "",
+		},
+		{
+			name:     "неверный курсор",
+			rawQuery: "cursor=broken",
+			setupMock: func(m *MockPVZService) {
+				m.On("GetWithReceptionsByCursor", mock.Anything, mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time"), "broken", 10).
+					Return(nil, "", servicePVZ.ErrInvalidCursor)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "неверный формат курсора",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockPVZService)
+			tt.setupMock(mockService)
+
+			handler := NewPVZHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodGet, "/pvz?"+tt.rawQuery, nil)
+			q := req.URL.Query()
+			q.Set("start_date", startDate)
+			q.Set("end_date", endDate)
+			req.URL.RawQuery = q.Encode()
+
+			rec := httptest.NewRecorder()
+
+			handler.GetWithReceptions(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedError != "" {
+				var response httpresponse.ProblemDetails
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+				assert.Equal(t, tt.expectedError, response.Detail)
+			} else {
+				var response struct {
+					Items      []json.RawMessage `json:"items"`
+					NextCursor string            `json:"next_cursor"`
+				}
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+				assert.NotNil(t, response.Items)
+				assert.Equal(t, tt.expectedCursor, response.NextCursor)
 			}
 
 			mockService.AssertExpectations(t)
@@ -373,9 +478,11 @@ func TestPVZHandler_UpdatePVZ(t *testing.T) {
 		name           string
 		pvzID          string
 		requestBody    map[string]interface{}
+		ifMatch        string
 		moderatorID    uuid.UUID
 		mockSetup      func(*MockPVZService)
 		expectedStatus int
+		expectedETag   string
 	}{
 		{
 			name:  "успешное обновление ПВЗ",
@@ -423,6 +530,50 @@ func TestPVZHandler_UpdatePVZ(t *testing.T) {
 			},
 			expectedStatus: http.StatusUnauthorized,
 		},
+		{
+			name:  "обновление с If-Match",
+			pvzID: uuid.New().String(),
+			requestBody: map[string]interface{}{
+				"city": "Казань",
+			},
+			ifMatch:     `"3"`,
+			moderatorID: uuid.New(),
+			mockSetup: func(m *MockPVZService) {
+				m.On("Update", mock.Anything, mock.MatchedBy(func(p *domainPVZ.PVZ) bool {
+					return p.Version == 3
+				}), mock.Anything).Run(func(args mock.Arguments) {
+					args.Get(1).(*domainPVZ.PVZ).Version = 4
+				}).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedETag:   `"4"`,
+		},
+		{
+			name:  "устаревший ETag",
+			pvzID: uuid.New().String(),
+			requestBody: map[string]interface{}{
+				"city": "Казань",
+			},
+			ifMatch:     `"2"`,
+			moderatorID: uuid.New(),
+			mockSetup: func(m *MockPVZService) {
+				m.On("Update", mock.Anything, mock.Anything, mock.Anything).Return(servicePVZ.ErrVersionConflict)
+			},
+			expectedStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name:  "слабый ETag в If-Match",
+			pvzID: uuid.New().String(),
+			requestBody: map[string]interface{}{
+				"city": "Казань",
+			},
+			ifMatch:     `W/"2"`,
+			moderatorID: uuid.New(),
+			mockSetup: func(m *MockPVZService) {
+				// Мок не нужен, так как до вызова сервиса не дойдет
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
 	}
 
 	for _, tt := range tests {
@@ -437,6 +588,9 @@ func TestPVZHandler_UpdatePVZ(t *testing.T) {
 
 			req := httptest.NewRequest(http.MethodPut, "/pvz/"+tt.pvzID, bytes.NewReader(body))
 			req.Header.Set("Content-Type", "application/json")
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
 
 			// Устанавливаем контекст с ID пользователя
 			ctx := context.Background()
@@ -455,6 +609,7 @@ func TestPVZHandler_UpdatePVZ(t *testing.T) {
 			handler.UpdatePVZ(rr, req)
 
 			assert.Equal(t, tt.expectedStatus, rr.Code)
+			assert.Equal(t, tt.expectedETag, rr.Header().Get("ETag"))
 			mockService.AssertExpectations(t)
 		})
 	}
@@ -561,7 +716,7 @@ func TestPVZHandler_ListPVZ(t *testing.T) {
 						City:      "Санкт-Петербург",
 					},
 				}
-				m.On("List", mock.Anything, 0, 10).Return(pvzList, nil)
+				m.On("ListByFilter", mock.Anything, domainPVZ.ListFilter{Page: listing.Page{Offset: 0, Limit: 10}}).Return(pvzList, nil)
 			},
 			expectedStatus: http.StatusOK,
 			expectedBody: []map[string]interface{}{
@@ -581,19 +736,49 @@ func TestPVZHandler_ListPVZ(t *testing.T) {
 			name:        "получение списка с неверными параметрами",
 			queryParams: "?offset=invalid&limit=invalid",
 			mockSetup: func(m *MockPVZService) {
-				m.On("List", mock.Anything, 0, 10).Return([]*domainPVZ.PVZ{}, nil)
+				m.On("ListByFilter", mock.Anything, domainPVZ.ListFilter{Page: listing.Page{Offset: 0, Limit: 10}}).Return([]*domainPVZ.PVZ{}, nil)
 			},
 			expectedStatus: http.StatusOK,
 			expectedBody:   []map[string]interface{}{},
 		},
+		{
+			name:        "фильтр по городу с сортировкой",
+			queryParams: "?city=Москва&sort=-city&from=2024-01-01T00:00:00Z",
+			mockSetup: func(m *MockPVZService) {
+				m.On("ListByFilter", mock.Anything, domainPVZ.ListFilter{
+					City:      "Москва",
+					CreatedAt: listing.DateRange{From: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+					Sort:      listing.Sort{Field: "city", Direction: listing.DirectionDesc},
+					Page:      listing.Page{Offset: 0, Limit: 10},
+				}).Return([]*domainPVZ.PVZ{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   []map[string]interface{}{},
+		},
+		{
+			name:           "неверный формат даты",
+			queryParams:    "?from=yesterday",
+			mockSetup:      func(m *MockPVZService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "неверный формат даты",
+		},
+		{
+			name:        "неизвестное поле сортировки",
+			queryParams: "?sort=password",
+			mockSetup: func(m *MockPVZService) {
+				m.On("ListByFilter", mock.Anything, mock.Anything).Return(nil, listing.ErrInvalidSort)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "неверное поле сортировки",
+		},
 		{
 			name:        "ошибка сервиса",
 			queryParams: "?offset=0&limit=10",
 			mockSetup: func(m *MockPVZService) {
-				m.On("List", mock.Anything, 0, 10).Return(nil, errors.New("internal error"))
+				m.On("ListByFilter", mock.Anything, domainPVZ.ListFilter{Page: listing.Page{Offset: 0, Limit: 10}}).Return(nil, errors.New("internal error"))
 			},
 			expectedStatus: http.StatusInternalServerError,
-			expectedBody:   "internal error",
+			expectedBody:   "ошибка при получении списка ПВЗ",
 		},
 	}
 
@@ -633,3 +818,44 @@ func TestPVZHandler_ListPVZ(t *testing.T) {
 		})
 	}
 }
+
+// withRolePermissions добавляет в запрос пользователя с ролью и разрешениями
+// этой роли по политике по умолчанию, как после middleware.Authenticate
+func withRolePermissions(req *http.Request, role domainUser.Role) *http.Request {
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, uuid.New().String())
+	ctx = context.WithValue(ctx, middleware.UserRoleKey, role)
+	ctx = rbac.WithPermissions(ctx, testAuthorizer.Permissions(role))
+	return req.WithContext(ctx)
+}
+
+func TestPVZHandler_RegisterRoutes(t *testing.T) {
+	r := chi.NewRouter()
+	NewPVZHandler(new(MockPVZService)).RegisterRoutes(r)
+
+	tests := []struct {
+		name           string
+		method, path   string
+		role           domainUser.Role
+		expectedStatus int
+	}{
+		{name: "анонимное создание", method: http.MethodPost, path: "/pvz", expectedStatus: http.StatusUnauthorized},
+		{name: "анонимный список", method: http.MethodGet, path: "/pvz", expectedStatus: http.StatusUnauthorized},
+		{name: "анонимное получение", method: http.MethodGet, path: "/pvz/" + uuid.New().String(), expectedStatus: http.StatusUnauthorized},
+		{name: "анонимное изменение", method: http.MethodPut, path: "/pvz/" + uuid.New().String(), expectedStatus: http.StatusUnauthorized},
+		{name: "создание сотрудником", method: http.MethodPost, path: "/pvz", role: domainUser.RoleEmployee, expectedStatus: http.StatusForbidden},
+		{name: "изменение сотрудником", method: http.MethodPut, path: "/pvz/" + uuid.New().String(), role: domainUser.RoleEmployee, expectedStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.role != "" {
+				req = withRolePermissions(req, tt.role)
+			}
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"net/http"
 
 	"github.com/avito/pvz/internal/domain/product"
+	"github.com/avito/pvz/internal/handler/apperror"
+	productService "github.com/avito/pvz/internal/service/product"
 	"github.com/avito/pvz/internal/service/reception"
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
@@ -24,19 +26,19 @@ func createReception(service *reception.Service) http.HandlerFunc {
 		}
 
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			apperror.WriteInvalidRequest(w, r, "invalid_body")
 			return
 		}
 
 		pvzID, err := uuid.Parse(req.PVZID)
 		if err != nil {
-			http.Error(w, "Invalid PVZ ID", http.StatusBadRequest)
+			apperror.WriteInvalidRequest(w, r, "invalid_pvz_id")
 			return
 		}
 
 		rec, err := service.Create(r.Context(), pvzID)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			apperror.WriteHTTP(w, r, err, "reception_create_failed")
 			return
 		}
 
@@ -50,13 +52,13 @@ func getReception(service *reception.Service) http.HandlerFunc {
 		vars := mux.Vars(r)
 		id, err := uuid.Parse(vars["id"])
 		if err != nil {
-			http.Error(w, "Invalid reception ID", http.StatusBadRequest)
+			apperror.WriteInvalidRequest(w, r, "invalid_reception_id")
 			return
 		}
 
 		rec, err := service.GetByID(r.Context(), id)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			apperror.WriteHTTP(w, r, err, "reception_get_failed")
 			return
 		}
 
@@ -70,7 +72,7 @@ func addProduct(service *reception.Service) http.HandlerFunc {
 		vars := mux.Vars(r)
 		receptionID, err := uuid.Parse(vars["id"])
 		if err != nil {
-			http.Error(w, "Invalid reception ID", http.StatusBadRequest)
+			apperror.WriteInvalidRequest(w, r, "invalid_reception_id")
 			return
 		}
 
@@ -79,7 +81,7 @@ func addProduct(service *reception.Service) http.HandlerFunc {
 		}
 
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			apperror.WriteInvalidRequest(w, r, "invalid_body")
 			return
 		}
 
@@ -90,13 +92,13 @@ func addProduct(service *reception.Service) http.HandlerFunc {
 			string(product.TypeFood),
 			string(product.TypeOther):
 		default:
-			http.Error(w, "Invalid product type", http.StatusBadRequest)
+			apperror.WriteHTTP(w, r, productService.ErrInvalidProductType, "")
 			return
 		}
 
 		err = service.CreateProduct(r.Context(), receptionID, req.Type)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			apperror.WriteHTTP(w, r, err, "product_create_failed")
 			return
 		}
 
@@ -3,9 +3,12 @@ package http
 import (
 	"encoding/json"
 	"net/http"
-	"strconv"
 
-	receptionService "github.com/avito/pvz/internal/service/reception"
+	"github.com/avito/pvz/internal/domain/listing"
+	"github.com/avito/pvz/internal/domain/rbac"
+	domainReception "github.com/avito/pvz/internal/domain/reception"
+	"github.com/avito/pvz/internal/handler/apperror"
+	"github.com/avito/pvz/internal/handler/http/middleware"
 	"github.com/avito/pvz/pkg/httpresponse"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -23,11 +26,17 @@ func NewReceptionHandler(service ReceptionServiceInterface) *ReceptionHandler {
 	}
 }
 
-// RegisterRoutes регистрирует маршруты для приемок
+// RegisterRoutes регистрирует маршруты для приемок, доступные только
+// аутентифицированным пользователям. Изменения требуют разрешений роли.
 func (h *ReceptionHandler) RegisterRoutes(r chi.Router) {
-	r.Post("/reception", h.Create)
-	r.Get("/reception/{id}", h.GetByID)
-	r.Post("/reception/close", h.Close)
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.AuthMiddleware)
+
+		r.With(middleware.RequirePermission(rbac.ReceptionCreate)).Post("/reception", h.Create)
+		r.Get("/reception", h.ListReceptions)
+		r.Get("/reception/{id}", h.GetByID)
+		r.With(middleware.RequirePermission(rbac.ReceptionClose)).Post("/reception/close", h.Close)
+	})
 }
 
 // Create обрабатывает создание приемки
@@ -37,20 +46,13 @@ func (h *ReceptionHandler) Create(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		httpresponse.Error(w, http.StatusBadRequest, "неверный формат запроса")
+		apperror.WriteInvalidRequest(w, r, "invalid_body")
 		return
 	}
 
 	reception, err := h.service.Create(r.Context(), req.PVZID)
 	if err != nil {
-		switch err {
-		case receptionService.ErrPVZNotFound:
-			httpresponse.Error(w, http.StatusNotFound, "ПВЗ не найден")
-		case receptionService.ErrReceptionAlreadyOpen:
-			httpresponse.Error(w, http.StatusBadRequest, "у ПВЗ уже есть открытая приемка")
-		default:
-			httpresponse.Error(w, http.StatusInternalServerError, "ошибка при создании приемки")
-		}
+		apperror.WriteHTTP(w, r, err, "reception_create_failed")
 		return
 	}
 
@@ -61,16 +63,17 @@ func (h *ReceptionHandler) Create(w http.ResponseWriter, r *http.Request) {
 func (h *ReceptionHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	id, err := uuid.Parse(chi.URLParam(r, "id"))
 	if err != nil {
-		httpresponse.Error(w, http.StatusBadRequest, "неверный формат ID приемки")
+		apperror.WriteInvalidRequest(w, r, "invalid_reception_id")
 		return
 	}
 
 	reception, err := h.service.GetByID(r.Context(), id)
 	if err != nil {
-		httpresponse.Error(w, http.StatusNotFound, "приемка не найдена")
+		apperror.WriteHTTP(w, r, err, "reception_get_failed")
 		return
 	}
 
+	setETag(w, reception.Version)
 	httpresponse.JSON(w, http.StatusOK, reception)
 }
 
@@ -81,27 +84,18 @@ func (h *ReceptionHandler) Close(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		httpresponse.Error(w, http.StatusBadRequest, "неверный формат запроса")
+		apperror.WriteInvalidRequest(w, r, "invalid_body")
 		return
 	}
 
 	pvzID, err := uuid.Parse(req.PVZID)
 	if err != nil {
-		httpresponse.Error(w, http.StatusBadRequest, "неверный формат ID ПВЗ")
+		apperror.WriteInvalidRequest(w, r, "invalid_pvz_id")
 		return
 	}
 
 	if err := h.service.Close(r.Context(), pvzID); err != nil {
-		switch err {
-		case receptionService.ErrPVZNotFound:
-			httpresponse.Error(w, http.StatusNotFound, "ПВЗ не найден")
-		case receptionService.ErrReceptionNotFound:
-			httpresponse.Error(w, http.StatusNotFound, "приемка не найдена")
-		case receptionService.ErrReceptionAlreadyClose:
-			httpresponse.Error(w, http.StatusBadRequest, "приемка уже закрыта")
-		default:
-			httpresponse.Error(w, http.StatusInternalServerError, "ошибка при закрытии приемки")
-		}
+		apperror.WriteHTTP(w, r, err, "reception_close_failed")
 		return
 	}
 
@@ -113,13 +107,13 @@ func (h *ReceptionHandler) GetReception(w http.ResponseWriter, r *http.Request)
 	id := chi.URLParam(r, "id")
 	receptionID, err := uuid.Parse(id)
 	if err != nil {
-		http.Error(w, "invalid reception ID", http.StatusBadRequest)
+		apperror.WriteInvalidRequest(w, r, "invalid_reception_id")
 		return
 	}
 
 	reception, err := h.service.GetByID(r.Context(), receptionID)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusNotFound)
+		apperror.WriteHTTP(w, r, err, "reception_get_failed")
 		return
 	}
 
@@ -144,13 +138,13 @@ func (h *ReceptionHandler) GetOpenReception(w http.ResponseWriter, r *http.Reque
 	pvzID := chi.URLParam(r, "pvz_id")
 	pvzUUID, err := uuid.Parse(pvzID)
 	if err != nil {
-		http.Error(w, "invalid PVZ ID", http.StatusBadRequest)
+		apperror.WriteInvalidRequest(w, r, "invalid_pvz_id")
 		return
 	}
 
 	reception, err := h.service.GetOpenByPVZID(r.Context(), pvzUUID)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusNotFound)
+		apperror.WriteHTTP(w, r, err, "open_reception_get_failed")
 		return
 	}
 
@@ -170,18 +164,43 @@ func (h *ReceptionHandler) GetOpenReception(w http.ResponseWriter, r *http.Reque
 	json.NewEncoder(w).Encode(response)
 }
 
-// ListReceptions возвращает список приемок
+// ListReceptions возвращает список приемок.
+// Поддерживает фильтры pvz_id, status, from и to и сортировку
+// sort=date_time|status, по убыванию с префиксом "-".
 func (h *ReceptionHandler) ListReceptions(w http.ResponseWriter, r *http.Request) {
-	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
-	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	q := r.URL.Query()
+
+	var pvzID uuid.UUID
+	if id := q.Get("pvz_id"); id != "" {
+		parsed, err := uuid.Parse(id)
+		if err != nil {
+			apperror.WriteInvalidRequest(w, r, "invalid_pvz_id")
+			return
+		}
+		pvzID = parsed
+	}
+
+	sort, err := listing.ParseSort(q.Get("sort"))
+	if err != nil {
+		apperror.WriteHTTP(w, r, err, "invalid_sort")
+		return
+	}
 
-	if limit <= 0 {
-		limit = 10
+	dateTime, err := parseListDateRange(q)
+	if err != nil {
+		apperror.WriteInvalidRequest(w, r, "invalid_date")
+		return
 	}
 
-	receptions, err := h.service.List(r.Context(), offset, limit)
+	receptions, err := h.service.ListByFilter(r.Context(), domainReception.ListFilter{
+		PVZID:    pvzID,
+		Status:   domainReception.Status(q.Get("status")),
+		DateTime: dateTime,
+		Sort:     sort,
+		Page:     parseListPage(q),
+	})
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		apperror.WriteHTTP(w, r, err, "reception_list_failed")
 		return
 	}
 
@@ -9,8 +9,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/avito/pvz/internal/domain/listing"
 	"github.com/avito/pvz/internal/domain/reception"
+	domainUser "github.com/avito/pvz/internal/domain/user"
 	receptionService "github.com/avito/pvz/internal/service/reception"
+	"github.com/avito/pvz/pkg/httpresponse"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -59,6 +62,14 @@ func (m *mockReceptionService) List(ctx context.Context, offset, limit int) ([]*
 	return args.Get(0).([]*reception.Reception), args.Error(1)
 }
 
+func (m *mockReceptionService) ListByFilter(ctx context.Context, filter reception.ListFilter) ([]*reception.Reception, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*reception.Reception), args.Error(1)
+}
+
 func TestReceptionHandler_Create(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -136,10 +147,10 @@ func TestReceptionHandler_Create(t *testing.T) {
 
 			assert.Equal(t, tt.expectedStatus, rec.Code)
 			if tt.expectedBody != "" {
-				var response map[string]string
+				var response httpresponse.ProblemDetails
 				err := json.NewDecoder(rec.Body).Decode(&response)
 				require.NoError(t, err)
-				assert.Equal(t, tt.expectedBody, response["error"])
+				assert.Equal(t, tt.expectedBody, response.Detail)
 			}
 
 			service.AssertExpectations(t)
@@ -154,6 +165,7 @@ func TestReceptionHandler_GetByID(t *testing.T) {
 		setupMocks     func(*mockReceptionService)
 		expectedStatus int
 		expectedBody   string
+		expectedETag   string
 	}{
 		{
 			name:        "успешное получение",
@@ -166,9 +178,11 @@ func TestReceptionHandler_GetByID(t *testing.T) {
 						PVZID:    uuid.New(),
 						DateTime: time.Now(),
 						Status:   reception.StatusInProgress,
+						Version:  2,
 					}, nil)
 			},
 			expectedStatus: http.StatusOK,
+			expectedETag:   `"2"`,
 		},
 		{
 			name:           "неверный формат ID",
@@ -205,11 +219,12 @@ func TestReceptionHandler_GetByID(t *testing.T) {
 			handler.GetByID(rec, req)
 
 			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.Equal(t, tt.expectedETag, rec.Header().Get("ETag"))
 			if tt.expectedBody != "" {
-				var response map[string]string
+				var response httpresponse.ProblemDetails
 				err := json.NewDecoder(rec.Body).Decode(&response)
 				require.NoError(t, err)
-				assert.Equal(t, tt.expectedBody, response["error"])
+				assert.Equal(t, tt.expectedBody, response.Detail)
 			}
 
 			service.AssertExpectations(t)
@@ -281,6 +296,18 @@ func TestReceptionHandler_Close(t *testing.T) {
 			expectedStatus: http.StatusBadRequest,
 			expectedBody:   "приемка уже закрыта",
 		},
+		{
+			name: "приемку одновременно изменили",
+			requestBody: map[string]interface{}{
+				"pvz_id": uuid.New().String(),
+			},
+			setupMocks: func(rs *mockReceptionService) {
+				rs.On("Close", mock.Anything, mock.AnythingOfType("uuid.UUID")).
+					Return(receptionService.ErrVersionConflict)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   "приемка изменена другим запросом",
+		},
 	}
 
 	for _, tt := range tests {
@@ -300,10 +327,10 @@ func TestReceptionHandler_Close(t *testing.T) {
 
 			assert.Equal(t, tt.expectedStatus, rec.Code)
 			if tt.expectedBody != "" {
-				var response map[string]string
+				var response httpresponse.ProblemDetails
 				err := json.NewDecoder(rec.Body).Decode(&response)
 				require.NoError(t, err)
-				assert.Equal(t, tt.expectedBody, response["error"])
+				assert.Equal(t, tt.expectedBody, response.Detail)
 			}
 
 			service.AssertExpectations(t)
@@ -312,6 +339,8 @@ func TestReceptionHandler_Close(t *testing.T) {
 }
 
 func TestReceptionHandler_ListReceptions(t *testing.T) {
+	pvzID := uuid.New()
+
 	tests := []struct {
 		name           string
 		queryParams    map[string]string
@@ -326,7 +355,7 @@ func TestReceptionHandler_ListReceptions(t *testing.T) {
 				"limit":  "10",
 			},
 			setupMocks: func(rs *mockReceptionService) {
-				rs.On("List", mock.Anything, 0, 10).
+				rs.On("ListByFilter", mock.Anything, reception.ListFilter{Page: listing.Page{Offset: 0, Limit: 10}}).
 					Return([]*reception.Reception{
 						{
 							ID:       uuid.New(),
@@ -341,11 +370,48 @@ func TestReceptionHandler_ListReceptions(t *testing.T) {
 		{
 			name: "без параметров пагинации",
 			setupMocks: func(rs *mockReceptionService) {
-				rs.On("List", mock.Anything, 0, 10).
+				rs.On("ListByFilter", mock.Anything, reception.ListFilter{Page: listing.Page{Offset: 0, Limit: 10}}).
 					Return([]*reception.Reception{}, nil)
 			},
 			expectedStatus: http.StatusOK,
 		},
+		{
+			name: "фильтр по ПВЗ и статусу",
+			queryParams: map[string]string{
+				"pvz_id": pvzID.String(),
+				"status": "close",
+				"sort":   "date_time",
+			},
+			setupMocks: func(rs *mockReceptionService) {
+				rs.On("ListByFilter", mock.Anything, reception.ListFilter{
+					PVZID:  pvzID,
+					Status: reception.StatusClose,
+					Sort:   listing.Sort{Field: "date_time", Direction: listing.DirectionAsc},
+					Page:   listing.Page{Offset: 0, Limit: 10},
+				}).Return([]*reception.Reception{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "неверный формат ID ПВЗ",
+			queryParams: map[string]string{
+				"pvz_id": "invalid",
+			},
+			setupMocks:     func(rs *mockReceptionService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "неверный формат ID ПВЗ",
+		},
+		{
+			name: "неверный статус",
+			queryParams: map[string]string{
+				"status": "unknown",
+			},
+			setupMocks: func(rs *mockReceptionService) {
+				rs.On("ListByFilter", mock.Anything, mock.Anything).Return(nil, listing.ErrInvalidFilter)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "неверное значение фильтра",
+		},
 		{
 			name: "ошибка сервиса",
 			queryParams: map[string]string{
@@ -353,7 +419,7 @@ func TestReceptionHandler_ListReceptions(t *testing.T) {
 				"limit":  "10",
 			},
 			setupMocks: func(rs *mockReceptionService) {
-				rs.On("List", mock.Anything, 0, 10).
+				rs.On("ListByFilter", mock.Anything, reception.ListFilter{Page: listing.Page{Offset: 0, Limit: 10}}).
 					Return(nil, assert.AnError)
 			},
 			expectedStatus: http.StatusInternalServerError,
@@ -382,13 +448,46 @@ func TestReceptionHandler_ListReceptions(t *testing.T) {
 
 			assert.Equal(t, tt.expectedStatus, rec.Code)
 			if tt.expectedBody != "" {
-				var response map[string]string
+				var response httpresponse.ProblemDetails
 				err := json.NewDecoder(rec.Body).Decode(&response)
 				require.NoError(t, err)
-				assert.Equal(t, tt.expectedBody, response["error"])
+				assert.Equal(t, tt.expectedBody, response.Detail)
 			}
 
 			service.AssertExpectations(t)
 		})
 	}
 }
+
+func TestReceptionHandler_RegisterRoutes(t *testing.T) {
+	r := chi.NewRouter()
+	NewReceptionHandler(new(mockReceptionService)).RegisterRoutes(r)
+
+	tests := []struct {
+		name           string
+		method, path   string
+		role           domainUser.Role
+		expectedStatus int
+	}{
+		{name: "анонимное создание", method: http.MethodPost, path: "/reception", expectedStatus: http.StatusUnauthorized},
+		{name: "анонимный список", method: http.MethodGet, path: "/reception", expectedStatus: http.StatusUnauthorized},
+		{name: "анонимное получение", method: http.MethodGet, path: "/reception/" + uuid.New().String(), expectedStatus: http.StatusUnauthorized},
+		{name: "анонимное закрытие", method: http.MethodPost, path: "/reception/close", expectedStatus: http.StatusUnauthorized},
+		// У модератора есть pvz:access_all, но не reception:create и reception:close
+		{name: "создание модератором", method: http.MethodPost, path: "/reception", role: domainUser.RoleAdmin, expectedStatus: http.StatusForbidden},
+		{name: "закрытие модератором", method: http.MethodPost, path: "/reception/close", role: domainUser.RoleAdmin, expectedStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.role != "" {
+				req = withRolePermissions(req, tt.role)
+			}
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
@@ -14,4 +14,5 @@ type ReceptionServiceInterface interface {
 	Close(ctx context.Context, pvzID uuid.UUID) error
 	GetOpenByPVZID(ctx context.Context, pvzID uuid.UUID) (*reception.Reception, error)
 	List(ctx context.Context, offset, limit int) ([]*reception.Reception, error)
+	ListByFilter(ctx context.Context, filter reception.ListFilter) ([]*reception.Reception, error)
 }
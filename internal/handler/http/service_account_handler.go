@@ -0,0 +1,241 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/apikey"
+	"github.com/avito/pvz/internal/domain/rbac"
+	"github.com/avito/pvz/internal/handler/apperror"
+	"github.com/avito/pvz/internal/handler/http/middleware"
+	"github.com/avito/pvz/pkg/httpresponse"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// ServiceAccountServiceInterface определяет интерфейс для сервиса сервисных аккаунтов и ключей API
+type ServiceAccountServiceInterface interface {
+	CreateAccount(ctx context.Context, name, description string, createdBy uuid.UUID) (*apikey.ServiceAccount, error)
+	ListAccounts(ctx context.Context) ([]*apikey.ServiceAccount, error)
+	DeleteAccount(ctx context.Context, id uuid.UUID) error
+	CreateKey(ctx context.Context, accountID uuid.UUID, name string, scopes []rbac.Permission, expiresAt *time.Time, createdBy uuid.UUID) (*apikey.Key, string, error)
+	ListKeys(ctx context.Context, accountID uuid.UUID) ([]*apikey.Key, error)
+	RevokeKey(ctx context.Context, accountID, keyID uuid.UUID) error
+}
+
+// ServiceAccountHandler обрабатывает HTTP-запросы управления сервисными аккаунтами и их ключами API
+type ServiceAccountHandler struct {
+	service ServiceAccountServiceInterface
+}
+
+// NewServiceAccountHandler создает новый экземпляр ServiceAccountHandler
+func NewServiceAccountHandler(service ServiceAccountServiceInterface) *ServiceAccountHandler {
+	return &ServiceAccountHandler{
+		service: service,
+	}
+}
+
+// RegisterRoutes регистрирует маршруты сервисных аккаунтов, доступные только модераторам
+func (h *ServiceAccountHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.AuthMiddleware)
+		r.Use(middleware.RequirePermission(rbac.ServiceAccountManage))
+
+		r.Post("/service-accounts", h.CreateAccount)
+		r.Get("/service-accounts", h.ListAccounts)
+		r.Delete("/service-accounts/{accountId}", h.DeleteAccount)
+		r.Post("/service-accounts/{accountId}/keys", h.CreateKey)
+		r.Get("/service-accounts/{accountId}/keys", h.ListKeys)
+		r.Delete("/service-accounts/{accountId}/keys/{keyId}", h.RevokeKey)
+	})
+}
+
+// serviceAccountResponse сервисный аккаунт в ответе API
+type serviceAccountResponse struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	CreatedBy   string    `json:"createdBy"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+func newServiceAccountResponse(a *apikey.ServiceAccount) serviceAccountResponse {
+	return serviceAccountResponse{
+		ID:          a.ID.String(),
+		Name:        a.Name,
+		Description: a.Description,
+		CreatedBy:   a.CreatedBy.String(),
+		CreatedAt:   a.CreatedAt,
+	}
+}
+
+// apiKeyResponse ключ API в ответе. Сам ключ отдается только при создании,
+// в списках ключ узнают по префиксу.
+type apiKeyResponse struct {
+	ID         string            `json:"id"`
+	Name       string            `json:"name,omitempty"`
+	Prefix     string            `json:"prefix"`
+	Key        string            `json:"key,omitempty"`
+	Scopes     []rbac.Permission `json:"scopes"`
+	ExpiresAt  time.Time         `json:"expiresAt"`
+	LastUsedAt *time.Time        `json:"lastUsedAt,omitempty"`
+	CreatedBy  string            `json:"createdBy"`
+	CreatedAt  time.Time         `json:"createdAt"`
+	RevokedAt  *time.Time        `json:"revokedAt,omitempty"`
+}
+
+func newAPIKeyResponse(k *apikey.Key) apiKeyResponse {
+	return apiKeyResponse{
+		ID:         k.ID.String(),
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		Scopes:     k.Scopes,
+		ExpiresAt:  k.ExpiresAt,
+		LastUsedAt: k.LastUsedAt,
+		CreatedBy:  k.CreatedBy.String(),
+		CreatedAt:  k.CreatedAt,
+		RevokedAt:  k.RevokedAt,
+	}
+}
+
+// CreateAccount обрабатывает создание сервисного аккаунта
+func (h *ServiceAccountHandler) CreateAccount(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apperror.WriteInvalidRequest(w, r, "invalid_body")
+		return
+	}
+
+	createdBy, err := caller(r)
+	if err != nil {
+		apperror.WriteHTTP(w, r, err, "")
+		return
+	}
+
+	account, err := h.service.CreateAccount(r.Context(), req.Name, req.Description, createdBy)
+	if err != nil {
+		apperror.WriteHTTP(w, r, err, "service_account_create_failed")
+		return
+	}
+
+	httpresponse.JSON(w, http.StatusCreated, newServiceAccountResponse(account))
+}
+
+// ListAccounts обрабатывает получение списка сервисных аккаунтов
+func (h *ServiceAccountHandler) ListAccounts(w http.ResponseWriter, r *http.Request) {
+	accounts, err := h.service.ListAccounts(r.Context())
+	if err != nil {
+		apperror.WriteHTTP(w, r, err, "service_account_list_failed")
+		return
+	}
+
+	resp := make([]serviceAccountResponse, len(accounts))
+	for i, a := range accounts {
+		resp[i] = newServiceAccountResponse(a)
+	}
+
+	httpresponse.JSON(w, http.StatusOK, resp)
+}
+
+// DeleteAccount обрабатывает удаление сервисного аккаунта вместе с его ключами
+func (h *ServiceAccountHandler) DeleteAccount(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "accountId"))
+	if err != nil {
+		apperror.WriteInvalidRequest(w, r, "invalid_service_account_id")
+		return
+	}
+
+	if err := h.service.DeleteAccount(r.Context(), id); err != nil {
+		apperror.WriteHTTP(w, r, err, "service_account_delete_failed")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CreateKey обрабатывает выпуск ключа API. Ключ возвращается в ответе
+// один раз и больше нигде не хранится.
+func (h *ServiceAccountHandler) CreateKey(w http.ResponseWriter, r *http.Request) {
+	accountID, err := uuid.Parse(chi.URLParam(r, "accountId"))
+	if err != nil {
+		apperror.WriteInvalidRequest(w, r, "invalid_service_account_id")
+		return
+	}
+
+	var req struct {
+		Name      string            `json:"name"`
+		Scopes    []rbac.Permission `json:"scopes"`
+		ExpiresAt *time.Time        `json:"expiresAt"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apperror.WriteInvalidRequest(w, r, "invalid_body")
+		return
+	}
+
+	createdBy, err := caller(r)
+	if err != nil {
+		apperror.WriteHTTP(w, r, err, "")
+		return
+	}
+
+	key, raw, err := h.service.CreateKey(r.Context(), accountID, req.Name, req.Scopes, req.ExpiresAt, createdBy)
+	if err != nil {
+		apperror.WriteHTTP(w, r, err, "api_key_create_failed")
+		return
+	}
+
+	resp := newAPIKeyResponse(key)
+	resp.Key = raw
+	httpresponse.NoStore(w)
+	httpresponse.JSON(w, http.StatusCreated, resp)
+}
+
+// ListKeys обрабатывает получение ключей сервисного аккаунта
+func (h *ServiceAccountHandler) ListKeys(w http.ResponseWriter, r *http.Request) {
+	accountID, err := uuid.Parse(chi.URLParam(r, "accountId"))
+	if err != nil {
+		apperror.WriteInvalidRequest(w, r, "invalid_service_account_id")
+		return
+	}
+
+	keys, err := h.service.ListKeys(r.Context(), accountID)
+	if err != nil {
+		apperror.WriteHTTP(w, r, err, "api_key_list_failed")
+		return
+	}
+
+	resp := make([]apiKeyResponse, len(keys))
+	for i, k := range keys {
+		resp[i] = newAPIKeyResponse(k)
+	}
+
+	httpresponse.JSON(w, http.StatusOK, resp)
+}
+
+// RevokeKey обрабатывает отзыв ключа API
+func (h *ServiceAccountHandler) RevokeKey(w http.ResponseWriter, r *http.Request) {
+	accountID, err := uuid.Parse(chi.URLParam(r, "accountId"))
+	if err != nil {
+		apperror.WriteInvalidRequest(w, r, "invalid_service_account_id")
+		return
+	}
+	keyID, err := uuid.Parse(chi.URLParam(r, "keyId"))
+	if err != nil {
+		apperror.WriteInvalidRequest(w, r, "invalid_api_key_id")
+		return
+	}
+
+	if err := h.service.RevokeKey(r.Context(), accountID, keyID); err != nil {
+		apperror.WriteHTTP(w, r, err, "api_key_revoke_failed")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,263 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/apikey"
+	"github.com/avito/pvz/internal/domain/rbac"
+	"github.com/avito/pvz/internal/domain/user"
+	"github.com/avito/pvz/internal/handler/apperror"
+	"github.com/avito/pvz/internal/handler/http/middleware"
+	apikeyService "github.com/avito/pvz/internal/service/apikey"
+	"github.com/avito/pvz/pkg/httpresponse"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockServiceAccountService struct {
+	mock.Mock
+}
+
+func (m *mockServiceAccountService) CreateAccount(ctx context.Context, name, description string, createdBy uuid.UUID) (*apikey.ServiceAccount, error) {
+	args := m.Called(ctx, name, description, createdBy)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*apikey.ServiceAccount), args.Error(1)
+}
+
+func (m *mockServiceAccountService) ListAccounts(ctx context.Context) ([]*apikey.ServiceAccount, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*apikey.ServiceAccount), args.Error(1)
+}
+
+func (m *mockServiceAccountService) DeleteAccount(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *mockServiceAccountService) CreateKey(ctx context.Context, accountID uuid.UUID, name string, scopes []rbac.Permission, expiresAt *time.Time, createdBy uuid.UUID) (*apikey.Key, string, error) {
+	args := m.Called(ctx, accountID, name, scopes, expiresAt, createdBy)
+	if args.Get(0) == nil {
+		return nil, "", args.Error(2)
+	}
+	return args.Get(0).(*apikey.Key), args.String(1), args.Error(2)
+}
+
+func (m *mockServiceAccountService) ListKeys(ctx context.Context, accountID uuid.UUID) ([]*apikey.Key, error) {
+	args := m.Called(ctx, accountID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*apikey.Key), args.Error(1)
+}
+
+func (m *mockServiceAccountService) RevokeKey(ctx context.Context, accountID, keyID uuid.UUID) error {
+	args := m.Called(ctx, accountID, keyID)
+	return args.Error(0)
+}
+
+// withModerator добавляет в запрос модератора с ID id
+func withModerator(req *http.Request, id uuid.UUID) *http.Request {
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, id.String())
+	ctx = context.WithValue(ctx, middleware.UserRoleKey, user.RoleAdmin)
+	return req.WithContext(ctx)
+}
+
+func TestServiceAccountHandler_CreateAccount(t *testing.T) {
+	moderatorID := uuid.New()
+	account := &apikey.ServiceAccount{ID: uuid.New(), Name: "warehouse", CreatedBy: moderatorID, CreatedAt: time.Now()}
+
+	t.Run("успешное создание", func(t *testing.T) {
+		service := new(mockServiceAccountService)
+		service.On("CreateAccount", mock.Anything, "warehouse", "", moderatorID).Return(account, nil)
+		handler := NewServiceAccountHandler(service)
+
+		req := withModerator(httptest.NewRequest(http.MethodPost, "/service-accounts", strings.NewReader(`{"name":"warehouse"}`)), moderatorID)
+		rec := httptest.NewRecorder()
+
+		handler.CreateAccount(rec, req)
+
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		var resp serviceAccountResponse
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+		assert.Equal(t, account.ID.String(), resp.ID)
+		service.AssertExpectations(t)
+	})
+
+	t.Run("пустое имя", func(t *testing.T) {
+		service := new(mockServiceAccountService)
+		service.On("CreateAccount", mock.Anything, "", "", moderatorID).Return(nil, apikeyService.ErrInvalidName)
+		handler := NewServiceAccountHandler(service)
+
+		req := withModerator(httptest.NewRequest(http.MethodPost, "/service-accounts", strings.NewReader(`{}`)), moderatorID)
+		rec := httptest.NewRecorder()
+
+		handler.CreateAccount(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		var problem httpresponse.ProblemDetails
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&problem))
+		assert.Equal(t, string(apperror.CodeInvalidServiceAccount), problem.Code)
+	})
+}
+
+func TestServiceAccountHandler_CreateKey(t *testing.T) {
+	moderatorID, accountID := uuid.New(), uuid.New()
+	scopes := []rbac.Permission{rbac.ReceptionCreate, rbac.ProductCreate}
+	key := &apikey.Key{
+		ID:               uuid.New(),
+		ServiceAccountID: accountID,
+		Prefix:           "pvz_1a2b3c4d",
+		KeyHash:          "hash",
+		Scopes:           scopes,
+		ExpiresAt:        time.Now().Add(time.Hour),
+		CreatedBy:        moderatorID,
+		CreatedAt:        time.Now(),
+	}
+
+	tests := []struct {
+		name           string
+		accountID      string
+		body           string
+		setupMock      func(*mockServiceAccountService)
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			name:      "успешный выпуск",
+			accountID: accountID.String(),
+			body:      `{"scopes":["reception:create","product:create"]}`,
+			setupMock: func(m *mockServiceAccountService) {
+				m.On("CreateKey", mock.Anything, accountID, "", scopes, (*time.Time)(nil), moderatorID).Return(key, "pvz_1a2b3c4d_secret", nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "неверный ID аккаунта",
+			accountID:      "bad",
+			body:           `{}`,
+			setupMock:      func(m *mockServiceAccountService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   string(apperror.CodeInvalidRequest),
+		},
+		{
+			name:      "неизвестная область",
+			accountID: accountID.String(),
+			body:      `{"scopes":["pvz:destroy"]}`,
+			setupMock: func(m *mockServiceAccountService) {
+				m.On("CreateKey", mock.Anything, accountID, "", []rbac.Permission{"pvz:destroy"}, (*time.Time)(nil), moderatorID).
+					Return(nil, "", apikeyService.ErrInvalidScopes)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   string(apperror.CodeInvalidAPIKeyScopes),
+		},
+		{
+			name:      "аккаунт не найден",
+			accountID: accountID.String(),
+			body:      `{"scopes":["reception:create","product:create"]}`,
+			setupMock: func(m *mockServiceAccountService) {
+				m.On("CreateKey", mock.Anything, accountID, "", scopes, (*time.Time)(nil), moderatorID).
+					Return(nil, "", apikey.ErrAccountNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   string(apperror.CodeServiceAccountNotFound),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(mockServiceAccountService)
+			tt.setupMock(service)
+			handler := NewServiceAccountHandler(service)
+
+			req := httptest.NewRequest(http.MethodPost, "/service-accounts/"+tt.accountID+"/keys", strings.NewReader(tt.body))
+			req = withRouteParam(withModerator(req, moderatorID), "accountId", tt.accountID)
+			rec := httptest.NewRecorder()
+
+			handler.CreateKey(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedCode != "" {
+				var problem httpresponse.ProblemDetails
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&problem))
+				assert.Equal(t, tt.expectedCode, problem.Code)
+			} else {
+				var resp apiKeyResponse
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+				assert.Equal(t, "pvz_1a2b3c4d_secret", resp.Key)
+				assert.Equal(t, "pvz_1a2b3c4d", resp.Prefix)
+				assert.Equal(t, scopes, resp.Scopes)
+			}
+			service.AssertExpectations(t)
+		})
+	}
+}
+
+func TestServiceAccountHandler_ListKeys(t *testing.T) {
+	accountID := uuid.New()
+	service := new(mockServiceAccountService)
+	service.On("ListKeys", mock.Anything, accountID).Return([]*apikey.Key{
+		{ID: uuid.New(), ServiceAccountID: accountID, Prefix: "pvz_1a2b3c4d", KeyHash: "hash", Scopes: []rbac.Permission{rbac.ProductCreate}},
+	}, nil)
+	handler := NewServiceAccountHandler(service)
+
+	req := withRouteParam(httptest.NewRequest(http.MethodGet, "/service-accounts/"+accountID.String()+"/keys", nil), "accountId", accountID.String())
+	rec := httptest.NewRecorder()
+
+	handler.ListKeys(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	// Ключ и его хеш не попадают в список
+	assert.NotContains(t, rec.Body.String(), "hash")
+	var resp []apiKeyResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	require.Len(t, resp, 1)
+	assert.Empty(t, resp[0].Key)
+	assert.Equal(t, "pvz_1a2b3c4d", resp[0].Prefix)
+}
+
+func TestServiceAccountHandler_RevokeKey(t *testing.T) {
+	accountID, keyID := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+	}{
+		{name: "успешный отзыв", err: nil, expectedStatus: http.StatusNoContent},
+		{name: "ключ не найден", err: apikey.ErrKeyNotFound, expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(mockServiceAccountService)
+			service.On("RevokeKey", mock.Anything, accountID, keyID).Return(tt.err)
+			handler := NewServiceAccountHandler(service)
+
+			req := httptest.NewRequest(http.MethodDelete, "/service-accounts/"+accountID.String()+"/keys/"+keyID.String(), nil)
+			chiCtx := chi.NewRouteContext()
+			chiCtx.URLParams.Add("accountId", accountID.String())
+			chiCtx.URLParams.Add("keyId", keyID.String())
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, chiCtx))
+			rec := httptest.NewRecorder()
+
+			handler.RevokeKey(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			service.AssertExpectations(t)
+		})
+	}
+}
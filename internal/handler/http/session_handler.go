@@ -0,0 +1,139 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/avito/pvz/internal/domain/rbac"
+	"github.com/avito/pvz/internal/handler/apperror"
+	"github.com/avito/pvz/internal/handler/http/middleware"
+	sessionService "github.com/avito/pvz/internal/service/session"
+	"github.com/avito/pvz/pkg/httpresponse"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// SessionServiceInterface определяет интерфейс для сервиса сессий
+type SessionServiceInterface interface {
+	Refresh(ctx context.Context, refreshToken string) (*sessionService.Tokens, error)
+	Logout(ctx context.Context, sessionID uuid.UUID) error
+	LogoutAll(ctx context.Context, userID uuid.UUID) error
+}
+
+// SessionHandler обрабатывает HTTP-запросы обновления токенов и выхода
+type SessionHandler struct {
+	service SessionServiceInterface
+}
+
+// NewSessionHandler создает новый экземпляр SessionHandler
+func NewSessionHandler(service SessionServiceInterface) *SessionHandler {
+	return &SessionHandler{
+		service: service,
+	}
+}
+
+// RegisterRoutes регистрирует маршруты сессий
+func (h *SessionHandler) RegisterRoutes(r chi.Router) {
+	r.Post("/token/refresh", h.Refresh)
+
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.AuthMiddleware)
+
+		r.Post("/logout", h.Logout)
+		r.Post("/logout/all", h.LogoutAll)
+	})
+
+	// Модератор завершает сессии сотрудника, например при увольнении
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.AuthMiddleware)
+		r.Use(middleware.RequirePermission(rbac.SessionRevoke))
+
+		r.Post("/user/{id}/logout", h.LogoutUser)
+	})
+}
+
+// tokenResponse пара токенов в ответе на вход и обновление
+type tokenResponse struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+func newTokenResponse(tokens *sessionService.Tokens) tokenResponse {
+	return tokenResponse{
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+	}
+}
+
+// Refresh обменивает токен обновления на новую пару токенов
+func (h *SessionHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apperror.WriteInvalidRequest(w, r, "invalid_body")
+		return
+	}
+	if req.RefreshToken == "" {
+		apperror.WriteInvalidRequest(w, r, "refresh_token_required")
+		return
+	}
+
+	tokens, err := h.service.Refresh(r.Context(), req.RefreshToken)
+	if err != nil {
+		apperror.WriteHTTP(w, r, err, "token_refresh_failed")
+		return
+	}
+
+	httpresponse.NoStore(w)
+	httpresponse.JSON(w, http.StatusOK, newTokenResponse(tokens))
+}
+
+// Logout завершает текущую сессию
+func (h *SessionHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.Logout(r.Context(), middleware.GetSessionID(r.Context())); err != nil {
+		apperror.WriteHTTP(w, r, err, "logout_failed")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// LogoutAll завершает все сессии текущего пользователя
+func (h *SessionHandler) LogoutAll(w http.ResponseWriter, r *http.Request) {
+	userIDStr, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		apperror.WriteHTTP(w, r, apperror.ErrUnauthorized, "")
+		return
+	}
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		apperror.WriteHTTP(w, r, apperror.ErrUnauthorized, "")
+		return
+	}
+
+	if err := h.service.LogoutAll(r.Context(), userID); err != nil {
+		apperror.WriteHTTP(w, r, err, "logout_failed")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// LogoutUser завершает все сессии пользователя по ID
+func (h *SessionHandler) LogoutUser(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		apperror.WriteInvalidRequest(w, r, "invalid_user_id")
+		return
+	}
+
+	if err := h.service.LogoutAll(r.Context(), userID); err != nil {
+		apperror.WriteHTTP(w, r, err, "logout_failed")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,163 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/avito/pvz/internal/handler/apperror"
+	"github.com/avito/pvz/internal/handler/http/middleware"
+	sessionService "github.com/avito/pvz/internal/service/session"
+	"github.com/avito/pvz/pkg/httpresponse"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockSessionService struct {
+	mock.Mock
+}
+
+func (m *mockSessionService) Refresh(ctx context.Context, refreshToken string) (*sessionService.Tokens, error) {
+	args := m.Called(ctx, refreshToken)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*sessionService.Tokens), args.Error(1)
+}
+
+func (m *mockSessionService) Logout(ctx context.Context, sessionID uuid.UUID) error {
+	args := m.Called(ctx, sessionID)
+	return args.Error(0)
+}
+
+func (m *mockSessionService) LogoutAll(ctx context.Context, userID uuid.UUID) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func TestSessionHandler_Refresh(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(*mockSessionService)
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			name: "успешное обновление",
+			body: `{"refresh_token":"old"}`,
+			setupMock: func(m *mockSessionService) {
+				m.On("Refresh", mock.Anything, "old").Return(&sessionService.Tokens{AccessToken: "access", RefreshToken: "new"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "нет токена обновления",
+			body:           `{}`,
+			setupMock:      func(m *mockSessionService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   string(apperror.CodeInvalidRequest),
+		},
+		{
+			name: "недействительный токен",
+			body: `{"refresh_token":"unknown"}`,
+			setupMock: func(m *mockSessionService) {
+				m.On("Refresh", mock.Anything, "unknown").Return(nil, sessionService.ErrInvalidRefreshToken)
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedCode:   string(apperror.CodeInvalidRefreshToken),
+		},
+		{
+			name: "повторное использование",
+			body: `{"refresh_token":"used"}`,
+			setupMock: func(m *mockSessionService) {
+				m.On("Refresh", mock.Anything, "used").Return(nil, sessionService.ErrRefreshTokenReused)
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedCode:   string(apperror.CodeRefreshTokenReused),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(mockSessionService)
+			tt.setupMock(service)
+			handler := NewSessionHandler(service)
+
+			req := httptest.NewRequest(http.MethodPost, "/token/refresh", strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+
+			handler.Refresh(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedCode != "" {
+				var problem httpresponse.ProblemDetails
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&problem))
+				assert.Equal(t, tt.expectedCode, problem.Code)
+			} else {
+				var resp tokenResponse
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+				assert.Equal(t, "access", resp.Token)
+				assert.Equal(t, "new", resp.RefreshToken)
+				assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+			}
+			service.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSessionHandler_Logout(t *testing.T) {
+	sessionID := uuid.New()
+	service := new(mockSessionService)
+	service.On("Logout", mock.Anything, sessionID).Return(nil)
+	handler := NewSessionHandler(service)
+
+	req := httptest.NewRequest(http.MethodPost, "/logout", nil)
+	req = req.WithContext(context.WithValue(req.Context(), middleware.SessionIDKey, sessionID))
+	rec := httptest.NewRecorder()
+
+	handler.Logout(rec, req)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	service.AssertExpectations(t)
+}
+
+func TestSessionHandler_LogoutAll(t *testing.T) {
+	userID := uuid.New()
+	service := new(mockSessionService)
+	service.On("LogoutAll", mock.Anything, userID).Return(nil)
+	handler := NewSessionHandler(service)
+
+	req := httptest.NewRequest(http.MethodPost, "/logout/all", nil)
+	req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, userID.String()))
+	rec := httptest.NewRecorder()
+
+	handler.LogoutAll(rec, req)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	service.AssertExpectations(t)
+}
+
+func TestSessionHandler_LogoutUser(t *testing.T) {
+	userID := uuid.New()
+	service := new(mockSessionService)
+	service.On("LogoutAll", mock.Anything, userID).Return(nil)
+	handler := NewSessionHandler(service)
+
+	req := withRouteParam(httptest.NewRequest(http.MethodPost, "/user/"+userID.String()+"/logout", nil), "id", userID.String())
+	rec := httptest.NewRecorder()
+	handler.LogoutUser(rec, req)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	req = withRouteParam(httptest.NewRequest(http.MethodPost, "/user/bad/logout", nil), "id", "bad")
+	rec = httptest.NewRecorder()
+	handler.LogoutUser(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	service.AssertExpectations(t)
+}
@@ -2,11 +2,12 @@ package http
 
 import (
 	"encoding/json"
-	"errors"
 	"net/http"
 	"strconv"
 
+	"github.com/avito/pvz/internal/domain/rbac"
 	"github.com/avito/pvz/internal/domain/user"
+	"github.com/avito/pvz/internal/handler/apperror"
 	"github.com/avito/pvz/internal/handler/http/middleware"
 	userService "github.com/avito/pvz/internal/service/user"
 	"github.com/go-chi/chi/v5"
@@ -14,7 +15,8 @@ import (
 )
 
 var (
-	ErrInvalidCredentials = errors.New("invalid credentials")
+	// ErrInvalidCredentials неверный email или пароль при входе
+	ErrInvalidCredentials = apperror.ErrInvalidCredentials
 )
 
 type UserHandler struct {
@@ -36,25 +38,14 @@ func (h *UserHandler) RegisterUser(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "invalid request body", http.StatusBadRequest)
+		apperror.WriteInvalidRequest(w, r, "invalid_body")
 		return
 	}
 
 	userRole := user.Role(req.Role)
 	newUser, err := h.service.Register(r.Context(), req.Email, req.Password, userRole)
 	if err != nil {
-		switch err {
-		case userService.ErrInvalidEmail:
-			http.Error(w, "email cannot be empty", http.StatusBadRequest)
-		case userService.ErrInvalidPassword:
-			http.Error(w, "password cannot be empty", http.StatusBadRequest)
-		case userService.ErrInvalidRole:
-			http.Error(w, "invalid role", http.StatusBadRequest)
-		case userService.ErrUserAlreadyExists:
-			http.Error(w, "user already exists", http.StatusConflict)
-		default:
-			http.Error(w, "internal server error", http.StatusInternalServerError)
-		}
+		apperror.WriteHTTP(w, r, err, "register_failed")
 		return
 	}
 
@@ -83,27 +74,13 @@ func (h *UserHandler) LoginUser(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{
-			"error": "неверный формат запроса",
-		})
+		apperror.WriteInvalidRequest(w, r, "invalid_body")
 		return
 	}
 
 	user, err := h.service.Login(r.Context(), req.Email, req.Password)
 	if err != nil {
-		switch err {
-		case userService.ErrUserNotFound, userService.ErrInvalidPassword:
-			w.WriteHeader(http.StatusUnauthorized)
-			json.NewEncoder(w).Encode(map[string]string{
-				"error": "неверные учетные данные",
-			})
-		default:
-			w.WriteHeader(http.StatusInternalServerError)
-			json.NewEncoder(w).Encode(map[string]string{
-				"error": "внутренняя ошибка сервера",
-			})
-		}
+		writeLoginError(w, r, err)
 		return
 	}
 
@@ -121,26 +98,28 @@ func (h *UserHandler) GetUser(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	userID, err := uuid.Parse(id)
 	if err != nil {
-		http.Error(w, "invalid user ID", http.StatusBadRequest)
+		apperror.WriteInvalidRequest(w, r, "invalid_user_id")
 		return
 	}
 
 	u, err := h.service.GetByID(r.Context(), userID)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusNotFound)
+		apperror.WriteHTTP(w, r, err, "user_get_failed")
 		return
 	}
 
 	response := struct {
-		ID        string `json:"id"`
-		Email     string `json:"email"`
-		Role      string `json:"role"`
-		CreatedAt string `json:"created_at"`
+		ID            string `json:"id"`
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"email_verified"`
+		Role          string `json:"role"`
+		CreatedAt     string `json:"created_at"`
 	}{
-		ID:        u.ID.String(),
-		Email:     u.Email,
-		Role:      string(u.Role),
-		CreatedAt: u.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		ID:            u.ID.String(),
+		Email:         u.Email,
+		EmailVerified: u.EmailVerifiedAt != nil,
+		Role:          string(u.Role),
+		CreatedAt:     u.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -152,7 +131,7 @@ func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	userID, err := uuid.Parse(id)
 	if err != nil {
-		http.Error(w, "invalid user ID", http.StatusBadRequest)
+		apperror.WriteInvalidRequest(w, r, "invalid_user_id")
 		return
 	}
 
@@ -162,12 +141,12 @@ func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "invalid request body", http.StatusBadRequest)
+		apperror.WriteInvalidRequest(w, r, "invalid_body")
 		return
 	}
 
 	if req.Email == "" && req.Role == "" {
-		http.Error(w, "invalid request body", http.StatusBadRequest)
+		apperror.WriteInvalidRequest(w, r, "invalid_body")
 		return
 	}
 
@@ -178,7 +157,7 @@ func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.service.Update(r.Context(), u); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		apperror.WriteHTTP(w, r, err, "user_update_failed")
 		return
 	}
 
@@ -190,12 +169,12 @@ func (h *UserHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	userID, err := uuid.Parse(id)
 	if err != nil {
-		http.Error(w, "invalid user ID", http.StatusBadRequest)
+		apperror.WriteInvalidRequest(w, r, "invalid_user_id")
 		return
 	}
 
 	if err := h.service.Delete(r.Context(), userID); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		apperror.WriteHTTP(w, r, err, "user_delete_failed")
 		return
 	}
 
@@ -213,7 +192,7 @@ func (h *UserHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
 
 	users, err := h.service.List(r.Context(), offset, limit)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		apperror.WriteHTTP(w, r, err, "user_list_failed")
 		return
 	}
 
@@ -246,7 +225,7 @@ func (h *UserHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
 func (h *UserHandler) RegisterRoutes(r chi.Router) {
 	r.Group(func(r chi.Router) {
 		r.Use(middleware.AuthMiddleware)
-		r.Use(middleware.RequireRole(user.RoleAdmin))
+		r.Use(middleware.RequirePermission(rbac.UserManage))
 
 		r.Put("/user/{id}", h.UpdateUser)
 		r.Delete("/user/{id}", h.DeleteUser)
@@ -7,9 +7,11 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/avito/pvz/internal/domain/lockout"
 	domainUser "github.com/avito/pvz/internal/domain/user"
 	userService "github.com/avito/pvz/internal/service/user"
 	"github.com/go-chi/chi/v5"
@@ -104,7 +106,7 @@ func TestUserHandler_RegisterUser(t *testing.T) {
 				// Для неверного формата тела запроса мок не нужен, так как до вызова сервиса не дойдет
 			},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   "invalid request body",
+			expectedBody:   "неверный формат запроса",
 		},
 		{
 			name: "пустой email",
@@ -117,7 +119,7 @@ func TestUserHandler_RegisterUser(t *testing.T) {
 				m.On("Register", mock.Anything, "", "testpass", domainUser.Role("moderator")).Return(nil, userService.ErrInvalidEmail)
 			},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   "email cannot be empty",
+			expectedBody:   "неверный формат email",
 		},
 		{
 			name: "пустой пароль",
@@ -130,7 +132,7 @@ func TestUserHandler_RegisterUser(t *testing.T) {
 				m.On("Register", mock.Anything, "test@example.com", "", domainUser.Role("moderator")).Return(nil, userService.ErrInvalidPassword)
 			},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   "password cannot be empty",
+			expectedBody:   "неверный формат пароля",
 		},
 		{
 			name: "неверная роль",
@@ -143,7 +145,7 @@ func TestUserHandler_RegisterUser(t *testing.T) {
 				m.On("Register", mock.Anything, "test@example.com", "testpass", domainUser.Role("invalid")).Return(nil, userService.ErrInvalidRole)
 			},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   "invalid role",
+			expectedBody:   "неверная роль",
 		},
 		{
 			name: "ошибка сервиса",
@@ -156,7 +158,7 @@ func TestUserHandler_RegisterUser(t *testing.T) {
 				m.On("Register", mock.Anything, "test@example.com", "testpass", domainUser.Role("moderator")).Return(nil, errors.New("service error"))
 			},
 			expectedStatus: http.StatusInternalServerError,
-			expectedBody:   "internal server error",
+			expectedBody:   "ошибка при регистрации",
 		},
 	}
 
@@ -246,7 +248,11 @@ func TestUserHandler_LoginUser(t *testing.T) {
 			},
 			expectedStatus: http.StatusUnauthorized,
 			expectedBody: map[string]interface{}{
-				"error": "неверные учетные данные",
+				"type":   "about:blank",
+				"title":  "Unauthorized",
+				"status": float64(http.StatusUnauthorized),
+				"detail": "неверный email или пароль",
+				"code":   "invalid_credentials",
 			},
 		},
 		{
@@ -255,7 +261,11 @@ func TestUserHandler_LoginUser(t *testing.T) {
 			mockSetup:      func(m *MockUserService) {},
 			expectedStatus: http.StatusBadRequest,
 			expectedBody: map[string]interface{}{
-				"error": "неверный формат запроса",
+				"type":   "about:blank",
+				"title":  "Bad Request",
+				"status": float64(http.StatusBadRequest),
+				"detail": "неверный формат запроса",
+				"code":   "invalid_request",
 			},
 		},
 	}
@@ -294,6 +304,24 @@ func TestUserHandler_LoginUser(t *testing.T) {
 			mockService.AssertExpectations(t)
 		})
 	}
+
+	t.Run("вход заблокирован", func(t *testing.T) {
+		mockService := new(MockUserService)
+		locked := &lockout.LockedError{Until: time.Now().Add(90 * time.Second)}
+		mockService.On("Login", mock.Anything, "test@example.com", "password123").Return(nil, locked)
+		handler := NewUserHandler(mockService)
+
+		req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(`{"email":"test@example.com","password":"password123"}`))
+		w := httptest.NewRecorder()
+
+		handler.LoginUser(w, req)
+
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "90", w.Header().Get("Retry-After"))
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "login_locked", response["code"])
+	})
 }
 
 func TestUserHandler_GetUser(t *testing.T) {
@@ -367,6 +395,7 @@ func TestUserHandler_GetUser(t *testing.T) {
 				assert.NoError(t, err)
 				assert.Equal(t, tt.expectedBody["email"], response["email"])
 				assert.Equal(t, tt.expectedBody["role"], response["role"])
+				assert.Equal(t, false, response["email_verified"])
 			}
 		})
 	}
@@ -427,7 +456,7 @@ func TestUserHandler_UpdateUser(t *testing.T) {
 					Role:  domainUser.RoleAdmin,
 				}).Return(userService.ErrUserNotFound)
 			},
-			expectedStatus: http.StatusInternalServerError,
+			expectedStatus: http.StatusNotFound,
 		},
 	}
 
@@ -481,7 +510,7 @@ func TestUserHandler_DeleteUser(t *testing.T) {
 			mockSetup: func(m *MockUserService) {
 				m.On("Delete", mock.Anything, userID).Return(userService.ErrUserNotFound)
 			},
-			expectedStatus: http.StatusInternalServerError,
+			expectedStatus: http.StatusNotFound,
 		},
 	}
 
@@ -0,0 +1,30 @@
+package v2
+
+import (
+	"net/http"
+
+	"github.com/avito/pvz/pkg/httpresponse"
+)
+
+// Envelope конверт успешного ответа
+type Envelope struct {
+	Data interface{} `json:"data"`
+	Meta *Meta       `json:"meta,omitempty"`
+}
+
+// Meta сведения о странице списка
+type Meta struct {
+	Page       int    `json:"page,omitempty"`
+	Limit      int    `json:"limit"`
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// writeData отправляет данные в конверте
+func writeData(w http.ResponseWriter, status int, data interface{}) {
+	httpresponse.JSON(w, status, Envelope{Data: data})
+}
+
+// writeList отправляет страницу списка в конверте
+func writeList(w http.ResponseWriter, data interface{}, meta Meta) {
+	httpresponse.JSON(w, http.StatusOK, Envelope{Data: data, Meta: &meta})
+}
@@ -0,0 +1,83 @@
+// Package v2 содержит HTTP API второй версии.
+// Поля ответов названы так же, как в swagger (registrationDate, pvzId и т.д.),
+// а успешные ответы оборачиваются в конверт с полями data и meta.
+// Обработчики используют те же сервисы, что и первая версия.
+package v2
+
+import (
+	"context"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/product"
+	domainPVZ "github.com/avito/pvz/internal/domain/pvz"
+	"github.com/avito/pvz/internal/domain/rbac"
+	"github.com/avito/pvz/internal/domain/reception"
+	"github.com/avito/pvz/internal/handler/http/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// PVZService определяет методы сервиса ПВЗ, которые использует API
+type PVZService interface {
+	Create(ctx context.Context, city string, userID uuid.UUID) (*domainPVZ.PVZ, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*domainPVZ.PVZ, error)
+	GetWithReceptions(ctx context.Context, startDate, endDate time.Time, page, limit int) ([]*domainPVZ.PVZWithReceptions, error)
+	GetWithReceptionsByCursor(ctx context.Context, startDate, endDate time.Time, cursor string, limit int) ([]*domainPVZ.PVZWithReceptions, string, error)
+}
+
+// ReceptionService определяет методы сервиса приемок, которые использует API
+type ReceptionService interface {
+	Create(ctx context.Context, pvzID uuid.UUID) (*reception.Reception, error)
+	Close(ctx context.Context, pvzID uuid.UUID) error
+	GetByID(ctx context.Context, id uuid.UUID) (*reception.Reception, error)
+	GetOpenByPVZID(ctx context.Context, pvzID uuid.UUID) (*reception.Reception, error)
+}
+
+// ProductService определяет методы сервиса товаров, которые использует API
+type ProductService interface {
+	Create(ctx context.Context, receptionID uuid.UUID, productType product.Type) (*product.Product, error)
+	DeleteLast(ctx context.Context, receptionID uuid.UUID) error
+}
+
+// Handler обрабатывает HTTP-запросы второй версии API
+type Handler struct {
+	pvz       PVZService
+	reception ReceptionService
+	product   ProductService
+}
+
+// New создает новый экземпляр Handler
+func New(pvzService PVZService, receptionService ReceptionService, productService ProductService) *Handler {
+	return &Handler{
+		pvz:       pvzService,
+		reception: receptionService,
+		product:   productService,
+	}
+}
+
+// RegisterRoutes регистрирует маршруты второй версии API
+func (h *Handler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.AuthMiddleware)
+
+		r.Get("/pvz", h.ListPVZ)
+		r.Get("/pvz/{pvzId}", h.GetPVZ)
+		r.Get("/products/types", h.ListProductTypes)
+	})
+
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.AuthMiddleware)
+		r.Use(middleware.RequirePermission(rbac.PVZCreate))
+
+		r.Post("/pvz", h.CreatePVZ)
+	})
+
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.AuthMiddleware)
+
+		r.With(middleware.RequirePermission(rbac.ReceptionCreate)).Post("/receptions", h.CreateReception)
+		r.With(middleware.RequirePermission(rbac.ProductCreate)).Post("/products", h.AddProduct)
+		r.With(middleware.RequirePermission(rbac.ReceptionClose)).Post("/pvz/{pvzId}/close_last_reception", h.CloseLastReception)
+		r.With(middleware.RequirePermission(rbac.ProductDelete)).Post("/pvz/{pvzId}/delete_last_product", h.DeleteLastProduct)
+	})
+}
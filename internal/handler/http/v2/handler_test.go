@@ -0,0 +1,167 @@
+package v2
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/product"
+	domainPVZ "github.com/avito/pvz/internal/domain/pvz"
+	"github.com/avito/pvz/internal/domain/reception"
+	"github.com/avito/pvz/internal/handler/http/middleware"
+	"github.com/avito/pvz/pkg/httpresponse"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockPVZService struct {
+	mock.Mock
+}
+
+func (m *mockPVZService) Create(ctx context.Context, city string, userID uuid.UUID) (*domainPVZ.PVZ, error) {
+	args := m.Called(ctx, city, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domainPVZ.PVZ), args.Error(1)
+}
+
+func (m *mockPVZService) GetByID(ctx context.Context, id uuid.UUID) (*domainPVZ.PVZ, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domainPVZ.PVZ), args.Error(1)
+}
+
+func (m *mockPVZService) GetWithReceptions(ctx context.Context, startDate, endDate time.Time, page, limit int) ([]*domainPVZ.PVZWithReceptions, error) {
+	args := m.Called(ctx, startDate, endDate, page, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domainPVZ.PVZWithReceptions), args.Error(1)
+}
+
+func (m *mockPVZService) GetWithReceptionsByCursor(ctx context.Context, startDate, endDate time.Time, cursor string, limit int) ([]*domainPVZ.PVZWithReceptions, string, error) {
+	args := m.Called(ctx, startDate, endDate, cursor, limit)
+	if args.Get(0) == nil {
+		return nil, "", args.Error(2)
+	}
+	return args.Get(0).([]*domainPVZ.PVZWithReceptions), args.String(1), args.Error(2)
+}
+
+type mockReceptionService struct {
+	mock.Mock
+}
+
+func (m *mockReceptionService) Create(ctx context.Context, pvzID uuid.UUID) (*reception.Reception, error) {
+	args := m.Called(ctx, pvzID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*reception.Reception), args.Error(1)
+}
+
+func (m *mockReceptionService) Close(ctx context.Context, pvzID uuid.UUID) error {
+	args := m.Called(ctx, pvzID)
+	return args.Error(0)
+}
+
+func (m *mockReceptionService) GetByID(ctx context.Context, id uuid.UUID) (*reception.Reception, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*reception.Reception), args.Error(1)
+}
+
+func (m *mockReceptionService) GetOpenByPVZID(ctx context.Context, pvzID uuid.UUID) (*reception.Reception, error) {
+	args := m.Called(ctx, pvzID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*reception.Reception), args.Error(1)
+}
+
+type mockProductService struct {
+	mock.Mock
+}
+
+func (m *mockProductService) Create(ctx context.Context, receptionID uuid.UUID, productType product.Type) (*product.Product, error) {
+	args := m.Called(ctx, receptionID, productType)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*product.Product), args.Error(1)
+}
+
+func (m *mockProductService) DeleteLast(ctx context.Context, receptionID uuid.UUID) error {
+	args := m.Called(ctx, receptionID)
+	return args.Error(0)
+}
+
+// testServices набор моков сервисов для одного теста
+type testServices struct {
+	pvz       *mockPVZService
+	reception *mockReceptionService
+	product   *mockProductService
+}
+
+func newTestServices() *testServices {
+	return &testServices{
+		pvz:       new(mockPVZService),
+		reception: new(mockReceptionService),
+		product:   new(mockProductService),
+	}
+}
+
+func (s *testServices) assertExpectations(t *testing.T) {
+	s.pvz.AssertExpectations(t)
+	s.reception.AssertExpectations(t)
+	s.product.AssertExpectations(t)
+}
+
+// serve выполняет запрос через маршрут pattern без проверки токена,
+// подставляя в контекст ID пользователя, как это делает AuthMiddleware
+func serve(method, pattern, target, body string, userID uuid.UUID, handler http.HandlerFunc) *httptest.ResponseRecorder {
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req := httptest.NewRequest(method, target, reader)
+	if userID != uuid.Nil {
+		req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, userID.String()))
+	}
+
+	r := chi.NewRouter()
+	r.Method(method, pattern, handler)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	return rec
+}
+
+// decodeData разбирает конверт ответа и его поле data
+func decodeData(t *testing.T, rec *httptest.ResponseRecorder, data interface{}) *Meta {
+	var envelope struct {
+		Data json.RawMessage `json:"data"`
+		Meta *Meta           `json:"meta"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&envelope))
+	require.NoError(t, json.Unmarshal(envelope.Data, data))
+	return envelope.Meta
+}
+
+// decodeProblem разбирает ответ с ошибкой
+func decodeProblem(t *testing.T, rec *httptest.ResponseRecorder) httpresponse.ProblemDetails {
+	var problem httpresponse.ProblemDetails
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&problem))
+	return problem
+}
@@ -0,0 +1,90 @@
+package v2
+
+import (
+	"time"
+
+	domainProduct "github.com/avito/pvz/internal/domain/product"
+	domainPVZ "github.com/avito/pvz/internal/domain/pvz"
+	domainReception "github.com/avito/pvz/internal/domain/reception"
+	"github.com/google/uuid"
+)
+
+// PVZ представление ПВЗ в ответах API
+type PVZ struct {
+	ID               uuid.UUID `json:"id"`
+	RegistrationDate time.Time `json:"registrationDate"`
+	City             string    `json:"city"`
+}
+
+// Reception представление приемки в ответах API
+type Reception struct {
+	ID       uuid.UUID              `json:"id"`
+	DateTime time.Time              `json:"dateTime"`
+	PVZID    uuid.UUID              `json:"pvzId"`
+	Status   domainReception.Status `json:"status"`
+}
+
+// Product представление товара в ответах API
+type Product struct {
+	ID          uuid.UUID          `json:"id"`
+	DateTime    time.Time          `json:"dateTime"`
+	Type        domainProduct.Type `json:"type"`
+	ReceptionID uuid.UUID          `json:"receptionId"`
+}
+
+// ReceptionWithProducts приемка с товарами
+type ReceptionWithProducts struct {
+	Reception Reception `json:"reception"`
+	Products  []Product `json:"products"`
+}
+
+// PVZWithReceptions ПВЗ с приемками за период
+type PVZWithReceptions struct {
+	PVZ        PVZ                     `json:"pvz"`
+	Receptions []ReceptionWithProducts `json:"receptions"`
+}
+
+func newPVZ(p *domainPVZ.PVZ) PVZ {
+	return PVZ{
+		ID:               p.ID,
+		RegistrationDate: p.CreatedAt,
+		City:             p.City,
+	}
+}
+
+func newReception(r *domainReception.Reception) Reception {
+	return Reception{
+		ID:       r.ID,
+		DateTime: r.DateTime,
+		PVZID:    r.PVZID,
+		Status:   r.Status,
+	}
+}
+
+func newProduct(p *domainProduct.Product) Product {
+	return Product{
+		ID:          p.ID,
+		DateTime:    p.DateTime,
+		Type:        p.Type,
+		ReceptionID: p.ReceptionID,
+	}
+}
+
+func newPVZWithReceptions(p *domainPVZ.PVZWithReceptions) PVZWithReceptions {
+	receptions := make([]ReceptionWithProducts, len(p.Receptions))
+	for i, r := range p.Receptions {
+		products := make([]Product, len(r.Products))
+		for j, prod := range r.Products {
+			products[j] = newProduct(prod)
+		}
+		receptions[i] = ReceptionWithProducts{
+			Reception: newReception(r.Reception),
+			Products:  products,
+		}
+	}
+
+	return PVZWithReceptions{
+		PVZ:        newPVZ(p.PVZ),
+		Receptions: receptions,
+	}
+}
@@ -0,0 +1,62 @@
+package v2
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/avito/pvz/internal/domain/product"
+	"github.com/avito/pvz/internal/handler/apperror"
+	"github.com/avito/pvz/internal/handler/i18n"
+	"github.com/google/uuid"
+)
+
+// AddProduct добавляет товар в открытую приемку ПВЗ
+func (h *Handler) AddProduct(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Type  product.Type `json:"type"`
+		PVZID uuid.UUID    `json:"pvzId"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apperror.WriteInvalidRequest(w, r, "invalid_body")
+		return
+	}
+
+	if req.PVZID == uuid.Nil {
+		apperror.WriteInvalidRequest(w, r, "invalid_pvz_id")
+		return
+	}
+
+	open, err := h.reception.GetOpenByPVZID(r.Context(), req.PVZID)
+	if err != nil {
+		apperror.WriteHTTP(w, r, err, "open_reception_get_failed")
+		return
+	}
+
+	p, err := h.product.Create(r.Context(), open.ID, req.Type)
+	if err != nil {
+		apperror.WriteHTTP(w, r, err, "product_create_failed")
+		return
+	}
+
+	writeData(w, http.StatusCreated, newProduct(p))
+}
+
+// ProductType тип товара с названием на языке запроса
+type ProductType struct {
+	Type product.Type `json:"type"`
+	Name string       `json:"name"`
+}
+
+// ListProductTypes возвращает допустимые типы товаров
+func (h *Handler) ListProductTypes(w http.ResponseWriter, r *http.Request) {
+	lang := i18n.LangFromContext(r.Context())
+
+	types := product.Types()
+	data := make([]ProductType, len(types))
+	for i, t := range types {
+		data[i] = ProductType{Type: t, Name: i18n.ProductTypeName(lang, t)}
+	}
+
+	writeData(w, http.StatusOK, data)
+}
@@ -0,0 +1,93 @@
+package v2
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/avito/pvz/internal/domain/product"
+	"github.com/avito/pvz/internal/domain/reception"
+	"github.com/avito/pvz/internal/handler/i18n"
+	serviceProduct "github.com/avito/pvz/internal/service/product"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHandler_AddProduct(t *testing.T) {
+	pvzID := uuid.New()
+	open := &reception.Reception{ID: uuid.New(), PVZID: pvzID, Status: reception.StatusInProgress}
+	created := &product.Product{ID: uuid.New(), ReceptionID: open.ID, Type: product.TypeClothing}
+
+	tests := []struct {
+		name           string
+		body           string
+		setupMocks     func(*testServices)
+		expectedStatus int
+	}{
+		{
+			name: "успешное добавление",
+			body: `{"type":"clothing","pvzId":"` + pvzID.String() + `"}`,
+			setupMocks: func(s *testServices) {
+				s.reception.On("GetOpenByPVZID", mock.Anything, pvzID).Return(open, nil)
+				s.product.On("Create", mock.Anything, open.ID, product.TypeClothing).Return(created, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name: "нет открытой приемки",
+			body: `{"type":"clothing","pvzId":"` + pvzID.String() + `"}`,
+			setupMocks: func(s *testServices) {
+				s.reception.On("GetOpenByPVZID", mock.Anything, pvzID).Return(nil, reception.ErrNoOpenReception)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "неверный тип товара",
+			body: `{"type":"weapons","pvzId":"` + pvzID.String() + `"}`,
+			setupMocks: func(s *testServices) {
+				s.reception.On("GetOpenByPVZID", mock.Anything, pvzID).Return(open, nil)
+				s.product.On("Create", mock.Anything, open.ID, product.Type("weapons")).Return(nil, serviceProduct.ErrInvalidProductType)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "неверное тело запроса",
+			body:           `{`,
+			setupMocks:     func(s *testServices) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			services := newTestServices()
+			tt.setupMocks(services)
+			h := New(services.pvz, services.reception, services.product)
+
+			rec := serve(http.MethodPost, "/products", "/products", tt.body, uuid.Nil, h.AddProduct)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedStatus == http.StatusCreated {
+				var got Product
+				decodeData(t, rec, &got)
+				assert.Equal(t, newProduct(created), got)
+			}
+			services.assertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_ListProductTypes(t *testing.T) {
+	services := newTestServices()
+	h := New(services.pvz, services.reception, services.product)
+
+	rec := serve(http.MethodGet, "/products/types", "/products/types", "", uuid.Nil, func(w http.ResponseWriter, r *http.Request) {
+		h.ListProductTypes(w, r.WithContext(i18n.WithLang(r.Context(), i18n.English)))
+	})
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var got []ProductType
+	decodeData(t, rec, &got)
+	assert.Len(t, got, len(product.Types()))
+	assert.Equal(t, ProductType{Type: product.TypeElectronics, Name: "electronics"}, got[0])
+}
@@ -0,0 +1,204 @@
+package v2
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/avito/pvz/internal/handler/apperror"
+	"github.com/avito/pvz/internal/handler/http/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	// defaultLimit размер страницы списка ПВЗ по умолчанию
+	defaultLimit = 10
+	// maxLimit максимальный размер страницы списка ПВЗ
+	maxLimit = 30
+)
+
+// CreatePVZ создает ПВЗ
+func (h *Handler) CreatePVZ(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		City string `json:"city"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apperror.WriteInvalidRequest(w, r, "invalid_body")
+		return
+	}
+
+	if req.City == "" {
+		apperror.WriteInvalidRequest(w, r, "city_required")
+		return
+	}
+
+	userID, err := currentUserID(r)
+	if err != nil {
+		apperror.WriteHTTP(w, r, err, "")
+		return
+	}
+
+	pvz, err := h.pvz.Create(r.Context(), req.City, userID)
+	if err != nil {
+		apperror.WriteHTTP(w, r, err, "pvz_create_failed")
+		return
+	}
+
+	writeData(w, http.StatusCreated, newPVZ(pvz))
+}
+
+// GetPVZ возвращает ПВЗ по ID
+func (h *Handler) GetPVZ(w http.ResponseWriter, r *http.Request) {
+	pvzID, err := uuid.Parse(chi.URLParam(r, "pvzId"))
+	if err != nil {
+		apperror.WriteInvalidRequest(w, r, "invalid_pvz_id")
+		return
+	}
+
+	pvz, err := h.pvz.GetByID(r.Context(), pvzID)
+	if err != nil {
+		apperror.WriteHTTP(w, r, err, "pvz_get_failed")
+		return
+	}
+
+	if pvz.Version > 0 {
+		w.Header().Set("ETag", `"`+strconv.FormatInt(pvz.Version, 10)+`"`)
+	}
+	writeData(w, http.StatusOK, newPVZ(pvz))
+}
+
+// ListPVZ возвращает ПВЗ с приемками и товарами за период startDate–endDate.
+// Страница задается параметрами page и limit, а с параметром cursor —
+// курсором из meta.nextCursor предыдущей страницы.
+func (h *Handler) ListPVZ(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	if q.Get("startDate") == "" || q.Get("endDate") == "" {
+		apperror.WriteInvalidRequest(w, r, "dates_required")
+		return
+	}
+
+	startDate, err := time.Parse(time.RFC3339, q.Get("startDate"))
+	if err != nil {
+		apperror.WriteInvalidRequest(w, r, "invalid_start_date")
+		return
+	}
+
+	endDate, err := time.Parse(time.RFC3339, q.Get("endDate"))
+	if err != nil {
+		apperror.WriteInvalidRequest(w, r, "invalid_end_date")
+		return
+	}
+
+	limit := defaultLimit
+	if v := q.Get("limit"); v != "" {
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > maxLimit {
+			apperror.WriteInvalidRequest(w, r, "invalid_limit")
+			return
+		}
+	}
+
+	if q.Has("cursor") {
+		pvzs, nextCursor, err := h.pvz.GetWithReceptionsByCursor(r.Context(), startDate, endDate, q.Get("cursor"), limit)
+		if err != nil {
+			apperror.WriteHTTP(w, r, err, "pvz_with_receptions_failed")
+			return
+		}
+
+		data := make([]PVZWithReceptions, len(pvzs))
+		for i, p := range pvzs {
+			data[i] = newPVZWithReceptions(p)
+		}
+		writeList(w, data, Meta{Limit: limit, NextCursor: nextCursor})
+		return
+	}
+
+	page := 1
+	if v := q.Get("page"); v != "" {
+		page, err = strconv.Atoi(v)
+		if err != nil || page < 1 {
+			apperror.WriteInvalidRequest(w, r, "invalid_page_number")
+			return
+		}
+	}
+
+	pvzs, err := h.pvz.GetWithReceptions(r.Context(), startDate, endDate, page, limit)
+	if err != nil {
+		apperror.WriteHTTP(w, r, err, "pvz_with_receptions_failed")
+		return
+	}
+
+	data := make([]PVZWithReceptions, len(pvzs))
+	for i, p := range pvzs {
+		data[i] = newPVZWithReceptions(p)
+	}
+	writeList(w, data, Meta{Page: page, Limit: limit})
+}
+
+// CloseLastReception закрывает открытую приемку ПВЗ и возвращает ее
+func (h *Handler) CloseLastReception(w http.ResponseWriter, r *http.Request) {
+	pvzID, err := uuid.Parse(chi.URLParam(r, "pvzId"))
+	if err != nil {
+		apperror.WriteInvalidRequest(w, r, "invalid_pvz_id")
+		return
+	}
+
+	open, err := h.reception.GetOpenByPVZID(r.Context(), pvzID)
+	if err != nil {
+		apperror.WriteHTTP(w, r, err, "open_reception_get_failed")
+		return
+	}
+
+	if err := h.reception.Close(r.Context(), pvzID); err != nil {
+		apperror.WriteHTTP(w, r, err, "reception_close_failed")
+		return
+	}
+
+	closed, err := h.reception.GetByID(r.Context(), open.ID)
+	if err != nil {
+		apperror.WriteHTTP(w, r, err, "reception_get_failed")
+		return
+	}
+
+	writeData(w, http.StatusOK, newReception(closed))
+}
+
+// DeleteLastProduct удаляет последний добавленный товар из открытой приемки ПВЗ
+func (h *Handler) DeleteLastProduct(w http.ResponseWriter, r *http.Request) {
+	pvzID, err := uuid.Parse(chi.URLParam(r, "pvzId"))
+	if err != nil {
+		apperror.WriteInvalidRequest(w, r, "invalid_pvz_id")
+		return
+	}
+
+	open, err := h.reception.GetOpenByPVZID(r.Context(), pvzID)
+	if err != nil {
+		apperror.WriteHTTP(w, r, err, "open_reception_get_failed")
+		return
+	}
+
+	if err := h.product.DeleteLast(r.Context(), open.ID); err != nil {
+		apperror.WriteHTTP(w, r, err, "product_delete_failed")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// currentUserID возвращает ID пользователя, сохраненный AuthMiddleware
+func currentUserID(r *http.Request) (uuid.UUID, error) {
+	id, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		return uuid.Nil, apperror.ErrUnauthorized
+	}
+
+	userID, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil, apperror.ErrInvalidToken
+	}
+	return userID, nil
+}
@@ -0,0 +1,332 @@
+package v2
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/product"
+	domainPVZ "github.com/avito/pvz/internal/domain/pvz"
+	"github.com/avito/pvz/internal/domain/reception"
+	"github.com/avito/pvz/internal/handler/apperror"
+	servicePVZ "github.com/avito/pvz/internal/service/pvz"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHandler_CreatePVZ(t *testing.T) {
+	userID := uuid.New()
+	created := &domainPVZ.PVZ{
+		ID:        uuid.New(),
+		CreatedAt: time.Date(2024, time.March, 1, 10, 0, 0, 0, time.UTC),
+		City:      "Москва",
+		Version:   1,
+	}
+
+	tests := []struct {
+		name           string
+		body           string
+		userID         uuid.UUID
+		setupMocks     func(*testServices)
+		expectedStatus int
+		expectedCode   string
+		expected       *PVZ
+	}{
+		{
+			name:   "успешное создание",
+			body:   `{"city":"Москва"}`,
+			userID: userID,
+			setupMocks: func(s *testServices) {
+				s.pvz.On("Create", mock.Anything, "Москва", userID).Return(created, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expected:       &PVZ{ID: created.ID, RegistrationDate: created.CreatedAt, City: "Москва"},
+		},
+		{
+			name:           "пустой город",
+			body:           `{"city":""}`,
+			userID:         userID,
+			setupMocks:     func(s *testServices) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   string(apperror.CodeInvalidRequest),
+		},
+		{
+			name:           "без пользователя",
+			body:           `{"city":"Москва"}`,
+			setupMocks:     func(s *testServices) {},
+			expectedStatus: http.StatusUnauthorized,
+			expectedCode:   string(apperror.CodeUnauthorized),
+		},
+		{
+			name:   "ПВЗ уже существует",
+			body:   `{"city":"Москва"}`,
+			userID: userID,
+			setupMocks: func(s *testServices) {
+				s.pvz.On("Create", mock.Anything, "Москва", userID).Return(nil, servicePVZ.ErrPVZAlreadyExists)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedCode:   string(apperror.CodePVZAlreadyExists),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			services := newTestServices()
+			tt.setupMocks(services)
+			h := New(services.pvz, services.reception, services.product)
+
+			rec := serve(http.MethodPost, "/pvz", "/pvz", tt.body, tt.userID, h.CreatePVZ)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedCode != "" {
+				assert.Equal(t, tt.expectedCode, decodeProblem(t, rec).Code)
+			}
+			if tt.expected != nil {
+				var got PVZ
+				decodeData(t, rec, &got)
+				assert.Equal(t, *tt.expected, got)
+			}
+			services.assertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_GetPVZ(t *testing.T) {
+	pvz := &domainPVZ.PVZ{ID: uuid.New(), City: "Казань", Version: 3}
+
+	tests := []struct {
+		name           string
+		pvzID          string
+		setupMocks     func(*testServices)
+		expectedStatus int
+		expectedETag   string
+	}{
+		{
+			name:  "успешное получение",
+			pvzID: pvz.ID.String(),
+			setupMocks: func(s *testServices) {
+				s.pvz.On("GetByID", mock.Anything, pvz.ID).Return(pvz, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedETag:   `"3"`,
+		},
+		{
+			name:           "неверный ID",
+			pvzID:          "invalid",
+			setupMocks:     func(s *testServices) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "ПВЗ не найден",
+			pvzID: pvz.ID.String(),
+			setupMocks: func(s *testServices) {
+				s.pvz.On("GetByID", mock.Anything, pvz.ID).Return(nil, servicePVZ.ErrPVZNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			services := newTestServices()
+			tt.setupMocks(services)
+			h := New(services.pvz, services.reception, services.product)
+
+			rec := serve(http.MethodGet, "/pvz/{pvzId}", "/pvz/"+tt.pvzID, "", uuid.Nil, h.GetPVZ)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.Equal(t, tt.expectedETag, rec.Header().Get("ETag"))
+			services.assertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_ListPVZ(t *testing.T) {
+	startDate := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)
+	period := "?startDate=2024-01-01T00:00:00Z&endDate=2024-02-01T00:00:00Z"
+
+	pvzID := uuid.New()
+	receptionID := uuid.New()
+	found := []*domainPVZ.PVZWithReceptions{
+		{
+			PVZ: &domainPVZ.PVZ{ID: pvzID, City: "Москва"},
+			Receptions: []*domainPVZ.ReceptionWithProducts{
+				{
+					Reception: &reception.Reception{ID: receptionID, PVZID: pvzID, Status: reception.StatusInProgress},
+					Products:  []*product.Product{{ID: uuid.New(), ReceptionID: receptionID, Type: product.TypeFood}},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		query          string
+		setupMocks     func(*testServices)
+		expectedStatus int
+		expectedMeta   *Meta
+	}{
+		{
+			name:  "страница по номеру",
+			query: period + "&page=2&limit=5",
+			setupMocks: func(s *testServices) {
+				s.pvz.On("GetWithReceptions", mock.Anything, startDate, endDate, 2, 5).Return(found, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedMeta:   &Meta{Page: 2, Limit: 5},
+		},
+		{
+			name:  "страница по курсору",
+			query: period + "&cursor=",
+			setupMocks: func(s *testServices) {
+				s.pvz.On("GetWithReceptionsByCursor", mock.Anything, startDate, endDate, "", defaultLimit).Return(found, "next", nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedMeta:   &Meta{Limit: defaultLimit, NextCursor: "next"},
+		},
+		{
+			name:           "без дат",
+			query:          "?page=1",
+			setupMocks:     func(s *testServices) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "лимит больше максимального",
+			query:          period + "&limit=31",
+			setupMocks:     func(s *testServices) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "неверный номер страницы",
+			query:          period + "&page=0",
+			setupMocks:     func(s *testServices) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "ошибка сервиса",
+			query: period,
+			setupMocks: func(s *testServices) {
+				s.pvz.On("GetWithReceptions", mock.Anything, startDate, endDate, 1, defaultLimit).Return(nil, errors.New("database error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			services := newTestServices()
+			tt.setupMocks(services)
+			h := New(services.pvz, services.reception, services.product)
+
+			rec := serve(http.MethodGet, "/pvz", "/pvz"+tt.query, "", uuid.Nil, h.ListPVZ)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedMeta != nil {
+				var got []PVZWithReceptions
+				meta := decodeData(t, rec, &got)
+				assert.Equal(t, tt.expectedMeta, meta)
+				assert.Len(t, got, 1)
+				assert.Equal(t, pvzID, got[0].PVZ.ID)
+				assert.Equal(t, pvzID, got[0].Receptions[0].Reception.PVZID)
+				assert.Equal(t, receptionID, got[0].Receptions[0].Products[0].ReceptionID)
+			}
+			services.assertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_CloseLastReception(t *testing.T) {
+	pvzID := uuid.New()
+	open := &reception.Reception{ID: uuid.New(), PVZID: pvzID, Status: reception.StatusInProgress}
+	closed := &reception.Reception{ID: open.ID, PVZID: pvzID, Status: reception.StatusClose}
+
+	tests := []struct {
+		name           string
+		setupMocks     func(*testServices)
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			name: "успешное закрытие",
+			setupMocks: func(s *testServices) {
+				s.reception.On("GetOpenByPVZID", mock.Anything, pvzID).Return(open, nil)
+				s.reception.On("Close", mock.Anything, pvzID).Return(nil)
+				s.reception.On("GetByID", mock.Anything, open.ID).Return(closed, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "нет открытой приемки",
+			setupMocks: func(s *testServices) {
+				s.reception.On("GetOpenByPVZID", mock.Anything, pvzID).Return(nil, reception.ErrNoOpenReception)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   string(apperror.CodeNoOpenReception),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			services := newTestServices()
+			tt.setupMocks(services)
+			h := New(services.pvz, services.reception, services.product)
+
+			target := "/pvz/" + pvzID.String() + "/close_last_reception"
+			rec := serve(http.MethodPost, "/pvz/{pvzId}/close_last_reception", target, "", uuid.Nil, h.CloseLastReception)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedCode != "" {
+				assert.Equal(t, tt.expectedCode, decodeProblem(t, rec).Code)
+			} else {
+				var got Reception
+				decodeData(t, rec, &got)
+				assert.Equal(t, newReception(closed), got)
+			}
+			services.assertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_DeleteLastProduct(t *testing.T) {
+	pvzID := uuid.New()
+	open := &reception.Reception{ID: uuid.New(), PVZID: pvzID, Status: reception.StatusInProgress}
+
+	tests := []struct {
+		name           string
+		setupMocks     func(*testServices)
+		expectedStatus int
+	}{
+		{
+			name: "успешное удаление",
+			setupMocks: func(s *testServices) {
+				s.reception.On("GetOpenByPVZID", mock.Anything, pvzID).Return(open, nil)
+				s.product.On("DeleteLast", mock.Anything, open.ID).Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name: "нет открытой приемки",
+			setupMocks: func(s *testServices) {
+				s.reception.On("GetOpenByPVZID", mock.Anything, pvzID).Return(nil, reception.ErrNoOpenReception)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			services := newTestServices()
+			tt.setupMocks(services)
+			h := New(services.pvz, services.reception, services.product)
+
+			target := "/pvz/" + pvzID.String() + "/delete_last_product"
+			rec := serve(http.MethodPost, "/pvz/{pvzId}/delete_last_product", target, "", uuid.Nil, h.DeleteLastProduct)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			services.assertExpectations(t)
+		})
+	}
+}
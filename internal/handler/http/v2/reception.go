@@ -0,0 +1,34 @@
+package v2
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/avito/pvz/internal/handler/apperror"
+	"github.com/google/uuid"
+)
+
+// CreateReception открывает новую приемку в ПВЗ
+func (h *Handler) CreateReception(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		PVZID uuid.UUID `json:"pvzId"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apperror.WriteInvalidRequest(w, r, "invalid_body")
+		return
+	}
+
+	if req.PVZID == uuid.Nil {
+		apperror.WriteInvalidRequest(w, r, "invalid_pvz_id")
+		return
+	}
+
+	reception, err := h.reception.Create(r.Context(), req.PVZID)
+	if err != nil {
+		apperror.WriteHTTP(w, r, err, "reception_create_failed")
+		return
+	}
+
+	writeData(w, http.StatusCreated, newReception(reception))
+}
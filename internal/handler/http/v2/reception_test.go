@@ -0,0 +1,78 @@
+package v2
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/avito/pvz/internal/domain/reception"
+	"github.com/avito/pvz/internal/handler/apperror"
+	serviceReception "github.com/avito/pvz/internal/service/reception"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHandler_CreateReception(t *testing.T) {
+	pvzID := uuid.New()
+	created := &reception.Reception{ID: uuid.New(), PVZID: pvzID, Status: reception.StatusInProgress}
+
+	tests := []struct {
+		name           string
+		body           string
+		setupMocks     func(*testServices)
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			name: "успешное создание",
+			body: `{"pvzId":"` + pvzID.String() + `"}`,
+			setupMocks: func(s *testServices) {
+				s.reception.On("Create", mock.Anything, pvzID).Return(created, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "без ID ПВЗ",
+			body:           `{}`,
+			setupMocks:     func(s *testServices) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   string(apperror.CodeInvalidRequest),
+		},
+		{
+			name:           "поле первой версии",
+			body:           `{"pvz_id":"` + pvzID.String() + `"}`,
+			setupMocks:     func(s *testServices) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   string(apperror.CodeInvalidRequest),
+		},
+		{
+			name: "уже есть открытая приемка",
+			body: `{"pvzId":"` + pvzID.String() + `"}`,
+			setupMocks: func(s *testServices) {
+				s.reception.On("Create", mock.Anything, pvzID).Return(nil, serviceReception.ErrReceptionAlreadyOpen)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   string(apperror.CodeReceptionAlreadyOpen),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			services := newTestServices()
+			tt.setupMocks(services)
+			h := New(services.pvz, services.reception, services.product)
+
+			rec := serve(http.MethodPost, "/receptions", "/receptions", tt.body, uuid.Nil, h.CreateReception)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedCode != "" {
+				assert.Equal(t, tt.expectedCode, decodeProblem(t, rec).Code)
+			} else {
+				var got Reception
+				decodeData(t, rec, &got)
+				assert.Equal(t, newReception(created), got)
+			}
+			services.assertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,217 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/event"
+	"github.com/avito/pvz/internal/domain/rbac"
+	"github.com/avito/pvz/internal/domain/webhook"
+	"github.com/avito/pvz/internal/handler/apperror"
+	"github.com/avito/pvz/internal/handler/http/middleware"
+	"github.com/avito/pvz/pkg/httpresponse"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// WebhookServiceInterface определяет интерфейс для сервиса подписок на события
+type WebhookServiceInterface interface {
+	CreateSubscription(ctx context.Context, url string, eventTypes []event.Type, secret string, createdBy uuid.UUID) (*webhook.Subscription, error)
+	ListSubscriptions(ctx context.Context) ([]*webhook.Subscription, error)
+	DeleteSubscription(ctx context.Context, id uuid.UUID) error
+	ListDeliveries(ctx context.Context, filter webhook.DeliveryFilter) ([]*webhook.Delivery, error)
+	Redeliver(ctx context.Context, id uuid.UUID) (*webhook.Delivery, error)
+}
+
+// WebhookHandler обрабатывает HTTP-запросы управления подписками на события
+type WebhookHandler struct {
+	service WebhookServiceInterface
+}
+
+// NewWebhookHandler создает новый экземпляр WebhookHandler
+func NewWebhookHandler(service WebhookServiceInterface) *WebhookHandler {
+	return &WebhookHandler{
+		service: service,
+	}
+}
+
+// RegisterRoutes регистрирует маршруты подписок, доступные только модераторам
+func (h *WebhookHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.AuthMiddleware)
+		r.Use(middleware.RequirePermission(rbac.WebhookManage))
+
+		r.Post("/webhooks", h.Create)
+		r.Get("/webhooks", h.List)
+		r.Delete("/webhooks/{webhookId}", h.Delete)
+		r.Get("/webhooks/{webhookId}/deliveries", h.ListDeliveries)
+		r.Post("/webhooks/deliveries/{deliveryId}/redeliver", h.Redeliver)
+	})
+}
+
+// subscriptionResponse подписка в ответе API. Секрет отдается только при создании.
+type subscriptionResponse struct {
+	ID         string       `json:"id"`
+	URL        string       `json:"url"`
+	EventTypes []event.Type `json:"eventTypes"`
+	Secret     string       `json:"secret,omitempty"`
+	CreatedAt  time.Time    `json:"createdAt"`
+}
+
+func newSubscriptionResponse(s *webhook.Subscription) subscriptionResponse {
+	return subscriptionResponse{
+		ID:         s.ID.String(),
+		URL:        s.URL,
+		EventTypes: s.EventTypes,
+		CreatedAt:  s.CreatedAt,
+	}
+}
+
+// deliveryResponse доставка события в истории подписки
+type deliveryResponse struct {
+	ID             string          `json:"id"`
+	EventType      event.Type      `json:"eventType"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  *time.Time      `json:"nextAttemptAt,omitempty"`
+	LastStatusCode int             `json:"lastStatusCode,omitempty"`
+	LastError      string          `json:"lastError,omitempty"`
+	CreatedAt      time.Time       `json:"createdAt"`
+	DeliveredAt    *time.Time      `json:"deliveredAt,omitempty"`
+}
+
+func newDeliveryResponse(d *webhook.Delivery) deliveryResponse {
+	resp := deliveryResponse{
+		ID:             d.ID.String(),
+		EventType:      d.EventType,
+		Payload:        d.Payload,
+		Status:         string(d.Status),
+		Attempts:       d.Attempts,
+		LastStatusCode: d.LastStatusCode,
+		LastError:      d.LastError,
+		CreatedAt:      d.CreatedAt,
+		DeliveredAt:    d.DeliveredAt,
+	}
+	// Время следующей попытки имеет смысл только для ожидающих доставок
+	if d.Status == webhook.DeliveryPending {
+		next := d.NextAttemptAt
+		resp.NextAttemptAt = &next
+	}
+	return resp
+}
+
+// Create обрабатывает создание подписки
+func (h *WebhookHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		URL        string       `json:"url"`
+		EventTypes []event.Type `json:"eventTypes"`
+		Secret     string       `json:"secret"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apperror.WriteInvalidRequest(w, r, "invalid_body")
+		return
+	}
+
+	userIDStr, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		apperror.WriteHTTP(w, r, apperror.ErrUnauthorized, "")
+		return
+	}
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		apperror.WriteHTTP(w, r, apperror.ErrUnauthorized, "")
+		return
+	}
+
+	sub, err := h.service.CreateSubscription(r.Context(), req.URL, req.EventTypes, req.Secret, userID)
+	if err != nil {
+		apperror.WriteHTTP(w, r, err, "webhook_create_failed")
+		return
+	}
+
+	resp := newSubscriptionResponse(sub)
+	resp.Secret = sub.Secret
+	httpresponse.NoStore(w)
+	httpresponse.JSON(w, http.StatusCreated, resp)
+}
+
+// List обрабатывает получение списка подписок
+func (h *WebhookHandler) List(w http.ResponseWriter, r *http.Request) {
+	subs, err := h.service.ListSubscriptions(r.Context())
+	if err != nil {
+		apperror.WriteHTTP(w, r, err, "webhook_list_failed")
+		return
+	}
+
+	resp := make([]subscriptionResponse, len(subs))
+	for i, s := range subs {
+		resp[i] = newSubscriptionResponse(s)
+	}
+
+	httpresponse.JSON(w, http.StatusOK, resp)
+}
+
+// Delete обрабатывает удаление подписки
+func (h *WebhookHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "webhookId"))
+	if err != nil {
+		apperror.WriteInvalidRequest(w, r, "invalid_webhook_id")
+		return
+	}
+
+	if err := h.service.DeleteSubscription(r.Context(), id); err != nil {
+		apperror.WriteHTTP(w, r, err, "webhook_delete_failed")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListDeliveries обрабатывает получение истории доставок подписки.
+// Параметр status ограничивает выборку одним статусом, например dead.
+func (h *WebhookHandler) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "webhookId"))
+	if err != nil {
+		apperror.WriteInvalidRequest(w, r, "invalid_webhook_id")
+		return
+	}
+
+	q := r.URL.Query()
+	deliveries, err := h.service.ListDeliveries(r.Context(), webhook.DeliveryFilter{
+		SubscriptionID: id,
+		Status:         webhook.DeliveryStatus(q.Get("status")),
+		Page:           parseListPage(q),
+	})
+	if err != nil {
+		apperror.WriteHTTP(w, r, err, "webhook_deliveries_failed")
+		return
+	}
+
+	resp := make([]deliveryResponse, len(deliveries))
+	for i, d := range deliveries {
+		resp[i] = newDeliveryResponse(d)
+	}
+
+	httpresponse.JSON(w, http.StatusOK, resp)
+}
+
+// Redeliver обрабатывает повторную отправку доставки из dead-letter
+func (h *WebhookHandler) Redeliver(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "deliveryId"))
+	if err != nil {
+		apperror.WriteInvalidRequest(w, r, "invalid_delivery_id")
+		return
+	}
+
+	d, err := h.service.Redeliver(r.Context(), id)
+	if err != nil {
+		apperror.WriteHTTP(w, r, err, "webhook_redeliver_failed")
+		return
+	}
+
+	httpresponse.JSON(w, http.StatusAccepted, newDeliveryResponse(d))
+}
@@ -0,0 +1,316 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/event"
+	"github.com/avito/pvz/internal/domain/listing"
+	"github.com/avito/pvz/internal/domain/webhook"
+	"github.com/avito/pvz/internal/handler/apperror"
+	"github.com/avito/pvz/internal/handler/http/middleware"
+	webhookService "github.com/avito/pvz/internal/service/webhook"
+	"github.com/avito/pvz/pkg/httpresponse"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockWebhookService struct {
+	mock.Mock
+}
+
+func (m *mockWebhookService) CreateSubscription(ctx context.Context, url string, eventTypes []event.Type, secret string, createdBy uuid.UUID) (*webhook.Subscription, error) {
+	args := m.Called(ctx, url, eventTypes, secret, createdBy)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*webhook.Subscription), args.Error(1)
+}
+
+func (m *mockWebhookService) ListSubscriptions(ctx context.Context) ([]*webhook.Subscription, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*webhook.Subscription), args.Error(1)
+}
+
+func (m *mockWebhookService) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *mockWebhookService) ListDeliveries(ctx context.Context, filter webhook.DeliveryFilter) ([]*webhook.Delivery, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*webhook.Delivery), args.Error(1)
+}
+
+func (m *mockWebhookService) Redeliver(ctx context.Context, id uuid.UUID) (*webhook.Delivery, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*webhook.Delivery), args.Error(1)
+}
+
+// withRouteParam добавляет в запрос параметр маршрута chi
+func withRouteParam(req *http.Request, key, value string) *http.Request {
+	chiCtx := chi.NewRouteContext()
+	chiCtx.URLParams.Add(key, value)
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, chiCtx))
+}
+
+func TestWebhookHandler_Create(t *testing.T) {
+	userID := uuid.New()
+	sub := &webhook.Subscription{
+		ID:         uuid.New(),
+		URL:        "https://partner.example/hook",
+		EventTypes: []event.Type{event.TypeReceptionClosed},
+		Secret:     "generated",
+		CreatedBy:  userID,
+		CreatedAt:  time.Now(),
+	}
+
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(*mockWebhookService)
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			name: "успешное создание",
+			body: `{"url":"https://partner.example/hook","eventTypes":["reception.closed"]}`,
+			setupMock: func(m *mockWebhookService) {
+				m.On("CreateSubscription", mock.Anything, sub.URL, []event.Type{event.TypeReceptionClosed}, "", userID).Return(sub, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "неверное тело запроса",
+			body:           `{`,
+			setupMock:      func(m *mockWebhookService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   string(apperror.CodeInvalidRequest),
+		},
+		{
+			name: "неверный адрес получателя",
+			body: `{"url":"ftp://partner.example","eventTypes":["reception.closed"]}`,
+			setupMock: func(m *mockWebhookService) {
+				m.On("CreateSubscription", mock.Anything, "ftp://partner.example", []event.Type{event.TypeReceptionClosed}, "", userID).
+					Return(nil, webhookService.ErrInvalidURL)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   string(apperror.CodeInvalidWebhookURL),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(mockWebhookService)
+			tt.setupMock(service)
+			handler := NewWebhookHandler(service)
+
+			req := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(tt.body))
+			req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, userID.String()))
+			rec := httptest.NewRecorder()
+
+			handler.Create(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedCode != "" {
+				var problem httpresponse.ProblemDetails
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&problem))
+				assert.Equal(t, tt.expectedCode, problem.Code)
+			} else {
+				var resp subscriptionResponse
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+				assert.Equal(t, sub.ID.String(), resp.ID)
+				// Секрет отдается только в ответе на создание
+				assert.Equal(t, "generated", resp.Secret)
+			}
+			service.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWebhookHandler_List(t *testing.T) {
+	service := new(mockWebhookService)
+	service.On("ListSubscriptions", mock.Anything).Return([]*webhook.Subscription{
+		{ID: uuid.New(), URL: "https://partner.example/hook", Secret: "secret"},
+	}, nil)
+	handler := NewWebhookHandler(service)
+
+	rec := httptest.NewRecorder()
+	handler.List(rec, httptest.NewRequest(http.MethodGet, "/webhooks", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "secret")
+}
+
+func TestWebhookHandler_Delete(t *testing.T) {
+	id := uuid.New()
+
+	tests := []struct {
+		name           string
+		webhookID      string
+		serviceErr     error
+		expectedStatus int
+	}{
+		{name: "успешное удаление", webhookID: id.String(), expectedStatus: http.StatusNoContent},
+		{name: "подписка не найдена", webhookID: id.String(), serviceErr: webhook.ErrNotFound, expectedStatus: http.StatusNotFound},
+		{name: "неверный ID подписки", webhookID: "bad", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(mockWebhookService)
+			service.On("DeleteSubscription", mock.Anything, id).Return(tt.serviceErr).Maybe()
+			handler := NewWebhookHandler(service)
+
+			req := withRouteParam(httptest.NewRequest(http.MethodDelete, "/webhooks/"+tt.webhookID, nil), "webhookId", tt.webhookID)
+			rec := httptest.NewRecorder()
+
+			handler.Delete(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestWebhookHandler_ListDeliveries(t *testing.T) {
+	id := uuid.New()
+	now := time.Now().UTC().Truncate(time.Second)
+	dead := &webhook.Delivery{
+		ID:             uuid.New(),
+		SubscriptionID: id,
+		EventType:      event.TypeProductAdded,
+		Payload:        []byte(`{"type":"product.added"}`),
+		Status:         webhook.DeliveryDead,
+		Attempts:       8,
+		NextAttemptAt:  now,
+		LastStatusCode: 500,
+		LastError:      "unexpected status 500",
+		CreatedAt:      now,
+	}
+
+	tests := []struct {
+		name           string
+		query          string
+		setupMock      func(*mockWebhookService)
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			name:  "dead-letter подписки",
+			query: "?status=dead&offset=10&limit=5",
+			setupMock: func(m *mockWebhookService) {
+				m.On("ListDeliveries", mock.Anything, webhook.DeliveryFilter{
+					SubscriptionID: id,
+					Status:         webhook.DeliveryDead,
+					Page:           listing.Page{Offset: 10, Limit: 5},
+				}).Return([]*webhook.Delivery{dead}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:  "неизвестный статус",
+			query: "?status=lost",
+			setupMock: func(m *mockWebhookService) {
+				m.On("ListDeliveries", mock.Anything, mock.Anything).Return(nil, webhookService.ErrInvalidStatus)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   string(apperror.CodeInvalidDeliveryStatus),
+		},
+		{
+			name:  "ошибка сервиса",
+			query: "",
+			setupMock: func(m *mockWebhookService) {
+				m.On("ListDeliveries", mock.Anything, mock.Anything).Return(nil, errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedCode:   string(apperror.CodeInternal),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(mockWebhookService)
+			tt.setupMock(service)
+			handler := NewWebhookHandler(service)
+
+			req := withRouteParam(httptest.NewRequest(http.MethodGet, "/webhooks/"+id.String()+"/deliveries"+tt.query, nil), "webhookId", id.String())
+			rec := httptest.NewRecorder()
+
+			handler.ListDeliveries(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedCode != "" {
+				var problem httpresponse.ProblemDetails
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&problem))
+				assert.Equal(t, tt.expectedCode, problem.Code)
+				return
+			}
+
+			var resp []map[string]interface{}
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+			require.Len(t, resp, 1)
+			assert.Equal(t, "dead", resp[0]["status"])
+			assert.Equal(t, map[string]interface{}{"type": "product.added"}, resp[0]["payload"])
+			assert.Equal(t, "unexpected status 500", resp[0]["lastError"])
+			assert.NotContains(t, resp[0], "nextAttemptAt")
+			service.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWebhookHandler_Redeliver(t *testing.T) {
+	id := uuid.New()
+
+	tests := []struct {
+		name           string
+		serviceErr     error
+		expectedStatus int
+		expectedCode   string
+	}{
+		{name: "доставка возвращена в очередь", expectedStatus: http.StatusAccepted},
+		{name: "доставка не в dead-letter", serviceErr: webhookService.ErrDeliveryNotRetrying, expectedStatus: http.StatusConflict, expectedCode: string(apperror.CodeWebhookDeliveryNotDead)},
+		{name: "доставка не найдена", serviceErr: webhook.ErrDeliveryNotFound, expectedStatus: http.StatusNotFound, expectedCode: string(apperror.CodeWebhookDeliveryNotFound)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(mockWebhookService)
+			if tt.serviceErr != nil {
+				service.On("Redeliver", mock.Anything, id).Return(nil, tt.serviceErr)
+			} else {
+				service.On("Redeliver", mock.Anything, id).Return(&webhook.Delivery{ID: id, Status: webhook.DeliveryPending}, nil)
+			}
+			handler := NewWebhookHandler(service)
+
+			req := withRouteParam(httptest.NewRequest(http.MethodPost, "/webhooks/deliveries/"+id.String()+"/redeliver", nil), "deliveryId", id.String())
+			rec := httptest.NewRecorder()
+
+			handler.Redeliver(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedCode != "" {
+				var problem httpresponse.ProblemDetails
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&problem))
+				assert.Equal(t, tt.expectedCode, problem.Code)
+			}
+		})
+	}
+}
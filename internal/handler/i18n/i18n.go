@@ -0,0 +1,110 @@
+// Package i18n содержит каталог сообщений API на русском и английском языках.
+// Сообщения об ошибках ищутся по коду ошибки, остальные — по ключу сообщения.
+package i18n
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/avito/pvz/internal/domain/product"
+)
+
+// Lang язык сообщений
+type Lang string
+
+const (
+	// Russian русский язык, используется в HTTP API по умолчанию
+	Russian Lang = "ru"
+	// English английский язык, используется в gRPC API по умолчанию
+	English Lang = "en"
+)
+
+// DefaultLang язык, на котором есть все сообщения каталога
+const DefaultLang = Russian
+
+type contextKey struct{}
+
+// Supported сообщает, есть ли в каталоге сообщения на языке
+func Supported(lang Lang) bool {
+	_, ok := catalogue[lang]
+	return ok
+}
+
+// ParseAcceptLanguage выбирает язык из значения заголовка Accept-Language
+// с учетом весов q. Региональные варианты (en-US) сводятся к основному языку.
+// Если ни один язык не поддерживается, возвращается fallback.
+func ParseAcceptLanguage(header string, fallback Lang) Lang {
+	type candidate struct {
+		lang Lang
+		q    float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if tag == "" {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q <= 0 {
+			continue
+		}
+
+		base, _, _ := strings.Cut(tag, "-")
+		lang := Lang(strings.ToLower(base))
+		if tag == "*" {
+			lang = fallback
+		}
+		if Supported(lang) {
+			candidates = append(candidates, candidate{lang: lang, q: q})
+		}
+	}
+
+	if len(candidates) == 0 {
+		return fallback
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].q > candidates[j].q
+	})
+	return candidates[0].lang
+}
+
+// WithLang сохраняет язык сообщений в контексте запроса
+func WithLang(ctx context.Context, lang Lang) context.Context {
+	return context.WithValue(ctx, contextKey{}, lang)
+}
+
+// LangFromContext возвращает язык сообщений из контекста или DefaultLang
+func LangFromContext(ctx context.Context) Lang {
+	if lang, ok := ctx.Value(contextKey{}).(Lang); ok {
+		return lang
+	}
+	return DefaultLang
+}
+
+// Message возвращает сообщение по ключу на языке lang.
+// Если перевода нет, возвращается сообщение на DefaultLang, а если нет и его — сам ключ.
+func Message(lang Lang, key string) string {
+	if msg, ok := catalogue[lang][key]; ok {
+		return msg
+	}
+	if msg, ok := catalogue[DefaultLang][key]; ok {
+		return msg
+	}
+	return key
+}
+
+// ProductTypeName возвращает отображаемое название типа товара
+func ProductTypeName(lang Lang, t product.Type) string {
+	return Message(lang, "product_type."+string(t))
+}
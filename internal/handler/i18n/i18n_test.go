@@ -0,0 +1,70 @@
+package i18n
+
+import (
+	"context"
+	"testing"
+
+	"github.com/avito/pvz/internal/domain/product"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		fallback Lang
+		expected Lang
+	}{
+		{name: "пустой заголовок", header: "", fallback: Russian, expected: Russian},
+		{name: "один язык", header: "en", fallback: Russian, expected: English},
+		{name: "региональный вариант", header: "en-US", fallback: Russian, expected: English},
+		{name: "веса q", header: "ru;q=0.5, en;q=0.9", fallback: Russian, expected: English},
+		{name: "порядок при равных весах", header: "ru, en", fallback: English, expected: Russian},
+		{name: "неподдерживаемый язык", header: "de-DE, fr;q=0.8", fallback: English, expected: English},
+		{name: "неподдерживаемый язык с запасным", header: "de, en;q=0.1", fallback: Russian, expected: English},
+		{name: "любой язык", header: "*", fallback: English, expected: English},
+		{name: "нулевой вес", header: "en;q=0, ru;q=0.1", fallback: English, expected: Russian},
+		{name: "неверный вес", header: "en;q=abc", fallback: Russian, expected: Russian},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ParseAcceptLanguage(tt.header, tt.fallback))
+		})
+	}
+}
+
+func TestLangFromContext(t *testing.T) {
+	assert.Equal(t, DefaultLang, LangFromContext(context.Background()))
+	assert.Equal(t, English, LangFromContext(WithLang(context.Background(), English)))
+}
+
+func TestMessage(t *testing.T) {
+	assert.Equal(t, "ПВЗ не найден", Message(Russian, "pvz_not_found"))
+	assert.Equal(t, "PVZ not found", Message(English, "pvz_not_found"))
+	assert.Equal(t, "ПВЗ не найден", Message(Lang("de"), "pvz_not_found"))
+	assert.Equal(t, "unknown_key", Message(English, "unknown_key"))
+}
+
+func TestCatalogueKeysMatch(t *testing.T) {
+	for key := range catalogue[DefaultLang] {
+		for lang, messages := range catalogue {
+			_, ok := messages[key]
+			assert.True(t, ok, "нет перевода %s на %s", key, lang)
+		}
+	}
+	for lang, messages := range catalogue {
+		for key := range messages {
+			_, ok := catalogue[DefaultLang][key]
+			assert.True(t, ok, "ключ %s на %s отсутствует в основном каталоге", key, lang)
+		}
+	}
+}
+
+func TestProductTypeName(t *testing.T) {
+	for _, typ := range product.Types() {
+		for lang := range catalogue {
+			assert.NotEqual(t, "product_type."+string(typ), ProductTypeName(lang, typ), "нет названия %s на %s", typ, lang)
+		}
+	}
+}
@@ -0,0 +1,336 @@
+package i18n
+
+// catalogue сообщения API по языкам. Ключи сообщений об ошибках домена
+// совпадают с кодами ошибок apperror.
+var catalogue = map[Lang]map[string]string{
+	Russian: {
+		// Ошибки домена
+		"unauthorized":                    "требуется авторизация",
+		"invalid_token":                   "недействительный токен",
+		"access_denied":                   "доступ запрещен",
+		"invalid_credentials":             "неверный email или пароль",
+		"idempotency_key_reused":          "Idempotency-Key уже использован с другим запросом",
+		"idempotency_request_in_progress": "запрос с этим Idempotency-Key еще выполняется",
+		"query_too_deep":                  "слишком глубокая вложенность запроса",
+		"query_too_complex":               "слишком сложный запрос",
+		"invalid_city":                    "неверное название города",
+		"invalid_pvz_data":                "неверные данные ПВЗ",
+		"pvz_not_found":                   "ПВЗ не найден",
+		"pvz_already_exists":              "ПВЗ уже существует",
+		"pvz_version_conflict":            "ПВЗ изменен другим запросом",
+		"invalid_pagination":              "неверный формат лимита",
+		"invalid_cursor":                  "неверный формат курсора",
+		"reception_not_found":             "приемка не найдена",
+		"reception_already_open":          "у ПВЗ уже есть открытая приемка",
+		"reception_already_closed":        "приемка уже закрыта",
+		"reception_version_conflict":      "приемка изменена другим запросом",
+		"no_open_reception":               "у ПВЗ нет открытой приемки",
+		"product_not_found":               "товар не найден",
+		"invalid_product_type":            "неверный тип товара",
+		"import_empty":                    "файл не содержит товаров",
+		"import_too_large":                "слишком много товаров в файле",
+		"invalid_email":                   "неверный формат email",
+		"invalid_password":                "неверный формат пароля",
+		"invalid_role":                    "неверная роль",
+		"user_already_exists":             "пользователь уже существует",
+		"user_not_found":                  "пользователь не найден",
+		"invalid_export_format":           "неподдерживаемый формат выгрузки",
+		"invalid_date_range":              "неверный диапазон дат",
+		"invalid_sort":                    "неверное поле сортировки",
+		"invalid_filter":                  "неверное значение фильтра",
+		"invalid_page":                    "неверные параметры пагинации",
+		"webhook_not_found":               "подписка на события не найдена",
+		"webhook_delivery_not_found":      "доставка события не найдена",
+		"invalid_webhook_url":             "адрес получателя должен быть абсолютным URL со схемой http или https и публичным адресом",
+		"invalid_webhook_event_types":     "неверный список типов событий",
+		"invalid_webhook_delivery_status": "неверный статус доставки",
+		"webhook_delivery_not_dead":       "повторить можно только доставку из dead-letter",
+		"job_not_found":                   "фоновая задача не найдена",
+		"unknown_job_type":                "неизвестный тип фоновой задачи",
+		"invalid_job_params":              "неверные параметры фоновой задачи",
+		"job_type_forbidden":              "недостаточно прав для запуска задачи этого типа",
+		"too_many_jobs":                   "слишком много незавершенных фоновых задач",
+		"job_not_cancellable":             "задача уже завершена",
+		"job_result_not_ready":            "результат задачи еще не готов",
+		"invalid_refresh_token":           "недействительный токен обновления",
+		"refresh_token_reused":            "токен обновления уже использован, сессия завершена",
+		"assignment_not_found":            "закрепление не найдено",
+		"pvz_not_assigned":                "сотрудник не закреплен за этим ПВЗ",
+		"invalid_assignment_period":       "окончание закрепления должно быть позже начала",
+		"rate_limited":                    "слишком много запросов, повторите позже",
+		"login_locked":                    "вход временно заблокирован из-за неудачных попыток, повторите позже",
+		"invalid_account_token":           "ссылка недействительна, устарела или уже использована",
+		"email_already_verified":          "email уже подтвержден",
+		"service_account_not_found":       "сервисный аккаунт не найден",
+		"api_key_not_found":               "ключ API не найден или уже отозван",
+		"invalid_service_account_name":    "имя должно быть непустым и не длиннее 255 символов",
+		"invalid_api_key_scopes":          "области ключа должны быть непустым списком известных разрешений",
+		"invalid_api_key_expiry":          "время истечения ключа должно быть в будущем и не дальше максимального срока",
+		"internal_error":                  "внутренняя ошибка сервера",
+
+		// Ошибки проверки запроса
+		"invalid_body":               "неверный формат запроса",
+		"unreadable_body":            "не удалось прочитать запрос",
+		"request_too_large":          "слишком большой запрос",
+		"invalid_pvz_id":             "неверный формат ID ПВЗ",
+		"invalid_reception_id":       "неверный формат ID приемки",
+		"invalid_product_id":         "неверный формат ID товара",
+		"invalid_user_id":            "неверный формат ID пользователя",
+		"invalid_ip":                 "неверный формат IP-адреса",
+		"invalid_moderator_id":       "неверный формат ID модератора",
+		"city_required":              "город не может быть пустым",
+		"dates_required":             "требуются даты начала и окончания",
+		"invalid_date":               "неверный формат даты",
+		"invalid_start_date":         "неверный формат даты начала",
+		"invalid_end_date":           "неверный формат даты окончания",
+		"invalid_page_number":        "неверный формат страницы",
+		"invalid_limit":              "неверный формат лимита",
+		"invalid_if_match":           "неверный заголовок If-Match",
+		"invalid_dry_run":            "неверное значение dry_run",
+		"invalid_csv":                "неверный формат CSV",
+		"import_file_too_large":      "файл импорта слишком большой",
+		"import_missing_columns":     "в заголовке файла нет колонок type и barcode",
+		"empty_barcode":              "штрихкод не может быть пустым",
+		"invalid_barcode":            "неверный формат штрихкода",
+		"metadata_too_long":          "слишком длинные метаданные",
+		"idempotency_key_too_long":   "слишком длинный Idempotency-Key",
+		"query_required":             "не указан текст GraphQL-запроса",
+		"invalid_last_event_id":      "неверный Last-Event-ID",
+		"invalid_webhook_id":         "неверный формат ID подписки",
+		"invalid_delivery_id":        "неверный формат ID доставки",
+		"invalid_job_id":             "неверный формат ID задачи",
+		"refresh_token_required":     "не указан токен обновления",
+		"invalid_assignment_id":      "неверный формат ID закрепления",
+		"user_id_required":           "не указан ID пользователя",
+		"pvz_id_required":            "не указан ID ПВЗ",
+		"email_required":             "не указан email",
+		"token_required":             "не указан токен из письма",
+		"invalid_service_account_id": "неверный формат ID сервисного аккаунта",
+		"invalid_api_key_id":         "неверный формат ID ключа API",
+		"invalid_entity_id":          "неверный формат ID сущности",
+		"invalid_actor_id":           "неверный формат ID автора изменения",
+
+		// Внутренние ошибки по операциям
+		"idempotency_check_failed":      "ошибка при проверке Idempotency-Key",
+		"register_failed":               "ошибка при регистрации",
+		"login_failed":                  "ошибка при авторизации",
+		"user_get_failed":               "ошибка при получении пользователя",
+		"user_update_failed":            "ошибка при обновлении пользователя",
+		"user_delete_failed":            "ошибка при удалении пользователя",
+		"user_list_failed":              "ошибка при получении списка пользователей",
+		"pvz_create_failed":             "ошибка при создании ПВЗ",
+		"pvz_get_failed":                "ошибка при получении ПВЗ",
+		"pvz_update_failed":             "ошибка при обновлении ПВЗ",
+		"pvz_delete_failed":             "ошибка при удалении ПВЗ",
+		"pvz_list_failed":               "ошибка при получении списка ПВЗ",
+		"pvz_with_receptions_failed":    "ошибка при получении ПВЗ с приемками",
+		"reception_create_failed":       "ошибка при создании приемки",
+		"reception_get_failed":          "ошибка при получении приемки",
+		"open_reception_get_failed":     "ошибка при получении открытой приемки",
+		"reception_close_failed":        "ошибка при закрытии приемки",
+		"reception_list_failed":         "ошибка при получении списка приемок",
+		"product_create_failed":         "ошибка при добавлении товара",
+		"products_create_failed":        "ошибка при добавлении товаров",
+		"product_delete_failed":         "ошибка при удалении товара",
+		"product_get_failed":            "ошибка при получении товара",
+		"reception_products_get_failed": "ошибка при получении товаров",
+		"product_list_failed":           "ошибка при получении списка товаров",
+		"product_import_failed":         "ошибка при импорте товаров",
+		"export_failed":                 "ошибка при выгрузке приемок",
+		"events_subscribe_failed":       "ошибка при подписке на события",
+		"webhook_create_failed":         "ошибка при создании подписки",
+		"webhook_list_failed":           "ошибка при получении списка подписок",
+		"webhook_delete_failed":         "ошибка при удалении подписки",
+		"webhook_deliveries_failed":     "ошибка при получении истории доставок",
+		"webhook_redeliver_failed":      "ошибка при повторной доставке",
+		"job_submit_failed":             "ошибка при постановке задачи в очередь",
+		"job_get_failed":                "ошибка при получении задачи",
+		"job_cancel_failed":             "ошибка при отмене задачи",
+		"token_refresh_failed":          "ошибка при обновлении токена",
+		"logout_failed":                 "ошибка при выходе",
+		"assignment_create_failed":      "ошибка при закреплении за ПВЗ",
+		"assignment_list_failed":        "ошибка при получении списка закреплений",
+		"assignment_delete_failed":      "ошибка при удалении закрепления",
+		"login_unlock_failed":           "ошибка при снятии блокировки входа",
+		"password_reset_failed":         "ошибка при сбросе пароля",
+		"email_verification_failed":     "ошибка при подтверждении email",
+		"service_account_create_failed": "ошибка при создании сервисного аккаунта",
+		"service_account_list_failed":   "ошибка при получении списка сервисных аккаунтов",
+		"service_account_delete_failed": "ошибка при удалении сервисного аккаунта",
+		"api_key_create_failed":         "ошибка при выпуске ключа API",
+		"api_key_list_failed":           "ошибка при получении списка ключей API",
+		"api_key_revoke_failed":         "ошибка при отзыве ключа API",
+		"audit_list_failed":             "ошибка при получении журнала аудита",
+
+		// Названия типов товаров
+		"product_type.electronics": "электроника",
+		"product_type.clothing":    "одежда",
+		"product_type.food":        "продукты",
+		"product_type.other":       "другое",
+	},
+	English: {
+		// Ошибки домена
+		"unauthorized":                    "authorization required",
+		"invalid_token":                   "invalid token",
+		"access_denied":                   "access denied",
+		"invalid_credentials":             "invalid email or password",
+		"idempotency_key_reused":          "Idempotency-Key has already been used with a different request",
+		"idempotency_request_in_progress": "request with this Idempotency-Key is still in progress",
+		"query_too_deep":                  "query is nested too deeply",
+		"query_too_complex":               "query is too complex",
+		"invalid_city":                    "invalid city name",
+		"invalid_pvz_data":                "invalid PVZ data",
+		"pvz_not_found":                   "PVZ not found",
+		"pvz_already_exists":              "PVZ already exists",
+		"pvz_version_conflict":            "PVZ was modified by another request",
+		"invalid_pagination":              "invalid pagination parameters",
+		"invalid_cursor":                  "invalid cursor",
+		"reception_not_found":             "reception not found",
+		"reception_already_open":          "PVZ already has an open reception",
+		"reception_already_closed":        "reception is already closed",
+		"reception_version_conflict":      "reception was modified by another request",
+		"no_open_reception":               "PVZ has no open reception",
+		"product_not_found":               "product not found",
+		"invalid_product_type":            "invalid product type",
+		"import_empty":                    "import file contains no products",
+		"import_too_large":                "too many products in import file",
+		"invalid_email":                   "invalid email format",
+		"invalid_password":                "invalid password format",
+		"invalid_role":                    "invalid role",
+		"user_already_exists":             "user already exists",
+		"user_not_found":                  "user not found",
+		"invalid_export_format":           "unsupported export format",
+		"invalid_date_range":              "invalid date range",
+		"invalid_sort":                    "invalid sort field",
+		"invalid_filter":                  "invalid filter value",
+		"invalid_page":                    "invalid pagination parameters",
+		"webhook_not_found":               "webhook subscription not found",
+		"webhook_delivery_not_found":      "webhook delivery not found",
+		"invalid_webhook_url":             "webhook url must be an absolute http or https URL with a public address",
+		"invalid_webhook_event_types":     "invalid list of event types",
+		"invalid_webhook_delivery_status": "invalid delivery status",
+		"webhook_delivery_not_dead":       "only dead-lettered deliveries can be redelivered",
+		"job_not_found":                   "job not found",
+		"unknown_job_type":                "unknown job type",
+		"invalid_job_params":              "invalid job params",
+		"job_type_forbidden":              "not allowed to submit jobs of this type",
+		"too_many_jobs":                   "too many unfinished jobs",
+		"job_not_cancellable":             "job has already finished",
+		"job_result_not_ready":            "job result is not ready yet",
+		"invalid_refresh_token":           "invalid refresh token",
+		"refresh_token_reused":            "refresh token has already been used, session revoked",
+		"assignment_not_found":            "assignment not found",
+		"pvz_not_assigned":                "employee is not assigned to this PVZ",
+		"invalid_assignment_period":       "assignment must end after it starts",
+		"rate_limited":                    "too many requests, retry later",
+		"login_locked":                    "sign-in is temporarily locked after failed attempts, retry later",
+		"invalid_account_token":           "link is invalid, expired or already used",
+		"email_already_verified":          "email is already verified",
+		"service_account_not_found":       "service account not found",
+		"api_key_not_found":               "API key not found or already revoked",
+		"invalid_service_account_name":    "name must be non-empty and at most 255 characters",
+		"invalid_api_key_scopes":          "key scopes must be a non-empty list of known permissions",
+		"invalid_api_key_expiry":          "key expiry must be in the future and within the maximum lifetime",
+		"internal_error":                  "internal server error",
+
+		// Ошибки проверки запроса
+		"invalid_body":               "invalid request body",
+		"unreadable_body":            "failed to read request body",
+		"request_too_large":          "request is too large",
+		"invalid_pvz_id":             "invalid PVZ ID",
+		"invalid_reception_id":       "invalid reception ID",
+		"invalid_product_id":         "invalid product ID",
+		"invalid_user_id":            "invalid user ID",
+		"invalid_ip":                 "invalid IP address",
+		"invalid_moderator_id":       "invalid moderator ID",
+		"city_required":              "city is required",
+		"dates_required":             "start and end dates are required",
+		"invalid_date":               "invalid date format",
+		"invalid_start_date":         "invalid start date format",
+		"invalid_end_date":           "invalid end date format",
+		"invalid_page_number":        "invalid page format",
+		"invalid_limit":              "invalid limit format",
+		"invalid_if_match":           "invalid If-Match header",
+		"invalid_dry_run":            "invalid dry_run value",
+		"invalid_csv":                "invalid CSV format",
+		"import_file_too_large":      "import file is too large",
+		"import_missing_columns":     "file header must contain type and barcode columns",
+		"empty_barcode":              "barcode is required",
+		"invalid_barcode":            "invalid barcode format",
+		"metadata_too_long":          "metadata is too long",
+		"idempotency_key_too_long":   "Idempotency-Key is too long",
+		"query_required":             "GraphQL query is required",
+		"invalid_last_event_id":      "invalid Last-Event-ID",
+		"invalid_webhook_id":         "invalid webhook ID format",
+		"invalid_delivery_id":        "invalid delivery ID format",
+		"invalid_job_id":             "invalid job ID format",
+		"refresh_token_required":     "refresh token is required",
+		"invalid_assignment_id":      "invalid assignment ID format",
+		"user_id_required":           "user ID is required",
+		"pvz_id_required":            "PVZ ID is required",
+		"email_required":             "email is required",
+		"token_required":             "token from the email is required",
+		"invalid_service_account_id": "invalid service account ID format",
+		"invalid_api_key_id":         "invalid API key ID format",
+		"invalid_entity_id":          "invalid entity ID format",
+		"invalid_actor_id":           "invalid actor ID format",
+
+		// Внутренние ошибки по операциям
+		"idempotency_check_failed":      "failed to check Idempotency-Key",
+		"register_failed":               "failed to register user",
+		"login_failed":                  "failed to log in",
+		"user_get_failed":               "failed to get user",
+		"user_update_failed":            "failed to update user",
+		"user_delete_failed":            "failed to delete user",
+		"user_list_failed":              "failed to get users",
+		"pvz_create_failed":             "failed to create PVZ",
+		"pvz_get_failed":                "failed to get PVZ",
+		"pvz_update_failed":             "failed to update PVZ",
+		"pvz_delete_failed":             "failed to delete PVZ",
+		"pvz_list_failed":               "failed to get PVZs",
+		"pvz_with_receptions_failed":    "failed to get PVZs with receptions",
+		"reception_create_failed":       "failed to create reception",
+		"reception_get_failed":          "failed to get reception",
+		"open_reception_get_failed":     "failed to get open reception",
+		"reception_close_failed":        "failed to close reception",
+		"reception_list_failed":         "failed to get receptions",
+		"product_create_failed":         "failed to add product",
+		"products_create_failed":        "failed to add products",
+		"product_delete_failed":         "failed to delete product",
+		"product_get_failed":            "failed to get product",
+		"reception_products_get_failed": "failed to get products",
+		"product_list_failed":           "failed to get products",
+		"product_import_failed":         "failed to import products",
+		"export_failed":                 "failed to export receptions",
+		"events_subscribe_failed":       "failed to subscribe to events",
+		"webhook_create_failed":         "failed to create webhook subscription",
+		"webhook_list_failed":           "failed to list webhook subscriptions",
+		"webhook_delete_failed":         "failed to delete webhook subscription",
+		"webhook_deliveries_failed":     "failed to list webhook deliveries",
+		"webhook_redeliver_failed":      "failed to redeliver webhook",
+		"job_submit_failed":             "failed to submit job",
+		"job_get_failed":                "failed to get job",
+		"job_cancel_failed":             "failed to cancel job",
+		"token_refresh_failed":          "failed to refresh token",
+		"logout_failed":                 "failed to log out",
+		"assignment_create_failed":      "failed to create assignment",
+		"assignment_list_failed":        "failed to list assignments",
+		"assignment_delete_failed":      "failed to delete assignment",
+		"login_unlock_failed":           "failed to unlock sign-in",
+		"password_reset_failed":         "failed to reset password",
+		"email_verification_failed":     "failed to verify email",
+		"service_account_create_failed": "failed to create service account",
+		"service_account_list_failed":   "failed to list service accounts",
+		"service_account_delete_failed": "failed to delete service account",
+		"api_key_create_failed":         "failed to create API key",
+		"api_key_list_failed":           "failed to list API keys",
+		"api_key_revoke_failed":         "failed to revoke API key",
+		"audit_list_failed":             "failed to get audit log",
+
+		// Названия типов товаров
+		"product_type.electronics": "electronics",
+		"product_type.clothing":    "clothing",
+		"product_type.food":        "food",
+		"product_type.other":       "other",
+	},
+}
@@ -63,4 +63,13 @@ var (
 		},
 		[]string{"type"},
 	)
+
+	// Метрики ограничения частоты запросов
+	RateLimitRejected = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "rate_limit_rejected_total",
+			Help: "Общее количество запросов, отклоненных ограничением частоты",
+		},
+		[]string{"protocol", "route", "role"},
+	)
 )
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_pvzs_created_at_id;
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS idx_pvzs_created_at_id ON pvzs (created_at DESC, id DESC);
@@ -0,0 +1,2 @@
+ALTER TABLE products DROP COLUMN IF EXISTS metadata;
+ALTER TABLE products DROP COLUMN IF EXISTS barcode;
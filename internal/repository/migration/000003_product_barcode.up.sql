@@ -0,0 +1,2 @@
+ALTER TABLE products ADD COLUMN IF NOT EXISTS barcode VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE products ADD COLUMN IF NOT EXISTS metadata TEXT NOT NULL DEFAULT '';
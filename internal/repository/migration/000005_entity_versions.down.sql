@@ -0,0 +1,2 @@
+ALTER TABLE receptions DROP COLUMN IF EXISTS version;
+ALTER TABLE pvzs DROP COLUMN IF EXISTS version;
@@ -0,0 +1,2 @@
+ALTER TABLE pvzs ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE receptions ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
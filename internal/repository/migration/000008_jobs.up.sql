@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS jobs (
+    id UUID PRIMARY KEY,
+    type VARCHAR(64) NOT NULL,
+    user_id UUID NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    params BYTEA NOT NULL,
+    progress INTEGER NOT NULL DEFAULT 0,
+    result BYTEA,
+    result_content_type VARCHAR(128) NOT NULL DEFAULT '',
+    error TEXT NOT NULL DEFAULT '',
+    cancel_requested BOOLEAN NOT NULL DEFAULT FALSE,
+    lease_until TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    started_at TIMESTAMP WITH TIME ZONE,
+    finished_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_jobs_active ON jobs(created_at) WHERE status IN ('queued', 'running');
+CREATE INDEX IF NOT EXISTS idx_jobs_user_active ON jobs(user_id) WHERE status IN ('queued', 'running');
+CREATE INDEX IF NOT EXISTS idx_jobs_finished_at ON jobs(finished_at) WHERE finished_at IS NOT NULL;
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS pvz_assignments (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    pvz_id UUID NOT NULL REFERENCES pvzs(id) ON DELETE CASCADE,
+    valid_from TIMESTAMP WITH TIME ZONE NOT NULL,
+    valid_to TIMESTAMP WITH TIME ZONE,
+    created_by UUID NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    CHECK (valid_to IS NULL OR valid_to > valid_from)
+);
+
+CREATE INDEX IF NOT EXISTS idx_pvz_assignments_user_id ON pvz_assignments(user_id, pvz_id);
+CREATE INDEX IF NOT EXISTS idx_pvz_assignments_pvz_id ON pvz_assignments(pvz_id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS audit_logs;
+ALTER TABLE IF EXISTS audit_logs_legacy RENAME TO audit_logs;
@@ -0,0 +1,20 @@
+-- Журнал из ранних версий с колонками operation_type и user_id не совместим
+-- с новым форматом и сохраняется под другим именем
+ALTER TABLE IF EXISTS audit_logs RENAME TO audit_logs_legacy;
+
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id UUID PRIMARY KEY,
+    actor_id UUID,
+    entity_type VARCHAR(50) NOT NULL,
+    entity_id UUID,
+    action VARCHAR(50) NOT NULL,
+    before JSONB,
+    after JSONB,
+    request_id VARCHAR(255) NOT NULL DEFAULT '',
+    source VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_logs_created_at ON audit_logs(created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_entity ON audit_logs(entity_type, entity_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_actor_id ON audit_logs(actor_id, created_at DESC);
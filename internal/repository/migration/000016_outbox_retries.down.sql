@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_outbox_pending_aggregate;
+DROP INDEX IF EXISTS idx_outbox_pending;
+CREATE INDEX IF NOT EXISTS idx_outbox_pending ON outbox(seq) WHERE published_at IS NULL;
+
+ALTER TABLE outbox DROP COLUMN IF EXISTS dead_at;
+ALTER TABLE outbox DROP COLUMN IF EXISTS next_attempt_at;
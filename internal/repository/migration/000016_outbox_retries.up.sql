@@ -0,0 +1,6 @@
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS next_attempt_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS dead_at TIMESTAMP WITH TIME ZONE;
+
+DROP INDEX IF EXISTS idx_outbox_pending;
+CREATE INDEX IF NOT EXISTS idx_outbox_pending ON outbox(seq) WHERE published_at IS NULL AND dead_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_outbox_pending_aggregate ON outbox(aggregate_id, seq) WHERE published_at IS NULL AND dead_at IS NULL;
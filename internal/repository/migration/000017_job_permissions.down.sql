@@ -0,0 +1,2 @@
+ALTER TABLE jobs DROP COLUMN IF EXISTS lease_id;
+ALTER TABLE jobs DROP COLUMN IF EXISTS permissions;
@@ -0,0 +1,2 @@
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS permissions TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS lease_id UUID;
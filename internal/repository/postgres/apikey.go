@@ -0,0 +1,237 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/apikey"
+	"github.com/avito/pvz/internal/domain/rbac"
+	"github.com/avito/pvz/internal/repository/postgres/queries"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// APIKeyRepository реализует интерфейс apikey.Repository.
+// Методы выполняются в транзакции из контекста, если она открыта.
+type APIKeyRepository struct {
+	db *sqlx.DB
+}
+
+// NewAPIKeyRepository создает новый экземпляр APIKeyRepository
+func NewAPIKeyRepository(db *sqlx.DB) *APIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+// serviceAccountRow строка таблицы service_accounts
+type serviceAccountRow struct {
+	ID          uuid.UUID `db:"id"`
+	Name        string    `db:"name"`
+	Description string    `db:"description"`
+	CreatedBy   uuid.UUID `db:"created_by"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+func (row *serviceAccountRow) toDomain() *apikey.ServiceAccount {
+	return &apikey.ServiceAccount{
+		ID:          row.ID,
+		Name:        row.Name,
+		Description: row.Description,
+		CreatedBy:   row.CreatedBy,
+		CreatedAt:   row.CreatedAt,
+	}
+}
+
+// apiKeyRow строка таблицы api_keys
+type apiKeyRow struct {
+	ID               uuid.UUID      `db:"id"`
+	ServiceAccountID uuid.UUID      `db:"service_account_id"`
+	Name             string         `db:"name"`
+	Prefix           string         `db:"prefix"`
+	KeyHash          string         `db:"key_hash"`
+	Scopes           pq.StringArray `db:"scopes"`
+	ExpiresAt        time.Time      `db:"expires_at"`
+	LastUsedAt       *time.Time     `db:"last_used_at"`
+	CreatedBy        uuid.UUID      `db:"created_by"`
+	CreatedAt        time.Time      `db:"created_at"`
+	RevokedAt        *time.Time     `db:"revoked_at"`
+}
+
+func (row *apiKeyRow) toDomain() *apikey.Key {
+	scopes := make([]rbac.Permission, len(row.Scopes))
+	for i, s := range row.Scopes {
+		scopes[i] = rbac.Permission(s)
+	}
+
+	return &apikey.Key{
+		ID:               row.ID,
+		ServiceAccountID: row.ServiceAccountID,
+		Name:             row.Name,
+		Prefix:           row.Prefix,
+		KeyHash:          row.KeyHash,
+		Scopes:           scopes,
+		ExpiresAt:        row.ExpiresAt,
+		LastUsedAt:       row.LastUsedAt,
+		CreatedBy:        row.CreatedBy,
+		CreatedAt:        row.CreatedAt,
+		RevokedAt:        row.RevokedAt,
+	}
+}
+
+// CreateAccount сохраняет сервисный аккаунт
+func (r *APIKeyRepository) CreateAccount(ctx context.Context, a *apikey.ServiceAccount) error {
+	query, args, err := queries.CreateServiceAccount(a)
+	if err != nil {
+		return err
+	}
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, query, args...)
+	return err
+}
+
+// GetAccount получает сервисный аккаунт по ID
+func (r *APIKeyRepository) GetAccount(ctx context.Context, id uuid.UUID) (*apikey.ServiceAccount, error) {
+	query, args, err := queries.GetServiceAccount(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var row serviceAccountRow
+	err = conn(ctx, r.db).GetContext(ctx, &row, query, args...)
+	if err == sql.ErrNoRows {
+		return nil, apikey.ErrAccountNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return row.toDomain(), nil
+}
+
+// ListAccounts возвращает все сервисные аккаунты в порядке создания
+func (r *APIKeyRepository) ListAccounts(ctx context.Context) ([]*apikey.ServiceAccount, error) {
+	query, args, err := queries.ListServiceAccounts()
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []serviceAccountRow
+	if err := conn(ctx, r.db).SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, err
+	}
+
+	result := make([]*apikey.ServiceAccount, len(rows))
+	for i := range rows {
+		result[i] = rows[i].toDomain()
+	}
+
+	return result, nil
+}
+
+// DeleteAccount удаляет сервисный аккаунт вместе с его ключами
+func (r *APIKeyRepository) DeleteAccount(ctx context.Context, id uuid.UUID) error {
+	query, args, err := queries.DeleteServiceAccount(id)
+	if err != nil {
+		return err
+	}
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return apikey.ErrAccountNotFound
+	}
+
+	return nil
+}
+
+// CreateKey сохраняет ключ
+func (r *APIKeyRepository) CreateKey(ctx context.Context, k *apikey.Key) error {
+	query, args, err := queries.CreateAPIKey(k)
+	if err != nil {
+		return err
+	}
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, query, args...)
+	return err
+}
+
+// GetKeyByHash получает ключ по хешу
+func (r *APIKeyRepository) GetKeyByHash(ctx context.Context, hash string) (*apikey.Key, error) {
+	query, args, err := queries.GetAPIKeyByHash(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	var row apiKeyRow
+	err = conn(ctx, r.db).GetContext(ctx, &row, query, args...)
+	if err == sql.ErrNoRows {
+		return nil, apikey.ErrKeyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return row.toDomain(), nil
+}
+
+// ListKeys возвращает ключи сервисного аккаунта, новые первыми
+func (r *APIKeyRepository) ListKeys(ctx context.Context, accountID uuid.UUID) ([]*apikey.Key, error) {
+	query, args, err := queries.ListAPIKeys(accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []apiKeyRow
+	if err := conn(ctx, r.db).SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, err
+	}
+
+	result := make([]*apikey.Key, len(rows))
+	for i := range rows {
+		result[i] = rows[i].toDomain()
+	}
+
+	return result, nil
+}
+
+// RevokeKey отзывает ключ сервисного аккаунта
+func (r *APIKeyRepository) RevokeKey(ctx context.Context, accountID, keyID uuid.UUID, at time.Time) error {
+	query, args, err := queries.RevokeAPIKey(accountID, keyID, at)
+	if err != nil {
+		return err
+	}
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return apikey.ErrKeyNotFound
+	}
+
+	return nil
+}
+
+// TouchKey обновляет время последнего использования ключа
+func (r *APIKeyRepository) TouchKey(ctx context.Context, id uuid.UUID, at time.Time) error {
+	query, args, err := queries.TouchAPIKey(id, at)
+	if err != nil {
+		return err
+	}
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, query, args...)
+	return err
+}
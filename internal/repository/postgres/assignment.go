@@ -0,0 +1,125 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/assignment"
+	"github.com/avito/pvz/internal/repository/postgres/queries"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// AssignmentRepository реализует интерфейс assignment.Repository.
+// Методы выполняются в транзакции из контекста, если она открыта.
+type AssignmentRepository struct {
+	db *sqlx.DB
+}
+
+// NewAssignmentRepository создает новый экземпляр AssignmentRepository
+func NewAssignmentRepository(db *sqlx.DB) *AssignmentRepository {
+	return &AssignmentRepository{db: db}
+}
+
+// assignmentRow строка таблицы pvz_assignments
+type assignmentRow struct {
+	ID        uuid.UUID  `db:"id"`
+	UserID    uuid.UUID  `db:"user_id"`
+	PVZID     uuid.UUID  `db:"pvz_id"`
+	ValidFrom time.Time  `db:"valid_from"`
+	ValidTo   *time.Time `db:"valid_to"`
+	CreatedBy uuid.UUID  `db:"created_by"`
+	CreatedAt time.Time  `db:"created_at"`
+}
+
+// Create сохраняет новое закрепление
+func (r *AssignmentRepository) Create(ctx context.Context, a *assignment.Assignment) error {
+	query, args, err := queries.CreateAssignment(a)
+	if err != nil {
+		return err
+	}
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, query, args...)
+	return err
+}
+
+// Delete удаляет закрепление
+func (r *AssignmentRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query, args, err := queries.DeleteAssignment(id)
+	if err != nil {
+		return err
+	}
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return assignment.ErrNotFound
+	}
+
+	return nil
+}
+
+// List возвращает закрепления по фильтру, новые первыми
+func (r *AssignmentRepository) List(ctx context.Context, filter assignment.Filter) ([]*assignment.Assignment, error) {
+	query, args, err := queries.ListAssignments(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []assignmentRow
+	if err := conn(ctx, r.db).SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, err
+	}
+
+	assignments := make([]*assignment.Assignment, len(rows))
+	for i, row := range rows {
+		assignments[i] = &assignment.Assignment{
+			ID:        row.ID,
+			UserID:    row.UserID,
+			PVZID:     row.PVZID,
+			ValidFrom: row.ValidFrom,
+			ValidTo:   row.ValidTo,
+			CreatedBy: row.CreatedBy,
+			CreatedAt: row.CreatedAt,
+		}
+	}
+
+	return assignments, nil
+}
+
+// ActivePVZIDs возвращает ПВЗ, за которыми пользователь закреплен в момент at
+func (r *AssignmentRepository) ActivePVZIDs(ctx context.Context, userID uuid.UUID, at time.Time) ([]uuid.UUID, error) {
+	query, args, err := queries.ActiveAssignedPVZIDs(userID, at)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []uuid.UUID
+	if err := conn(ctx, r.db).SelectContext(ctx, &ids, query, args...); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// IsAssigned сообщает, закреплен ли пользователь за ПВЗ в момент at
+func (r *AssignmentRepository) IsAssigned(ctx context.Context, userID, pvzID uuid.UUID, at time.Time) (bool, error) {
+	query, args, err := queries.IsAssigned(userID, pvzID, at)
+	if err != nil {
+		return false, err
+	}
+
+	var assigned bool
+	if err := conn(ctx, r.db).GetContext(ctx, &assigned, query, args...); err != nil {
+		return false, err
+	}
+
+	return assigned, nil
+}
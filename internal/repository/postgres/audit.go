@@ -2,29 +2,115 @@ package postgres
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
+	"github.com/avito/pvz/internal/domain/audit"
+	"github.com/avito/pvz/internal/repository/postgres/queries"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 )
 
-// AuditLog реализует интерфейс audit.AuditLog для PostgreSQL
-type AuditLog struct {
+// AuditRepository реализует интерфейс audit.Repository.
+// Записи сохраняются в транзакции из контекста, если она открыта.
+type AuditRepository struct {
 	db *sqlx.DB
 }
 
+// NewAuditRepository создает новый экземпляр AuditRepository
+func NewAuditRepository(db *sqlx.DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+// auditRow строка таблицы audit_logs
+type auditRow struct {
+	ID         uuid.UUID     `db:"id"`
+	ActorID    uuid.NullUUID `db:"actor_id"`
+	EntityType string        `db:"entity_type"`
+	EntityID   uuid.NullUUID `db:"entity_id"`
+	Action     string        `db:"action"`
+	Before     []byte        `db:"before"`
+	After      []byte        `db:"after"`
+	RequestID  string        `db:"request_id"`
+	Source     string        `db:"source"`
+	CreatedAt  time.Time     `db:"created_at"`
+}
+
+func (row *auditRow) toDomain() *audit.Entry {
+	return &audit.Entry{
+		ID:         row.ID,
+		ActorID:    row.ActorID.UUID,
+		EntityType: audit.EntityType(row.EntityType),
+		EntityID:   row.EntityID.UUID,
+		Action:     audit.Action(row.Action),
+		Before:     json.RawMessage(row.Before),
+		After:      json.RawMessage(row.After),
+		RequestID:  row.RequestID,
+		Source:     audit.Source(row.Source),
+		CreatedAt:  row.CreatedAt,
+	}
+}
+
+// Record записывает записи журнала
+func (r *AuditRepository) Record(ctx context.Context, entries ...*audit.Entry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	query, args, err := queries.InsertAuditEntries(entries)
+	if err != nil {
+		return err
+	}
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, query, args...)
+	return err
+}
+
+// List возвращает записи журнала по фильтру, новые первыми
+func (r *AuditRepository) List(ctx context.Context, filter audit.Filter) ([]*audit.Entry, error) {
+	query, args, err := queries.ListAuditEntries(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []auditRow
+	if err := conn(ctx, r.db).SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, err
+	}
+
+	result := make([]*audit.Entry, len(rows))
+	for i := range rows {
+		result[i] = rows[i].toDomain()
+	}
+
+	return result, nil
+}
+
+// AuditLog реализует интерфейс audit.AuditLog для PostgreSQL.
+// События безопасности записываются в общий журнал аудита.
+type AuditLog struct {
+	repo *AuditRepository
+}
+
 // NewAuditLog создает новый экземпляр AuditLog
 func NewAuditLog(db *sqlx.DB) *AuditLog {
-	return &AuditLog{db: db}
+	return &AuditLog{repo: NewAuditRepository(db)}
+}
+
+// LogLoginLockout логирует блокировку входа
+func (a *AuditLog) LogLoginLockout(ctx context.Context, key string, userID uuid.UUID, lockedUntil time.Time) error {
+	return a.record(ctx, uuid.Nil, userID, audit.ActionLock, audit.State{"key": key, "lockedUntil": lockedUntil})
 }
 
-// LogPVZCreation логирует создание ПВЗ
-func (a *AuditLog) LogPVZCreation(ctx context.Context, pvzID, userID uuid.UUID) error {
-	query := `
-		INSERT INTO audit_logs (operation_type, entity_id, user_id, created_at)
-		VALUES ($1, $2, $3, $4)
-	`
+// LogLoginUnlock логирует снятие блокировки входа модератором
+func (a *AuditLog) LogLoginUnlock(ctx context.Context, key string, userID, unlockedBy uuid.UUID) error {
+	return a.record(ctx, unlockedBy, userID, audit.ActionUnlock, audit.State{"key": key})
+}
 
-	_, err := a.db.ExecContext(ctx, query, "pvz_creation", pvzID, userID, time.Now())
-	return err
+func (a *AuditLog) record(ctx context.Context, actorID, userID uuid.UUID, action audit.Action, after audit.State) error {
+	entry, err := audit.NewEntry(ctx, actorID, audit.EntityUser, userID, action, nil, after)
+	if err != nil {
+		return err
+	}
+	return a.repo.Record(ctx, entry)
 }
@@ -0,0 +1,180 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/idempotency"
+	"github.com/avito/pvz/internal/repository/postgres/queries"
+	"github.com/jmoiron/sqlx"
+)
+
+// idempotencyAcquireAttempts ограничивает число попыток занять ключ,
+// если запись удаляется между вставкой и чтением
+const idempotencyAcquireAttempts = 3
+
+// IdempotencyRepository реализует интерфейс idempotency.Repository
+type IdempotencyRepository struct {
+	db *sqlx.DB
+}
+
+// NewIdempotencyRepository создает новый экземпляр IdempotencyRepository
+func NewIdempotencyRepository(db *sqlx.DB) *IdempotencyRepository {
+	return &IdempotencyRepository{db: db}
+}
+
+// idempotencyRow строка таблицы idempotency_keys
+type idempotencyRow struct {
+	UserID      string     `db:"user_id"`
+	Key         string     `db:"key"`
+	RequestHash string     `db:"request_hash"`
+	StatusCode  int        `db:"status_code"`
+	Headers     []byte     `db:"response_headers"`
+	Body        []byte     `db:"response_body"`
+	CreatedAt   time.Time  `db:"created_at"`
+	CompletedAt *time.Time `db:"completed_at"`
+}
+
+// Acquire занимает ключ идемпотентности или возвращает существующую запись
+func (r *IdempotencyRepository) Acquire(ctx context.Context, record *idempotency.Record, staleBefore, expiredBefore time.Time) (*idempotency.Record, bool, error) {
+	// Postgres хранит время с точностью до микросекунд, а Complete и Release
+	// находят запись по created_at, поэтому оно должно совпасть с сохраненным
+	record.CreatedAt = record.CreatedAt.Truncate(time.Microsecond)
+
+	for i := 0; i < idempotencyAcquireAttempts; i++ {
+		query, args, err := queries.InsertIdempotencyKey(record.UserID, record.Key, record.RequestHash, record.CreatedAt)
+		if err != nil {
+			return nil, false, err
+		}
+		acquired, err := r.exec(ctx, query, args)
+		if err != nil || acquired {
+			return nil, acquired, err
+		}
+
+		query, args, err = queries.ReclaimIdempotencyKey(record.UserID, record.Key, record.RequestHash, record.CreatedAt, staleBefore, expiredBefore)
+		if err != nil {
+			return nil, false, err
+		}
+		acquired, err = r.exec(ctx, query, args)
+		if err != nil || acquired {
+			return nil, acquired, err
+		}
+
+		existing, err := r.Get(ctx, record.UserID, record.Key)
+		if err == idempotency.ErrNotFound {
+			// Запись освободили между попытками, пробуем занять ключ снова
+			continue
+		}
+		if err != nil {
+			return nil, false, err
+		}
+		return existing, false, nil
+	}
+
+	return nil, false, idempotency.ErrNotFound
+}
+
+// Get получает запись ключа идемпотентности
+func (r *IdempotencyRepository) Get(ctx context.Context, userID, key string) (*idempotency.Record, error) {
+	query, args, err := queries.GetIdempotencyKey(userID, key)
+	if err != nil {
+		return nil, err
+	}
+
+	var row idempotencyRow
+	err = r.db.GetContext(ctx, &row, query, args...)
+	if err == sql.ErrNoRows {
+		return nil, idempotency.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	record := &idempotency.Record{
+		UserID:      row.UserID,
+		Key:         row.Key,
+		RequestHash: row.RequestHash,
+		StatusCode:  row.StatusCode,
+		Body:        row.Body,
+		CreatedAt:   row.CreatedAt,
+		CompletedAt: row.CompletedAt,
+	}
+	if len(row.Headers) > 0 {
+		if err := json.Unmarshal(row.Headers, &record.Header); err != nil {
+			return nil, err
+		}
+	}
+
+	return record, nil
+}
+
+// Complete сохраняет ответ на запрос
+func (r *IdempotencyRepository) Complete(ctx context.Context, record *idempotency.Record) error {
+	headers, err := json.Marshal(record.Header)
+	if err != nil {
+		return err
+	}
+
+	completedAt := time.Now()
+	if record.CompletedAt != nil {
+		completedAt = *record.CompletedAt
+	}
+
+	query, args, err := queries.CompleteIdempotencyKey(record.UserID, record.Key, record.CreatedAt, record.StatusCode, string(headers), record.Body, completedAt)
+	if err != nil {
+		return err
+	}
+
+	completed, err := r.exec(ctx, query, args)
+	if err != nil {
+		return err
+	}
+	if !completed {
+		return idempotency.ErrNotFound
+	}
+
+	return nil
+}
+
+// Release освобождает незавершенный ключ идемпотентности
+func (r *IdempotencyRepository) Release(ctx context.Context, record *idempotency.Record) error {
+	query, args, err := queries.ReleaseIdempotencyKey(record.UserID, record.Key, record.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.ExecContext(ctx, query, args...)
+	return err
+}
+
+// DeleteExpired удаляет записи ключей идемпотентности, созданные раньше before
+func (r *IdempotencyRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	query, args, err := queries.DeleteExpiredIdempotencyKeys(before)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// exec выполняет запрос и сообщает, была ли затронута хотя бы одна строка
+func (r *IdempotencyRepository) exec(ctx context.Context, query string, args []interface{}) (bool, error) {
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rows > 0, nil
+}
@@ -4,7 +4,8 @@ CREATE TABLE IF NOT EXISTS users (
     email VARCHAR(255) UNIQUE NOT NULL,
     password VARCHAR(255) NOT NULL,
     role VARCHAR(50) NOT NULL,
-    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    email_verified_at TIMESTAMP WITH TIME ZONE
 );
 
 -- Создание таблицы ПВЗ
@@ -12,6 +13,7 @@ CREATE TABLE IF NOT EXISTS pvzs (
     id UUID PRIMARY KEY,
     created_at TIMESTAMP WITH TIME ZONE NOT NULL,
     city VARCHAR(100) NOT NULL,
+    version BIGINT NOT NULL DEFAULT 1,
     CONSTRAINT city_check CHECK (city IN ('Москва', 'Санкт-Петербург', 'Казань'))
 );
 
@@ -21,6 +23,7 @@ CREATE TABLE IF NOT EXISTS receptions (
     date_time TIMESTAMP WITH TIME ZONE NOT NULL,
     pvz_id UUID NOT NULL REFERENCES pvzs(id),
     status VARCHAR(50) NOT NULL DEFAULT 'in_progress',
+    version BIGINT NOT NULL DEFAULT 1,
     CONSTRAINT status_check CHECK (status IN ('in_progress', 'close'))
 );
 
@@ -30,18 +33,228 @@ CREATE TABLE IF NOT EXISTS products (
     date_time TIMESTAMP WITH TIME ZONE NOT NULL,
     type VARCHAR(50) NOT NULL,
     reception_id UUID NOT NULL REFERENCES receptions(id),
+    barcode VARCHAR(64) NOT NULL DEFAULT '',
+    metadata TEXT NOT NULL DEFAULT '',
     CONSTRAINT type_check CHECK (type IN ('электроника', 'одежда', 'обувь'))
 );
 
+-- Создание таблицы ключей идемпотентности
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    user_id VARCHAR(64) NOT NULL,
+    key VARCHAR(255) NOT NULL,
+    request_hash VARCHAR(64) NOT NULL,
+    status_code INTEGER NOT NULL DEFAULT 0,
+    response_headers JSONB NOT NULL DEFAULT '{}',
+    response_body BYTEA,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY (user_id, key)
+);
+
+-- Создание таблиц подписок на события и их доставок
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    id UUID PRIMARY KEY,
+    url TEXT NOT NULL,
+    event_types TEXT[] NOT NULL,
+    secret VARCHAR(255) NOT NULL,
+    created_by UUID NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id UUID PRIMARY KEY,
+    subscription_id UUID NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+    event_type VARCHAR(64) NOT NULL,
+    payload BYTEA NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    last_status_code INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    delivered_at TIMESTAMP WITH TIME ZONE,
+    CONSTRAINT webhook_delivery_status_check CHECK (status IN ('pending', 'succeeded', 'dead'))
+);
+
+-- Создание таблицы outbox
+CREATE TABLE IF NOT EXISTS outbox (
+    seq BIGSERIAL PRIMARY KEY,
+    id UUID NOT NULL UNIQUE,
+    aggregate_type VARCHAR(64) NOT NULL,
+    aggregate_id UUID NOT NULL,
+    event_type VARCHAR(64) NOT NULL,
+    payload BYTEA NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMP WITH TIME ZONE,
+    published_at TIMESTAMP WITH TIME ZONE,
+    dead_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Создание таблицы фоновых задач
+CREATE TABLE IF NOT EXISTS jobs (
+    id UUID PRIMARY KEY,
+    type VARCHAR(64) NOT NULL,
+    user_id UUID NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    params BYTEA NOT NULL,
+    permissions TEXT[] NOT NULL DEFAULT '{}',
+    progress INTEGER NOT NULL DEFAULT 0,
+    result BYTEA,
+    result_content_type VARCHAR(128) NOT NULL DEFAULT '',
+    error TEXT NOT NULL DEFAULT '',
+    cancel_requested BOOLEAN NOT NULL DEFAULT FALSE,
+    lease_id UUID,
+    lease_until TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    started_at TIMESTAMP WITH TIME ZONE,
+    finished_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Создание таблицы токенов обновления
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id UUID PRIMARY KEY,
+    session_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    replaced_by UUID,
+    revoked_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Создание таблицы отозванных сессий и пользователей
+CREATE TABLE IF NOT EXISTS token_revocations (
+    kind VARCHAR(16) NOT NULL,
+    subject_id UUID NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (kind, subject_id)
+);
+
+-- Создание таблицы закреплений сотрудников за ПВЗ
+CREATE TABLE IF NOT EXISTS pvz_assignments (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    pvz_id UUID NOT NULL REFERENCES pvzs(id) ON DELETE CASCADE,
+    valid_from TIMESTAMP WITH TIME ZONE NOT NULL,
+    valid_to TIMESTAMP WITH TIME ZONE,
+    created_by UUID NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    CHECK (valid_to IS NULL OR valid_to > valid_from)
+);
+
+-- Создание таблицы корзин ограничения частоты запросов
+CREATE TABLE IF NOT EXISTS rate_limit_buckets (
+    key VARCHAR(512) PRIMARY KEY,
+    tokens DOUBLE PRECISION NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+-- Создание таблицы счетчиков неудачных попыток входа
+CREATE TABLE IF NOT EXISTS login_attempts (
+    key VARCHAR(320) PRIMARY KEY,
+    failures INTEGER NOT NULL DEFAULT 0,
+    last_failure_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    locked_until TIMESTAMP WITH TIME ZONE
+);
+
+-- Создание таблицы одноразовых токенов сброса пароля и подтверждения email
+CREATE TABLE IF NOT EXISTS user_tokens (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    purpose VARCHAR(32) NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE TABLE IF NOT EXISTS service_accounts (
+    id UUID PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_by UUID NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS api_keys (
+    id UUID PRIMARY KEY,
+    service_account_id UUID NOT NULL REFERENCES service_accounts(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    prefix VARCHAR(16) NOT NULL,
+    key_hash VARCHAR(64) NOT NULL UNIQUE,
+    scopes TEXT[] NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    created_by UUID NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id UUID PRIMARY KEY,
+    actor_id UUID,
+    entity_type VARCHAR(50) NOT NULL,
+    entity_id UUID,
+    action VARCHAR(50) NOT NULL,
+    before JSONB,
+    after JSONB,
+    request_id VARCHAR(255) NOT NULL DEFAULT '',
+    source VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
 -- Создание индексов
 CREATE INDEX IF NOT EXISTS idx_users_email ON users(email);
 CREATE INDEX IF NOT EXISTS idx_receptions_pvz_id ON receptions(pvz_id);
 CREATE INDEX IF NOT EXISTS idx_receptions_status ON receptions(status);
 CREATE INDEX IF NOT EXISTS idx_products_reception_id ON products(reception_id);
 CREATE INDEX IF NOT EXISTS idx_receptions_date_time ON receptions(date_time);
+CREATE INDEX IF NOT EXISTS idx_pvzs_created_at_id ON pvzs(created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_created_at ON idempotency_keys(created_at);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_subscription ON webhook_deliveries(subscription_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_outbox_pending ON outbox(seq) WHERE published_at IS NULL AND dead_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_outbox_pending_aggregate ON outbox(aggregate_id, seq) WHERE published_at IS NULL AND dead_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_outbox_published_at ON outbox(published_at) WHERE published_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_jobs_active ON jobs(created_at) WHERE status IN ('queued', 'running');
+CREATE INDEX IF NOT EXISTS idx_jobs_user_active ON jobs(user_id) WHERE status IN ('queued', 'running');
+CREATE INDEX IF NOT EXISTS idx_jobs_finished_at ON jobs(finished_at) WHERE finished_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_session_id ON refresh_tokens(session_id);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user_id ON refresh_tokens(user_id);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_expires_at ON refresh_tokens(expires_at);
+CREATE INDEX IF NOT EXISTS idx_token_revocations_expires_at ON token_revocations(expires_at);
+CREATE INDEX IF NOT EXISTS idx_pvz_assignments_user_id ON pvz_assignments(user_id, pvz_id);
+CREATE INDEX IF NOT EXISTS idx_pvz_assignments_pvz_id ON pvz_assignments(pvz_id);
+CREATE INDEX IF NOT EXISTS idx_rate_limit_buckets_updated_at ON rate_limit_buckets(updated_at);
+CREATE INDEX IF NOT EXISTS idx_login_attempts_last_failure_at ON login_attempts(last_failure_at);
+CREATE INDEX IF NOT EXISTS idx_user_tokens_user_id ON user_tokens(user_id, purpose) WHERE used_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_user_tokens_expires_at ON user_tokens(expires_at);
+CREATE INDEX IF NOT EXISTS idx_api_keys_service_account_id ON api_keys(service_account_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_created_at ON audit_logs(created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_entity ON audit_logs(entity_type, entity_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_actor_id ON audit_logs(actor_id, created_at DESC);
 
 -- Добавление комментариев к таблицам
 COMMENT ON TABLE users IS 'Таблица пользователей системы';
 COMMENT ON TABLE pvzs IS 'Таблица пунктов выдачи заказов';
 COMMENT ON TABLE receptions IS 'Таблица приемок товаров';
-COMMENT ON TABLE products IS 'Таблица товаров'; 
\ No newline at end of file
+COMMENT ON TABLE products IS 'Таблица товаров';
+COMMENT ON TABLE idempotency_keys IS 'Таблица сохраненных ответов на запросы с ключом идемпотентности';
+COMMENT ON TABLE webhook_subscriptions IS 'Таблица подписок внешних систем на события';
+COMMENT ON TABLE webhook_deliveries IS 'Таблица доставок событий по подпискам';
+COMMENT ON TABLE outbox IS 'Таблица событий, ожидающих публикации';
+COMMENT ON TABLE jobs IS 'Таблица фоновых задач пользователей';
+COMMENT ON TABLE refresh_tokens IS 'Таблица хешей токенов обновления';
+COMMENT ON TABLE token_revocations IS 'Таблица отозванных сессий и пользователей';
+COMMENT ON TABLE pvz_assignments IS 'Таблица закреплений сотрудников за ПВЗ';
+COMMENT ON TABLE rate_limit_buckets IS 'Таблица корзин ограничения частоты запросов';
+COMMENT ON TABLE login_attempts IS 'Таблица счетчиков неудачных попыток входа';
+COMMENT ON TABLE user_tokens IS 'Таблица хешей одноразовых токенов сброса пароля и подтверждения email';
+COMMENT ON TABLE service_accounts IS 'Таблица сервисных аккаунтов внешних систем';
+COMMENT ON TABLE api_keys IS 'Таблица хешей ключей API сервисных аккаунтов';
+COMMENT ON TABLE audit_logs IS 'Таблица журнала аудита изменений'; 
\ No newline at end of file
@@ -0,0 +1,229 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/job"
+	"github.com/avito/pvz/internal/domain/rbac"
+	"github.com/avito/pvz/internal/repository/postgres/queries"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// JobRepository реализует интерфейс job.Repository.
+// Методы выполняются в транзакции из контекста, если она открыта.
+type JobRepository struct {
+	db *sqlx.DB
+}
+
+// NewJobRepository создает новый экземпляр JobRepository
+func NewJobRepository(db *sqlx.DB) *JobRepository {
+	return &JobRepository{db: db}
+}
+
+// jobRow строка таблицы jobs
+type jobRow struct {
+	ID                uuid.UUID      `db:"id"`
+	Type              string         `db:"type"`
+	UserID            uuid.UUID      `db:"user_id"`
+	Status            string         `db:"status"`
+	Params            []byte         `db:"params"`
+	Permissions       pq.StringArray `db:"permissions"`
+	Progress          int            `db:"progress"`
+	Result            []byte         `db:"result"`
+	ResultContentType string         `db:"result_content_type"`
+	Error             string         `db:"error"`
+	CancelRequested   bool           `db:"cancel_requested"`
+	LeaseID           uuid.NullUUID  `db:"lease_id"`
+	LeaseUntil        *time.Time     `db:"lease_until"`
+	CreatedAt         time.Time      `db:"created_at"`
+	StartedAt         *time.Time     `db:"started_at"`
+	FinishedAt        *time.Time     `db:"finished_at"`
+}
+
+func (row jobRow) toJob() *job.Job {
+	permissions := make([]rbac.Permission, len(row.Permissions))
+	for i, p := range row.Permissions {
+		permissions[i] = rbac.Permission(p)
+	}
+
+	return &job.Job{
+		ID:                row.ID,
+		Type:              job.Type(row.Type),
+		UserID:            row.UserID,
+		Status:            job.Status(row.Status),
+		Params:            row.Params,
+		Permissions:       permissions,
+		Progress:          row.Progress,
+		Result:            row.Result,
+		ResultContentType: row.ResultContentType,
+		Error:             row.Error,
+		CancelRequested:   row.CancelRequested,
+		LeaseID:           row.LeaseID.UUID,
+		LeaseUntil:        row.LeaseUntil,
+		CreatedAt:         row.CreatedAt,
+		StartedAt:         row.StartedAt,
+		FinishedAt:        row.FinishedAt,
+	}
+}
+
+// Create ставит задачу в очередь
+func (r *JobRepository) Create(ctx context.Context, j *job.Job) error {
+	query, args, err := queries.CreateJob(j)
+	if err != nil {
+		return err
+	}
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, query, args...)
+	return err
+}
+
+// GetByID получает задачу по ID
+func (r *JobRepository) GetByID(ctx context.Context, id uuid.UUID) (*job.Job, error) {
+	query, args, err := queries.GetJob(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var row jobRow
+	err = conn(ctx, r.db).GetContext(ctx, &row, query, args...)
+	if err == sql.ErrNoRows {
+		return nil, job.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return row.toJob(), nil
+}
+
+// CountActive возвращает число незавершенных задач пользователя
+func (r *JobRepository) CountActive(ctx context.Context, userID uuid.UUID) (int, error) {
+	query, args, err := queries.CountActiveJobs(userID)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	if err := conn(ctx, r.db).GetContext(ctx, &count, query, args...); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// LockClaims захватывает блокировку выбора задач до конца транзакции
+func (r *JobRepository) LockClaims(ctx context.Context) error {
+	query, args, err := queries.LockJobClaims()
+	if err != nil {
+		return err
+	}
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, query, args...)
+	return err
+}
+
+// ClaimNext переводит в выполнение самую старую доступную задачу с новой арендой
+func (r *JobRepository) ClaimNext(ctx context.Context, now, leaseUntil time.Time, maxRunning int) (*job.Job, error) {
+	query, args, err := queries.ClaimNextJob(now, leaseUntil, uuid.New(), maxRunning)
+	if err != nil {
+		return nil, err
+	}
+
+	var row jobRow
+	err = conn(ctx, r.db).GetContext(ctx, &row, query, args...)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return row.toJob(), nil
+}
+
+// Heartbeat сохраняет прогресс, продлевает аренду и сообщает, запрошена ли отмена
+func (r *JobRepository) Heartbeat(ctx context.Context, id, leaseID uuid.UUID, progress int, leaseUntil time.Time) (bool, error) {
+	query, args, err := queries.HeartbeatJob(id, leaseID, progress, leaseUntil)
+	if err != nil {
+		return false, err
+	}
+
+	var cancelRequested bool
+	err = conn(ctx, r.db).GetContext(ctx, &cancelRequested, query, args...)
+	if err == sql.ErrNoRows {
+		return false, job.ErrLeaseLost
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return cancelRequested, nil
+}
+
+// Finish сохраняет итоговый статус, результат и ошибку задачи
+func (r *JobRepository) Finish(ctx context.Context, j *job.Job) error {
+	query, args, err := queries.FinishJob(j)
+	if err != nil {
+		return err
+	}
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return job.ErrLeaseLost
+	}
+	return nil
+}
+
+// RequestCancel отменяет задачу в очереди или запрашивает отмену выполняющейся
+func (r *JobRepository) RequestCancel(ctx context.Context, id uuid.UUID, at time.Time) error {
+	query, args, err := queries.CancelJob(id, at)
+	if err != nil {
+		return err
+	}
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows > 0 {
+		return nil
+	}
+
+	// Задача не изменилась: либо ее нет, либо она уже завершена
+	if _, err := r.GetByID(ctx, id); err != nil {
+		return err
+	}
+	return job.ErrNotCancellable
+}
+
+// DeleteFinishedBefore удаляет задачи, завершенные раньше before
+func (r *JobRepository) DeleteFinishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	query, args, err := queries.DeleteFinishedJobs(before)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
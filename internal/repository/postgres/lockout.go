@@ -0,0 +1,118 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/lockout"
+	"github.com/avito/pvz/internal/repository/postgres/queries"
+	"github.com/jmoiron/sqlx"
+)
+
+// LockoutRepository реализует интерфейс lockout.Repository.
+// Методы выполняются в транзакции из контекста, если она открыта.
+type LockoutRepository struct {
+	db *sqlx.DB
+}
+
+// NewLockoutRepository создает новый экземпляр LockoutRepository
+func NewLockoutRepository(db *sqlx.DB) *LockoutRepository {
+	return &LockoutRepository{db: db}
+}
+
+// loginAttemptsRow строка таблицы login_attempts
+type loginAttemptsRow struct {
+	Key           string     `db:"key"`
+	Failures      int        `db:"failures"`
+	LastFailureAt time.Time  `db:"last_failure_at"`
+	LockedUntil   *time.Time `db:"locked_until"`
+}
+
+func (row loginAttemptsRow) toDomain() *lockout.Counter {
+	return &lockout.Counter{
+		Key:           row.Key,
+		Failures:      row.Failures,
+		LastFailureAt: row.LastFailureAt,
+		LockedUntil:   row.LockedUntil,
+	}
+}
+
+// Get возвращает счетчик попыток входа
+func (r *LockoutRepository) Get(ctx context.Context, key string) (*lockout.Counter, error) {
+	query, args, err := queries.GetLoginAttempts(key)
+	if err != nil {
+		return nil, err
+	}
+
+	var row loginAttemptsRow
+	if err := conn(ctx, r.db).GetContext(ctx, &row, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, lockout.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return row.toDomain(), nil
+}
+
+// Lock блокирует счетчик до конца транзакции, создавая пустой при отсутствии
+func (r *LockoutRepository) Lock(ctx context.Context, key string, now time.Time) (*lockout.Counter, error) {
+	query, args, err := queries.InsertLoginAttempts(key, now)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, args...); err != nil {
+		return nil, err
+	}
+
+	query, args, err = queries.LockLoginAttempts(key)
+	if err != nil {
+		return nil, err
+	}
+
+	var row loginAttemptsRow
+	if err := conn(ctx, r.db).GetContext(ctx, &row, query, args...); err != nil {
+		return nil, err
+	}
+
+	return row.toDomain(), nil
+}
+
+// Save сохраняет счетчик попыток входа
+func (r *LockoutRepository) Save(ctx context.Context, c *lockout.Counter) error {
+	query, args, err := queries.UpdateLoginAttempts(c)
+	if err != nil {
+		return err
+	}
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, query, args...)
+	return err
+}
+
+// Delete удаляет счетчик попыток входа
+func (r *LockoutRepository) Delete(ctx context.Context, key string) error {
+	query, args, err := queries.DeleteLoginAttempts(key)
+	if err != nil {
+		return err
+	}
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, query, args...)
+	return err
+}
+
+// DeleteStale удаляет счетчики без неудач и блокировок после before
+func (r *LockoutRepository) DeleteStale(ctx context.Context, before time.Time) (int64, error) {
+	query, args, err := queries.DeleteStaleLoginAttempts(before)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,149 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/outbox"
+	"github.com/avito/pvz/internal/repository/postgres/queries"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// OutboxRepository реализует интерфейс outbox.Repository.
+// Все методы выполняются в транзакции из контекста, если она открыта.
+type OutboxRepository struct {
+	db *sqlx.DB
+}
+
+// NewOutboxRepository создает новый экземпляр OutboxRepository
+func NewOutboxRepository(db *sqlx.DB) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+// outboxRow строка таблицы outbox
+type outboxRow struct {
+	Seq           int64      `db:"seq"`
+	ID            uuid.UUID  `db:"id"`
+	AggregateType string     `db:"aggregate_type"`
+	AggregateID   uuid.UUID  `db:"aggregate_id"`
+	EventType     string     `db:"event_type"`
+	Payload       []byte     `db:"payload"`
+	CreatedAt     time.Time  `db:"created_at"`
+	Attempts      int        `db:"attempts"`
+	LastError     string     `db:"last_error"`
+	NextAttemptAt *time.Time `db:"next_attempt_at"`
+	PublishedAt   *time.Time `db:"published_at"`
+	DeadAt        *time.Time `db:"dead_at"`
+}
+
+// Add записывает сообщения
+func (r *OutboxRepository) Add(ctx context.Context, messages ...*outbox.Message) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	query, args, err := queries.InsertOutboxMessages(messages)
+	if err != nil {
+		return err
+	}
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, query, args...)
+	return err
+}
+
+// TryLock захватывает блокировку публикации до конца транзакции
+func (r *OutboxRepository) TryLock(ctx context.Context) (bool, error) {
+	query, args, err := queries.TryLockOutbox()
+	if err != nil {
+		return false, err
+	}
+
+	var locked bool
+	if err := conn(ctx, r.db).GetContext(ctx, &locked, query, args...); err != nil {
+		return false, err
+	}
+
+	return locked, nil
+}
+
+// ListPending возвращает неопубликованные сообщения, время публикации которых наступило
+func (r *OutboxRepository) ListPending(ctx context.Context, now time.Time, limit int) ([]*outbox.Message, error) {
+	query, args, err := queries.ListPendingOutbox(now, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []outboxRow
+	if err := conn(ctx, r.db).SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, err
+	}
+
+	result := make([]*outbox.Message, len(rows))
+	for i, row := range rows {
+		result[i] = &outbox.Message{
+			ID:            row.ID,
+			Sequence:      row.Seq,
+			AggregateType: row.AggregateType,
+			AggregateID:   row.AggregateID,
+			EventType:     row.EventType,
+			Payload:       row.Payload,
+			CreatedAt:     row.CreatedAt,
+			Attempts:      row.Attempts,
+			LastError:     row.LastError,
+			NextAttemptAt: row.NextAttemptAt,
+			PublishedAt:   row.PublishedAt,
+			DeadAt:        row.DeadAt,
+		}
+	}
+
+	return result, nil
+}
+
+// MarkPublished отмечает сообщение опубликованным
+func (r *OutboxRepository) MarkPublished(ctx context.Context, id uuid.UUID, at time.Time) error {
+	query, args, err := queries.MarkOutboxPublished(id, at)
+	if err != nil {
+		return err
+	}
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, query, args...)
+	return err
+}
+
+// MarkFailed увеличивает счетчик попыток и откладывает следующую попытку
+func (r *OutboxRepository) MarkFailed(ctx context.Context, id uuid.UUID, reason string, nextAttemptAt time.Time) error {
+	query, args, err := queries.MarkOutboxFailed(id, reason, nextAttemptAt)
+	if err != nil {
+		return err
+	}
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, query, args...)
+	return err
+}
+
+// MarkDead увеличивает счетчик попыток и исключает сообщение из публикации
+func (r *OutboxRepository) MarkDead(ctx context.Context, id uuid.UUID, reason string, at time.Time) error {
+	query, args, err := queries.MarkOutboxDead(id, reason, at)
+	if err != nil {
+		return err
+	}
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, query, args...)
+	return err
+}
+
+// DeletePublishedBefore удаляет сообщения, опубликованные раньше before
+func (r *OutboxRepository) DeletePublishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	query, args, err := queries.DeletePublishedOutbox(before)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
@@ -11,6 +11,9 @@ import (
 	"github.com/jmoiron/sqlx"
 )
 
+// productInsertChunkSize количество товаров в одном INSERT при пакетной вставке
+const productInsertChunkSize = 1000
+
 // ProductRepository реализует интерфейс product.Repository
 type ProductRepository struct {
 	db *sqlx.DB
@@ -28,40 +31,54 @@ func (r *ProductRepository) Create(ctx context.Context, p *product.Product) erro
 		return err
 	}
 
-	_, err = r.db.ExecContext(ctx, query, args...)
+	_, err = conn(ctx, r.db).ExecContext(ctx, query, args...)
 	return err
 }
 
-// CreateBatch создает несколько товаров в одной транзакции
+// CreateBatch создает несколько товаров в одной транзакции.
+// Внутри транзакции менеджера товары вставляются в нее, иначе открывается своя.
 func (r *ProductRepository) CreateBatch(ctx context.Context, products []*product.Product) error {
-	tx, err := r.db.BeginTx(ctx, nil)
+	if tx, ok := conn(ctx, r.db).(*sqlx.Tx); ok {
+		return insertProducts(ctx, tx, products)
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
-	defer func() {
-		if err != nil {
-			if rbErr := tx.Rollback(); rbErr != nil {
-				err = fmt.Errorf("tx err: %v, rollback err: %v", err, rbErr)
-			}
+
+	if err := insertProducts(ctx, tx, products); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("tx err: %v, rollback err: %v", err, rbErr)
 		}
-	}()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
 
-	for _, p := range products {
-		query, args, err := queries.CreateProduct(p.ID, p.DateTime, string(p.Type), p.ReceptionID)
+	return nil
+}
+
+// insertProducts вставляет товары пачками, чтобы не превысить лимит параметров запроса
+func insertProducts(ctx context.Context, exec executor, products []*product.Product) error {
+	for start := 0; start < len(products); start += productInsertChunkSize {
+		end := start + productInsertChunkSize
+		if end > len(products) {
+			end = len(products)
+		}
+
+		query, args, err := queries.CreateProducts(products[start:end])
 		if err != nil {
 			return fmt.Errorf("failed to create product query: %w", err)
 		}
 
-		_, err = tx.ExecContext(ctx, query, args...)
-		if err != nil {
+		if _, err := exec.ExecContext(ctx, query, args...); err != nil {
 			return fmt.Errorf("failed to execute product creation: %w", err)
 		}
 	}
 
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
-	}
-
 	return nil
 }
 
@@ -73,7 +90,7 @@ func (r *ProductRepository) GetByID(ctx context.Context, id uuid.UUID) (*product
 	}
 
 	var result product.Product
-	err = r.db.GetContext(ctx, &result, query, args...)
+	err = conn(ctx, r.db).GetContext(ctx, &result, query, args...)
 	if err == sql.ErrNoRows {
 		return nil, product.ErrNotFound
 	}
@@ -92,7 +109,23 @@ func (r *ProductRepository) GetByReceptionID(ctx context.Context, receptionID uu
 	}
 
 	var result []*product.Product
-	err = r.db.SelectContext(ctx, &result, query, args...)
+	err = conn(ctx, r.db).SelectContext(ctx, &result, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ListByReceptionIDs получает до limit последних товаров каждой приемки одним запросом
+func (r *ProductRepository) ListByReceptionIDs(ctx context.Context, receptionIDs []uuid.UUID, limit int) ([]*product.Product, error) {
+	query, args, err := queries.ListProductsByReceptionIDs(receptionIDs, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*product.Product
+	err = conn(ctx, r.db).SelectContext(ctx, &result, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -107,7 +140,7 @@ func (r *ProductRepository) DeleteLast(ctx context.Context, receptionID uuid.UUI
 		return err
 	}
 
-	result, err := r.db.ExecContext(ctx, query, args...)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, args...)
 	if err != nil {
 		return err
 	}
@@ -131,7 +164,23 @@ func (r *ProductRepository) List(ctx context.Context, offset, limit int) ([]*pro
 	}
 
 	var result []*product.Product
-	err = r.db.SelectContext(ctx, &result, query, args...)
+	err = conn(ctx, r.db).SelectContext(ctx, &result, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ListByFilter возвращает список товаров, отобранных и отсортированных по фильтру
+func (r *ProductRepository) ListByFilter(ctx context.Context, filter product.ListFilter) ([]*product.Product, error) {
+	query, args, err := queries.ListProductsByFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*product.Product
+	err = conn(ctx, r.db).SelectContext(ctx, &result, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -143,7 +192,7 @@ func (r *ProductRepository) List(ctx context.Context, offset, limit int) ([]*pro
 func (r *ProductRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	query := `DELETE FROM products WHERE id = $1`
 
-	result, err := r.db.ExecContext(ctx, query, id)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete product: %w", err)
 	}
@@ -169,7 +218,7 @@ func (r *ProductRepository) GetLast(ctx context.Context, receptionID uuid.UUID)
 		ORDER BY date_time DESC 
 		LIMIT 1
 	`
-	row := r.db.QueryRowContext(ctx, query, receptionID)
+	row := conn(ctx, r.db).QueryRowContext(ctx, query, receptionID)
 	product := &product.Product{}
 	err := row.Scan(&product.ID, &product.DateTime, &product.Type, &product.ReceptionID)
 	if err == sql.ErrNoRows {
@@ -185,7 +234,7 @@ func (r *ProductRepository) Update(ctx context.Context, p *product.Product) erro
 		SET type = $1, date_time = $2 
 		WHERE id = $3
 	`
-	result, err := r.db.ExecContext(ctx, query, p.Type, p.DateTime, p.ID)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, p.Type, p.DateTime, p.ID)
 	if err != nil {
 		return fmt.Errorf("failed to update product: %w", err)
 	}
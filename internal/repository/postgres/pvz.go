@@ -31,7 +31,7 @@ func (r *PVZRepository) Create(ctx context.Context, pvz *domainpvz.PVZ) error {
 		return err
 	}
 
-	_, err = r.db.ExecContext(ctx, query, args...)
+	_, err = conn(ctx, r.db).ExecContext(ctx, query, args...)
 	return err
 }
 
@@ -43,7 +43,7 @@ func (r *PVZRepository) GetByID(ctx context.Context, id uuid.UUID) (*domainpvz.P
 	}
 
 	var result domainpvz.PVZ
-	err = r.db.GetContext(ctx, &result, query, args...)
+	err = conn(ctx, r.db).GetContext(ctx, &result, query, args...)
 	if err == sql.ErrNoRows {
 		return nil, domainpvz.ErrNotFound
 	}
@@ -54,14 +54,30 @@ func (r *PVZRepository) GetByID(ctx context.Context, id uuid.UUID) (*domainpvz.P
 	return &result, nil
 }
 
+// GetByIDs получает ПВЗ по списку ID, отсутствующие ID пропускаются
+func (r *PVZRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*domainpvz.PVZ, error) {
+	query, args, err := queries.GetPVZsByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*domainpvz.PVZ
+	err = conn(ctx, r.db).SelectContext(ctx, &result, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
 // Update обновляет данные ПВЗ
 func (r *PVZRepository) Update(ctx context.Context, pvz *domainpvz.PVZ) error {
-	query, args, err := queries.UpdatePVZ(pvz.ID, pvz.City)
+	query, args, err := queries.UpdatePVZ(pvz.ID, pvz.City, pvz.Version)
 	if err != nil {
 		return err
 	}
 
-	result, err := r.db.ExecContext(ctx, query, args...)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, args...)
 	if err != nil {
 		return err
 	}
@@ -71,9 +87,14 @@ func (r *PVZRepository) Update(ctx context.Context, pvz *domainpvz.PVZ) error {
 		return err
 	}
 	if rows == 0 {
-		return domainpvz.ErrNotFound
+		// Строка не обновлена: ПВЗ либо удален, либо изменен после чтения
+		if _, err := r.GetByID(ctx, pvz.ID); err != nil {
+			return err
+		}
+		return domainpvz.ErrVersionConflict
 	}
 
+	pvz.Version++
 	return nil
 }
 
@@ -84,7 +105,7 @@ func (r *PVZRepository) Delete(ctx context.Context, id uuid.UUID) error {
 		return err
 	}
 
-	result, err := r.db.ExecContext(ctx, query, args...)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, args...)
 	if err != nil {
 		return err
 	}
@@ -108,7 +129,23 @@ func (r *PVZRepository) List(ctx context.Context, offset, limit int) ([]*domainp
 	}
 
 	var result []*domainpvz.PVZ
-	err = r.db.SelectContext(ctx, &result, query, args...)
+	err = conn(ctx, r.db).SelectContext(ctx, &result, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ListByFilter возвращает список ПВЗ, отобранных и отсортированных по фильтру
+func (r *PVZRepository) ListByFilter(ctx context.Context, filter domainpvz.ListFilter) ([]*domainpvz.PVZ, error) {
+	query, args, err := queries.ListPVZsByFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*domainpvz.PVZ
+	err = conn(ctx, r.db).SelectContext(ctx, &result, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -124,7 +161,7 @@ func (r *PVZRepository) GetByCity(ctx context.Context, city string) (*domainpvz.
 	}
 
 	var result domainpvz.PVZ
-	err = r.db.GetContext(ctx, &result, query, args...)
+	err = conn(ctx, r.db).GetContext(ctx, &result, query, args...)
 	if err == sql.ErrNoRows {
 		return nil, domainpvz.ErrNotFound
 	}
@@ -147,7 +184,7 @@ func (r *PVZRepository) GetWithReceptions(ctx context.Context, startDate, endDat
 		LIMIT $3 OFFSET $4
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, startDate, endDate, limit, offset)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, startDate, endDate, limit, offset)
 	if err != nil {
 		return nil, err
 	}
@@ -192,12 +229,86 @@ func (r *PVZRepository) GetWithReceptions(ctx context.Context, startDate, endDat
 	return result, nil
 }
 
+// GetWithReceptionsByCursor получает страницу ПВЗ с приемками за период после курсора
+func (r *PVZRepository) GetWithReceptionsByCursor(ctx context.Context, startDate, endDate time.Time, cursor *domainpvz.Cursor, limit int) ([]*domainpvz.PVZWithReceptions, *domainpvz.Cursor, error) {
+	query, args, err := queries.GetPVZsWithReceptionsByCursor(startDate, endDate, cursor, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get PVZs with receptions: %w", err)
+	}
+	defer rows.Close()
+
+	// Строки приходят упорядоченными по ПВЗ, поэтому порядок сохраняется срезом
+	result := make([]*domainpvz.PVZWithReceptions, 0)
+	receptions := make(map[uuid.UUID]*domainpvz.ReceptionWithProducts)
+	for rows.Next() {
+		var p domainpvz.PVZ
+		var rec reception.Reception
+		var productID uuid.NullUUID
+		var productDateTime sql.NullTime
+		var productType sql.NullString
+
+		err := rows.Scan(
+			&p.ID, &p.CreatedAt, &p.City,
+			&rec.ID, &rec.DateTime, &rec.Status,
+			&productID, &productDateTime, &productType,
+		)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to scan PVZ with receptions: %w", err)
+		}
+
+		if len(result) == 0 || result[len(result)-1].PVZ.ID != p.ID {
+			result = append(result, &domainpvz.PVZWithReceptions{
+				PVZ:        &p,
+				Receptions: make([]*domainpvz.ReceptionWithProducts, 0),
+			})
+		}
+		current := result[len(result)-1]
+
+		item, ok := receptions[rec.ID]
+		if !ok {
+			rec.PVZID = p.ID
+			item = &domainpvz.ReceptionWithProducts{
+				Reception: &rec,
+				Products:  make([]*product.Product, 0),
+			}
+			receptions[rec.ID] = item
+			current.Receptions = append(current.Receptions, item)
+		}
+
+		if productID.Valid {
+			item.Products = append(item.Products, &product.Product{
+				ID:          productID.UUID,
+				DateTime:    productDateTime.Time,
+				Type:        product.Type(productType.String),
+				ReceptionID: rec.ID,
+			})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	// Запрос выбирает limit+1 ПВЗ: наличие лишнего означает, что есть следующая страница
+	if len(result) <= limit {
+		return result, nil, nil
+	}
+
+	result = result[:limit]
+	last := result[limit-1].PVZ
+	return result, &domainpvz.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}, nil
+}
+
 // GetAll возвращает список всех ПВЗ
 func (r *PVZRepository) GetAll(ctx context.Context) ([]*domainpvz.PVZ, error) {
-	query := `SELECT id, city, created_at FROM pvzs ORDER BY created_at DESC`
+	query := `SELECT id, city, created_at, version FROM pvzs ORDER BY created_at DESC`
 
 	var pvzs []*domainpvz.PVZ
-	err := r.db.SelectContext(ctx, &pvzs, query)
+	err := conn(ctx, r.db).SelectContext(ctx, &pvzs, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get all PVZs: %w", err)
 	}
@@ -119,18 +119,30 @@ func TestPVZRepository_Update(t *testing.T) {
 	require.NoError(t, err)
 
 	tests := []struct {
-		name    string
-		pvz     *pvz.PVZ
-		wantErr bool
+		name        string
+		pvz         *pvz.PVZ
+		wantErr     bool
+		expectedErr error
 	}{
 		{
 			name: "успешное обновление",
 			pvz: &pvz.PVZ{
-				ID:   pvzID,
-				City: "Санкт-Петербург",
+				ID:      pvzID,
+				City:    "Санкт-Петербург",
+				Version: 1,
 			},
 			wantErr: false,
 		},
+		{
+			name: "устаревшая версия",
+			pvz: &pvz.PVZ{
+				ID:      pvzID,
+				City:    "Казань",
+				Version: 1,
+			},
+			wantErr:     true,
+			expectedErr: pvz.ErrVersionConflict,
+		},
 		{
 			name: "ПВЗ не существует",
 			pvz: &pvz.PVZ{
@@ -146,12 +158,16 @@ func TestPVZRepository_Update(t *testing.T) {
 			err := repo.Update(ctx, tt.pvz)
 			if tt.wantErr {
 				assert.Error(t, err)
+				if tt.expectedErr != nil {
+					assert.ErrorIs(t, err, tt.expectedErr)
+				}
 			} else {
 				assert.NoError(t, err)
 				// Проверяем, что ПВЗ действительно обновлен
 				updated, err := repo.GetByID(ctx, tt.pvz.ID)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.pvz.City, updated.City)
+				assert.Equal(t, tt.pvz.Version, updated.Version)
 			}
 		})
 	}
@@ -0,0 +1,99 @@
+package queries
+
+import (
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/avito/pvz/internal/domain/apikey"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// serviceAccountColumns колонки сервисного аккаунта
+var serviceAccountColumns = []string{"id", "name", "description", "created_by", "created_at"}
+
+// apiKeyColumns колонки ключа API
+var apiKeyColumns = []string{
+	"id", "service_account_id", "name", "prefix", "key_hash", "scopes",
+	"expires_at", "last_used_at", "created_by", "created_at", "revoked_at",
+}
+
+// CreateServiceAccount создает сервисный аккаунт
+func CreateServiceAccount(a *apikey.ServiceAccount) (string, []interface{}, error) {
+	return PostgresBuilder.Insert("service_accounts").
+		Columns(serviceAccountColumns...).
+		Values(FormatUUID(a.ID), a.Name, a.Description, FormatUUID(a.CreatedBy), a.CreatedAt).
+		ToSql()
+}
+
+// GetServiceAccount получает сервисный аккаунт по ID
+func GetServiceAccount(id uuid.UUID) (string, []interface{}, error) {
+	return PostgresBuilder.Select(serviceAccountColumns...).
+		From("service_accounts").
+		Where(squirrel.Eq{"id": FormatUUID(id)}).
+		ToSql()
+}
+
+// ListServiceAccounts получает все сервисные аккаунты в порядке создания
+func ListServiceAccounts() (string, []interface{}, error) {
+	return PostgresBuilder.Select(serviceAccountColumns...).
+		From("service_accounts").
+		OrderBy("created_at ASC", "id ASC").
+		ToSql()
+}
+
+// DeleteServiceAccount удаляет сервисный аккаунт
+func DeleteServiceAccount(id uuid.UUID) (string, []interface{}, error) {
+	return PostgresBuilder.Delete("service_accounts").
+		Where(squirrel.Eq{"id": FormatUUID(id)}).
+		ToSql()
+}
+
+// CreateAPIKey создает ключ API
+func CreateAPIKey(k *apikey.Key) (string, []interface{}, error) {
+	scopes := make([]string, len(k.Scopes))
+	for i, s := range k.Scopes {
+		scopes[i] = string(s)
+	}
+
+	return PostgresBuilder.Insert("api_keys").
+		Columns("id", "service_account_id", "name", "prefix", "key_hash", "scopes", "expires_at", "created_by", "created_at").
+		Values(
+			FormatUUID(k.ID), FormatUUID(k.ServiceAccountID), k.Name, k.Prefix, k.KeyHash,
+			pq.StringArray(scopes), k.ExpiresAt, FormatUUID(k.CreatedBy), k.CreatedAt,
+		).
+		ToSql()
+}
+
+// GetAPIKeyByHash получает ключ API по хешу
+func GetAPIKeyByHash(hash string) (string, []interface{}, error) {
+	return PostgresBuilder.Select(apiKeyColumns...).
+		From("api_keys").
+		Where(squirrel.Eq{"key_hash": hash}).
+		ToSql()
+}
+
+// ListAPIKeys получает ключи сервисного аккаунта, новые первыми
+func ListAPIKeys(accountID uuid.UUID) (string, []interface{}, error) {
+	return PostgresBuilder.Select(apiKeyColumns...).
+		From("api_keys").
+		Where(squirrel.Eq{"service_account_id": FormatUUID(accountID)}).
+		OrderBy("created_at DESC", "id DESC").
+		ToSql()
+}
+
+// RevokeAPIKey отзывает неотозванный ключ сервисного аккаунта
+func RevokeAPIKey(accountID, keyID uuid.UUID, at time.Time) (string, []interface{}, error) {
+	return PostgresBuilder.Update("api_keys").
+		Set("revoked_at", at).
+		Where(squirrel.Eq{"id": FormatUUID(keyID), "service_account_id": FormatUUID(accountID), "revoked_at": nil}).
+		ToSql()
+}
+
+// TouchAPIKey обновляет время последнего использования ключа API
+func TouchAPIKey(id uuid.UUID, at time.Time) (string, []interface{}, error) {
+	return PostgresBuilder.Update("api_keys").
+		Set("last_used_at", at).
+		Where(squirrel.Eq{"id": FormatUUID(id)}).
+		ToSql()
+}
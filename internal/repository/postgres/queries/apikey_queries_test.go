@@ -0,0 +1,115 @@
+package queries
+
+import (
+	"testing"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/apikey"
+	"github.com/avito/pvz/internal/domain/rbac"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateServiceAccountQuery(t *testing.T) {
+	a := &apikey.ServiceAccount{
+		ID:          uuid.New(),
+		Name:        "warehouse",
+		Description: "Складская интеграция",
+		CreatedBy:   uuid.New(),
+		CreatedAt:   time.Now(),
+	}
+
+	query, args, err := CreateServiceAccount(a)
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO service_accounts (id,name,description,created_by,created_at) VALUES ($1,$2,$3,$4,$5)", query)
+	assert.Equal(t, []interface{}{a.ID.String(), "warehouse", "Складская интеграция", a.CreatedBy.String(), a.CreatedAt}, args)
+}
+
+func TestGetServiceAccountQuery(t *testing.T) {
+	id := uuid.New()
+
+	query, args, err := GetServiceAccount(id)
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT id, name, description, created_by, created_at FROM service_accounts WHERE id = $1", query)
+	assert.Equal(t, []interface{}{id.String()}, args)
+}
+
+func TestListServiceAccountsQuery(t *testing.T) {
+	query, args, err := ListServiceAccounts()
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT id, name, description, created_by, created_at FROM service_accounts ORDER BY created_at ASC, id ASC", query)
+	assert.Empty(t, args)
+}
+
+func TestDeleteServiceAccountQuery(t *testing.T) {
+	id := uuid.New()
+
+	query, args, err := DeleteServiceAccount(id)
+	require.NoError(t, err)
+	assert.Equal(t, "DELETE FROM service_accounts WHERE id = $1", query)
+	assert.Equal(t, []interface{}{id.String()}, args)
+}
+
+func TestCreateAPIKeyQuery(t *testing.T) {
+	now := time.Now()
+	k := &apikey.Key{
+		ID:               uuid.New(),
+		ServiceAccountID: uuid.New(),
+		Name:             "prod",
+		Prefix:           "pvz_1a2b3c4d",
+		KeyHash:          "hash",
+		Scopes:           []rbac.Permission{rbac.ReceptionCreate, rbac.ProductCreate},
+		ExpiresAt:        now.Add(time.Hour),
+		CreatedBy:        uuid.New(),
+		CreatedAt:        now,
+	}
+
+	query, args, err := CreateAPIKey(k)
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO api_keys (id,service_account_id,name,prefix,key_hash,scopes,expires_at,created_by,created_at) "+
+		"VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9)", query)
+	assert.Equal(t, []interface{}{
+		k.ID.String(), k.ServiceAccountID.String(), "prod", "pvz_1a2b3c4d", "hash",
+		pq.StringArray{"reception:create", "product:create"}, k.ExpiresAt, k.CreatedBy.String(), now,
+	}, args)
+}
+
+func TestGetAPIKeyByHashQuery(t *testing.T) {
+	query, args, err := GetAPIKeyByHash("hash")
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT id, service_account_id, name, prefix, key_hash, scopes, expires_at, last_used_at, created_by, created_at, revoked_at "+
+		"FROM api_keys WHERE key_hash = $1", query)
+	assert.Equal(t, []interface{}{"hash"}, args)
+}
+
+func TestListAPIKeysQuery(t *testing.T) {
+	accountID := uuid.New()
+
+	query, args, err := ListAPIKeys(accountID)
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT id, service_account_id, name, prefix, key_hash, scopes, expires_at, last_used_at, created_by, created_at, revoked_at "+
+		"FROM api_keys WHERE service_account_id = $1 ORDER BY created_at DESC, id DESC", query)
+	assert.Equal(t, []interface{}{accountID.String()}, args)
+}
+
+func TestRevokeAPIKeyQuery(t *testing.T) {
+	now := time.Now()
+	accountID, keyID := uuid.New(), uuid.New()
+
+	query, args, err := RevokeAPIKey(accountID, keyID, now)
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE api_keys SET revoked_at = $1 WHERE id = $2 AND revoked_at IS NULL AND service_account_id = $3", query)
+	assert.Equal(t, []interface{}{now, keyID.String(), accountID.String()}, args)
+}
+
+func TestTouchAPIKeyQuery(t *testing.T) {
+	now := time.Now()
+	id := uuid.New()
+
+	query, args, err := TouchAPIKey(id, now)
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE api_keys SET last_used_at = $1 WHERE id = $2", query)
+	assert.Equal(t, []interface{}{now, id.String()}, args)
+}
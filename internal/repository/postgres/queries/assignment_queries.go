@@ -0,0 +1,72 @@
+package queries
+
+import (
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/avito/pvz/internal/domain/assignment"
+	"github.com/google/uuid"
+)
+
+// assignmentColumns колонки закрепления
+var assignmentColumns = []string{
+	"id", "user_id", "pvz_id", "valid_from", "valid_to", "created_by", "created_at",
+}
+
+// activeAssignment условие действия закрепления в момент at
+func activeAssignment(at time.Time) squirrel.Sqlizer {
+	return squirrel.And{
+		squirrel.LtOrEq{"valid_from": at},
+		squirrel.Or{squirrel.Eq{"valid_to": nil}, squirrel.Gt{"valid_to": at}},
+	}
+}
+
+// CreateAssignment сохраняет закрепление
+func CreateAssignment(a *assignment.Assignment) (string, []interface{}, error) {
+	return PostgresBuilder.Insert("pvz_assignments").
+		Columns(assignmentColumns...).
+		Values(FormatUUID(a.ID), FormatUUID(a.UserID), FormatUUID(a.PVZID), a.ValidFrom, a.ValidTo, FormatUUID(a.CreatedBy), a.CreatedAt).
+		ToSql()
+}
+
+// DeleteAssignment удаляет закрепление
+func DeleteAssignment(id uuid.UUID) (string, []interface{}, error) {
+	return PostgresBuilder.Delete("pvz_assignments").
+		Where(squirrel.Eq{"id": FormatUUID(id)}).
+		ToSql()
+}
+
+// ListAssignments возвращает закрепления по фильтру, новые первыми
+func ListAssignments(filter assignment.Filter) (string, []interface{}, error) {
+	builder := PostgresBuilder.Select(assignmentColumns...).
+		From("pvz_assignments").
+		OrderBy("created_at DESC", "id DESC")
+
+	if filter.UserID != uuid.Nil {
+		builder = builder.Where(squirrel.Eq{"user_id": FormatUUID(filter.UserID)})
+	}
+	if filter.PVZID != uuid.Nil {
+		builder = builder.Where(squirrel.Eq{"pvz_id": FormatUUID(filter.PVZID)})
+	}
+
+	return builder.ToSql()
+}
+
+// ActiveAssignedPVZIDs возвращает ПВЗ, за которыми пользователь закреплен в момент at
+func ActiveAssignedPVZIDs(userID uuid.UUID, at time.Time) (string, []interface{}, error) {
+	return PostgresBuilder.Select("DISTINCT pvz_id").
+		From("pvz_assignments").
+		Where(squirrel.Eq{"user_id": FormatUUID(userID)}).
+		Where(activeAssignment(at)).
+		ToSql()
+}
+
+// IsAssigned проверяет, закреплен ли пользователь за ПВЗ в момент at
+func IsAssigned(userID, pvzID uuid.UUID, at time.Time) (string, []interface{}, error) {
+	sub := PostgresBuilder.Select("1").
+		From("pvz_assignments").
+		Where(squirrel.Eq{"user_id": FormatUUID(userID), "pvz_id": FormatUUID(pvzID)}).
+		Where(activeAssignment(at))
+
+	return sub.Prefix("SELECT EXISTS (").Suffix(")").ToSql()
+}
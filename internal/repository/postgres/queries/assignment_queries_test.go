@@ -0,0 +1,69 @@
+package queries
+
+import (
+	"testing"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/assignment"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateAssignmentQuery(t *testing.T) {
+	now := time.Now()
+	a := &assignment.Assignment{
+		ID:        uuid.New(),
+		UserID:    uuid.New(),
+		PVZID:     uuid.New(),
+		ValidFrom: now,
+		CreatedBy: uuid.New(),
+		CreatedAt: now,
+	}
+
+	query, args, err := CreateAssignment(a)
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO pvz_assignments (id,user_id,pvz_id,valid_from,valid_to,created_by,created_at) VALUES ($1,$2,$3,$4,$5,$6,$7)", query)
+	assert.Equal(t, []interface{}{a.ID.String(), a.UserID.String(), a.PVZID.String(), now, (*time.Time)(nil), a.CreatedBy.String(), now}, args)
+}
+
+func TestDeleteAssignmentQuery(t *testing.T) {
+	id := uuid.New()
+
+	query, args, err := DeleteAssignment(id)
+	require.NoError(t, err)
+	assert.Equal(t, "DELETE FROM pvz_assignments WHERE id = $1", query)
+	assert.Equal(t, []interface{}{id.String()}, args)
+}
+
+func TestListAssignmentsQuery(t *testing.T) {
+	userID, pvzID := uuid.New(), uuid.New()
+
+	query, args, err := ListAssignments(assignment.Filter{})
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT id, user_id, pvz_id, valid_from, valid_to, created_by, created_at FROM pvz_assignments ORDER BY created_at DESC, id DESC", query)
+	assert.Empty(t, args)
+
+	query, args, err = ListAssignments(assignment.Filter{UserID: userID, PVZID: pvzID})
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT id, user_id, pvz_id, valid_from, valid_to, created_by, created_at FROM pvz_assignments "+
+		"WHERE user_id = $1 AND pvz_id = $2 ORDER BY created_at DESC, id DESC", query)
+	assert.Equal(t, []interface{}{userID.String(), pvzID.String()}, args)
+}
+
+func TestActiveAssignmentQueries(t *testing.T) {
+	userID, pvzID := uuid.New(), uuid.New()
+	at := time.Now()
+
+	query, args, err := ActiveAssignedPVZIDs(userID, at)
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT DISTINCT pvz_id FROM pvz_assignments "+
+		"WHERE user_id = $1 AND (valid_from <= $2 AND (valid_to IS NULL OR valid_to > $3))", query)
+	assert.Equal(t, []interface{}{userID.String(), at, at}, args)
+
+	query, args, err = IsAssigned(userID, pvzID, at)
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT EXISTS ( SELECT 1 FROM pvz_assignments "+
+		"WHERE pvz_id = $1 AND user_id = $2 AND (valid_from <= $3 AND (valid_to IS NULL OR valid_to > $4)) )", query)
+	assert.Equal(t, []interface{}{pvzID.String(), userID.String(), at, at}, args)
+}
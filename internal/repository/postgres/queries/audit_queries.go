@@ -0,0 +1,78 @@
+package queries
+
+import (
+	"encoding/json"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/avito/pvz/internal/domain/audit"
+	"github.com/google/uuid"
+)
+
+// auditColumns колонки записи журнала аудита
+var auditColumns = []string{
+	"id", "actor_id", "entity_type", "entity_id", "action", "before", "after",
+	"request_id", "source", "created_at",
+}
+
+// InsertAuditEntries записывает записи журнала аудита
+func InsertAuditEntries(entries []*audit.Entry) (string, []interface{}, error) {
+	builder := PostgresBuilder.Insert("audit_logs").Columns(auditColumns...)
+
+	for _, e := range entries {
+		builder = builder.Values(
+			FormatUUID(e.ID), nullUUID(e.ActorID), string(e.EntityType), nullUUID(e.EntityID), string(e.Action),
+			nullJSON(e.Before), nullJSON(e.After), e.RequestID, string(e.Source), e.CreatedAt,
+		)
+	}
+
+	return builder.ToSql()
+}
+
+// ListAuditEntries возвращает записи журнала аудита по фильтру, новые первыми
+func ListAuditEntries(filter audit.Filter) (string, []interface{}, error) {
+	builder := PostgresBuilder.Select(auditColumns...).
+		From("audit_logs")
+
+	if filter.EntityType != "" {
+		builder = builder.Where(squirrel.Eq{"entity_type": string(filter.EntityType)})
+	}
+	if filter.EntityID != uuid.Nil {
+		builder = builder.Where(squirrel.Eq{"entity_id": FormatUUID(filter.EntityID)})
+	}
+	if filter.ActorID != uuid.Nil {
+		builder = builder.Where(squirrel.Eq{"actor_id": FormatUUID(filter.ActorID)})
+	}
+	if filter.Action != "" {
+		builder = builder.Where(squirrel.Eq{"action": string(filter.Action)})
+	}
+	if filter.Source != "" {
+		builder = builder.Where(squirrel.Eq{"source": string(filter.Source)})
+	}
+	if filter.RequestID != "" {
+		builder = builder.Where(squirrel.Eq{"request_id": filter.RequestID})
+	}
+	if !filter.CreatedAt.From.IsZero() {
+		builder = builder.Where(squirrel.GtOrEq{"created_at": filter.CreatedAt.From})
+	}
+	if !filter.CreatedAt.To.IsZero() {
+		builder = builder.Where(squirrel.LtOrEq{"created_at": filter.CreatedAt.To})
+	}
+
+	return Paginate(builder.OrderBy("created_at DESC", "id DESC"), filter.Page.Offset, filter.Page.Limit)
+}
+
+// nullUUID возвращает NULL вместо uuid.Nil
+func nullUUID(id uuid.UUID) interface{} {
+	if id == uuid.Nil {
+		return nil
+	}
+	return FormatUUID(id)
+}
+
+// nullJSON возвращает NULL вместо пустого JSON
+func nullJSON(data json.RawMessage) interface{} {
+	if len(data) == 0 {
+		return nil
+	}
+	return string(data)
+}
@@ -0,0 +1,86 @@
+package queries
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/audit"
+	"github.com/avito/pvz/internal/domain/listing"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInsertAuditEntriesQuery(t *testing.T) {
+	now := time.Now()
+	e1 := &audit.Entry{
+		ID: uuid.New(), ActorID: uuid.New(), EntityType: audit.EntityPVZ, EntityID: uuid.New(), Action: audit.ActionUpdate,
+		Before: json.RawMessage(`{"city":"Москва"}`), After: json.RawMessage(`{"city":"Казань"}`),
+		RequestID: "req-1", Source: audit.SourceHTTP, CreatedAt: now,
+	}
+	// Системное изменение без пользователя и снимка до изменения
+	e2 := &audit.Entry{
+		ID: uuid.New(), EntityType: audit.EntityUser, Action: audit.ActionLock,
+		After: json.RawMessage(`{"key":"ip:10.0.0.1"}`), Source: audit.SourceSystem, CreatedAt: now,
+	}
+
+	query, args, err := InsertAuditEntries([]*audit.Entry{e1, e2})
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO audit_logs (id,actor_id,entity_type,entity_id,action,before,after,request_id,source,created_at) "+
+		"VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10),($11,$12,$13,$14,$15,$16,$17,$18,$19,$20)", query)
+	assert.Equal(t, []interface{}{
+		e1.ID.String(), e1.ActorID.String(), "pvz", e1.EntityID.String(), "update", `{"city":"Москва"}`, `{"city":"Казань"}`, "req-1", "http", now,
+		e2.ID.String(), nil, "user", nil, "lock", nil, `{"key":"ip:10.0.0.1"}`, "", "system", now,
+	}, args)
+}
+
+func TestListAuditEntriesQuery(t *testing.T) {
+	entityID, actorID := uuid.New(), uuid.New()
+	from := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, time.October, 2, 0, 0, 0, 0, time.UTC)
+	columns := "SELECT id, actor_id, entity_type, entity_id, action, before, after, request_id, source, created_at FROM audit_logs "
+
+	tests := []struct {
+		name          string
+		filter        audit.Filter
+		expectedQuery string
+		expectedArgs  []interface{}
+	}{
+		{
+			name:          "без фильтров",
+			filter:        audit.Filter{Page: listing.Page{Offset: 0, Limit: 10}},
+			expectedQuery: columns + "ORDER BY created_at DESC, id DESC LIMIT 10 OFFSET 0",
+			expectedArgs:  []interface{}{},
+		},
+		{
+			name:          "история сущности",
+			filter:        audit.Filter{EntityType: audit.EntityReception, EntityID: entityID, Page: listing.Page{Offset: 20, Limit: 10}},
+			expectedQuery: columns + "WHERE entity_type = $1 AND entity_id = $2 ORDER BY created_at DESC, id DESC LIMIT 10 OFFSET 20",
+			expectedArgs:  []interface{}{"reception", entityID.String()},
+		},
+		{
+			name: "все фильтры",
+			filter: audit.Filter{
+				ActorID:   actorID,
+				Action:    audit.ActionDelete,
+				Source:    audit.SourceGRPC,
+				RequestID: "req-1",
+				CreatedAt: listing.DateRange{From: from, To: to},
+				Page:      listing.Page{Offset: 0, Limit: 50},
+			},
+			expectedQuery: columns + "WHERE actor_id = $1 AND action = $2 AND source = $3 AND request_id = $4 AND created_at >= $5 AND created_at <= $6 " +
+				"ORDER BY created_at DESC, id DESC LIMIT 50 OFFSET 0",
+			expectedArgs: []interface{}{actorID.String(), "delete", "grpc", "req-1", from, to},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args, err := ListAuditEntries(tt.filter)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedQuery, query)
+			assert.Equal(t, tt.expectedArgs, args)
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package queries
+
+import (
+	"time"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// idempotencyColumns колонки записи ключа идемпотентности
+var idempotencyColumns = []string{
+	"user_id", "key", "request_hash", "status_code",
+	"response_headers", "response_body", "created_at", "completed_at",
+}
+
+// InsertIdempotencyKey занимает ключ идемпотентности, если он еще не занят
+func InsertIdempotencyKey(userID, key, requestHash string, createdAt time.Time) (string, []interface{}, error) {
+	return PostgresBuilder.Insert("idempotency_keys").
+		Columns("user_id", "key", "request_hash", "created_at").
+		Values(userID, key, requestHash, createdAt).
+		Suffix("ON CONFLICT (user_id, key) DO NOTHING").
+		ToSql()
+}
+
+// ReclaimIdempotencyKey занимает заново зависший или устаревший ключ идемпотентности
+func ReclaimIdempotencyKey(userID, key, requestHash string, createdAt, staleBefore, expiredBefore time.Time) (string, []interface{}, error) {
+	return PostgresBuilder.Update("idempotency_keys").
+		Set("request_hash", requestHash).
+		Set("status_code", 0).
+		Set("response_headers", "{}").
+		Set("response_body", nil).
+		Set("created_at", createdAt).
+		Set("completed_at", nil).
+		Where(squirrel.Eq{"user_id": userID, "key": key}).
+		Where(squirrel.Or{
+			squirrel.And{
+				squirrel.Eq{"completed_at": nil},
+				squirrel.Lt{"created_at": staleBefore},
+			},
+			squirrel.Lt{"created_at": expiredBefore},
+		}).
+		ToSql()
+}
+
+// GetIdempotencyKey получает запись ключа идемпотентности
+func GetIdempotencyKey(userID, key string) (string, []interface{}, error) {
+	return PostgresBuilder.Select(idempotencyColumns...).
+		From("idempotency_keys").
+		Where(squirrel.Eq{"user_id": userID, "key": key}).
+		ToSql()
+}
+
+// CompleteIdempotencyKey сохраняет ответ на запрос с ключом идемпотентности.
+// Запись обновляется, только если ключ занят тем же запросом: после LockTimeout
+// ключ может занять повтор, и created_at записи меняется.
+func CompleteIdempotencyKey(userID, key string, createdAt time.Time, statusCode int, headers string, body []byte, completedAt time.Time) (string, []interface{}, error) {
+	return PostgresBuilder.Update("idempotency_keys").
+		Set("status_code", statusCode).
+		Set("response_headers", headers).
+		Set("response_body", body).
+		Set("completed_at", completedAt).
+		Where(squirrel.Eq{"user_id": userID, "key": key, "created_at": createdAt, "completed_at": nil}).
+		ToSql()
+}
+
+// ReleaseIdempotencyKey удаляет незавершенную запись ключа идемпотентности,
+// если ключ занят тем же запросом
+func ReleaseIdempotencyKey(userID, key string, createdAt time.Time) (string, []interface{}, error) {
+	return PostgresBuilder.Delete("idempotency_keys").
+		Where(squirrel.Eq{"user_id": userID, "key": key, "created_at": createdAt, "completed_at": nil}).
+		ToSql()
+}
+
+// DeleteExpiredIdempotencyKeys удаляет записи ключей идемпотентности, созданные раньше before
+func DeleteExpiredIdempotencyKeys(before time.Time) (string, []interface{}, error) {
+	return PostgresBuilder.Delete("idempotency_keys").
+		Where(squirrel.Lt{"created_at": before}).
+		ToSql()
+}
@@ -0,0 +1,72 @@
+package queries
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInsertIdempotencyKeyQuery(t *testing.T) {
+	createdAt := time.Now()
+
+	query, args, err := InsertIdempotencyKey("user-1", "key-1", "hash", createdAt)
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO idempotency_keys (user_id,key,request_hash,created_at) VALUES ($1,$2,$3,$4) ON CONFLICT (user_id, key) DO NOTHING", query)
+	assert.Equal(t, []interface{}{"user-1", "key-1", "hash", createdAt}, args)
+}
+
+func TestReclaimIdempotencyKeyQuery(t *testing.T) {
+	now := time.Now()
+	staleBefore := now.Add(-time.Minute)
+	expiredBefore := now.Add(-24 * time.Hour)
+
+	query, args, err := ReclaimIdempotencyKey("user-1", "key-1", "hash", now, staleBefore, expiredBefore)
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE idempotency_keys SET request_hash = $1, status_code = $2, response_headers = $3, response_body = $4, created_at = $5, completed_at = $6 "+
+		"WHERE key = $7 AND user_id = $8 AND ((completed_at IS NULL AND created_at < $9) OR created_at < $10)", query)
+	assert.Equal(t, []interface{}{"hash", 0, "{}", nil, now, nil, "key-1", "user-1", staleBefore, expiredBefore}, args)
+}
+
+func TestGetIdempotencyKeyQuery(t *testing.T) {
+	query, args, err := GetIdempotencyKey("user-1", "key-1")
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT user_id, key, request_hash, status_code, response_headers, response_body, created_at, completed_at "+
+		"FROM idempotency_keys WHERE key = $1 AND user_id = $2", query)
+	assert.Equal(t, []interface{}{"key-1", "user-1"}, args)
+}
+
+func TestCompleteIdempotencyKeyQuery(t *testing.T) {
+	createdAt := time.Now().Add(-time.Second)
+	completedAt := time.Now()
+	body := []byte(`{"id":"1"}`)
+
+	query, args, err := CompleteIdempotencyKey("user-1", "key-1", createdAt, 201, `{"Content-Type":["application/json"]}`, body, completedAt)
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE idempotency_keys SET status_code = $1, response_headers = $2, response_body = $3, completed_at = $4 "+
+		"WHERE completed_at IS NULL AND created_at = $5 AND key = $6 AND user_id = $7", query)
+	assert.Len(t, args, 7)
+	assert.Equal(t, 201, args[0])
+	assert.Equal(t, body, args[2])
+	assert.Equal(t, completedAt, args[3])
+	assert.Equal(t, createdAt, args[4])
+}
+
+func TestReleaseIdempotencyKeyQuery(t *testing.T) {
+	createdAt := time.Now()
+
+	query, args, err := ReleaseIdempotencyKey("user-1", "key-1", createdAt)
+	require.NoError(t, err)
+	assert.Equal(t, "DELETE FROM idempotency_keys WHERE completed_at IS NULL AND created_at = $1 AND key = $2 AND user_id = $3", query)
+	assert.Equal(t, []interface{}{createdAt, "key-1", "user-1"}, args)
+}
+
+func TestDeleteExpiredIdempotencyKeysQuery(t *testing.T) {
+	before := time.Now()
+
+	query, args, err := DeleteExpiredIdempotencyKeys(before)
+	require.NoError(t, err)
+	assert.Equal(t, "DELETE FROM idempotency_keys WHERE created_at < $1", query)
+	assert.Equal(t, []interface{}{before}, args)
+}
@@ -0,0 +1,136 @@
+package queries
+
+import (
+	"strings"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/avito/pvz/internal/domain/job"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// jobClaimLockKey ключ advisory-блокировки выбора задач
+const jobClaimLockKey = 7_340_002
+
+// jobColumns колонки задачи
+var jobColumns = []string{
+	"id", "type", "user_id", "status", "params", "permissions", "progress", "result", "result_content_type",
+	"error", "cancel_requested", "lease_id", "lease_until", "created_at", "started_at", "finished_at",
+}
+
+// activeJobStatuses статусы незавершенных задач
+var activeJobStatuses = []string{string(job.StatusQueued), string(job.StatusRunning)}
+
+// CreateJob ставит задачу в очередь
+func CreateJob(j *job.Job) (string, []interface{}, error) {
+	permissions := make([]string, len(j.Permissions))
+	for i, p := range j.Permissions {
+		permissions[i] = string(p)
+	}
+
+	return PostgresBuilder.Insert("jobs").
+		Columns("id", "type", "user_id", "status", "params", "permissions", "created_at").
+		Values(FormatUUID(j.ID), string(j.Type), FormatUUID(j.UserID), string(j.Status), j.Params, pq.StringArray(permissions), j.CreatedAt).
+		ToSql()
+}
+
+// GetJob получает задачу по ID
+func GetJob(id uuid.UUID) (string, []interface{}, error) {
+	return PostgresBuilder.Select(jobColumns...).
+		From("jobs").
+		Where(squirrel.Eq{"id": FormatUUID(id)}).
+		ToSql()
+}
+
+// CountActiveJobs считает незавершенные задачи пользователя
+func CountActiveJobs(userID uuid.UUID) (string, []interface{}, error) {
+	return PostgresBuilder.Select("COUNT(*)").
+		From("jobs").
+		Where(squirrel.Eq{"user_id": FormatUUID(userID), "status": activeJobStatuses}).
+		ToSql()
+}
+
+// LockJobClaims захватывает блокировку выбора задач до конца транзакции
+func LockJobClaims() (string, []interface{}, error) {
+	return PostgresBuilder.Select().
+		Column(squirrel.Expr("pg_advisory_xact_lock(?)", jobClaimLockKey)).
+		ToSql()
+}
+
+// ClaimNextJob переводит в выполнение самую старую доступную задачу и выдает ей аренду leaseID.
+// Задача доступна, если она в очереди или ее аренда истекла, а у ее владельца
+// выполняется меньше maxRunning задач с действующей арендой.
+func ClaimNextJob(now, leaseUntil time.Time, leaseID uuid.UUID, maxRunning int) (string, []interface{}, error) {
+	// Подзапросы собираются без нумерации параметров: ее задает внешний запрос
+	running := squirrel.Select("COUNT(*)").
+		From("jobs r").
+		Where("r.user_id = j.user_id").
+		Where(squirrel.Eq{"r.status": string(job.StatusRunning)}).
+		Where(squirrel.GtOrEq{"r.lease_until": now})
+
+	next := squirrel.Select("j.id").
+		From("jobs j").
+		Where(squirrel.Or{
+			squirrel.Eq{"j.status": string(job.StatusQueued)},
+			squirrel.And{
+				squirrel.Eq{"j.status": string(job.StatusRunning)},
+				squirrel.Lt{"j.lease_until": now},
+			},
+		}).
+		Where(squirrel.Expr("(?) < ?", running, maxRunning)).
+		OrderBy("j.created_at ASC").
+		Limit(1).
+		Suffix("FOR UPDATE SKIP LOCKED")
+
+	return PostgresBuilder.Update("jobs").
+		Set("status", string(job.StatusRunning)).
+		Set("lease_id", FormatUUID(leaseID)).
+		Set("lease_until", leaseUntil).
+		Set("started_at", squirrel.Expr("COALESCE(started_at, ?)", now)).
+		Where(squirrel.Expr("id = (?)", next)).
+		Suffix("RETURNING " + strings.Join(jobColumns, ", ")).
+		ToSql()
+}
+
+// HeartbeatJob сохраняет прогресс задачи и продлевает аренду leaseID
+func HeartbeatJob(id, leaseID uuid.UUID, progress int, leaseUntil time.Time) (string, []interface{}, error) {
+	return PostgresBuilder.Update("jobs").
+		Set("progress", progress).
+		Set("lease_until", leaseUntil).
+		Where(squirrel.Eq{"id": FormatUUID(id), "status": string(job.StatusRunning), "lease_id": FormatUUID(leaseID)}).
+		Suffix("RETURNING cancel_requested").
+		ToSql()
+}
+
+// FinishJob сохраняет итог выполнения задачи, если ее аренда принадлежит обработчику
+func FinishJob(j *job.Job) (string, []interface{}, error) {
+	return PostgresBuilder.Update("jobs").
+		Set("status", string(j.Status)).
+		Set("progress", j.Progress).
+		Set("result", j.Result).
+		Set("result_content_type", j.ResultContentType).
+		Set("error", j.Error).
+		Set("lease_until", nil).
+		Set("finished_at", j.FinishedAt).
+		Where(squirrel.Eq{"id": FormatUUID(j.ID), "status": string(job.StatusRunning), "lease_id": FormatUUID(j.LeaseID)}).
+		ToSql()
+}
+
+// CancelJob отменяет задачу в очереди и запрашивает отмену выполняющейся
+func CancelJob(id uuid.UUID, at time.Time) (string, []interface{}, error) {
+	queued := string(job.StatusQueued)
+	return PostgresBuilder.Update("jobs").
+		Set("cancel_requested", true).
+		Set("status", squirrel.Expr("CASE WHEN status = ? THEN ? ELSE status END", queued, string(job.StatusCancelled))).
+		Set("finished_at", squirrel.Expr("CASE WHEN status = ? THEN ? ELSE finished_at END", queued, at)).
+		Where(squirrel.Eq{"id": FormatUUID(id), "status": activeJobStatuses}).
+		ToSql()
+}
+
+// DeleteFinishedJobs удаляет задачи, завершенные раньше before
+func DeleteFinishedJobs(before time.Time) (string, []interface{}, error) {
+	return PostgresBuilder.Delete("jobs").
+		Where(squirrel.Lt{"finished_at": before}).
+		ToSql()
+}
@@ -0,0 +1,120 @@
+package queries
+
+import (
+	"testing"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/job"
+	"github.com/avito/pvz/internal/domain/rbac"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const jobColumnList = "id, type, user_id, status, params, permissions, progress, result, result_content_type, " +
+	"error, cancel_requested, lease_id, lease_until, created_at, started_at, finished_at"
+
+func TestCreateJobQuery(t *testing.T) {
+	j := job.New("export.receptions", uuid.New(), []byte(`{"format":"csv"}`))
+	j.Permissions = []rbac.Permission{rbac.PVZAccessAll, rbac.ReceptionExport}
+
+	query, args, err := CreateJob(j)
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO jobs (id,type,user_id,status,params,permissions,created_at) VALUES ($1,$2,$3,$4,$5,$6,$7)", query)
+	assert.Equal(t, []interface{}{
+		j.ID.String(), "export.receptions", j.UserID.String(), "queued", j.Params,
+		pq.StringArray{"pvz:access_all", "reception:export"}, j.CreatedAt,
+	}, args)
+}
+
+func TestGetJobQuery(t *testing.T) {
+	id := uuid.New()
+
+	query, args, err := GetJob(id)
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT "+jobColumnList+" FROM jobs WHERE id = $1", query)
+	assert.Equal(t, []interface{}{id.String()}, args)
+}
+
+func TestCountActiveJobsQuery(t *testing.T) {
+	userID := uuid.New()
+
+	query, args, err := CountActiveJobs(userID)
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT COUNT(*) FROM jobs WHERE status IN ($1,$2) AND user_id = $3", query)
+	assert.Equal(t, []interface{}{"queued", "running", userID.String()}, args)
+}
+
+func TestLockJobClaimsQuery(t *testing.T) {
+	query, args, err := LockJobClaims()
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT pg_advisory_xact_lock($1)", query)
+	assert.Equal(t, []interface{}{jobClaimLockKey}, args)
+}
+
+func TestClaimNextJobQuery(t *testing.T) {
+	now := time.Now()
+	leaseUntil := now.Add(time.Minute)
+	leaseID := uuid.New()
+
+	query, args, err := ClaimNextJob(now, leaseUntil, leaseID, 2)
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE jobs SET status = $1, lease_id = $2, lease_until = $3, started_at = COALESCE(started_at, $4) "+
+		"WHERE id = (SELECT j.id FROM jobs j WHERE (j.status = $5 OR (j.status = $6 AND j.lease_until < $7)) "+
+		"AND (SELECT COUNT(*) FROM jobs r WHERE r.user_id = j.user_id AND r.status = $8 AND r.lease_until >= $9) < $10 "+
+		"ORDER BY j.created_at ASC LIMIT 1 FOR UPDATE SKIP LOCKED) "+
+		"RETURNING "+jobColumnList, query)
+	assert.Equal(t, []interface{}{"running", leaseID.String(), leaseUntil, now, "queued", "running", now, "running", now, 2}, args)
+}
+
+func TestHeartbeatJobQuery(t *testing.T) {
+	id, leaseID := uuid.New(), uuid.New()
+	leaseUntil := time.Now()
+
+	query, args, err := HeartbeatJob(id, leaseID, 40, leaseUntil)
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE jobs SET progress = $1, lease_until = $2 WHERE id = $3 AND lease_id = $4 AND status = $5 RETURNING cancel_requested", query)
+	assert.Equal(t, []interface{}{40, leaseUntil, id.String(), leaseID.String(), "running"}, args)
+}
+
+func TestFinishJobQuery(t *testing.T) {
+	now := time.Now()
+	j := &job.Job{
+		ID:                uuid.New(),
+		Status:            job.StatusSucceeded,
+		Progress:          100,
+		Result:            []byte("id\n"),
+		ResultContentType: "text/csv",
+		LeaseID:           uuid.New(),
+		FinishedAt:        &now,
+	}
+
+	query, args, err := FinishJob(j)
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE jobs SET status = $1, progress = $2, result = $3, result_content_type = $4, error = $5, "+
+		"lease_until = $6, finished_at = $7 WHERE id = $8 AND lease_id = $9 AND status = $10", query)
+	assert.Equal(t, []interface{}{"succeeded", 100, j.Result, "text/csv", "", nil, &now, j.ID.String(), j.LeaseID.String(), "running"}, args)
+}
+
+func TestCancelJobQuery(t *testing.T) {
+	id := uuid.New()
+	now := time.Now()
+
+	query, args, err := CancelJob(id, now)
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE jobs SET cancel_requested = $1, "+
+		"status = CASE WHEN status = $2 THEN $3 ELSE status END, "+
+		"finished_at = CASE WHEN status = $4 THEN $5 ELSE finished_at END "+
+		"WHERE id = $6 AND status IN ($7,$8)", query)
+	assert.Equal(t, []interface{}{true, "queued", "cancelled", "queued", now, id.String(), "queued", "running"}, args)
+}
+
+func TestDeleteFinishedJobsQuery(t *testing.T) {
+	before := time.Now()
+
+	query, args, err := DeleteFinishedJobs(before)
+	require.NoError(t, err)
+	assert.Equal(t, "DELETE FROM jobs WHERE finished_at < $1", query)
+	assert.Equal(t, []interface{}{before}, args)
+}
@@ -0,0 +1,65 @@
+package queries
+
+import (
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/avito/pvz/internal/domain/lockout"
+)
+
+// loginAttemptColumns колонки счетчика попыток входа
+var loginAttemptColumns = []string{"key", "failures", "last_failure_at", "locked_until"}
+
+// GetLoginAttempts получает счетчик попыток входа
+func GetLoginAttempts(key string) (string, []interface{}, error) {
+	return PostgresBuilder.Select(loginAttemptColumns...).
+		From("login_attempts").
+		Where(squirrel.Eq{"key": key}).
+		ToSql()
+}
+
+// InsertLoginAttempts создает пустой счетчик попыток входа, если его еще нет
+func InsertLoginAttempts(key string, now time.Time) (string, []interface{}, error) {
+	return PostgresBuilder.Insert("login_attempts").
+		Columns("key", "failures", "last_failure_at").
+		Values(key, 0, now).
+		Suffix("ON CONFLICT (key) DO NOTHING").
+		ToSql()
+}
+
+// LockLoginAttempts получает счетчик с блокировкой строки до конца транзакции
+func LockLoginAttempts(key string) (string, []interface{}, error) {
+	return PostgresBuilder.Select(loginAttemptColumns...).
+		From("login_attempts").
+		Where(squirrel.Eq{"key": key}).
+		Suffix("FOR UPDATE").
+		ToSql()
+}
+
+// UpdateLoginAttempts сохраняет счетчик попыток входа
+func UpdateLoginAttempts(c *lockout.Counter) (string, []interface{}, error) {
+	return PostgresBuilder.Update("login_attempts").
+		Set("failures", c.Failures).
+		Set("last_failure_at", c.LastFailureAt).
+		Set("locked_until", c.LockedUntil).
+		Where(squirrel.Eq{"key": c.Key}).
+		ToSql()
+}
+
+// DeleteLoginAttempts удаляет счетчик попыток входа
+func DeleteLoginAttempts(key string) (string, []interface{}, error) {
+	return PostgresBuilder.Delete("login_attempts").
+		Where(squirrel.Eq{"key": key}).
+		ToSql()
+}
+
+// DeleteStaleLoginAttempts удаляет счетчики без неудач и блокировок после before
+func DeleteStaleLoginAttempts(before time.Time) (string, []interface{}, error) {
+	return PostgresBuilder.Delete("login_attempts").
+		Where(squirrel.Lt{"last_failure_at": before}).
+		Where(squirrel.Or{
+			squirrel.Eq{"locked_until": nil},
+			squirrel.Lt{"locked_until": before},
+		}).
+		ToSql()
+}
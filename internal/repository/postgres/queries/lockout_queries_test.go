@@ -0,0 +1,60 @@
+package queries
+
+import (
+	"testing"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/lockout"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetLoginAttemptsQuery(t *testing.T) {
+	query, args, err := GetLoginAttempts("account:user@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT key, failures, last_failure_at, locked_until FROM login_attempts WHERE key = $1", query)
+	assert.Equal(t, []interface{}{"account:user@example.com"}, args)
+}
+
+func TestInsertLoginAttemptsQuery(t *testing.T) {
+	now := time.Now()
+
+	query, args, err := InsertLoginAttempts("ip:10.0.0.1", now)
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO login_attempts (key,failures,last_failure_at) VALUES ($1,$2,$3) ON CONFLICT (key) DO NOTHING", query)
+	assert.Equal(t, []interface{}{"ip:10.0.0.1", 0, now}, args)
+}
+
+func TestLockLoginAttemptsQuery(t *testing.T) {
+	query, args, err := LockLoginAttempts("ip:10.0.0.1")
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT key, failures, last_failure_at, locked_until FROM login_attempts WHERE key = $1 FOR UPDATE", query)
+	assert.Equal(t, []interface{}{"ip:10.0.0.1"}, args)
+}
+
+func TestUpdateLoginAttemptsQuery(t *testing.T) {
+	now := time.Now()
+	until := now.Add(15 * time.Minute)
+	c := &lockout.Counter{Key: "ip:10.0.0.1", Failures: 3, LastFailureAt: now, LockedUntil: &until}
+
+	query, args, err := UpdateLoginAttempts(c)
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE login_attempts SET failures = $1, last_failure_at = $2, locked_until = $3 WHERE key = $4", query)
+	assert.Equal(t, []interface{}{3, now, &until, "ip:10.0.0.1"}, args)
+}
+
+func TestDeleteLoginAttemptsQuery(t *testing.T) {
+	query, args, err := DeleteLoginAttempts("account:user@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "DELETE FROM login_attempts WHERE key = $1", query)
+	assert.Equal(t, []interface{}{"account:user@example.com"}, args)
+}
+
+func TestDeleteStaleLoginAttemptsQuery(t *testing.T) {
+	before := time.Now()
+
+	query, args, err := DeleteStaleLoginAttempts(before)
+	require.NoError(t, err)
+	assert.Equal(t, "DELETE FROM login_attempts WHERE last_failure_at < $1 AND (locked_until IS NULL OR locked_until < $2)", query)
+	assert.Equal(t, []interface{}{before, before}, args)
+}
@@ -0,0 +1,93 @@
+package queries
+
+import (
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/avito/pvz/internal/domain/outbox"
+	"github.com/google/uuid"
+)
+
+// outboxLockKey ключ advisory-блокировки публикации сообщений outbox
+const outboxLockKey = 7_340_001
+
+// outboxColumns колонки сообщения outbox
+var outboxColumns = []string{
+	"seq", "id", "aggregate_type", "aggregate_id", "event_type", "payload",
+	"created_at", "attempts", "last_error", "next_attempt_at", "published_at", "dead_at",
+}
+
+// InsertOutboxMessages записывает сообщения outbox. Порядковый номер присваивает база.
+func InsertOutboxMessages(messages []*outbox.Message) (string, []interface{}, error) {
+	builder := PostgresBuilder.Insert("outbox").
+		Columns("id", "aggregate_type", "aggregate_id", "event_type", "payload", "created_at")
+
+	for _, m := range messages {
+		builder = builder.Values(FormatUUID(m.ID), m.AggregateType, FormatUUID(m.AggregateID), m.EventType, m.Payload, m.CreatedAt)
+	}
+
+	return builder.ToSql()
+}
+
+// TryLockOutbox захватывает блокировку публикации до конца транзакции
+func TryLockOutbox() (string, []interface{}, error) {
+	return PostgresBuilder.Select().
+		Column(squirrel.Expr("pg_try_advisory_xact_lock(?)", outboxLockKey)).
+		ToSql()
+}
+
+// ListPendingOutbox получает неопубликованные сообщения, время публикации которых
+// наступило, по возрастанию номера. Сообщения агрегата после неудачно
+// опубликованного не выбираются, чтобы не занимать выборку до его публикации.
+func ListPendingOutbox(now time.Time, limit int) (string, []interface{}, error) {
+	return PostgresBuilder.Select(outboxColumns...).
+		From("outbox").
+		Where(squirrel.Eq{"published_at": nil}).
+		Where(squirrel.Eq{"dead_at": nil}).
+		Where(squirrel.Or{
+			squirrel.Eq{"next_attempt_at": nil},
+			squirrel.LtOrEq{"next_attempt_at": now},
+		}).
+		Where("NOT EXISTS (SELECT 1 FROM outbox AS failed WHERE failed.aggregate_id = outbox.aggregate_id " +
+			"AND failed.seq < outbox.seq AND failed.published_at IS NULL AND failed.dead_at IS NULL AND failed.attempts > 0)").
+		OrderBy("seq ASC").
+		Limit(uint64(limit)).
+		ToSql()
+}
+
+// MarkOutboxPublished отмечает сообщение опубликованным
+func MarkOutboxPublished(id uuid.UUID, at time.Time) (string, []interface{}, error) {
+	return PostgresBuilder.Update("outbox").
+		Set("published_at", at).
+		Where(squirrel.Eq{"id": FormatUUID(id)}).
+		ToSql()
+}
+
+// MarkOutboxFailed увеличивает счетчик неудачных попыток публикации
+// и откладывает следующую попытку
+func MarkOutboxFailed(id uuid.UUID, reason string, nextAttemptAt time.Time) (string, []interface{}, error) {
+	return PostgresBuilder.Update("outbox").
+		Set("attempts", squirrel.Expr("attempts + 1")).
+		Set("last_error", reason).
+		Set("next_attempt_at", nextAttemptAt).
+		Where(squirrel.Eq{"id": FormatUUID(id)}).
+		ToSql()
+}
+
+// MarkOutboxDead увеличивает счетчик неудачных попыток публикации
+// и исключает сообщение из публикации
+func MarkOutboxDead(id uuid.UUID, reason string, at time.Time) (string, []interface{}, error) {
+	return PostgresBuilder.Update("outbox").
+		Set("attempts", squirrel.Expr("attempts + 1")).
+		Set("last_error", reason).
+		Set("dead_at", at).
+		Where(squirrel.Eq{"id": FormatUUID(id)}).
+		ToSql()
+}
+
+// DeletePublishedOutbox удаляет сообщения, опубликованные раньше before
+func DeletePublishedOutbox(before time.Time) (string, []interface{}, error) {
+	return PostgresBuilder.Delete("outbox").
+		Where(squirrel.Lt{"published_at": before}).
+		ToSql()
+}
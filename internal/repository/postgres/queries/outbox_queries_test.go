@@ -0,0 +1,84 @@
+package queries
+
+import (
+	"testing"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/outbox"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInsertOutboxMessagesQuery(t *testing.T) {
+	now := time.Now()
+	m1 := &outbox.Message{ID: uuid.New(), AggregateType: outbox.AggregateReception, AggregateID: uuid.New(), EventType: "reception.opened", Payload: []byte(`{}`), CreatedAt: now}
+	m2 := &outbox.Message{ID: uuid.New(), AggregateType: outbox.AggregateReception, AggregateID: m1.AggregateID, EventType: "product.added", Payload: []byte(`{}`), CreatedAt: now}
+
+	query, args, err := InsertOutboxMessages([]*outbox.Message{m1, m2})
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO outbox (id,aggregate_type,aggregate_id,event_type,payload,created_at) VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12)", query)
+	assert.Equal(t, []interface{}{
+		m1.ID.String(), "reception", m1.AggregateID.String(), "reception.opened", []byte(`{}`), now,
+		m2.ID.String(), "reception", m1.AggregateID.String(), "product.added", []byte(`{}`), now,
+	}, args)
+}
+
+func TestTryLockOutboxQuery(t *testing.T) {
+	query, args, err := TryLockOutbox()
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT pg_try_advisory_xact_lock($1)", query)
+	assert.Equal(t, []interface{}{outboxLockKey}, args)
+}
+
+func TestListPendingOutboxQuery(t *testing.T) {
+	now := time.Now()
+
+	query, args, err := ListPendingOutbox(now, 100)
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT seq, id, aggregate_type, aggregate_id, event_type, payload, created_at, attempts, last_error, next_attempt_at, published_at, dead_at "+
+		"FROM outbox WHERE published_at IS NULL AND dead_at IS NULL AND (next_attempt_at IS NULL OR next_attempt_at <= $1) "+
+		"AND NOT EXISTS (SELECT 1 FROM outbox AS failed WHERE failed.aggregate_id = outbox.aggregate_id "+
+		"AND failed.seq < outbox.seq AND failed.published_at IS NULL AND failed.dead_at IS NULL AND failed.attempts > 0) "+
+		"ORDER BY seq ASC LIMIT 100", query)
+	assert.Equal(t, []interface{}{now}, args)
+}
+
+func TestMarkOutboxPublishedQuery(t *testing.T) {
+	id := uuid.New()
+	now := time.Now()
+
+	query, args, err := MarkOutboxPublished(id, now)
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE outbox SET published_at = $1 WHERE id = $2", query)
+	assert.Equal(t, []interface{}{now, id.String()}, args)
+}
+
+func TestMarkOutboxFailedQuery(t *testing.T) {
+	id := uuid.New()
+	next := time.Now()
+
+	query, args, err := MarkOutboxFailed(id, "connection refused", next)
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE outbox SET attempts = attempts + 1, last_error = $1, next_attempt_at = $2 WHERE id = $3", query)
+	assert.Equal(t, []interface{}{"connection refused", next, id.String()}, args)
+}
+
+func TestMarkOutboxDeadQuery(t *testing.T) {
+	id := uuid.New()
+	now := time.Now()
+
+	query, args, err := MarkOutboxDead(id, "connection refused", now)
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE outbox SET attempts = attempts + 1, last_error = $1, dead_at = $2 WHERE id = $3", query)
+	assert.Equal(t, []interface{}{"connection refused", now, id.String()}, args)
+}
+
+func TestDeletePublishedOutboxQuery(t *testing.T) {
+	before := time.Now()
+
+	query, args, err := DeletePublishedOutbox(before)
+	require.NoError(t, err)
+	assert.Equal(t, "DELETE FROM outbox WHERE published_at < $1", query)
+	assert.Equal(t, []interface{}{before}, args)
+}
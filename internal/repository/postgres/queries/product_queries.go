@@ -4,6 +4,8 @@ import (
 	"time"
 
 	"github.com/Masterminds/squirrel"
+	"github.com/avito/pvz/internal/domain/listing"
+	"github.com/avito/pvz/internal/domain/product"
 	"github.com/google/uuid"
 )
 
@@ -15,9 +17,21 @@ func CreateProduct(id uuid.UUID, dateTime time.Time, productType string, recepti
 		ToSql()
 }
 
+// CreateProducts создает несколько товаров одним запросом
+func CreateProducts(products []*product.Product) (string, []interface{}, error) {
+	builder := PostgresBuilder.Insert("products").
+		Columns("id", "date_time", "type", "reception_id", "barcode", "metadata")
+
+	for _, p := range products {
+		builder = builder.Values(FormatUUID(p.ID), p.DateTime, string(p.Type), FormatUUID(p.ReceptionID), p.Barcode, p.Metadata)
+	}
+
+	return builder.ToSql()
+}
+
 // GetProductByID получает товар по ID
 func GetProductByID(id uuid.UUID) (string, []interface{}, error) {
-	return PostgresBuilder.Select("id", "date_time", "type", "reception_id").
+	return PostgresBuilder.Select("id", "date_time", "type", "reception_id", "barcode", "metadata").
 		From("products").
 		Where(squirrel.Eq{"id": FormatUUID(id)}).
 		ToSql()
@@ -25,13 +39,29 @@ func GetProductByID(id uuid.UUID) (string, []interface{}, error) {
 
 // GetProductsByReceptionID получает товары по ID приемки
 func GetProductsByReceptionID(receptionID uuid.UUID) (string, []interface{}, error) {
-	return PostgresBuilder.Select("id", "date_time", "type", "reception_id").
+	return PostgresBuilder.Select("id", "date_time", "type", "reception_id", "barcode", "metadata").
 		From("products").
 		Where(squirrel.Eq{"reception_id": FormatUUID(receptionID)}).
 		OrderBy("date_time DESC").
 		ToSql()
 }
 
+// ListProductsByReceptionIDs получает до limit последних товаров каждой из нескольких
+// приемок, начиная с последнего. Ограничение действует на каждую приемку отдельно.
+func ListProductsByReceptionIDs(receptionIDs []uuid.UUID, limit int) (string, []interface{}, error) {
+	// Подзапрос собирается без нумерации параметров: ее задает внешний запрос
+	ranked := squirrel.Select("id", "date_time", "type", "reception_id", "barcode", "metadata",
+		"ROW_NUMBER() OVER (PARTITION BY reception_id ORDER BY date_time DESC) AS rn").
+		From("products").
+		Where(squirrel.Eq{"reception_id": FormatUUIDs(receptionIDs)})
+
+	return PostgresBuilder.Select("id", "date_time", "type", "reception_id", "barcode", "metadata").
+		FromSelect(ranked, "ranked").
+		Where(squirrel.LtOrEq{"rn": limit}).
+		OrderBy("date_time DESC").
+		ToSql()
+}
+
 // DeleteLastProduct удаляет последний товар по ID приемки
 func DeleteLastProduct(receptionID uuid.UUID) (string, []interface{}, error) {
 	return PostgresBuilder.Delete("products").
@@ -49,3 +79,33 @@ func ListProducts(offset, limit int) (string, []interface{}, error) {
 		limit,
 	)
 }
+
+// productSortColumns задает разрешенные поля сортировки списка товаров
+var productSortColumns = SortColumns{
+	product.SortByDateTime: "date_time",
+	product.SortByType:     "type",
+}
+
+// ListProductsByFilter получает список товаров по фильтру с сортировкой и пагинацией
+func ListProductsByFilter(filter product.ListFilter) (string, []interface{}, error) {
+	builder := PostgresBuilder.Select("id", "date_time", "type", "reception_id", "barcode", "metadata").
+		From("products")
+
+	if filter.ReceptionID != uuid.Nil {
+		builder = builder.Where(squirrel.Eq{"reception_id": FormatUUID(filter.ReceptionID)})
+	}
+	if filter.Type != "" {
+		builder = builder.Where(squirrel.Eq{"type": string(filter.Type)})
+	}
+	builder = ApplyDateRange(builder, "date_time", filter.DateTime)
+
+	builder, err := ApplySort(builder, filter.Sort, productSortColumns, listing.Sort{
+		Field:     product.SortByDateTime,
+		Direction: listing.DirectionDesc,
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	return Paginate(builder, filter.Page.Offset, filter.Page.Limit)
+}
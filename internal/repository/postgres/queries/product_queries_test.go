@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"github.com/Masterminds/squirrel"
+	"github.com/avito/pvz/internal/domain/listing"
+	"github.com/avito/pvz/internal/domain/product"
 	"github.com/avito/pvz/internal/models"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -31,7 +33,7 @@ func TestGetProductByIDQuery(t *testing.T) {
 	id := uuid.New()
 	query, args, err := GetProductByID(id)
 	require.NoError(t, err)
-	assert.Equal(t, "SELECT id, date_time, type, reception_id FROM products WHERE id = $1", query)
+	assert.Equal(t, "SELECT id, date_time, type, reception_id, barcode, metadata FROM products WHERE id = $1", query)
 	assert.Len(t, args, 1)
 	assert.Equal(t, id.String(), args[0])
 }
@@ -40,10 +42,38 @@ func TestGetProductsByReceptionIDQuery(t *testing.T) {
 	receptionID := uuid.MustParse("3dff3016-2a29-40db-8d84-3c8fe1bb4354")
 	query, args, err := GetProductsByReceptionID(receptionID)
 	require.NoError(t, err)
-	assert.Equal(t, "SELECT id, date_time, type, reception_id FROM products WHERE reception_id = $1 ORDER BY date_time DESC", query)
+	assert.Equal(t, "SELECT id, date_time, type, reception_id, barcode, metadata FROM products WHERE reception_id = $1 ORDER BY date_time DESC", query)
 	assert.Equal(t, []interface{}{receptionID.String()}, args)
 }
 
+func TestListProductsByReceptionIDsQuery(t *testing.T) {
+	receptionIDs := []uuid.UUID{uuid.New(), uuid.New()}
+	query, args, err := ListProductsByReceptionIDs(receptionIDs, 20)
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT id, date_time, type, reception_id, barcode, metadata FROM ("+
+		"SELECT id, date_time, type, reception_id, barcode, metadata, ROW_NUMBER() OVER (PARTITION BY reception_id ORDER BY date_time DESC) AS rn "+
+		"FROM products WHERE reception_id IN ($1,$2)) AS ranked WHERE rn <= $3 ORDER BY date_time DESC", query)
+	assert.Equal(t, []interface{}{receptionIDs[0].String(), receptionIDs[1].String(), 20}, args)
+}
+
+func TestCreateProductsQuery(t *testing.T) {
+	receptionID := uuid.New()
+	dateTime := time.Now()
+	products := []*product.Product{
+		{ID: uuid.New(), DateTime: dateTime, Type: product.TypeFood, ReceptionID: receptionID, Barcode: "4600000000001"},
+		{ID: uuid.New(), DateTime: dateTime, Type: product.TypeOther, ReceptionID: receptionID, Barcode: "4600000000002", Metadata: "коробка 2"},
+	}
+
+	query, args, err := CreateProducts(products)
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO products (id,date_time,type,reception_id,barcode,metadata) "+
+		"VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12)", query)
+	assert.Equal(t, []interface{}{
+		products[0].ID.String(), dateTime, "food", receptionID.String(), "4600000000001", "",
+		products[1].ID.String(), dateTime, "other", receptionID.String(), "4600000000002", "коробка 2",
+	}, args)
+}
+
 func TestDeleteLastProductQuery(t *testing.T) {
 	receptionID := uuid.New()
 	query, args, err := DeleteLastProduct(receptionID)
@@ -141,3 +171,20 @@ func TestGetProductsByType(t *testing.T) {
 	assert.Equal(t, "SELECT id, date_time, type, reception_id FROM products WHERE reception_id = $1 AND type = $2", query)
 	assert.Equal(t, []interface{}{receptionID.String(), productType}, args)
 }
+
+func TestListProductsByFilterQuery(t *testing.T) {
+	receptionID := uuid.New()
+	to := time.Now()
+
+	query, args, err := ListProductsByFilter(product.ListFilter{
+		ReceptionID: receptionID,
+		Type:        product.TypeFood,
+		DateTime:    listing.DateRange{To: to},
+		Page:        listing.Page{Offset: 10, Limit: 10},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT id, date_time, type, reception_id, barcode, metadata FROM products "+
+		"WHERE reception_id = $1 AND type = $2 AND date_time <= $3 "+
+		"ORDER BY date_time DESC, id DESC LIMIT 10 OFFSET 10", query)
+	assert.Equal(t, []interface{}{receptionID.String(), "food", to}, args)
+}
@@ -4,6 +4,8 @@ import (
 	"time"
 
 	"github.com/Masterminds/squirrel"
+	"github.com/avito/pvz/internal/domain/listing"
+	"github.com/avito/pvz/internal/domain/pvz"
 	"github.com/google/uuid"
 )
 
@@ -17,17 +19,26 @@ func CreatePVZ(id uuid.UUID, createdAt time.Time, city string) (string, []interf
 
 // GetPVZByID получает ПВЗ по ID
 func GetPVZByID(id uuid.UUID) (string, []interface{}, error) {
-	return PostgresBuilder.Select("id", "created_at", "city").
+	return PostgresBuilder.Select("id", "created_at", "city", "version").
 		From("pvzs").
 		Where(squirrel.Eq{"id": FormatUUID(id)}).
 		ToSql()
 }
 
-// UpdatePVZ обновляет город ПВЗ
-func UpdatePVZ(id uuid.UUID, city string) (string, []interface{}, error) {
+// GetPVZsByIDs получает ПВЗ по списку ID
+func GetPVZsByIDs(ids []uuid.UUID) (string, []interface{}, error) {
+	return PostgresBuilder.Select("id", "created_at", "city", "version").
+		From("pvzs").
+		Where(squirrel.Eq{"id": FormatUUIDs(ids)}).
+		ToSql()
+}
+
+// UpdatePVZ обновляет город ПВЗ, если его версия не изменилась, и увеличивает версию
+func UpdatePVZ(id uuid.UUID, city string, version int64) (string, []interface{}, error) {
 	return PostgresBuilder.Update("pvzs").
 		Set("city", city).
-		Where(squirrel.Eq{"id": FormatUUID(id)}).
+		Set("version", squirrel.Expr("version + 1")).
+		Where(squirrel.Eq{"id": FormatUUID(id), "version": version}).
 		ToSql()
 }
 
@@ -41,7 +52,7 @@ func DeletePVZ(id uuid.UUID) (string, []interface{}, error) {
 // ListPVZs получает список ПВЗ с пагинацией
 func ListPVZs(offset, limit int) (string, []interface{}, error) {
 	return Paginate(
-		PostgresBuilder.Select("id", "created_at", "city").
+		PostgresBuilder.Select("id", "created_at", "city", "version").
 			From("pvzs").
 			OrderBy("created_at DESC"),
 		offset,
@@ -49,10 +60,66 @@ func ListPVZs(offset, limit int) (string, []interface{}, error) {
 	)
 }
 
+// pvzSortColumns задает разрешенные поля сортировки списка ПВЗ
+var pvzSortColumns = SortColumns{
+	pvz.SortByCreatedAt: "created_at",
+	pvz.SortByCity:      "city",
+}
+
+// ListPVZsByFilter получает список ПВЗ по фильтру с сортировкой и пагинацией
+func ListPVZsByFilter(filter pvz.ListFilter) (string, []interface{}, error) {
+	builder := PostgresBuilder.Select("id", "created_at", "city", "version").
+		From("pvzs")
+
+	if filter.City != "" {
+		builder = builder.Where(squirrel.Eq{"city": filter.City})
+	}
+	builder = ApplyDateRange(builder, "created_at", filter.CreatedAt)
+
+	builder, err := ApplySort(builder, filter.Sort, pvzSortColumns, listing.Sort{
+		Field:     pvz.SortByCreatedAt,
+		Direction: listing.DirectionDesc,
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	return Paginate(builder, filter.Page.Offset, filter.Page.Limit)
+}
+
 // GetPVZByCity получает ПВЗ по городу
 func GetPVZByCity(city string) (string, []interface{}, error) {
-	return PostgresBuilder.Select("id", "created_at", "city").
+	return PostgresBuilder.Select("id", "created_at", "city", "version").
 		From("pvzs").
 		Where(squirrel.Eq{"city": city}).
 		ToSql()
 }
+
+// GetPVZsWithReceptionsByCursor получает страницу ПВЗ с приемками и товарами за период.
+// Пагинация выполняется по ПВЗ, а не по строкам соединения: сначала выбирается
+// limit+1 ПВЗ после курсора, затем к ним присоединяются приемки и товары.
+// Лишний ПВЗ позволяет определить, есть ли следующая страница.
+func GetPVZsWithReceptionsByCursor(startDate, endDate time.Time, cursor *pvz.Cursor, limit int) (string, []interface{}, error) {
+	page := PostgresBuilder.Select("p.id", "p.created_at", "p.city").
+		From("pvzs p").
+		Where("EXISTS (SELECT 1 FROM receptions r WHERE r.pvz_id = p.id AND r.date_time BETWEEN ? AND ?)", startDate, endDate)
+
+	if cursor != nil {
+		page = page.Where("(p.created_at, p.id) < (?, ?)", cursor.CreatedAt, FormatUUID(cursor.ID))
+	}
+
+	page = page.
+		OrderBy("p.created_at DESC", "p.id DESC").
+		Limit(uint64(limit + 1))
+
+	return PostgresBuilder.Select(
+		"page.id", "page.created_at", "page.city",
+		"r.id", "r.date_time", "r.status",
+		"pr.id", "pr.date_time", "pr.type",
+	).
+		FromSelect(page, "page").
+		Join("receptions r ON r.pvz_id = page.id AND r.date_time BETWEEN ? AND ?", startDate, endDate).
+		LeftJoin("products pr ON pr.reception_id = r.id").
+		OrderBy("page.created_at DESC", "page.id DESC", "r.date_time ASC", "pr.date_time ASC").
+		ToSql()
+}
@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"github.com/Masterminds/squirrel"
+	"github.com/avito/pvz/internal/domain/listing"
+	"github.com/avito/pvz/internal/domain/pvz"
 	"github.com/avito/pvz/internal/models"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -29,21 +31,30 @@ func TestGetPVZByIDQuery(t *testing.T) {
 	id := uuid.New()
 	query, args, err := GetPVZByID(id)
 	require.NoError(t, err)
-	assert.Equal(t, "SELECT id, created_at, city FROM pvzs WHERE id = $1", query)
+	assert.Equal(t, "SELECT id, created_at, city, version FROM pvzs WHERE id = $1", query)
 	assert.Len(t, args, 1)
 	assert.Equal(t, id.String(), args[0])
 }
 
+func TestGetPVZsByIDsQuery(t *testing.T) {
+	ids := []uuid.UUID{uuid.New(), uuid.New()}
+	query, args, err := GetPVZsByIDs(ids)
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT id, created_at, city, version FROM pvzs WHERE id IN ($1,$2)", query)
+	assert.Equal(t, []interface{}{ids[0].String(), ids[1].String()}, args)
+}
+
 func TestUpdatePVZQuery(t *testing.T) {
 	id := uuid.New()
 	city := "Moscow"
 
-	query, args, err := UpdatePVZ(id, city)
+	query, args, err := UpdatePVZ(id, city, 3)
 	require.NoError(t, err)
-	assert.Equal(t, "UPDATE pvzs SET city = $1 WHERE id = $2", query)
-	assert.Len(t, args, 2)
+	assert.Equal(t, "UPDATE pvzs SET city = $1, version = version + 1 WHERE id = $2 AND version = $3", query)
+	assert.Len(t, args, 3)
 	assert.Equal(t, city, args[0])
 	assert.Equal(t, id.String(), args[1])
+	assert.Equal(t, int64(3), args[2])
 }
 
 func TestDeletePVZQuery(t *testing.T) {
@@ -58,7 +69,7 @@ func TestDeletePVZQuery(t *testing.T) {
 func TestListPVZsQuery(t *testing.T) {
 	query, args, err := ListPVZs(20, 10)
 	require.NoError(t, err)
-	assert.Equal(t, "SELECT id, created_at, city FROM pvzs ORDER BY created_at DESC LIMIT 10 OFFSET 20", query)
+	assert.Equal(t, "SELECT id, created_at, city, version FROM pvzs ORDER BY created_at DESC LIMIT 10 OFFSET 20", query)
 	assert.Equal(t, []interface{}{}, args)
 }
 
@@ -66,7 +77,7 @@ func TestGetPVZByCityQuery(t *testing.T) {
 	city := "Moscow"
 	query, args, err := GetPVZByCity(city)
 	require.NoError(t, err)
-	assert.Equal(t, "SELECT id, created_at, city FROM pvzs WHERE city = $1", query)
+	assert.Equal(t, "SELECT id, created_at, city, version FROM pvzs WHERE city = $1", query)
 	assert.Len(t, args, 1)
 	assert.Equal(t, city, args[0])
 }
@@ -155,3 +166,65 @@ func TestGetPVZByCity(t *testing.T) {
 	assert.Equal(t, "SELECT id, created_at, city FROM pvzs WHERE city = $1", query)
 	assert.Equal(t, []interface{}{"Москва"}, args)
 }
+
+func TestGetPVZsWithReceptionsByCursorQuery(t *testing.T) {
+	startDate := time.Now().Add(-24 * time.Hour)
+	endDate := time.Now()
+
+	t.Run("первая страница", func(t *testing.T) {
+		query, args, err := GetPVZsWithReceptionsByCursor(startDate, endDate, nil, 10)
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT page.id, page.created_at, page.city, r.id, r.date_time, r.status, pr.id, pr.date_time, pr.type "+
+			"FROM (SELECT p.id, p.created_at, p.city FROM pvzs p "+
+			"WHERE EXISTS (SELECT 1 FROM receptions r WHERE r.pvz_id = p.id AND r.date_time BETWEEN $1 AND $2) "+
+			"ORDER BY p.created_at DESC, p.id DESC LIMIT 11) AS page "+
+			"JOIN receptions r ON r.pvz_id = page.id AND r.date_time BETWEEN $3 AND $4 "+
+			"LEFT JOIN products pr ON pr.reception_id = r.id "+
+			"ORDER BY page.created_at DESC, page.id DESC, r.date_time ASC, pr.date_time ASC", query)
+		assert.Equal(t, []interface{}{startDate, endDate, startDate, endDate}, args)
+	})
+
+	t.Run("страница после курсора", func(t *testing.T) {
+		cursor := &pvz.Cursor{CreatedAt: time.Now().Add(-time.Hour), ID: uuid.New()}
+
+		query, args, err := GetPVZsWithReceptionsByCursor(startDate, endDate, cursor, 5)
+		require.NoError(t, err)
+		assert.Contains(t, query, "AND (p.created_at, p.id) < ($3, $4) ORDER BY p.created_at DESC, p.id DESC LIMIT 6) AS page")
+		assert.Contains(t, query, "r.date_time BETWEEN $5 AND $6")
+		assert.Equal(t, []interface{}{startDate, endDate, cursor.CreatedAt, cursor.ID.String(), startDate, endDate}, args)
+	})
+}
+
+func TestListPVZsByFilterQuery(t *testing.T) {
+	from := time.Now().Add(-24 * time.Hour)
+	to := time.Now()
+
+	t.Run("без условий", func(t *testing.T) {
+		query, args, err := ListPVZsByFilter(pvz.ListFilter{Page: listing.Page{Offset: 0, Limit: 10}})
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT id, created_at, city, version FROM pvzs ORDER BY created_at DESC, id DESC LIMIT 10 OFFSET 0", query)
+		assert.Equal(t, []interface{}{}, args)
+	})
+
+	t.Run("город, даты и сортировка", func(t *testing.T) {
+		query, args, err := ListPVZsByFilter(pvz.ListFilter{
+			City:      "Москва",
+			CreatedAt: listing.DateRange{From: from, To: to},
+			Sort:      listing.Sort{Field: pvz.SortByCity, Direction: listing.DirectionAsc},
+			Page:      listing.Page{Offset: 20, Limit: 10},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT id, created_at, city, version FROM pvzs "+
+			"WHERE city = $1 AND created_at >= $2 AND created_at <= $3 "+
+			"ORDER BY city ASC, id ASC LIMIT 10 OFFSET 20", query)
+		assert.Equal(t, []interface{}{"Москва", from, to}, args)
+	})
+
+	t.Run("неизвестное поле сортировки", func(t *testing.T) {
+		_, _, err := ListPVZsByFilter(pvz.ListFilter{
+			Sort: listing.Sort{Field: "city; DROP TABLE pvzs", Direction: listing.DirectionAsc},
+			Page: listing.Page{Limit: 10},
+		})
+		assert.ErrorIs(t, err, listing.ErrInvalidSort)
+	})
+}
@@ -0,0 +1,41 @@
+package queries
+
+import (
+	"time"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// InsertRateLimitBucket создает корзину ограничения частоты запросов, если ее еще нет
+func InsertRateLimitBucket(key string, tokens float64, updatedAt time.Time) (string, []interface{}, error) {
+	return PostgresBuilder.Insert("rate_limit_buckets").
+		Columns("key", "tokens", "updated_at").
+		Values(key, tokens, updatedAt).
+		Suffix("ON CONFLICT (key) DO NOTHING").
+		ToSql()
+}
+
+// LockRateLimitBucket получает корзину с блокировкой строки до конца транзакции
+func LockRateLimitBucket(key string) (string, []interface{}, error) {
+	return PostgresBuilder.Select("tokens", "updated_at").
+		From("rate_limit_buckets").
+		Where(squirrel.Eq{"key": key}).
+		Suffix("FOR UPDATE").
+		ToSql()
+}
+
+// UpdateRateLimitBucket сохраняет состояние корзины
+func UpdateRateLimitBucket(key string, tokens float64, updatedAt time.Time) (string, []interface{}, error) {
+	return PostgresBuilder.Update("rate_limit_buckets").
+		Set("tokens", tokens).
+		Set("updated_at", updatedAt).
+		Where(squirrel.Eq{"key": key}).
+		ToSql()
+}
+
+// DeleteIdleRateLimitBuckets удаляет корзины, не менявшиеся с before
+func DeleteIdleRateLimitBuckets(before time.Time) (string, []interface{}, error) {
+	return PostgresBuilder.Delete("rate_limit_buckets").
+		Where(squirrel.Lt{"updated_at": before}).
+		ToSql()
+}
@@ -0,0 +1,43 @@
+package queries
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInsertRateLimitBucketQuery(t *testing.T) {
+	now := time.Now()
+
+	query, args, err := InsertRateLimitBucket("POST /login|ip:10.0.0.1", 5, now)
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO rate_limit_buckets (key,tokens,updated_at) VALUES ($1,$2,$3) ON CONFLICT (key) DO NOTHING", query)
+	assert.Equal(t, []interface{}{"POST /login|ip:10.0.0.1", 5.0, now}, args)
+}
+
+func TestLockRateLimitBucketQuery(t *testing.T) {
+	query, args, err := LockRateLimitBucket("POST /login|ip:10.0.0.1")
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT tokens, updated_at FROM rate_limit_buckets WHERE key = $1 FOR UPDATE", query)
+	assert.Equal(t, []interface{}{"POST /login|ip:10.0.0.1"}, args)
+}
+
+func TestUpdateRateLimitBucketQuery(t *testing.T) {
+	now := time.Now()
+
+	query, args, err := UpdateRateLimitBucket("POST /login|ip:10.0.0.1", 3.5, now)
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE rate_limit_buckets SET tokens = $1, updated_at = $2 WHERE key = $3", query)
+	assert.Equal(t, []interface{}{3.5, now, "POST /login|ip:10.0.0.1"}, args)
+}
+
+func TestDeleteIdleRateLimitBucketsQuery(t *testing.T) {
+	before := time.Now()
+
+	query, args, err := DeleteIdleRateLimitBuckets(before)
+	require.NoError(t, err)
+	assert.Equal(t, "DELETE FROM rate_limit_buckets WHERE updated_at < $1", query)
+	assert.Equal(t, []interface{}{before}, args)
+}
@@ -1,7 +1,10 @@
 package queries
 
 import (
+	"time"
+
 	"github.com/Masterminds/squirrel"
+	"github.com/avito/pvz/internal/domain/listing"
 	"github.com/avito/pvz/internal/domain/reception"
 	"github.com/google/uuid"
 )
@@ -16,15 +19,49 @@ func CreateReception(r *reception.Reception) (string, []interface{}, error) {
 
 // GetReceptionByID получает приемку по ID
 func GetReceptionByID(id uuid.UUID) (string, []interface{}, error) {
-	return PostgresBuilder.Select("id", "date_time", "pvz_id", "status").
+	return PostgresBuilder.Select("id", "date_time", "pvz_id", "status", "version").
+		From("receptions").
+		Where(squirrel.Eq{"id": FormatUUID(id)}).
+		ToSql()
+}
+
+// LockReceptionByID получает приемку по ID с блокировкой строки до конца транзакции
+func LockReceptionByID(id uuid.UUID) (string, []interface{}, error) {
+	return PostgresBuilder.Select("id", "date_time", "pvz_id", "status", "version").
 		From("receptions").
 		Where(squirrel.Eq{"id": FormatUUID(id)}).
+		Suffix("FOR UPDATE").
+		ToSql()
+}
+
+// GetReceptionsByIDs получает приемки по списку ID
+func GetReceptionsByIDs(ids []uuid.UUID) (string, []interface{}, error) {
+	return PostgresBuilder.Select("id", "date_time", "pvz_id", "status", "version").
+		From("receptions").
+		Where(squirrel.Eq{"id": FormatUUIDs(ids)}).
+		ToSql()
+}
+
+// ListReceptionsByPVZIDs получает до limit последних приемок каждого из нескольких ПВЗ,
+// начиная с последней. Приемки нумеруются внутри ПВЗ, поэтому ограничение
+// действует на каждый ПВЗ отдельно.
+func ListReceptionsByPVZIDs(pvzIDs []uuid.UUID, limit int) (string, []interface{}, error) {
+	// Подзапрос собирается без нумерации параметров: ее задает внешний запрос
+	ranked := squirrel.Select("id", "date_time", "pvz_id", "status", "version",
+		"ROW_NUMBER() OVER (PARTITION BY pvz_id ORDER BY date_time DESC) AS rn").
+		From("receptions").
+		Where(squirrel.Eq{"pvz_id": FormatUUIDs(pvzIDs)})
+
+	return PostgresBuilder.Select("id", "date_time", "pvz_id", "status", "version").
+		FromSelect(ranked, "ranked").
+		Where(squirrel.LtOrEq{"rn": limit}).
+		OrderBy("date_time DESC").
 		ToSql()
 }
 
 // GetOpenReceptionByPVZID получает открытую приемку для ПВЗ
 func GetOpenReceptionByPVZID(pvzID uuid.UUID) (string, []interface{}, error) {
-	return PostgresBuilder.Select("id", "date_time", "pvz_id", "status").
+	return PostgresBuilder.Select("id", "date_time", "pvz_id", "status", "version").
 		From("receptions").
 		Where(squirrel.Eq{
 			"pvz_id": FormatUUID(pvzID),
@@ -37,17 +74,73 @@ func GetOpenReceptionByPVZID(pvzID uuid.UUID) (string, []interface{}, error) {
 func CloseReception(id uuid.UUID) (string, []interface{}, error) {
 	return PostgresBuilder.Update("receptions").
 		Set("status", reception.StatusClose).
+		Set("version", squirrel.Expr("version + 1")).
 		Where(squirrel.Eq{"id": FormatUUID(id)}).
 		ToSql()
 }
 
+// UpdateReception обновляет статус приемки, если ее версия не изменилась, и увеличивает версию
+func UpdateReception(r *reception.Reception) (string, []interface{}, error) {
+	return PostgresBuilder.Update("receptions").
+		Set("status", r.Status).
+		Set("version", squirrel.Expr("version + 1")).
+		Where(squirrel.Eq{"id": FormatUUID(r.ID), "version": r.Version}).
+		ToSql()
+}
+
 // ListReceptions получает список приемок с пагинацией
 func ListReceptions(offset, limit int) (string, []interface{}, error) {
 	return Paginate(
-		PostgresBuilder.Select("id", "date_time", "pvz_id", "status").
+		PostgresBuilder.Select("id", "date_time", "pvz_id", "status", "version").
 			From("receptions").
 			OrderBy("date_time DESC"),
 		offset,
 		limit,
 	)
 }
+
+// receptionSortColumns задает разрешенные поля сортировки списка приемок
+var receptionSortColumns = SortColumns{
+	reception.SortByDateTime: "date_time",
+	reception.SortByStatus:   "status",
+}
+
+// ListReceptionsByFilter получает список приемок по фильтру с сортировкой и пагинацией
+func ListReceptionsByFilter(filter reception.ListFilter) (string, []interface{}, error) {
+	builder := PostgresBuilder.Select("id", "date_time", "pvz_id", "status", "version").
+		From("receptions")
+
+	if filter.PVZID != uuid.Nil {
+		builder = builder.Where(squirrel.Eq{"pvz_id": FormatUUID(filter.PVZID)})
+	}
+	if filter.Status != "" {
+		builder = builder.Where(squirrel.Eq{"status": string(filter.Status)})
+	}
+	builder = ApplyDateRange(builder, "date_time", filter.DateTime)
+
+	builder, err := ApplySort(builder, filter.Sort, receptionSortColumns, listing.Sort{
+		Field:     reception.SortByDateTime,
+		Direction: listing.DirectionDesc,
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	return Paginate(builder, filter.Page.Offset, filter.Page.Limit)
+}
+
+// ExportReceptionsWithProducts выбирает приемки за период вместе с ПВЗ и товарами
+// в порядке выгрузки: по времени приемки, затем по времени добавления товара
+func ExportReceptionsWithProducts(startDate, endDate time.Time) (string, []interface{}, error) {
+	return PostgresBuilder.Select(
+		"p.id", "p.city",
+		"r.id", "r.date_time", "r.status",
+		"pr.id", "pr.date_time", "pr.type", "pr.barcode",
+	).
+		From("receptions r").
+		Join("pvzs p ON p.id = r.pvz_id").
+		LeftJoin("products pr ON pr.reception_id = r.id").
+		Where("r.date_time BETWEEN ? AND ?", startDate, endDate).
+		OrderBy("r.date_time ASC", "r.id ASC", "pr.date_time ASC").
+		ToSql()
+}
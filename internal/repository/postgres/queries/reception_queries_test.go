@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/avito/pvz/internal/domain/listing"
 	"github.com/avito/pvz/internal/domain/reception"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -32,16 +33,42 @@ func TestGetReceptionByIDQuery(t *testing.T) {
 	id := uuid.New()
 	query, args, err := GetReceptionByID(id)
 	require.NoError(t, err)
-	assert.Equal(t, "SELECT id, date_time, pvz_id, status FROM receptions WHERE id = $1", query)
+	assert.Equal(t, "SELECT id, date_time, pvz_id, status, version FROM receptions WHERE id = $1", query)
 	assert.Len(t, args, 1)
 	assert.Equal(t, id.String(), args[0])
 }
 
+func TestLockReceptionByIDQuery(t *testing.T) {
+	id := uuid.New()
+	query, args, err := LockReceptionByID(id)
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT id, date_time, pvz_id, status, version FROM receptions WHERE id = $1 FOR UPDATE", query)
+	assert.Equal(t, []interface{}{id.String()}, args)
+}
+
+func TestGetReceptionsByIDsQuery(t *testing.T) {
+	ids := []uuid.UUID{uuid.New(), uuid.New()}
+	query, args, err := GetReceptionsByIDs(ids)
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT id, date_time, pvz_id, status, version FROM receptions WHERE id IN ($1,$2)", query)
+	assert.Equal(t, []interface{}{ids[0].String(), ids[1].String()}, args)
+}
+
+func TestListReceptionsByPVZIDsQuery(t *testing.T) {
+	pvzIDs := []uuid.UUID{uuid.New(), uuid.New()}
+	query, args, err := ListReceptionsByPVZIDs(pvzIDs, 5)
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT id, date_time, pvz_id, status, version FROM ("+
+		"SELECT id, date_time, pvz_id, status, version, ROW_NUMBER() OVER (PARTITION BY pvz_id ORDER BY date_time DESC) AS rn "+
+		"FROM receptions WHERE pvz_id IN ($1,$2)) AS ranked WHERE rn <= $3 ORDER BY date_time DESC", query)
+	assert.Equal(t, []interface{}{pvzIDs[0].String(), pvzIDs[1].String(), 5}, args)
+}
+
 func TestGetOpenReceptionByPVZIDQuery(t *testing.T) {
 	pvzID := uuid.New()
 	query, args, err := GetOpenReceptionByPVZID(pvzID)
 	require.NoError(t, err)
-	assert.Equal(t, "SELECT id, date_time, pvz_id, status FROM receptions WHERE pvz_id = $1 AND status = $2", query)
+	assert.Equal(t, "SELECT id, date_time, pvz_id, status, version FROM receptions WHERE pvz_id = $1 AND status = $2", query)
 	assert.Len(t, args, 2)
 	assert.Equal(t, pvzID.String(), args[0])
 	assert.Equal(t, reception.StatusInProgress, args[1])
@@ -51,15 +78,60 @@ func TestCloseReceptionQuery(t *testing.T) {
 	id := uuid.New()
 	query, args, err := CloseReception(id)
 	require.NoError(t, err)
-	assert.Equal(t, "UPDATE receptions SET status = $1 WHERE id = $2", query)
+	assert.Equal(t, "UPDATE receptions SET status = $1, version = version + 1 WHERE id = $2", query)
 	assert.Len(t, args, 2)
 	assert.Equal(t, reception.StatusClose, args[0])
 	assert.Equal(t, id.String(), args[1])
 }
 
+func TestUpdateReceptionQuery(t *testing.T) {
+	r := &reception.Reception{
+		ID:      uuid.New(),
+		Status:  reception.StatusClose,
+		Version: 2,
+	}
+
+	query, args, err := UpdateReception(r)
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE receptions SET status = $1, version = version + 1 WHERE id = $2 AND version = $3", query)
+	assert.Equal(t, []interface{}{reception.StatusClose, r.ID.String(), int64(2)}, args)
+}
+
 func TestListReceptionsQuery(t *testing.T) {
 	query, args, err := ListReceptions(20, 10)
 	require.NoError(t, err)
-	assert.Equal(t, "SELECT id, date_time, pvz_id, status FROM receptions ORDER BY date_time DESC LIMIT 10 OFFSET 20", query)
+	assert.Equal(t, "SELECT id, date_time, pvz_id, status, version FROM receptions ORDER BY date_time DESC LIMIT 10 OFFSET 20", query)
 	assert.Equal(t, []interface{}{}, args)
 }
+
+func TestListReceptionsByFilterQuery(t *testing.T) {
+	pvzID := uuid.New()
+	from := time.Now().Add(-24 * time.Hour)
+
+	query, args, err := ListReceptionsByFilter(reception.ListFilter{
+		PVZID:    pvzID,
+		Status:   reception.StatusClose,
+		DateTime: listing.DateRange{From: from},
+		Sort:     listing.Sort{Field: reception.SortByStatus, Direction: listing.DirectionDesc},
+		Page:     listing.Page{Offset: 0, Limit: 5},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT id, date_time, pvz_id, status, version FROM receptions "+
+		"WHERE pvz_id = $1 AND status = $2 AND date_time >= $3 "+
+		"ORDER BY status DESC, id DESC LIMIT 5 OFFSET 0", query)
+	assert.Equal(t, []interface{}{pvzID.String(), "close", from}, args)
+}
+
+func TestExportReceptionsWithProductsQuery(t *testing.T) {
+	startDate := time.Now().Add(-24 * time.Hour)
+	endDate := time.Now()
+
+	query, args, err := ExportReceptionsWithProducts(startDate, endDate)
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT p.id, p.city, r.id, r.date_time, r.status, pr.id, pr.date_time, pr.type, pr.barcode "+
+		"FROM receptions r JOIN pvzs p ON p.id = r.pvz_id "+
+		"LEFT JOIN products pr ON pr.reception_id = r.id "+
+		"WHERE r.date_time BETWEEN $1 AND $2 "+
+		"ORDER BY r.date_time ASC, r.id ASC, pr.date_time ASC", query)
+	assert.Equal(t, []interface{}{startDate, endDate}, args)
+}
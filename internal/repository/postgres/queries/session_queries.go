@@ -0,0 +1,91 @@
+package queries
+
+import (
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/avito/pvz/internal/domain/session"
+	"github.com/google/uuid"
+)
+
+// refreshTokenColumns колонки токена обновления
+var refreshTokenColumns = []string{
+	"id", "session_id", "user_id", "token_hash", "created_at",
+	"expires_at", "used_at", "replaced_by", "revoked_at",
+}
+
+// CreateRefreshToken сохраняет токен обновления
+func CreateRefreshToken(t *session.RefreshToken) (string, []interface{}, error) {
+	return PostgresBuilder.Insert("refresh_tokens").
+		Columns("id", "session_id", "user_id", "token_hash", "created_at", "expires_at").
+		Values(FormatUUID(t.ID), FormatUUID(t.SessionID), FormatUUID(t.UserID), t.TokenHash, t.CreatedAt, t.ExpiresAt).
+		ToSql()
+}
+
+// GetRefreshTokenByHash получает токен обновления по хешу
+func GetRefreshTokenByHash(hash string) (string, []interface{}, error) {
+	return PostgresBuilder.Select(refreshTokenColumns...).
+		From("refresh_tokens").
+		Where(squirrel.Eq{"token_hash": hash}).
+		ToSql()
+}
+
+// UseRefreshToken помечает неиспользованный и неотозванный токен замененным
+func UseRefreshToken(id, replacedBy uuid.UUID, at time.Time) (string, []interface{}, error) {
+	return PostgresBuilder.Update("refresh_tokens").
+		Set("used_at", at).
+		Set("replaced_by", FormatUUID(replacedBy)).
+		Where(squirrel.Eq{"id": FormatUUID(id), "used_at": nil, "revoked_at": nil}).
+		ToSql()
+}
+
+// RevokeSessionRefreshTokens отзывает токены обновления сессии
+func RevokeSessionRefreshTokens(sessionID uuid.UUID, at time.Time) (string, []interface{}, error) {
+	return PostgresBuilder.Update("refresh_tokens").
+		Set("revoked_at", at).
+		Where(squirrel.Eq{"session_id": FormatUUID(sessionID), "revoked_at": nil}).
+		ToSql()
+}
+
+// RevokeUserRefreshTokens отзывает токены обновления пользователя и возвращает их сессии
+func RevokeUserRefreshTokens(userID uuid.UUID, at time.Time) (string, []interface{}, error) {
+	return PostgresBuilder.Update("refresh_tokens").
+		Set("revoked_at", at).
+		Where(squirrel.Eq{"user_id": FormatUUID(userID), "revoked_at": nil}).
+		Suffix("RETURNING session_id").
+		ToSql()
+}
+
+// DeleteExpiredRefreshTokens удаляет токены обновления, истекшие раньше before
+func DeleteExpiredRefreshTokens(before time.Time) (string, []interface{}, error) {
+	return PostgresBuilder.Delete("refresh_tokens").
+		Where(squirrel.Lt{"expires_at": before}).
+		ToSql()
+}
+
+// UpsertTokenRevocation добавляет запись в список отзыва. Повторный отзыв
+// сдвигает время отзыва и срок хранения записи вперед.
+func UpsertTokenRevocation(r *session.Revocation) (string, []interface{}, error) {
+	return PostgresBuilder.Insert("token_revocations").
+		Columns("kind", "subject_id", "revoked_at", "expires_at").
+		Values(string(r.Kind), FormatUUID(r.SubjectID), r.RevokedAt, r.ExpiresAt).
+		Suffix("ON CONFLICT (kind, subject_id) DO UPDATE SET " +
+			"revoked_at = GREATEST(token_revocations.revoked_at, EXCLUDED.revoked_at), " +
+			"expires_at = GREATEST(token_revocations.expires_at, EXCLUDED.expires_at)").
+		ToSql()
+}
+
+// ListTokenRevocations возвращает записи списка отзыва, действующие после now
+func ListTokenRevocations(now time.Time) (string, []interface{}, error) {
+	return PostgresBuilder.Select("kind", "subject_id", "revoked_at", "expires_at").
+		From("token_revocations").
+		Where(squirrel.Gt{"expires_at": now}).
+		ToSql()
+}
+
+// DeleteExpiredTokenRevocations удаляет записи списка отзыва, истекшие раньше before
+func DeleteExpiredTokenRevocations(before time.Time) (string, []interface{}, error) {
+	return PostgresBuilder.Delete("token_revocations").
+		Where(squirrel.Lt{"expires_at": before}).
+		ToSql()
+}
@@ -0,0 +1,102 @@
+package queries
+
+import (
+	"testing"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/session"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateRefreshTokenQuery(t *testing.T) {
+	now := time.Now()
+	token := &session.RefreshToken{
+		ID:        uuid.New(),
+		SessionID: uuid.New(),
+		UserID:    uuid.New(),
+		TokenHash: "hash",
+		CreatedAt: now,
+		ExpiresAt: now.Add(time.Hour),
+	}
+
+	query, args, err := CreateRefreshToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO refresh_tokens (id,session_id,user_id,token_hash,created_at,expires_at) VALUES ($1,$2,$3,$4,$5,$6)", query)
+	assert.Equal(t, []interface{}{token.ID.String(), token.SessionID.String(), token.UserID.String(), "hash", now, token.ExpiresAt}, args)
+}
+
+func TestGetRefreshTokenByHashQuery(t *testing.T) {
+	query, args, err := GetRefreshTokenByHash("hash")
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT id, session_id, user_id, token_hash, created_at, expires_at, used_at, replaced_by, revoked_at "+
+		"FROM refresh_tokens WHERE token_hash = $1", query)
+	assert.Equal(t, []interface{}{"hash"}, args)
+}
+
+func TestUseRefreshTokenQuery(t *testing.T) {
+	id, replacedBy := uuid.New(), uuid.New()
+	at := time.Now()
+
+	query, args, err := UseRefreshToken(id, replacedBy, at)
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE refresh_tokens SET used_at = $1, replaced_by = $2 WHERE id = $3 AND revoked_at IS NULL AND used_at IS NULL", query)
+	assert.Equal(t, []interface{}{at, replacedBy.String(), id.String()}, args)
+}
+
+func TestRevokeRefreshTokensQueries(t *testing.T) {
+	id := uuid.New()
+	at := time.Now()
+
+	query, args, err := RevokeSessionRefreshTokens(id, at)
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE refresh_tokens SET revoked_at = $1 WHERE revoked_at IS NULL AND session_id = $2", query)
+	assert.Equal(t, []interface{}{at, id.String()}, args)
+
+	query, args, err = RevokeUserRefreshTokens(id, at)
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE refresh_tokens SET revoked_at = $1 WHERE revoked_at IS NULL AND user_id = $2 RETURNING session_id", query)
+	assert.Equal(t, []interface{}{at, id.String()}, args)
+}
+
+func TestDeleteExpiredRefreshTokensQuery(t *testing.T) {
+	before := time.Now()
+
+	query, args, err := DeleteExpiredRefreshTokens(before)
+	require.NoError(t, err)
+	assert.Equal(t, "DELETE FROM refresh_tokens WHERE expires_at < $1", query)
+	assert.Equal(t, []interface{}{before}, args)
+}
+
+func TestUpsertTokenRevocationQuery(t *testing.T) {
+	now := time.Now()
+	revocation := &session.Revocation{
+		Kind:      session.RevocationUser,
+		SubjectID: uuid.New(),
+		RevokedAt: now,
+		ExpiresAt: now.Add(time.Hour),
+	}
+
+	query, args, err := UpsertTokenRevocation(revocation)
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO token_revocations (kind,subject_id,revoked_at,expires_at) VALUES ($1,$2,$3,$4) "+
+		"ON CONFLICT (kind, subject_id) DO UPDATE SET "+
+		"revoked_at = GREATEST(token_revocations.revoked_at, EXCLUDED.revoked_at), "+
+		"expires_at = GREATEST(token_revocations.expires_at, EXCLUDED.expires_at)", query)
+	assert.Equal(t, []interface{}{"user", revocation.SubjectID.String(), now, revocation.ExpiresAt}, args)
+}
+
+func TestTokenRevocationListQueries(t *testing.T) {
+	now := time.Now()
+
+	query, args, err := ListTokenRevocations(now)
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT kind, subject_id, revoked_at, expires_at FROM token_revocations WHERE expires_at > $1", query)
+	assert.Equal(t, []interface{}{now}, args)
+
+	query, args, err = DeleteExpiredTokenRevocations(now)
+	require.NoError(t, err)
+	assert.Equal(t, "DELETE FROM token_revocations WHERE expires_at < $1", query)
+	assert.Equal(t, []interface{}{now}, args)
+}
@@ -1,7 +1,10 @@
 package queries
 
 import (
+	"fmt"
+
 	"github.com/Masterminds/squirrel"
+	"github.com/avito/pvz/internal/domain/listing"
 	"github.com/google/uuid"
 )
 
@@ -36,3 +39,49 @@ func Paginate(builder squirrel.SelectBuilder, offset, limit int) (string, []inte
 	}
 	return query, args, nil
 }
+
+// SortColumns сопоставляет поля сортировки из API с колонками таблицы.
+// В ORDER BY попадают только имена колонок из этой таблицы, значение
+// от клиента в SQL никогда не подставляется.
+type SortColumns map[string]string
+
+// ApplySort добавляет к запросу ORDER BY по разрешенному полю.
+// Нулевая сортировка заменяется сортировкой по умолчанию def.
+// Для стабильного порядка страниц последним ключом всегда идет id.
+func ApplySort(builder squirrel.SelectBuilder, sort listing.Sort, columns SortColumns, def listing.Sort) (squirrel.SelectBuilder, error) {
+	if sort.IsZero() {
+		sort = def
+	}
+
+	column, ok := columns[sort.Field]
+	if !ok {
+		return builder, fmt.Errorf("%w: %s", listing.ErrInvalidSort, sort.Field)
+	}
+
+	var direction string
+	switch sort.Direction {
+	case listing.DirectionAsc:
+		direction = "ASC"
+	case listing.DirectionDesc:
+		direction = "DESC"
+	default:
+		return builder, fmt.Errorf("%w: %s", listing.ErrInvalidSort, sort.Direction)
+	}
+
+	return builder.OrderBy(
+		column+" "+direction,
+		"id "+direction,
+	), nil
+}
+
+// ApplyDateRange ограничивает колонку диапазоном дат включительно.
+// Нулевые границы диапазона пропускаются.
+func ApplyDateRange(builder squirrel.SelectBuilder, column string, r listing.DateRange) squirrel.SelectBuilder {
+	if !r.From.IsZero() {
+		builder = builder.Where(squirrel.GtOrEq{column: r.From})
+	}
+	if !r.To.IsZero() {
+		builder = builder.Where(squirrel.LtOrEq{column: r.To})
+	}
+	return builder
+}
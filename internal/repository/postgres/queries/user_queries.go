@@ -8,6 +8,9 @@ import (
 	"github.com/google/uuid"
 )
 
+// userColumns колонки пользователя
+var userColumns = []string{"id", "email", "password", "role", "created_at", "email_verified_at"}
+
 // CreateUser создает нового пользователя
 func CreateUser(id uuid.UUID, email, password string, role user.Role, createdAt time.Time) (string, []interface{}, error) {
 	return PostgresBuilder.Insert("users").
@@ -18,7 +21,7 @@ func CreateUser(id uuid.UUID, email, password string, role user.Role, createdAt
 
 // GetUserByID получает пользователя по ID
 func GetUserByID(id uuid.UUID) (string, []interface{}, error) {
-	return PostgresBuilder.Select("id", "email", "password", "role", "created_at").
+	return PostgresBuilder.Select(userColumns...).
 		From("users").
 		Where(squirrel.Eq{"id": FormatUUID(id)}).
 		ToSql()
@@ -26,15 +29,16 @@ func GetUserByID(id uuid.UUID) (string, []interface{}, error) {
 
 // GetUserByEmail получает пользователя по email
 func GetUserByEmail(email string) (string, []interface{}, error) {
-	return PostgresBuilder.Select("id", "email", "password", "role", "created_at").
+	return PostgresBuilder.Select(userColumns...).
 		From("users").
 		Where(squirrel.Eq{"email": email}).
 		ToSql()
 }
 
-// UpdateUser обновляет данные пользователя
+// UpdateUser обновляет данные пользователя. При смене email подтверждение сбрасывается.
 func UpdateUser(id uuid.UUID, email, password string, role user.Role) (string, []interface{}, error) {
 	return PostgresBuilder.Update("users").
+		Set("email_verified_at", squirrel.Expr("CASE WHEN email = ? THEN email_verified_at END", email)).
 		Set("email", email).
 		Set("password", password).
 		Set("role", role).
@@ -42,6 +46,23 @@ func UpdateUser(id uuid.UUID, email, password string, role user.Role) (string, [
 		ToSql()
 }
 
+// UpdateUserPassword заменяет хеш пароля пользователя
+func UpdateUserPassword(id uuid.UUID, password string) (string, []interface{}, error) {
+	return PostgresBuilder.Update("users").
+		Set("password", password).
+		Where(squirrel.Eq{"id": FormatUUID(id)}).
+		ToSql()
+}
+
+// MarkUserEmailVerified отмечает email пользователя подтвержденным, если он
+// все еще равен email
+func MarkUserEmailVerified(id uuid.UUID, email string, at time.Time) (string, []interface{}, error) {
+	return PostgresBuilder.Update("users").
+		Set("email_verified_at", at).
+		Where(squirrel.Eq{"id": FormatUUID(id), "email": email}).
+		ToSql()
+}
+
 // DeleteUser удаляет пользователя
 func DeleteUser(id uuid.UUID) (string, []interface{}, error) {
 	return PostgresBuilder.Delete("users").
@@ -52,7 +73,7 @@ func DeleteUser(id uuid.UUID) (string, []interface{}, error) {
 // ListUsers получает список пользователей с пагинацией
 func ListUsers(offset, limit int) (string, []interface{}, error) {
 	return Paginate(
-		PostgresBuilder.Select("id", "email", "password", "role", "created_at").
+		PostgresBuilder.Select(userColumns...).
 			From("users").
 			OrderBy("created_at DESC"),
 		offset,
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/Masterminds/squirrel"
+	domainUser "github.com/avito/pvz/internal/domain/user"
 	"github.com/avito/pvz/internal/models"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -102,3 +103,31 @@ func TestListUsers(t *testing.T) {
 	assert.Equal(t, "SELECT id, email, password, role, created_at FROM users ORDER BY created_at DESC LIMIT 20 OFFSET 10", query)
 	assert.Empty(t, args)
 }
+
+func TestUpdateUserResetsVerification(t *testing.T) {
+	id := uuid.New()
+
+	query, args, err := UpdateUser(id, "new@example.com", "hash", "employee")
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE users SET email_verified_at = CASE WHEN email = $1 THEN email_verified_at END, email = $2, password = $3, role = $4 WHERE id = $5", query)
+	assert.Equal(t, []interface{}{"new@example.com", "new@example.com", "hash", domainUser.RoleEmployee, id.String()}, args)
+}
+
+func TestUpdateUserPassword(t *testing.T) {
+	id := uuid.New()
+
+	query, args, err := UpdateUserPassword(id, "hash")
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE users SET password = $1 WHERE id = $2", query)
+	assert.Equal(t, []interface{}{"hash", id.String()}, args)
+}
+
+func TestMarkUserEmailVerified(t *testing.T) {
+	id := uuid.New()
+	now := time.Now()
+
+	query, args, err := MarkUserEmailVerified(id, "user@example.com", now)
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE users SET email_verified_at = $1 WHERE email = $2 AND id = $3", query)
+	assert.Equal(t, []interface{}{now, "user@example.com", id.String()}, args)
+}
@@ -0,0 +1,50 @@
+package queries
+
+import (
+	"strings"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/avito/pvz/internal/domain/usertoken"
+	"github.com/google/uuid"
+)
+
+// userTokenColumns колонки одноразового токена пользователя
+var userTokenColumns = []string{
+	"id", "user_id", "purpose", "email", "token_hash", "created_at", "expires_at", "used_at",
+}
+
+// CreateUserToken сохраняет одноразовый токен пользователя
+func CreateUserToken(t *usertoken.Token) (string, []interface{}, error) {
+	return PostgresBuilder.Insert("user_tokens").
+		Columns("id", "user_id", "purpose", "email", "token_hash", "created_at", "expires_at").
+		Values(FormatUUID(t.ID), FormatUUID(t.UserID), string(t.Purpose), t.Email, t.TokenHash, t.CreatedAt, t.ExpiresAt).
+		ToSql()
+}
+
+// UseUserToken помечает действующий токен использованным и возвращает его.
+// Проверка и отметка выполняются одним запросом, поэтому токен нельзя
+// использовать дважды параллельными запросами.
+func UseUserToken(hash string, purpose usertoken.Purpose, at time.Time) (string, []interface{}, error) {
+	return PostgresBuilder.Update("user_tokens").
+		Set("used_at", at).
+		Where(squirrel.Eq{"token_hash": hash, "purpose": string(purpose), "used_at": nil}).
+		Where(squirrel.Gt{"expires_at": at}).
+		Suffix("RETURNING " + strings.Join(userTokenColumns, ", ")).
+		ToSql()
+}
+
+// InvalidateUserTokens помечает неиспользованные токены пользователя с назначением purpose использованными
+func InvalidateUserTokens(userID uuid.UUID, purpose usertoken.Purpose, at time.Time) (string, []interface{}, error) {
+	return PostgresBuilder.Update("user_tokens").
+		Set("used_at", at).
+		Where(squirrel.Eq{"user_id": FormatUUID(userID), "purpose": string(purpose), "used_at": nil}).
+		ToSql()
+}
+
+// DeleteExpiredUserTokens удаляет токены, истекшие раньше before
+func DeleteExpiredUserTokens(before time.Time) (string, []interface{}, error) {
+	return PostgresBuilder.Delete("user_tokens").
+		Where(squirrel.Lt{"expires_at": before}).
+		ToSql()
+}
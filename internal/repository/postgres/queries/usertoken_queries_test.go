@@ -0,0 +1,50 @@
+package queries
+
+import (
+	"testing"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/usertoken"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateUserTokenQuery(t *testing.T) {
+	now := time.Now()
+	token := usertoken.New(uuid.New(), "user@example.com", usertoken.PurposePasswordReset, "hash", now, time.Hour)
+
+	query, args, err := CreateUserToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO user_tokens (id,user_id,purpose,email,token_hash,created_at,expires_at) VALUES ($1,$2,$3,$4,$5,$6,$7)", query)
+	assert.Equal(t, []interface{}{token.ID.String(), token.UserID.String(), "password_reset", "user@example.com", "hash", now, token.ExpiresAt}, args)
+}
+
+func TestUseUserTokenQuery(t *testing.T) {
+	now := time.Now()
+
+	query, args, err := UseUserToken("hash", usertoken.PurposeEmailVerification, now)
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE user_tokens SET used_at = $1 WHERE purpose = $2 AND token_hash = $3 AND used_at IS NULL AND expires_at > $4 "+
+		"RETURNING id, user_id, purpose, email, token_hash, created_at, expires_at, used_at", query)
+	assert.Equal(t, []interface{}{now, "email_verification", "hash", now}, args)
+}
+
+func TestInvalidateUserTokensQuery(t *testing.T) {
+	now := time.Now()
+	userID := uuid.New()
+
+	query, args, err := InvalidateUserTokens(userID, usertoken.PurposePasswordReset, now)
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE user_tokens SET used_at = $1 WHERE purpose = $2 AND used_at IS NULL AND user_id = $3", query)
+	assert.Equal(t, []interface{}{now, "password_reset", userID.String()}, args)
+}
+
+func TestDeleteExpiredUserTokensQuery(t *testing.T) {
+	before := time.Now()
+
+	query, args, err := DeleteExpiredUserTokens(before)
+	require.NoError(t, err)
+	assert.Equal(t, "DELETE FROM user_tokens WHERE expires_at < $1", query)
+	assert.Equal(t, []interface{}{before}, args)
+}
@@ -0,0 +1,125 @@
+package queries
+
+import (
+	"strings"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/avito/pvz/internal/domain/webhook"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// webhookSubscriptionColumns колонки подписки на события
+var webhookSubscriptionColumns = []string{"id", "url", "event_types", "secret", "created_by", "created_at"}
+
+// webhookDeliveryColumns колонки доставки события
+var webhookDeliveryColumns = []string{
+	"id", "subscription_id", "event_type", "payload", "status", "attempts",
+	"next_attempt_at", "last_status_code", "last_error", "created_at", "delivered_at",
+}
+
+// CreateWebhookSubscription создает подписку на события
+func CreateWebhookSubscription(s *webhook.Subscription) (string, []interface{}, error) {
+	eventTypes := make([]string, len(s.EventTypes))
+	for i, t := range s.EventTypes {
+		eventTypes[i] = string(t)
+	}
+
+	return PostgresBuilder.Insert("webhook_subscriptions").
+		Columns(webhookSubscriptionColumns...).
+		Values(FormatUUID(s.ID), s.URL, pq.StringArray(eventTypes), s.Secret, FormatUUID(s.CreatedBy), s.CreatedAt).
+		ToSql()
+}
+
+// GetWebhookSubscription получает подписку по ID
+func GetWebhookSubscription(id uuid.UUID) (string, []interface{}, error) {
+	return PostgresBuilder.Select(webhookSubscriptionColumns...).
+		From("webhook_subscriptions").
+		Where(squirrel.Eq{"id": FormatUUID(id)}).
+		ToSql()
+}
+
+// ListWebhookSubscriptions получает все подписки в порядке создания
+func ListWebhookSubscriptions() (string, []interface{}, error) {
+	return PostgresBuilder.Select(webhookSubscriptionColumns...).
+		From("webhook_subscriptions").
+		OrderBy("created_at ASC", "id ASC").
+		ToSql()
+}
+
+// DeleteWebhookSubscription удаляет подписку
+func DeleteWebhookSubscription(id uuid.UUID) (string, []interface{}, error) {
+	return PostgresBuilder.Delete("webhook_subscriptions").
+		Where(squirrel.Eq{"id": FormatUUID(id)}).
+		ToSql()
+}
+
+// CreateWebhookDeliveries создает доставки событий
+func CreateWebhookDeliveries(deliveries []*webhook.Delivery) (string, []interface{}, error) {
+	builder := PostgresBuilder.Insert("webhook_deliveries").
+		Columns("id", "subscription_id", "event_type", "payload", "status", "attempts", "next_attempt_at", "created_at")
+
+	for _, d := range deliveries {
+		builder = builder.Values(
+			FormatUUID(d.ID), FormatUUID(d.SubscriptionID), string(d.EventType), d.Payload,
+			string(d.Status), d.Attempts, d.NextAttemptAt, d.CreatedAt,
+		)
+	}
+
+	// Повторная публикация сообщения outbox создает доставки с теми же ID
+	return builder.Suffix("ON CONFLICT (id) DO NOTHING").ToSql()
+}
+
+// ClaimDueWebhookDeliveries откладывает на leaseUntil следующую попытку ожидающих доставок,
+// время которых наступило к now, и возвращает их. Строки, занятые другим обработчиком, пропускаются.
+func ClaimDueWebhookDeliveries(now, leaseUntil time.Time, limit int) (string, []interface{}, error) {
+	// Подзапрос собирается без нумерации параметров: ее задает внешний запрос
+	due := squirrel.Select("id").
+		From("webhook_deliveries").
+		Where(squirrel.Eq{"status": string(webhook.DeliveryPending)}).
+		Where(squirrel.LtOrEq{"next_attempt_at": now}).
+		OrderBy("next_attempt_at ASC").
+		Limit(uint64(limit)).
+		Suffix("FOR UPDATE SKIP LOCKED")
+
+	return PostgresBuilder.Update("webhook_deliveries").
+		Set("next_attempt_at", leaseUntil).
+		Where(squirrel.Expr("id IN (?)", due)).
+		Suffix("RETURNING " + strings.Join(webhookDeliveryColumns, ", ")).
+		ToSql()
+}
+
+// GetWebhookDelivery получает доставку по ID
+func GetWebhookDelivery(id uuid.UUID) (string, []interface{}, error) {
+	return PostgresBuilder.Select(webhookDeliveryColumns...).
+		From("webhook_deliveries").
+		Where(squirrel.Eq{"id": FormatUUID(id)}).
+		ToSql()
+}
+
+// UpdateWebhookDelivery сохраняет результат попытки доставки
+func UpdateWebhookDelivery(d *webhook.Delivery) (string, []interface{}, error) {
+	return PostgresBuilder.Update("webhook_deliveries").
+		Set("status", string(d.Status)).
+		Set("attempts", d.Attempts).
+		Set("next_attempt_at", d.NextAttemptAt).
+		Set("last_status_code", d.LastStatusCode).
+		Set("last_error", d.LastError).
+		Set("delivered_at", d.DeliveredAt).
+		Where(squirrel.Eq{"id": FormatUUID(d.ID)}).
+		ToSql()
+}
+
+// ListWebhookDeliveries получает историю доставок подписки, новые первыми
+func ListWebhookDeliveries(filter webhook.DeliveryFilter) (string, []interface{}, error) {
+	builder := PostgresBuilder.Select(webhookDeliveryColumns...).
+		From("webhook_deliveries").
+		Where(squirrel.Eq{"subscription_id": FormatUUID(filter.SubscriptionID)})
+
+	if filter.Status != "" {
+		builder = builder.Where(squirrel.Eq{"status": string(filter.Status)})
+	}
+
+	return Paginate(builder.OrderBy("created_at DESC", "id DESC"), filter.Page.Offset, filter.Page.Limit)
+}
@@ -0,0 +1,128 @@
+package queries
+
+import (
+	"testing"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/event"
+	"github.com/avito/pvz/internal/domain/listing"
+	"github.com/avito/pvz/internal/domain/webhook"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateWebhookSubscriptionQuery(t *testing.T) {
+	s := &webhook.Subscription{
+		ID:         uuid.New(),
+		URL:        "https://partner.example/hook",
+		EventTypes: []event.Type{event.TypeReceptionClosed, event.TypeProductAdded},
+		Secret:     "secret",
+		CreatedBy:  uuid.New(),
+		CreatedAt:  time.Now(),
+	}
+
+	query, args, err := CreateWebhookSubscription(s)
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO webhook_subscriptions (id,url,event_types,secret,created_by,created_at) VALUES ($1,$2,$3,$4,$5,$6)", query)
+	assert.Equal(t, []interface{}{
+		s.ID.String(), s.URL, pq.StringArray{"reception.closed", "product.added"}, "secret", s.CreatedBy.String(), s.CreatedAt,
+	}, args)
+}
+
+func TestGetWebhookSubscriptionQuery(t *testing.T) {
+	id := uuid.New()
+
+	query, args, err := GetWebhookSubscription(id)
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT id, url, event_types, secret, created_by, created_at FROM webhook_subscriptions WHERE id = $1", query)
+	assert.Equal(t, []interface{}{id.String()}, args)
+}
+
+func TestListWebhookSubscriptionsQuery(t *testing.T) {
+	query, args, err := ListWebhookSubscriptions()
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT id, url, event_types, secret, created_by, created_at FROM webhook_subscriptions ORDER BY created_at ASC, id ASC", query)
+	assert.Empty(t, args)
+}
+
+func TestDeleteWebhookSubscriptionQuery(t *testing.T) {
+	id := uuid.New()
+
+	query, args, err := DeleteWebhookSubscription(id)
+	require.NoError(t, err)
+	assert.Equal(t, "DELETE FROM webhook_subscriptions WHERE id = $1", query)
+	assert.Equal(t, []interface{}{id.String()}, args)
+}
+
+func TestCreateWebhookDeliveriesQuery(t *testing.T) {
+	now := time.Now()
+	d1 := &webhook.Delivery{ID: uuid.New(), SubscriptionID: uuid.New(), EventType: event.TypeProductAdded, Payload: []byte(`{}`), Status: webhook.DeliveryPending, NextAttemptAt: now, CreatedAt: now}
+	d2 := &webhook.Delivery{ID: uuid.New(), SubscriptionID: uuid.New(), EventType: event.TypeProductAdded, Payload: []byte(`{}`), Status: webhook.DeliveryPending, NextAttemptAt: now, CreatedAt: now}
+
+	query, args, err := CreateWebhookDeliveries([]*webhook.Delivery{d1, d2})
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO webhook_deliveries (id,subscription_id,event_type,payload,status,attempts,next_attempt_at,created_at) "+
+		"VALUES ($1,$2,$3,$4,$5,$6,$7,$8),($9,$10,$11,$12,$13,$14,$15,$16) ON CONFLICT (id) DO NOTHING", query)
+	assert.Len(t, args, 16)
+	assert.Equal(t, d2.ID.String(), args[8])
+}
+
+func TestClaimDueWebhookDeliveriesQuery(t *testing.T) {
+	now := time.Now()
+	leaseUntil := now.Add(time.Minute)
+
+	query, args, err := ClaimDueWebhookDeliveries(now, leaseUntil, 50)
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE webhook_deliveries SET next_attempt_at = $1 WHERE id IN ("+
+		"SELECT id FROM webhook_deliveries WHERE status = $2 AND next_attempt_at <= $3 "+
+		"ORDER BY next_attempt_at ASC LIMIT 50 FOR UPDATE SKIP LOCKED) "+
+		"RETURNING id, subscription_id, event_type, payload, status, attempts, next_attempt_at, last_status_code, last_error, created_at, delivered_at", query)
+	assert.Equal(t, []interface{}{leaseUntil, "pending", now}, args)
+}
+
+func TestUpdateWebhookDeliveryQuery(t *testing.T) {
+	now := time.Now()
+	d := &webhook.Delivery{ID: uuid.New(), Status: webhook.DeliverySucceeded, Attempts: 2, NextAttemptAt: now, LastStatusCode: 200, DeliveredAt: &now}
+
+	query, args, err := UpdateWebhookDelivery(d)
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE webhook_deliveries SET status = $1, attempts = $2, next_attempt_at = $3, last_status_code = $4, last_error = $5, delivered_at = $6 WHERE id = $7", query)
+	assert.Equal(t, []interface{}{"succeeded", 2, now, 200, "", &now, d.ID.String()}, args)
+}
+
+func TestListWebhookDeliveriesQuery(t *testing.T) {
+	subscriptionID := uuid.New()
+
+	tests := []struct {
+		name          string
+		filter        webhook.DeliveryFilter
+		expectedQuery string
+		expectedArgs  []interface{}
+	}{
+		{
+			name:   "все доставки подписки",
+			filter: webhook.DeliveryFilter{SubscriptionID: subscriptionID, Page: listing.Page{Offset: 0, Limit: 10}},
+			expectedQuery: "SELECT id, subscription_id, event_type, payload, status, attempts, next_attempt_at, last_status_code, last_error, created_at, delivered_at " +
+				"FROM webhook_deliveries WHERE subscription_id = $1 ORDER BY created_at DESC, id DESC LIMIT 10 OFFSET 0",
+			expectedArgs: []interface{}{subscriptionID.String()},
+		},
+		{
+			name:   "только dead-letter",
+			filter: webhook.DeliveryFilter{SubscriptionID: subscriptionID, Status: webhook.DeliveryDead, Page: listing.Page{Offset: 20, Limit: 10}},
+			expectedQuery: "SELECT id, subscription_id, event_type, payload, status, attempts, next_attempt_at, last_status_code, last_error, created_at, delivered_at " +
+				"FROM webhook_deliveries WHERE subscription_id = $1 AND status = $2 ORDER BY created_at DESC, id DESC LIMIT 10 OFFSET 20",
+			expectedArgs: []interface{}{subscriptionID.String(), "dead"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args, err := ListWebhookDeliveries(tt.filter)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedQuery, query)
+			assert.Equal(t, tt.expectedArgs, args)
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/ratelimit"
+	"github.com/avito/pvz/internal/repository/postgres/queries"
+	"github.com/jmoiron/sqlx"
+)
+
+// RateLimitRepository реализует интерфейс ratelimit.Repository.
+// Методы выполняются в транзакции из контекста, если она открыта.
+type RateLimitRepository struct {
+	db *sqlx.DB
+}
+
+// NewRateLimitRepository создает новый экземпляр RateLimitRepository
+func NewRateLimitRepository(db *sqlx.DB) *RateLimitRepository {
+	return &RateLimitRepository{db: db}
+}
+
+// rateLimitBucketRow строка таблицы rate_limit_buckets
+type rateLimitBucketRow struct {
+	Tokens    float64   `db:"tokens"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// Lock блокирует корзину до конца транзакции, создавая ее из initial при отсутствии
+func (r *RateLimitRepository) Lock(ctx context.Context, key string, initial *ratelimit.Bucket) (*ratelimit.Bucket, error) {
+	query, args, err := queries.InsertRateLimitBucket(key, initial.Tokens, initial.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, args...); err != nil {
+		return nil, err
+	}
+
+	query, args, err = queries.LockRateLimitBucket(key)
+	if err != nil {
+		return nil, err
+	}
+
+	var row rateLimitBucketRow
+	if err := conn(ctx, r.db).GetContext(ctx, &row, query, args...); err != nil {
+		return nil, err
+	}
+
+	return &ratelimit.Bucket{Tokens: row.Tokens, UpdatedAt: row.UpdatedAt}, nil
+}
+
+// Save сохраняет состояние корзины
+func (r *RateLimitRepository) Save(ctx context.Context, key string, bucket *ratelimit.Bucket) error {
+	query, args, err := queries.UpdateRateLimitBucket(key, bucket.Tokens, bucket.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, query, args...)
+	return err
+}
+
+// DeleteIdle удаляет корзины, не менявшиеся с before
+func (r *RateLimitRepository) DeleteIdle(ctx context.Context, before time.Time) (int64, error) {
+	query, args, err := queries.DeleteIdleRateLimitBuckets(before)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/avito/pvz/internal/domain/product"
 	"github.com/avito/pvz/internal/domain/reception"
@@ -29,7 +30,7 @@ func (r *ReceptionRepository) Create(ctx context.Context, reception *reception.R
 		return err
 	}
 
-	_, err = r.db.ExecContext(ctx, query, args...)
+	_, err = conn(ctx, r.db).ExecContext(ctx, query, args...)
 	return err
 }
 
@@ -41,7 +42,7 @@ func (r *ReceptionRepository) GetByID(ctx context.Context, id uuid.UUID) (*recep
 	}
 
 	var result reception.Reception
-	err = r.db.GetContext(ctx, &result, query, args...)
+	err = conn(ctx, r.db).GetContext(ctx, &result, query, args...)
 	if err == sql.ErrNoRows {
 		return nil, reception.ErrNotFound
 	}
@@ -52,6 +53,57 @@ func (r *ReceptionRepository) GetByID(ctx context.Context, id uuid.UUID) (*recep
 	return &result, nil
 }
 
+// GetByIDForUpdate получает приемку по ID и блокирует ее строку до конца транзакции
+func (r *ReceptionRepository) GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*reception.Reception, error) {
+	query, args, err := queries.LockReceptionByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var result reception.Reception
+	err = conn(ctx, r.db).GetContext(ctx, &result, query, args...)
+	if err == sql.ErrNoRows {
+		return nil, reception.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// GetByIDs получает приемки по списку ID, отсутствующие ID пропускаются
+func (r *ReceptionRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*reception.Reception, error) {
+	query, args, err := queries.GetReceptionsByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*reception.Reception
+	err = conn(ctx, r.db).SelectContext(ctx, &result, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ListByPVZIDs получает до limit последних приемок каждого ПВЗ одним запросом
+func (r *ReceptionRepository) ListByPVZIDs(ctx context.Context, pvzIDs []uuid.UUID, limit int) ([]*reception.Reception, error) {
+	query, args, err := queries.ListReceptionsByPVZIDs(pvzIDs, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*reception.Reception
+	err = conn(ctx, r.db).SelectContext(ctx, &result, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
 // GetOpenByPVZID получает открытую приемку для ПВЗ
 func (r *ReceptionRepository) GetOpenByPVZID(ctx context.Context, pvzID uuid.UUID) (*reception.Reception, error) {
 	query, args, err := queries.GetOpenReceptionByPVZID(pvzID)
@@ -60,7 +112,7 @@ func (r *ReceptionRepository) GetOpenByPVZID(ctx context.Context, pvzID uuid.UUI
 	}
 
 	var result reception.Reception
-	err = r.db.GetContext(ctx, &result, query, args...)
+	err = conn(ctx, r.db).GetContext(ctx, &result, query, args...)
 	if err == sql.ErrNoRows {
 		return nil, reception.ErrNotFound
 	}
@@ -78,7 +130,7 @@ func (r *ReceptionRepository) Close(ctx context.Context, id uuid.UUID) error {
 		return err
 	}
 
-	result, err := r.db.ExecContext(ctx, query, args...)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, args...)
 	if err != nil {
 		return err
 	}
@@ -102,7 +154,23 @@ func (r *ReceptionRepository) List(ctx context.Context, offset, limit int) ([]*r
 	}
 
 	var result []*reception.Reception
-	err = r.db.SelectContext(ctx, &result, query, args...)
+	err = conn(ctx, r.db).SelectContext(ctx, &result, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ListByFilter возвращает список приемок, отобранных и отсортированных по фильтру
+func (r *ReceptionRepository) ListByFilter(ctx context.Context, filter reception.ListFilter) ([]*reception.Reception, error) {
+	query, args, err := queries.ListReceptionsByFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*reception.Reception
+	err = conn(ctx, r.db).SelectContext(ctx, &result, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -111,10 +179,13 @@ func (r *ReceptionRepository) List(ctx context.Context, offset, limit int) ([]*r
 }
 
 // Update обновляет данные приемки
-func (r *ReceptionRepository) Update(ctx context.Context, reception *reception.Reception) error {
-	query := `UPDATE receptions SET status = $1 WHERE id = $2`
+func (r *ReceptionRepository) Update(ctx context.Context, rec *reception.Reception) error {
+	query, args, err := queries.UpdateReception(rec)
+	if err != nil {
+		return err
+	}
 
-	result, err := r.db.ExecContext(ctx, query, reception.Status, reception.ID)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, args...)
 	if err != nil {
 		return err
 	}
@@ -124,9 +195,14 @@ func (r *ReceptionRepository) Update(ctx context.Context, reception *reception.R
 		return err
 	}
 	if rowsAffected == 0 {
-		return fmt.Errorf("reception not found")
+		// Строка не обновлена: приемка либо удалена, либо изменена после чтения
+		if _, err := r.GetByID(ctx, rec.ID); err != nil {
+			return err
+		}
+		return reception.ErrVersionConflict
 	}
 
+	rec.Version++
 	return nil
 }
 
@@ -134,7 +210,7 @@ func (r *ReceptionRepository) Update(ctx context.Context, reception *reception.R
 func (r *ReceptionRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	query := `DELETE FROM receptions WHERE id = $1`
 
-	result, err := r.db.ExecContext(ctx, query, id)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete reception: %w", err)
 	}
@@ -160,7 +236,7 @@ func (r *ReceptionRepository) GetProducts(ctx context.Context, receptionID uuid.
 		ORDER BY date_time DESC
 	`
 	var products []*product.Product
-	err := r.db.SelectContext(ctx, &products, query, receptionID)
+	err := conn(ctx, r.db).SelectContext(ctx, &products, query, receptionID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get products: %w", err)
 	}
@@ -170,14 +246,14 @@ func (r *ReceptionRepository) GetProducts(ctx context.Context, receptionID uuid.
 // GetLastOpen получает последнюю открытую приемку для ПВЗ
 func (r *ReceptionRepository) GetLastOpen(ctx context.Context, pvzID uuid.UUID) (*reception.Reception, error) {
 	query := `
-		SELECT id, date_time, pvz_id, status 
+		SELECT id, date_time, pvz_id, status, version
 		FROM receptions 
 		WHERE pvz_id = $1 AND status = $2 
 		ORDER BY date_time DESC 
 		LIMIT 1
 	`
 	var result reception.Reception
-	err := r.db.GetContext(ctx, &result, query, pvzID, reception.StatusInProgress)
+	err := conn(ctx, r.db).GetContext(ctx, &result, query, pvzID, reception.StatusInProgress)
 	if err == sql.ErrNoRows {
 		return nil, reception.ErrNotFound
 	}
@@ -186,3 +262,45 @@ func (r *ReceptionRepository) GetLastOpen(ctx context.Context, pvzID uuid.UUID)
 	}
 	return &result, nil
 }
+
+// ExportWithProducts построчно передает в fn приемки за период вместе с товарами.
+// Строки читаются из соединения по мере обработки и не накапливаются в памяти.
+func (r *ReceptionRepository) ExportWithProducts(ctx context.Context, startDate, endDate time.Time, fn func(*reception.ExportRow) error) error {
+	query, args, err := queries.ExportReceptionsWithProducts(startDate, endDate)
+	if err != nil {
+		return err
+	}
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to export receptions: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row reception.ExportRow
+		var productDateTime sql.NullTime
+		var productType, barcode sql.NullString
+
+		err := rows.Scan(
+			&row.PVZID, &row.PVZCity,
+			&row.ReceptionID, &row.ReceptionDateTime, &row.Status,
+			&row.ProductID, &productDateTime, &productType, &barcode,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to scan export row: %w", err)
+		}
+
+		if productDateTime.Valid {
+			row.ProductDateTime = &productDateTime.Time
+		}
+		row.ProductType = product.Type(productType.String)
+		row.Barcode = barcode.String
+
+		if err := fn(&row); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
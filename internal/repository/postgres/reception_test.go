@@ -317,14 +317,23 @@ func TestReceptionRepository_Update(t *testing.T) {
 		name    string
 		id      uuid.UUID
 		status  reception.Status
+		version int64
 		wantErr bool
 	}{
 		{
 			name:    "успешное обновление",
 			id:      receptionID,
 			status:  reception.StatusClose,
+			version: 1,
 			wantErr: false,
 		},
+		{
+			name:    "устаревшая версия",
+			id:      receptionID,
+			status:  reception.StatusInProgress,
+			version: 1,
+			wantErr: true,
+		},
 		{
 			name:    "приемка не найдена",
 			id:      uuid.New(),
@@ -336,8 +345,9 @@ func TestReceptionRepository_Update(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			r := &reception.Reception{
-				ID:     tt.id,
-				Status: tt.status,
+				ID:      tt.id,
+				Status:  tt.status,
+				Version: tt.version,
 			}
 			err := repo.Update(ctx, r)
 			if tt.wantErr {
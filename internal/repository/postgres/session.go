@@ -0,0 +1,206 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/session"
+	"github.com/avito/pvz/internal/repository/postgres/queries"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// SessionRepository реализует интерфейс session.Repository.
+// Методы выполняются в транзакции из контекста, если она открыта.
+type SessionRepository struct {
+	db *sqlx.DB
+}
+
+// NewSessionRepository создает новый экземпляр SessionRepository
+func NewSessionRepository(db *sqlx.DB) *SessionRepository {
+	return &SessionRepository{db: db}
+}
+
+// refreshTokenRow строка таблицы refresh_tokens
+type refreshTokenRow struct {
+	ID         uuid.UUID  `db:"id"`
+	SessionID  uuid.UUID  `db:"session_id"`
+	UserID     uuid.UUID  `db:"user_id"`
+	TokenHash  string     `db:"token_hash"`
+	CreatedAt  time.Time  `db:"created_at"`
+	ExpiresAt  time.Time  `db:"expires_at"`
+	UsedAt     *time.Time `db:"used_at"`
+	ReplacedBy *uuid.UUID `db:"replaced_by"`
+	RevokedAt  *time.Time `db:"revoked_at"`
+}
+
+// revocationRow строка таблицы token_revocations
+type revocationRow struct {
+	Kind      string    `db:"kind"`
+	SubjectID uuid.UUID `db:"subject_id"`
+	RevokedAt time.Time `db:"revoked_at"`
+	ExpiresAt time.Time `db:"expires_at"`
+}
+
+// CreateRefreshToken сохраняет токен обновления
+func (r *SessionRepository) CreateRefreshToken(ctx context.Context, t *session.RefreshToken) error {
+	query, args, err := queries.CreateRefreshToken(t)
+	if err != nil {
+		return err
+	}
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, query, args...)
+	return err
+}
+
+// GetRefreshTokenByHash получает токен обновления по хешу
+func (r *SessionRepository) GetRefreshTokenByHash(ctx context.Context, hash string) (*session.RefreshToken, error) {
+	query, args, err := queries.GetRefreshTokenByHash(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	var row refreshTokenRow
+	err = conn(ctx, r.db).GetContext(ctx, &row, query, args...)
+	if err == sql.ErrNoRows {
+		return nil, session.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &session.RefreshToken{
+		ID:         row.ID,
+		SessionID:  row.SessionID,
+		UserID:     row.UserID,
+		TokenHash:  row.TokenHash,
+		CreatedAt:  row.CreatedAt,
+		ExpiresAt:  row.ExpiresAt,
+		UsedAt:     row.UsedAt,
+		ReplacedBy: row.ReplacedBy,
+		RevokedAt:  row.RevokedAt,
+	}, nil
+}
+
+// UseRefreshToken помечает токен использованным
+func (r *SessionRepository) UseRefreshToken(ctx context.Context, id, replacedBy uuid.UUID, at time.Time) (bool, error) {
+	query, args, err := queries.UseRefreshToken(id, replacedBy, at)
+	if err != nil {
+		return false, err
+	}
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rows > 0, nil
+}
+
+// RevokeSession отзывает все токены обновления сессии
+func (r *SessionRepository) RevokeSession(ctx context.Context, sessionID uuid.UUID, at time.Time) error {
+	query, args, err := queries.RevokeSessionRefreshTokens(sessionID, at)
+	if err != nil {
+		return err
+	}
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, query, args...)
+	return err
+}
+
+// RevokeUserSessions отзывает все токены обновления пользователя
+func (r *SessionRepository) RevokeUserSessions(ctx context.Context, userID uuid.UUID, at time.Time) ([]uuid.UUID, error) {
+	query, args, err := queries.RevokeUserRefreshTokens(userID, at)
+	if err != nil {
+		return nil, err
+	}
+
+	var sessionIDs []uuid.UUID
+	if err := conn(ctx, r.db).SelectContext(ctx, &sessionIDs, query, args...); err != nil {
+		return nil, err
+	}
+
+	// Отозванные токены одной сессии возвращаются по одному на строку
+	seen := make(map[uuid.UUID]bool, len(sessionIDs))
+	unique := sessionIDs[:0]
+	for _, id := range sessionIDs {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	return unique, nil
+}
+
+// DeleteExpiredRefreshTokens удаляет токены обновления, истекшие раньше before
+func (r *SessionRepository) DeleteExpiredRefreshTokens(ctx context.Context, before time.Time) (int64, error) {
+	query, args, err := queries.DeleteExpiredRefreshTokens(before)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// AddRevocation добавляет запись в список отзыва
+func (r *SessionRepository) AddRevocation(ctx context.Context, revocation *session.Revocation) error {
+	query, args, err := queries.UpsertTokenRevocation(revocation)
+	if err != nil {
+		return err
+	}
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, query, args...)
+	return err
+}
+
+// ListRevocations возвращает действующие записи списка отзыва
+func (r *SessionRepository) ListRevocations(ctx context.Context, now time.Time) ([]*session.Revocation, error) {
+	query, args, err := queries.ListTokenRevocations(now)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []revocationRow
+	if err := conn(ctx, r.db).SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, err
+	}
+
+	revocations := make([]*session.Revocation, len(rows))
+	for i, row := range rows {
+		revocations[i] = &session.Revocation{
+			Kind:      session.RevocationKind(row.Kind),
+			SubjectID: row.SubjectID,
+			RevokedAt: row.RevokedAt,
+			ExpiresAt: row.ExpiresAt,
+		}
+	}
+
+	return revocations, nil
+}
+
+// DeleteExpiredRevocations удаляет записи списка отзыва, истекшие раньше before
+func (r *SessionRepository) DeleteExpiredRevocations(ctx context.Context, before time.Time) (int64, error) {
+	query, args, err := queries.DeleteExpiredTokenRevocations(before)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
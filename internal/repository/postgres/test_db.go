@@ -67,6 +67,7 @@ func initTestDB(db *sql.DB) error {
 			id UUID PRIMARY KEY,
 			created_at TIMESTAMP WITH TIME ZONE NOT NULL,
 			city VARCHAR(100) NOT NULL,
+			version BIGINT NOT NULL DEFAULT 1,
 			CONSTRAINT city_check CHECK (city IN ('Москва', 'Санкт-Петербург', 'Казань'))
 		);
 
@@ -76,6 +77,7 @@ func initTestDB(db *sql.DB) error {
 			date_time TIMESTAMP WITH TIME ZONE NOT NULL,
 			pvz_id UUID NOT NULL REFERENCES pvzs(id),
 			status VARCHAR(50) NOT NULL DEFAULT 'in_progress',
+			version BIGINT NOT NULL DEFAULT 1,
 			CONSTRAINT status_check CHECK (status IN ('in_progress', 'close'))
 		);
 
@@ -85,15 +87,32 @@ func initTestDB(db *sql.DB) error {
 			date_time TIMESTAMP WITH TIME ZONE NOT NULL,
 			type VARCHAR(50) NOT NULL,
 			reception_id UUID NOT NULL REFERENCES receptions(id),
+			barcode VARCHAR(64) NOT NULL DEFAULT '',
+			metadata TEXT NOT NULL DEFAULT '',
 			CONSTRAINT type_check CHECK (type IN ('electronics', 'clothing', 'food', 'other'))
 		);
 
+		-- Создание таблицы ключей идемпотентности
+		CREATE TABLE IF NOT EXISTS idempotency_keys (
+			user_id VARCHAR(64) NOT NULL,
+			key VARCHAR(255) NOT NULL,
+			request_hash VARCHAR(64) NOT NULL,
+			status_code INTEGER NOT NULL DEFAULT 0,
+			response_headers JSONB NOT NULL DEFAULT '{}',
+			response_body BYTEA,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			completed_at TIMESTAMP WITH TIME ZONE,
+			PRIMARY KEY (user_id, key)
+		);
+
 		-- Создание индексов
 		CREATE INDEX IF NOT EXISTS idx_users_email ON users(email);
 		CREATE INDEX IF NOT EXISTS idx_receptions_pvz_id ON receptions(pvz_id);
 		CREATE INDEX IF NOT EXISTS idx_receptions_status ON receptions(status);
 		CREATE INDEX IF NOT EXISTS idx_products_reception_id ON products(reception_id);
 		CREATE INDEX IF NOT EXISTS idx_receptions_date_time ON receptions(date_time);
+		CREATE INDEX IF NOT EXISTS idx_pvzs_created_at_id ON pvzs(created_at DESC, id DESC);
+		CREATE INDEX IF NOT EXISTS idx_idempotency_keys_created_at ON idempotency_keys(created_at);
 	`)
 	return err
 }
@@ -111,6 +130,7 @@ func SetupTestDB(t *testing.T) *sqlx.DB {
 		TRUNCATE TABLE receptions CASCADE;
 		TRUNCATE TABLE pvzs CASCADE;
 		TRUNCATE TABLE users CASCADE;
+		TRUNCATE TABLE idempotency_keys;
 	`)
 	if err != nil {
 		t.Fatalf("Failed to clean test database: %v", err)
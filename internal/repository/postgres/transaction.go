@@ -3,21 +3,25 @@ package postgres
 import (
 	"context"
 	"database/sql"
+
+	"github.com/avito/pvz/internal/domain/transaction"
+	"github.com/jmoiron/sqlx"
 )
 
 // TransactionManager реализует интерфейс transaction.Manager
 type TransactionManager struct {
-	db *sql.DB
+	db *sqlx.DB
 }
 
 // NewTransactionManager создает новый экземпляр TransactionManager
 func NewTransactionManager(db *sql.DB) *TransactionManager {
-	return &TransactionManager{db: db}
+	return &TransactionManager{db: sqlx.NewDb(db, "postgres")}
 }
 
-// WithinTransaction выполняет функцию в транзакции
+// WithinTransaction выполняет функцию в транзакции. Репозитории, вызванные
+// с контекстом fn, выполняют запросы в этой же транзакции.
 func (tm *TransactionManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
-	tx, err := tm.db.BeginTx(ctx, nil)
+	tx, err := tm.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return err
 	}
@@ -29,10 +33,27 @@ func (tm *TransactionManager) WithinTransaction(ctx context.Context, fn func(ctx
 		}
 	}()
 
-	if err := fn(ctx); err != nil {
+	if err := fn(context.WithValue(ctx, transaction.TransactionCtxKey{}, tx)); err != nil {
 		_ = tx.Rollback()
 		return err
 	}
 
 	return tx.Commit()
 }
+
+// executor методы *sqlx.DB и *sqlx.Tx, через которые репозитории выполняют запросы
+type executor interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+}
+
+// conn возвращает транзакцию, открытую в ctx менеджером транзакций, или db вне транзакции
+func conn(ctx context.Context, db *sqlx.DB) executor {
+	if tx, ok := ctx.Value(transaction.TransactionCtxKey{}).(*sqlx.Tx); ok {
+		return tx
+	}
+	return db
+}
@@ -3,6 +3,7 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/avito/pvz/internal/domain/user"
 	"github.com/avito/pvz/internal/repository/postgres/queries"
@@ -130,3 +131,50 @@ func (r *UserRepository) List(ctx context.Context, offset, limit int) ([]*user.U
 
 	return result, nil
 }
+
+// UpdatePassword заменяет хеш пароля пользователя
+func (r *UserRepository) UpdatePassword(ctx context.Context, id uuid.UUID, password string) error {
+	query, args, err := queries.UpdateUserPassword(id, password)
+	if err != nil {
+		return err
+	}
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return user.ErrNotFound
+	}
+
+	return nil
+}
+
+// MarkEmailVerified отмечает email пользователя подтвержденным. Возвращает
+// user.ErrNotFound, если пользователя нет или его email уже не равен email.
+func (r *UserRepository) MarkEmailVerified(ctx context.Context, id uuid.UUID, email string, at time.Time) error {
+	query, args, err := queries.MarkUserEmailVerified(id, email, at)
+	if err != nil {
+		return err
+	}
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return user.ErrNotFound
+	}
+
+	return nil
+}
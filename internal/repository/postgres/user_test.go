@@ -67,9 +67,9 @@ func TestUserRepository_GetByID(t *testing.T) {
 					CreatedAt: createdAt,
 				}
 
-				rows := sqlmock.NewRows([]string{"id", "email", "password", "role", "created_at"}).
-					AddRow(userID, u.Email, u.Password, u.Role, createdAt)
-				mock.ExpectQuery("SELECT id, email, password, role, created_at FROM users").
+				rows := sqlmock.NewRows([]string{"id", "email", "password", "role", "created_at", "email_verified_at"}).
+					AddRow(userID, u.Email, u.Password, u.Role, createdAt, nil)
+				mock.ExpectQuery("SELECT id, email, password, role, created_at, email_verified_at FROM users").
 					WithArgs(userID).
 					WillReturnRows(rows)
 
@@ -81,7 +81,7 @@ func TestUserRepository_GetByID(t *testing.T) {
 			name: "пользователь не найден",
 			setup: func() (uuid.UUID, *user.User) {
 				userID := uuid.New()
-				mock.ExpectQuery("SELECT id, email, password, role, created_at FROM users").
+				mock.ExpectQuery("SELECT id, email, password, role, created_at, email_verified_at FROM users").
 					WithArgs(userID).
 					WillReturnError(sql.ErrNoRows)
 				return userID, nil
@@ -132,9 +132,9 @@ func TestUserRepository_GetByEmail(t *testing.T) {
 			name:  "успешное получение пользователя",
 			email: "test@example.com",
 			mockSetup: func() {
-				rows := sqlmock.NewRows([]string{"id", "email", "password", "role", "created_at"}).
-					AddRow(uuid.New(), "test@example.com", "password123", user.RoleAdmin, time.Now())
-				mock.ExpectQuery("SELECT id, email, password, role, created_at FROM users").
+				rows := sqlmock.NewRows([]string{"id", "email", "password", "role", "created_at", "email_verified_at"}).
+					AddRow(uuid.New(), "test@example.com", "password123", user.RoleAdmin, time.Now(), nil)
+				mock.ExpectQuery("SELECT id, email, password, role, created_at, email_verified_at FROM users").
 					WithArgs("test@example.com").
 					WillReturnRows(rows)
 			},
@@ -151,7 +151,7 @@ func TestUserRepository_GetByEmail(t *testing.T) {
 			name:  "пользователь не найден",
 			email: "nonexistent@example.com",
 			mockSetup: func() {
-				mock.ExpectQuery("SELECT id, email, password, role, created_at FROM users").
+				mock.ExpectQuery("SELECT id, email, password, role, created_at, email_verified_at FROM users").
 					WithArgs("nonexistent@example.com").
 					WillReturnError(sql.ErrNoRows)
 			},
@@ -197,7 +197,7 @@ func TestUserRepository_Update(t *testing.T) {
 	}
 
 	mock.ExpectExec("UPDATE users SET").
-		WithArgs(u.Email, u.Password, u.Role, u.ID).
+		WithArgs(u.Email, u.Email, u.Password, u.Role, u.ID).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	err = repo.Update(context.Background(), u)
@@ -261,12 +261,12 @@ func TestUserRepository_List(t *testing.T) {
 					},
 				}
 
-				rows := sqlmock.NewRows([]string{"id", "email", "password", "role", "created_at"})
+				rows := sqlmock.NewRows([]string{"id", "email", "password", "role", "created_at", "email_verified_at"})
 				for _, u := range users {
-					rows.AddRow(u.ID, u.Email, u.Password, u.Role, u.CreatedAt)
+					rows.AddRow(u.ID, u.Email, u.Password, u.Role, u.CreatedAt, nil)
 				}
 
-				mock.ExpectQuery("SELECT id, email, password, role, created_at FROM users ORDER BY created_at DESC LIMIT 10 OFFSET 0").
+				mock.ExpectQuery("SELECT id, email, password, role, created_at, email_verified_at FROM users ORDER BY created_at DESC LIMIT 10 OFFSET 0").
 					WillReturnRows(rows)
 
 				return users
@@ -278,8 +278,8 @@ func TestUserRepository_List(t *testing.T) {
 			offset: 0,
 			limit:  10,
 			mockSetup: func() []*user.User {
-				rows := sqlmock.NewRows([]string{"id", "email", "password", "role", "created_at"})
-				mock.ExpectQuery("SELECT id, email, password, role, created_at FROM users ORDER BY created_at DESC LIMIT 10 OFFSET 0").
+				rows := sqlmock.NewRows([]string{"id", "email", "password", "role", "created_at", "email_verified_at"})
+				mock.ExpectQuery("SELECT id, email, password, role, created_at, email_verified_at FROM users ORDER BY created_at DESC LIMIT 10 OFFSET 0").
 					WillReturnRows(rows)
 				return []*user.User{}
 			},
@@ -290,7 +290,7 @@ func TestUserRepository_List(t *testing.T) {
 			offset: 0,
 			limit:  10,
 			mockSetup: func() []*user.User {
-				mock.ExpectQuery("SELECT id, email, password, role, created_at FROM users ORDER BY created_at DESC LIMIT 10 OFFSET 0").
+				mock.ExpectQuery("SELECT id, email, password, role, created_at, email_verified_at FROM users ORDER BY created_at DESC LIMIT 10 OFFSET 0").
 					WillReturnError(sql.ErrConnDone)
 				return nil
 			},
@@ -342,7 +342,7 @@ func TestUserRepository_Update_NotFound(t *testing.T) {
 	}
 
 	mock.ExpectExec("UPDATE users SET").
-		WithArgs(u.Email, u.Password, u.Role, u.ID).
+		WithArgs(u.Email, u.Email, u.Password, u.Role, u.ID).
 		WillReturnResult(sqlmock.NewResult(0, 0))
 
 	err = repo.Update(context.Background(), u)
@@ -370,3 +370,38 @@ func TestUserRepository_Delete_NotFound(t *testing.T) {
 	require.Equal(t, user.ErrNotFound, err)
 	require.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestUserRepository_UpdatePassword(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewUserRepository(sqlx.NewDb(db, "sqlmock"))
+	id := uuid.New()
+
+	mock.ExpectExec("UPDATE users SET password").
+		WithArgs("new-hash", id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	require.NoError(t, repo.UpdatePassword(context.Background(), id, "new-hash"))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_MarkEmailVerified(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewUserRepository(sqlx.NewDb(db, "sqlmock"))
+	id := uuid.New()
+	now := time.Now()
+
+	// Email изменился после отправки ссылки: строка не обновлена
+	mock.ExpectExec("UPDATE users SET email_verified_at").
+		WithArgs(now, "old@example.com", id).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.MarkEmailVerified(context.Background(), id, "old@example.com", now)
+	assert.ErrorIs(t, err, user.ErrNotFound)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,100 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/usertoken"
+	"github.com/avito/pvz/internal/repository/postgres/queries"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// UserTokenRepository реализует интерфейс usertoken.Repository.
+// Методы выполняются в транзакции из контекста, если она открыта.
+type UserTokenRepository struct {
+	db *sqlx.DB
+}
+
+// NewUserTokenRepository создает новый экземпляр UserTokenRepository
+func NewUserTokenRepository(db *sqlx.DB) *UserTokenRepository {
+	return &UserTokenRepository{db: db}
+}
+
+// userTokenRow строка таблицы user_tokens
+type userTokenRow struct {
+	ID        uuid.UUID  `db:"id"`
+	UserID    uuid.UUID  `db:"user_id"`
+	Purpose   string     `db:"purpose"`
+	Email     string     `db:"email"`
+	TokenHash string     `db:"token_hash"`
+	CreatedAt time.Time  `db:"created_at"`
+	ExpiresAt time.Time  `db:"expires_at"`
+	UsedAt    *time.Time `db:"used_at"`
+}
+
+// Create сохраняет токен
+func (r *UserTokenRepository) Create(ctx context.Context, t *usertoken.Token) error {
+	query, args, err := queries.CreateUserToken(t)
+	if err != nil {
+		return err
+	}
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, query, args...)
+	return err
+}
+
+// Use помечает действующий токен использованным и возвращает его
+func (r *UserTokenRepository) Use(ctx context.Context, hash string, purpose usertoken.Purpose, at time.Time) (*usertoken.Token, error) {
+	query, args, err := queries.UseUserToken(hash, purpose, at)
+	if err != nil {
+		return nil, err
+	}
+
+	var row userTokenRow
+	err = conn(ctx, r.db).GetContext(ctx, &row, query, args...)
+	if err == sql.ErrNoRows {
+		return nil, usertoken.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &usertoken.Token{
+		ID:        row.ID,
+		UserID:    row.UserID,
+		Email:     row.Email,
+		Purpose:   usertoken.Purpose(row.Purpose),
+		TokenHash: row.TokenHash,
+		CreatedAt: row.CreatedAt,
+		ExpiresAt: row.ExpiresAt,
+		UsedAt:    row.UsedAt,
+	}, nil
+}
+
+// Invalidate помечает неиспользованные токены пользователя с назначением purpose использованными
+func (r *UserTokenRepository) Invalidate(ctx context.Context, userID uuid.UUID, purpose usertoken.Purpose, at time.Time) error {
+	query, args, err := queries.InvalidateUserTokens(userID, purpose, at)
+	if err != nil {
+		return err
+	}
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, query, args...)
+	return err
+}
+
+// DeleteExpired удаляет токены, истекшие раньше before
+func (r *UserTokenRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	query, args, err := queries.DeleteExpiredUserTokens(before)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,250 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/event"
+	"github.com/avito/pvz/internal/domain/webhook"
+	"github.com/avito/pvz/internal/repository/postgres/queries"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// WebhookRepository реализует интерфейс webhook.Repository
+type WebhookRepository struct {
+	db *sqlx.DB
+}
+
+// NewWebhookRepository создает новый экземпляр WebhookRepository
+func NewWebhookRepository(db *sqlx.DB) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+// webhookSubscriptionRow строка таблицы webhook_subscriptions
+type webhookSubscriptionRow struct {
+	ID         uuid.UUID      `db:"id"`
+	URL        string         `db:"url"`
+	EventTypes pq.StringArray `db:"event_types"`
+	Secret     string         `db:"secret"`
+	CreatedBy  uuid.UUID      `db:"created_by"`
+	CreatedAt  time.Time      `db:"created_at"`
+}
+
+func (row *webhookSubscriptionRow) toDomain() *webhook.Subscription {
+	eventTypes := make([]event.Type, len(row.EventTypes))
+	for i, t := range row.EventTypes {
+		eventTypes[i] = event.Type(t)
+	}
+
+	return &webhook.Subscription{
+		ID:         row.ID,
+		URL:        row.URL,
+		EventTypes: eventTypes,
+		Secret:     row.Secret,
+		CreatedBy:  row.CreatedBy,
+		CreatedAt:  row.CreatedAt,
+	}
+}
+
+// webhookDeliveryRow строка таблицы webhook_deliveries
+type webhookDeliveryRow struct {
+	ID             uuid.UUID  `db:"id"`
+	SubscriptionID uuid.UUID  `db:"subscription_id"`
+	EventType      string     `db:"event_type"`
+	Payload        []byte     `db:"payload"`
+	Status         string     `db:"status"`
+	Attempts       int        `db:"attempts"`
+	NextAttemptAt  time.Time  `db:"next_attempt_at"`
+	LastStatusCode int        `db:"last_status_code"`
+	LastError      string     `db:"last_error"`
+	CreatedAt      time.Time  `db:"created_at"`
+	DeliveredAt    *time.Time `db:"delivered_at"`
+}
+
+func (row *webhookDeliveryRow) toDomain() *webhook.Delivery {
+	return &webhook.Delivery{
+		ID:             row.ID,
+		SubscriptionID: row.SubscriptionID,
+		EventType:      event.Type(row.EventType),
+		Payload:        row.Payload,
+		Status:         webhook.DeliveryStatus(row.Status),
+		Attempts:       row.Attempts,
+		NextAttemptAt:  row.NextAttemptAt,
+		LastStatusCode: row.LastStatusCode,
+		LastError:      row.LastError,
+		CreatedAt:      row.CreatedAt,
+		DeliveredAt:    row.DeliveredAt,
+	}
+}
+
+// CreateSubscription сохраняет новую подписку
+func (r *WebhookRepository) CreateSubscription(ctx context.Context, s *webhook.Subscription) error {
+	query, args, err := queries.CreateWebhookSubscription(s)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.ExecContext(ctx, query, args...)
+	return err
+}
+
+// GetSubscription получает подписку по ID
+func (r *WebhookRepository) GetSubscription(ctx context.Context, id uuid.UUID) (*webhook.Subscription, error) {
+	query, args, err := queries.GetWebhookSubscription(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var row webhookSubscriptionRow
+	err = r.db.GetContext(ctx, &row, query, args...)
+	if err == sql.ErrNoRows {
+		return nil, webhook.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return row.toDomain(), nil
+}
+
+// ListSubscriptions возвращает все подписки
+func (r *WebhookRepository) ListSubscriptions(ctx context.Context) ([]*webhook.Subscription, error) {
+	query, args, err := queries.ListWebhookSubscriptions()
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []webhookSubscriptionRow
+	if err := conn(ctx, r.db).SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, err
+	}
+
+	result := make([]*webhook.Subscription, len(rows))
+	for i := range rows {
+		result[i] = rows[i].toDomain()
+	}
+
+	return result, nil
+}
+
+// DeleteSubscription удаляет подписку вместе с ее доставками
+func (r *WebhookRepository) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	query, args, err := queries.DeleteWebhookSubscription(id)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return webhook.ErrNotFound
+	}
+
+	return nil
+}
+
+// CreateDeliveries сохраняет новые доставки
+func (r *WebhookRepository) CreateDeliveries(ctx context.Context, deliveries []*webhook.Delivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	query, args, err := queries.CreateWebhookDeliveries(deliveries)
+	if err != nil {
+		return err
+	}
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, query, args...)
+	return err
+}
+
+// ClaimDueDeliveries выбирает ожидающие доставки и откладывает их следующую попытку на lease
+func (r *WebhookRepository) ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*webhook.Delivery, error) {
+	query, args, err := queries.ClaimDueWebhookDeliveries(now, now.Add(lease), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []webhookDeliveryRow
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, err
+	}
+
+	return webhookDeliveries(rows), nil
+}
+
+// GetDelivery получает доставку по ID
+func (r *WebhookRepository) GetDelivery(ctx context.Context, id uuid.UUID) (*webhook.Delivery, error) {
+	query, args, err := queries.GetWebhookDelivery(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var row webhookDeliveryRow
+	err = r.db.GetContext(ctx, &row, query, args...)
+	if err == sql.ErrNoRows {
+		return nil, webhook.ErrDeliveryNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return row.toDomain(), nil
+}
+
+// UpdateDelivery сохраняет результат попытки доставки
+func (r *WebhookRepository) UpdateDelivery(ctx context.Context, d *webhook.Delivery) error {
+	query, args, err := queries.UpdateWebhookDelivery(d)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return webhook.ErrDeliveryNotFound
+	}
+
+	return nil
+}
+
+// ListDeliveries возвращает историю доставок подписки, новые первыми
+func (r *WebhookRepository) ListDeliveries(ctx context.Context, filter webhook.DeliveryFilter) ([]*webhook.Delivery, error) {
+	query, args, err := queries.ListWebhookDeliveries(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []webhookDeliveryRow
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, err
+	}
+
+	return webhookDeliveries(rows), nil
+}
+
+// webhookDeliveries переводит строки таблицы в доставки
+func webhookDeliveries(rows []webhookDeliveryRow) []*webhook.Delivery {
+	result := make([]*webhook.Delivery, len(rows))
+	for i := range rows {
+		result[i] = rows[i].toDomain()
+	}
+	return result
+}
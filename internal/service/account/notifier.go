@@ -0,0 +1,76 @@
+package account
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/usertoken"
+	"github.com/google/uuid"
+)
+
+// Message письмо пользователю со ссылкой сброса пароля или подтверждения email
+type Message struct {
+	Purpose usertoken.Purpose `json:"purpose"`
+	UserID  uuid.UUID         `json:"userId"`
+	// To адрес получателя
+	To string `json:"to"`
+	// Token одноразовый токен, который пользователь предъявит по ссылке
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// Notifier доставляет письма пользователям. Реализация для почты или
+// мессенджера подключается вместо LogNotifier и NDJSONNotifier.
+type Notifier interface {
+	Notify(ctx context.Context, msg Message) error
+}
+
+// LogNotifier пишет письма в лог. Используется при локальном запуске.
+type LogNotifier struct{}
+
+// Notify пишет письмо в лог
+func (LogNotifier) Notify(_ context.Context, msg Message) error {
+	log.Printf("Notification %s to %s: token %s, expires at %s",
+		msg.Purpose, msg.To, msg.Token, msg.ExpiresAt.Format(time.RFC3339))
+	return nil
+}
+
+// NDJSONNotifier записывает письма построчно в формате NDJSON, например в
+// локальный файл исходящих писем, который читает отдельный отправитель
+type NDJSONNotifier struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewNDJSONNotifier создает новый экземпляр NDJSONNotifier
+func NewNDJSONNotifier(w io.Writer) *NDJSONNotifier {
+	return &NDJSONNotifier{w: w}
+}
+
+// OpenNDJSONFile открывает файл path для дозаписи и возвращает отправителя
+// вместе с файлом, который нужно закрыть при остановке
+func OpenNDJSONFile(path string) (*NDJSONNotifier, *os.File, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, nil, err
+	}
+	return NewNDJSONNotifier(f), f, nil
+}
+
+// Notify записывает письмо одной строкой
+func (n *NDJSONNotifier) Notify(_ context.Context, msg Message) error {
+	line, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	_, err = n.w.Write(append(line, '\n'))
+	return err
+}
@@ -0,0 +1,35 @@
+package account
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/usertoken"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNDJSONNotifier(t *testing.T) {
+	var buf bytes.Buffer
+	notifier := NewNDJSONNotifier(&buf)
+	msg := Message{
+		Purpose:   usertoken.PurposePasswordReset,
+		UserID:    uuid.New(),
+		To:        "user@example.com",
+		Token:     "token",
+		ExpiresAt: time.Date(2026, time.October, 1, 13, 0, 0, 0, time.UTC),
+	}
+
+	require.NoError(t, notifier.Notify(context.Background(), msg))
+	require.NoError(t, notifier.Notify(context.Background(), msg))
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 2)
+	var got Message
+	require.NoError(t, json.Unmarshal(lines[0], &got))
+	assert.Equal(t, msg, got)
+}
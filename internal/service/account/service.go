@@ -0,0 +1,258 @@
+// Package account восстанавливает доступ к учетной записи: сбрасывает забытый
+// пароль и подтверждает email по одноразовым ссылкам из писем. Ссылки
+// действуют ограниченное время и только один раз, а при выдаче новой ссылки
+// прежние перестают действовать.
+package account
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/audit"
+	"github.com/avito/pvz/internal/domain/transaction"
+	"github.com/avito/pvz/internal/domain/user"
+	"github.com/avito/pvz/internal/domain/usertoken"
+	userservice "github.com/avito/pvz/internal/service/user"
+	"github.com/avito/pvz/pkg/auth"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrInvalidToken токен не найден, истек или уже использован
+	ErrInvalidToken = errors.New("invalid or expired token")
+	// ErrEmailAlreadyVerified email пользователя уже подтвержден
+	ErrEmailAlreadyVerified = errors.New("email already verified")
+	// ErrUnauthorized запрос подтверждения без пользователя в контексте
+	ErrUnauthorized = errors.New("unauthorized")
+)
+
+// Config параметры одноразовых ссылок
+type Config struct {
+	// ResetTTL время действия ссылки сброса пароля
+	ResetTTL time.Duration
+	// VerificationTTL время действия ссылки подтверждения email
+	VerificationTTL time.Duration
+	// CleanupInterval период удаления истекших токенов
+	CleanupInterval time.Duration
+}
+
+// DefaultConfig параметры одноразовых ссылок по умолчанию
+var DefaultConfig = Config{
+	ResetTTL:        time.Hour,
+	VerificationTTL: 48 * time.Hour,
+	CleanupInterval: time.Hour,
+}
+
+// Users хранилище пользователей
+type Users interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*user.User, error)
+	GetByEmail(ctx context.Context, email string) (*user.User, error)
+	// UpdatePassword заменяет хеш пароля пользователя
+	UpdatePassword(ctx context.Context, id uuid.UUID, password string) error
+	// MarkEmailVerified отмечает email подтвержденным, если email пользователя не изменился
+	MarkEmailVerified(ctx context.Context, id uuid.UUID, email string, at time.Time) error
+}
+
+// Sessions завершает сессии пользователя
+type Sessions interface {
+	LogoutAll(ctx context.Context, userID uuid.UUID) error
+}
+
+// Service выдает и принимает одноразовые ссылки сброса пароля и подтверждения email
+type Service struct {
+	tokens    usertoken.Repository
+	users     Users
+	txManager transaction.Manager
+	notifier  Notifier
+	sessions  Sessions
+	audit     audit.Writer
+	cfg       Config
+	now       func() time.Time
+}
+
+// New создает новый экземпляр Service. Ссылки отправляются через notifier,
+// а после сброса пароля сессии пользователя завершаются через sessions.
+// Сброс пароля и подтверждение email записываются в журнал audit.
+func New(tokens usertoken.Repository, users Users, txManager transaction.Manager, notifier Notifier, sessions Sessions, audit audit.Writer, cfg Config) *Service {
+	return &Service{
+		tokens:    tokens,
+		users:     users,
+		txManager: txManager,
+		notifier:  notifier,
+		sessions:  sessions,
+		audit:     audit,
+		cfg:       cfg,
+		now:       time.Now,
+	}
+}
+
+// RequestPasswordReset отправляет ссылку сброса пароля на email. Ответ не
+// зависит от того, зарегистрирован ли email: для неизвестного адреса письмо
+// не отправляется, а ошибки отправки только пишутся в лог.
+func (s *Service) RequestPasswordReset(ctx context.Context, email string) error {
+	u, err := s.users.GetByEmail(ctx, email)
+	if errors.Is(err, user.ErrNotFound) || errors.Is(err, user.ErrUserNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := s.issue(ctx, u, usertoken.PurposePasswordReset, s.cfg.ResetTTL); err != nil {
+		log.Printf("Failed to send password reset to user %s: %v", u.ID, err)
+	}
+	return nil
+}
+
+// ResetPassword заменяет пароль по ссылке сброса и завершает все сессии
+// пользователя: если пароль утек, прежние сессии перестают действовать
+func (s *Service) ResetPassword(ctx context.Context, token, password string) error {
+	hash, err := userservice.HashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	var userID uuid.UUID
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		t, err := s.use(ctx, token, usertoken.PurposePasswordReset)
+		if err != nil {
+			return err
+		}
+
+		u, err := s.users.GetByID(ctx, t.UserID)
+		if errors.Is(err, user.ErrNotFound) || errors.Is(err, user.ErrUserNotFound) {
+			return ErrInvalidToken
+		}
+		if err != nil {
+			return err
+		}
+		// Ссылка ушла на прежний адрес: после смены email она не действует
+		if u.Email != t.Email {
+			return ErrInvalidToken
+		}
+
+		userID = u.ID
+		if err := s.users.UpdatePassword(ctx, u.ID, hash); err != nil {
+			return err
+		}
+		// Хеш пароля в журнал не попадает, записывается только факт сброса
+		return s.record(ctx, u.ID, nil, audit.State{"passwordReset": true})
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.sessions.LogoutAll(ctx, userID)
+}
+
+// RequestEmailVerification отправляет ссылку подтверждения на email
+// пользователя из контекста
+func (s *Service) RequestEmailVerification(ctx context.Context) error {
+	userID, ok := auth.GetUserID(ctx)
+	if !ok {
+		return ErrUnauthorized
+	}
+
+	u, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if u.EmailVerifiedAt != nil {
+		return ErrEmailAlreadyVerified
+	}
+
+	return s.issue(ctx, u, usertoken.PurposeEmailVerification, s.cfg.VerificationTTL)
+}
+
+// VerifyEmail подтверждает email по ссылке подтверждения
+func (s *Service) VerifyEmail(ctx context.Context, token string) error {
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		t, err := s.use(ctx, token, usertoken.PurposeEmailVerification)
+		if err != nil {
+			return err
+		}
+
+		verifiedAt := s.now()
+		err = s.users.MarkEmailVerified(ctx, t.UserID, t.Email, verifiedAt)
+		if errors.Is(err, user.ErrNotFound) || errors.Is(err, user.ErrUserNotFound) {
+			return ErrInvalidToken
+		}
+		if err != nil {
+			return err
+		}
+		return s.record(ctx, t.UserID, audit.State{"emailVerifiedAt": nil}, audit.State{"emailVerifiedAt": verifiedAt})
+	})
+}
+
+// record записывает изменение пользователя по ссылке в журнал аудита. Изменение
+// выполняет сам пользователь, получивший ссылку.
+func (s *Service) record(ctx context.Context, userID uuid.UUID, before, after audit.State) error {
+	entry, err := audit.NewEntry(ctx, userID, audit.EntityUser, userID, audit.ActionUpdate, before, after)
+	if err != nil {
+		return err
+	}
+	return s.audit.Record(ctx, entry)
+}
+
+// Run периодически удаляет истекшие токены до отмены ctx
+func (s *Service) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.CleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.tokens.DeleteExpired(ctx, s.now()); err != nil && ctx.Err() == nil {
+				log.Printf("Failed to delete expired user tokens: %v", err)
+			}
+		}
+	}
+}
+
+// issue выдает пользователю новую ссылку с назначением purpose и отправляет ее.
+// Ранее выданные ссылки с тем же назначением перестают действовать.
+func (s *Service) issue(ctx context.Context, u *user.User, purpose usertoken.Purpose, ttl time.Duration) error {
+	raw, err := auth.NewOpaqueToken()
+	if err != nil {
+		return err
+	}
+	t := usertoken.New(u.ID, u.Email, purpose, auth.HashOpaqueToken(raw), s.now(), ttl)
+
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.tokens.Invalidate(ctx, u.ID, purpose, t.CreatedAt); err != nil {
+			return err
+		}
+		return s.tokens.Create(ctx, t)
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.notifier.Notify(ctx, Message{
+		Purpose:   purpose,
+		UserID:    u.ID,
+		To:        u.Email,
+		Token:     raw,
+		ExpiresAt: t.ExpiresAt,
+	})
+}
+
+// use отмечает токен использованным и возвращает его
+func (s *Service) use(ctx context.Context, token string, purpose usertoken.Purpose) (*usertoken.Token, error) {
+	if token == "" {
+		return nil, ErrInvalidToken
+	}
+
+	t, err := s.tokens.Use(ctx, auth.HashOpaqueToken(token), purpose, s.now())
+	if errors.Is(err, usertoken.ErrNotFound) {
+		return nil, ErrInvalidToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
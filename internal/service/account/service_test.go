@@ -0,0 +1,241 @@
+package account
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/audit"
+	"github.com/avito/pvz/internal/domain/user"
+	"github.com/avito/pvz/internal/domain/usertoken"
+	userservice "github.com/avito/pvz/internal/service/user"
+	"github.com/avito/pvz/pkg/auth"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// memoryTokens хранит токены в памяти
+type memoryTokens struct {
+	tokens map[string]*usertoken.Token
+}
+
+func (r *memoryTokens) Create(_ context.Context, t *usertoken.Token) error {
+	r.tokens[t.TokenHash] = t
+	return nil
+}
+
+func (r *memoryTokens) Use(_ context.Context, hash string, purpose usertoken.Purpose, at time.Time) (*usertoken.Token, error) {
+	t, ok := r.tokens[hash]
+	if !ok || t.Purpose != purpose || t.UsedAt != nil || !at.Before(t.ExpiresAt) {
+		return nil, usertoken.ErrNotFound
+	}
+	t.UsedAt = &at
+	return t, nil
+}
+
+func (r *memoryTokens) Invalidate(_ context.Context, userID uuid.UUID, purpose usertoken.Purpose, at time.Time) error {
+	for _, t := range r.tokens {
+		if t.UserID == userID && t.Purpose == purpose && t.UsedAt == nil {
+			t.UsedAt = &at
+		}
+	}
+	return nil
+}
+
+func (r *memoryTokens) DeleteExpired(context.Context, time.Time) (int64, error) {
+	return 0, nil
+}
+
+// memoryUsers хранит пользователей в памяти
+type memoryUsers struct {
+	users map[uuid.UUID]*user.User
+}
+
+func (r *memoryUsers) GetByID(_ context.Context, id uuid.UUID) (*user.User, error) {
+	u, ok := r.users[id]
+	if !ok {
+		return nil, user.ErrNotFound
+	}
+	return u, nil
+}
+
+func (r *memoryUsers) GetByEmail(_ context.Context, email string) (*user.User, error) {
+	for _, u := range r.users {
+		if u.Email == email {
+			return u, nil
+		}
+	}
+	return nil, user.ErrNotFound
+}
+
+func (r *memoryUsers) UpdatePassword(_ context.Context, id uuid.UUID, password string) error {
+	r.users[id].Password = password
+	return nil
+}
+
+func (r *memoryUsers) MarkEmailVerified(_ context.Context, id uuid.UUID, email string, at time.Time) error {
+	u, ok := r.users[id]
+	if !ok || u.Email != email {
+		return user.ErrNotFound
+	}
+	u.EmailVerifiedAt = &at
+	return nil
+}
+
+// recordingNotifier запоминает отправленные письма
+type recordingNotifier struct {
+	messages []Message
+}
+
+func (n *recordingNotifier) Notify(_ context.Context, msg Message) error {
+	n.messages = append(n.messages, msg)
+	return nil
+}
+
+// recordingSessions запоминает пользователей, чьи сессии завершены
+type recordingSessions struct {
+	loggedOut []uuid.UUID
+}
+
+func (s *recordingSessions) LogoutAll(_ context.Context, userID uuid.UUID) error {
+	s.loggedOut = append(s.loggedOut, userID)
+	return nil
+}
+
+// fakeTxManager выполняет функцию без транзакции
+type fakeTxManager struct{}
+
+func (fakeTxManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type testService struct {
+	*Service
+	users    *memoryUsers
+	notifier *recordingNotifier
+	sessions *recordingSessions
+	user     *user.User
+	now      time.Time
+}
+
+func newTestService() *testService {
+	u := user.New("user@example.com", "old-hash", user.RoleEmployee)
+	ts := &testService{
+		users:    &memoryUsers{users: map[uuid.UUID]*user.User{u.ID: u}},
+		notifier: new(recordingNotifier),
+		sessions: new(recordingSessions),
+		user:     u,
+		now:      time.Date(2026, time.October, 1, 12, 0, 0, 0, time.UTC),
+	}
+	tokens := &memoryTokens{tokens: make(map[string]*usertoken.Token)}
+	ts.Service = New(tokens, ts.users, fakeTxManager{}, ts.notifier, ts.sessions, audit.Discard, DefaultConfig)
+	ts.Service.now = func() time.Time { return ts.now }
+	return ts
+}
+
+// lastToken возвращает токен из последнего письма
+func (ts *testService) lastToken(t *testing.T) string {
+	require.NotEmpty(t, ts.notifier.messages)
+	return ts.notifier.messages[len(ts.notifier.messages)-1].Token
+}
+
+func TestService_ResetPassword(t *testing.T) {
+	ts := newTestService()
+	ctx := context.Background()
+
+	require.NoError(t, ts.RequestPasswordReset(ctx, "user@example.com"))
+	require.Len(t, ts.notifier.messages, 1)
+	msg := ts.notifier.messages[0]
+	assert.Equal(t, usertoken.PurposePasswordReset, msg.Purpose)
+	assert.Equal(t, "user@example.com", msg.To)
+	assert.Equal(t, ts.now.Add(time.Hour), msg.ExpiresAt)
+
+	require.NoError(t, ts.ResetPassword(ctx, msg.Token, "NewPassw0rd!"))
+	assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(ts.user.Password), []byte("NewPassw0rd!")))
+	assert.Equal(t, []uuid.UUID{ts.user.ID}, ts.sessions.loggedOut)
+
+	// Ссылка одноразовая
+	assert.ErrorIs(t, ts.ResetPassword(ctx, msg.Token, "OtherPassw0rd!"), ErrInvalidToken)
+}
+
+func TestService_ResetPasswordInvalid(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("неизвестный email", func(t *testing.T) {
+		ts := newTestService()
+
+		require.NoError(t, ts.RequestPasswordReset(ctx, "unknown@example.com"))
+		assert.Empty(t, ts.notifier.messages)
+	})
+
+	t.Run("ссылка истекла", func(t *testing.T) {
+		ts := newTestService()
+		require.NoError(t, ts.RequestPasswordReset(ctx, "user@example.com"))
+
+		ts.now = ts.now.Add(time.Hour)
+		assert.ErrorIs(t, ts.ResetPassword(ctx, ts.lastToken(t), "NewPassw0rd!"), ErrInvalidToken)
+		assert.Equal(t, "old-hash", ts.user.Password)
+	})
+
+	t.Run("новая ссылка отменяет прежнюю", func(t *testing.T) {
+		ts := newTestService()
+		require.NoError(t, ts.RequestPasswordReset(ctx, "user@example.com"))
+		first := ts.lastToken(t)
+		require.NoError(t, ts.RequestPasswordReset(ctx, "user@example.com"))
+
+		assert.ErrorIs(t, ts.ResetPassword(ctx, first, "NewPassw0rd!"), ErrInvalidToken)
+		assert.NoError(t, ts.ResetPassword(ctx, ts.lastToken(t), "NewPassw0rd!"))
+	})
+
+	t.Run("email изменен после запроса", func(t *testing.T) {
+		ts := newTestService()
+		require.NoError(t, ts.RequestPasswordReset(ctx, "user@example.com"))
+		ts.user.Email = "new@example.com"
+
+		assert.ErrorIs(t, ts.ResetPassword(ctx, ts.lastToken(t), "NewPassw0rd!"), ErrInvalidToken)
+	})
+
+	t.Run("слабый пароль", func(t *testing.T) {
+		ts := newTestService()
+		require.NoError(t, ts.RequestPasswordReset(ctx, "user@example.com"))
+
+		assert.ErrorIs(t, ts.ResetPassword(ctx, ts.lastToken(t), "weak"), userservice.ErrInvalidPassword)
+		// Ссылка не израсходована неудачной попыткой
+		assert.NoError(t, ts.ResetPassword(ctx, ts.lastToken(t), "NewPassw0rd!"))
+	})
+}
+
+func TestService_VerifyEmail(t *testing.T) {
+	ts := newTestService()
+
+	assert.ErrorIs(t, ts.RequestEmailVerification(context.Background()), ErrUnauthorized)
+
+	ctx := auth.WithUserID(context.Background(), ts.user.ID)
+	require.NoError(t, ts.RequestEmailVerification(ctx))
+	msg := ts.notifier.messages[0]
+	assert.Equal(t, usertoken.PurposeEmailVerification, msg.Purpose)
+	assert.Equal(t, ts.now.Add(48*time.Hour), msg.ExpiresAt)
+
+	// Ссылка подтверждения не подходит для сброса пароля
+	assert.ErrorIs(t, ts.ResetPassword(ctx, msg.Token, "NewPassw0rd!"), ErrInvalidToken)
+
+	require.NoError(t, ts.VerifyEmail(context.Background(), msg.Token))
+	require.NotNil(t, ts.user.EmailVerifiedAt)
+	assert.Equal(t, ts.now, *ts.user.EmailVerifiedAt)
+
+	assert.ErrorIs(t, ts.VerifyEmail(context.Background(), msg.Token), ErrInvalidToken)
+	assert.ErrorIs(t, ts.RequestEmailVerification(ctx), ErrEmailAlreadyVerified)
+}
+
+func TestService_VerifyEmailChanged(t *testing.T) {
+	ts := newTestService()
+	ctx := auth.WithUserID(context.Background(), ts.user.ID)
+
+	require.NoError(t, ts.RequestEmailVerification(ctx))
+	ts.user.Email = "new@example.com"
+
+	assert.ErrorIs(t, ts.VerifyEmail(ctx, ts.lastToken(t)), ErrInvalidToken)
+	assert.Nil(t, ts.user.EmailVerifiedAt)
+}
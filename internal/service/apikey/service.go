@@ -0,0 +1,231 @@
+// Package apikey управляет сервисными аккаунтами внешних систем и их ключами
+// API и проверяет ключи, предъявленные в заголовке Authorization. Ключ
+// показывается один раз при создании, а хранится только его хеш. Вызывающий
+// с ключом получает разрешения из областей ключа — те же, что выдаются ролям.
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/apikey"
+	"github.com/avito/pvz/internal/domain/rbac"
+	"github.com/avito/pvz/pkg/auth"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidName   = errors.New("invalid service account name")
+	ErrInvalidScopes = errors.New("invalid api key scopes")
+	ErrInvalidExpiry = errors.New("invalid api key expiry")
+	// ErrInvalidKey ключ не найден, истек или отозван
+	ErrInvalidKey = errors.New("invalid api key")
+)
+
+// maxNameLength наибольшая длина имени сервисного аккаунта и ключа
+const maxNameLength = 255
+
+// Config параметры ключей API
+type Config struct {
+	// DefaultTTL срок действия ключа, если время истечения не задано
+	DefaultTTL time.Duration
+	// MaxTTL наибольший срок действия ключа
+	MaxTTL time.Duration
+	// TouchInterval время последнего использования ключа обновляется не
+	// чаще этого интервала, чтобы не писать в базу на каждый запрос
+	TouchInterval time.Duration
+}
+
+// DefaultConfig параметры ключей API по умолчанию
+var DefaultConfig = Config{
+	DefaultTTL:    90 * 24 * time.Hour,
+	MaxTTL:        365 * 24 * time.Hour,
+	TouchInterval: time.Minute,
+}
+
+// Service управляет сервисными аккаунтами и ключами API
+type Service struct {
+	repo apikey.Repository
+	cfg  Config
+	now  func() time.Time
+}
+
+// New создает новый экземпляр Service
+func New(repo apikey.Repository, cfg Config) *Service {
+	return &Service{
+		repo: repo,
+		cfg:  cfg,
+		now:  time.Now,
+	}
+}
+
+// CreateAccount создает сервисный аккаунт
+func (s *Service) CreateAccount(ctx context.Context, name, description string, createdBy uuid.UUID) (*apikey.ServiceAccount, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > maxNameLength {
+		return nil, ErrInvalidName
+	}
+
+	account := &apikey.ServiceAccount{
+		ID:          uuid.New(),
+		Name:        name,
+		Description: description,
+		CreatedBy:   createdBy,
+		CreatedAt:   s.now(),
+	}
+	if err := s.repo.CreateAccount(ctx, account); err != nil {
+		return nil, err
+	}
+
+	return account, nil
+}
+
+// ListAccounts возвращает все сервисные аккаунты
+func (s *Service) ListAccounts(ctx context.Context) ([]*apikey.ServiceAccount, error) {
+	return s.repo.ListAccounts(ctx)
+}
+
+// DeleteAccount удаляет сервисный аккаунт. Его ключи перестают действовать сразу.
+func (s *Service) DeleteAccount(ctx context.Context, id uuid.UUID) error {
+	return s.repo.DeleteAccount(ctx, id)
+}
+
+// CreateKey выпускает ключ сервисного аккаунта с областями scopes и возвращает
+// его вместе со значением ключа, которое больше нигде не хранится. Если
+// expiresAt не задан, ключ действует DefaultTTL.
+func (s *Service) CreateKey(ctx context.Context, accountID uuid.UUID, name string, scopes []rbac.Permission, expiresAt *time.Time, createdBy uuid.UUID) (*apikey.Key, string, error) {
+	name = strings.TrimSpace(name)
+	if len(name) > maxNameLength {
+		return nil, "", ErrInvalidName
+	}
+	scopes, err := validateScopes(scopes)
+	if err != nil {
+		return nil, "", err
+	}
+
+	now := s.now()
+	expires := now.Add(s.cfg.DefaultTTL)
+	if expiresAt != nil {
+		if !expiresAt.After(now) || expiresAt.After(now.Add(s.cfg.MaxTTL)) {
+			return nil, "", ErrInvalidExpiry
+		}
+		expires = *expiresAt
+	}
+
+	if _, err := s.repo.GetAccount(ctx, accountID); err != nil {
+		return nil, "", err
+	}
+
+	raw, prefix, err := generateKey()
+	if err != nil {
+		return nil, "", err
+	}
+
+	key := &apikey.Key{
+		ID:               uuid.New(),
+		ServiceAccountID: accountID,
+		Name:             name,
+		Prefix:           prefix,
+		KeyHash:          auth.HashOpaqueToken(raw),
+		Scopes:           scopes,
+		ExpiresAt:        expires,
+		CreatedBy:        createdBy,
+		CreatedAt:        now,
+	}
+	if err := s.repo.CreateKey(ctx, key); err != nil {
+		return nil, "", err
+	}
+
+	return key, raw, nil
+}
+
+// ListKeys возвращает ключи сервисного аккаунта, включая истекшие и отозванные
+func (s *Service) ListKeys(ctx context.Context, accountID uuid.UUID) ([]*apikey.Key, error) {
+	if _, err := s.repo.GetAccount(ctx, accountID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListKeys(ctx, accountID)
+}
+
+// RevokeKey отзывает ключ сервисного аккаунта
+func (s *Service) RevokeKey(ctx context.Context, accountID, keyID uuid.UUID) error {
+	return s.repo.RevokeKey(ctx, accountID, keyID, s.now())
+}
+
+// ValidateAPIKey проверяет ключ и возвращает его сервисный аккаунт и
+// разрешения. Время последнего использования обновляется не чаще
+// TouchInterval, ошибка обновления только пишется в лог.
+func (s *Service) ValidateAPIKey(ctx context.Context, raw string) (*apikey.Principal, error) {
+	if !apikey.IsKey(raw) {
+		return nil, ErrInvalidKey
+	}
+
+	key, err := s.repo.GetKeyByHash(ctx, auth.HashOpaqueToken(raw))
+	if errors.Is(err, apikey.ErrKeyNotFound) {
+		return nil, ErrInvalidKey
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	if !key.Active(now) {
+		return nil, ErrInvalidKey
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= s.cfg.TouchInterval {
+		if err := s.repo.TouchKey(ctx, key.ID, now); err != nil {
+			log.Printf("Failed to update last use of api key %s: %v", key.Prefix, err)
+		}
+	}
+
+	return &apikey.Principal{
+		ServiceAccountID: key.ServiceAccountID,
+		KeyID:            key.ID,
+		Permissions:      rbac.NewSet(key.Scopes...),
+	}, nil
+}
+
+// validateScopes проверяет, что области заданы и есть в каталоге разрешений,
+// и убирает повторы
+func validateScopes(scopes []rbac.Permission) ([]rbac.Permission, error) {
+	if len(scopes) == 0 {
+		return nil, ErrInvalidScopes
+	}
+
+	known := rbac.NewSet(rbac.Catalogue...)
+	seen := make(rbac.Set, len(scopes))
+	result := make([]rbac.Permission, 0, len(scopes))
+	for _, p := range scopes {
+		if !known.Has(p) {
+			return nil, ErrInvalidScopes
+		}
+		if seen.Has(p) {
+			continue
+		}
+		seen[p] = struct{}{}
+		result = append(result, p)
+	}
+
+	return result, nil
+}
+
+// generateKey генерирует ключ вида pvz_<8 hex>_<секрет> и возвращает его вместе с префиксом
+func generateKey() (string, string, error) {
+	id := make([]byte, 4)
+	if _, err := rand.Read(id); err != nil {
+		return "", "", err
+	}
+	secret, err := auth.NewOpaqueToken()
+	if err != nil {
+		return "", "", err
+	}
+
+	prefix := apikey.KeyPrefix + hex.EncodeToString(id)
+	return prefix + "_" + secret, prefix, nil
+}
@@ -0,0 +1,272 @@
+package apikey
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/apikey"
+	"github.com/avito/pvz/internal/domain/rbac"
+	"github.com/avito/pvz/pkg/auth"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryRepo хранит сервисные аккаунты и ключи в памяти
+type memoryRepo struct {
+	accounts map[uuid.UUID]*apikey.ServiceAccount
+	keys     map[uuid.UUID]*apikey.Key
+	touches  int
+}
+
+func newMemoryRepo() *memoryRepo {
+	return &memoryRepo{
+		accounts: make(map[uuid.UUID]*apikey.ServiceAccount),
+		keys:     make(map[uuid.UUID]*apikey.Key),
+	}
+}
+
+func (r *memoryRepo) CreateAccount(_ context.Context, a *apikey.ServiceAccount) error {
+	r.accounts[a.ID] = a
+	return nil
+}
+
+func (r *memoryRepo) GetAccount(_ context.Context, id uuid.UUID) (*apikey.ServiceAccount, error) {
+	a, ok := r.accounts[id]
+	if !ok {
+		return nil, apikey.ErrAccountNotFound
+	}
+	return a, nil
+}
+
+func (r *memoryRepo) ListAccounts(context.Context) ([]*apikey.ServiceAccount, error) {
+	result := make([]*apikey.ServiceAccount, 0, len(r.accounts))
+	for _, a := range r.accounts {
+		result = append(result, a)
+	}
+	return result, nil
+}
+
+func (r *memoryRepo) DeleteAccount(_ context.Context, id uuid.UUID) error {
+	if _, ok := r.accounts[id]; !ok {
+		return apikey.ErrAccountNotFound
+	}
+	delete(r.accounts, id)
+	for keyID, k := range r.keys {
+		if k.ServiceAccountID == id {
+			delete(r.keys, keyID)
+		}
+	}
+	return nil
+}
+
+func (r *memoryRepo) CreateKey(_ context.Context, k *apikey.Key) error {
+	r.keys[k.ID] = k
+	return nil
+}
+
+func (r *memoryRepo) GetKeyByHash(_ context.Context, hash string) (*apikey.Key, error) {
+	for _, k := range r.keys {
+		if k.KeyHash == hash {
+			return k, nil
+		}
+	}
+	return nil, apikey.ErrKeyNotFound
+}
+
+func (r *memoryRepo) ListKeys(_ context.Context, accountID uuid.UUID) ([]*apikey.Key, error) {
+	var result []*apikey.Key
+	for _, k := range r.keys {
+		if k.ServiceAccountID == accountID {
+			result = append(result, k)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].CreatedAt.After(result[j].CreatedAt) })
+	return result, nil
+}
+
+func (r *memoryRepo) RevokeKey(_ context.Context, accountID, keyID uuid.UUID, at time.Time) error {
+	k, ok := r.keys[keyID]
+	if !ok || k.ServiceAccountID != accountID || k.RevokedAt != nil {
+		return apikey.ErrKeyNotFound
+	}
+	k.RevokedAt = &at
+	return nil
+}
+
+func (r *memoryRepo) TouchKey(_ context.Context, id uuid.UUID, at time.Time) error {
+	r.touches++
+	r.keys[id].LastUsedAt = &at
+	return nil
+}
+
+type testService struct {
+	*Service
+	repo    *memoryRepo
+	account *apikey.ServiceAccount
+	now     time.Time
+}
+
+func newTestService(t *testing.T) *testService {
+	ts := &testService{
+		repo: newMemoryRepo(),
+		now:  time.Date(2026, time.October, 1, 12, 0, 0, 0, time.UTC),
+	}
+	ts.Service = New(ts.repo, DefaultConfig)
+	ts.Service.now = func() time.Time { return ts.now }
+
+	account, err := ts.CreateAccount(context.Background(), " warehouse ", "Складская интеграция", uuid.New())
+	require.NoError(t, err)
+	ts.account = account
+	return ts
+}
+
+func TestService_CreateAccount(t *testing.T) {
+	ts := newTestService(t)
+
+	assert.Equal(t, "warehouse", ts.account.Name)
+	assert.Equal(t, ts.now, ts.account.CreatedAt)
+
+	_, err := ts.CreateAccount(context.Background(), "  ", "", uuid.New())
+	assert.ErrorIs(t, err, ErrInvalidName)
+}
+
+func TestService_CreateKey(t *testing.T) {
+	ts := newTestService(t)
+	ctx := context.Background()
+	scopes := []rbac.Permission{rbac.ReceptionCreate, rbac.ProductCreate, rbac.ReceptionCreate}
+
+	key, raw, err := ts.CreateKey(ctx, ts.account.ID, "prod", scopes, nil, uuid.New())
+	require.NoError(t, err)
+
+	assert.True(t, apikey.IsKey(raw))
+	assert.Equal(t, apikey.Prefix(raw), key.Prefix)
+	assert.Equal(t, auth.HashOpaqueToken(raw), key.KeyHash)
+	assert.Equal(t, []rbac.Permission{rbac.ReceptionCreate, rbac.ProductCreate}, key.Scopes)
+	assert.Equal(t, ts.now.Add(DefaultConfig.DefaultTTL), key.ExpiresAt)
+
+	keys, err := ts.ListKeys(ctx, ts.account.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []*apikey.Key{key}, keys)
+}
+
+func TestService_CreateKeyInvalid(t *testing.T) {
+	ctx := context.Background()
+	past := time.Date(2026, time.September, 1, 0, 0, 0, 0, time.UTC)
+	tooFar := time.Date(2028, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		accountID func(ts *testService) uuid.UUID
+		scopes    []rbac.Permission
+		expiresAt *time.Time
+		wantErr   error
+	}{
+		{
+			name:    "без областей",
+			wantErr: ErrInvalidScopes,
+		},
+		{
+			name:    "неизвестная область",
+			scopes:  []rbac.Permission{"pvz:destroy"},
+			wantErr: ErrInvalidScopes,
+		},
+		{
+			name:      "время истечения в прошлом",
+			scopes:    []rbac.Permission{rbac.ProductCreate},
+			expiresAt: &past,
+			wantErr:   ErrInvalidExpiry,
+		},
+		{
+			name:      "срок больше максимального",
+			scopes:    []rbac.Permission{rbac.ProductCreate},
+			expiresAt: &tooFar,
+			wantErr:   ErrInvalidExpiry,
+		},
+		{
+			name:      "аккаунт не найден",
+			accountID: func(*testService) uuid.UUID { return uuid.New() },
+			scopes:    []rbac.Permission{rbac.ProductCreate},
+			wantErr:   apikey.ErrAccountNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := newTestService(t)
+			accountID := ts.account.ID
+			if tt.accountID != nil {
+				accountID = tt.accountID(ts)
+			}
+
+			_, _, err := ts.CreateKey(ctx, accountID, "", tt.scopes, tt.expiresAt, uuid.New())
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Empty(t, ts.repo.keys)
+		})
+	}
+}
+
+func TestService_ValidateAPIKey(t *testing.T) {
+	ts := newTestService(t)
+	ctx := context.Background()
+	expiresAt := ts.now.Add(time.Hour)
+
+	key, raw, err := ts.CreateKey(ctx, ts.account.ID, "prod", []rbac.Permission{rbac.ProductCreate}, &expiresAt, uuid.New())
+	require.NoError(t, err)
+
+	principal, err := ts.ValidateAPIKey(ctx, raw)
+	require.NoError(t, err)
+	assert.Equal(t, ts.account.ID, principal.ServiceAccountID)
+	assert.Equal(t, key.ID, principal.KeyID)
+	assert.Equal(t, rbac.NewSet(rbac.ProductCreate), principal.Permissions)
+	assert.Equal(t, ts.now, *key.LastUsedAt)
+
+	// Время использования обновляется не чаще TouchInterval
+	ts.now = ts.now.Add(30 * time.Second)
+	_, err = ts.ValidateAPIKey(ctx, raw)
+	require.NoError(t, err)
+	assert.Equal(t, 1, ts.repo.touches)
+
+	ts.now = ts.now.Add(30 * time.Second)
+	_, err = ts.ValidateAPIKey(ctx, raw)
+	require.NoError(t, err)
+	assert.Equal(t, 2, ts.repo.touches)
+
+	t.Run("неизвестный ключ", func(t *testing.T) {
+		_, err := ts.ValidateAPIKey(ctx, key.Prefix+"_unknown")
+		assert.ErrorIs(t, err, ErrInvalidKey)
+	})
+
+	t.Run("ключ истек", func(t *testing.T) {
+		ts.now = expiresAt
+		defer func() { ts.now = expiresAt.Add(-time.Minute) }()
+
+		_, err := ts.ValidateAPIKey(ctx, raw)
+		assert.ErrorIs(t, err, ErrInvalidKey)
+	})
+
+	t.Run("ключ отозван", func(t *testing.T) {
+		require.NoError(t, ts.RevokeKey(ctx, ts.account.ID, key.ID))
+
+		_, err := ts.ValidateAPIKey(ctx, raw)
+		assert.ErrorIs(t, err, ErrInvalidKey)
+		assert.ErrorIs(t, ts.RevokeKey(ctx, ts.account.ID, key.ID), apikey.ErrKeyNotFound)
+	})
+}
+
+func TestService_DeleteAccount(t *testing.T) {
+	ts := newTestService(t)
+	ctx := context.Background()
+
+	_, raw, err := ts.CreateKey(ctx, ts.account.ID, "", []rbac.Permission{rbac.ProductCreate}, nil, uuid.New())
+	require.NoError(t, err)
+
+	require.NoError(t, ts.DeleteAccount(ctx, ts.account.ID))
+
+	_, err = ts.ValidateAPIKey(ctx, raw)
+	assert.ErrorIs(t, err, ErrInvalidKey)
+	_, err = ts.ListKeys(ctx, ts.account.ID)
+	assert.ErrorIs(t, err, apikey.ErrAccountNotFound)
+}
@@ -0,0 +1,114 @@
+// Package assignment управляет закреплением сотрудников за ПВЗ и проверяет,
+// что сотрудник работает только с приемками и товарами своих ПВЗ. Закрепления
+// проверяются по базе при каждом изменении, а не хранятся в токене доступа:
+// новое, истекшее или удаленное закрепление действует сразу, без перевыпуска токена.
+package assignment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/assignment"
+	"github.com/avito/pvz/internal/domain/pvz"
+	"github.com/avito/pvz/internal/domain/rbac"
+	"github.com/avito/pvz/internal/domain/user"
+	"github.com/avito/pvz/pkg/auth"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidPeriod = errors.New("invalid assignment period")
+	ErrUnauthorized  = errors.New("caller is not authenticated")
+)
+
+// Service управляет закреплениями и реализует assignment.Access
+type Service struct {
+	repo  assignment.Repository
+	users user.Repository
+	pvzs  pvz.Repository
+	now   func() time.Time
+}
+
+// New создает новый экземпляр Service
+func New(repo assignment.Repository, users user.Repository, pvzs pvz.Repository) *Service {
+	return &Service{
+		repo:  repo,
+		users: users,
+		pvzs:  pvzs,
+		now:   time.Now,
+	}
+}
+
+// Create закрепляет пользователя за ПВЗ на период [validFrom, validTo).
+// Нулевой validFrom означает начало действия сейчас, nil validTo — бессрочное закрепление.
+func (s *Service) Create(ctx context.Context, userID, pvzID uuid.UUID, validFrom time.Time, validTo *time.Time, createdBy uuid.UUID) (*assignment.Assignment, error) {
+	now := s.now()
+	if validFrom.IsZero() {
+		validFrom = now
+	}
+	if validTo != nil && !validTo.After(validFrom) {
+		return nil, ErrInvalidPeriod
+	}
+
+	if _, err := s.users.GetByID(ctx, userID); err != nil {
+		return nil, err
+	}
+	if _, err := s.pvzs.GetByID(ctx, pvzID); err != nil {
+		return nil, err
+	}
+
+	a := &assignment.Assignment{
+		ID:        uuid.New(),
+		UserID:    userID,
+		PVZID:     pvzID,
+		ValidFrom: validFrom,
+		ValidTo:   validTo,
+		CreatedBy: createdBy,
+		CreatedAt: now,
+	}
+	if err := s.repo.Create(ctx, a); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// Delete удаляет закрепление
+func (s *Service) Delete(ctx context.Context, id uuid.UUID) error {
+	return s.repo.Delete(ctx, id)
+}
+
+// List возвращает закрепления по фильтру, новые первыми
+func (s *Service) List(ctx context.Context, filter assignment.Filter) ([]*assignment.Assignment, error) {
+	return s.repo.List(ctx, filter)
+}
+
+// ActivePVZs возвращает ПВЗ, за которыми пользователь закреплен сейчас
+func (s *Service) ActivePVZs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	return s.repo.ActivePVZIDs(ctx, userID, s.now())
+}
+
+// CheckPVZ проверяет, что вызывающий из контекста закреплен за ПВЗ.
+// Роли с разрешением rbac.PVZAccessAll работают с любым ПВЗ.
+func (s *Service) CheckPVZ(ctx context.Context, pvzID uuid.UUID) error {
+	if rbac.FromContext(ctx).Has(rbac.PVZAccessAll) {
+		return nil
+	}
+
+	userID, ok := auth.GetUserID(ctx)
+	if !ok {
+		return ErrUnauthorized
+	}
+
+	assigned, err := s.repo.IsAssigned(ctx, userID, pvzID, s.now())
+	if err != nil {
+		return err
+	}
+	if !assigned {
+		return fmt.Errorf("%w: %s", assignment.ErrNotAssigned, pvzID)
+	}
+
+	return nil
+}
@@ -0,0 +1,241 @@
+package assignment
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/assignment"
+	"github.com/avito/pvz/internal/domain/pvz"
+	"github.com/avito/pvz/internal/domain/rbac"
+	"github.com/avito/pvz/internal/domain/user"
+	"github.com/avito/pvz/pkg/auth"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockRepository мок для assignment.Repository
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) Create(ctx context.Context, a *assignment.Assignment) error {
+	args := m.Called(ctx, a)
+	return args.Error(0)
+}
+
+func (m *MockRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockRepository) List(ctx context.Context, filter assignment.Filter) ([]*assignment.Assignment, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*assignment.Assignment), args.Error(1)
+}
+
+func (m *MockRepository) ActivePVZIDs(ctx context.Context, userID uuid.UUID, at time.Time) ([]uuid.UUID, error) {
+	args := m.Called(ctx, userID, at)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+func (m *MockRepository) IsAssigned(ctx context.Context, userID, pvzID uuid.UUID, at time.Time) (bool, error) {
+	args := m.Called(ctx, userID, pvzID, at)
+	return args.Bool(0), args.Error(1)
+}
+
+// MockUserRepository мок для user.Repository
+type MockUserRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserRepository) Create(ctx context.Context, u *user.User) error {
+	args := m.Called(ctx, u)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) GetByID(ctx context.Context, id uuid.UUID) (*user.User, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetByEmail(ctx context.Context, email string) (*user.User, error) {
+	args := m.Called(ctx, email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.User), args.Error(1)
+}
+
+func (m *MockUserRepository) Update(ctx context.Context, u *user.User) error {
+	args := m.Called(ctx, u)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) List(ctx context.Context, offset, limit int) ([]*user.User, error) {
+	args := m.Called(ctx, offset, limit)
+	return args.Get(0).([]*user.User), args.Error(1)
+}
+
+// MockPVZRepository мок для pvz.Repository
+type MockPVZRepository struct {
+	mock.Mock
+}
+
+func (m *MockPVZRepository) Create(ctx context.Context, p *pvz.PVZ) error {
+	args := m.Called(ctx, p)
+	return args.Error(0)
+}
+
+func (m *MockPVZRepository) GetByID(ctx context.Context, id uuid.UUID) (*pvz.PVZ, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pvz.PVZ), args.Error(1)
+}
+
+func (m *MockPVZRepository) GetByCity(ctx context.Context, city string) (*pvz.PVZ, error) {
+	args := m.Called(ctx, city)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pvz.PVZ), args.Error(1)
+}
+
+func (m *MockPVZRepository) Update(ctx context.Context, p *pvz.PVZ) error {
+	args := m.Called(ctx, p)
+	return args.Error(0)
+}
+
+func (m *MockPVZRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockPVZRepository) List(ctx context.Context, offset, limit int) ([]*pvz.PVZ, error) {
+	args := m.Called(ctx, offset, limit)
+	return args.Get(0).([]*pvz.PVZ), args.Error(1)
+}
+
+func (m *MockPVZRepository) ListByFilter(ctx context.Context, filter pvz.ListFilter) ([]*pvz.PVZ, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]*pvz.PVZ), args.Error(1)
+}
+
+func (m *MockPVZRepository) GetWithReceptions(ctx context.Context, startDate, endDate time.Time, page, limit int) ([]*pvz.PVZWithReceptions, error) {
+	args := m.Called(ctx, startDate, endDate, page, limit)
+	return args.Get(0).([]*pvz.PVZWithReceptions), args.Error(1)
+}
+
+func (m *MockPVZRepository) GetWithReceptionsByCursor(ctx context.Context, startDate, endDate time.Time, cursor *pvz.Cursor, limit int) ([]*pvz.PVZWithReceptions, *pvz.Cursor, error) {
+	args := m.Called(ctx, startDate, endDate, cursor, limit)
+	return args.Get(0).([]*pvz.PVZWithReceptions), args.Get(1).(*pvz.Cursor), args.Error(2)
+}
+
+func (m *MockPVZRepository) GetAll(ctx context.Context) ([]*pvz.PVZ, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*pvz.PVZ), args.Error(1)
+}
+
+// newTestService создает сервис с моками и фиксированным временем
+func newTestService(now time.Time) (*Service, *MockRepository, *MockUserRepository, *MockPVZRepository) {
+	repo := new(MockRepository)
+	users := new(MockUserRepository)
+	pvzs := new(MockPVZRepository)
+	s := New(repo, users, pvzs)
+	s.now = func() time.Time { return now }
+	return s, repo, users, pvzs
+}
+
+func TestService_Create(t *testing.T) {
+	now := time.Date(2026, time.October, 1, 12, 0, 0, 0, time.UTC)
+	moderatorID, userID, pvzID := uuid.New(), uuid.New(), uuid.New()
+	ctx := context.Background()
+
+	t.Run("успешное закрепление с началом сейчас", func(t *testing.T) {
+		s, repo, users, pvzs := newTestService(now)
+		validTo := now.Add(24 * time.Hour)
+
+		users.On("GetByID", ctx, userID).Return(&user.User{ID: userID}, nil)
+		pvzs.On("GetByID", ctx, pvzID).Return(&pvz.PVZ{ID: pvzID}, nil)
+		repo.On("Create", ctx, mock.MatchedBy(func(a *assignment.Assignment) bool {
+			return a.UserID == userID && a.PVZID == pvzID && a.ValidFrom.Equal(now) &&
+				a.ValidTo == &validTo && a.CreatedBy == moderatorID
+		})).Return(nil)
+
+		a, err := s.Create(ctx, userID, pvzID, time.Time{}, &validTo, moderatorID)
+		require.NoError(t, err)
+		assert.Equal(t, now, a.CreatedAt)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("окончание не позже начала", func(t *testing.T) {
+		s, repo, _, _ := newTestService(now)
+
+		_, err := s.Create(ctx, userID, pvzID, now, &now, moderatorID)
+		assert.ErrorIs(t, err, ErrInvalidPeriod)
+		repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("ПВЗ не найден", func(t *testing.T) {
+		s, repo, users, pvzs := newTestService(now)
+
+		users.On("GetByID", ctx, userID).Return(&user.User{ID: userID}, nil)
+		pvzs.On("GetByID", ctx, pvzID).Return(nil, pvz.ErrNotFound)
+
+		_, err := s.Create(ctx, userID, pvzID, time.Time{}, nil, moderatorID)
+		assert.ErrorIs(t, err, pvz.ErrNotFound)
+		repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestService_CheckPVZ(t *testing.T) {
+	now := time.Date(2026, time.October, 1, 12, 0, 0, 0, time.UTC)
+	userID, pvzID := uuid.New(), uuid.New()
+	employeeCtx := auth.WithUserID(context.Background(), userID)
+
+	t.Run("сотрудник закреплен за ПВЗ", func(t *testing.T) {
+		s, repo, _, _ := newTestService(now)
+		repo.On("IsAssigned", employeeCtx, userID, pvzID, now).Return(true, nil)
+
+		assert.NoError(t, s.CheckPVZ(employeeCtx, pvzID))
+	})
+
+	t.Run("сотрудник не закреплен за ПВЗ", func(t *testing.T) {
+		s, repo, _, _ := newTestService(now)
+		repo.On("IsAssigned", employeeCtx, userID, pvzID, now).Return(false, nil)
+
+		assert.ErrorIs(t, s.CheckPVZ(employeeCtx, pvzID), assignment.ErrNotAssigned)
+	})
+
+	t.Run("доступ ко всем ПВЗ без проверки закрепления", func(t *testing.T) {
+		s, repo, _, _ := newTestService(now)
+		ctx := rbac.WithPermissions(employeeCtx, rbac.NewSet(rbac.PVZAccessAll))
+
+		assert.NoError(t, s.CheckPVZ(ctx, pvzID))
+		repo.AssertNotCalled(t, "IsAssigned", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("без вызывающего", func(t *testing.T) {
+		s, _, _, _ := newTestService(now)
+
+		assert.ErrorIs(t, s.CheckPVZ(context.Background(), pvzID), ErrUnauthorized)
+	})
+}
@@ -0,0 +1,27 @@
+// Package audit выдает записи журнала аудита модераторам. Сами записи
+// сохраняют сервисы ПВЗ, приемок, товаров и пользователей в своих транзакциях.
+package audit
+
+import (
+	"context"
+
+	"github.com/avito/pvz/internal/domain/audit"
+)
+
+// Service выдает записи журнала аудита
+type Service struct {
+	repo audit.Repository
+}
+
+// New создает новый экземпляр Service
+func New(repo audit.Repository) *Service {
+	return &Service{repo: repo}
+}
+
+// List возвращает записи журнала по фильтру, новые первыми
+func (s *Service) List(ctx context.Context, filter audit.Filter) ([]*audit.Entry, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	return s.repo.List(ctx, filter)
+}
@@ -0,0 +1,56 @@
+package audit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/avito/pvz/internal/domain/audit"
+	"github.com/avito/pvz/internal/domain/listing"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockRepository мок для audit.Repository
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) Record(ctx context.Context, entries ...*audit.Entry) error {
+	args := m.Called(ctx, entries)
+	return args.Error(0)
+}
+
+func (m *MockRepository) List(ctx context.Context, filter audit.Filter) ([]*audit.Entry, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*audit.Entry), args.Error(1)
+}
+
+func TestService_List(t *testing.T) {
+	ctx := context.Background()
+	page := listing.Page{Limit: listing.DefaultLimit}
+
+	t.Run("успешная выборка", func(t *testing.T) {
+		repo := new(MockRepository)
+		filter := audit.Filter{EntityType: audit.EntityPVZ, Action: audit.ActionUpdate, Page: page}
+		entries := []*audit.Entry{{ID: uuid.New(), EntityType: audit.EntityPVZ, Action: audit.ActionUpdate}}
+		repo.On("List", ctx, filter).Return(entries, nil)
+
+		result, err := New(repo).List(ctx, filter)
+		require.NoError(t, err)
+		assert.Equal(t, entries, result)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("неверный фильтр", func(t *testing.T) {
+		repo := new(MockRepository)
+
+		_, err := New(repo).List(ctx, audit.Filter{Action: "purge", Page: page})
+		assert.ErrorIs(t, err, listing.ErrInvalidFilter)
+		repo.AssertNotCalled(t, "List", mock.Anything, mock.Anything)
+	})
+}
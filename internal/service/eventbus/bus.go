@@ -0,0 +1,141 @@
+// Package eventbus передает типизированные события предметной области
+// подписчикам внутри процесса. Синхронные подписчики выполняются в вызове
+// Raise, асинхронные - в собственной горутине, получая события в порядке
+// их появления. Сервисы вызывают Raise только после фиксации транзакции.
+package eventbus
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"github.com/avito/pvz/internal/domain/event"
+)
+
+// DefaultBufferSize размер очереди асинхронного подписчика по умолчанию
+const DefaultBufferSize = 256
+
+// delivery событие в очереди асинхронного подписчика
+type delivery struct {
+	ctx   context.Context
+	event event.Domain
+}
+
+// subscription подписка на события одного типа
+type subscription struct {
+	accepts func(e event.Domain) bool
+	handle  func(ctx context.Context, e event.Domain)
+	// queue очередь асинхронного подписчика, nil для синхронного
+	queue chan delivery
+}
+
+// Bus шина событий, реализует интерфейс event.Bus
+type Bus struct {
+	mu         sync.RWMutex
+	subs       []*subscription
+	bufferSize int
+	closed     bool
+	wg         sync.WaitGroup
+}
+
+// New создает новый экземпляр Bus. bufferSize задает размер очереди
+// каждого асинхронного подписчика: когда очередь заполнена, Raise ждет.
+func New(bufferSize int) *Bus {
+	return &Bus{bufferSize: bufferSize}
+}
+
+// Subscribe подписывает h на события типа E. Обработчик выполняется
+// в вызове Raise, поэтому должен быть быстрым.
+func Subscribe[E event.Domain](b *Bus, h func(ctx context.Context, e E)) {
+	b.add(newSubscription(h), false)
+}
+
+// SubscribeAsync подписывает h на события типа E. Обработчик выполняется
+// в отдельной горутине с контекстом, который не отменяется вместе с запросом.
+func SubscribeAsync[E event.Domain](b *Bus, h func(ctx context.Context, e E)) {
+	b.add(newSubscription(h), true)
+}
+
+func newSubscription[E event.Domain](h func(ctx context.Context, e E)) *subscription {
+	return &subscription{
+		accepts: func(e event.Domain) bool {
+			_, ok := e.(E)
+			return ok
+		},
+		handle: func(ctx context.Context, e event.Domain) {
+			h(ctx, e.(E))
+		},
+	}
+}
+
+func (b *Bus) add(s *subscription, async bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return
+	}
+	if async {
+		s.queue = make(chan delivery, b.bufferSize)
+		b.wg.Add(1)
+		go func() {
+			defer b.wg.Done()
+			for d := range s.queue {
+				s.call(d.ctx, d.event)
+			}
+		}()
+	}
+	b.subs = append(b.subs, s)
+}
+
+// Raise передает события подписчикам по порядку
+func (b *Bus) Raise(ctx context.Context, events ...event.Domain) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, e := range events {
+		for _, s := range b.subs {
+			if !s.accepts(e) {
+				continue
+			}
+			if s.queue == nil {
+				s.call(ctx, e)
+				continue
+			}
+			if !b.closed {
+				s.queue <- delivery{ctx: context.WithoutCancel(ctx), event: e}
+			}
+		}
+	}
+}
+
+// Close дожидается обработки событий асинхронными подписчиками.
+// События, сообщенные после Close, получают только синхронные подписчики.
+func (b *Bus) Close() error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	for _, s := range b.subs {
+		if s.queue != nil {
+			close(s.queue)
+		}
+	}
+	b.mu.Unlock()
+
+	b.wg.Wait()
+	return nil
+}
+
+// call выполняет обработчик. Паника подписчика не должна прерывать
+// сервис, который уже зафиксировал изменения.
+func (s *subscription) call(ctx context.Context, e event.Domain) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("event subscriber panicked on %s: %v", e.EventType(), r)
+		}
+	}()
+	s.handle(ctx, e)
+}
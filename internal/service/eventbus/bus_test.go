@@ -0,0 +1,78 @@
+package eventbus
+
+import (
+	"context"
+	"testing"
+
+	"github.com/avito/pvz/internal/domain/event"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBus_Subscribe(t *testing.T) {
+	bus := New(DefaultBufferSize)
+
+	var opened []event.ReceptionOpened
+	var feed []event.Type
+	Subscribe(bus, func(_ context.Context, e event.ReceptionOpened) {
+		opened = append(opened, e)
+	})
+	Subscribe(bus, func(_ context.Context, e event.Feed) {
+		feed = append(feed, e.EventType())
+	})
+
+	receptionID := uuid.New()
+	bus.Raise(context.Background(),
+		event.PVZCreated{PVZID: uuid.New()},
+		event.ReceptionOpened{ReceptionID: receptionID},
+		event.ProductRemoved{ReceptionID: receptionID},
+	)
+
+	// Подписчик получает только события своего типа
+	assert.Equal(t, []event.ReceptionOpened{{ReceptionID: receptionID}}, opened)
+	// Подписка на интерфейс получает все реализующие его события
+	assert.Equal(t, []event.Type{event.TypeReceptionOpened, event.TypeProductRemoved}, feed)
+}
+
+func TestBus_SubscribeAsync(t *testing.T) {
+	bus := New(1)
+
+	var added []uuid.UUID
+	SubscribeAsync(bus, func(_ context.Context, e event.ProductAdded) {
+		added = append(added, e.ProductID)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var expected []uuid.UUID
+	for i := 0; i < 10; i++ {
+		id := uuid.New()
+		expected = append(expected, id)
+		bus.Raise(ctx, event.ProductAdded{ProductID: id})
+	}
+	// Отмена запроса не прерывает обработку уже переданных событий
+	cancel()
+
+	assert.NoError(t, bus.Close())
+	assert.Equal(t, expected, added)
+
+	// После закрытия асинхронные подписчики событий не получают
+	bus.Raise(context.Background(), event.ProductAdded{ProductID: uuid.New()})
+	assert.Len(t, added, 10)
+}
+
+func TestBus_SubscriberPanic(t *testing.T) {
+	bus := New(DefaultBufferSize)
+
+	called := false
+	Subscribe(bus, func(context.Context, event.PVZDeleted) {
+		panic("subscriber failed")
+	})
+	Subscribe(bus, func(context.Context, event.PVZDeleted) {
+		called = true
+	})
+
+	assert.NotPanics(t, func() {
+		bus.Raise(context.Background(), event.PVZDeleted{PVZID: uuid.New()})
+	})
+	assert.True(t, called)
+}
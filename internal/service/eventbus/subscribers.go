@@ -0,0 +1,48 @@
+package eventbus
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/audit"
+	"github.com/avito/pvz/internal/domain/event"
+	"github.com/avito/pvz/internal/metrics"
+)
+
+// SubscribeMetrics обновляет бизнес-метрики по событиям
+func SubscribeMetrics(b *Bus) {
+	Subscribe(b, func(context.Context, event.PVZCreated) {
+		metrics.PVZCreatedTotal.Inc()
+	})
+	Subscribe(b, func(context.Context, event.ReceptionOpened) {
+		metrics.ReceptionCreatedTotal.Inc()
+	})
+	Subscribe(b, func(context.Context, event.ProductAdded) {
+		metrics.ProductCreatedTotal.Inc()
+	})
+}
+
+// SubscribeAudit записывает блокировки входа в журнал аудита. Изменения
+// данных сервисы записывают в журнал сами в транзакции изменения.
+func SubscribeAudit(b *Bus, auditLog audit.AuditLog) {
+	SubscribeAsync(b, func(ctx context.Context, e event.LoginLocked) {
+		log.Printf("Login locked for %s until %s after %d failures", e.Key, e.LockedUntil.Format(time.RFC3339), e.Failures)
+		if err := auditLog.LogLoginLockout(ctx, e.Key, e.UserID, e.LockedUntil); err != nil {
+			log.Printf("failed to log login lockout %s: %v", e.Key, err)
+		}
+	})
+	SubscribeAsync(b, func(ctx context.Context, e event.LoginUnlocked) {
+		if err := auditLog.LogLoginUnlock(ctx, e.Key, e.UserID, e.UnlockedBy); err != nil {
+			log.Printf("failed to log login unlock %s: %v", e.Key, err)
+		}
+	})
+}
+
+// SubscribeNotifications передает события приемок и товаров в publisher:
+// ленту событий и вебхуки
+func SubscribeNotifications(b *Bus, publisher event.Publisher) {
+	SubscribeAsync(b, func(ctx context.Context, e event.Feed) {
+		publisher.Publish(ctx, e.Event())
+	})
+}
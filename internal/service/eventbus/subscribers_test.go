@@ -0,0 +1,111 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/event"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockAuditLog мок журнала аудита
+type MockAuditLog struct {
+	mock.Mock
+}
+
+func (m *MockAuditLog) LogLoginLockout(ctx context.Context, key string, userID uuid.UUID, lockedUntil time.Time) error {
+	args := m.Called(ctx, key, userID, lockedUntil)
+	return args.Error(0)
+}
+
+func (m *MockAuditLog) LogLoginUnlock(ctx context.Context, key string, userID, unlockedBy uuid.UUID) error {
+	args := m.Called(ctx, key, userID, unlockedBy)
+	return args.Error(0)
+}
+
+// recordingPublisher запоминает опубликованные события
+type recordingPublisher struct {
+	events []event.Event
+}
+
+func (p *recordingPublisher) Publish(_ context.Context, e event.Event) {
+	p.events = append(p.events, e)
+}
+
+func TestSubscribeAudit(t *testing.T) {
+	userID := uuid.New()
+	until := time.Date(2026, time.October, 1, 12, 15, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		auditErr error
+	}{
+		{name: "блокировка записана в журнал"},
+		{name: "ошибка журнала не влияет на шину", auditErr: errors.New("db error")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auditLog := new(MockAuditLog)
+			auditLog.On("LogLoginLockout", mock.Anything, "ip:10.0.0.1", userID, until).Return(tt.auditErr).Once()
+
+			bus := New(DefaultBufferSize)
+			SubscribeAudit(bus, auditLog)
+
+			// События ПВЗ записывает в журнал сам сервис, подписка их пропускает
+			bus.Raise(context.Background(),
+				event.PVZCreated{PVZID: uuid.New(), CreatedBy: userID},
+				event.LoginLocked{Key: "ip:10.0.0.1", UserID: userID, Failures: 20, LockedUntil: until},
+			)
+			require.NoError(t, bus.Close())
+
+			auditLog.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSubscribeAudit_Login(t *testing.T) {
+	userID, moderatorID := uuid.New(), uuid.New()
+	until := time.Date(2026, time.October, 1, 12, 15, 0, 0, time.UTC)
+
+	auditLog := new(MockAuditLog)
+	auditLog.On("LogLoginLockout", mock.Anything, "account:user@example.com", userID, until).Return(nil).Once()
+	auditLog.On("LogLoginUnlock", mock.Anything, "account:user@example.com", userID, moderatorID).Return(nil).Once()
+
+	bus := New(DefaultBufferSize)
+	SubscribeAudit(bus, auditLog)
+
+	bus.Raise(context.Background(),
+		event.LoginLocked{Key: "account:user@example.com", UserID: userID, Failures: 5, LockedUntil: until},
+		event.LoginUnlocked{Key: "account:user@example.com", UserID: userID, UnlockedBy: moderatorID},
+	)
+	require.NoError(t, bus.Close())
+
+	auditLog.AssertExpectations(t)
+}
+
+func TestSubscribeNotifications(t *testing.T) {
+	publisher := new(recordingPublisher)
+	bus := New(DefaultBufferSize)
+	SubscribeNotifications(bus, publisher)
+
+	pvzID, receptionID, productID := uuid.New(), uuid.New(), uuid.New()
+	bus.Raise(context.Background(),
+		event.PVZCreated{PVZID: pvzID},
+		event.ReceptionOpened{PVZID: pvzID, ReceptionID: receptionID},
+		event.ProductAdded{PVZID: pvzID, ReceptionID: receptionID, ProductID: productID},
+	)
+	require.NoError(t, bus.Close())
+
+	// События ПВЗ в ленту не попадают
+	require.Len(t, publisher.events, 2)
+	assert.Equal(t, event.TypeReceptionOpened, publisher.events[0].Type)
+	assert.Equal(t, event.TypeProductAdded, publisher.events[1].Type)
+	require.NotNil(t, publisher.events[1].ProductID)
+	assert.Equal(t, productID, *publisher.events[1].ProductID)
+}
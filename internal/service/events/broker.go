@@ -0,0 +1,106 @@
+// Package events раздает события предметной области подписчикам
+// живой ленты и хранит последние события для возобновления подписки.
+package events
+
+import (
+	"context"
+	"sync"
+
+	"github.com/avito/pvz/internal/domain/event"
+)
+
+const (
+	// DefaultBufferSize число последних событий, доступных для возобновления
+	DefaultBufferSize = 1024
+	// subscriberQueueSize число событий, которые подписчик может не успеть прочитать
+	subscriberQueueSize = 64
+)
+
+// Record событие с порядковым номером в ленте
+type Record struct {
+	ID    uint64
+	Event event.Event
+}
+
+// Broker рассылает события подписчикам и хранит последние события в кольцевом буфере.
+// Номера событий начинаются с 1 и растут на время жизни процесса.
+type Broker struct {
+	mu     sync.Mutex
+	buffer []Record
+	start  int
+	lastID uint64
+	subs   map[*subscription]struct{}
+}
+
+type subscription struct {
+	ch chan Record
+}
+
+// NewBroker создает брокер, хранящий до bufferSize последних событий
+func NewBroker(bufferSize int) *Broker {
+	if bufferSize <= 0 {
+		bufferSize = DefaultBufferSize
+	}
+	return &Broker{
+		buffer: make([]Record, 0, bufferSize),
+		subs:   make(map[*subscription]struct{}),
+	}
+}
+
+// Publish сохраняет событие в буфере и отправляет его подписчикам.
+// Подписчик, который не успевает читать события, отключается: он может
+// переподключиться и дочитать пропущенное из буфера по Last-Event-ID.
+func (b *Broker) Publish(_ context.Context, e event.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastID++
+	rec := Record{ID: b.lastID, Event: e}
+	if len(b.buffer) < cap(b.buffer) {
+		b.buffer = append(b.buffer, rec)
+	} else {
+		b.buffer[b.start] = rec
+		b.start = (b.start + 1) % len(b.buffer)
+	}
+
+	for sub := range b.subs {
+		select {
+		case sub.ch <- rec:
+		default:
+			delete(b.subs, sub)
+			close(sub.ch)
+		}
+	}
+}
+
+// Subscribe возвращает события из буфера с номером больше lastID и канал новых событий.
+// Если lastID больше номера последнего события (например, после перезапуска сервера),
+// возвращается весь буфер. Канал закрывается при отключении медленного подписчика;
+// cancel нужно вызвать, когда подписка больше не нужна.
+func (b *Broker) Subscribe(lastID uint64) (backlog []Record, updates <-chan Record, cancel func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if lastID > b.lastID {
+		lastID = 0
+	}
+	for i := 0; i < len(b.buffer); i++ {
+		rec := b.buffer[(b.start+i)%len(b.buffer)]
+		if rec.ID > lastID {
+			backlog = append(backlog, rec)
+		}
+	}
+
+	sub := &subscription{ch: make(chan Record, subscriberQueueSize)}
+	b.subs[sub] = struct{}{}
+
+	cancel = func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.subs[sub]; ok {
+			delete(b.subs, sub)
+			close(sub.ch)
+		}
+	}
+	return backlog, sub.ch, cancel
+}
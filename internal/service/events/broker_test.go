@@ -0,0 +1,117 @@
+package events
+
+import (
+	"context"
+	"testing"
+
+	"github.com/avito/pvz/internal/domain/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func publishN(b *Broker, n int) {
+	for i := 0; i < n; i++ {
+		b.Publish(context.Background(), event.Event{Type: event.TypeProductAdded})
+	}
+}
+
+func ids(records []Record) []uint64 {
+	var result []uint64
+	for _, r := range records {
+		result = append(result, r.ID)
+	}
+	return result
+}
+
+func TestBroker_SubscribeBacklog(t *testing.T) {
+	tests := []struct {
+		name       string
+		bufferSize int
+		published  int
+		lastID     uint64
+		expected   []uint64
+	}{
+		{
+			name:       "новая подписка получает весь буфер",
+			bufferSize: 5,
+			published:  3,
+			lastID:     0,
+			expected:   []uint64{1, 2, 3},
+		},
+		{
+			name:       "возобновление после последнего полученного события",
+			bufferSize: 5,
+			published:  4,
+			lastID:     2,
+			expected:   []uint64{3, 4},
+		},
+		{
+			name:       "буфер хранит только последние события",
+			bufferSize: 3,
+			published:  7,
+			lastID:     1,
+			expected:   []uint64{5, 6, 7},
+		},
+		{
+			name:       "клиент уже получил все события",
+			bufferSize: 3,
+			published:  3,
+			lastID:     3,
+			expected:   nil,
+		},
+		{
+			name:       "номер из будущего после перезапуска",
+			bufferSize: 3,
+			published:  2,
+			lastID:     100,
+			expected:   []uint64{1, 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewBroker(tt.bufferSize)
+			publishN(b, tt.published)
+
+			backlog, _, cancel := b.Subscribe(tt.lastID)
+			defer cancel()
+
+			assert.Equal(t, tt.expected, ids(backlog))
+		})
+	}
+}
+
+func TestBroker_Updates(t *testing.T) {
+	b := NewBroker(10)
+	publishN(b, 1)
+
+	backlog, updates, cancel := b.Subscribe(0)
+	require.Len(t, backlog, 1)
+
+	b.Publish(context.Background(), event.Event{Type: event.TypeReceptionClosed})
+	rec := <-updates
+	assert.Equal(t, uint64(2), rec.ID)
+	assert.Equal(t, event.TypeReceptionClosed, rec.Event.Type)
+
+	cancel()
+	_, ok := <-updates
+	assert.False(t, ok)
+
+	// Повторная отмена и публикация после отмены безопасны
+	cancel()
+	publishN(b, 1)
+}
+
+func TestBroker_SlowSubscriberIsDropped(t *testing.T) {
+	b := NewBroker(10)
+	_, updates, cancel := b.Subscribe(0)
+	defer cancel()
+
+	publishN(b, subscriberQueueSize+1)
+
+	received := 0
+	for range updates {
+		received++
+	}
+	assert.Equal(t, subscriberQueueSize, received)
+}
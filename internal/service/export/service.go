@@ -0,0 +1,72 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/reception"
+)
+
+// Format формат выгрузки
+type Format string
+
+const (
+	FormatCSV    Format = "csv"
+	FormatNDJSON Format = "ndjson"
+)
+
+var (
+	ErrInvalidFormat    = errors.New("invalid export format")
+	ErrInvalidDateRange = errors.New("invalid date range")
+)
+
+// ParseFormat возвращает формат выгрузки по его названию
+func ParseFormat(s string) (Format, error) {
+	switch Format(s) {
+	case FormatCSV, FormatNDJSON:
+		return Format(s), nil
+	default:
+		return "", ErrInvalidFormat
+	}
+}
+
+// ContentType возвращает MIME-тип формата
+func (f Format) ContentType() string {
+	if f == FormatNDJSON {
+		return "application/x-ndjson"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// Service выгружает приемки с товарами для финансов и аналитики
+type Service struct {
+	exporter reception.Exporter
+}
+
+// New создает новый экземпляр Service
+func New(exporter reception.Exporter) *Service {
+	return &Service{
+		exporter: exporter,
+	}
+}
+
+// Export записывает в w приемки за период вместе с товарами в выбранном формате.
+// Строки пишутся по мере чтения из хранилища, выгрузка целиком в памяти не хранится.
+func (s *Service) Export(ctx context.Context, startDate, endDate time.Time, format Format, w io.Writer) error {
+	if endDate.Before(startDate) {
+		return ErrInvalidDateRange
+	}
+
+	rw, err := newRowWriter(format, w)
+	if err != nil {
+		return err
+	}
+
+	if err := s.exporter.ExportWithProducts(ctx, startDate, endDate, rw.Write); err != nil {
+		return err
+	}
+
+	return rw.Flush()
+}
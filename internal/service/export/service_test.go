@@ -0,0 +1,159 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/product"
+	"github.com/avito/pvz/internal/domain/reception"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockExporter мок для reception.Exporter, отдает заранее заданные строки
+type MockExporter struct {
+	mock.Mock
+	rows []*reception.ExportRow
+}
+
+func (m *MockExporter) ExportWithProducts(ctx context.Context, startDate, endDate time.Time, fn func(*reception.ExportRow) error) error {
+	args := m.Called(ctx, startDate, endDate)
+	for _, row := range m.rows {
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return args.Error(0)
+}
+
+func TestService_Export(t *testing.T) {
+	startDate := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	pvzID := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	receptionID := uuid.MustParse("22222222-2222-2222-2222-222222222222")
+	emptyReceptionID := uuid.MustParse("33333333-3333-3333-3333-333333333333")
+	productID := uuid.MustParse("44444444-4444-4444-4444-444444444444")
+	receptionTime := time.Date(2024, 1, 10, 9, 0, 0, 0, time.UTC)
+	productTime := time.Date(2024, 1, 10, 9, 5, 0, 0, time.UTC)
+
+	rows := []*reception.ExportRow{
+		{
+			PVZID:             pvzID,
+			PVZCity:           "Москва",
+			ReceptionID:       receptionID,
+			ReceptionDateTime: receptionTime,
+			Status:            reception.StatusClose,
+			ProductID:         uuid.NullUUID{UUID: productID, Valid: true},
+			ProductDateTime:   &productTime,
+			ProductType:       product.TypeFood,
+			Barcode:           "4600000000001",
+		},
+		{
+			PVZID:             pvzID,
+			PVZCity:           "Москва",
+			ReceptionID:       emptyReceptionID,
+			ReceptionDateTime: receptionTime,
+			Status:            reception.StatusInProgress,
+		},
+	}
+
+	tests := []struct {
+		name          string
+		format        Format
+		startDate     time.Time
+		endDate       time.Time
+		setupMocks    func(*MockExporter)
+		expected      string
+		expectedError error
+	}{
+		{
+			name:      "выгрузка в CSV",
+			format:    FormatCSV,
+			startDate: startDate,
+			endDate:   endDate,
+			setupMocks: func(m *MockExporter) {
+				m.rows = rows
+				m.On("ExportWithProducts", mock.Anything, startDate, endDate).Return(nil)
+			},
+			expected: "pvz_id,pvz_city,reception_id,reception_date_time,reception_status,product_id,product_date_time,product_type,product_barcode\n" +
+				"11111111-1111-1111-1111-111111111111,Москва,22222222-2222-2222-2222-222222222222,2024-01-10T09:00:00Z,close," +
+				"44444444-4444-4444-4444-444444444444,2024-01-10T09:05:00Z,food,4600000000001\n" +
+				"11111111-1111-1111-1111-111111111111,Москва,33333333-3333-3333-3333-333333333333,2024-01-10T09:00:00Z,in_progress,,,,\n",
+		},
+		{
+			name:      "выгрузка в NDJSON",
+			format:    FormatNDJSON,
+			startDate: startDate,
+			endDate:   endDate,
+			setupMocks: func(m *MockExporter) {
+				m.rows = rows
+				m.On("ExportWithProducts", mock.Anything, startDate, endDate).Return(nil)
+			},
+			expected: `{"pvz_id":"11111111-1111-1111-1111-111111111111","pvz_city":"Москва","reception_id":"22222222-2222-2222-2222-222222222222",` +
+				`"reception_date_time":"2024-01-10T09:00:00Z","reception_status":"close","product_id":"44444444-4444-4444-4444-444444444444",` +
+				`"product_date_time":"2024-01-10T09:05:00Z","product_type":"food","product_barcode":"4600000000001"}` + "\n" +
+				`{"pvz_id":"11111111-1111-1111-1111-111111111111","pvz_city":"Москва","reception_id":"33333333-3333-3333-3333-333333333333",` +
+				`"reception_date_time":"2024-01-10T09:00:00Z","reception_status":"in_progress"}` + "\n",
+		},
+		{
+			name:          "неверный диапазон дат",
+			format:        FormatCSV,
+			startDate:     endDate,
+			endDate:       startDate,
+			setupMocks:    func(m *MockExporter) {},
+			expectedError: ErrInvalidDateRange,
+		},
+		{
+			name:          "неизвестный формат",
+			format:        Format("xml"),
+			startDate:     startDate,
+			endDate:       endDate,
+			setupMocks:    func(m *MockExporter) {},
+			expectedError: ErrInvalidFormat,
+		},
+		{
+			name:      "ошибка хранилища",
+			format:    FormatCSV,
+			startDate: startDate,
+			endDate:   endDate,
+			setupMocks: func(m *MockExporter) {
+				m.On("ExportWithProducts", mock.Anything, startDate, endDate).Return(errors.New("database error"))
+			},
+			expectedError: errors.New("database error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exporter := new(MockExporter)
+			tt.setupMocks(exporter)
+
+			var buf bytes.Buffer
+			service := New(exporter)
+			err := service.Export(context.Background(), tt.startDate, tt.endDate, tt.format, &buf)
+
+			if tt.expectedError != nil {
+				assert.Equal(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, buf.String())
+			}
+
+			exporter.AssertExpectations(t)
+		})
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	format, err := ParseFormat("ndjson")
+	assert.NoError(t, err)
+	assert.Equal(t, FormatNDJSON, format)
+
+	_, err = ParseFormat("xlsx")
+	assert.Equal(t, ErrInvalidFormat, err)
+}
@@ -0,0 +1,114 @@
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/reception"
+)
+
+// csvHeader колонки CSV-выгрузки, совпадают с ключами NDJSON
+var csvHeader = []string{
+	"pvz_id", "pvz_city",
+	"reception_id", "reception_date_time", "reception_status",
+	"product_id", "product_date_time", "product_type", "product_barcode",
+}
+
+// rowWriter кодирует строки выгрузки в выбранный формат
+type rowWriter interface {
+	Write(row *reception.ExportRow) error
+	Flush() error
+}
+
+// newRowWriter создает кодировщик строк для формата
+func newRowWriter(format Format, w io.Writer) (rowWriter, error) {
+	switch format {
+	case FormatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(csvHeader); err != nil {
+			return nil, err
+		}
+		return &csvRowWriter{w: cw}, nil
+	case FormatNDJSON:
+		buf := bufio.NewWriter(w)
+		return &ndjsonRowWriter{buf: buf, enc: json.NewEncoder(buf)}, nil
+	default:
+		return nil, ErrInvalidFormat
+	}
+}
+
+// csvRowWriter пишет строки в CSV с заголовком
+type csvRowWriter struct {
+	w *csv.Writer
+}
+
+func (c *csvRowWriter) Write(row *reception.ExportRow) error {
+	record := []string{
+		row.PVZID.String(),
+		row.PVZCity,
+		row.ReceptionID.String(),
+		row.ReceptionDateTime.UTC().Format(time.RFC3339),
+		string(row.Status),
+		"", "", "", "",
+	}
+	if row.ProductID.Valid {
+		record[5] = row.ProductID.UUID.String()
+		record[7] = string(row.ProductType)
+		record[8] = row.Barcode
+	}
+	if row.ProductDateTime != nil {
+		record[6] = row.ProductDateTime.UTC().Format(time.RFC3339)
+	}
+	return c.w.Write(record)
+}
+
+func (c *csvRowWriter) Flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// jsonRow строка NDJSON-выгрузки
+type jsonRow struct {
+	PVZID             string     `json:"pvz_id"`
+	PVZCity           string     `json:"pvz_city"`
+	ReceptionID       string     `json:"reception_id"`
+	ReceptionDateTime time.Time  `json:"reception_date_time"`
+	ReceptionStatus   string     `json:"reception_status"`
+	ProductID         string     `json:"product_id,omitempty"`
+	ProductDateTime   *time.Time `json:"product_date_time,omitempty"`
+	ProductType       string     `json:"product_type,omitempty"`
+	ProductBarcode    string     `json:"product_barcode,omitempty"`
+}
+
+// ndjsonRowWriter пишет по одному JSON-объекту на строку
+type ndjsonRowWriter struct {
+	buf *bufio.Writer
+	enc *json.Encoder
+}
+
+func (n *ndjsonRowWriter) Write(row *reception.ExportRow) error {
+	out := jsonRow{
+		PVZID:             row.PVZID.String(),
+		PVZCity:           row.PVZCity,
+		ReceptionID:       row.ReceptionID.String(),
+		ReceptionDateTime: row.ReceptionDateTime.UTC(),
+		ReceptionStatus:   string(row.Status),
+	}
+	if row.ProductID.Valid {
+		out.ProductID = row.ProductID.UUID.String()
+		out.ProductType = string(row.ProductType)
+		out.ProductBarcode = row.Barcode
+	}
+	if row.ProductDateTime != nil {
+		t := row.ProductDateTime.UTC()
+		out.ProductDateTime = &t
+	}
+	return n.enc.Encode(out)
+}
+
+func (n *ndjsonRowWriter) Flush() error {
+	return n.buf.Flush()
+}
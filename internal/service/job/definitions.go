@@ -0,0 +1,149 @@
+package job
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/job"
+	"github.com/avito/pvz/internal/domain/rbac"
+	"github.com/avito/pvz/internal/service/export"
+	"github.com/avito/pvz/internal/service/product"
+	"github.com/google/uuid"
+)
+
+// Типы задач, которые регистрирует приложение
+const (
+	// TypeExportReceptions выгрузка приемок с товарами за период
+	TypeExportReceptions job.Type = "export.receptions"
+	// TypeImportProducts импорт товаров в приемку
+	TypeImportProducts job.Type = "products.import"
+)
+
+// Exporter выгружает приемки за период
+type Exporter interface {
+	Export(ctx context.Context, startDate, endDate time.Time, format export.Format, w io.Writer) error
+}
+
+// Importer добавляет товары в приемку
+type Importer interface {
+	Import(ctx context.Context, receptionID uuid.UUID, rows []product.ImportRow, dryRun bool) (*product.ImportResult, error)
+}
+
+// exportParams параметры выгрузки приемок
+type exportParams struct {
+	StartDate time.Time `json:"startDate"`
+	EndDate   time.Time `json:"endDate"`
+	Format    string    `json:"format"`
+}
+
+// parseExportParams разбирает и проверяет параметры выгрузки
+func parseExportParams(params []byte) (exportParams, export.Format, error) {
+	var p exportParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return p, "", err
+	}
+	if p.Format == "" {
+		p.Format = string(export.FormatCSV)
+	}
+	format, err := export.ParseFormat(p.Format)
+	if err != nil {
+		return p, "", err
+	}
+	if p.StartDate.IsZero() || p.EndDate.Before(p.StartDate) {
+		return p, "", export.ErrInvalidDateRange
+	}
+	return p, format, nil
+}
+
+// ExportReceptions описывает выгрузку приемок, доступную администраторам.
+// Результат хранится целиком, поэтому подходит для выгрузок умеренного размера.
+func ExportReceptions(exporter Exporter) Definition {
+	return Definition{
+		Permission: rbac.ReceptionExport,
+		Validate: func(params []byte) error {
+			_, _, err := parseExportParams(params)
+			return err
+		},
+		Handle: func(ctx context.Context, j *job.Job, _ func(int)) (*Result, error) {
+			p, format, err := parseExportParams(j.Params)
+			if err != nil {
+				return nil, err
+			}
+
+			var buf bytes.Buffer
+			if err := exporter.Export(ctx, p.StartDate, p.EndDate, format, &buf); err != nil {
+				return nil, err
+			}
+			return &Result{ContentType: format.ContentType(), Data: buf.Bytes()}, nil
+		},
+	}
+}
+
+// importParams параметры импорта товаров
+type importParams struct {
+	ReceptionID uuid.UUID `json:"receptionId"`
+	Rows        []struct {
+		Type     string `json:"type"`
+		Barcode  string `json:"barcode"`
+		Metadata string `json:"metadata"`
+	} `json:"rows"`
+	DryRun bool `json:"dryRun"`
+}
+
+// errEmptyReceptionID возвращается, когда в параметрах импорта нет приемки
+var errEmptyReceptionID = errors.New("receptionId is required")
+
+// parseImportParams разбирает параметры импорта в строки для сервиса товаров
+func parseImportParams(params []byte) (importParams, []product.ImportRow, error) {
+	var p importParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return p, nil, err
+	}
+	if p.ReceptionID == uuid.Nil {
+		return p, nil, errEmptyReceptionID
+	}
+	if len(p.Rows) == 0 {
+		return p, nil, product.ErrEmptyImport
+	}
+	if len(p.Rows) > product.MaxImportRows {
+		return p, nil, product.ErrImportTooLarge
+	}
+
+	rows := make([]product.ImportRow, len(p.Rows))
+	for i, r := range p.Rows {
+		rows[i] = product.ImportRow{Line: i + 1, Type: r.Type, Barcode: r.Barcode, Metadata: r.Metadata}
+	}
+	return p, rows, nil
+}
+
+// ImportProducts описывает импорт товаров в приемку, доступный сотрудникам ПВЗ.
+// Результат — отчет импорта в JSON, включая ошибки по строкам.
+func ImportProducts(importer Importer) Definition {
+	return Definition{
+		Permission: rbac.ProductCreate,
+		Validate: func(params []byte) error {
+			_, _, err := parseImportParams(params)
+			return err
+		},
+		Handle: func(ctx context.Context, j *job.Job, _ func(int)) (*Result, error) {
+			p, rows, err := parseImportParams(j.Params)
+			if err != nil {
+				return nil, err
+			}
+
+			report, err := importer.Import(ctx, p.ReceptionID, rows, p.DryRun)
+			if err != nil {
+				return nil, err
+			}
+			data, err := json.Marshal(report)
+			if err != nil {
+				return nil, err
+			}
+			return &Result{ContentType: "application/json", Data: data}, nil
+		},
+	}
+}
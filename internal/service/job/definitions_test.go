@@ -0,0 +1,104 @@
+package job
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/job"
+	"github.com/avito/pvz/internal/service/export"
+	"github.com/avito/pvz/internal/service/product"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeExporter пишет фиксированную выгрузку
+type fakeExporter struct {
+	format export.Format
+}
+
+func (e *fakeExporter) Export(_ context.Context, _, _ time.Time, format export.Format, w io.Writer) error {
+	e.format = format
+	_, err := io.WriteString(w, "reception_id\n")
+	return err
+}
+
+// fakeImporter запоминает строки импорта
+type fakeImporter struct {
+	rows []product.ImportRow
+}
+
+func (i *fakeImporter) Import(_ context.Context, _ uuid.UUID, rows []product.ImportRow, dryRun bool) (*product.ImportResult, error) {
+	i.rows = rows
+	return &product.ImportResult{Total: len(rows), Imported: len(rows), DryRun: dryRun}, nil
+}
+
+func TestExportReceptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  string
+		wantErr bool
+	}{
+		{name: "формат по умолчанию", params: `{"startDate":"2026-01-01T00:00:00Z","endDate":"2026-02-01T00:00:00Z"}`},
+		{name: "неизвестный формат", params: `{"startDate":"2026-01-01T00:00:00Z","endDate":"2026-02-01T00:00:00Z","format":"xml"}`, wantErr: true},
+		{name: "конец периода раньше начала", params: `{"startDate":"2026-02-01T00:00:00Z","endDate":"2026-01-01T00:00:00Z"}`, wantErr: true},
+		{name: "не JSON", params: `csv`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exporter := new(fakeExporter)
+			def := ExportReceptions(exporter)
+
+			err := def.Validate([]byte(tt.params))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			result, err := def.Handle(context.Background(), &job.Job{Params: []byte(tt.params)}, func(int) {})
+			require.NoError(t, err)
+			assert.Equal(t, export.FormatCSV, exporter.format)
+			assert.Equal(t, export.FormatCSV.ContentType(), result.ContentType)
+			assert.Equal(t, "reception_id\n", string(result.Data))
+		})
+	}
+}
+
+func TestImportProducts(t *testing.T) {
+	receptionID := uuid.New()
+
+	tests := []struct {
+		name    string
+		params  string
+		wantErr error
+	}{
+		{name: "строки переданы сервису", params: `{"receptionId":"` + receptionID.String() + `","rows":[{"type":"обувь","barcode":"1"}],"dryRun":true}`},
+		{name: "нет приемки", params: `{"rows":[{"type":"обувь","barcode":"1"}]}`, wantErr: errEmptyReceptionID},
+		{name: "нет строк", params: `{"receptionId":"` + receptionID.String() + `"}`, wantErr: product.ErrEmptyImport},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			importer := new(fakeImporter)
+			def := ImportProducts(importer)
+
+			err := def.Validate([]byte(tt.params))
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			result, err := def.Handle(context.Background(), &job.Job{Params: []byte(tt.params)}, func(int) {})
+			require.NoError(t, err)
+			assert.Equal(t, "application/json", result.ContentType)
+			assert.JSONEq(t, `{"total":1,"imported":1,"dry_run":true}`, string(result.Data))
+			require.Len(t, importer.rows, 1)
+			assert.Equal(t, product.ImportRow{Line: 1, Type: "обувь", Barcode: "1"}, importer.rows[0])
+		})
+	}
+}
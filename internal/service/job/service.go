@@ -0,0 +1,175 @@
+// Package job выполняет долгие операции в фоне. Задача ставится в очередь
+// в Postgres и сразу получает ID, по которому клиент следит за статусом и
+// прогрессом и скачивает результат. Обработчики забирают задачи из очереди
+// с арендой: если обработчик упал, задача после истечения аренды достается
+// другому. Число одновременно выполняющихся и ожидающих задач одного
+// пользователя ограничено, завершенные задачи удаляются после Retention.
+package job
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/job"
+	"github.com/avito/pvz/internal/domain/rbac"
+	"github.com/avito/pvz/internal/domain/transaction"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrUnknownType     = errors.New("unknown job type")
+	ErrInvalidParams   = errors.New("invalid job params")
+	ErrForbidden       = errors.New("job type is not allowed for role")
+	ErrTooManyJobs     = errors.New("too many active jobs")
+	ErrResultNotReady  = errors.New("job result is not ready")
+	errHandlerPanicked = errors.New("job handler panicked")
+)
+
+// Config параметры очереди задач
+type Config struct {
+	// Workers число задач, выполняемых одним экземпляром сервиса одновременно
+	Workers int
+	// PollInterval период опроса очереди, когда свободных задач нет
+	PollInterval time.Duration
+	// Lease время, на которое задача закрепляется за обработчиком. Аренда
+	// продлевается, пока задача выполняется.
+	Lease time.Duration
+	// MaxRunningPerUser число одновременно выполняющихся задач пользователя
+	MaxRunningPerUser int
+	// MaxActivePerUser число задач пользователя в очереди и в работе
+	MaxActivePerUser int
+	// Retention время хранения завершенных задач и их результатов
+	Retention time.Duration
+}
+
+// DefaultConfig параметры очереди по умолчанию
+var DefaultConfig = Config{
+	Workers:           4,
+	PollInterval:      time.Second,
+	Lease:             time.Minute,
+	MaxRunningPerUser: 2,
+	MaxActivePerUser:  10,
+	Retention:         24 * time.Hour,
+}
+
+// Result результат успешно выполненной задачи
+type Result struct {
+	ContentType string
+	Data        []byte
+}
+
+// Handler выполняет задачу. Обработчик должен завершиться при отмене ctx и
+// может сообщать процент выполнения через progress.
+type Handler func(ctx context.Context, j *job.Job, progress func(percent int)) (*Result, error)
+
+// Definition описание типа задачи
+type Definition struct {
+	// Permission разрешение, необходимое для постановки задачи этого типа
+	Permission rbac.Permission
+	// Validate проверяет параметры при постановке в очередь, может быть nil
+	Validate func(params []byte) error
+	// Handle выполняет задачу
+	Handle Handler
+}
+
+// Service ставит задачи в очередь и выполняет их
+type Service struct {
+	repo        job.Repository
+	txManager   transaction.Manager
+	cfg         Config
+	definitions map[job.Type]Definition
+	now         func() time.Time
+}
+
+// New создает новый экземпляр Service. Права на постановку задач и доступ к
+// чужим задачам проверяются по разрешениям вызывающего из контекста: роли
+// пользователя или областям ключа API сервисного аккаунта.
+func New(repo job.Repository, txManager transaction.Manager, cfg Config) *Service {
+	return &Service{
+		repo:        repo,
+		txManager:   txManager,
+		cfg:         cfg,
+		definitions: make(map[job.Type]Definition),
+		now:         time.Now,
+	}
+}
+
+// Register регистрирует тип задачи. Все типы регистрируются до запуска Run.
+func (s *Service) Register(jobType job.Type, def Definition) {
+	s.definitions[jobType] = def
+}
+
+// Submit проверяет параметры и ставит задачу в очередь
+func (s *Service) Submit(ctx context.Context, jobType job.Type, params []byte, userID uuid.UUID) (*job.Job, error) {
+	def, ok := s.definitions[jobType]
+	if !ok {
+		return nil, ErrUnknownType
+	}
+	if err := rbac.Check(ctx, def.Permission); err != nil {
+		return nil, ErrForbidden
+	}
+	if def.Validate != nil {
+		if err := def.Validate(params); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidParams, err)
+		}
+	}
+
+	// Ограничение мягкое: параллельные запросы одного пользователя могут
+	// ненадолго превысить его, но число выполняющихся задач ограничено строго
+	active, err := s.repo.CountActive(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if active >= s.cfg.MaxActivePerUser {
+		return nil, ErrTooManyJobs
+	}
+
+	j := job.New(jobType, userID, params)
+	j.CreatedAt = s.now()
+	// Разрешения сохраняются вместе с задачей: обработчик выполняет ее вне запроса
+	// и проверяет права автора так же, как при синхронном вызове
+	j.Permissions = rbac.FromContext(ctx).List()
+	if err := s.repo.Create(ctx, j); err != nil {
+		return nil, err
+	}
+
+	return j, nil
+}
+
+// Get возвращает задачу ее владельцу или пользователю с правом видеть все задачи
+func (s *Service) Get(ctx context.Context, id, userID uuid.UUID) (*job.Job, error) {
+	j, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	// Чужие задачи неотличимы от несуществующих
+	if j.UserID != userID && !rbac.FromContext(ctx).Has(rbac.JobReadAll) {
+		return nil, job.ErrNotFound
+	}
+	return j, nil
+}
+
+// GetResult возвращает задачу с результатом, если она успешно выполнена
+func (s *Service) GetResult(ctx context.Context, id, userID uuid.UUID) (*job.Job, error) {
+	j, err := s.Get(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if j.Status != job.StatusSucceeded {
+		return nil, ErrResultNotReady
+	}
+	return j, nil
+}
+
+// Cancel отменяет задачу в очереди или просит обработчик прервать выполняющуюся
+func (s *Service) Cancel(ctx context.Context, id, userID uuid.UUID) (*job.Job, error) {
+	if _, err := s.Get(ctx, id, userID); err != nil {
+		return nil, err
+	}
+	if err := s.repo.RequestCancel(ctx, id, s.now()); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, id)
+}
@@ -0,0 +1,372 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/job"
+	"github.com/avito/pvz/internal/domain/rbac"
+	"github.com/avito/pvz/internal/domain/user"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockRepository мок для job.Repository
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) Create(ctx context.Context, j *job.Job) error {
+	args := m.Called(ctx, j)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetByID(ctx context.Context, id uuid.UUID) (*job.Job, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*job.Job), args.Error(1)
+}
+
+func (m *MockRepository) CountActive(ctx context.Context, userID uuid.UUID) (int, error) {
+	args := m.Called(ctx, userID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockRepository) LockClaims(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func (m *MockRepository) ClaimNext(ctx context.Context, now, leaseUntil time.Time, maxRunning int) (*job.Job, error) {
+	args := m.Called(ctx, now, leaseUntil, maxRunning)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*job.Job), args.Error(1)
+}
+
+func (m *MockRepository) Heartbeat(ctx context.Context, id, leaseID uuid.UUID, progress int, leaseUntil time.Time) (bool, error) {
+	args := m.Called(ctx, id, leaseID, progress, leaseUntil)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRepository) Finish(ctx context.Context, j *job.Job) error {
+	args := m.Called(ctx, j)
+	return args.Error(0)
+}
+
+func (m *MockRepository) RequestCancel(ctx context.Context, id uuid.UUID, at time.Time) error {
+	args := m.Called(ctx, id, at)
+	return args.Error(0)
+}
+
+func (m *MockRepository) DeleteFinishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// fakeTxManager выполняет функцию без транзакции
+type fakeTxManager struct{}
+
+func (fakeTxManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+const testType job.Type = "test.job"
+
+// testAuthorizer проверяет права по политике по умолчанию
+var testAuthorizer = rbac.MustNewAuthorizer(rbac.DefaultPolicy)
+
+// withRole добавляет в контекст разрешения роли, как это делает аутентификация
+func withRole(role user.Role) context.Context {
+	return rbac.WithPermissions(context.Background(), testAuthorizer.Permissions(role))
+}
+
+func newTestService(repo *MockRepository, def Definition) (*Service, time.Time) {
+	now := time.Date(2026, time.October, 1, 12, 0, 0, 0, time.UTC)
+	cfg := DefaultConfig
+	cfg.Lease = 30 * time.Millisecond
+	s := New(repo, fakeTxManager{}, cfg)
+	s.now = func() time.Time { return now }
+	s.Register(testType, def)
+	return s, now
+}
+
+func TestService_Submit(t *testing.T) {
+	userID := uuid.New()
+	def := Definition{
+		Permission: rbac.ProductCreate,
+		Validate: func(params []byte) error {
+			if string(params) == "{}" {
+				return errors.New("empty params")
+			}
+			return nil
+		},
+		Handle: func(context.Context, *job.Job, func(int)) (*Result, error) { return nil, nil },
+	}
+
+	tests := []struct {
+		name    string
+		jobType job.Type
+		params  string
+		ctx     context.Context
+		active  int
+		wantErr error
+	}{
+		{name: "задача поставлена в очередь", jobType: testType, params: `{"a":1}`, ctx: withRole(user.RoleEmployee)},
+		{name: "ключ API с областью задачи", jobType: testType, params: `{"a":1}`, ctx: rbac.WithPermissions(context.Background(), rbac.NewSet(rbac.ProductCreate))},
+		{name: "неизвестный тип", jobType: "unknown", params: `{"a":1}`, ctx: withRole(user.RoleEmployee), wantErr: ErrUnknownType},
+		{name: "роль не допущена", jobType: testType, params: `{"a":1}`, ctx: withRole(user.RoleUser), wantErr: ErrForbidden},
+		{name: "ключ API без области задачи", jobType: testType, params: `{"a":1}`, ctx: rbac.WithPermissions(context.Background(), rbac.NewSet(rbac.PVZCreate)), wantErr: ErrForbidden},
+		{name: "неверные параметры", jobType: testType, params: `{}`, ctx: withRole(user.RoleEmployee), wantErr: ErrInvalidParams},
+		{name: "превышен лимит задач", jobType: testType, params: `{"a":1}`, ctx: withRole(user.RoleEmployee), active: DefaultConfig.MaxActivePerUser, wantErr: ErrTooManyJobs},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockRepository)
+			s, now := newTestService(repo, def)
+			repo.On("CountActive", mock.Anything, userID).Return(tt.active, nil).Maybe()
+			repo.On("Create", mock.Anything, mock.AnythingOfType("*job.Job")).Return(nil).Maybe()
+
+			j, err := s.Submit(tt.ctx, tt.jobType, []byte(tt.params), userID)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, job.StatusQueued, j.Status)
+			assert.Equal(t, userID, j.UserID)
+			assert.Equal(t, now, j.CreatedAt)
+			assert.Equal(t, rbac.FromContext(tt.ctx).List(), j.Permissions)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_Get(t *testing.T) {
+	ownerID := uuid.New()
+	j := &job.Job{ID: uuid.New(), UserID: ownerID, Status: job.StatusRunning}
+
+	tests := []struct {
+		name    string
+		userID  uuid.UUID
+		role    user.Role
+		wantErr error
+	}{
+		{name: "владелец видит задачу", userID: ownerID, role: user.RoleEmployee},
+		{name: "администратор видит чужую задачу", userID: uuid.New(), role: user.RoleAdmin},
+		{name: "чужая задача не найдена", userID: uuid.New(), role: user.RoleEmployee, wantErr: job.ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockRepository)
+			s, _ := newTestService(repo, Definition{})
+			repo.On("GetByID", mock.Anything, j.ID).Return(j, nil)
+
+			got, err := s.Get(withRole(tt.role), j.ID, tt.userID)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, j, got)
+		})
+	}
+}
+
+func TestService_GetResult(t *testing.T) {
+	userID := uuid.New()
+	repo := new(MockRepository)
+	s, _ := newTestService(repo, Definition{})
+
+	running := &job.Job{ID: uuid.New(), UserID: userID, Status: job.StatusRunning}
+	repo.On("GetByID", mock.Anything, running.ID).Return(running, nil)
+
+	_, err := s.GetResult(withRole(user.RoleEmployee), running.ID, userID)
+	assert.ErrorIs(t, err, ErrResultNotReady)
+}
+
+func TestService_Cancel(t *testing.T) {
+	userID := uuid.New()
+	repo := new(MockRepository)
+	s, now := newTestService(repo, Definition{})
+
+	queued := &job.Job{ID: uuid.New(), UserID: userID, Status: job.StatusQueued}
+	cancelled := &job.Job{ID: queued.ID, UserID: userID, Status: job.StatusCancelled}
+	repo.On("GetByID", mock.Anything, queued.ID).Return(queued, nil).Once()
+	repo.On("RequestCancel", mock.Anything, queued.ID, now).Return(nil)
+	repo.On("GetByID", mock.Anything, queued.ID).Return(cancelled, nil).Once()
+
+	got, err := s.Cancel(withRole(user.RoleEmployee), queued.ID, userID)
+	require.NoError(t, err)
+	assert.Equal(t, job.StatusCancelled, got.Status)
+	repo.AssertExpectations(t)
+}
+
+func TestService_ProcessNext(t *testing.T) {
+	tests := []struct {
+		name         string
+		handle       Handler
+		wantStatus   job.Status
+		wantProgress int
+		wantError    string
+		wantResult   string
+	}{
+		{
+			name: "задача выполнена",
+			handle: func(_ context.Context, _ *job.Job, progress func(int)) (*Result, error) {
+				progress(50)
+				return &Result{ContentType: "text/csv", Data: []byte("id\n")}, nil
+			},
+			wantStatus:   job.StatusSucceeded,
+			wantProgress: 100,
+			wantResult:   "id\n",
+		},
+		{
+			name: "ошибка обработчика",
+			handle: func(_ context.Context, _ *job.Job, progress func(int)) (*Result, error) {
+				progress(30)
+				return nil, errors.New("boom")
+			},
+			wantStatus:   job.StatusFailed,
+			wantProgress: 30,
+			wantError:    "boom",
+		},
+		{
+			name: "паника обработчика",
+			handle: func(context.Context, *job.Job, func(int)) (*Result, error) {
+				panic("boom")
+			},
+			wantStatus: job.StatusFailed,
+			wantError:  errHandlerPanicked.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockRepository)
+			s, now := newTestService(repo, Definition{Handle: tt.handle})
+			j := &job.Job{ID: uuid.New(), Type: testType, Status: job.StatusRunning, LeaseID: uuid.New()}
+
+			repo.On("LockClaims", mock.Anything).Return(nil)
+			repo.On("ClaimNext", mock.Anything, now, now.Add(s.cfg.Lease), s.cfg.MaxRunningPerUser).Return(j, nil)
+			repo.On("Heartbeat", mock.Anything, j.ID, j.LeaseID, mock.Anything, mock.Anything).Return(false, nil).Maybe()
+			repo.On("Finish", mock.Anything, j).Return(nil)
+
+			processed, err := s.ProcessNext(context.Background())
+			require.NoError(t, err)
+			assert.True(t, processed)
+			assert.Equal(t, tt.wantStatus, j.Status)
+			assert.Equal(t, tt.wantProgress, j.Progress)
+			assert.Equal(t, tt.wantError, j.Error)
+			assert.Equal(t, tt.wantResult, string(j.Result))
+			require.NotNil(t, j.FinishedAt)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_ProcessNext_Empty(t *testing.T) {
+	repo := new(MockRepository)
+	s, _ := newTestService(repo, Definition{})
+	repo.On("LockClaims", mock.Anything).Return(nil)
+	repo.On("ClaimNext", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+
+	processed, err := s.ProcessNext(context.Background())
+	require.NoError(t, err)
+	assert.False(t, processed)
+	repo.AssertNotCalled(t, "Finish", mock.Anything, mock.Anything)
+}
+
+func TestService_ProcessNext_Cancelled(t *testing.T) {
+	repo := new(MockRepository)
+	// Обработчик ждет отмены контекста, которую вызывает запрос отмены из heartbeat
+	s, _ := newTestService(repo, Definition{
+		Handle: func(ctx context.Context, _ *job.Job, _ func(int)) (*Result, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	})
+	j := &job.Job{ID: uuid.New(), Type: testType, Status: job.StatusRunning, LeaseID: uuid.New()}
+
+	repo.On("LockClaims", mock.Anything).Return(nil)
+	repo.On("ClaimNext", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(j, nil)
+	repo.On("Heartbeat", mock.Anything, j.ID, j.LeaseID, 0, mock.Anything).Return(true, nil)
+	repo.On("Finish", mock.Anything, j).Return(nil)
+
+	processed, err := s.ProcessNext(context.Background())
+	require.NoError(t, err)
+	assert.True(t, processed)
+	assert.Equal(t, job.StatusCancelled, j.Status)
+	assert.Empty(t, j.Error)
+	repo.AssertExpectations(t)
+}
+
+func TestService_ProcessNext_Permissions(t *testing.T) {
+	repo := new(MockRepository)
+	var got rbac.Set
+	s, _ := newTestService(repo, Definition{
+		Handle: func(ctx context.Context, _ *job.Job, _ func(int)) (*Result, error) {
+			got = rbac.FromContext(ctx)
+			return nil, nil
+		},
+	})
+	j := &job.Job{
+		ID:          uuid.New(),
+		Type:        testType,
+		Status:      job.StatusRunning,
+		Permissions: []rbac.Permission{rbac.PVZAccessAll, rbac.ProductCreate},
+		LeaseID:     uuid.New(),
+	}
+
+	repo.On("LockClaims", mock.Anything).Return(nil)
+	repo.On("ClaimNext", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(j, nil)
+	repo.On("Heartbeat", mock.Anything, j.ID, j.LeaseID, mock.Anything, mock.Anything).Return(false, nil).Maybe()
+	repo.On("Finish", mock.Anything, j).Return(nil)
+
+	_, err := s.ProcessNext(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, rbac.NewSet(rbac.PVZAccessAll, rbac.ProductCreate), got)
+}
+
+func TestService_ProcessNext_LeaseLost(t *testing.T) {
+	repo := new(MockRepository)
+	// Обработчик ждет отмены контекста, которую вызывает потеря аренды в heartbeat
+	s, _ := newTestService(repo, Definition{
+		Handle: func(ctx context.Context, _ *job.Job, _ func(int)) (*Result, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	})
+	j := &job.Job{ID: uuid.New(), Type: testType, Status: job.StatusRunning, LeaseID: uuid.New()}
+
+	repo.On("LockClaims", mock.Anything).Return(nil)
+	repo.On("ClaimNext", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(j, nil)
+	repo.On("Heartbeat", mock.Anything, j.ID, j.LeaseID, 0, mock.Anything).Return(false, job.ErrLeaseLost)
+
+	processed, err := s.ProcessNext(context.Background())
+	assert.ErrorIs(t, err, job.ErrLeaseLost)
+	assert.True(t, processed)
+	assert.Equal(t, job.StatusRunning, j.Status)
+	repo.AssertNotCalled(t, "Finish", mock.Anything, mock.Anything)
+}
+
+func TestService_Cleanup(t *testing.T) {
+	repo := new(MockRepository)
+	s, now := newTestService(repo, Definition{})
+	repo.On("DeleteFinishedBefore", mock.Anything, now.Add(-DefaultConfig.Retention)).Return(int64(3), nil)
+
+	deleted, err := s.Cleanup(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), deleted)
+}
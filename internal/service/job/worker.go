@@ -0,0 +1,230 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/audit"
+	"github.com/avito/pvz/internal/domain/job"
+	"github.com/avito/pvz/internal/domain/rbac"
+	"github.com/avito/pvz/pkg/auth"
+)
+
+// maxErrorLength ограничивает длину сохраняемой причины неудачи
+const maxErrorLength = 512
+
+// Run запускает Workers обработчиков очереди и работает до отмены ctx.
+// Завершенные задачи старше Retention удаляются раз в час.
+func (s *Service) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for i := 0; i < s.cfg.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.work(ctx)
+		}()
+	}
+
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	for {
+		if _, err := s.Cleanup(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("failed to clean up jobs: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// work выполняет задачи одну за другой, пока очередь не опустеет,
+// после чего ждет PollInterval
+func (s *Service) work(ctx context.Context) {
+	for {
+		processed, err := s.ProcessNext(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("failed to process job: %v", err)
+		}
+		if processed && ctx.Err() == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(s.cfg.PollInterval):
+		}
+	}
+}
+
+// ProcessNext забирает из очереди одну задачу и выполняет ее.
+// Возвращает false, если подходящих задач нет.
+func (s *Service) ProcessNext(ctx context.Context) (bool, error) {
+	var claimed *job.Job
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		// Блокировка упорядочивает выбор задач между обработчиками, иначе
+		// они могли бы одновременно превысить лимит выполняющихся задач пользователя
+		if err := s.repo.LockClaims(ctx); err != nil {
+			return err
+		}
+
+		now := s.now()
+		j, err := s.repo.ClaimNext(ctx, now, now.Add(s.cfg.Lease), s.cfg.MaxRunningPerUser)
+		if err != nil {
+			return err
+		}
+		claimed = j
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	if claimed == nil {
+		return false, nil
+	}
+
+	return true, s.execute(ctx, claimed)
+}
+
+// execute выполняет задачу, продлевая аренду, и сохраняет итог
+func (s *Service) execute(ctx context.Context, j *job.Job) error {
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var progress atomic.Int32
+	progress.Store(int32(j.Progress))
+	var cancelled, lost atomic.Bool
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		s.heartbeat(runCtx, j, &progress, stop, func() {
+			cancelled.Store(true)
+			cancel()
+		}, func() {
+			lost.Store(true)
+			cancel()
+		})
+	}()
+
+	result, runErr := s.run(runCtx, j, func(percent int) {
+		progress.Store(int32(clamp(percent)))
+	})
+	close(stop)
+	wg.Wait()
+
+	// Сервер останавливается: задача не завершена и после истечения
+	// аренды достанется другому обработчику
+	if ctx.Err() != nil && !cancelled.Load() {
+		return nil
+	}
+	// Аренда истекла, и задачу выполняет другой обработчик: итог сохранит он
+	if lost.Load() {
+		return job.ErrLeaseLost
+	}
+
+	finishedAt := s.now()
+	j.FinishedAt = &finishedAt
+	j.Progress = int(progress.Load())
+	switch {
+	case cancelled.Load():
+		j.Status = job.StatusCancelled
+	case runErr != nil:
+		j.Status = job.StatusFailed
+		j.Error = truncate(runErr.Error())
+	default:
+		j.Status = job.StatusSucceeded
+		j.Progress = 100
+		if result != nil {
+			j.Result = result.Data
+			j.ResultContentType = result.ContentType
+		}
+	}
+
+	return s.repo.Finish(context.WithoutCancel(ctx), j)
+}
+
+// run вызывает обработчик типа задачи, превращая панику в ошибку
+func (s *Service) run(ctx context.Context, j *job.Job, progress func(int)) (result *Result, err error) {
+	def, ok := s.definitions[j.Type]
+	if !ok {
+		return nil, ErrUnknownType
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			log.Printf("job %s of type %s panicked: %v", j.ID, j.Type, p)
+			result, err = nil, errHandlerPanicked
+		}
+	}()
+
+	// Задача выполняется от имени ее автора и с его разрешениями на момент постановки:
+	// сервисы проверяют его закрепления за ПВЗ и права доступа ко всем ПВЗ,
+	// а изменения попадают в журнал аудита с ID задачи вместо ID запроса
+	ctx = auth.WithUserID(ctx, j.UserID)
+	ctx = rbac.WithPermissions(ctx, rbac.NewSet(j.Permissions...))
+	ctx = audit.WithOrigin(ctx, audit.Origin{Source: audit.SourceJob, RequestID: j.ID.String()})
+	return def.Handle(ctx, j, progress)
+}
+
+// heartbeat периодически сохраняет прогресс и продлевает аренду задачи.
+// Если пользователь запросил отмену, вызывается onCancel, если аренда
+// перешла к другому обработчику - onLost.
+func (s *Service) heartbeat(ctx context.Context, j *job.Job, progress *atomic.Int32, stop <-chan struct{}, onCancel, onLost func()) {
+	ticker := time.NewTicker(s.cfg.Lease / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		cancelRequested, err := s.repo.Heartbeat(ctx, j.ID, j.LeaseID, int(progress.Load()), s.now().Add(s.cfg.Lease))
+		if errors.Is(err, job.ErrLeaseLost) {
+			onLost()
+			return
+		}
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("failed to extend job %s lease: %v", j.ID, err)
+			}
+			continue
+		}
+		if cancelRequested {
+			onCancel()
+			return
+		}
+	}
+}
+
+// Cleanup удаляет задачи, завершенные раньше Retention, и возвращает их число
+func (s *Service) Cleanup(ctx context.Context) (int64, error) {
+	return s.repo.DeleteFinishedBefore(ctx, s.now().Add(-s.cfg.Retention))
+}
+
+// clamp ограничивает процент выполнения диапазоном от 0 до 100
+func clamp(percent int) int {
+	return min(max(percent, 0), 100)
+}
+
+// truncate обрезает причину неудачи до maxErrorLength байт
+func truncate(s string) string {
+	if len(s) <= maxErrorLength {
+		return s
+	}
+	return s[:maxErrorLength]
+}
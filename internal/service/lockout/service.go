@@ -0,0 +1,251 @@
+// Package lockout защищает вход от подбора паролей. Неудачные попытки считаются
+// для учетной записи (по email) и для IP-адреса клиента. Каждая неудача подряд
+// увеличивает задержку ответа, а после порога вход с этим ключом блокируется
+// на время. Счетчики ведутся и для несуществующих email, поэтому ни ответ,
+// ни блокировка не раскрывают, зарегистрирован ли адрес.
+package lockout
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/event"
+	"github.com/avito/pvz/internal/domain/lockout"
+	"github.com/avito/pvz/internal/domain/transaction"
+	"github.com/avito/pvz/internal/domain/user"
+	"github.com/avito/pvz/pkg/auth"
+	"github.com/google/uuid"
+)
+
+// ErrUnauthorized снятие блокировки без вызывающего в контексте
+var ErrUnauthorized = errors.New("unauthorized")
+
+// Config параметры защиты входа
+type Config struct {
+	// MaxAccountFailures число неудач подряд, после которого блокируется учетная запись
+	MaxAccountFailures int
+	// MaxIPFailures число неудач подряд, после которого блокируется IP-адрес.
+	// Выше порога учетной записи: за одним адресом может работать весь ПВЗ.
+	MaxIPFailures int
+	// Window неудачи старше этого времени не учитываются
+	Window time.Duration
+	// LockoutDuration длительность блокировки
+	LockoutDuration time.Duration
+	// BaseDelay задержка ответа после первой неудачи, каждая следующая удваивает ее
+	BaseDelay time.Duration
+	// MaxDelay наибольшая задержка ответа
+	MaxDelay time.Duration
+	// CleanupInterval период удаления устаревших счетчиков
+	CleanupInterval time.Duration
+}
+
+// DefaultConfig параметры защиты входа по умолчанию
+var DefaultConfig = Config{
+	MaxAccountFailures: 5,
+	MaxIPFailures:      50,
+	Window:             15 * time.Minute,
+	LockoutDuration:    15 * time.Minute,
+	BaseDelay:          250 * time.Millisecond,
+	MaxDelay:           4 * time.Second,
+	CleanupInterval:    time.Hour,
+}
+
+// Service ведет счетчики неудачных попыток входа
+type Service struct {
+	repo      lockout.Repository
+	users     user.Repository
+	txManager transaction.Manager
+	events    event.Bus
+	cfg       Config
+	now       func() time.Time
+	sleep     func(ctx context.Context, d time.Duration)
+}
+
+// New создает новый экземпляр Service
+func New(repo lockout.Repository, users user.Repository, txManager transaction.Manager, events event.Bus, cfg Config) *Service {
+	return &Service{
+		repo:      repo,
+		users:     users,
+		txManager: txManager,
+		events:    events,
+		cfg:       cfg,
+		now:       time.Now,
+		sleep:     sleep,
+	}
+}
+
+// Check возвращает *lockout.LockedError, если вход по email или с IP-адреса
+// клиента из контекста заблокирован
+func (s *Service) Check(ctx context.Context, email string) error {
+	now := s.now()
+	for _, key := range s.keys(ctx, email) {
+		c, err := s.repo.Get(ctx, key)
+		if errors.Is(err, lockout.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if c.Locked(now) {
+			return &lockout.LockedError{Until: *c.LockedUntil}
+		}
+	}
+	return nil
+}
+
+// Failed учитывает неудачную попытку входа по email с IP-адреса клиента
+// и задерживает ответ тем дольше, чем больше неудач подряд. userID —
+// пользователь с этим email или uuid.Nil, если его нет.
+func (s *Service) Failed(ctx context.Context, email string, userID uuid.UUID) error {
+	var (
+		locked   []event.Domain
+		failures int
+	)
+	accountKey := lockout.AccountKey(email)
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		locked = nil
+		now := s.now()
+		for _, key := range s.keys(ctx, email) {
+			c, err := s.repo.Lock(ctx, key, now)
+			if err != nil {
+				return err
+			}
+
+			limit, lockedUserID := s.cfg.MaxIPFailures, uuid.Nil
+			if key == accountKey {
+				limit, lockedUserID = s.cfg.MaxAccountFailures, userID
+			}
+
+			// Неудачи вне окна и до истекшей блокировки начинают отсчет заново
+			if now.Sub(c.LastFailureAt) > s.cfg.Window || (c.LockedUntil != nil && !c.Locked(now)) {
+				c.Failures = 0
+				c.LockedUntil = nil
+			}
+			c.Failures++
+			c.LastFailureAt = now
+			if key == accountKey {
+				failures = c.Failures
+			}
+
+			if c.Failures >= limit {
+				until := now.Add(s.cfg.LockoutDuration)
+				c.LockedUntil = &until
+				locked = append(locked, event.LoginLocked{
+					Key:         key,
+					UserID:      lockedUserID,
+					Failures:    c.Failures,
+					LockedUntil: until,
+					OccurredAt:  now,
+				})
+			}
+
+			if err := s.repo.Save(ctx, c); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.events.Raise(ctx, locked...)
+	s.sleep(ctx, s.delay(failures))
+	return nil
+}
+
+// Succeeded сбрасывает счетчик учетной записи после успешного входа.
+// Счетчик IP-адреса не сбрасывается: успешный вход в одну учетную запись
+// не должен разрешать перебор остальных.
+func (s *Service) Succeeded(ctx context.Context, email string) error {
+	return s.repo.Delete(ctx, lockout.AccountKey(email))
+}
+
+// Unlock снимает блокировку и сбрасывает счетчик учетной записи пользователя
+func (s *Service) Unlock(ctx context.Context, userID uuid.UUID) error {
+	u, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return s.unlock(ctx, lockout.AccountKey(u.Email), userID)
+}
+
+// UnlockIP снимает блокировку и сбрасывает счетчик IP-адреса
+func (s *Service) UnlockIP(ctx context.Context, ip string) error {
+	return s.unlock(ctx, lockout.IPKey(ip), uuid.Nil)
+}
+
+// unlock удаляет счетчик key и сообщает о снятии блокировки
+func (s *Service) unlock(ctx context.Context, key string, userID uuid.UUID) error {
+	moderatorID, ok := auth.GetUserID(ctx)
+	if !ok {
+		return ErrUnauthorized
+	}
+
+	if err := s.repo.Delete(ctx, key); err != nil {
+		return err
+	}
+
+	s.events.Raise(ctx, event.LoginUnlocked{
+		Key:        key,
+		UserID:     userID,
+		UnlockedBy: moderatorID,
+		OccurredAt: s.now(),
+	})
+	return nil
+}
+
+// Run периодически удаляет устаревшие счетчики до отмены ctx
+func (s *Service) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.CleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			before := s.now().Add(-max(s.cfg.Window, s.cfg.LockoutDuration))
+			if _, err := s.repo.DeleteStale(ctx, before); err != nil && ctx.Err() == nil {
+				log.Printf("Failed to delete stale login attempts: %v", err)
+			}
+		}
+	}
+}
+
+// keys возвращает ключи счетчиков попытки входа: учетной записи и IP-адреса
+func (s *Service) keys(ctx context.Context, email string) []string {
+	keys := []string{lockout.AccountKey(email)}
+	if ip, ok := auth.GetClientIP(ctx); ok {
+		keys = append(keys, lockout.IPKey(ip))
+	}
+	return keys
+}
+
+// delay возвращает задержку ответа после failures неудач подряд
+func (s *Service) delay(failures int) time.Duration {
+	if failures <= 0 || s.cfg.BaseDelay <= 0 {
+		return 0
+	}
+	d := s.cfg.BaseDelay
+	for i := 1; i < failures && d < s.cfg.MaxDelay; i++ {
+		d *= 2
+	}
+	return min(d, s.cfg.MaxDelay)
+}
+
+// sleep ждет d или отмены ctx
+func sleep(ctx context.Context, d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
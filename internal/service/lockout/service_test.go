@@ -0,0 +1,274 @@
+package lockout
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/event"
+	"github.com/avito/pvz/internal/domain/lockout"
+	"github.com/avito/pvz/internal/domain/user"
+	"github.com/avito/pvz/pkg/auth"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryRepository хранит счетчики в памяти
+type memoryRepository struct {
+	counters map[string]lockout.Counter
+}
+
+func newMemoryRepository() *memoryRepository {
+	return &memoryRepository{counters: make(map[string]lockout.Counter)}
+}
+
+func (r *memoryRepository) Get(_ context.Context, key string) (*lockout.Counter, error) {
+	c, ok := r.counters[key]
+	if !ok {
+		return nil, lockout.ErrNotFound
+	}
+	return &c, nil
+}
+
+func (r *memoryRepository) Lock(_ context.Context, key string, now time.Time) (*lockout.Counter, error) {
+	c, ok := r.counters[key]
+	if !ok {
+		c = lockout.Counter{Key: key, LastFailureAt: now}
+		r.counters[key] = c
+	}
+	return &c, nil
+}
+
+func (r *memoryRepository) Save(_ context.Context, c *lockout.Counter) error {
+	r.counters[c.Key] = *c
+	return nil
+}
+
+func (r *memoryRepository) Delete(_ context.Context, key string) error {
+	delete(r.counters, key)
+	return nil
+}
+
+func (r *memoryRepository) DeleteStale(context.Context, time.Time) (int64, error) {
+	return 0, nil
+}
+
+// MockUserRepository мок для user.Repository
+type MockUserRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserRepository) Create(ctx context.Context, u *user.User) error {
+	args := m.Called(ctx, u)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) GetByID(ctx context.Context, id uuid.UUID) (*user.User, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetByEmail(ctx context.Context, email string) (*user.User, error) {
+	args := m.Called(ctx, email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.User), args.Error(1)
+}
+
+func (m *MockUserRepository) Update(ctx context.Context, u *user.User) error {
+	args := m.Called(ctx, u)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) List(ctx context.Context, offset, limit int) ([]*user.User, error) {
+	args := m.Called(ctx, offset, limit)
+	return args.Get(0).([]*user.User), args.Error(1)
+}
+
+// fakeTxManager выполняет функцию без транзакции
+type fakeTxManager struct{}
+
+func (fakeTxManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// recordingBus запоминает события, переданные в шину
+type recordingBus struct {
+	events []event.Domain
+}
+
+func (b *recordingBus) Raise(_ context.Context, events ...event.Domain) {
+	b.events = append(b.events, events...)
+}
+
+// testService сервис с хранилищем в памяти, управляемым временем и записью задержек
+type testService struct {
+	*Service
+	repo   *memoryRepository
+	users  *MockUserRepository
+	bus    *recordingBus
+	clock  time.Time
+	delays []time.Duration
+}
+
+func newTestService(cfg Config) *testService {
+	ts := &testService{
+		repo:  newMemoryRepository(),
+		users: new(MockUserRepository),
+		bus:   new(recordingBus),
+		clock: time.Date(2026, time.October, 1, 12, 0, 0, 0, time.UTC),
+	}
+	ts.Service = New(ts.repo, ts.users, fakeTxManager{}, ts.bus, cfg)
+	ts.now = func() time.Time { return ts.clock }
+	ts.sleep = func(_ context.Context, d time.Duration) { ts.delays = append(ts.delays, d) }
+	return ts
+}
+
+var testConfig = Config{
+	MaxAccountFailures: 3,
+	MaxIPFailures:      5,
+	Window:             15 * time.Minute,
+	LockoutDuration:    10 * time.Minute,
+	BaseDelay:          100 * time.Millisecond,
+	MaxDelay:           300 * time.Millisecond,
+	CleanupInterval:    time.Hour,
+}
+
+func TestService_AccountLockout(t *testing.T) {
+	s := newTestService(testConfig)
+	userID := uuid.New()
+	ctx := auth.WithClientIP(context.Background(), "10.0.0.1")
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, s.Check(ctx, "user@example.com"))
+		require.NoError(t, s.Failed(ctx, "User@Example.com", userID))
+	}
+
+	// Задержка растет с каждой неудачей до MaxDelay
+	assert.Equal(t, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond}, s.delays)
+
+	err := s.Check(ctx, "user@example.com")
+	var locked *lockout.LockedError
+	require.ErrorAs(t, err, &locked)
+	assert.Equal(t, s.clock.Add(10*time.Minute), locked.Until)
+
+	// Блокировка учетной записи действует и с другого адреса
+	assert.ErrorIs(t, s.Check(auth.WithClientIP(context.Background(), "10.0.0.2"), "user@example.com"), lockout.ErrLocked)
+	// Другая учетная запись с того же адреса не заблокирована
+	assert.NoError(t, s.Check(ctx, "other@example.com"))
+
+	require.Len(t, s.bus.events, 1)
+	lockedEvent := s.bus.events[0].(event.LoginLocked)
+	assert.Equal(t, "account:user@example.com", lockedEvent.Key)
+	assert.Equal(t, userID, lockedEvent.UserID)
+
+	// После окончания блокировки отсчет начинается заново
+	s.clock = s.clock.Add(10 * time.Minute)
+	assert.NoError(t, s.Check(ctx, "user@example.com"))
+	require.NoError(t, s.Failed(ctx, "user@example.com", userID))
+	assert.NoError(t, s.Check(ctx, "user@example.com"))
+}
+
+func TestService_UnknownEmailLockedLikeExisting(t *testing.T) {
+	s := newTestService(testConfig)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, s.Failed(ctx, "nobody@example.com", uuid.Nil))
+	}
+
+	assert.ErrorIs(t, s.Check(ctx, "nobody@example.com"), lockout.ErrLocked)
+}
+
+func TestService_IPLockout(t *testing.T) {
+	s := newTestService(testConfig)
+	ctx := auth.WithClientIP(context.Background(), "10.0.0.1")
+
+	// Перебор разных учетных записей с одного адреса
+	for i := 0; i < 5; i++ {
+		require.NoError(t, s.Failed(ctx, uuid.NewString()+"@example.com", uuid.Nil))
+	}
+
+	assert.ErrorIs(t, s.Check(ctx, "user@example.com"), lockout.ErrLocked)
+	assert.NoError(t, s.Check(auth.WithClientIP(context.Background(), "10.0.0.2"), "user@example.com"))
+}
+
+func TestService_FailuresOutsideWindow(t *testing.T) {
+	s := newTestService(testConfig)
+	ctx := context.Background()
+
+	require.NoError(t, s.Failed(ctx, "user@example.com", uuid.Nil))
+	require.NoError(t, s.Failed(ctx, "user@example.com", uuid.Nil))
+	s.clock = s.clock.Add(16 * time.Minute)
+	require.NoError(t, s.Failed(ctx, "user@example.com", uuid.Nil))
+
+	assert.NoError(t, s.Check(ctx, "user@example.com"))
+	assert.Equal(t, 1, s.repo.counters["account:user@example.com"].Failures)
+}
+
+func TestService_Succeeded(t *testing.T) {
+	s := newTestService(testConfig)
+	ctx := auth.WithClientIP(context.Background(), "10.0.0.1")
+
+	require.NoError(t, s.Failed(ctx, "user@example.com", uuid.Nil))
+	require.NoError(t, s.Succeeded(ctx, "user@example.com"))
+
+	assert.NotContains(t, s.repo.counters, "account:user@example.com")
+	// Счетчик адреса сохраняется
+	assert.Contains(t, s.repo.counters, "ip:10.0.0.1")
+}
+
+func TestService_Unlock(t *testing.T) {
+	userID, moderatorID := uuid.New(), uuid.New()
+	moderatorCtx := auth.WithUserID(context.Background(), moderatorID)
+
+	t.Run("модератор снимает блокировку учетной записи", func(t *testing.T) {
+		s := newTestService(testConfig)
+		for i := 0; i < 3; i++ {
+			require.NoError(t, s.Failed(context.Background(), "user@example.com", userID))
+		}
+		s.users.On("GetByID", moderatorCtx, userID).Return(&user.User{ID: userID, Email: "user@example.com"}, nil)
+
+		require.NoError(t, s.Unlock(moderatorCtx, userID))
+
+		assert.NoError(t, s.Check(context.Background(), "user@example.com"))
+		unlocked := s.bus.events[len(s.bus.events)-1].(event.LoginUnlocked)
+		assert.Equal(t, moderatorID, unlocked.UnlockedBy)
+		assert.Equal(t, userID, unlocked.UserID)
+	})
+
+	t.Run("пользователь не найден", func(t *testing.T) {
+		s := newTestService(testConfig)
+		s.users.On("GetByID", moderatorCtx, userID).Return(nil, user.ErrNotFound)
+
+		assert.ErrorIs(t, s.Unlock(moderatorCtx, userID), user.ErrNotFound)
+	})
+
+	t.Run("снятие блокировки адреса", func(t *testing.T) {
+		s := newTestService(testConfig)
+		ipCtx := auth.WithClientIP(context.Background(), "10.0.0.1")
+		for i := 0; i < 5; i++ {
+			require.NoError(t, s.Failed(ipCtx, uuid.NewString()+"@example.com", uuid.Nil))
+		}
+
+		require.NoError(t, s.UnlockIP(moderatorCtx, "10.0.0.1"))
+		assert.NoError(t, s.Check(ipCtx, "user@example.com"))
+	})
+
+	t.Run("без вызывающего", func(t *testing.T) {
+		s := newTestService(testConfig)
+		assert.True(t, errors.Is(s.UnlockIP(context.Background(), "10.0.0.1"), ErrUnauthorized))
+	})
+}
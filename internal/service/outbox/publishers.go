@@ -0,0 +1,149 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/outbox"
+)
+
+// HeaderMessageID заголовок с ID сообщения, по которому получатель отбрасывает повторы
+const HeaderMessageID = "X-Outbox-Message-Id"
+
+// maxResponseBodySize ограничивает часть тела ответа, включаемую в ошибку
+const maxResponseBodySize = 512
+
+// Envelope сообщение в том виде, в котором его получают внешние системы
+type Envelope struct {
+	ID            string          `json:"id"`
+	Sequence      int64           `json:"sequence"`
+	AggregateType string          `json:"aggregateType"`
+	AggregateID   string          `json:"aggregateId"`
+	EventType     string          `json:"eventType"`
+	Payload       json.RawMessage `json:"payload"`
+	CreatedAt     time.Time       `json:"createdAt"`
+}
+
+// NewEnvelope создает конверт сообщения
+func NewEnvelope(m *outbox.Message) Envelope {
+	return Envelope{
+		ID:            m.ID.String(),
+		Sequence:      m.Sequence,
+		AggregateType: m.AggregateType,
+		AggregateID:   m.AggregateID.String(),
+		EventType:     m.EventType,
+		Payload:       m.Payload,
+		CreatedAt:     m.CreatedAt,
+	}
+}
+
+// MemoryPublisher сохраняет сообщения в памяти. Используется в тестах
+// и при локальном запуске без внешних систем.
+type MemoryPublisher struct {
+	mu       sync.Mutex
+	messages []Envelope
+}
+
+// NewMemoryPublisher создает новый экземпляр MemoryPublisher
+func NewMemoryPublisher() *MemoryPublisher {
+	return &MemoryPublisher{}
+}
+
+// Publish сохраняет сообщение
+func (p *MemoryPublisher) Publish(_ context.Context, m *outbox.Message) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.messages = append(p.messages, NewEnvelope(m))
+	return nil
+}
+
+// Messages возвращает копию опубликованных сообщений
+func (p *MemoryPublisher) Messages() []Envelope {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]Envelope(nil), p.messages...)
+}
+
+// NDJSONPublisher записывает сообщения построчно в формате NDJSON
+type NDJSONPublisher struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewNDJSONPublisher создает новый экземпляр NDJSONPublisher
+func NewNDJSONPublisher(w io.Writer) *NDJSONPublisher {
+	return &NDJSONPublisher{w: w}
+}
+
+// OpenNDJSONFile открывает файл path для дозаписи и возвращает публикатор
+// вместе с файлом, который нужно закрыть при остановке
+func OpenNDJSONFile(path string) (*NDJSONPublisher, *os.File, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, nil, err
+	}
+	return NewNDJSONPublisher(f), f, nil
+}
+
+// Publish записывает сообщение одной строкой
+func (p *NDJSONPublisher) Publish(_ context.Context, m *outbox.Message) error {
+	line, err := json.Marshal(NewEnvelope(m))
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, err = p.w.Write(append(line, '\n'))
+	return err
+}
+
+// HTTPPublisher отправляет сообщения POST-запросом на заданный адрес
+type HTTPPublisher struct {
+	url    string
+	client *http.Client
+}
+
+// NewHTTPPublisher создает новый экземпляр HTTPPublisher.
+// Если client не задан, используется клиент с таймаутом 10 секунд.
+func NewHTTPPublisher(url string, client *http.Client) *HTTPPublisher {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &HTTPPublisher{url: url, client: client}
+}
+
+// Publish отправляет сообщение. Ответ 2xx считается успешной публикацией.
+func (p *HTTPPublisher) Publish(ctx context.Context, m *outbox.Message) error {
+	body, err := json.Marshal(NewEnvelope(m))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderMessageID, m.ID.String())
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBodySize))
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, data)
+	}
+
+	return nil
+}
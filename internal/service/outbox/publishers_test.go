@@ -0,0 +1,95 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/outbox"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestMessage(seq int64) *outbox.Message {
+	return &outbox.Message{
+		ID:            uuid.New(),
+		Sequence:      seq,
+		AggregateType: outbox.AggregateReception,
+		AggregateID:   uuid.New(),
+		EventType:     "reception.opened",
+		Payload:       []byte(`{"type":"reception.opened"}`),
+		CreatedAt:     time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestMemoryPublisher(t *testing.T) {
+	p := NewMemoryPublisher()
+	m := newTestMessage(1)
+
+	require.NoError(t, p.Publish(context.Background(), m))
+
+	messages := p.Messages()
+	require.Len(t, messages, 1)
+	assert.Equal(t, m.ID.String(), messages[0].ID)
+	assert.JSONEq(t, `{"type":"reception.opened"}`, string(messages[0].Payload))
+}
+
+func TestNDJSONPublisher(t *testing.T) {
+	var buf bytes.Buffer
+	p := NewNDJSONPublisher(&buf)
+	first, second := newTestMessage(1), newTestMessage(2)
+
+	require.NoError(t, p.Publish(context.Background(), first))
+	require.NoError(t, p.Publish(context.Background(), second))
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	require.Len(t, lines, 2)
+
+	var got Envelope
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &got))
+	assert.Equal(t, second.ID.String(), got.ID)
+	assert.Equal(t, int64(2), got.Sequence)
+	assert.Equal(t, "reception", got.AggregateType)
+}
+
+func TestHTTPPublisher(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		expectedErr bool
+	}{
+		{name: "получатель принял сообщение", status: http.StatusAccepted},
+		{name: "получатель вернул ошибку", status: http.StatusServiceUnavailable, expectedErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestMessage(1)
+			var received Envelope
+			var messageID string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				messageID = r.Header.Get(HeaderMessageID)
+				json.NewDecoder(r.Body).Decode(&received)
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			err := NewHTTPPublisher(server.URL, server.Client()).Publish(context.Background(), m)
+
+			if tt.expectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, m.ID.String(), messageID)
+			assert.Equal(t, m.ID.String(), received.ID)
+			assert.Equal(t, "reception.opened", received.EventType)
+		})
+	}
+}
@@ -0,0 +1,190 @@
+// Package outbox публикует сообщения, записанные сервисами в таблицу outbox
+// в одной транзакции с изменением данных. Сообщение отмечается опубликованным
+// только после успешной публикации, поэтому доставка гарантируется хотя бы
+// один раз. Сообщения одного агрегата публикуются строго по порядку записи:
+// после неудачи остальные сообщения агрегата ждут повторной попытки, которая
+// откладывается с экспоненциальной задержкой. Сообщение, исчерпавшее попытки,
+// переносится в dead-letter, и публикация сообщений агрегата продолжается.
+package outbox
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/outbox"
+	"github.com/avito/pvz/internal/domain/transaction"
+	"github.com/google/uuid"
+)
+
+// maxErrorLength ограничивает длину сохраняемой причины неудачи
+const maxErrorLength = 512
+
+// Config параметры публикации сообщений
+type Config struct {
+	// PollInterval период выборки неопубликованных сообщений
+	PollInterval time.Duration
+	// BatchSize число сообщений, публикуемых за один проход
+	BatchSize int
+	// MaxAttempts число попыток, после которого сообщение переносится в dead-letter
+	MaxAttempts int
+	// BaseBackoff задержка перед второй попыткой, далее она удваивается
+	BaseBackoff time.Duration
+	// MaxBackoff верхняя граница задержки между попытками
+	MaxBackoff time.Duration
+	// Retention время хранения опубликованных сообщений
+	Retention time.Duration
+}
+
+// DefaultConfig параметры публикации по умолчанию
+var DefaultConfig = Config{
+	PollInterval: time.Second,
+	BatchSize:    100,
+	MaxAttempts:  20,
+	BaseBackoff:  time.Second,
+	MaxBackoff:   10 * time.Minute,
+	Retention:    7 * 24 * time.Hour,
+}
+
+// Relay переносит сообщения из outbox в publisher
+type Relay struct {
+	repo      outbox.Repository
+	txManager transaction.Manager
+	publisher outbox.Publisher
+	cfg       Config
+	now       func() time.Time
+}
+
+// NewRelay создает новый экземпляр Relay
+func NewRelay(repo outbox.Repository, txManager transaction.Manager, publisher outbox.Publisher, cfg Config) *Relay {
+	return &Relay{
+		repo:      repo,
+		txManager: txManager,
+		publisher: publisher,
+		cfg:       cfg,
+		now:       time.Now,
+	}
+}
+
+// Run публикует сообщения с периодом PollInterval до отмены ctx.
+// Опубликованные сообщения старше Retention удаляются раз в час.
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.cfg.PollInterval)
+	defer ticker.Stop()
+
+	var lastCleanup time.Time
+	for {
+		if _, err := r.RelayPending(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("failed to relay outbox messages: %v", err)
+		}
+
+		if r.now().Sub(lastCleanup) >= time.Hour {
+			if _, err := r.Cleanup(ctx); err != nil && ctx.Err() == nil {
+				log.Printf("failed to clean up outbox: %v", err)
+			}
+			lastCleanup = r.now()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayPending публикует одну выборку неопубликованных сообщений и возвращает
+// число опубликованных. Выборка обрабатывается в транзакции под блокировкой,
+// поэтому несколько экземпляров приложения не публикуют сообщения одновременно.
+func (r *Relay) RelayPending(ctx context.Context) (int, error) {
+	published := 0
+
+	err := r.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		locked, err := r.repo.TryLock(ctx)
+		if err != nil {
+			return err
+		}
+		if !locked {
+			// Сообщения публикует другой экземпляр
+			return nil
+		}
+
+		messages, err := r.repo.ListPending(ctx, r.now(), r.cfg.BatchSize)
+		if err != nil {
+			return err
+		}
+
+		// blocked агрегаты, сообщение которых не удалось опубликовать в этом проходе
+		blocked := make(map[uuid.UUID]bool)
+		for _, m := range messages {
+			if blocked[m.AggregateID] {
+				continue
+			}
+
+			if err := r.publisher.Publish(ctx, m); err != nil {
+				if err := r.fail(ctx, m, err); err != nil {
+					return err
+				}
+				if m.DeadAt == nil {
+					blocked[m.AggregateID] = true
+				}
+				continue
+			}
+
+			if err := r.repo.MarkPublished(ctx, m.ID, r.now()); err != nil {
+				return err
+			}
+			published++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return published, nil
+}
+
+// fail записывает неудачную попытку публикации сообщения. После MaxAttempts
+// попыток сообщение переносится в dead-letter и больше не публикуется.
+func (r *Relay) fail(ctx context.Context, m *outbox.Message, publishErr error) error {
+	now := r.now()
+	m.Attempts++
+	m.LastError = truncate(publishErr.Error())
+
+	if m.Attempts >= r.cfg.MaxAttempts {
+		m.DeadAt = &now
+		log.Printf("outbox message %s moved to dead-letter after %d attempts: %s", m.ID, m.Attempts, m.LastError)
+		return r.repo.MarkDead(ctx, m.ID, m.LastError, now)
+	}
+
+	next := now.Add(r.backoff(m.Attempts))
+	m.NextAttemptAt = &next
+	return r.repo.MarkFailed(ctx, m.ID, m.LastError, next)
+}
+
+// backoff возвращает задержку перед следующей попыткой после attempts неудачных
+func (r *Relay) backoff(attempts int) time.Duration {
+	delay := r.cfg.BaseBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= r.cfg.MaxBackoff {
+			return r.cfg.MaxBackoff
+		}
+	}
+	return delay
+}
+
+// Cleanup удаляет сообщения, опубликованные раньше Retention, и возвращает их число
+func (r *Relay) Cleanup(ctx context.Context) (int64, error) {
+	return r.repo.DeletePublishedBefore(ctx, r.now().Add(-r.cfg.Retention))
+}
+
+// truncate обрезает причину неудачи до maxErrorLength байт
+func truncate(s string) string {
+	if len(s) <= maxErrorLength {
+		return s
+	}
+	return s[:maxErrorLength]
+}
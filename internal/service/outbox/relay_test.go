@@ -0,0 +1,223 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/outbox"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockRepository мок для outbox.Repository
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) Add(ctx context.Context, messages ...*outbox.Message) error {
+	args := m.Called(ctx, messages)
+	return args.Error(0)
+}
+
+func (m *MockRepository) TryLock(ctx context.Context) (bool, error) {
+	args := m.Called(ctx)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRepository) ListPending(ctx context.Context, now time.Time, limit int) ([]*outbox.Message, error) {
+	args := m.Called(ctx, now, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*outbox.Message), args.Error(1)
+}
+
+func (m *MockRepository) MarkPublished(ctx context.Context, id uuid.UUID, at time.Time) error {
+	args := m.Called(ctx, id, at)
+	return args.Error(0)
+}
+
+func (m *MockRepository) MarkFailed(ctx context.Context, id uuid.UUID, reason string, nextAttemptAt time.Time) error {
+	args := m.Called(ctx, id, reason, nextAttemptAt)
+	return args.Error(0)
+}
+
+func (m *MockRepository) MarkDead(ctx context.Context, id uuid.UUID, reason string, at time.Time) error {
+	args := m.Called(ctx, id, reason, at)
+	return args.Error(0)
+}
+
+func (m *MockRepository) DeletePublishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// txKey отмечает контекст, переданный в функцию транзакции
+type txKey struct{}
+
+// fakeTxManager выполняет функцию с отмеченным контекстом
+type fakeTxManager struct{}
+
+func (fakeTxManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(context.WithValue(ctx, txKey{}, true))
+}
+
+// inTx проверяет, что вызов репозитория выполнен в транзакции
+var inTx = mock.MatchedBy(func(ctx context.Context) bool {
+	return ctx.Value(txKey{}) == true
+})
+
+// failingPublisher не публикует сообщения с ID из failed
+type failingPublisher struct {
+	failed    map[uuid.UUID]bool
+	published []uuid.UUID
+}
+
+func (p *failingPublisher) Publish(_ context.Context, m *outbox.Message) error {
+	if p.failed[m.ID] {
+		return errors.New("broker unavailable")
+	}
+	p.published = append(p.published, m.ID)
+	return nil
+}
+
+func TestRelay_RelayPending(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	first, second := uuid.New(), uuid.New()
+	a1 := &outbox.Message{ID: uuid.New(), Sequence: 1, AggregateID: first}
+	b1 := &outbox.Message{ID: uuid.New(), Sequence: 2, AggregateID: second}
+	a2 := &outbox.Message{ID: uuid.New(), Sequence: 3, AggregateID: first}
+	b2 := &outbox.Message{ID: uuid.New(), Sequence: 4, AggregateID: second}
+
+	tests := []struct {
+		name              string
+		messages          func() []*outbox.Message
+		failed            map[uuid.UUID]bool
+		setupMock         func(*MockRepository)
+		expectedPublished []uuid.UUID
+		expectedCount     int
+		expectedErr       bool
+	}{
+		{
+			name: "все сообщения опубликованы по порядку",
+			setupMock: func(m *MockRepository) {
+				m.On("TryLock", inTx).Return(true, nil)
+				m.On("ListPending", inTx, now, 10).Return([]*outbox.Message{a1, b1, a2, b2}, nil)
+				for _, msg := range []*outbox.Message{a1, b1, a2, b2} {
+					m.On("MarkPublished", inTx, msg.ID, now).Return(nil).Once()
+				}
+			},
+			expectedPublished: []uuid.UUID{a1.ID, b1.ID, a2.ID, b2.ID},
+			expectedCount:     4,
+		},
+		{
+			name:   "после неудачи остальные сообщения агрегата ждут",
+			failed: map[uuid.UUID]bool{a1.ID: true},
+			setupMock: func(m *MockRepository) {
+				m.On("TryLock", inTx).Return(true, nil)
+				m.On("ListPending", inTx, now, 10).Return([]*outbox.Message{a1, b1, a2, b2}, nil)
+				m.On("MarkFailed", inTx, a1.ID, "broker unavailable", now.Add(time.Second)).Return(nil).Once()
+				m.On("MarkPublished", inTx, b1.ID, now).Return(nil).Once()
+				m.On("MarkPublished", inTx, b2.ID, now).Return(nil).Once()
+			},
+			expectedPublished: []uuid.UUID{b1.ID, b2.ID},
+			expectedCount:     2,
+		},
+		{
+			name: "задержка повтора удваивается до максимальной",
+			messages: func() []*outbox.Message {
+				return []*outbox.Message{
+					{ID: a1.ID, Sequence: 1, AggregateID: first, Attempts: 2},
+					{ID: b1.ID, Sequence: 2, AggregateID: second, Attempts: 5},
+				}
+			},
+			failed: map[uuid.UUID]bool{a1.ID: true, b1.ID: true},
+			setupMock: func(m *MockRepository) {
+				m.On("TryLock", inTx).Return(true, nil)
+				m.On("MarkFailed", inTx, a1.ID, "broker unavailable", now.Add(4*time.Second)).Return(nil).Once()
+				m.On("MarkFailed", inTx, b1.ID, "broker unavailable", now.Add(10*time.Second)).Return(nil).Once()
+			},
+		},
+		{
+			name: "исчерпавшее попытки сообщение переносится в dead-letter",
+			messages: func() []*outbox.Message {
+				return []*outbox.Message{
+					{ID: a1.ID, Sequence: 1, AggregateID: first, Attempts: 9},
+					a2,
+				}
+			},
+			failed: map[uuid.UUID]bool{a1.ID: true},
+			setupMock: func(m *MockRepository) {
+				m.On("TryLock", inTx).Return(true, nil)
+				m.On("MarkDead", inTx, a1.ID, "broker unavailable", now).Return(nil).Once()
+				m.On("MarkPublished", inTx, a2.ID, now).Return(nil).Once()
+			},
+			expectedPublished: []uuid.UUID{a2.ID},
+			expectedCount:     1,
+		},
+		{
+			name: "сообщения публикует другой экземпляр",
+			setupMock: func(m *MockRepository) {
+				m.On("TryLock", inTx).Return(false, nil)
+			},
+		},
+		{
+			name: "ошибка выборки",
+			setupMock: func(m *MockRepository) {
+				m.On("TryLock", inTx).Return(true, nil)
+				m.On("ListPending", inTx, now, 10).Return(nil, errors.New("db error"))
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockRepository)
+			if tt.messages != nil {
+				repo.On("ListPending", inTx, now, 10).Return(tt.messages(), nil)
+			}
+			tt.setupMock(repo)
+			publisher := &failingPublisher{failed: tt.failed}
+
+			relay := NewRelay(repo, fakeTxManager{}, publisher, Config{
+				PollInterval: time.Second,
+				BatchSize:    10,
+				MaxAttempts:  10,
+				BaseBackoff:  time.Second,
+				MaxBackoff:   10 * time.Second,
+			})
+			relay.now = func() time.Time { return now }
+
+			count, err := relay.RelayPending(context.Background())
+
+			if tt.expectedErr {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectedCount, count)
+			assert.Equal(t, tt.expectedPublished, publisher.published)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRelay_Cleanup(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	repo := new(MockRepository)
+	repo.On("DeletePublishedBefore", mock.Anything, now.Add(-24*time.Hour)).Return(int64(3), nil)
+
+	relay := NewRelay(repo, fakeTxManager{}, NewMemoryPublisher(), Config{Retention: 24 * time.Hour})
+	relay.now = func() time.Time { return now }
+
+	deleted, err := relay.Cleanup(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), deleted)
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,157 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"unicode"
+
+	"github.com/avito/pvz/internal/domain/event"
+	"github.com/avito/pvz/internal/domain/product"
+	"github.com/avito/pvz/internal/domain/reception"
+	"github.com/google/uuid"
+)
+
+const (
+	// MaxImportRows максимальное количество товаров в одном файле импорта
+	MaxImportRows = 5000
+	// maxBarcodeLength максимальная длина штрихкода
+	maxBarcodeLength = 64
+	// maxMetadataLength максимальная длина метаданных товара
+	maxMetadataLength = 1024
+)
+
+var (
+	ErrEmptyImport    = errors.New("import file has no rows")
+	ErrImportTooLarge = errors.New("import file has too many rows")
+)
+
+// ImportRow строка файла поставщика
+type ImportRow struct {
+	// Line номер строки в исходном файле, используется в отчете об ошибках
+	Line     int
+	Type     string
+	Barcode  string
+	Metadata string
+}
+
+// RowError описывает ошибку в строке файла импорта.
+// Code — стабильный код ошибки, по которому обработчик подбирает перевод Message.
+type RowError struct {
+	Line    int    `json:"line"`
+	Field   string `json:"field,omitempty"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ImportResult отчет об импорте товаров
+type ImportResult struct {
+	Total    int        `json:"total"`
+	Imported int        `json:"imported"`
+	DryRun   bool       `json:"dry_run"`
+	Errors   []RowError `json:"errors,omitempty"`
+}
+
+// Import проверяет все строки файла и добавляет товары в приемку одной транзакцией.
+// Если хотя бы одна строка не прошла проверку, ничего не добавляется и в отчете
+// перечисляются ошибки по строкам. В режиме dryRun строки только проверяются.
+func (s *Service) Import(ctx context.Context, receptionID uuid.UUID, rows []ImportRow, dryRun bool) (*ImportResult, error) {
+	if len(rows) == 0 {
+		return nil, ErrEmptyImport
+	}
+	if len(rows) > MaxImportRows {
+		return nil, ErrImportTooLarge
+	}
+
+	result := &ImportResult{
+		Total:  len(rows),
+		DryRun: dryRun,
+		Errors: validateImportRows(rows),
+	}
+
+	var events []event.Feed
+
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		// Приемка блокируется до конца транзакции, чтобы события ее товаров
+		// фиксировались в порядке записи в outbox
+		r, err := s.receptionRepo.GetByIDForUpdate(ctx, receptionID)
+		if err != nil {
+			return ErrReceptionNotFound
+		}
+		if err := s.access.CheckPVZ(ctx, r.PVZID); err != nil {
+			return err
+		}
+
+		// Проверяем статус приемки
+		if r.Status == reception.StatusClose {
+			return ErrReceptionAlreadyClose
+		}
+
+		if dryRun || len(result.Errors) > 0 {
+			return nil
+		}
+
+		products := make([]*product.Product, len(rows))
+		for i, row := range rows {
+			p := product.New(receptionID, product.Type(row.Type))
+			p.Barcode = row.Barcode
+			p.Metadata = row.Metadata
+			products[i] = p
+		}
+
+		if err := s.productRepo.CreateBatch(ctx, products); err != nil {
+			return err
+		}
+
+		events = addedEvents(r.PVZID, products)
+		if err := s.record(ctx, events...); err != nil {
+			return err
+		}
+		if err := s.recordAdded(ctx, products...); err != nil {
+			return err
+		}
+
+		result.Imported = len(products)
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.publish(ctx, events)
+	return result, nil
+}
+
+// validateImportRows проверяет строки файла и возвращает ошибки по каждой из них
+func validateImportRows(rows []ImportRow) []RowError {
+	var errs []RowError
+
+	for _, row := range rows {
+		if err := validateProductType(product.Type(row.Type)); err != nil {
+			errs = append(errs, RowError{Line: row.Line, Field: "type", Code: "invalid_product_type", Message: "неверный тип товара"})
+		}
+
+		switch {
+		case row.Barcode == "":
+			errs = append(errs, RowError{Line: row.Line, Field: "barcode", Code: "empty_barcode", Message: "штрихкод не может быть пустым"})
+		case len(row.Barcode) > maxBarcodeLength || !isBarcode(row.Barcode):
+			errs = append(errs, RowError{Line: row.Line, Field: "barcode", Code: "invalid_barcode", Message: "неверный формат штрихкода"})
+		}
+
+		if len(row.Metadata) > maxMetadataLength {
+			errs = append(errs, RowError{Line: row.Line, Field: "metadata", Code: "metadata_too_long", Message: "слишком длинные метаданные"})
+		}
+	}
+
+	return errs
+}
+
+// isBarcode проверяет, что штрихкод состоит только из латинских букв, цифр и дефисов
+func isBarcode(s string) bool {
+	for _, r := range s {
+		if r > unicode.MaxASCII || !(unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-') {
+			return false
+		}
+	}
+	return true
+}
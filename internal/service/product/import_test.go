@@ -0,0 +1,146 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/avito/pvz/internal/domain/assignment"
+	"github.com/avito/pvz/internal/domain/audit"
+	"github.com/avito/pvz/internal/domain/event"
+	"github.com/avito/pvz/internal/domain/outbox"
+	"github.com/avito/pvz/internal/domain/product"
+	"github.com/avito/pvz/internal/domain/reception"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestService_Import(t *testing.T) {
+	validRows := []ImportRow{
+		{Line: 2, Type: "electronics", Barcode: "4600000000001", Metadata: "партия 7"},
+		{Line: 3, Type: "food", Barcode: "4600000000002"},
+	}
+
+	runTx := func(tx *MockTransactionManager, result error) {
+		tx.On("WithinTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Run(func(args mock.Arguments) {
+			fn := args.Get(1).(func(context.Context) error)
+			fn(context.Background())
+		}).Return(result)
+	}
+
+	tests := []struct {
+		name          string
+		rows          []ImportRow
+		dryRun        bool
+		setupMocks    func(*MockProductRepository, *MockReceptionRepository, *MockTransactionManager)
+		expected      *ImportResult
+		expectedError error
+	}{
+		{
+			name: "успешный импорт",
+			rows: validRows,
+			setupMocks: func(productRepo *MockProductRepository, receptionRepo *MockReceptionRepository, tx *MockTransactionManager) {
+				receptionRepo.On("GetByIDForUpdate", mock.Anything, mock.AnythingOfType("uuid.UUID")).Return(&reception.Reception{Status: reception.StatusInProgress}, nil)
+				runTx(tx, nil)
+				productRepo.On("CreateBatch", mock.Anything, mock.MatchedBy(func(products []*product.Product) bool {
+					return len(products) == 2 &&
+						products[0].Type == product.TypeElectronics &&
+						products[0].Barcode == "4600000000001" &&
+						products[0].Metadata == "партия 7" &&
+						products[1].Barcode == "4600000000002"
+				})).Return(nil)
+			},
+			expected: &ImportResult{Total: 2, Imported: 2},
+		},
+		{
+			name:   "проверка без сохранения",
+			rows:   validRows,
+			dryRun: true,
+			setupMocks: func(productRepo *MockProductRepository, receptionRepo *MockReceptionRepository, tx *MockTransactionManager) {
+				receptionRepo.On("GetByIDForUpdate", mock.Anything, mock.AnythingOfType("uuid.UUID")).Return(&reception.Reception{Status: reception.StatusInProgress}, nil)
+				runTx(tx, nil)
+			},
+			expected: &ImportResult{Total: 2, DryRun: true},
+		},
+		{
+			name: "ошибки в строках",
+			rows: []ImportRow{
+				{Line: 2, Type: "electronics", Barcode: "4600000000001"},
+				{Line: 3, Type: "weapons", Barcode: ""},
+				{Line: 4, Type: "food", Barcode: "46 00", Metadata: strings.Repeat("x", maxMetadataLength+1)},
+			},
+			setupMocks: func(productRepo *MockProductRepository, receptionRepo *MockReceptionRepository, tx *MockTransactionManager) {
+				receptionRepo.On("GetByIDForUpdate", mock.Anything, mock.AnythingOfType("uuid.UUID")).Return(&reception.Reception{Status: reception.StatusInProgress}, nil)
+				runTx(tx, nil)
+			},
+			expected: &ImportResult{
+				Total: 3,
+				Errors: []RowError{
+					{Line: 3, Field: "type", Code: "invalid_product_type", Message: "неверный тип товара"},
+					{Line: 3, Field: "barcode", Code: "empty_barcode", Message: "штрихкод не может быть пустым"},
+					{Line: 4, Field: "barcode", Code: "invalid_barcode", Message: "неверный формат штрихкода"},
+					{Line: 4, Field: "metadata", Code: "metadata_too_long", Message: "слишком длинные метаданные"},
+				},
+			},
+		},
+		{
+			name: "приемка закрыта",
+			rows: validRows,
+			setupMocks: func(productRepo *MockProductRepository, receptionRepo *MockReceptionRepository, tx *MockTransactionManager) {
+				receptionRepo.On("GetByIDForUpdate", mock.Anything, mock.AnythingOfType("uuid.UUID")).Return(&reception.Reception{Status: reception.StatusClose}, nil)
+				runTx(tx, ErrReceptionAlreadyClose)
+			},
+			expectedError: ErrReceptionAlreadyClose,
+		},
+		{
+			name: "ошибка сохранения",
+			rows: validRows,
+			setupMocks: func(productRepo *MockProductRepository, receptionRepo *MockReceptionRepository, tx *MockTransactionManager) {
+				receptionRepo.On("GetByIDForUpdate", mock.Anything, mock.AnythingOfType("uuid.UUID")).Return(&reception.Reception{Status: reception.StatusInProgress}, nil)
+				runTx(tx, errors.New("database error"))
+				productRepo.On("CreateBatch", mock.Anything, mock.Anything).Return(errors.New("database error"))
+			},
+			expectedError: errors.New("database error"),
+		},
+		{
+			name: "пустой файл",
+			rows: nil,
+			setupMocks: func(productRepo *MockProductRepository, receptionRepo *MockReceptionRepository, tx *MockTransactionManager) {
+			},
+			expectedError: ErrEmptyImport,
+		},
+		{
+			name: "слишком много строк",
+			rows: make([]ImportRow, MaxImportRows+1),
+			setupMocks: func(productRepo *MockProductRepository, receptionRepo *MockReceptionRepository, tx *MockTransactionManager) {
+			},
+			expectedError: ErrImportTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			productRepo := new(MockProductRepository)
+			receptionRepo := new(MockReceptionRepository)
+			tx := new(MockTransactionManager)
+			tt.setupMocks(productRepo, receptionRepo, tx)
+
+			service := New(productRepo, receptionRepo, tx, event.Discard, outbox.Discard, audit.Discard, assignment.AllowAll)
+			result, err := service.Import(context.Background(), uuid.New(), tt.rows, tt.dryRun)
+
+			if tt.expectedError != nil {
+				assert.Equal(t, tt.expectedError, err)
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, result)
+			}
+
+			productRepo.AssertExpectations(t)
+			receptionRepo.AssertExpectations(t)
+			tx.AssertExpectations(t)
+		})
+	}
+}
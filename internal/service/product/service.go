@@ -5,9 +5,14 @@ import (
 	"errors"
 	"time"
 
+	"github.com/avito/pvz/internal/domain/assignment"
+	"github.com/avito/pvz/internal/domain/audit"
+	"github.com/avito/pvz/internal/domain/event"
+	"github.com/avito/pvz/internal/domain/outbox"
 	"github.com/avito/pvz/internal/domain/product"
 	"github.com/avito/pvz/internal/domain/reception"
 	"github.com/avito/pvz/internal/domain/transaction"
+	"github.com/avito/pvz/pkg/auth"
 	"github.com/google/uuid"
 )
 
@@ -23,27 +28,44 @@ type Service struct {
 	productRepo   product.Repository
 	receptionRepo reception.Repository
 	txManager     transaction.Manager
+	events        event.Bus
+	outbox        outbox.Writer
+	audit         audit.Writer
+	access        assignment.Access
 }
 
-// New создает новый экземпляр Service
-func New(productRepo product.Repository, receptionRepo reception.Repository, txManager transaction.Manager) *Service {
+// New создает новый экземпляр Service.
+// События о добавлении и удалении товаров записываются в outbox в той же транзакции,
+// что и изменение товаров, и передаются в events после ее фиксации.
+// В той же транзакции добавленные и удаленные товары записываются в журнал audit.
+// Изменять товары приемки может только вызывающий, которому ПВЗ приемки доступен по access.
+func New(productRepo product.Repository, receptionRepo reception.Repository, txManager transaction.Manager, events event.Bus, outbox outbox.Writer, audit audit.Writer, access assignment.Access) *Service {
 	return &Service{
 		productRepo:   productRepo,
 		receptionRepo: receptionRepo,
 		txManager:     txManager,
+		events:        events,
+		outbox:        outbox,
+		audit:         audit,
+		access:        access,
 	}
 }
 
 // Create создает новый товар
 func (s *Service) Create(ctx context.Context, receptionID uuid.UUID, productType product.Type) (*product.Product, error) {
 	var result *product.Product
+	var events []event.Feed
 
 	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
-		// Проверяем существование приемки
-		r, err := s.receptionRepo.GetByID(ctx, receptionID)
+		// Приемка блокируется до конца транзакции, чтобы события ее товаров
+		// фиксировались в порядке записи в outbox
+		r, err := s.receptionRepo.GetByIDForUpdate(ctx, receptionID)
 		if err != nil {
 			return ErrReceptionNotFound
 		}
+		if err := s.access.CheckPVZ(ctx, r.PVZID); err != nil {
+			return err
+		}
 
 		// Проверяем статус приемки
 		if r.Status == reception.StatusClose {
@@ -60,6 +82,14 @@ func (s *Service) Create(ctx context.Context, receptionID uuid.UUID, productType
 			return err
 		}
 
+		events = []event.Feed{addedEvent(r.PVZID, newProduct)}
+		if err := s.record(ctx, events...); err != nil {
+			return err
+		}
+		if err := s.recordAdded(ctx, newProduct); err != nil {
+			return err
+		}
+
 		result = newProduct
 		return nil
 	})
@@ -68,17 +98,24 @@ func (s *Service) Create(ctx context.Context, receptionID uuid.UUID, productType
 		return nil, err
 	}
 
+	s.publish(ctx, events)
 	return result, nil
 }
 
 // CreateBatch создает несколько товаров
 func (s *Service) CreateBatch(ctx context.Context, receptionID uuid.UUID, productTypes []product.Type) error {
-	return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
-		// Проверяем существование приемки
-		r, err := s.receptionRepo.GetByID(ctx, receptionID)
+	var events []event.Feed
+
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		// Приемка блокируется до конца транзакции, чтобы события ее товаров
+		// фиксировались в порядке записи в outbox
+		r, err := s.receptionRepo.GetByIDForUpdate(ctx, receptionID)
 		if err != nil {
 			return ErrReceptionNotFound
 		}
+		if err := s.access.CheckPVZ(ctx, r.PVZID); err != nil {
+			return err
+		}
 
 		// Проверяем статус приемки
 		if r.Status == reception.StatusClose {
@@ -93,31 +130,153 @@ func (s *Service) CreateBatch(ctx context.Context, receptionID uuid.UUID, produc
 		}
 
 		// Создаем товары
-		products := make([]*product.Product, len(productTypes))
+		batch := make([]*product.Product, len(productTypes))
 		for i, t := range productTypes {
-			products[i] = product.New(receptionID, t)
+			batch[i] = product.New(receptionID, t)
 		}
 
-		return s.productRepo.CreateBatch(ctx, products)
+		if err := s.productRepo.CreateBatch(ctx, batch); err != nil {
+			return err
+		}
+
+		events = addedEvents(r.PVZID, batch)
+		if err := s.record(ctx, events...); err != nil {
+			return err
+		}
+		return s.recordAdded(ctx, batch...)
 	})
+	if err != nil {
+		return err
+	}
+
+	s.publish(ctx, events)
+	return nil
 }
 
 // DeleteLast удаляет последний добавленный товар
 func (s *Service) DeleteLast(ctx context.Context, receptionID uuid.UUID) error {
-	return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
-		// Проверяем существование приемки
-		r, err := s.receptionRepo.GetByID(ctx, receptionID)
+	var events []event.Feed
+
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		// Приемка блокируется до конца транзакции, чтобы события ее товаров
+		// фиксировались в порядке записи в outbox
+		r, err := s.receptionRepo.GetByIDForUpdate(ctx, receptionID)
 		if err != nil {
 			return ErrReceptionNotFound
 		}
+		if err := s.access.CheckPVZ(ctx, r.PVZID); err != nil {
+			return err
+		}
 
 		// Проверяем статус приемки
 		if r.Status == reception.StatusClose {
 			return ErrReceptionAlreadyClose
 		}
 
-		return s.productRepo.DeleteLast(ctx, receptionID)
+		// Товар читается до удаления, чтобы сохранить его в журнале аудита
+		last, err := s.productRepo.GetLast(ctx, receptionID)
+		if err != nil {
+			return err
+		}
+		if last == nil {
+			return product.ErrNotFound
+		}
+
+		if err := s.productRepo.DeleteLast(ctx, receptionID); err != nil {
+			return err
+		}
+
+		events = []event.Feed{event.ProductRemoved{
+			PVZID:       r.PVZID,
+			ReceptionID: receptionID,
+			OccurredAt:  time.Now(),
+		}}
+		if err := s.record(ctx, events...); err != nil {
+			return err
+		}
+		return s.recordAudit(ctx, last.ID, audit.ActionDelete, productState(last), nil)
 	})
+	if err != nil {
+		return err
+	}
+
+	s.publish(ctx, events)
+	return nil
+}
+
+// addedEvent создает событие о добавленном товаре
+func addedEvent(pvzID uuid.UUID, p *product.Product) event.ProductAdded {
+	return event.ProductAdded{
+		PVZID:       pvzID,
+		ReceptionID: p.ReceptionID,
+		ProductID:   p.ID,
+		ProductType: p.Type,
+		OccurredAt:  p.DateTime,
+	}
+}
+
+// addedEvents создает события о добавленных товарах в порядке их добавления
+func addedEvents(pvzID uuid.UUID, products []*product.Product) []event.Feed {
+	events := make([]event.Feed, len(products))
+	for i, p := range products {
+		events[i] = addedEvent(pvzID, p)
+	}
+	return events
+}
+
+// record записывает события в outbox транзакции из ctx
+func (s *Service) record(ctx context.Context, events ...event.Feed) error {
+	messages, err := outbox.FromFeed(events...)
+	if err != nil {
+		return err
+	}
+	return s.outbox.Add(ctx, messages...)
+}
+
+// recordAdded записывает добавленные товары в журнал аудита транзакции из ctx
+func (s *Service) recordAdded(ctx context.Context, products ...*product.Product) error {
+	actorID, _ := auth.GetUserID(ctx)
+	entries := make([]*audit.Entry, len(products))
+	for i, p := range products {
+		entry, err := audit.NewEntry(ctx, actorID, audit.EntityProduct, p.ID, audit.ActionCreate, nil, productState(p))
+		if err != nil {
+			return err
+		}
+		entries[i] = entry
+	}
+	return s.audit.Record(ctx, entries...)
+}
+
+// recordAudit записывает изменение товара в журнал аудита транзакции из ctx
+func (s *Service) recordAudit(ctx context.Context, productID uuid.UUID, action audit.Action, before, after audit.State) error {
+	actorID, _ := auth.GetUserID(ctx)
+	entry, err := audit.NewEntry(ctx, actorID, audit.EntityProduct, productID, action, before, after)
+	if err != nil {
+		return err
+	}
+	return s.audit.Record(ctx, entry)
+}
+
+// productState снимок товара для журнала аудита
+func productState(p *product.Product) audit.State {
+	state := audit.State{
+		"receptionId": p.ReceptionID,
+		"dateTime":    p.DateTime,
+		"type":        p.Type,
+	}
+	if p.Barcode != "" {
+		state["barcode"] = p.Barcode
+	}
+	return state
+}
+
+// publish передает события подписчикам после фиксации транзакции
+func (s *Service) publish(ctx context.Context, events []event.Feed) {
+	domain := make([]event.Domain, len(events))
+	for i, e := range events {
+		domain[i] = e
+	}
+	s.events.Raise(ctx, domain...)
 }
 
 // GetByID получает товар по ID
@@ -139,6 +298,14 @@ func (s *Service) List(ctx context.Context, offset, limit int) ([]*product.Produ
 	return s.productRepo.List(ctx, offset, limit)
 }
 
+// ListByFilter возвращает список товаров, отобранных и отсортированных по фильтру
+func (s *Service) ListByFilter(ctx context.Context, filter product.ListFilter) ([]*product.Product, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	return s.productRepo.ListByFilter(ctx, filter)
+}
+
 // validateProductType проверяет корректность типа товара
 func validateProductType(t product.Type) error {
 	switch t {
@@ -159,7 +326,13 @@ func (s *Service) AddProduct(ctx context.Context, receptionID uuid.UUID, product
 	}
 
 	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
-		return s.productRepo.Create(ctx, product)
+		if err := s.checkReception(ctx, receptionID); err != nil {
+			return err
+		}
+		if err := s.productRepo.Create(ctx, product); err != nil {
+			return err
+		}
+		return s.recordAdded(ctx, product)
 	})
 
 	if err != nil {
@@ -182,7 +355,13 @@ func (s *Service) AddProducts(ctx context.Context, receptionID uuid.UUID, types
 	}
 
 	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
-		return s.productRepo.CreateBatch(ctx, products)
+		if err := s.checkReception(ctx, receptionID); err != nil {
+			return err
+		}
+		if err := s.productRepo.CreateBatch(ctx, products); err != nil {
+			return err
+		}
+		return s.recordAdded(ctx, products...)
 	})
 
 	if err != nil {
@@ -195,10 +374,34 @@ func (s *Service) AddProducts(ctx context.Context, receptionID uuid.UUID, types
 // DeleteLastProduct удаляет последний добавленный товар
 func (s *Service) DeleteLastProduct(ctx context.Context, receptionID uuid.UUID) error {
 	return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
-		return s.productRepo.DeleteLast(ctx, receptionID)
+		if err := s.checkReception(ctx, receptionID); err != nil {
+			return err
+		}
+
+		last, err := s.productRepo.GetLast(ctx, receptionID)
+		if err != nil {
+			return err
+		}
+		if last == nil {
+			return product.ErrNotFound
+		}
+
+		if err := s.productRepo.DeleteLast(ctx, receptionID); err != nil {
+			return err
+		}
+		return s.recordAudit(ctx, last.ID, audit.ActionDelete, productState(last), nil)
 	})
 }
 
+// checkReception проверяет, что ПВЗ приемки доступен вызывающему
+func (s *Service) checkReception(ctx context.Context, receptionID uuid.UUID) error {
+	r, err := s.receptionRepo.GetByID(ctx, receptionID)
+	if err != nil {
+		return ErrReceptionNotFound
+	}
+	return s.access.CheckPVZ(ctx, r.PVZID)
+}
+
 // GetProducts получает все товары приемки
 func (s *Service) GetProducts(ctx context.Context, receptionID uuid.UUID) ([]*product.Product, error) {
 	return s.productRepo.GetByReceptionID(ctx, receptionID)
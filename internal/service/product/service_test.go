@@ -5,11 +5,16 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/avito/pvz/internal/domain/assignment"
+	"github.com/avito/pvz/internal/domain/audit"
+	"github.com/avito/pvz/internal/domain/event"
+	"github.com/avito/pvz/internal/domain/outbox"
 	"github.com/avito/pvz/internal/domain/product"
 	"github.com/avito/pvz/internal/domain/reception"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // MockProductRepository реализует мок для product.Repository
@@ -53,6 +58,14 @@ func (m *MockProductRepository) List(ctx context.Context, offset, limit int) ([]
 	return args.Get(0).([]*product.Product), args.Error(1)
 }
 
+func (m *MockProductRepository) ListByFilter(ctx context.Context, filter product.ListFilter) ([]*product.Product, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*product.Product), args.Error(1)
+}
+
 func (m *MockProductRepository) GetLast(ctx context.Context, receptionID uuid.UUID) (*product.Product, error) {
 	args := m.Called(ctx, receptionID)
 	if args.Get(0) == nil {
@@ -92,6 +105,14 @@ func (m *MockReceptionRepository) GetByID(ctx context.Context, id uuid.UUID) (*r
 	return args.Get(0).(*reception.Reception), args.Error(1)
 }
 
+func (m *MockReceptionRepository) GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*reception.Reception, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*reception.Reception), args.Error(1)
+}
+
 func (m *MockReceptionRepository) Update(ctx context.Context, r *reception.Reception) error {
 	args := m.Called(ctx, r)
 	return args.Error(0)
@@ -110,6 +131,14 @@ func (m *MockReceptionRepository) List(ctx context.Context, offset, limit int) (
 	return args.Get(0).([]*reception.Reception), args.Error(1)
 }
 
+func (m *MockReceptionRepository) ListByFilter(ctx context.Context, filter reception.ListFilter) ([]*reception.Reception, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*reception.Reception), args.Error(1)
+}
+
 func (m *MockReceptionRepository) GetOpenByPVZID(ctx context.Context, pvzID uuid.UUID) (*reception.Reception, error) {
 	args := m.Called(ctx, pvzID)
 	if args.Get(0) == nil {
@@ -147,6 +176,15 @@ func (m *MockTransactionManager) WithinTransaction(ctx context.Context, fn func(
 	return args.Error(0)
 }
 
+// recordingBus запоминает события, переданные в шину
+type recordingBus struct {
+	events []event.Domain
+}
+
+func (b *recordingBus) Raise(_ context.Context, events ...event.Domain) {
+	b.events = append(b.events, events...)
+}
+
 func TestService_CreateBatch(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -160,7 +198,7 @@ func TestService_CreateBatch(t *testing.T) {
 			receptionID:  uuid.New(),
 			productTypes: []product.Type{product.TypeElectronics, product.TypeClothing},
 			setupMocks: func(productRepo *MockProductRepository, receptionRepo *MockReceptionRepository, tx *MockTransactionManager) {
-				receptionRepo.On("GetByID", mock.Anything, mock.AnythingOfType("uuid.UUID")).Return(&reception.Reception{Status: reception.StatusInProgress}, nil)
+				receptionRepo.On("GetByIDForUpdate", mock.Anything, mock.AnythingOfType("uuid.UUID")).Return(&reception.Reception{Status: reception.StatusInProgress}, nil)
 				tx.On("WithinTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Run(func(args mock.Arguments) {
 					fn := args.Get(1).(func(context.Context) error)
 					fn(context.Background())
@@ -174,7 +212,7 @@ func TestService_CreateBatch(t *testing.T) {
 			receptionID:  uuid.New(),
 			productTypes: []product.Type{product.TypeElectronics},
 			setupMocks: func(productRepo *MockProductRepository, receptionRepo *MockReceptionRepository, tx *MockTransactionManager) {
-				receptionRepo.On("GetByID", mock.Anything, mock.AnythingOfType("uuid.UUID")).Return(nil, errors.New("not found"))
+				receptionRepo.On("GetByIDForUpdate", mock.Anything, mock.AnythingOfType("uuid.UUID")).Return(nil, errors.New("not found"))
 				tx.On("WithinTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Run(func(args mock.Arguments) {
 					fn := args.Get(1).(func(context.Context) error)
 					fn(context.Background())
@@ -187,7 +225,7 @@ func TestService_CreateBatch(t *testing.T) {
 			receptionID:  uuid.New(),
 			productTypes: []product.Type{product.TypeElectronics},
 			setupMocks: func(productRepo *MockProductRepository, receptionRepo *MockReceptionRepository, tx *MockTransactionManager) {
-				receptionRepo.On("GetByID", mock.Anything, mock.AnythingOfType("uuid.UUID")).Return(&reception.Reception{Status: reception.StatusClose}, nil)
+				receptionRepo.On("GetByIDForUpdate", mock.Anything, mock.AnythingOfType("uuid.UUID")).Return(&reception.Reception{Status: reception.StatusClose}, nil)
 				tx.On("WithinTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Run(func(args mock.Arguments) {
 					fn := args.Get(1).(func(context.Context) error)
 					fn(context.Background())
@@ -200,7 +238,7 @@ func TestService_CreateBatch(t *testing.T) {
 			receptionID:  uuid.New(),
 			productTypes: []product.Type{product.Type("invalid")},
 			setupMocks: func(productRepo *MockProductRepository, receptionRepo *MockReceptionRepository, tx *MockTransactionManager) {
-				receptionRepo.On("GetByID", mock.Anything, mock.AnythingOfType("uuid.UUID")).Return(&reception.Reception{Status: reception.StatusInProgress}, nil)
+				receptionRepo.On("GetByIDForUpdate", mock.Anything, mock.AnythingOfType("uuid.UUID")).Return(&reception.Reception{Status: reception.StatusInProgress}, nil)
 				tx.On("WithinTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Run(func(args mock.Arguments) {
 					fn := args.Get(1).(func(context.Context) error)
 					fn(context.Background())
@@ -217,7 +255,7 @@ func TestService_CreateBatch(t *testing.T) {
 			tx := new(MockTransactionManager)
 			tt.setupMocks(productRepo, receptionRepo, tx)
 
-			service := New(productRepo, receptionRepo, tx)
+			service := New(productRepo, receptionRepo, tx, event.Discard, outbox.Discard, audit.Discard, assignment.AllowAll)
 			err := service.CreateBatch(context.Background(), tt.receptionID, tt.productTypes)
 
 			if tt.expectedError != nil {
@@ -244,11 +282,12 @@ func TestService_DeleteLast(t *testing.T) {
 			name:        "успешное удаление",
 			receptionID: uuid.New(),
 			setupMocks: func(productRepo *MockProductRepository, receptionRepo *MockReceptionRepository, tx *MockTransactionManager) {
-				receptionRepo.On("GetByID", mock.Anything, mock.AnythingOfType("uuid.UUID")).Return(&reception.Reception{Status: reception.StatusInProgress}, nil)
+				receptionRepo.On("GetByIDForUpdate", mock.Anything, mock.AnythingOfType("uuid.UUID")).Return(&reception.Reception{Status: reception.StatusInProgress}, nil)
 				tx.On("WithinTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Run(func(args mock.Arguments) {
 					fn := args.Get(1).(func(context.Context) error)
 					fn(context.Background())
 				}).Return(nil)
+				productRepo.On("GetLast", mock.Anything, mock.AnythingOfType("uuid.UUID")).Return(&product.Product{ID: uuid.New()}, nil)
 				productRepo.On("DeleteLast", mock.Anything, mock.AnythingOfType("uuid.UUID")).Return(nil)
 			},
 			expectedError: nil,
@@ -257,7 +296,7 @@ func TestService_DeleteLast(t *testing.T) {
 			name:        "приемка не найдена",
 			receptionID: uuid.New(),
 			setupMocks: func(productRepo *MockProductRepository, receptionRepo *MockReceptionRepository, tx *MockTransactionManager) {
-				receptionRepo.On("GetByID", mock.Anything, mock.AnythingOfType("uuid.UUID")).Return(nil, errors.New("not found"))
+				receptionRepo.On("GetByIDForUpdate", mock.Anything, mock.AnythingOfType("uuid.UUID")).Return(nil, errors.New("not found"))
 				tx.On("WithinTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Run(func(args mock.Arguments) {
 					fn := args.Get(1).(func(context.Context) error)
 					fn(context.Background())
@@ -269,7 +308,7 @@ func TestService_DeleteLast(t *testing.T) {
 			name:        "приемка закрыта",
 			receptionID: uuid.New(),
 			setupMocks: func(productRepo *MockProductRepository, receptionRepo *MockReceptionRepository, tx *MockTransactionManager) {
-				receptionRepo.On("GetByID", mock.Anything, mock.AnythingOfType("uuid.UUID")).Return(&reception.Reception{Status: reception.StatusClose}, nil)
+				receptionRepo.On("GetByIDForUpdate", mock.Anything, mock.AnythingOfType("uuid.UUID")).Return(&reception.Reception{Status: reception.StatusClose}, nil)
 				tx.On("WithinTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Run(func(args mock.Arguments) {
 					fn := args.Get(1).(func(context.Context) error)
 					fn(context.Background())
@@ -286,13 +325,18 @@ func TestService_DeleteLast(t *testing.T) {
 			tx := new(MockTransactionManager)
 			tt.setupMocks(productRepo, receptionRepo, tx)
 
-			service := New(productRepo, receptionRepo, tx)
+			bus := new(recordingBus)
+			service := New(productRepo, receptionRepo, tx, bus, outbox.Discard, audit.Discard, assignment.AllowAll)
 			err := service.DeleteLast(context.Background(), tt.receptionID)
 
 			if tt.expectedError != nil {
 				assert.Equal(t, tt.expectedError, err)
+				assert.Empty(t, bus.events)
 			} else {
 				assert.NoError(t, err)
+				require.Len(t, bus.events, 1)
+				require.IsType(t, event.ProductRemoved{}, bus.events[0])
+				assert.Equal(t, tt.receptionID, bus.events[0].(event.ProductRemoved).ReceptionID)
 			}
 
 			productRepo.AssertExpectations(t)
@@ -332,7 +376,7 @@ func TestService_GetByID(t *testing.T) {
 			productRepo := new(MockProductRepository)
 			tt.setupMocks(productRepo)
 
-			service := New(productRepo, nil, nil)
+			service := New(productRepo, nil, nil, event.Discard, outbox.Discard, audit.Discard, assignment.AllowAll)
 			_, err := service.GetByID(context.Background(), tt.id)
 
 			if tt.expectedError != nil {
@@ -376,7 +420,7 @@ func TestService_GetByReceptionID(t *testing.T) {
 			productRepo := new(MockProductRepository)
 			tt.setupMocks(productRepo)
 
-			service := New(productRepo, nil, nil)
+			service := New(productRepo, nil, nil, event.Discard, outbox.Discard, audit.Discard, assignment.AllowAll)
 			_, err := service.GetByReceptionID(context.Background(), tt.receptionID)
 
 			if tt.expectedError != nil {
@@ -423,7 +467,7 @@ func TestService_List(t *testing.T) {
 			productRepo := new(MockProductRepository)
 			tt.setupMocks(productRepo)
 
-			service := New(productRepo, nil, nil)
+			service := New(productRepo, nil, nil, event.Discard, outbox.Discard, audit.Discard, assignment.AllowAll)
 			_, err := service.List(context.Background(), tt.offset, tt.limit)
 
 			if tt.expectedError != nil {
@@ -479,7 +523,10 @@ func TestService_AddProduct(t *testing.T) {
 			tx := new(MockTransactionManager)
 			tt.setupMocks(productRepo, tx)
 
-			service := New(productRepo, nil, tx)
+			receptionRepo := new(MockReceptionRepository)
+			receptionRepo.On("GetByID", mock.Anything, tt.receptionID).Return(&reception.Reception{ID: tt.receptionID, PVZID: uuid.New()}, nil)
+
+			service := New(productRepo, receptionRepo, tx, event.Discard, outbox.Discard, audit.Discard, assignment.AllowAll)
 			_, err := service.AddProduct(context.Background(), tt.receptionID, tt.productType)
 
 			if tt.expectedError != nil {
@@ -536,7 +583,10 @@ func TestService_AddProducts(t *testing.T) {
 			tx := new(MockTransactionManager)
 			tt.setupMocks(productRepo, tx)
 
-			service := New(productRepo, nil, tx)
+			receptionRepo := new(MockReceptionRepository)
+			receptionRepo.On("GetByID", mock.Anything, tt.receptionID).Return(&reception.Reception{ID: tt.receptionID, PVZID: uuid.New()}, nil)
+
+			service := New(productRepo, receptionRepo, tx, event.Discard, outbox.Discard, audit.Discard, assignment.AllowAll)
 			_, err := service.AddProducts(context.Background(), tt.receptionID, tt.types)
 
 			if tt.expectedError != nil {
@@ -566,6 +616,7 @@ func TestService_DeleteLastProduct(t *testing.T) {
 					fn := args.Get(1).(func(context.Context) error)
 					fn(context.Background())
 				}).Return(nil)
+				productRepo.On("GetLast", mock.Anything, mock.AnythingOfType("uuid.UUID")).Return(&product.Product{ID: uuid.New()}, nil)
 				productRepo.On("DeleteLast", mock.Anything, mock.AnythingOfType("uuid.UUID")).Return(nil)
 			},
 			expectedError: nil,
@@ -578,6 +629,7 @@ func TestService_DeleteLastProduct(t *testing.T) {
 					fn := args.Get(1).(func(context.Context) error)
 					fn(context.Background())
 				}).Return(errors.New("delete error"))
+				productRepo.On("GetLast", mock.Anything, mock.AnythingOfType("uuid.UUID")).Return(&product.Product{ID: uuid.New()}, nil)
 				productRepo.On("DeleteLast", mock.Anything, mock.AnythingOfType("uuid.UUID")).Return(errors.New("delete error"))
 			},
 			expectedError: errors.New("delete error"),
@@ -590,7 +642,10 @@ func TestService_DeleteLastProduct(t *testing.T) {
 			tx := new(MockTransactionManager)
 			tt.setupMocks(productRepo, tx)
 
-			service := New(productRepo, nil, tx)
+			receptionRepo := new(MockReceptionRepository)
+			receptionRepo.On("GetByID", mock.Anything, tt.receptionID).Return(&reception.Reception{ID: tt.receptionID, PVZID: uuid.New()}, nil)
+
+			service := New(productRepo, receptionRepo, tx, event.Discard, outbox.Discard, audit.Discard, assignment.AllowAll)
 			err := service.DeleteLastProduct(context.Background(), tt.receptionID)
 
 			if tt.expectedError != nil {
@@ -618,7 +673,7 @@ func TestService_Create(t *testing.T) {
 			receptionID: uuid.New(),
 			productType: product.TypeElectronics,
 			setupMocks: func(productRepo *MockProductRepository, receptionRepo *MockReceptionRepository, tx *MockTransactionManager) {
-				receptionRepo.On("GetByID", mock.Anything, mock.AnythingOfType("uuid.UUID")).Return(&reception.Reception{Status: reception.StatusInProgress}, nil)
+				receptionRepo.On("GetByIDForUpdate", mock.Anything, mock.AnythingOfType("uuid.UUID")).Return(&reception.Reception{Status: reception.StatusInProgress}, nil)
 				tx.On("WithinTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Run(func(args mock.Arguments) {
 					fn := args.Get(1).(func(context.Context) error)
 					fn(context.Background())
@@ -632,7 +687,7 @@ func TestService_Create(t *testing.T) {
 			receptionID: uuid.New(),
 			productType: product.TypeElectronics,
 			setupMocks: func(productRepo *MockProductRepository, receptionRepo *MockReceptionRepository, tx *MockTransactionManager) {
-				receptionRepo.On("GetByID", mock.Anything, mock.AnythingOfType("uuid.UUID")).Return(nil, errors.New("not found"))
+				receptionRepo.On("GetByIDForUpdate", mock.Anything, mock.AnythingOfType("uuid.UUID")).Return(nil, errors.New("not found"))
 				tx.On("WithinTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Run(func(args mock.Arguments) {
 					fn := args.Get(1).(func(context.Context) error)
 					fn(context.Background())
@@ -645,7 +700,7 @@ func TestService_Create(t *testing.T) {
 			receptionID: uuid.New(),
 			productType: product.TypeElectronics,
 			setupMocks: func(productRepo *MockProductRepository, receptionRepo *MockReceptionRepository, tx *MockTransactionManager) {
-				receptionRepo.On("GetByID", mock.Anything, mock.AnythingOfType("uuid.UUID")).Return(&reception.Reception{Status: reception.StatusClose}, nil)
+				receptionRepo.On("GetByIDForUpdate", mock.Anything, mock.AnythingOfType("uuid.UUID")).Return(&reception.Reception{Status: reception.StatusClose}, nil)
 				tx.On("WithinTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Run(func(args mock.Arguments) {
 					fn := args.Get(1).(func(context.Context) error)
 					fn(context.Background())
@@ -658,7 +713,7 @@ func TestService_Create(t *testing.T) {
 			receptionID: uuid.New(),
 			productType: product.Type("invalid"),
 			setupMocks: func(productRepo *MockProductRepository, receptionRepo *MockReceptionRepository, tx *MockTransactionManager) {
-				receptionRepo.On("GetByID", mock.Anything, mock.AnythingOfType("uuid.UUID")).Return(&reception.Reception{Status: reception.StatusInProgress}, nil)
+				receptionRepo.On("GetByIDForUpdate", mock.Anything, mock.AnythingOfType("uuid.UUID")).Return(&reception.Reception{Status: reception.StatusInProgress}, nil)
 				tx.On("WithinTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Run(func(args mock.Arguments) {
 					fn := args.Get(1).(func(context.Context) error)
 					fn(context.Background())
@@ -675,13 +730,18 @@ func TestService_Create(t *testing.T) {
 			tx := new(MockTransactionManager)
 			tt.setupMocks(productRepo, receptionRepo, tx)
 
-			service := New(productRepo, receptionRepo, tx)
+			bus := new(recordingBus)
+			service := New(productRepo, receptionRepo, tx, bus, outbox.Discard, audit.Discard, assignment.AllowAll)
 			_, err := service.Create(context.Background(), tt.receptionID, tt.productType)
 
 			if tt.expectedError != nil {
 				assert.Equal(t, tt.expectedError, err)
+				assert.Empty(t, bus.events)
 			} else {
 				assert.NoError(t, err)
+				require.Len(t, bus.events, 1)
+				require.IsType(t, event.ProductAdded{}, bus.events[0])
+				assert.Equal(t, tt.receptionID, bus.events[0].(event.ProductAdded).ReceptionID)
 			}
 
 			productRepo.AssertExpectations(t)
@@ -690,3 +750,63 @@ func TestService_Create(t *testing.T) {
 		})
 	}
 }
+
+func TestService_PVZAccess(t *testing.T) {
+	receptionID, pvzID := uuid.New(), uuid.New()
+	denied := assignment.AccessFunc(func(ctx context.Context, id uuid.UUID) error {
+		assert.Equal(t, pvzID, id)
+		return assignment.ErrNotAssigned
+	})
+
+	tests := []struct {
+		name string
+		call func(*Service) error
+	}{
+		{
+			name: "добавление товара",
+			call: func(s *Service) error {
+				_, err := s.Create(context.Background(), receptionID, product.TypeElectronics)
+				return err
+			},
+		},
+		{
+			name: "добавление нескольких товаров",
+			call: func(s *Service) error {
+				return s.CreateBatch(context.Background(), receptionID, []product.Type{product.TypeFood})
+			},
+		},
+		{
+			name: "удаление последнего товара",
+			call: func(s *Service) error {
+				return s.DeleteLast(context.Background(), receptionID)
+			},
+		},
+		{
+			name: "импорт товаров",
+			call: func(s *Service) error {
+				_, err := s.Import(context.Background(), receptionID, []ImportRow{{Line: 2, Type: "food", Barcode: "4600000000002"}}, false)
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			productRepo := new(MockProductRepository)
+			receptionRepo := new(MockReceptionRepository)
+			receptionRepo.On("GetByIDForUpdate", mock.Anything, receptionID).Return(&reception.Reception{ID: receptionID, PVZID: pvzID, Status: reception.StatusInProgress}, nil)
+			tx := new(MockTransactionManager)
+			tx.On("WithinTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Run(func(args mock.Arguments) {
+				fn := args.Get(1).(func(context.Context) error)
+				assert.ErrorIs(t, fn(context.Background()), assignment.ErrNotAssigned)
+			}).Return(assignment.ErrNotAssigned)
+
+			service := New(productRepo, receptionRepo, tx, event.Discard, outbox.Discard, audit.Discard, denied)
+
+			assert.ErrorIs(t, tt.call(service), assignment.ErrNotAssigned)
+			productRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+			productRepo.AssertNotCalled(t, "CreateBatch", mock.Anything, mock.Anything)
+			productRepo.AssertNotCalled(t, "DeleteLast", mock.Anything, mock.Anything)
+		})
+	}
+}
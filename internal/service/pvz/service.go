@@ -7,7 +7,10 @@ import (
 	"time"
 
 	"github.com/avito/pvz/internal/domain/audit"
+	"github.com/avito/pvz/internal/domain/event"
+	"github.com/avito/pvz/internal/domain/listing"
 	"github.com/avito/pvz/internal/domain/pvz"
+	"github.com/avito/pvz/internal/domain/rbac"
 	"github.com/avito/pvz/internal/domain/transaction"
 	"github.com/avito/pvz/internal/domain/user"
 	"github.com/avito/pvz/internal/metrics"
@@ -15,13 +18,16 @@ import (
 )
 
 var (
-	ErrInvalidCity      = errors.New("invalid city name")
-	ErrAccessDenied     = errors.New("access denied")
-	ErrPVZNotFound      = errors.New("pvz not found")
-	ErrPVZAlreadyExists = errors.New("pvz already exists")
-	ErrInvalidPVZData   = errors.New("неверные данные пвз")
-	ErrDuplicatePVZ     = errors.New("пвз с таким городом уже существует")
-	ErrUnauthorized     = errors.New("недостаточно прав для выполнения операции")
+	ErrInvalidCity       = errors.New("invalid city name")
+	ErrAccessDenied      = errors.New("access denied")
+	ErrPVZNotFound       = errors.New("pvz not found")
+	ErrPVZAlreadyExists  = errors.New("pvz already exists")
+	ErrInvalidPVZData    = errors.New("неверные данные пвз")
+	ErrDuplicatePVZ      = errors.New("пвз с таким городом уже существует")
+	ErrUnauthorized      = errors.New("недостаточно прав для выполнения операции")
+	ErrInvalidPagination = errors.New("invalid pagination parameters")
+	ErrInvalidCursor     = errors.New("invalid cursor")
+	ErrVersionConflict   = errors.New("pvz version conflict")
 )
 
 // Service определяет бизнес-логику для работы с ПВЗ
@@ -29,17 +35,23 @@ type Service struct {
 	pvzRepo   pvz.Repository
 	userRepo  user.Repository
 	txManager transaction.Manager
-	auditLog  audit.AuditLog
+	events    event.Bus
+	audit     audit.Writer
 	userModel *user.User
 }
 
-// New создает новый экземпляр Service
-func New(pvzRepo pvz.Repository, userRepo user.Repository, txManager transaction.Manager, auditLog audit.AuditLog, userModel *user.User) *Service {
+// New создает новый экземпляр Service.
+// События о создании, изменении и удалении ПВЗ передаются в events после фиксации транзакции.
+// Сами изменения записываются в журнал audit в той же транзакции.
+// Права на изменение ПВЗ проверяются по разрешениям вызывающего из контекста:
+// роли пользователя или областям ключа API сервисного аккаунта.
+func New(pvzRepo pvz.Repository, userRepo user.Repository, txManager transaction.Manager, events event.Bus, audit audit.Writer, userModel *user.User) *Service {
 	return &Service{
 		pvzRepo:   pvzRepo,
 		userRepo:  userRepo,
 		txManager: txManager,
-		auditLog:  auditLog,
+		events:    events,
+		audit:     audit,
 		userModel: userModel,
 	}
 }
@@ -55,12 +67,8 @@ func (s *Service) Create(ctx context.Context, city string, userID uuid.UUID) (*p
 		return nil, ErrAccessDenied
 	}
 
-	// Проверяем права пользователя
-	user, err := s.userRepo.GetByID(ctx, userID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get user: %w", err)
-	}
-	if !user.CanCreatePVZ() {
+	// Проверяем права вызывающего
+	if err := rbac.Check(ctx, rbac.PVZCreate); err != nil {
 		return nil, ErrAccessDenied
 	}
 
@@ -68,10 +76,11 @@ func (s *Service) Create(ctx context.Context, city string, userID uuid.UUID) (*p
 		ID:        uuid.New(),
 		CreatedAt: time.Now(),
 		City:      city,
+		Version:   1,
 	}
 
 	// Выполняем операцию в транзакции для обеспечения атомарности
-	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
 		// Проверяем существование ПВЗ с таким же городом
 		existingPVZ, err := s.pvzRepo.GetByCity(ctx, city)
 		if err == nil && existingPVZ != nil {
@@ -86,18 +95,19 @@ func (s *Service) Create(ctx context.Context, city string, userID uuid.UUID) (*p
 			return fmt.Errorf("failed to create pvz: %w", err)
 		}
 
-		// Создаем запись в журнале аудита
-		if err := s.auditLog.LogPVZCreation(ctx, newPVZ.ID, userID); err != nil {
-			return fmt.Errorf("failed to log pvz creation: %w", err)
-		}
-
-		return nil
+		return s.record(ctx, userID, newPVZ.ID, audit.ActionCreate, nil, pvzState(newPVZ))
 	})
 
 	if err != nil {
 		return nil, err
 	}
 
+	s.events.Raise(ctx, event.PVZCreated{
+		PVZID:      newPVZ.ID,
+		City:       newPVZ.City,
+		CreatedBy:  userID,
+		OccurredAt: newPVZ.CreatedAt,
+	})
 	return newPVZ, nil
 }
 
@@ -120,12 +130,36 @@ func (s *Service) GetByID(ctx context.Context, id uuid.UUID) (*pvz.PVZ, error) {
 
 // GetWithReceptions получает список ПВЗ с приемками за период
 func (s *Service) GetWithReceptions(ctx context.Context, startDate, endDate time.Time, page, limit int) ([]*pvz.PVZWithReceptions, error) {
-	if page <= 0 || limit <= 0 {
-		return nil, errors.New("invalid pagination parameters")
+	if page <= 0 || limit <= 0 || limit > listing.MaxLimit {
+		return nil, ErrInvalidPagination
 	}
 	return s.pvzRepo.GetWithReceptions(ctx, startDate, endDate, page, limit)
 }
 
+// GetWithReceptionsByCursor получает страницу ПВЗ с приемками за период по курсору.
+// Пустой курсор соответствует первой странице. Вместе со страницей возвращается
+// курсор следующей страницы, пустой для последней.
+func (s *Service) GetWithReceptionsByCursor(ctx context.Context, startDate, endDate time.Time, cursor string, limit int) ([]*pvz.PVZWithReceptions, string, error) {
+	if limit <= 0 || limit > listing.MaxLimit {
+		return nil, "", ErrInvalidPagination
+	}
+
+	after, err := pvz.DecodeCursor(cursor)
+	if err != nil {
+		return nil, "", ErrInvalidCursor
+	}
+
+	pvzs, next, err := s.pvzRepo.GetWithReceptionsByCursor(ctx, startDate, endDate, after, limit)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if next == nil {
+		return pvzs, "", nil
+	}
+	return pvzs, next.Encode(), nil
+}
+
 // GetAll возвращает список всех ПВЗ
 func (s *Service) GetAll(ctx context.Context) ([]*pvz.PVZ, error) {
 	start := time.Now()
@@ -140,45 +174,72 @@ func (s *Service) GetAll(ctx context.Context) ([]*pvz.PVZ, error) {
 	return pvzs, err
 }
 
-// Update обновляет данные ПВЗ
-func (s *Service) Update(ctx context.Context, pvz *pvz.PVZ, moderatorID uuid.UUID) error {
+// Update обновляет данные ПВЗ. Если задана pvz.Version, обновление применяется только
+// к этой версии ПВЗ, иначе к текущей. При успехе pvz.Version содержит новую версию.
+func (s *Service) Update(ctx context.Context, p *pvz.PVZ, moderatorID uuid.UUID) error {
 	// Проверяем права модератора
-	moderator, err := s.userRepo.GetByID(ctx, moderatorID)
-	if err != nil {
-		return err
-	}
-	if moderator.Role != user.RoleAdmin {
+	if err := rbac.Check(ctx, rbac.PVZUpdate); err != nil {
 		return ErrAccessDenied
 	}
 
 	// Валидация города
-	if err := validateCity(pvz.City); err != nil {
+	if err := validateCity(p.City); err != nil {
 		return err
 	}
 
 	// Проверяем ID
-	if pvz.ID == uuid.Nil {
+	if p.ID == uuid.Nil {
 		return ErrPVZNotFound
 	}
 
-	return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
 		// Проверяем существование ПВЗ
-		if _, err := s.pvzRepo.GetByID(ctx, pvz.ID); err != nil {
+		current, err := s.pvzRepo.GetByID(ctx, p.ID)
+		if err != nil {
 			return ErrPVZNotFound
 		}
 
-		return s.pvzRepo.Update(ctx, pvz)
+		if p.Version == 0 {
+			p.Version = current.Version
+		}
+		if p.Version != current.Version {
+			return ErrVersionConflict
+		}
+
+		// Версия проверяется повторно при записи, если ПВЗ изменили после чтения
+		err = s.pvzRepo.Update(ctx, p)
+		switch {
+		case errors.Is(err, pvz.ErrVersionConflict):
+			return ErrVersionConflict
+		case errors.Is(err, pvz.ErrNotFound):
+			return ErrPVZNotFound
+		case err != nil:
+			return err
+		}
+
+		updated := *current
+		updated.City = p.City
+		updated.Version = p.Version
+		return s.record(ctx, moderatorID, p.ID, audit.ActionUpdate, pvzState(current), pvzState(&updated))
+	})
+	if err != nil {
+		return err
+	}
+
+	s.events.Raise(ctx, event.PVZUpdated{
+		PVZID:      p.ID,
+		City:       p.City,
+		Version:    p.Version,
+		UpdatedBy:  moderatorID,
+		OccurredAt: time.Now(),
 	})
+	return nil
 }
 
 // Delete удаляет ПВЗ
 func (s *Service) Delete(ctx context.Context, id uuid.UUID, moderatorID uuid.UUID) error {
 	// Проверяем права модератора
-	moderator, err := s.userRepo.GetByID(ctx, moderatorID)
-	if err != nil {
-		return err
-	}
-	if moderator.Role != user.RoleAdmin {
+	if err := rbac.Check(ctx, rbac.PVZDelete); err != nil {
 		return ErrAccessDenied
 	}
 
@@ -187,14 +248,29 @@ func (s *Service) Delete(ctx context.Context, id uuid.UUID, moderatorID uuid.UUI
 		return ErrPVZNotFound
 	}
 
-	return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
 		// Проверяем существование ПВЗ
-		if _, err := s.pvzRepo.GetByID(ctx, id); err != nil {
+		current, err := s.pvzRepo.GetByID(ctx, id)
+		if err != nil {
 			return ErrPVZNotFound
 		}
 
-		return s.pvzRepo.Delete(ctx, id)
+		if err := s.pvzRepo.Delete(ctx, id); err != nil {
+			return err
+		}
+
+		return s.record(ctx, moderatorID, id, audit.ActionDelete, pvzState(current), nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.events.Raise(ctx, event.PVZDeleted{
+		PVZID:      id,
+		DeletedBy:  moderatorID,
+		OccurredAt: time.Now(),
 	})
+	return nil
 }
 
 // List возвращает список ПВЗ
@@ -202,6 +278,32 @@ func (s *Service) List(ctx context.Context, offset, limit int) ([]*pvz.PVZ, erro
 	return s.pvzRepo.List(ctx, offset, limit)
 }
 
+// ListByFilter возвращает список ПВЗ, отобранных и отсортированных по фильтру
+func (s *Service) ListByFilter(ctx context.Context, filter pvz.ListFilter) ([]*pvz.PVZ, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	return s.pvzRepo.ListByFilter(ctx, filter)
+}
+
+// record записывает изменение ПВЗ в журнал аудита транзакции из ctx
+func (s *Service) record(ctx context.Context, actorID, pvzID uuid.UUID, action audit.Action, before, after audit.State) error {
+	entry, err := audit.NewEntry(ctx, actorID, audit.EntityPVZ, pvzID, action, before, after)
+	if err != nil {
+		return err
+	}
+	return s.audit.Record(ctx, entry)
+}
+
+// pvzState снимок ПВЗ для журнала аудита
+func pvzState(p *pvz.PVZ) audit.State {
+	return audit.State{
+		"city":             p.City,
+		"registrationDate": p.CreatedAt,
+		"version":          p.Version,
+	}
+}
+
 // validateCity проверяет корректность названия города
 func validateCity(city string) error {
 	if city == "" {
@@ -7,15 +7,29 @@ import (
 	"testing"
 	"time"
 
+	"github.com/avito/pvz/internal/domain/audit"
+	"github.com/avito/pvz/internal/domain/event"
+	"github.com/avito/pvz/internal/domain/listing"
 	"github.com/avito/pvz/internal/domain/product"
 	"github.com/avito/pvz/internal/domain/pvz"
+	"github.com/avito/pvz/internal/domain/rbac"
 	"github.com/avito/pvz/internal/domain/reception"
 	"github.com/avito/pvz/internal/domain/user"
+	"github.com/avito/pvz/pkg/auth"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
+// testAuthorizer проверяет права по политике по умолчанию
+var testAuthorizer = rbac.MustNewAuthorizer(rbac.DefaultPolicy)
+
+// withRole добавляет в контекст разрешения роли, как это делает аутентификация
+func withRole(ctx context.Context, role user.Role) context.Context {
+	return rbac.WithPermissions(ctx, testAuthorizer.Permissions(role))
+}
+
 // MockPVZRepository мок репозитория ПВЗ
 type MockPVZRepository struct {
 	mock.Mock
@@ -68,11 +82,31 @@ func (m *MockPVZRepository) List(ctx context.Context, offset, limit int) ([]*pvz
 	return args.Get(0).([]*pvz.PVZ), args.Error(1)
 }
 
+func (m *MockPVZRepository) ListByFilter(ctx context.Context, filter pvz.ListFilter) ([]*pvz.PVZ, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*pvz.PVZ), args.Error(1)
+}
+
 func (m *MockPVZRepository) GetWithReceptions(ctx context.Context, startDate, endDate time.Time, page, limit int) ([]*pvz.PVZWithReceptions, error) {
 	args := m.Called(ctx, startDate, endDate, page, limit)
 	return args.Get(0).([]*pvz.PVZWithReceptions), args.Error(1)
 }
 
+func (m *MockPVZRepository) GetWithReceptionsByCursor(ctx context.Context, startDate, endDate time.Time, cursor *pvz.Cursor, limit int) ([]*pvz.PVZWithReceptions, *pvz.Cursor, error) {
+	args := m.Called(ctx, startDate, endDate, cursor, limit)
+	var next *pvz.Cursor
+	if c, ok := args.Get(1).(*pvz.Cursor); ok {
+		next = c
+	}
+	if args.Get(0) == nil {
+		return nil, next, args.Error(2)
+	}
+	return args.Get(0).([]*pvz.PVZWithReceptions), next, args.Error(2)
+}
+
 // MockUserRepository мок репозитория пользователей
 type MockUserRepository struct {
 	mock.Mock
@@ -124,14 +158,13 @@ func (m *MockTransactionManager) WithinTransaction(ctx context.Context, fn func(
 	return args.Error(0)
 }
 
-// MockAuditLog мок журнала аудита
-type MockAuditLog struct {
+// MockBus мок шины событий
+type MockBus struct {
 	mock.Mock
 }
 
-func (m *MockAuditLog) LogPVZCreation(ctx context.Context, pvzID, userID uuid.UUID) error {
-	args := m.Called(ctx, pvzID, userID)
-	return args.Error(0)
+func (m *MockBus) Raise(ctx context.Context, events ...event.Domain) {
+	m.Called(ctx, events)
 }
 
 func TestService_Create(t *testing.T) {
@@ -139,19 +172,22 @@ func TestService_Create(t *testing.T) {
 		name        string
 		city        string
 		userID      uuid.UUID
-		setupMocks  func(*MockPVZRepository, *MockUserRepository, *MockTransactionManager, *MockAuditLog)
+		role        user.Role
+		setupMocks  func(*MockPVZRepository, *MockUserRepository, *MockTransactionManager, *MockBus)
 		expectedErr error
 	}{
 		{
 			name:   "успешное создание ПВЗ",
+			role:   user.RoleAdmin,
 			city:   "Москва",
 			userID: uuid.New(),
-			setupMocks: func(pvzRepo *MockPVZRepository, userRepo *MockUserRepository, txManager *MockTransactionManager, auditLog *MockAuditLog) {
-				user := &user.User{Role: user.RoleAdmin}
-				userRepo.On("GetByID", mock.Anything, mock.Anything).Return(user, nil)
+			setupMocks: func(pvzRepo *MockPVZRepository, userRepo *MockUserRepository, txManager *MockTransactionManager, bus *MockBus) {
 				pvzRepo.On("GetByCity", mock.Anything, "Москва").Return(nil, pvz.ErrNotFound)
 				pvzRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
-				auditLog.On("LogPVZCreation", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+				bus.On("Raise", mock.Anything, mock.MatchedBy(func(events []event.Domain) bool {
+					created, ok := events[0].(event.PVZCreated)
+					return len(events) == 1 && ok && created.City == "Москва"
+				})).Return()
 				txManager.On("WithinTransaction", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
 					fn := args.Get(1).(func(context.Context) error)
 					fn(context.Background())
@@ -163,28 +199,26 @@ func TestService_Create(t *testing.T) {
 			name:   "неверный город",
 			city:   "",
 			userID: uuid.New(),
-			setupMocks: func(pvzRepo *MockPVZRepository, userRepo *MockUserRepository, txManager *MockTransactionManager, auditLog *MockAuditLog) {
+			setupMocks: func(pvzRepo *MockPVZRepository, userRepo *MockUserRepository, txManager *MockTransactionManager, bus *MockBus) {
 				// Моки не нужны, так как валидация происходит до их вызова
 			},
 			expectedErr: ErrInvalidCity,
 		},
 		{
 			name:   "нет прав доступа",
+			role:   user.RoleUser,
 			city:   "Москва",
 			userID: uuid.New(),
-			setupMocks: func(pvzRepo *MockPVZRepository, userRepo *MockUserRepository, txManager *MockTransactionManager, auditLog *MockAuditLog) {
-				user := &user.User{Role: user.RoleUser}
-				userRepo.On("GetByID", mock.Anything, mock.Anything).Return(user, nil)
+			setupMocks: func(pvzRepo *MockPVZRepository, userRepo *MockUserRepository, txManager *MockTransactionManager, bus *MockBus) {
 			},
 			expectedErr: ErrAccessDenied,
 		},
 		{
 			name:   "ПВЗ уже существует",
+			role:   user.RoleAdmin,
 			city:   "Москва",
 			userID: uuid.New(),
-			setupMocks: func(pvzRepo *MockPVZRepository, userRepo *MockUserRepository, txManager *MockTransactionManager, auditLog *MockAuditLog) {
-				user := &user.User{Role: user.RoleAdmin}
-				userRepo.On("GetByID", mock.Anything, mock.Anything).Return(user, nil)
+			setupMocks: func(pvzRepo *MockPVZRepository, userRepo *MockUserRepository, txManager *MockTransactionManager, bus *MockBus) {
 				existingPVZ := &pvz.PVZ{City: "Москва"}
 				pvzRepo.On("GetByCity", mock.Anything, "Москва").Return(existingPVZ, nil)
 				txManager.On("WithinTransaction", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
@@ -201,12 +235,12 @@ func TestService_Create(t *testing.T) {
 			pvzRepo := new(MockPVZRepository)
 			userRepo := new(MockUserRepository)
 			txManager := new(MockTransactionManager)
-			auditLog := new(MockAuditLog)
+			bus := new(MockBus)
 
-			tt.setupMocks(pvzRepo, userRepo, txManager, auditLog)
+			tt.setupMocks(pvzRepo, userRepo, txManager, bus)
 
-			service := New(pvzRepo, userRepo, txManager, auditLog, nil)
-			result, err := service.Create(context.Background(), tt.city, tt.userID)
+			service := New(pvzRepo, userRepo, txManager, bus, audit.Discard, nil)
+			result, err := service.Create(withRole(context.Background(), tt.role), tt.city, tt.userID)
 
 			if tt.expectedErr != nil {
 				assert.ErrorIs(t, err, tt.expectedErr)
@@ -221,7 +255,7 @@ func TestService_Create(t *testing.T) {
 			pvzRepo.AssertExpectations(t)
 			userRepo.AssertExpectations(t)
 			txManager.AssertExpectations(t)
-			auditLog.AssertExpectations(t)
+			bus.AssertExpectations(t)
 		})
 	}
 }
@@ -266,7 +300,7 @@ func TestService_GetByID(t *testing.T) {
 			pvzRepo := new(MockPVZRepository)
 			tt.setupMocks(pvzRepo)
 
-			service := New(pvzRepo, nil, nil, nil, nil)
+			service := New(pvzRepo, nil, nil, nil, nil, nil)
 			result, err := service.GetByID(context.Background(), tt.id)
 
 			if tt.expectedErr != nil {
@@ -283,24 +317,54 @@ func TestService_GetByID(t *testing.T) {
 	}
 }
 
+func TestService_CreateByServiceAccount(t *testing.T) {
+	serviceAccountID := uuid.New()
+	pvzRepo := new(MockPVZRepository)
+	userRepo := new(MockUserRepository)
+	txManager := new(MockTransactionManager)
+	pvzRepo.On("GetByCity", mock.Anything, "Казань").Return(nil, pvz.ErrNotFound)
+	pvzRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+	txManager.On("WithinTransaction", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		fn := args.Get(1).(func(context.Context) error)
+		require.NoError(t, fn(args.Get(0).(context.Context)))
+	})
+
+	// Ключ API сервисного аккаунта дает разрешения областями, а не ролью
+	// пользователя, поэтому сервисный аккаунт не ищется среди пользователей
+	ctx := auth.WithUserID(context.Background(), serviceAccountID)
+	ctx = auth.WithUserRole(ctx, user.RoleService)
+	ctx = rbac.WithPermissions(ctx, rbac.NewSet(rbac.PVZCreate))
+
+	service := New(pvzRepo, userRepo, txManager, event.Discard, audit.Discard, nil)
+	created, err := service.Create(ctx, "Казань", serviceAccountID)
+	require.NoError(t, err)
+	assert.Equal(t, "Казань", created.City)
+	userRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+
+	// Без области pvz:create ключ не создает ПВЗ
+	ctx = rbac.WithPermissions(ctx, rbac.NewSet(rbac.PVZUpdate))
+	_, err = service.Create(ctx, "Казань", serviceAccountID)
+	assert.ErrorIs(t, err, ErrAccessDenied)
+}
+
 func TestService_Update(t *testing.T) {
 	tests := []struct {
 		name        string
 		pvz         *pvz.PVZ
 		moderatorID uuid.UUID
+		role        user.Role
 		setupMocks  func(*MockPVZRepository, *MockUserRepository, *MockTransactionManager)
 		expectedErr error
 	}{
 		{
 			name: "успешное обновление ПВЗ",
+			role: user.RoleAdmin,
 			pvz: &pvz.PVZ{
 				ID:   uuid.New(),
 				City: "Санкт-Петербург",
 			},
 			moderatorID: uuid.New(),
 			setupMocks: func(pvzRepo *MockPVZRepository, userRepo *MockUserRepository, txManager *MockTransactionManager) {
-				moderator := &user.User{Role: user.RoleAdmin}
-				userRepo.On("GetByID", mock.Anything, mock.Anything).Return(moderator, nil)
 				pvzRepo.On("GetByID", mock.Anything, mock.Anything).Return(&pvz.PVZ{}, nil)
 				pvzRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
 				txManager.On("WithinTransaction", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
@@ -312,30 +376,86 @@ func TestService_Update(t *testing.T) {
 		},
 		{
 			name: "нет прав доступа",
+			role: user.RoleUser,
 			pvz: &pvz.PVZ{
 				ID:   uuid.New(),
 				City: "Санкт-Петербург",
 			},
 			moderatorID: uuid.New(),
 			setupMocks: func(pvzRepo *MockPVZRepository, userRepo *MockUserRepository, txManager *MockTransactionManager) {
-				moderator := &user.User{Role: user.RoleUser}
-				userRepo.On("GetByID", mock.Anything, mock.Anything).Return(moderator, nil)
 			},
 			expectedErr: ErrAccessDenied,
 		},
 		{
 			name: "неверные данные ПВЗ",
+			role: user.RoleAdmin,
 			pvz: &pvz.PVZ{
 				ID:   uuid.New(),
 				City: "",
 			},
 			moderatorID: uuid.New(),
 			setupMocks: func(pvzRepo *MockPVZRepository, userRepo *MockUserRepository, txManager *MockTransactionManager) {
-				moderator := &user.User{Role: user.RoleAdmin}
-				userRepo.On("GetByID", mock.Anything, mock.Anything).Return(moderator, nil)
 			},
 			expectedErr: ErrInvalidCity,
 		},
+		{
+			name: "обновление с ожидаемой версией",
+			role: user.RoleAdmin,
+			pvz: &pvz.PVZ{
+				ID:      uuid.New(),
+				City:    "Казань",
+				Version: 2,
+			},
+			moderatorID: uuid.New(),
+			setupMocks: func(pvzRepo *MockPVZRepository, userRepo *MockUserRepository, txManager *MockTransactionManager) {
+				pvzRepo.On("GetByID", mock.Anything, mock.Anything).Return(&pvz.PVZ{Version: 2}, nil)
+				pvzRepo.On("Update", mock.Anything, mock.MatchedBy(func(p *pvz.PVZ) bool {
+					return p.Version == 2
+				})).Return(nil)
+				txManager.On("WithinTransaction", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+					fn := args.Get(1).(func(context.Context) error)
+					fn(context.Background())
+				}).Return(nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "устаревшая версия ПВЗ",
+			role: user.RoleAdmin,
+			pvz: &pvz.PVZ{
+				ID:      uuid.New(),
+				City:    "Казань",
+				Version: 1,
+			},
+			moderatorID: uuid.New(),
+			setupMocks: func(pvzRepo *MockPVZRepository, userRepo *MockUserRepository, txManager *MockTransactionManager) {
+				pvzRepo.On("GetByID", mock.Anything, mock.Anything).Return(&pvz.PVZ{Version: 2}, nil)
+				txManager.On("WithinTransaction", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+					fn := args.Get(1).(func(context.Context) error)
+					fn(context.Background())
+				}).Return(ErrVersionConflict)
+			},
+			expectedErr: ErrVersionConflict,
+		},
+		{
+			name: "ПВЗ изменен во время обновления",
+			role: user.RoleAdmin,
+			pvz: &pvz.PVZ{
+				ID:      uuid.New(),
+				City:    "Казань",
+				Version: 2,
+			},
+			moderatorID: uuid.New(),
+			setupMocks: func(pvzRepo *MockPVZRepository, userRepo *MockUserRepository, txManager *MockTransactionManager) {
+				pvzRepo.On("GetByID", mock.Anything, mock.Anything).Return(&pvz.PVZ{Version: 2}, nil)
+				pvzRepo.On("Update", mock.Anything, mock.Anything).Return(pvz.ErrVersionConflict)
+				txManager.On("WithinTransaction", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+					fn := args.Get(1).(func(context.Context) error)
+					fn(context.Background())
+				}).Return(ErrVersionConflict)
+			},
+			expectedErr: ErrVersionConflict,
+		},
 	}
 
 	for _, tt := range tests {
@@ -346,8 +466,8 @@ func TestService_Update(t *testing.T) {
 
 			tt.setupMocks(pvzRepo, userRepo, txManager)
 
-			service := New(pvzRepo, userRepo, txManager, nil, nil)
-			err := service.Update(context.Background(), tt.pvz, tt.moderatorID)
+			service := New(pvzRepo, userRepo, txManager, event.Discard, audit.Discard, nil)
+			err := service.Update(withRole(context.Background(), tt.role), tt.pvz, tt.moderatorID)
 
 			if tt.expectedErr != nil {
 				assert.ErrorIs(t, err, tt.expectedErr)
@@ -362,21 +482,66 @@ func TestService_Update(t *testing.T) {
 	}
 }
 
+// recordingAudit запоминает записи журнала аудита
+type recordingAudit struct {
+	entries []*audit.Entry
+}
+
+func (r *recordingAudit) Record(_ context.Context, entries ...*audit.Entry) error {
+	r.entries = append(r.entries, entries...)
+	return nil
+}
+
+func TestService_UpdateAudit(t *testing.T) {
+	moderatorID := uuid.New()
+	current := &pvz.PVZ{ID: uuid.New(), City: "Москва", CreatedAt: time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC), Version: 3}
+
+	pvzRepo := new(MockPVZRepository)
+	userRepo := new(MockUserRepository)
+	txManager := new(MockTransactionManager)
+	pvzRepo.On("GetByID", mock.Anything, current.ID).Return(current, nil)
+	pvzRepo.On("Update", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(1).(*pvz.PVZ).Version++
+	}).Return(nil)
+	txManager.On("WithinTransaction", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		fn := args.Get(1).(func(context.Context) error)
+		require.NoError(t, fn(args.Get(0).(context.Context)))
+	})
+
+	recorder := new(recordingAudit)
+	service := New(pvzRepo, userRepo, txManager, event.Discard, recorder, nil)
+	ctx := audit.WithOrigin(withRole(context.Background(), user.RoleAdmin), audit.Origin{Source: audit.SourceHTTP, RequestID: "req-1"})
+
+	// В запросе приходит только город, дата регистрации в журнал не попадает
+	require.NoError(t, service.Update(ctx, &pvz.PVZ{ID: current.ID, City: "Казань"}, moderatorID))
+
+	require.Len(t, recorder.entries, 1)
+	entry := recorder.entries[0]
+	assert.Equal(t, moderatorID, entry.ActorID)
+	assert.Equal(t, audit.EntityPVZ, entry.EntityType)
+	assert.Equal(t, current.ID, entry.EntityID)
+	assert.Equal(t, audit.ActionUpdate, entry.Action)
+	assert.JSONEq(t, `{"city":"Москва","version":3}`, string(entry.Before))
+	assert.JSONEq(t, `{"city":"Казань","version":4}`, string(entry.After))
+	assert.Equal(t, audit.SourceHTTP, entry.Source)
+	assert.Equal(t, "req-1", entry.RequestID)
+}
+
 func TestService_Delete(t *testing.T) {
 	tests := []struct {
 		name        string
 		id          uuid.UUID
 		moderatorID uuid.UUID
+		role        user.Role
 		setupMocks  func(*MockPVZRepository, *MockUserRepository, *MockTransactionManager)
 		expectedErr error
 	}{
 		{
 			name:        "успешное удаление ПВЗ",
+			role:        user.RoleAdmin,
 			id:          uuid.New(),
 			moderatorID: uuid.New(),
 			setupMocks: func(pvzRepo *MockPVZRepository, userRepo *MockUserRepository, txManager *MockTransactionManager) {
-				moderator := &user.User{Role: user.RoleAdmin}
-				userRepo.On("GetByID", mock.Anything, mock.Anything).Return(moderator, nil)
 				pvzRepo.On("GetByID", mock.Anything, mock.Anything).Return(&pvz.PVZ{}, nil)
 				pvzRepo.On("Delete", mock.Anything, mock.Anything).Return(nil)
 				txManager.On("WithinTransaction", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
@@ -388,21 +553,19 @@ func TestService_Delete(t *testing.T) {
 		},
 		{
 			name:        "нет прав доступа",
+			role:        user.RoleUser,
 			id:          uuid.New(),
 			moderatorID: uuid.New(),
 			setupMocks: func(pvzRepo *MockPVZRepository, userRepo *MockUserRepository, txManager *MockTransactionManager) {
-				moderator := &user.User{Role: user.RoleUser}
-				userRepo.On("GetByID", mock.Anything, mock.Anything).Return(moderator, nil)
 			},
 			expectedErr: ErrAccessDenied,
 		},
 		{
 			name:        "ПВЗ не найден",
+			role:        user.RoleAdmin,
 			id:          uuid.New(),
 			moderatorID: uuid.New(),
 			setupMocks: func(pvzRepo *MockPVZRepository, userRepo *MockUserRepository, txManager *MockTransactionManager) {
-				moderator := &user.User{Role: user.RoleAdmin}
-				userRepo.On("GetByID", mock.Anything, mock.Anything).Return(moderator, nil)
 				pvzRepo.On("GetByID", mock.Anything, mock.Anything).Return(nil, pvz.ErrNotFound)
 				txManager.On("WithinTransaction", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
 					fn := args.Get(1).(func(context.Context) error)
@@ -421,8 +584,8 @@ func TestService_Delete(t *testing.T) {
 
 			tt.setupMocks(pvzRepo, userRepo, txManager)
 
-			service := New(pvzRepo, userRepo, txManager, nil, nil)
-			err := service.Delete(context.Background(), tt.id, tt.moderatorID)
+			service := New(pvzRepo, userRepo, txManager, event.Discard, audit.Discard, nil)
+			err := service.Delete(withRole(context.Background(), tt.role), tt.id, tt.moderatorID)
 
 			if tt.expectedErr != nil {
 				assert.ErrorIs(t, err, tt.expectedErr)
@@ -474,7 +637,7 @@ func TestService_GetAll(t *testing.T) {
 			pvzRepo := new(MockPVZRepository)
 			tt.setupMocks(pvzRepo)
 
-			service := New(pvzRepo, nil, nil, nil, nil)
+			service := New(pvzRepo, nil, nil, nil, nil, nil)
 			result, err := service.GetAll(context.Background())
 
 			if tt.expectedErr != nil {
@@ -536,7 +699,7 @@ func TestService_List(t *testing.T) {
 			pvzRepo := new(MockPVZRepository)
 			tt.setupMocks(pvzRepo)
 
-			service := New(pvzRepo, nil, nil, nil, nil)
+			service := New(pvzRepo, nil, nil, nil, nil, nil)
 			result, err := service.List(context.Background(), tt.offset, tt.limit)
 
 			if tt.expectedErr != nil {
@@ -604,6 +767,15 @@ func TestService_GetWithReceptions(t *testing.T) {
 			},
 			expectedErr: errors.New("invalid pagination parameters"),
 		},
+		{
+			name:        "лимит больше максимального",
+			startDate:   time.Now().Add(-24 * time.Hour),
+			endDate:     time.Now(),
+			page:        1,
+			limit:       listing.MaxLimit + 1,
+			setupMocks:  func(pvzRepo *MockPVZRepository) {},
+			expectedErr: errors.New("invalid pagination parameters"),
+		},
 	}
 
 	for _, tt := range tests {
@@ -611,7 +783,7 @@ func TestService_GetWithReceptions(t *testing.T) {
 			pvzRepo := new(MockPVZRepository)
 			tt.setupMocks(pvzRepo)
 
-			service := New(pvzRepo, nil, nil, nil, nil)
+			service := New(pvzRepo, nil, nil, nil, nil, nil)
 			result, err := service.GetWithReceptions(context.Background(), tt.startDate, tt.endDate, tt.page, tt.limit)
 
 			if tt.expectedErr != nil {
@@ -633,6 +805,180 @@ func TestService_GetWithReceptions(t *testing.T) {
 	}
 }
 
+func TestService_GetWithReceptionsByCursor(t *testing.T) {
+	startDate := time.Now().Add(-24 * time.Hour)
+	endDate := time.Now()
+	pvzs := []*pvz.PVZWithReceptions{
+		{
+			PVZ: &pvz.PVZ{
+				ID:        uuid.New(),
+				CreatedAt: time.Now(),
+				City:      "Москва",
+			},
+		},
+	}
+	next := &pvz.Cursor{CreatedAt: pvzs[0].PVZ.CreatedAt, ID: pvzs[0].PVZ.ID}
+	after := pvz.Cursor{CreatedAt: time.Now().Add(time.Hour), ID: uuid.New()}
+
+	tests := []struct {
+		name           string
+		cursor         string
+		limit          int
+		setupMocks     func(*MockPVZRepository)
+		expectedCursor string
+		expectedErr    error
+	}{
+		{
+			name:   "первая страница",
+			cursor: "",
+			limit:  1,
+			setupMocks: func(pvzRepo *MockPVZRepository) {
+				pvzRepo.On("GetWithReceptionsByCursor", mock.Anything, startDate, endDate, (*pvz.Cursor)(nil), 1).
+					Return(pvzs, next, nil)
+			},
+			expectedCursor: next.Encode(),
+		},
+		{
+			name:   "последняя страница",
+			cursor: after.Encode(),
+			limit:  10,
+			setupMocks: func(pvzRepo *MockPVZRepository) {
+				pvzRepo.On("GetWithReceptionsByCursor", mock.Anything, startDate, endDate, mock.MatchedBy(func(c *pvz.Cursor) bool {
+					return c != nil && c.ID == after.ID && c.CreatedAt.Equal(after.CreatedAt)
+				}), 10).Return(pvzs, nil, nil)
+			},
+			expectedCursor: "",
+		},
+		{
+			name:        "неверный курсор",
+			cursor:      "invalid",
+			limit:       10,
+			setupMocks:  func(pvzRepo *MockPVZRepository) {},
+			expectedErr: ErrInvalidCursor,
+		},
+		{
+			name:        "неверный лимит",
+			cursor:      "",
+			limit:       0,
+			setupMocks:  func(pvzRepo *MockPVZRepository) {},
+			expectedErr: ErrInvalidPagination,
+		},
+		{
+			name:        "лимит больше максимального",
+			cursor:      "",
+			limit:       listing.MaxLimit + 1,
+			setupMocks:  func(pvzRepo *MockPVZRepository) {},
+			expectedErr: ErrInvalidPagination,
+		},
+		{
+			name:   "ошибка репозитория",
+			cursor: "",
+			limit:  10,
+			setupMocks: func(pvzRepo *MockPVZRepository) {
+				pvzRepo.On("GetWithReceptionsByCursor", mock.Anything, startDate, endDate, (*pvz.Cursor)(nil), 10).
+					Return(nil, nil, errors.New("database error"))
+			},
+			expectedErr: errors.New("database error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pvzRepo := new(MockPVZRepository)
+			tt.setupMocks(pvzRepo)
+
+			service := New(pvzRepo, nil, nil, nil, nil, nil)
+			result, nextCursor, err := service.GetWithReceptionsByCursor(context.Background(), startDate, endDate, tt.cursor, tt.limit)
+
+			if tt.expectedErr != nil {
+				assert.EqualError(t, err, tt.expectedErr.Error())
+				assert.Nil(t, result)
+				assert.Empty(t, nextCursor)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, pvzs, result)
+				assert.Equal(t, tt.expectedCursor, nextCursor)
+			}
+
+			pvzRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_ListByFilter(t *testing.T) {
+	pvzs := []*pvz.PVZ{
+		{
+			ID:        uuid.New(),
+			CreatedAt: time.Now(),
+			City:      "Москва",
+		},
+	}
+	page := listing.Page{Offset: 0, Limit: 10}
+
+	tests := []struct {
+		name        string
+		filter      pvz.ListFilter
+		setupMocks  func(*MockPVZRepository, pvz.ListFilter)
+		expectedErr error
+	}{
+		{
+			name: "фильтр по городу с сортировкой",
+			filter: pvz.ListFilter{
+				City: "Москва",
+				Sort: listing.Sort{Field: pvz.SortByCity, Direction: listing.DirectionAsc},
+				Page: page,
+			},
+			setupMocks: func(pvzRepo *MockPVZRepository, f pvz.ListFilter) {
+				pvzRepo.On("ListByFilter", mock.Anything, f).Return(pvzs, nil)
+			},
+		},
+		{
+			name: "неизвестное поле сортировки",
+			filter: pvz.ListFilter{
+				Sort: listing.Sort{Field: "id", Direction: listing.DirectionAsc},
+				Page: page,
+			},
+			setupMocks:  func(pvzRepo *MockPVZRepository, f pvz.ListFilter) {},
+			expectedErr: listing.ErrInvalidSort,
+		},
+		{
+			name: "перевернутый диапазон дат",
+			filter: pvz.ListFilter{
+				CreatedAt: listing.DateRange{From: time.Now(), To: time.Now().Add(-time.Hour)},
+				Page:      page,
+			},
+			setupMocks:  func(pvzRepo *MockPVZRepository, f pvz.ListFilter) {},
+			expectedErr: listing.ErrInvalidDateRange,
+		},
+		{
+			name:        "неверная пагинация",
+			filter:      pvz.ListFilter{Page: listing.Page{Offset: -1, Limit: 10}},
+			setupMocks:  func(pvzRepo *MockPVZRepository, f pvz.ListFilter) {},
+			expectedErr: listing.ErrInvalidPage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pvzRepo := new(MockPVZRepository)
+			tt.setupMocks(pvzRepo, tt.filter)
+
+			service := New(pvzRepo, nil, nil, nil, nil, nil)
+			result, err := service.ListByFilter(context.Background(), tt.filter)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, pvzs, result)
+			}
+
+			pvzRepo.AssertExpectations(t)
+		})
+	}
+}
+
 func TestValidateCity(t *testing.T) {
 	tests := []struct {
 		name          string
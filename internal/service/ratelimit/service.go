@@ -0,0 +1,50 @@
+// Package ratelimit ограничивает частоту запросов по политике ratelimit.Policy.
+// Для каждой пары маршрута и вызывающего (пользователя или IP-адреса) ведется
+// своя корзина токенов. Корзины хранятся в памяти экземпляра сервиса или,
+// если экземпляров несколько, в общей таблице Postgres.
+package ratelimit
+
+import (
+	"context"
+	"log"
+
+	"github.com/avito/pvz/internal/domain/ratelimit"
+	"github.com/avito/pvz/internal/domain/user"
+)
+
+// Store хранилище корзин токенов
+type Store interface {
+	// Take забирает токен из корзины key, создавая полную корзину при первом обращении
+	Take(ctx context.Context, key string, limit ratelimit.Limit) (ratelimit.Decision, error)
+}
+
+// Limiter проверяет запросы по политике
+type Limiter struct {
+	policy ratelimit.Policy
+	store  Store
+}
+
+// New создает новый экземпляр Limiter
+func New(policy ratelimit.Policy, store Store) *Limiter {
+	return &Limiter{
+		policy: policy,
+		store:  store,
+	}
+}
+
+// Allow забирает токен из корзины маршрута route для вызывающего subject
+// с основной ролью role. Ошибка хранилища не должна останавливать сервис,
+// поэтому при ней запрос пропускается.
+func (l *Limiter) Allow(ctx context.Context, route string, role user.Role, subject string) ratelimit.Decision {
+	limit := l.policy.Match(route, role)
+	if limit.Unlimited() {
+		return ratelimit.Decision{Allowed: true}
+	}
+
+	decision, err := l.store.Take(ctx, route+"|"+subject, limit)
+	if err != nil {
+		log.Printf("Failed to check rate limit for %s: %v", route, err)
+		return ratelimit.Decision{Allowed: true}
+	}
+	return decision
+}
@@ -0,0 +1,55 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/ratelimit"
+	"github.com/avito/pvz/internal/domain/user"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockStore мок для Store
+type MockStore struct {
+	mock.Mock
+}
+
+func (m *MockStore) Take(ctx context.Context, key string, limit ratelimit.Limit) (ratelimit.Decision, error) {
+	args := m.Called(ctx, key, limit)
+	return args.Get(0).(ratelimit.Decision), args.Error(1)
+}
+
+func TestLimiter_Allow(t *testing.T) {
+	login := ratelimit.Limit{Requests: 5, Period: ratelimit.Duration(time.Minute)}
+	policy := ratelimit.Policy{
+		Rules: []ratelimit.Rule{{Route: "POST /login", Limit: login}},
+	}
+	ctx := context.Background()
+
+	t.Run("токен забирается из корзины маршрута и вызывающего", func(t *testing.T) {
+		store := new(MockStore)
+		denied := ratelimit.Decision{RetryAfter: time.Second}
+		store.On("Take", ctx, "POST /login|ip:10.0.0.1", login).Return(denied, nil)
+
+		assert.Equal(t, denied, New(policy, store).Allow(ctx, "POST /login", "", "ip:10.0.0.1"))
+		store.AssertExpectations(t)
+	})
+
+	t.Run("маршрут без ограничения", func(t *testing.T) {
+		store := new(MockStore)
+
+		decision := New(policy, store).Allow(ctx, "GET /pvz", user.RoleEmployee, "user:1")
+		assert.True(t, decision.Allowed)
+		store.AssertNotCalled(t, "Take", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("ошибка хранилища пропускает запрос", func(t *testing.T) {
+		store := new(MockStore)
+		store.On("Take", ctx, mock.Anything, login).Return(ratelimit.Decision{}, errors.New("db error"))
+
+		assert.True(t, New(policy, store).Allow(ctx, "POST /login", "", "ip:10.0.0.1").Allowed)
+	})
+}
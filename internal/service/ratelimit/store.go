@@ -0,0 +1,131 @@
+package ratelimit
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/ratelimit"
+	"github.com/avito/pvz/internal/domain/transaction"
+)
+
+const (
+	// IdleTTL время, после которого неиспользуемая корзина удаляется. Корзины
+	// политики по умолчанию за это время успевают наполниться, поэтому удаление
+	// не меняет решений.
+	IdleTTL = time.Hour
+	// sweepInterval период удаления неиспользуемых корзин
+	sweepInterval = time.Minute
+)
+
+// MemoryStore хранит корзины в памяти экземпляра сервиса
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*ratelimit.Bucket
+	now     func() time.Time
+}
+
+// NewMemoryStore создает новый экземпляр MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: make(map[string]*ratelimit.Bucket),
+		now:     time.Now,
+	}
+}
+
+// Take забирает токен из корзины key
+func (s *MemoryStore) Take(_ context.Context, key string, limit ratelimit.Limit) (ratelimit.Decision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	bucket, ok := s.buckets[key]
+	if !ok {
+		bucket = ratelimit.NewBucket(limit, now)
+		s.buckets[key] = bucket
+	}
+	return bucket.Take(limit, now), nil
+}
+
+// Sweep удаляет корзины, не использовавшиеся дольше IdleTTL
+func (s *MemoryStore) Sweep() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	before := s.now().Add(-IdleTTL)
+	deleted := 0
+	for key, bucket := range s.buckets {
+		if bucket.UpdatedAt.Before(before) {
+			delete(s.buckets, key)
+			deleted++
+		}
+	}
+	return deleted
+}
+
+// Run периодически удаляет неиспользуемые корзины до отмены ctx
+func (s *MemoryStore) Run(ctx context.Context) {
+	ticker := time.NewTicker(sweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.Sweep()
+		}
+	}
+}
+
+// SharedStore хранит корзины в базе, общей для всех экземпляров сервиса.
+// Корзина блокируется на время проверки, поэтому одновременные запросы
+// с разных экземпляров не тратят один и тот же токен.
+type SharedStore struct {
+	repo      ratelimit.Repository
+	txManager transaction.Manager
+	now       func() time.Time
+}
+
+// NewSharedStore создает новый экземпляр SharedStore
+func NewSharedStore(repo ratelimit.Repository, txManager transaction.Manager) *SharedStore {
+	return &SharedStore{
+		repo:      repo,
+		txManager: txManager,
+		now:       time.Now,
+	}
+}
+
+// Take забирает токен из корзины key
+func (s *SharedStore) Take(ctx context.Context, key string, limit ratelimit.Limit) (ratelimit.Decision, error) {
+	var decision ratelimit.Decision
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		now := s.now()
+		bucket, err := s.repo.Lock(ctx, key, ratelimit.NewBucket(limit, now))
+		if err != nil {
+			return err
+		}
+
+		decision = bucket.Take(limit, now)
+		return s.repo.Save(ctx, key, bucket)
+	})
+	return decision, err
+}
+
+// Run периодически удаляет неиспользуемые корзины до отмены ctx
+func (s *SharedStore) Run(ctx context.Context) {
+	ticker := time.NewTicker(sweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.repo.DeleteIdle(ctx, s.now().Add(-IdleTTL)); err != nil && ctx.Err() == nil {
+				log.Printf("Failed to delete idle rate limit buckets: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockRepository мок для ratelimit.Repository
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) Lock(ctx context.Context, key string, initial *ratelimit.Bucket) (*ratelimit.Bucket, error) {
+	args := m.Called(ctx, key, initial)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ratelimit.Bucket), args.Error(1)
+}
+
+func (m *MockRepository) Save(ctx context.Context, key string, bucket *ratelimit.Bucket) error {
+	args := m.Called(ctx, key, bucket)
+	return args.Error(0)
+}
+
+func (m *MockRepository) DeleteIdle(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// fakeTxManager выполняет функцию без транзакции
+type fakeTxManager struct{}
+
+func (fakeTxManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func TestMemoryStore_Take(t *testing.T) {
+	now := time.Date(2026, time.October, 1, 12, 0, 0, 0, time.UTC)
+	limit := ratelimit.Limit{Requests: 2, Period: ratelimit.Duration(time.Minute)}
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		decision, err := store.Take(ctx, "a", limit)
+		require.NoError(t, err)
+		assert.True(t, decision.Allowed)
+	}
+
+	decision, err := store.Take(ctx, "a", limit)
+	require.NoError(t, err)
+	assert.False(t, decision.Allowed)
+	assert.Equal(t, 30*time.Second, decision.RetryAfter)
+
+	// У другого ключа своя корзина
+	decision, err = store.Take(ctx, "b", limit)
+	require.NoError(t, err)
+	assert.True(t, decision.Allowed)
+
+	now = now.Add(IdleTTL + time.Second)
+	assert.Equal(t, 2, store.Sweep())
+}
+
+func TestSharedStore_Take(t *testing.T) {
+	now := time.Date(2026, time.October, 1, 12, 0, 0, 0, time.UTC)
+	limit := ratelimit.Limit{Requests: 60, Period: ratelimit.Duration(time.Minute)}
+	ctx := context.Background()
+
+	repo := new(MockRepository)
+	store := NewSharedStore(repo, fakeTxManager{})
+	store.now = func() time.Time { return now }
+
+	// Корзина в базе пуста с прошлой секунды: за секунду восполнен один токен
+	locked := &ratelimit.Bucket{Tokens: 0, UpdatedAt: now.Add(-time.Second)}
+	repo.On("Lock", ctx, "POST /login|ip:10.0.0.1", ratelimit.NewBucket(limit, now)).Return(locked, nil)
+	repo.On("Save", ctx, "POST /login|ip:10.0.0.1", mock.MatchedBy(func(b *ratelimit.Bucket) bool {
+		return b.Tokens == 0 && b.UpdatedAt.Equal(now)
+	})).Return(nil)
+
+	decision, err := store.Take(ctx, "POST /login|ip:10.0.0.1", limit)
+	require.NoError(t, err)
+	assert.True(t, decision.Allowed)
+	repo.AssertExpectations(t)
+}
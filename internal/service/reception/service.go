@@ -5,11 +5,16 @@ import (
 	"errors"
 	"time"
 
+	"github.com/avito/pvz/internal/domain/assignment"
+	"github.com/avito/pvz/internal/domain/audit"
+	"github.com/avito/pvz/internal/domain/event"
+	"github.com/avito/pvz/internal/domain/outbox"
 	"github.com/avito/pvz/internal/domain/product"
 	"github.com/avito/pvz/internal/domain/pvz"
 	"github.com/avito/pvz/internal/domain/reception"
 	"github.com/avito/pvz/internal/domain/transaction"
 	"github.com/avito/pvz/internal/metrics"
+	"github.com/avito/pvz/pkg/auth"
 	"github.com/google/uuid"
 )
 
@@ -18,6 +23,7 @@ var (
 	ErrReceptionNotFound     = errors.New("reception not found")
 	ErrReceptionAlreadyOpen  = errors.New("reception already open")
 	ErrReceptionAlreadyClose = errors.New("reception already close")
+	ErrVersionConflict       = errors.New("reception version conflict")
 )
 
 // Service определяет бизнес-логику для работы с приемками
@@ -26,15 +32,27 @@ type Service struct {
 	pvzRepo       pvz.Repository
 	txManager     transaction.Manager
 	productRepo   product.Repository
+	events        event.Bus
+	outbox        outbox.Writer
+	audit         audit.Writer
+	access        assignment.Access
 }
 
-// New создает новый экземпляр Service
-func New(receptionRepo reception.Repository, pvzRepo pvz.Repository, txManager transaction.Manager, productRepo product.Repository) *Service {
+// New создает новый экземпляр Service.
+// События об открытии и закрытии приемок записываются в outbox в той же транзакции,
+// что и изменение приемки, и передаются в events после ее фиксации.
+// В той же транзакции изменения приемок и товаров записываются в журнал audit.
+// Изменять приемки ПВЗ может только вызывающий, которому ПВЗ доступен по access.
+func New(receptionRepo reception.Repository, pvzRepo pvz.Repository, txManager transaction.Manager, productRepo product.Repository, events event.Bus, outbox outbox.Writer, audit audit.Writer, access assignment.Access) *Service {
 	return &Service{
 		receptionRepo: receptionRepo,
 		pvzRepo:       pvzRepo,
 		txManager:     txManager,
 		productRepo:   productRepo,
+		events:        events,
+		outbox:        outbox,
+		audit:         audit,
+		access:        access,
 	}
 }
 
@@ -42,8 +60,13 @@ func New(receptionRepo reception.Repository, pvzRepo pvz.Repository, txManager t
 func (s *Service) Create(ctx context.Context, pvzID uuid.UUID) (*reception.Reception, error) {
 	start := time.Now()
 	var result *reception.Reception
+	var opened event.ReceptionOpened
 
 	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.access.CheckPVZ(ctx, pvzID); err != nil {
+			return err
+		}
+
 		// Проверяем существование ПВЗ
 		if _, err := s.pvzRepo.GetByID(ctx, pvzID); err != nil {
 			return ErrPVZNotFound
@@ -62,6 +85,18 @@ func (s *Service) Create(ctx context.Context, pvzID uuid.UUID) (*reception.Recep
 			return err
 		}
 
+		opened = event.ReceptionOpened{
+			PVZID:       newReception.PVZID,
+			ReceptionID: newReception.ID,
+			OccurredAt:  newReception.DateTime,
+		}
+		if err := s.record(ctx, opened); err != nil {
+			return err
+		}
+		if err := s.recordAudit(ctx, audit.EntityReception, newReception.ID, audit.ActionCreate, nil, receptionState(newReception)); err != nil {
+			return err
+		}
+
 		result = newReception
 		return nil
 	})
@@ -73,21 +108,52 @@ func (s *Service) Create(ctx context.Context, pvzID uuid.UUID) (*reception.Recep
 		return nil, err
 	}
 
-	metrics.ReceptionCreatedTotal.Inc()
+	s.events.Raise(ctx, opened)
 	return result, nil
 }
 
-// Close закрывает приемку
+// Close закрывает приемку. Если приемку одновременно изменил другой запрос,
+// возвращает ErrVersionConflict.
 func (s *Service) Close(ctx context.Context, pvzID uuid.UUID) error {
-	return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
-		reception, err := s.receptionRepo.GetLastOpen(ctx, pvzID)
+	var closed event.ReceptionClosed
+
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.access.CheckPVZ(ctx, pvzID); err != nil {
+			return err
+		}
+
+		rec, err := s.receptionRepo.GetLastOpen(ctx, pvzID)
 		if err != nil {
 			return err
 		}
 
-		reception.Status = "close"
-		return s.receptionRepo.Update(ctx, reception)
+		before := receptionState(rec)
+		rec.Status = "close"
+		// Обновление блокирует строку приемки до записи события в outbox, поэтому
+		// закрытие фиксируется после уже начатых изменений ее товаров
+		if err := s.receptionRepo.Update(ctx, rec); err != nil {
+			return err
+		}
+
+		closed = event.ReceptionClosed{
+			PVZID:       rec.PVZID,
+			ReceptionID: rec.ID,
+			OccurredAt:  time.Now(),
+		}
+		if err := s.record(ctx, closed); err != nil {
+			return err
+		}
+		return s.recordAudit(ctx, audit.EntityReception, rec.ID, audit.ActionClose, before, receptionState(rec))
 	})
+	if errors.Is(err, reception.ErrVersionConflict) {
+		return ErrVersionConflict
+	}
+	if err != nil {
+		return err
+	}
+
+	s.events.Raise(ctx, closed)
+	return nil
 }
 
 // GetByID получает приемку по ID
@@ -121,6 +187,14 @@ func (s *Service) List(ctx context.Context, offset, limit int) ([]*reception.Rec
 	return s.receptionRepo.List(ctx, offset, limit)
 }
 
+// ListByFilter возвращает список приемок, отобранных и отсортированных по фильтру
+func (s *Service) ListByFilter(ctx context.Context, filter reception.ListFilter) ([]*reception.Reception, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	return s.receptionRepo.ListByFilter(ctx, filter)
+}
+
 // GetProducts получает список товаров приемки
 func (s *Service) GetProducts(ctx context.Context, receptionID uuid.UUID) ([]*product.Product, error) {
 	return s.receptionRepo.GetProducts(ctx, receptionID)
@@ -129,13 +203,18 @@ func (s *Service) GetProducts(ctx context.Context, receptionID uuid.UUID) ([]*pr
 // CreateProduct добавляет товар в приемку
 func (s *Service) CreateProduct(ctx context.Context, receptionID uuid.UUID, productType string) error {
 	start := time.Now()
+	var added event.ProductAdded
 
 	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
-		// Получаем приемку
-		r, err := s.receptionRepo.GetByID(ctx, receptionID)
+		// Приемка блокируется до конца транзакции, чтобы события ее товаров
+		// фиксировались в порядке записи в outbox
+		r, err := s.receptionRepo.GetByIDForUpdate(ctx, receptionID)
 		if err != nil {
 			return ErrReceptionNotFound
 		}
+		if err := s.access.CheckPVZ(ctx, r.PVZID); err != nil {
+			return err
+		}
 
 		// Проверяем статус
 		if r.Status == reception.StatusClose {
@@ -144,7 +223,21 @@ func (s *Service) CreateProduct(ctx context.Context, receptionID uuid.UUID, prod
 
 		// Создаем товар
 		p := product.New(r.ID, product.Type(productType))
-		return s.productRepo.Create(ctx, p)
+		if err := s.productRepo.Create(ctx, p); err != nil {
+			return err
+		}
+
+		added = event.ProductAdded{
+			PVZID:       r.PVZID,
+			ReceptionID: p.ReceptionID,
+			ProductID:   p.ID,
+			ProductType: p.Type,
+			OccurredAt:  p.DateTime,
+		}
+		if err := s.record(ctx, added); err != nil {
+			return err
+		}
+		return s.recordAudit(ctx, audit.EntityProduct, p.ID, audit.ActionCreate, nil, productState(p))
 	})
 
 	// Обновляем метрики
@@ -154,6 +247,44 @@ func (s *Service) CreateProduct(ctx context.Context, receptionID uuid.UUID, prod
 		return err
 	}
 
-	metrics.ProductCreatedTotal.Inc()
+	s.events.Raise(ctx, added)
 	return nil
 }
+
+// record записывает события в outbox транзакции из ctx
+func (s *Service) record(ctx context.Context, events ...event.Feed) error {
+	messages, err := outbox.FromFeed(events...)
+	if err != nil {
+		return err
+	}
+	return s.outbox.Add(ctx, messages...)
+}
+
+// recordAudit записывает изменение сущности в журнал аудита транзакции из ctx
+func (s *Service) recordAudit(ctx context.Context, entityType audit.EntityType, entityID uuid.UUID, action audit.Action, before, after audit.State) error {
+	actorID, _ := auth.GetUserID(ctx)
+	entry, err := audit.NewEntry(ctx, actorID, entityType, entityID, action, before, after)
+	if err != nil {
+		return err
+	}
+	return s.audit.Record(ctx, entry)
+}
+
+// receptionState снимок приемки для журнала аудита
+func receptionState(r *reception.Reception) audit.State {
+	return audit.State{
+		"pvzId":    r.PVZID,
+		"dateTime": r.DateTime,
+		"status":   r.Status,
+		"version":  r.Version,
+	}
+}
+
+// productState снимок товара для журнала аудита
+func productState(p *product.Product) audit.State {
+	return audit.State{
+		"receptionId": p.ReceptionID,
+		"dateTime":    p.DateTime,
+		"type":        p.Type,
+	}
+}
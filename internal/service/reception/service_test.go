@@ -6,12 +6,17 @@ import (
 	"testing"
 	"time"
 
+	"github.com/avito/pvz/internal/domain/assignment"
+	"github.com/avito/pvz/internal/domain/audit"
+	"github.com/avito/pvz/internal/domain/event"
+	"github.com/avito/pvz/internal/domain/outbox"
 	"github.com/avito/pvz/internal/domain/product"
 	"github.com/avito/pvz/internal/domain/pvz"
 	"github.com/avito/pvz/internal/domain/reception"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // MockReceptionRepository реализует мок для reception.Repository
@@ -32,6 +37,14 @@ func (m *MockReceptionRepository) GetByID(ctx context.Context, id uuid.UUID) (*r
 	return args.Get(0).(*reception.Reception), args.Error(1)
 }
 
+func (m *MockReceptionRepository) GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*reception.Reception, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*reception.Reception), args.Error(1)
+}
+
 func (m *MockReceptionRepository) Update(ctx context.Context, r *reception.Reception) error {
 	args := m.Called(ctx, r)
 	return args.Error(0)
@@ -42,6 +55,14 @@ func (m *MockReceptionRepository) List(ctx context.Context, offset, limit int) (
 	return args.Get(0).([]*reception.Reception), args.Error(1)
 }
 
+func (m *MockReceptionRepository) ListByFilter(ctx context.Context, filter reception.ListFilter) ([]*reception.Reception, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*reception.Reception), args.Error(1)
+}
+
 func (m *MockReceptionRepository) GetOpenByPVZID(ctx context.Context, pvzID uuid.UUID) (*reception.Reception, error) {
 	args := m.Called(ctx, pvzID)
 	if args.Get(0) == nil {
@@ -101,6 +122,14 @@ func (m *MockPVZRepository) List(ctx context.Context, offset, limit int) ([]*pvz
 	return args.Get(0).([]*pvz.PVZ), args.Error(1)
 }
 
+func (m *MockPVZRepository) ListByFilter(ctx context.Context, filter pvz.ListFilter) ([]*pvz.PVZ, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*pvz.PVZ), args.Error(1)
+}
+
 func (m *MockPVZRepository) GetAll(ctx context.Context) ([]*pvz.PVZ, error) {
 	args := m.Called(ctx)
 	return args.Get(0).([]*pvz.PVZ), args.Error(1)
@@ -111,6 +140,18 @@ func (m *MockPVZRepository) GetWithReceptions(ctx context.Context, startDate, en
 	return args.Get(0).([]*pvz.PVZWithReceptions), args.Error(1)
 }
 
+func (m *MockPVZRepository) GetWithReceptionsByCursor(ctx context.Context, startDate, endDate time.Time, cursor *pvz.Cursor, limit int) ([]*pvz.PVZWithReceptions, *pvz.Cursor, error) {
+	args := m.Called(ctx, startDate, endDate, cursor, limit)
+	var next *pvz.Cursor
+	if c, ok := args.Get(1).(*pvz.Cursor); ok {
+		next = c
+	}
+	if args.Get(0) == nil {
+		return nil, next, args.Error(2)
+	}
+	return args.Get(0).([]*pvz.PVZWithReceptions), next, args.Error(2)
+}
+
 func (m *MockPVZRepository) GetByCity(ctx context.Context, city string) (*pvz.PVZ, error) {
 	args := m.Called(ctx, city)
 	if args.Get(0) == nil {
@@ -152,6 +193,14 @@ func (m *MockProductRepository) List(ctx context.Context, offset, limit int) ([]
 	return args.Get(0).([]*product.Product), args.Error(1)
 }
 
+func (m *MockProductRepository) ListByFilter(ctx context.Context, filter product.ListFilter) ([]*product.Product, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*product.Product), args.Error(1)
+}
+
 func (m *MockProductRepository) CreateBatch(ctx context.Context, products []*product.Product) error {
 	args := m.Called(ctx, products)
 	return args.Error(0)
@@ -188,6 +237,51 @@ func (m *MockTransactionManager) WithinTransaction(ctx context.Context, fn func(
 	return args.Error(0)
 }
 
+// recordingBus запоминает события, переданные в шину
+type recordingBus struct {
+	events []event.Domain
+}
+
+func (b *recordingBus) Raise(_ context.Context, events ...event.Domain) {
+	b.events = append(b.events, events...)
+}
+
+func (b *recordingBus) types() []event.Type {
+	var types []event.Type
+	for _, e := range b.events {
+		types = append(types, e.EventType())
+	}
+	return types
+}
+
+// recordingOutbox запоминает записанные в outbox сообщения
+type recordingOutbox struct {
+	messages []*outbox.Message
+}
+
+func (o *recordingOutbox) Add(_ context.Context, messages ...*outbox.Message) error {
+	o.messages = append(o.messages, messages...)
+	return nil
+}
+
+func (o *recordingOutbox) eventTypes() []string {
+	var types []string
+	for _, m := range o.messages {
+		types = append(types, m.EventType)
+	}
+	return types
+}
+
+// recordingAudit запоминает записи журнала аудита
+type recordingAudit struct {
+	entries []*audit.Entry
+}
+
+func (r *recordingAudit) Record(_ context.Context, entries ...*audit.Entry) error {
+	r.entries = append(r.entries, entries...)
+	return nil
+}
+
 func TestService_Create(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -258,13 +352,25 @@ func TestService_Create(t *testing.T) {
 			tx := new(MockTransactionManager)
 			tt.setupMocks(receptionRepo, pvzRepo, tx)
 
-			service := New(receptionRepo, pvzRepo, tx, productRepo)
+			bus := new(recordingBus)
+			messages := new(recordingOutbox)
+			entries := new(recordingAudit)
+			service := New(receptionRepo, pvzRepo, tx, productRepo, bus, messages, entries, assignment.AllowAll)
 			_, err := service.Create(context.Background(), tt.pvzID)
 
 			if tt.expectedError != nil {
 				assert.Equal(t, tt.expectedError, err)
+				assert.Empty(t, bus.events)
+				assert.Empty(t, messages.messages)
+				assert.Empty(t, entries.entries)
 			} else {
 				assert.NoError(t, err)
+				assert.Equal(t, []event.Type{event.TypeReceptionOpened}, bus.types())
+				assert.Contains(t, messages.eventTypes(), string(event.TypeReceptionOpened))
+				require.NotEmpty(t, entries.entries)
+				assert.Equal(t, audit.EntityReception, entries.entries[0].EntityType)
+				assert.Equal(t, audit.ActionCreate, entries.entries[0].Action)
+				assert.Nil(t, entries.entries[0].Before)
 			}
 
 			receptionRepo.AssertExpectations(t)
@@ -306,6 +412,19 @@ func TestService_Close(t *testing.T) {
 			},
 			expectedError: errors.New("not found"),
 		},
+		{
+			name:  "приемку одновременно изменили",
+			pvzID: uuid.New(),
+			setupMocks: func(receptionRepo *MockReceptionRepository, tx *MockTransactionManager) {
+				receptionRepo.On("GetLastOpen", mock.Anything, mock.AnythingOfType("uuid.UUID")).Return(&reception.Reception{Version: 1}, nil)
+				receptionRepo.On("Update", mock.Anything, mock.AnythingOfType("*reception.Reception")).Return(reception.ErrVersionConflict)
+				tx.On("WithinTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Run(func(args mock.Arguments) {
+					fn := args.Get(1).(func(context.Context) error)
+					fn(context.Background())
+				}).Return(reception.ErrVersionConflict)
+			},
+			expectedError: ErrVersionConflict,
+		},
 	}
 
 	for _, tt := range tests {
@@ -314,13 +433,25 @@ func TestService_Close(t *testing.T) {
 			tx := new(MockTransactionManager)
 			tt.setupMocks(receptionRepo, tx)
 
-			service := New(receptionRepo, nil, tx, nil)
+			bus := new(recordingBus)
+			messages := new(recordingOutbox)
+			entries := new(recordingAudit)
+			service := New(receptionRepo, nil, tx, nil, bus, messages, entries, assignment.AllowAll)
 			err := service.Close(context.Background(), tt.pvzID)
 
 			if tt.expectedError != nil {
 				assert.Equal(t, tt.expectedError, err)
+				assert.Empty(t, bus.events)
+				assert.Empty(t, messages.messages)
+				assert.Empty(t, entries.entries)
 			} else {
 				assert.NoError(t, err)
+				assert.Equal(t, []event.Type{event.TypeReceptionClosed}, bus.types())
+				assert.Contains(t, messages.eventTypes(), string(event.TypeReceptionClosed))
+				require.NotEmpty(t, entries.entries)
+				assert.Equal(t, audit.ActionClose, entries.entries[0].Action)
+				assert.JSONEq(t, `{"status":""}`, string(entries.entries[0].Before))
+				assert.JSONEq(t, `{"status":"close"}`, string(entries.entries[0].After))
 			}
 
 			receptionRepo.AssertExpectations(t)
@@ -359,7 +490,7 @@ func TestService_GetByID(t *testing.T) {
 			receptionRepo := new(MockReceptionRepository)
 			tt.setupMocks(receptionRepo)
 
-			service := New(receptionRepo, nil, nil, nil)
+			service := New(receptionRepo, nil, nil, nil, event.Discard, outbox.Discard, audit.Discard, assignment.AllowAll)
 			_, err := service.GetByID(context.Background(), tt.id)
 
 			if tt.expectedError != nil {
@@ -403,7 +534,7 @@ func TestService_GetOpenByPVZID(t *testing.T) {
 			receptionRepo := new(MockReceptionRepository)
 			tt.setupMocks(receptionRepo)
 
-			service := New(receptionRepo, nil, nil, nil)
+			service := New(receptionRepo, nil, nil, nil, event.Discard, outbox.Discard, audit.Discard, assignment.AllowAll)
 			_, err := service.GetOpenByPVZID(context.Background(), tt.pvzID)
 
 			if tt.expectedError != nil {
@@ -450,7 +581,7 @@ func TestService_List(t *testing.T) {
 			receptionRepo := new(MockReceptionRepository)
 			tt.setupMocks(receptionRepo)
 
-			service := New(receptionRepo, nil, nil, nil)
+			service := New(receptionRepo, nil, nil, nil, event.Discard, outbox.Discard, audit.Discard, assignment.AllowAll)
 			_, err := service.List(context.Background(), tt.offset, tt.limit)
 
 			if tt.expectedError != nil {
@@ -494,7 +625,7 @@ func TestService_GetProducts(t *testing.T) {
 			receptionRepo := new(MockReceptionRepository)
 			tt.setupMocks(receptionRepo)
 
-			service := New(receptionRepo, nil, nil, nil)
+			service := New(receptionRepo, nil, nil, nil, event.Discard, outbox.Discard, audit.Discard, assignment.AllowAll)
 			_, err := service.GetProducts(context.Background(), tt.receptionID)
 
 			if tt.expectedError != nil {
@@ -521,7 +652,7 @@ func TestService_CreateProduct(t *testing.T) {
 			receptionID: uuid.New(),
 			productType: string(product.TypeElectronics),
 			setupMocks: func(receptionRepo *MockReceptionRepository, productRepo *MockProductRepository, tx *MockTransactionManager) {
-				receptionRepo.On("GetByID", mock.Anything, mock.AnythingOfType("uuid.UUID")).Return(&reception.Reception{Status: reception.StatusInProgress}, nil)
+				receptionRepo.On("GetByIDForUpdate", mock.Anything, mock.AnythingOfType("uuid.UUID")).Return(&reception.Reception{Status: reception.StatusInProgress}, nil)
 				productRepo.On("Create", mock.Anything, mock.AnythingOfType("*product.Product")).Return(nil)
 				tx.On("WithinTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Run(func(args mock.Arguments) {
 					fn := args.Get(1).(func(context.Context) error)
@@ -535,7 +666,7 @@ func TestService_CreateProduct(t *testing.T) {
 			receptionID: uuid.New(),
 			productType: string(product.TypeElectronics),
 			setupMocks: func(receptionRepo *MockReceptionRepository, productRepo *MockProductRepository, tx *MockTransactionManager) {
-				receptionRepo.On("GetByID", mock.Anything, mock.AnythingOfType("uuid.UUID")).Return(nil, errors.New("not found"))
+				receptionRepo.On("GetByIDForUpdate", mock.Anything, mock.AnythingOfType("uuid.UUID")).Return(nil, errors.New("not found"))
 				tx.On("WithinTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Run(func(args mock.Arguments) {
 					fn := args.Get(1).(func(context.Context) error)
 					fn(context.Background())
@@ -548,7 +679,7 @@ func TestService_CreateProduct(t *testing.T) {
 			receptionID: uuid.New(),
 			productType: string(product.TypeElectronics),
 			setupMocks: func(receptionRepo *MockReceptionRepository, productRepo *MockProductRepository, tx *MockTransactionManager) {
-				receptionRepo.On("GetByID", mock.Anything, mock.AnythingOfType("uuid.UUID")).Return(&reception.Reception{Status: reception.StatusClose}, nil)
+				receptionRepo.On("GetByIDForUpdate", mock.Anything, mock.AnythingOfType("uuid.UUID")).Return(&reception.Reception{Status: reception.StatusClose}, nil)
 				tx.On("WithinTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Run(func(args mock.Arguments) {
 					fn := args.Get(1).(func(context.Context) error)
 					fn(context.Background())
@@ -565,7 +696,7 @@ func TestService_CreateProduct(t *testing.T) {
 			tx := new(MockTransactionManager)
 			tt.setupMocks(receptionRepo, productRepo, tx)
 
-			service := New(receptionRepo, nil, tx, productRepo)
+			service := New(receptionRepo, nil, tx, productRepo, event.Discard, outbox.Discard, audit.Discard, assignment.AllowAll)
 			err := service.CreateProduct(context.Background(), tt.receptionID, tt.productType)
 
 			if tt.expectedError != nil {
@@ -580,3 +711,53 @@ func TestService_CreateProduct(t *testing.T) {
 		})
 	}
 }
+
+func TestService_PVZAccess(t *testing.T) {
+	pvzID := uuid.New()
+	denied := assignment.AccessFunc(func(ctx context.Context, id uuid.UUID) error {
+		assert.Equal(t, pvzID, id)
+		return assignment.ErrNotAssigned
+	})
+
+	// runTx выполняет функцию транзакции и проверяет, что она вернула отказ в доступе
+	runTx := func(t *testing.T) *MockTransactionManager {
+		tx := new(MockTransactionManager)
+		tx.On("WithinTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Run(func(args mock.Arguments) {
+			fn := args.Get(1).(func(context.Context) error)
+			assert.ErrorIs(t, fn(context.Background()), assignment.ErrNotAssigned)
+		}).Return(assignment.ErrNotAssigned)
+		return tx
+	}
+
+	t.Run("создание приемки в чужом ПВЗ", func(t *testing.T) {
+		receptionRepo := new(MockReceptionRepository)
+		pvzRepo := new(MockPVZRepository)
+		service := New(receptionRepo, pvzRepo, runTx(t), nil, event.Discard, outbox.Discard, audit.Discard, denied)
+
+		_, err := service.Create(context.Background(), pvzID)
+		assert.ErrorIs(t, err, assignment.ErrNotAssigned)
+		receptionRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		pvzRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("закрытие приемки в чужом ПВЗ", func(t *testing.T) {
+		receptionRepo := new(MockReceptionRepository)
+		service := New(receptionRepo, nil, runTx(t), nil, event.Discard, outbox.Discard, audit.Discard, denied)
+
+		err := service.Close(context.Background(), pvzID)
+		assert.ErrorIs(t, err, assignment.ErrNotAssigned)
+		receptionRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("добавление товара в приемку чужого ПВЗ", func(t *testing.T) {
+		receptionID := uuid.New()
+		receptionRepo := new(MockReceptionRepository)
+		receptionRepo.On("GetByIDForUpdate", mock.Anything, receptionID).Return(&reception.Reception{ID: receptionID, PVZID: pvzID, Status: reception.StatusInProgress}, nil)
+		productRepo := new(MockProductRepository)
+		service := New(receptionRepo, nil, runTx(t), productRepo, event.Discard, outbox.Discard, audit.Discard, denied)
+
+		err := service.CreateProduct(context.Background(), receptionID, string(product.TypeElectronics))
+		assert.ErrorIs(t, err, assignment.ErrNotAssigned)
+		productRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,88 @@
+package session
+
+import (
+	"sync"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/session"
+	"github.com/avito/pvz/pkg/auth"
+	"github.com/google/uuid"
+)
+
+// RevocationList кэш списка отзыва токенов доступа. Проверка токена не
+// обращается к базе: список загружается из базы периодически, а отзывы этого
+// экземпляра попадают в него сразу.
+type RevocationList struct {
+	mu       sync.RWMutex
+	sessions map[uuid.UUID]session.Revocation
+	users    map[uuid.UUID]session.Revocation
+}
+
+// NewRevocationList создает пустой список отзыва
+func NewRevocationList() *RevocationList {
+	return &RevocationList{
+		sessions: make(map[uuid.UUID]session.Revocation),
+		users:    make(map[uuid.UUID]session.Revocation),
+	}
+}
+
+// Add добавляет запись в список
+func (l *RevocationList) Add(r *session.Revocation) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.add(r)
+}
+
+// Replace заменяет содержимое списка записями из базы
+func (l *RevocationList) Replace(revocations []*session.Revocation) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sessions = make(map[uuid.UUID]session.Revocation, len(revocations))
+	l.users = make(map[uuid.UUID]session.Revocation)
+	for _, r := range revocations {
+		l.add(r)
+	}
+}
+
+func (l *RevocationList) add(r *session.Revocation) {
+	entries := l.sessions
+	if r.Kind == session.RevocationUser {
+		entries = l.users
+	}
+
+	// Повторный отзыв сдвигает время отзыва и срок хранения только вперед
+	if existing, ok := entries[r.SubjectID]; ok {
+		if existing.RevokedAt.After(r.RevokedAt) {
+			r.RevokedAt = existing.RevokedAt
+		}
+		if existing.ExpiresAt.After(r.ExpiresAt) {
+			r.ExpiresAt = existing.ExpiresAt
+		}
+	}
+	entries[r.SubjectID] = *r
+}
+
+// Revoked сообщает, отозван ли токен к моменту now. Токен отозван, если
+// отозвана его сессия или пользователь вышел на всех устройствах после его
+// выпуска. iat хранится с точностью до секунды, поэтому по пользователю
+// отзываются токены, выпущенные раньше секунды отзыва, а токены из этой
+// секунды отзываются вместе с их сессиями.
+func (l *RevocationList) Revoked(claims *auth.Claims, now time.Time) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if claims.SessionID != uuid.Nil {
+		if r, ok := l.sessions[claims.SessionID]; ok && now.Before(r.ExpiresAt) {
+			return true
+		}
+	}
+
+	if r, ok := l.users[claims.UserID]; ok && now.Before(r.ExpiresAt) {
+		if claims.IssuedAt == nil || claims.IssuedAt.Time.Before(r.RevokedAt.Truncate(time.Second)) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,63 @@
+package session
+
+import (
+	"testing"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/session"
+	"github.com/avito/pvz/pkg/auth"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRevocationList_Revoked(t *testing.T) {
+	revokedAt := time.Date(2026, time.October, 1, 12, 0, 0, 500_000_000, time.UTC)
+	userID := uuid.New()
+	sessionID := uuid.New()
+
+	list := NewRevocationList()
+	list.Add(&session.Revocation{Kind: session.RevocationUser, SubjectID: userID, RevokedAt: revokedAt, ExpiresAt: revokedAt.Add(time.Hour)})
+	list.Add(&session.Revocation{Kind: session.RevocationSession, SubjectID: sessionID, RevokedAt: revokedAt, ExpiresAt: revokedAt.Add(time.Hour)})
+
+	claimsAt := func(userID, sessionID uuid.UUID, issuedAt time.Time) *auth.Claims {
+		return &auth.Claims{
+			UserID:           userID,
+			SessionID:        sessionID,
+			RegisteredClaims: jwt.RegisteredClaims{IssuedAt: jwt.NewNumericDate(issuedAt)},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		claims *auth.Claims
+		now    time.Time
+		want   bool
+	}{
+		{name: "отозванная сессия", claims: claimsAt(uuid.New(), sessionID, revokedAt), now: revokedAt, want: true},
+		{name: "отозванная сессия после срока хранения записи", claims: claimsAt(uuid.New(), sessionID, revokedAt), now: revokedAt.Add(time.Hour), want: false},
+		{name: "токен пользователя до отзыва", claims: claimsAt(userID, uuid.Nil, revokedAt.Add(-time.Minute)), now: revokedAt, want: true},
+		{name: "токен пользователя после отзыва", claims: claimsAt(userID, uuid.New(), revokedAt.Add(time.Minute)), now: revokedAt, want: false},
+		{name: "токен пользователя в секунду отзыва", claims: claimsAt(userID, uuid.New(), revokedAt), now: revokedAt, want: false},
+		{name: "другой пользователь", claims: claimsAt(uuid.New(), uuid.New(), revokedAt.Add(-time.Minute)), now: revokedAt, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, list.Revoked(tt.claims, tt.now))
+		})
+	}
+}
+
+func TestRevocationList_Replace(t *testing.T) {
+	now := time.Now()
+	stale := uuid.New()
+	fresh := uuid.New()
+
+	list := NewRevocationList()
+	list.Add(&session.Revocation{Kind: session.RevocationSession, SubjectID: stale, RevokedAt: now, ExpiresAt: now.Add(time.Hour)})
+	list.Replace([]*session.Revocation{{Kind: session.RevocationSession, SubjectID: fresh, RevokedAt: now, ExpiresAt: now.Add(time.Hour)}})
+
+	assert.False(t, list.Revoked(&auth.Claims{SessionID: stale}, now))
+	assert.True(t, list.Revoked(&auth.Claims{SessionID: fresh}, now))
+}
@@ -0,0 +1,343 @@
+// Package session управляет сессиями входа. При входе выдается пара токенов:
+// короткоживущий токен доступа и токен обновления. Токен обновления одноразовый:
+// при обмене он заменяется новым в той же сессии. Повторное предъявление уже
+// использованного токена означает, что он утек, и отзывает всю сессию. Отозванные
+// сессии попадают в список отзыва, по которому проверяются токены доступа.
+package session
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/session"
+	"github.com/avito/pvz/internal/domain/transaction"
+	"github.com/avito/pvz/internal/domain/user"
+	"github.com/avito/pvz/pkg/auth"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token reused")
+	ErrTokenRevoked        = errors.New("token revoked")
+)
+
+// Config параметры сессий
+type Config struct {
+	// RefreshTTL время жизни токена обновления. Обмен токена продлевает сессию.
+	RefreshTTL time.Duration
+	// SyncInterval период загрузки списка отзыва из базы. Отзыв на другом
+	// экземпляре сервиса начинает действовать здесь не позже чем через этот период.
+	SyncInterval time.Duration
+}
+
+// DefaultConfig параметры сессий по умолчанию
+var DefaultConfig = Config{
+	RefreshTTL:   30 * 24 * time.Hour,
+	SyncInterval: 10 * time.Second,
+}
+
+// TokenService выпускает и проверяет токены доступа
+type TokenService interface {
+	GenerateSessionToken(userID uuid.UUID, role user.Role, sessionID uuid.UUID) (string, error)
+	ValidateToken(token string) (*auth.Claims, error)
+	// MaxTokenAge время, в течение которого выпущенный токен проходит проверку
+	MaxTokenAge() time.Duration
+}
+
+// Tokens пара токенов сессии
+type Tokens struct {
+	AccessToken  string
+	RefreshToken string
+	SessionID    uuid.UUID
+}
+
+// Service управляет сессиями и проверяет токены доступа по списку отзыва
+type Service struct {
+	repo        session.Repository
+	users       user.Repository
+	txManager   transaction.Manager
+	tokens      TokenService
+	cfg         Config
+	revocations *RevocationList
+	now         func() time.Time
+}
+
+// New создает новый экземпляр Service
+func New(repo session.Repository, users user.Repository, txManager transaction.Manager, tokens TokenService, cfg Config) *Service {
+	return &Service{
+		repo:        repo,
+		users:       users,
+		txManager:   txManager,
+		tokens:      tokens,
+		cfg:         cfg,
+		revocations: NewRevocationList(),
+		now:         time.Now,
+	}
+}
+
+// Start открывает новую сессию пользователя и выдает для нее пару токенов
+func (s *Service) Start(ctx context.Context, u *user.User) (*Tokens, error) {
+	var result *Tokens
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		refreshToken, err := s.newRefreshToken(uuid.New(), u.ID)
+		if err != nil {
+			return err
+		}
+		if err := s.repo.CreateRefreshToken(ctx, refreshToken.token); err != nil {
+			return err
+		}
+
+		result, err = s.tokenPair(u, refreshToken)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Refresh обменивает токен обновления на новую пару токенов той же сессии.
+// Повторный обмен уже использованного токена отзывает сессию и возвращает
+// ErrRefreshTokenReused.
+func (s *Service) Refresh(ctx context.Context, raw string) (*Tokens, error) {
+	var (
+		result *Tokens
+		reused *session.RefreshToken
+	)
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		current, err := s.repo.GetRefreshTokenByHash(ctx, auth.HashRefreshToken(raw))
+		if errors.Is(err, session.ErrNotFound) {
+			return ErrInvalidRefreshToken
+		}
+		if err != nil {
+			return err
+		}
+
+		now := s.now()
+		if current.RevokedAt != nil || current.Expired(now) {
+			return ErrInvalidRefreshToken
+		}
+		if current.UsedAt != nil {
+			reused = current
+			return nil
+		}
+
+		// Роль берется из базы: изменение роли применяется при следующем обмене,
+		// а удаленный пользователь не может продлить сессию
+		u, err := s.users.GetByID(ctx, current.UserID)
+		if errors.Is(err, user.ErrNotFound) || errors.Is(err, user.ErrUserNotFound) {
+			return ErrInvalidRefreshToken
+		}
+		if err != nil {
+			return err
+		}
+
+		next, err := s.newRefreshToken(current.SessionID, current.UserID)
+		if err != nil {
+			return err
+		}
+
+		// Параллельный обмен того же токена уже заменил его: считаем это повторным использованием
+		used, err := s.repo.UseRefreshToken(ctx, current.ID, next.token.ID, now)
+		if err != nil {
+			return err
+		}
+		if !used {
+			reused = current
+			return nil
+		}
+		if err := s.repo.CreateRefreshToken(ctx, next.token); err != nil {
+			return err
+		}
+
+		result, err = s.tokenPair(u, next)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if reused != nil {
+		log.Printf("Refresh token reuse detected for session %s of user %s, revoking session", reused.SessionID, reused.UserID)
+		if err := s.RevokeSession(ctx, reused.SessionID); err != nil {
+			return nil, err
+		}
+		return nil, ErrRefreshTokenReused
+	}
+
+	return result, nil
+}
+
+// RevokeSession завершает сессию: отзывает ее токены обновления и токены доступа
+func (s *Service) RevokeSession(ctx context.Context, sessionID uuid.UUID) error {
+	now := s.now()
+	revocation := &session.Revocation{
+		Kind:      session.RevocationSession,
+		SubjectID: sessionID,
+		RevokedAt: now,
+		ExpiresAt: now.Add(s.tokens.MaxTokenAge()),
+	}
+
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.RevokeSession(ctx, sessionID, now); err != nil {
+			return err
+		}
+		return s.repo.AddRevocation(ctx, revocation)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.revocations.Add(revocation)
+	return nil
+}
+
+// Logout завершает сессию sessionID из токена доступа. Токены, выпущенные
+// без сессии, отзываются только выходом на всех устройствах.
+func (s *Service) Logout(ctx context.Context, sessionID uuid.UUID) error {
+	if sessionID == uuid.Nil {
+		return nil
+	}
+	return s.RevokeSession(ctx, sessionID)
+}
+
+// LogoutAll завершает все сессии пользователя и отзывает все выпущенные ему токены доступа
+func (s *Service) LogoutAll(ctx context.Context, userID uuid.UUID) error {
+	now := s.now()
+	expiresAt := now.Add(s.tokens.MaxTokenAge())
+	revocations := []*session.Revocation{{
+		Kind:      session.RevocationUser,
+		SubjectID: userID,
+		RevokedAt: now,
+		ExpiresAt: expiresAt,
+	}}
+
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		sessionIDs, err := s.repo.RevokeUserSessions(ctx, userID, now)
+		if err != nil {
+			return err
+		}
+
+		// Сессии отзываются явно, чтобы токены, выпущенные в секунду отзыва,
+		// тоже перестали действовать
+		for _, id := range sessionIDs {
+			revocations = append(revocations, &session.Revocation{
+				Kind:      session.RevocationSession,
+				SubjectID: id,
+				RevokedAt: now,
+				ExpiresAt: expiresAt,
+			})
+		}
+
+		for _, r := range revocations {
+			if err := s.repo.AddRevocation(ctx, r); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, r := range revocations {
+		s.revocations.Add(r)
+	}
+	return nil
+}
+
+// ValidateToken проверяет токен доступа и сверяет его со списком отзыва
+func (s *Service) ValidateToken(token string) (*auth.Claims, error) {
+	claims, err := s.tokens.ValidateToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.revocations.Revoked(claims, s.now()) {
+		return nil, ErrTokenRevoked
+	}
+
+	return claims, nil
+}
+
+// Sync загружает список отзыва из базы и удаляет устаревшие записи
+func (s *Service) Sync(ctx context.Context) error {
+	now := s.now()
+
+	if _, err := s.repo.DeleteExpiredRevocations(ctx, now); err != nil {
+		return err
+	}
+	if _, err := s.repo.DeleteExpiredRefreshTokens(ctx, now); err != nil {
+		return err
+	}
+
+	revocations, err := s.repo.ListRevocations(ctx, now)
+	if err != nil {
+		return err
+	}
+	s.revocations.Replace(revocations)
+
+	return nil
+}
+
+// Run синхронизирует список отзыва до отмены ctx
+func (s *Service) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.SyncInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Sync(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Failed to sync token revocation list: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// issuedRefreshToken сохраненный токен обновления и его значение для клиента
+type issuedRefreshToken struct {
+	token *session.RefreshToken
+	raw   string
+}
+
+// newRefreshToken генерирует токен обновления сессии
+func (s *Service) newRefreshToken(sessionID, userID uuid.UUID) (*issuedRefreshToken, error) {
+	raw, err := auth.NewRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	token := &session.RefreshToken{
+		ID:        uuid.New(),
+		SessionID: sessionID,
+		UserID:    userID,
+		TokenHash: auth.HashRefreshToken(raw),
+		CreatedAt: now,
+		ExpiresAt: now.Add(s.cfg.RefreshTTL),
+	}
+
+	return &issuedRefreshToken{token: token, raw: raw}, nil
+}
+
+// tokenPair выпускает токен доступа к сессии токена обновления
+func (s *Service) tokenPair(u *user.User, refresh *issuedRefreshToken) (*Tokens, error) {
+	accessToken, err := s.tokens.GenerateSessionToken(u.ID, u.Role, refresh.token.SessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Tokens{
+		AccessToken:  accessToken,
+		RefreshToken: refresh.raw,
+		SessionID:    refresh.token.SessionID,
+	}, nil
+}
@@ -0,0 +1,326 @@
+package session
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/session"
+	"github.com/avito/pvz/internal/domain/user"
+	"github.com/avito/pvz/pkg/auth"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockRepository мок для session.Repository
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) CreateRefreshToken(ctx context.Context, token *session.RefreshToken) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetRefreshTokenByHash(ctx context.Context, hash string) (*session.RefreshToken, error) {
+	args := m.Called(ctx, hash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*session.RefreshToken), args.Error(1)
+}
+
+func (m *MockRepository) UseRefreshToken(ctx context.Context, id, replacedBy uuid.UUID, at time.Time) (bool, error) {
+	args := m.Called(ctx, id, replacedBy, at)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRepository) RevokeSession(ctx context.Context, sessionID uuid.UUID, at time.Time) error {
+	args := m.Called(ctx, sessionID, at)
+	return args.Error(0)
+}
+
+func (m *MockRepository) RevokeUserSessions(ctx context.Context, userID uuid.UUID, at time.Time) ([]uuid.UUID, error) {
+	args := m.Called(ctx, userID, at)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+func (m *MockRepository) DeleteExpiredRefreshTokens(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockRepository) AddRevocation(ctx context.Context, revocation *session.Revocation) error {
+	args := m.Called(ctx, revocation)
+	return args.Error(0)
+}
+
+func (m *MockRepository) ListRevocations(ctx context.Context, now time.Time) ([]*session.Revocation, error) {
+	args := m.Called(ctx, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*session.Revocation), args.Error(1)
+}
+
+func (m *MockRepository) DeleteExpiredRevocations(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// MockUserRepository мок для user.Repository
+type MockUserRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserRepository) Create(ctx context.Context, u *user.User) error {
+	args := m.Called(ctx, u)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) GetByID(ctx context.Context, id uuid.UUID) (*user.User, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetByEmail(ctx context.Context, email string) (*user.User, error) {
+	args := m.Called(ctx, email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.User), args.Error(1)
+}
+
+func (m *MockUserRepository) Update(ctx context.Context, u *user.User) error {
+	args := m.Called(ctx, u)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) List(ctx context.Context, offset, limit int) ([]*user.User, error) {
+	args := m.Called(ctx, offset, limit)
+	return args.Get(0).([]*user.User), args.Error(1)
+}
+
+// fakeTxManager выполняет функцию без транзакции
+type fakeTxManager struct{}
+
+func (fakeTxManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// newTestService создает сервис с настоящим сервисом токенов и фиксированным временем
+func newTestService(t *testing.T, repo *MockRepository, users *MockUserRepository, now time.Time) *Service {
+	t.Helper()
+	tokens, err := auth.NewTokenService(auth.DefaultTokenConfig)
+	require.NoError(t, err)
+
+	s := New(repo, users, fakeTxManager{}, tokens, DefaultConfig)
+	s.now = func() time.Time { return now }
+	return s
+}
+
+func TestService_Start(t *testing.T) {
+	now := time.Now()
+	u := &user.User{ID: uuid.New(), Role: user.RoleEmployee}
+
+	repo := new(MockRepository)
+	repo.On("CreateRefreshToken", mock.Anything, mock.MatchedBy(func(rt *session.RefreshToken) bool {
+		return rt.UserID == u.ID && rt.ExpiresAt.Equal(now.Add(DefaultConfig.RefreshTTL))
+	})).Return(nil)
+
+	s := newTestService(t, repo, new(MockUserRepository), now)
+	tokens, err := s.Start(context.Background(), u)
+	require.NoError(t, err)
+
+	// В базу попадает только хеш токена обновления
+	created := repo.Calls[0].Arguments.Get(1).(*session.RefreshToken)
+	assert.Equal(t, auth.HashRefreshToken(tokens.RefreshToken), created.TokenHash)
+	assert.Equal(t, created.SessionID, tokens.SessionID)
+
+	claims, err := s.ValidateToken(tokens.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, u.ID, claims.UserID)
+	assert.Equal(t, tokens.SessionID, claims.SessionID)
+}
+
+func TestService_Refresh(t *testing.T) {
+	now := time.Now()
+	u := &user.User{ID: uuid.New(), Role: user.RoleAdmin}
+	usedAt := now.Add(-time.Minute)
+
+	newToken := func() *session.RefreshToken {
+		return &session.RefreshToken{
+			ID:        uuid.New(),
+			SessionID: uuid.New(),
+			UserID:    u.ID,
+			TokenHash: auth.HashRefreshToken("raw"),
+			CreatedAt: now.Add(-time.Hour),
+			ExpiresAt: now.Add(time.Hour),
+		}
+	}
+
+	t.Run("успешный обмен", func(t *testing.T) {
+		current := newToken()
+		repo := new(MockRepository)
+		users := new(MockUserRepository)
+		repo.On("GetRefreshTokenByHash", mock.Anything, current.TokenHash).Return(current, nil)
+		users.On("GetByID", mock.Anything, u.ID).Return(u, nil)
+		repo.On("UseRefreshToken", mock.Anything, current.ID, mock.Anything, now).Return(true, nil)
+		repo.On("CreateRefreshToken", mock.Anything, mock.MatchedBy(func(rt *session.RefreshToken) bool {
+			return rt.SessionID == current.SessionID
+		})).Return(nil)
+
+		s := newTestService(t, repo, users, now)
+		tokens, err := s.Refresh(context.Background(), "raw")
+		require.NoError(t, err)
+		assert.NotEqual(t, "raw", tokens.RefreshToken)
+		assert.Equal(t, current.SessionID, tokens.SessionID)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("неизвестный токен", func(t *testing.T) {
+		repo := new(MockRepository)
+		repo.On("GetRefreshTokenByHash", mock.Anything, mock.Anything).Return(nil, session.ErrNotFound)
+
+		_, err := newTestService(t, repo, new(MockUserRepository), now).Refresh(context.Background(), "raw")
+		assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+	})
+
+	t.Run("истекший токен", func(t *testing.T) {
+		current := newToken()
+		current.ExpiresAt = now
+		repo := new(MockRepository)
+		repo.On("GetRefreshTokenByHash", mock.Anything, mock.Anything).Return(current, nil)
+
+		_, err := newTestService(t, repo, new(MockUserRepository), now).Refresh(context.Background(), "raw")
+		assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+	})
+
+	t.Run("удаленный пользователь", func(t *testing.T) {
+		current := newToken()
+		repo := new(MockRepository)
+		users := new(MockUserRepository)
+		repo.On("GetRefreshTokenByHash", mock.Anything, mock.Anything).Return(current, nil)
+		users.On("GetByID", mock.Anything, u.ID).Return(nil, user.ErrNotFound)
+
+		_, err := newTestService(t, repo, users, now).Refresh(context.Background(), "raw")
+		assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+	})
+
+	t.Run("повторное использование отзывает сессию", func(t *testing.T) {
+		current := newToken()
+		current.UsedAt = &usedAt
+		repo := new(MockRepository)
+		repo.On("GetRefreshTokenByHash", mock.Anything, mock.Anything).Return(current, nil)
+		repo.On("RevokeSession", mock.Anything, current.SessionID, now).Return(nil)
+		repo.On("AddRevocation", mock.Anything, mock.MatchedBy(func(r *session.Revocation) bool {
+			return r.Kind == session.RevocationSession && r.SubjectID == current.SessionID
+		})).Return(nil)
+
+		s := newTestService(t, repo, new(MockUserRepository), now)
+		_, err := s.Refresh(context.Background(), "raw")
+		assert.ErrorIs(t, err, ErrRefreshTokenReused)
+		repo.AssertExpectations(t)
+
+		// Токены доступа отозванной сессии больше не принимаются
+		access, err := s.tokens.GenerateSessionToken(u.ID, u.Role, current.SessionID)
+		require.NoError(t, err)
+		_, err = s.ValidateToken(access)
+		assert.ErrorIs(t, err, ErrTokenRevoked)
+	})
+
+	t.Run("параллельный обмен считается повторным использованием", func(t *testing.T) {
+		current := newToken()
+		repo := new(MockRepository)
+		users := new(MockUserRepository)
+		repo.On("GetRefreshTokenByHash", mock.Anything, mock.Anything).Return(current, nil)
+		users.On("GetByID", mock.Anything, u.ID).Return(u, nil)
+		repo.On("UseRefreshToken", mock.Anything, current.ID, mock.Anything, now).Return(false, nil)
+		repo.On("RevokeSession", mock.Anything, current.SessionID, now).Return(nil)
+		repo.On("AddRevocation", mock.Anything, mock.Anything).Return(nil)
+
+		_, err := newTestService(t, repo, users, now).Refresh(context.Background(), "raw")
+		assert.ErrorIs(t, err, ErrRefreshTokenReused)
+		repo.AssertNotCalled(t, "CreateRefreshToken", mock.Anything, mock.Anything)
+	})
+}
+
+func TestService_Logout(t *testing.T) {
+	now := time.Now()
+	sessionID := uuid.New()
+
+	repo := new(MockRepository)
+	repo.On("RevokeSession", mock.Anything, sessionID, now).Return(nil)
+	repo.On("AddRevocation", mock.Anything, mock.Anything).Return(nil)
+
+	s := newTestService(t, repo, new(MockUserRepository), now)
+	require.NoError(t, s.Logout(context.Background(), sessionID))
+
+	// Токен без сессии выходом не отзывается
+	require.NoError(t, s.Logout(context.Background(), uuid.Nil))
+	repo.AssertNumberOfCalls(t, "RevokeSession", 1)
+}
+
+func TestService_LogoutAll(t *testing.T) {
+	now := time.Now()
+	userID := uuid.New()
+	sessionID := uuid.New()
+
+	repo := new(MockRepository)
+	repo.On("RevokeUserSessions", mock.Anything, userID, now).Return([]uuid.UUID{sessionID}, nil)
+	repo.On("AddRevocation", mock.Anything, mock.Anything).Return(nil)
+
+	s := newTestService(t, repo, new(MockUserRepository), now)
+	sessionToken, err := s.tokens.GenerateSessionToken(userID, user.RoleEmployee, sessionID)
+	require.NoError(t, err)
+
+	require.NoError(t, s.LogoutAll(context.Background(), userID))
+	repo.AssertNumberOfCalls(t, "AddRevocation", 2)
+
+	_, err = s.ValidateToken(sessionToken)
+	assert.ErrorIs(t, err, ErrTokenRevoked)
+
+	// Токены других пользователей продолжают действовать
+	other, err := s.tokens.GenerateSessionToken(uuid.New(), user.RoleEmployee, uuid.New())
+	require.NoError(t, err)
+	_, err = s.ValidateToken(other)
+	assert.NoError(t, err)
+}
+
+func TestService_Sync(t *testing.T) {
+	now := time.Now()
+	sessionID := uuid.New()
+	userID := uuid.New()
+
+	repo := new(MockRepository)
+	repo.On("DeleteExpiredRevocations", mock.Anything, now).Return(int64(1), nil)
+	repo.On("DeleteExpiredRefreshTokens", mock.Anything, now).Return(int64(0), nil)
+	repo.On("ListRevocations", mock.Anything, now).Return([]*session.Revocation{{
+		Kind:      session.RevocationSession,
+		SubjectID: sessionID,
+		RevokedAt: now,
+		ExpiresAt: now.Add(time.Hour),
+	}}, nil)
+
+	s := newTestService(t, repo, new(MockUserRepository), now)
+	require.NoError(t, s.Sync(context.Background()))
+
+	// Сессия, отозванная другим экземпляром сервиса, отклоняется после синхронизации
+	token, err := s.tokens.GenerateSessionToken(userID, user.RoleEmployee, sessionID)
+	require.NoError(t, err)
+	_, err = s.ValidateToken(token)
+	assert.ErrorIs(t, err, ErrTokenRevoked)
+}
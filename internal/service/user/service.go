@@ -3,9 +3,12 @@ package user
 import (
 	"context"
 	"errors"
+	"log"
 	"strings"
 	"unicode"
 
+	"github.com/avito/pvz/internal/domain/audit"
+	"github.com/avito/pvz/internal/domain/rbac"
 	"github.com/avito/pvz/internal/domain/transaction"
 	"github.com/avito/pvz/internal/domain/user"
 	"github.com/avito/pvz/pkg/auth"
@@ -32,17 +35,56 @@ type ServiceInterface interface {
 	LoginUser(ctx context.Context, email, password string) (string, error)
 }
 
+// LoginAttempts учитывает попытки входа для защиты от подбора паролей
+type LoginAttempts interface {
+	// Check возвращает ошибку, если вход по email временно заблокирован
+	Check(ctx context.Context, email string) error
+	// Failed учитывает неудачную попытку; userID — uuid.Nil, если email не найден
+	Failed(ctx context.Context, email string, userID uuid.UUID) error
+	// Succeeded сбрасывает счетчик неудач после успешного входа
+	Succeeded(ctx context.Context, email string) error
+}
+
+// NoLoginAttempts не ограничивает попытки входа
+var NoLoginAttempts LoginAttempts = noLoginAttempts{}
+
+type noLoginAttempts struct{}
+
+func (noLoginAttempts) Check(context.Context, string) error             { return nil }
+func (noLoginAttempts) Failed(context.Context, string, uuid.UUID) error { return nil }
+func (noLoginAttempts) Succeeded(context.Context, string) error         { return nil }
+
+// dummyPasswordHash хеш, с которым сравнивается пароль для несуществующего email,
+// чтобы время ответа не выдавало, зарегистрирован ли адрес
+const dummyPasswordHash = "$2a$10$vJVvoHliGyEzEPZuAWZzc.TSBMSl7Ef0sq6tehT8zgxtGFzYRDWcm"
+
+// TokenIssuer выпускает токены доступа
+type TokenIssuer interface {
+	GenerateToken(userID uuid.UUID, role user.Role) (string, error)
+}
+
 // Service реализует ServiceInterface
 type Service struct {
 	userRepo  user.Repository
 	txManager transaction.Manager
+	tokens    TokenIssuer
+	authz     *rbac.Authorizer
+	attempts  LoginAttempts
+	audit     audit.Writer
 }
 
-// New создает новый экземпляр Service
-func New(userRepo user.Repository, txManager transaction.Manager) *Service {
+// New создает новый экземпляр Service. Допустимые роли пользователей и их
+// псевдонимы задает политика authz, а попытки входа учитывает attempts.
+// Создание, изменение и удаление пользователей записываются в журнал audit
+// в той же транзакции.
+func New(userRepo user.Repository, txManager transaction.Manager, tokens TokenIssuer, authz *rbac.Authorizer, attempts LoginAttempts, audit audit.Writer) *Service {
 	return &Service{
 		userRepo:  userRepo,
 		txManager: txManager,
+		tokens:    tokens,
+		authz:     authz,
+		attempts:  attempts,
+		audit:     audit,
 	}
 }
 
@@ -55,12 +97,13 @@ func (s *Service) Register(ctx context.Context, email, password string, role use
 	if err := validatePassword(password); err != nil {
 		return nil, err
 	}
-	if err := validateRole(role); err != nil {
+	role, err := s.validateRole(role)
+	if err != nil {
 		return nil, err
 	}
 
 	var result *user.User
-	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
 		// Проверяем существование пользователя
 		if _, err := s.userRepo.GetByEmail(ctx, email); err != user.ErrNotFound {
 			if err == nil {
@@ -81,6 +124,15 @@ func (s *Service) Register(ctx context.Context, email, password string, role use
 			return err
 		}
 
+		// При самостоятельной регистрации изменение выполняет сам новый пользователь
+		actorID, ok := auth.GetUserID(ctx)
+		if !ok {
+			actorID = newUser.ID
+		}
+		if err := s.record(ctx, actorID, newUser.ID, audit.ActionCreate, nil, userState(newUser)); err != nil {
+			return err
+		}
+
 		result = newUser
 		return nil
 	})
@@ -94,20 +146,42 @@ func (s *Service) Register(ctx context.Context, email, password string, role use
 
 // Login выполняет авторизацию пользователя
 func (s *Service) Login(ctx context.Context, email, password string) (*user.User, error) {
+	// Заблокированный вход отклоняется до проверки пароля
+	if err := s.attempts.Check(ctx, email); err != nil {
+		return nil, err
+	}
+
 	// Получаем пользователя
 	u, err := s.userRepo.GetByEmail(ctx, email)
 	if err != nil {
+		if !errors.Is(err, user.ErrNotFound) && !errors.Is(err, user.ErrUserNotFound) {
+			return nil, err
+		}
+		// Пароль все равно проверяется, чтобы ответ занял столько же времени
+		_ = bcrypt.CompareHashAndPassword([]byte(dummyPasswordHash), []byte(password))
+		s.loginFailed(ctx, email, uuid.Nil)
 		return nil, ErrUserNotFound
 	}
 
 	// Проверяем пароль
 	if err := bcrypt.CompareHashAndPassword([]byte(u.Password), []byte(password)); err != nil {
+		s.loginFailed(ctx, email, u.ID)
 		return nil, ErrInvalidPassword
 	}
 
+	if err := s.attempts.Succeeded(ctx, email); err != nil {
+		log.Printf("Failed to reset login attempts: %v", err)
+	}
 	return u, nil
 }
 
+// loginFailed учитывает неудачный вход. Ошибка учета не меняет ответ клиенту.
+func (s *Service) loginFailed(ctx context.Context, email string, userID uuid.UUID) {
+	if err := s.attempts.Failed(ctx, email, userID); err != nil {
+		log.Printf("Failed to record login attempt: %v", err)
+	}
+}
+
 // GetByID получает пользователя по ID
 func (s *Service) GetByID(ctx context.Context, id uuid.UUID) (*user.User, error) {
 	u, err := s.userRepo.GetByID(ctx, id)
@@ -121,7 +195,8 @@ func (s *Service) GetByID(ctx context.Context, id uuid.UUID) (*user.User, error)
 func (s *Service) Update(ctx context.Context, u *user.User) error {
 	return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
 		// Проверяем существование пользователя
-		if _, err := s.userRepo.GetByID(ctx, u.ID); err != nil {
+		current, err := s.userRepo.GetByID(ctx, u.ID)
+		if err != nil {
 			return ErrUserNotFound
 		}
 
@@ -129,11 +204,18 @@ func (s *Service) Update(ctx context.Context, u *user.User) error {
 		if err := validateEmail(u.Email); err != nil {
 			return err
 		}
-		if err := validateRole(u.Role); err != nil {
+		role, err := s.validateRole(u.Role)
+		if err != nil {
+			return err
+		}
+		u.Role = role
+
+		if err := s.userRepo.Update(ctx, u); err != nil {
 			return err
 		}
 
-		return s.userRepo.Update(ctx, u)
+		actorID, _ := auth.GetUserID(ctx)
+		return s.record(ctx, actorID, u.ID, audit.ActionUpdate, userState(current), userState(u))
 	})
 }
 
@@ -141,14 +223,38 @@ func (s *Service) Update(ctx context.Context, u *user.User) error {
 func (s *Service) Delete(ctx context.Context, id uuid.UUID) error {
 	return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
 		// Проверяем существование пользователя
-		if _, err := s.userRepo.GetByID(ctx, id); err != nil {
+		current, err := s.userRepo.GetByID(ctx, id)
+		if err != nil {
 			return ErrUserNotFound
 		}
 
-		return s.userRepo.Delete(ctx, id)
+		if err := s.userRepo.Delete(ctx, id); err != nil {
+			return err
+		}
+
+		actorID, _ := auth.GetUserID(ctx)
+		return s.record(ctx, actorID, id, audit.ActionDelete, userState(current), nil)
 	})
 }
 
+// record записывает изменение пользователя в журнал аудита транзакции из ctx
+func (s *Service) record(ctx context.Context, actorID, userID uuid.UUID, action audit.Action, before, after audit.State) error {
+	entry, err := audit.NewEntry(ctx, actorID, audit.EntityUser, userID, action, before, after)
+	if err != nil {
+		return err
+	}
+	return s.audit.Record(ctx, entry)
+}
+
+// userState снимок пользователя для журнала аудита без хеша пароля
+func userState(u *user.User) audit.State {
+	return audit.State{
+		"email":           u.Email,
+		"role":            u.Role,
+		"emailVerifiedAt": u.EmailVerifiedAt,
+	}
+}
+
 // List возвращает список пользователей
 func (s *Service) List(ctx context.Context, offset, limit int) ([]*user.User, error) {
 	return s.userRepo.List(ctx, offset, limit)
@@ -183,6 +289,19 @@ func validateEmail(email string) error {
 	return nil
 }
 
+// HashPassword проверяет требования к паролю и возвращает его bcrypt-хеш
+func HashPassword(password string) (string, error) {
+	if err := validatePassword(password); err != nil {
+		return "", err
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
 // validatePassword проверяет корректность пароля
 func validatePassword(password string) error {
 	if password == "" {
@@ -219,31 +338,26 @@ func validatePassword(password string) error {
 	return nil
 }
 
-// validateRole проверяет корректность роли
-func validateRole(role user.Role) error {
-	switch role {
-	case user.RoleAdmin, user.RoleEmployee:
-		return nil
-	default:
-		return ErrInvalidRole
+// validateRole проверяет, что роль описана в политике, и возвращает ее
+// основное имя: пользователь, зарегистрированный под псевдонимом роли,
+// хранится с основной ролью
+func (s *Service) validateRole(role user.Role) (user.Role, error) {
+	if !s.authz.Known(role) {
+		return "", ErrInvalidRole
 	}
+	return s.authz.Role(role), nil
 }
 
 // LoginUser выполняет вход пользователя
 func (s *Service) LoginUser(ctx context.Context, email, password string) (string, error) {
-	// Получаем пользователя по email
-	user, err := s.userRepo.GetByEmail(ctx, email)
+	// Проверяем email и пароль с учетом попыток входа
+	user, err := s.Login(ctx, email, password)
 	if err != nil {
 		return "", err
 	}
 
-	// Проверяем пароль
-	if !auth.CheckPasswordHash(password, user.Password) {
-		return "", errors.New("invalid password")
-	}
-
 	// Генерируем JWT токен
-	token, err := auth.GenerateToken(user.ID, user.Role)
+	token, err := s.tokens.GenerateToken(user.ID, user.Role)
 	if err != nil {
 		return "", err
 	}
@@ -257,7 +371,7 @@ func (s *Service) DummyLogin(ctx context.Context, role user.Role) (string, error
 	id := uuid.New()
 
 	// Генерируем JWT токен
-	token, err := auth.GenerateToken(id, role)
+	token, err := s.tokens.GenerateToken(id, role)
 	if err != nil {
 		return "", err
 	}
@@ -5,14 +5,29 @@ import (
 	"errors"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/avito/pvz/internal/domain/audit"
+	"github.com/avito/pvz/internal/domain/lockout"
+	"github.com/avito/pvz/internal/domain/rbac"
 	"github.com/avito/pvz/internal/domain/user"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"golang.org/x/crypto/bcrypt"
 )
 
+// testAuthorizer проверяет роли по политике по умолчанию
+var testAuthorizer = rbac.MustNewAuthorizer(rbac.DefaultPolicy)
+
+// stubTokenIssuer выпускает токен вида "<роль>:<ID пользователя>"
+type stubTokenIssuer struct{}
+
+func (stubTokenIssuer) GenerateToken(userID uuid.UUID, role user.Role) (string, error) {
+	return string(role) + ":" + userID.String(), nil
+}
+
 // MockUserRepository реализует мок для user.Repository
 type MockUserRepository struct {
 	mock.Mock
@@ -70,9 +85,27 @@ func TestService_Register(t *testing.T) {
 		email       string
 		password    string
 		role        user.Role
+		wantRole    user.Role
 		setupMocks  func(*MockUserRepository, *MockTransactionManager)
 		expectedErr error
 	}{
+		{
+			name:     "регистрация под псевдонимом роли",
+			email:    "moderator@example.com",
+			password: "StrongPass123!",
+			role:     "moderator",
+			wantRole: user.RoleAdmin,
+			setupMocks: func(userRepo *MockUserRepository, txManager *MockTransactionManager) {
+				userRepo.On("GetByEmail", mock.Anything, "moderator@example.com").Return(nil, user.ErrNotFound)
+				userRepo.On("Create", mock.Anything, mock.MatchedBy(func(u *user.User) bool {
+					return u.Role == user.RoleAdmin
+				})).Return(nil)
+				txManager.On("WithinTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Run(func(args mock.Arguments) {
+					fn := args.Get(1).(func(context.Context) error)
+					fn(context.Background())
+				}).Return(nil)
+			},
+		},
 		{
 			name:     "успешная регистрация",
 			email:    "test@example.com",
@@ -150,7 +183,7 @@ func TestService_Register(t *testing.T) {
 			txManager := new(MockTransactionManager)
 			tt.setupMocks(userRepo, txManager)
 
-			service := New(userRepo, txManager)
+			service := New(userRepo, txManager, stubTokenIssuer{}, testAuthorizer, NoLoginAttempts, audit.Discard)
 			result, err := service.Register(context.Background(), tt.email, tt.password, tt.role)
 
 			if tt.expectedErr != nil {
@@ -160,7 +193,11 @@ func TestService_Register(t *testing.T) {
 				assert.NoError(t, err)
 				assert.NotNil(t, result)
 				assert.Equal(t, tt.email, result.Email)
-				assert.Equal(t, tt.role, result.Role)
+				wantRole := tt.role
+				if tt.wantRole != "" {
+					wantRole = tt.wantRole
+				}
+				assert.Equal(t, wantRole, result.Role)
 				assert.NotEmpty(t, result.Password)
 				assert.NotEqual(t, uuid.Nil, result.ID)
 			}
@@ -224,7 +261,7 @@ func TestService_Login(t *testing.T) {
 			txManager := new(MockTransactionManager)
 			tt.setupMocks(userRepo, txManager)
 
-			service := New(userRepo, txManager)
+			service := New(userRepo, txManager, stubTokenIssuer{}, testAuthorizer, NoLoginAttempts, audit.Discard)
 			result, err := service.Login(context.Background(), tt.email, tt.password)
 
 			if tt.expectedErr != nil {
@@ -272,7 +309,7 @@ func TestService_GetByID(t *testing.T) {
 			tx := new(MockTransactionManager)
 			tt.setupMocks(repo)
 
-			service := New(repo, tx)
+			service := New(repo, tx, stubTokenIssuer{}, testAuthorizer, NoLoginAttempts, audit.Discard)
 			_, err := service.GetByID(context.Background(), tt.id)
 
 			if tt.expectedError != nil {
@@ -399,7 +436,7 @@ func TestService_Update(t *testing.T) {
 			tx := new(MockTransactionManager)
 			tt.setupMocks(repo, tx)
 
-			service := New(repo, tx)
+			service := New(repo, tx, stubTokenIssuer{}, testAuthorizer, NoLoginAttempts, audit.Discard)
 			err := service.Update(context.Background(), tt.user)
 
 			if tt.expectedError != nil {
@@ -454,7 +491,7 @@ func TestService_Delete(t *testing.T) {
 			tx := new(MockTransactionManager)
 			tt.setupMocks(repo, tx)
 
-			service := New(repo, tx)
+			service := New(repo, tx, stubTokenIssuer{}, testAuthorizer, NoLoginAttempts, audit.Discard)
 			err := service.Delete(context.Background(), tt.id)
 
 			if tt.expectedError != nil {
@@ -503,7 +540,7 @@ func TestService_List(t *testing.T) {
 			tx := new(MockTransactionManager)
 			tt.setupMocks(repo)
 
-			service := New(repo, tx)
+			service := New(repo, tx, stubTokenIssuer{}, testAuthorizer, NoLoginAttempts, audit.Discard)
 			_, err := service.List(context.Background(), tt.offset, tt.limit)
 
 			if tt.expectedError != nil {
@@ -517,6 +554,78 @@ func TestService_List(t *testing.T) {
 	}
 }
 
+// MockLoginAttempts мок для LoginAttempts
+type MockLoginAttempts struct {
+	mock.Mock
+}
+
+func (m *MockLoginAttempts) Check(ctx context.Context, email string) error {
+	args := m.Called(ctx, email)
+	return args.Error(0)
+}
+
+func (m *MockLoginAttempts) Failed(ctx context.Context, email string, userID uuid.UUID) error {
+	args := m.Called(ctx, email, userID)
+	return args.Error(0)
+}
+
+func (m *MockLoginAttempts) Succeeded(ctx context.Context, email string) error {
+	args := m.Called(ctx, email)
+	return args.Error(0)
+}
+
+func TestService_LoginAttempts(t *testing.T) {
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("StrongPass123!"), bcrypt.MinCost)
+	u := &user.User{ID: uuid.New(), Email: "test@example.com", Password: string(hashedPassword)}
+	ctx := context.Background()
+
+	t.Run("успешный вход сбрасывает счетчик", func(t *testing.T) {
+		repo, attempts := new(MockUserRepository), new(MockLoginAttempts)
+		attempts.On("Check", ctx, "test@example.com").Return(nil)
+		repo.On("GetByEmail", ctx, "test@example.com").Return(u, nil)
+		attempts.On("Succeeded", ctx, "test@example.com").Return(nil)
+
+		service := New(repo, new(MockTransactionManager), stubTokenIssuer{}, testAuthorizer, attempts, audit.Discard)
+		_, err := service.Login(ctx, "test@example.com", "StrongPass123!")
+		require.NoError(t, err)
+		attempts.AssertExpectations(t)
+	})
+
+	t.Run("неверный пароль учитывается для пользователя", func(t *testing.T) {
+		repo, attempts := new(MockUserRepository), new(MockLoginAttempts)
+		attempts.On("Check", ctx, "test@example.com").Return(nil)
+		repo.On("GetByEmail", ctx, "test@example.com").Return(u, nil)
+		attempts.On("Failed", ctx, "test@example.com", u.ID).Return(nil)
+
+		service := New(repo, new(MockTransactionManager), stubTokenIssuer{}, testAuthorizer, attempts, audit.Discard)
+		_, err := service.Login(ctx, "test@example.com", "WrongPass123!")
+		assert.ErrorIs(t, err, ErrInvalidPassword)
+		attempts.AssertExpectations(t)
+	})
+
+	t.Run("несуществующий email учитывается так же", func(t *testing.T) {
+		repo, attempts := new(MockUserRepository), new(MockLoginAttempts)
+		attempts.On("Check", ctx, "nobody@example.com").Return(nil)
+		repo.On("GetByEmail", ctx, "nobody@example.com").Return(nil, user.ErrNotFound)
+		attempts.On("Failed", ctx, "nobody@example.com", uuid.Nil).Return(nil)
+
+		service := New(repo, new(MockTransactionManager), stubTokenIssuer{}, testAuthorizer, attempts, audit.Discard)
+		_, err := service.Login(ctx, "nobody@example.com", "StrongPass123!")
+		assert.ErrorIs(t, err, ErrUserNotFound)
+		attempts.AssertExpectations(t)
+	})
+
+	t.Run("заблокированный вход не проверяет пароль", func(t *testing.T) {
+		repo, attempts := new(MockUserRepository), new(MockLoginAttempts)
+		attempts.On("Check", ctx, "test@example.com").Return(&lockout.LockedError{Until: time.Now()})
+
+		service := New(repo, new(MockTransactionManager), stubTokenIssuer{}, testAuthorizer, attempts, audit.Discard)
+		_, err := service.Login(ctx, "test@example.com", "StrongPass123!")
+		assert.ErrorIs(t, err, lockout.ErrLocked)
+		repo.AssertNotCalled(t, "GetByEmail", mock.Anything, mock.Anything)
+	})
+}
+
 func TestService_LoginUser(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -547,7 +656,7 @@ func TestService_LoginUser(t *testing.T) {
 			setupMocks: func(repo *MockUserRepository) {
 				repo.On("GetByEmail", mock.Anything, "nonexistent@example.com").Return(nil, user.ErrUserNotFound)
 			},
-			expectedError: user.ErrUserNotFound,
+			expectedError: ErrUserNotFound,
 		},
 		{
 			name:     "неверный пароль",
@@ -562,7 +671,7 @@ func TestService_LoginUser(t *testing.T) {
 					Role:     user.RoleAdmin,
 				}, nil)
 			},
-			expectedError: errors.New("invalid password"),
+			expectedError: ErrInvalidPassword,
 		},
 	}
 
@@ -572,11 +681,11 @@ func TestService_LoginUser(t *testing.T) {
 			tx := new(MockTransactionManager)
 			tt.setupMocks(repo)
 
-			service := New(repo, tx)
+			service := New(repo, tx, stubTokenIssuer{}, testAuthorizer, NoLoginAttempts, audit.Discard)
 			_, err := service.LoginUser(context.Background(), tt.email, tt.password)
 
 			if tt.expectedError != nil {
-				assert.Equal(t, tt.expectedError, err)
+				assert.ErrorIs(t, err, tt.expectedError)
 			} else {
 				assert.NoError(t, err)
 			}
@@ -586,6 +695,42 @@ func TestService_LoginUser(t *testing.T) {
 	}
 }
 
+// recordingAudit запоминает записи журнала аудита
+type recordingAudit struct {
+	entries []*audit.Entry
+}
+
+func (r *recordingAudit) Record(_ context.Context, entries ...*audit.Entry) error {
+	r.entries = append(r.entries, entries...)
+	return nil
+}
+
+func TestService_RegisterAudit(t *testing.T) {
+	repo := new(MockUserRepository)
+	repo.On("GetByEmail", mock.Anything, "new@example.com").Return(nil, user.ErrNotFound)
+	repo.On("Create", mock.Anything, mock.AnythingOfType("*user.User")).Return(nil)
+	tx := new(MockTransactionManager)
+	tx.On("WithinTransaction", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		fn := args.Get(1).(func(context.Context) error)
+		require.NoError(t, fn(args.Get(0).(context.Context)))
+	})
+
+	entries := new(recordingAudit)
+	service := New(repo, tx, stubTokenIssuer{}, testAuthorizer, NoLoginAttempts, entries)
+
+	created, err := service.Register(context.Background(), "new@example.com", "StrongPass123!", user.RoleEmployee)
+	require.NoError(t, err)
+
+	require.Len(t, entries.entries, 1)
+	entry := entries.entries[0]
+	// Без пользователя в контексте регистрацию выполняет сам новый пользователь
+	assert.Equal(t, created.ID, entry.ActorID)
+	assert.Equal(t, audit.EntityUser, entry.EntityType)
+	assert.Equal(t, audit.ActionCreate, entry.Action)
+	assert.JSONEq(t, `{"email":"new@example.com","role":"employee","emailVerifiedAt":null}`, string(entry.After))
+	assert.NotContains(t, string(entry.After), created.Password)
+}
+
 func TestService_DummyLogin(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -609,7 +754,7 @@ func TestService_DummyLogin(t *testing.T) {
 			repo := new(MockUserRepository)
 			tx := new(MockTransactionManager)
 
-			service := New(repo, tx)
+			service := New(repo, tx, stubTokenIssuer{}, testAuthorizer, NoLoginAttempts, audit.Discard)
 			token, err := service.DummyLogin(context.Background(), tt.role)
 
 			if tt.expectedError != nil {
@@ -673,6 +818,15 @@ func TestValidateEmail(t *testing.T) {
 	}
 }
 
+func TestHashPassword(t *testing.T) {
+	hash, err := HashPassword("Passw0rd!")
+	require.NoError(t, err)
+	assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(hash), []byte("Passw0rd!")))
+
+	_, err = HashPassword("password")
+	assert.ErrorIs(t, err, ErrInvalidPassword)
+}
+
 func TestValidatePassword(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -0,0 +1,208 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/webhook"
+	"github.com/google/uuid"
+)
+
+// Заголовки запроса к получателю
+const (
+	HeaderID        = "X-Webhook-Id"
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// maxDrainBodySize ограничивает часть тела ответа, которая вычитывается
+// для повторного использования соединения
+const maxDrainBodySize = 4 << 10
+
+// errPrivateAddress возвращается при попытке соединиться с непубличным адресом
+var errPrivateAddress = errors.New("webhook receiver address is not public")
+
+// newClient создает клиент доставки. Клиент не следует перенаправлениям
+// и проверяет адрес при каждом соединении, поэтому получатель не может
+// направить запрос во внутреннюю сеть ни ответом 3xx, ни сменой DNS-записи.
+// Прокси отключен, так как иначе проверялся бы адрес прокси, а не получателя.
+func newClient(cfg Config) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: cfg.Timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !publicIP(ip) {
+				return errPrivateAddress
+			}
+			return nil
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   cfg.Timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// publicIP сообщает, что адрес не относится к локальной, частной,
+// link-local или групповой сети
+func publicIP(ip net.IP) bool {
+	return !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsUnspecified() &&
+		!ip.IsLinkLocalUnicast() && !ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() && !ip.IsMulticast()
+}
+
+// Sign возвращает подпись тела запроса в формате "sha256=<hex>".
+// Подписывается строка "<timestamp>.<body>", чтобы получатель мог
+// отклонять повторно отправленные старые запросы.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Run выбирает и отправляет доставки с периодом PollInterval до отмены ctx
+func (s *Service) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.DispatchDue(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("failed to dispatch webhook deliveries: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchDue отправляет доставки, время попытки которых наступило,
+// и возвращает их число. Доставки одной выборки отправляются параллельно.
+func (s *Service) DispatchDue(ctx context.Context) (int, error) {
+	deliveries, err := s.repo.ClaimDueDeliveries(ctx, s.now(), s.cfg.Lease, s.cfg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	subs := make(map[uuid.UUID]*webhook.Subscription)
+	var wg sync.WaitGroup
+	for _, d := range deliveries {
+		sub, ok := subs[d.SubscriptionID]
+		if !ok {
+			sub, err = s.repo.GetSubscription(ctx, d.SubscriptionID)
+			if err == webhook.ErrNotFound {
+				// Подписку удалили после выборки, доставки удалены вместе с ней
+				continue
+			}
+			if err != nil {
+				return 0, err
+			}
+			subs[d.SubscriptionID] = sub
+		}
+
+		wg.Add(1)
+		go func(sub *webhook.Subscription, d *webhook.Delivery) {
+			defer wg.Done()
+			s.attempt(ctx, sub, d)
+			if err := s.repo.UpdateDelivery(ctx, d); err != nil {
+				log.Printf("failed to save webhook delivery %s: %v", d.ID, err)
+			}
+		}(sub, d)
+	}
+	wg.Wait()
+
+	return len(deliveries), nil
+}
+
+// attempt отправляет доставку и записывает в нее результат попытки
+func (s *Service) attempt(ctx context.Context, sub *webhook.Subscription, d *webhook.Delivery) {
+	now := s.now()
+	d.Attempts++
+
+	statusCode, err := s.send(ctx, sub, d, now)
+	d.LastStatusCode = statusCode
+	if err == nil {
+		d.Status = webhook.DeliverySucceeded
+		d.LastError = ""
+		d.DeliveredAt = &now
+		return
+	}
+
+	d.LastError = err.Error()
+	if d.Attempts >= s.cfg.MaxAttempts {
+		d.Status = webhook.DeliveryDead
+		return
+	}
+	d.NextAttemptAt = now.Add(s.backoff(d.Attempts))
+}
+
+// send выполняет запрос к получателю. Ответ 2xx считается успешной доставкой.
+func (s *Service) send(ctx context.Context, sub *webhook.Subscription, d *webhook.Delivery, now time.Time) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderID, d.ID.String())
+	req.Header.Set(HeaderEvent, string(d.EventType))
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, Sign(sub.Secret, timestamp, d.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// Тело ответа не сохраняется: оно видно в истории доставок и могло бы раскрыть
+	// содержимое внутренних ресурсов
+	io.Copy(io.Discard, io.LimitReader(resp.Body, maxDrainBodySize))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// backoff возвращает задержку перед следующей попыткой после attempts неудачных
+func (s *Service) backoff(attempts int) time.Duration {
+	delay := s.cfg.BaseBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= s.cfg.MaxBackoff {
+			return s.cfg.MaxBackoff
+		}
+	}
+	return delay
+}
@@ -0,0 +1,266 @@
+// Package webhook доставляет события приемок и товаров внешним системам
+// по подпискам. Каждая доставка подписывается HMAC-SHA256, неудачные
+// попытки повторяются с экспоненциальной задержкой, а после исчерпания
+// попыток доставка переносится в dead-letter.
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/event"
+	"github.com/avito/pvz/internal/domain/outbox"
+	"github.com/avito/pvz/internal/domain/webhook"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidURL          = errors.New("invalid webhook url")
+	ErrInvalidEventTypes   = errors.New("invalid webhook event types")
+	ErrInvalidStatus       = errors.New("invalid webhook delivery status")
+	ErrDeliveryNotRetrying = errors.New("webhook delivery is not dead")
+)
+
+// secretSize длина генерируемого секрета подписи в байтах
+const secretSize = 32
+
+// knownEventTypes типы событий, на которые можно подписаться
+var knownEventTypes = map[event.Type]bool{
+	event.TypeReceptionOpened: true,
+	event.TypeReceptionClosed: true,
+	event.TypeProductAdded:    true,
+	event.TypeProductRemoved:  true,
+}
+
+// Config параметры доставки событий
+type Config struct {
+	// MaxAttempts число попыток, после которого доставка переносится в dead-letter
+	MaxAttempts int
+	// BaseBackoff задержка перед второй попыткой, далее она удваивается
+	BaseBackoff time.Duration
+	// MaxBackoff верхняя граница задержки между попытками
+	MaxBackoff time.Duration
+	// Timeout время ожидания ответа получателя
+	Timeout time.Duration
+	// PollInterval период выборки доставок, время которых наступило
+	PollInterval time.Duration
+	// BatchSize число доставок, выбираемых за один проход
+	BatchSize int
+	// Lease время, на которое выбранная доставка скрывается от других обработчиков
+	Lease time.Duration
+}
+
+// DefaultConfig параметры доставки по умолчанию
+var DefaultConfig = Config{
+	MaxAttempts:  8,
+	BaseBackoff:  30 * time.Second,
+	MaxBackoff:   time.Hour,
+	Timeout:      10 * time.Second,
+	PollInterval: 5 * time.Second,
+	BatchSize:    50,
+	Lease:        time.Minute,
+}
+
+// Service управляет подписками и доставляет по ним события
+type Service struct {
+	repo     webhook.Repository
+	client   *http.Client
+	cfg      Config
+	now      func() time.Time
+	lookupIP func(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+// New создает новый экземпляр Service. Если client не задан, используется
+// клиент из newClient, который соединяется только с публичными адресами.
+func New(repo webhook.Repository, client *http.Client, cfg Config) *Service {
+	if client == nil {
+		client = newClient(cfg)
+	}
+
+	return &Service{
+		repo:     repo,
+		client:   client,
+		cfg:      cfg,
+		now:      time.Now,
+		lookupIP: net.DefaultResolver.LookupIPAddr,
+	}
+}
+
+// CreateSubscription создает подписку. Если секрет не задан, он генерируется
+// и возвращается в подписке.
+func (s *Service) CreateSubscription(ctx context.Context, rawURL string, eventTypes []event.Type, secret string, createdBy uuid.UUID) (*webhook.Subscription, error) {
+	if err := s.validateURL(ctx, rawURL); err != nil {
+		return nil, err
+	}
+	if err := validateEventTypes(eventTypes); err != nil {
+		return nil, err
+	}
+
+	if secret == "" {
+		generated, err := generateSecret()
+		if err != nil {
+			return nil, err
+		}
+		secret = generated
+	}
+
+	sub := &webhook.Subscription{
+		ID:         uuid.New(),
+		URL:        rawURL,
+		EventTypes: eventTypes,
+		Secret:     secret,
+		CreatedBy:  createdBy,
+		CreatedAt:  s.now(),
+	}
+
+	if err := s.repo.CreateSubscription(ctx, sub); err != nil {
+		return nil, err
+	}
+
+	return sub, nil
+}
+
+// ListSubscriptions возвращает все подписки
+func (s *Service) ListSubscriptions(ctx context.Context) ([]*webhook.Subscription, error) {
+	return s.repo.ListSubscriptions(ctx)
+}
+
+// DeleteSubscription удаляет подписку вместе с историей ее доставок
+func (s *Service) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	return s.repo.DeleteSubscription(ctx, id)
+}
+
+// ListDeliveries возвращает историю доставок подписки, новые первыми
+func (s *Service) ListDeliveries(ctx context.Context, filter webhook.DeliveryFilter) ([]*webhook.Delivery, error) {
+	if filter.Status != "" && !filter.Status.Valid() {
+		return nil, ErrInvalidStatus
+	}
+	if err := filter.Page.Validate(); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.repo.GetSubscription(ctx, filter.SubscriptionID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.ListDeliveries(ctx, filter)
+}
+
+// Redeliver возвращает доставку из dead-letter в очередь с новым счетчиком попыток
+func (s *Service) Redeliver(ctx context.Context, id uuid.UUID) (*webhook.Delivery, error) {
+	d, err := s.repo.GetDelivery(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if d.Status != webhook.DeliveryDead {
+		return nil, ErrDeliveryNotRetrying
+	}
+
+	d.Status = webhook.DeliveryPending
+	d.Attempts = 0
+	d.NextAttemptAt = s.now()
+	d.LastStatusCode = 0
+	d.LastError = ""
+
+	if err := s.repo.UpdateDelivery(ctx, d); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// Publish создает доставки сообщения outbox по всем подпискам на его тип.
+// Сервис подключается к обработчику outbox, поэтому доставки записываются в его
+// транзакции вместе с отметкой о публикации и не теряются при сбое. ID доставки
+// выводится из ID сообщения и подписки, и повторная публикация не создает дублей.
+func (s *Service) Publish(ctx context.Context, m *outbox.Message) error {
+	eventType := event.Type(m.EventType)
+	if !knownEventTypes[eventType] {
+		return nil
+	}
+
+	subs, err := s.repo.ListSubscriptions(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := s.now()
+	var deliveries []*webhook.Delivery
+	for _, sub := range subs {
+		if !sub.Matches(eventType) {
+			continue
+		}
+		deliveries = append(deliveries, &webhook.Delivery{
+			ID:             uuid.NewSHA1(m.ID, sub.ID[:]),
+			SubscriptionID: sub.ID,
+			EventType:      eventType,
+			Payload:        m.Payload,
+			Status:         webhook.DeliveryPending,
+			NextAttemptAt:  now,
+			CreatedAt:      now,
+		})
+	}
+
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	return s.repo.CreateDeliveries(ctx, deliveries)
+}
+
+// validateURL проверяет, что адрес получателя абсолютный, использует HTTP или HTTPS
+// и указывает только на публичные адреса. При отправке адрес проверяется повторно,
+// так как DNS-запись могут изменить после создания подписки.
+func (s *Service) validateURL(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return ErrInvalidURL
+	}
+
+	host := u.Hostname()
+	if ip := net.ParseIP(host); ip != nil {
+		if !publicIP(ip) {
+			return ErrInvalidURL
+		}
+		return nil
+	}
+
+	addrs, err := s.lookupIP(ctx, host)
+	if err != nil || len(addrs) == 0 {
+		return ErrInvalidURL
+	}
+	for _, addr := range addrs {
+		if !publicIP(addr.IP) {
+			return ErrInvalidURL
+		}
+	}
+	return nil
+}
+
+// validateEventTypes проверяет, что список типов непуст и содержит только известные типы
+func validateEventTypes(eventTypes []event.Type) error {
+	if len(eventTypes) == 0 {
+		return ErrInvalidEventTypes
+	}
+	for _, t := range eventTypes {
+		if !knownEventTypes[t] {
+			return ErrInvalidEventTypes
+		}
+	}
+	return nil
+}
+
+// generateSecret создает случайный секрет подписи
+func generateSecret() (string, error) {
+	b := make([]byte, secretSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,631 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/event"
+	"github.com/avito/pvz/internal/domain/listing"
+	"github.com/avito/pvz/internal/domain/outbox"
+	"github.com/avito/pvz/internal/domain/webhook"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockRepository реализует мок для webhook.Repository
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) CreateSubscription(ctx context.Context, s *webhook.Subscription) error {
+	args := m.Called(ctx, s)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetSubscription(ctx context.Context, id uuid.UUID) (*webhook.Subscription, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*webhook.Subscription), args.Error(1)
+}
+
+func (m *MockRepository) ListSubscriptions(ctx context.Context) ([]*webhook.Subscription, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*webhook.Subscription), args.Error(1)
+}
+
+func (m *MockRepository) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockRepository) CreateDeliveries(ctx context.Context, deliveries []*webhook.Delivery) error {
+	args := m.Called(ctx, deliveries)
+	return args.Error(0)
+}
+
+func (m *MockRepository) ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*webhook.Delivery, error) {
+	args := m.Called(ctx, now, lease, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*webhook.Delivery), args.Error(1)
+}
+
+func (m *MockRepository) GetDelivery(ctx context.Context, id uuid.UUID) (*webhook.Delivery, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*webhook.Delivery), args.Error(1)
+}
+
+func (m *MockRepository) UpdateDelivery(ctx context.Context, d *webhook.Delivery) error {
+	args := m.Called(ctx, d)
+	return args.Error(0)
+}
+
+func (m *MockRepository) ListDeliveries(ctx context.Context, filter webhook.DeliveryFilter) ([]*webhook.Delivery, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*webhook.Delivery), args.Error(1)
+}
+
+// newTestService создает сервис с фиксированным временем. Имена разрешаются
+// в публичный адрес, а клиент без проверки адресов, чтобы отправлять на httptest-сервер.
+func newTestService(repo webhook.Repository, now time.Time) *Service {
+	s := New(repo, &http.Client{Timeout: DefaultConfig.Timeout}, DefaultConfig)
+	s.now = func() time.Time { return now }
+	s.lookupIP = func(ctx context.Context, host string) ([]net.IPAddr, error) {
+		switch host {
+		case "internal.example":
+			return []net.IPAddr{{IP: net.ParseIP("203.0.113.10")}, {IP: net.ParseIP("169.254.169.254")}}, nil
+		case "unknown.example":
+			return nil, errors.New("no such host")
+		}
+		return []net.IPAddr{{IP: net.ParseIP("203.0.113.10")}}, nil
+	}
+	return s
+}
+
+func TestService_CreateSubscription(t *testing.T) {
+	createdBy := uuid.New()
+
+	tests := []struct {
+		name          string
+		url           string
+		eventTypes    []event.Type
+		secret        string
+		repoErr       error
+		expectedError error
+	}{
+		{
+			name:       "успешное создание",
+			url:        "https://partner.example/hook",
+			eventTypes: []event.Type{event.TypeReceptionClosed},
+			secret:     "partner-secret",
+		},
+		{
+			name:       "секрет генерируется, если не задан",
+			url:        "http://partner.example/hook",
+			eventTypes: []event.Type{event.TypeProductAdded},
+		},
+		{
+			name:          "адрес без схемы",
+			url:           "partner.example/hook",
+			eventTypes:    []event.Type{event.TypeProductAdded},
+			expectedError: ErrInvalidURL,
+		},
+		{
+			name:          "неподдерживаемая схема",
+			url:           "ftp://partner.example/hook",
+			eventTypes:    []event.Type{event.TypeProductAdded},
+			expectedError: ErrInvalidURL,
+		},
+		{
+			name:          "локальный адрес",
+			url:           "http://127.0.0.1:8080/hook",
+			eventTypes:    []event.Type{event.TypeProductAdded},
+			expectedError: ErrInvalidURL,
+		},
+		{
+			name:          "адрес частной сети",
+			url:           "https://10.0.0.5/hook",
+			eventTypes:    []event.Type{event.TypeProductAdded},
+			expectedError: ErrInvalidURL,
+		},
+		{
+			name:          "адрес метаданных облака",
+			url:           "http://169.254.169.254/latest/meta-data",
+			eventTypes:    []event.Type{event.TypeProductAdded},
+			expectedError: ErrInvalidURL,
+		},
+		{
+			name:          "локальный IPv6-адрес",
+			url:           "http://[::1]/hook",
+			eventTypes:    []event.Type{event.TypeProductAdded},
+			expectedError: ErrInvalidURL,
+		},
+		{
+			name:          "имя разрешается во внутренний адрес",
+			url:           "https://internal.example/hook",
+			eventTypes:    []event.Type{event.TypeProductAdded},
+			expectedError: ErrInvalidURL,
+		},
+		{
+			name:          "имя не разрешается",
+			url:           "https://unknown.example/hook",
+			eventTypes:    []event.Type{event.TypeProductAdded},
+			expectedError: ErrInvalidURL,
+		},
+		{
+			name:          "пустой список событий",
+			url:           "https://partner.example/hook",
+			expectedError: ErrInvalidEventTypes,
+		},
+		{
+			name:          "неизвестный тип события",
+			url:           "https://partner.example/hook",
+			eventTypes:    []event.Type{"pvz.created"},
+			expectedError: ErrInvalidEventTypes,
+		},
+		{
+			name:          "ошибка репозитория",
+			url:           "https://partner.example/hook",
+			eventTypes:    []event.Type{event.TypeReceptionClosed},
+			repoErr:       errors.New("db error"),
+			expectedError: errors.New("db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockRepository)
+			repo.On("CreateSubscription", mock.Anything, mock.AnythingOfType("*webhook.Subscription")).Return(tt.repoErr).Maybe()
+			s := newTestService(repo, time.Now())
+
+			sub, err := s.CreateSubscription(context.Background(), tt.url, tt.eventTypes, tt.secret, createdBy)
+			if tt.expectedError != nil {
+				assert.EqualError(t, err, tt.expectedError.Error())
+				assert.Nil(t, sub)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.url, sub.URL)
+			assert.Equal(t, tt.eventTypes, sub.EventTypes)
+			assert.Equal(t, createdBy, sub.CreatedBy)
+			if tt.secret != "" {
+				assert.Equal(t, tt.secret, sub.Secret)
+			} else {
+				assert.Len(t, sub.Secret, 2*secretSize)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_Publish(t *testing.T) {
+	now := time.Now()
+	closedSub := &webhook.Subscription{ID: uuid.New(), EventTypes: []event.Type{event.TypeReceptionClosed}}
+	productSub := &webhook.Subscription{ID: uuid.New(), EventTypes: []event.Type{event.TypeProductAdded, event.TypeReceptionClosed}}
+	otherSub := &webhook.Subscription{ID: uuid.New(), EventTypes: []event.Type{event.TypeProductRemoved}}
+
+	repo := new(MockRepository)
+	repo.On("ListSubscriptions", mock.Anything).Return([]*webhook.Subscription{closedSub, productSub, otherSub}, nil)
+
+	var created [][]*webhook.Delivery
+	repo.On("CreateDeliveries", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		created = append(created, args.Get(1).([]*webhook.Delivery))
+	}).Return(nil)
+
+	s := newTestService(repo, now)
+	e := event.ReceptionClosed{PVZID: uuid.New(), ReceptionID: uuid.New(), OccurredAt: now}
+	messages, err := outbox.FromFeed(e)
+	require.NoError(t, err)
+	m := messages[0]
+
+	require.NoError(t, s.Publish(context.Background(), m))
+	require.Len(t, created, 1)
+	require.Len(t, created[0], 2)
+	assert.Equal(t, closedSub.ID, created[0][0].SubscriptionID)
+	assert.Equal(t, productSub.ID, created[0][1].SubscriptionID)
+	for _, d := range created[0] {
+		assert.Equal(t, webhook.DeliveryPending, d.Status)
+		assert.Equal(t, event.TypeReceptionClosed, d.EventType)
+		assert.Equal(t, now, d.NextAttemptAt)
+		assert.Equal(t, m.Payload, d.Payload)
+		assert.Contains(t, string(d.Payload), `"receptionId":"`+e.ReceptionID.String()+`"`)
+	}
+
+	// Повторная публикация того же сообщения создает доставки с теми же ID
+	require.NoError(t, s.Publish(context.Background(), m))
+	require.Len(t, created, 2)
+	assert.Equal(t, created[0][0].ID, created[1][0].ID)
+	assert.Equal(t, created[0][1].ID, created[1][1].ID)
+	assert.NotEqual(t, created[0][0].ID, created[0][1].ID)
+
+	// Событие без подписчиков не создает доставок
+	opened, err := outbox.FromFeed(event.ReceptionOpened{ReceptionID: uuid.New()})
+	require.NoError(t, err)
+	require.NoError(t, s.Publish(context.Background(), opened[0]))
+	repo.AssertNumberOfCalls(t, "CreateDeliveries", 2)
+}
+
+func TestService_Publish_UnknownEventType(t *testing.T) {
+	repo := new(MockRepository)
+	s := newTestService(repo, time.Now())
+
+	m := &outbox.Message{ID: uuid.New(), EventType: "pvz.created", Payload: []byte(`{}`)}
+	require.NoError(t, s.Publish(context.Background(), m))
+	repo.AssertNotCalled(t, "ListSubscriptions", mock.Anything)
+}
+
+func TestService_Publish_RepositoryError(t *testing.T) {
+	repo := new(MockRepository)
+	repo.On("ListSubscriptions", mock.Anything).Return(nil, errors.New("db error"))
+	s := newTestService(repo, time.Now())
+
+	// Ошибка возвращается обработчику outbox, и сообщение публикуется повторно
+	messages, err := outbox.FromFeed(event.ProductAdded{ReceptionID: uuid.New()})
+	require.NoError(t, err)
+	assert.EqualError(t, s.Publish(context.Background(), messages[0]), "db error")
+}
+
+func TestService_DispatchDue(t *testing.T) {
+	now := time.Date(2026, time.October, 1, 12, 0, 0, 0, time.UTC)
+	payload := []byte(`{"type":"product.added"}`)
+
+	tests := []struct {
+		name             string
+		receiverStatus   int
+		attempts         int
+		expectedStatus   webhook.DeliveryStatus
+		expectedAttempts int
+		expectedNext     time.Time
+		expectError      bool
+	}{
+		{
+			name:             "получатель подтвердил доставку",
+			receiverStatus:   http.StatusNoContent,
+			expectedStatus:   webhook.DeliverySucceeded,
+			expectedAttempts: 1,
+			expectedNext:     now,
+		},
+		{
+			name:             "первая неудача откладывает попытку на базовую задержку",
+			receiverStatus:   http.StatusInternalServerError,
+			expectedStatus:   webhook.DeliveryPending,
+			expectedAttempts: 1,
+			expectedNext:     now.Add(30 * time.Second),
+			expectError:      true,
+		},
+		{
+			name:             "задержка удваивается с каждой попыткой",
+			receiverStatus:   http.StatusBadGateway,
+			attempts:         2,
+			expectedStatus:   webhook.DeliveryPending,
+			expectedAttempts: 3,
+			expectedNext:     now.Add(2 * time.Minute),
+			expectError:      true,
+		},
+		{
+			name:             "после последней попытки доставка в dead-letter",
+			receiverStatus:   http.StatusInternalServerError,
+			attempts:         DefaultConfig.MaxAttempts - 1,
+			expectedStatus:   webhook.DeliveryDead,
+			expectedAttempts: DefaultConfig.MaxAttempts,
+			expectedNext:     now,
+			expectError:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub := &webhook.Subscription{ID: uuid.New(), Secret: "partner-secret"}
+			d := &webhook.Delivery{
+				ID:             uuid.New(),
+				SubscriptionID: sub.ID,
+				EventType:      event.TypeProductAdded,
+				Payload:        payload,
+				Status:         webhook.DeliveryPending,
+				Attempts:       tt.attempts,
+				NextAttemptAt:  now,
+			}
+
+			var received *http.Request
+			var body []byte
+			receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				received = r
+				body, _ = io.ReadAll(r.Body)
+				w.WriteHeader(tt.receiverStatus)
+				w.Write([]byte("internal details"))
+			}))
+			defer receiver.Close()
+			sub.URL = receiver.URL
+
+			repo := new(MockRepository)
+			repo.On("ClaimDueDeliveries", mock.Anything, now, DefaultConfig.Lease, DefaultConfig.BatchSize).Return([]*webhook.Delivery{d}, nil)
+			repo.On("GetSubscription", mock.Anything, sub.ID).Return(sub, nil)
+			repo.On("UpdateDelivery", mock.Anything, d).Return(nil)
+
+			s := newTestService(repo, now)
+			n, err := s.DispatchDue(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, 1, n)
+
+			// Получатель может проверить подпись общим секретом
+			require.NotNil(t, received)
+			assert.Equal(t, payload, body)
+			assert.Equal(t, d.ID.String(), received.Header.Get(HeaderID))
+			assert.Equal(t, "product.added", received.Header.Get(HeaderEvent))
+			timestamp := received.Header.Get(HeaderTimestamp)
+			assert.Equal(t, "1790856000", timestamp)
+			mac := hmac.New(sha256.New, []byte("partner-secret"))
+			mac.Write([]byte(timestamp + "." + string(payload)))
+			assert.Equal(t, "sha256="+hex.EncodeToString(mac.Sum(nil)), received.Header.Get(HeaderSignature))
+
+			assert.Equal(t, tt.expectedStatus, d.Status)
+			assert.Equal(t, tt.expectedAttempts, d.Attempts)
+			assert.Equal(t, tt.expectedNext, d.NextAttemptAt)
+			assert.Equal(t, tt.receiverStatus, d.LastStatusCode)
+			if tt.expectError {
+				assert.Equal(t, fmt.Sprintf("unexpected status %d", tt.receiverStatus), d.LastError)
+				assert.Nil(t, d.DeliveredAt)
+			} else {
+				assert.Empty(t, d.LastError)
+				require.NotNil(t, d.DeliveredAt)
+				assert.Equal(t, now, *d.DeliveredAt)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_DispatchDue_ReceiverUnavailable(t *testing.T) {
+	now := time.Now()
+	receiver := httptest.NewServer(http.NotFoundHandler())
+	receiver.Close()
+
+	sub := &webhook.Subscription{ID: uuid.New(), URL: receiver.URL, Secret: "s"}
+	d := &webhook.Delivery{ID: uuid.New(), SubscriptionID: sub.ID, Status: webhook.DeliveryPending}
+
+	repo := new(MockRepository)
+	repo.On("ClaimDueDeliveries", mock.Anything, now, DefaultConfig.Lease, DefaultConfig.BatchSize).Return([]*webhook.Delivery{d}, nil)
+	repo.On("GetSubscription", mock.Anything, sub.ID).Return(sub, nil)
+	repo.On("UpdateDelivery", mock.Anything, d).Return(nil)
+
+	s := newTestService(repo, now)
+	_, err := s.DispatchDue(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, webhook.DeliveryPending, d.Status)
+	assert.Equal(t, 0, d.LastStatusCode)
+	assert.NotEmpty(t, d.LastError)
+	assert.Equal(t, now.Add(DefaultConfig.BaseBackoff), d.NextAttemptAt)
+}
+
+func TestService_DispatchDue_PrivateAddress(t *testing.T) {
+	now := time.Now()
+	called := false
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer receiver.Close()
+
+	// Адрес проверяется при соединении, даже если подписка его уже прошла
+	sub := &webhook.Subscription{ID: uuid.New(), URL: receiver.URL, Secret: "s"}
+	d := &webhook.Delivery{ID: uuid.New(), SubscriptionID: sub.ID, Status: webhook.DeliveryPending}
+
+	repo := new(MockRepository)
+	repo.On("ClaimDueDeliveries", mock.Anything, now, DefaultConfig.Lease, DefaultConfig.BatchSize).Return([]*webhook.Delivery{d}, nil)
+	repo.On("GetSubscription", mock.Anything, sub.ID).Return(sub, nil)
+	repo.On("UpdateDelivery", mock.Anything, d).Return(nil)
+
+	s := New(repo, nil, DefaultConfig)
+	s.now = func() time.Time { return now }
+	_, err := s.DispatchDue(context.Background())
+	require.NoError(t, err)
+
+	assert.False(t, called)
+	assert.Equal(t, webhook.DeliveryPending, d.Status)
+	assert.Equal(t, 0, d.LastStatusCode)
+	assert.Contains(t, d.LastError, errPrivateAddress.Error())
+}
+
+func TestNewClient_DoesNotFollowRedirects(t *testing.T) {
+	redirected := false
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		redirected = true
+	}))
+	defer target.Close()
+	receiver := httptest.NewServer(http.RedirectHandler(target.URL, http.StatusFound))
+	defer receiver.Close()
+
+	// Проверку адресов отключаем, чтобы соединиться с httptest-сервером
+	client := newClient(DefaultConfig)
+	client.Transport = http.DefaultTransport
+
+	resp, err := client.Post(receiver.URL, "application/json", nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusFound, resp.StatusCode)
+	assert.False(t, redirected)
+}
+
+func TestPublicIP(t *testing.T) {
+	tests := []struct {
+		ip       string
+		expected bool
+	}{
+		{"203.0.113.10", true},
+		{"2001:db8::1", true},
+		{"127.0.0.1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"0.0.0.0", false},
+		{"224.0.0.1", false},
+		{"::1", false},
+		{"fe80::1", false},
+		{"fd00::1", false},
+		{"::ffff:127.0.0.1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ip, func(t *testing.T) {
+			assert.Equal(t, tt.expected, publicIP(net.ParseIP(tt.ip)))
+		})
+	}
+}
+
+func TestService_Backoff(t *testing.T) {
+	s := New(nil, nil, Config{BaseBackoff: time.Second, MaxBackoff: 10 * time.Second})
+
+	tests := []struct {
+		name     string
+		attempts int
+		expected time.Duration
+	}{
+		{name: "после первой попытки", attempts: 1, expected: time.Second},
+		{name: "после второй попытки", attempts: 2, expected: 2 * time.Second},
+		{name: "после четвертой попытки", attempts: 4, expected: 8 * time.Second},
+		{name: "ограничение сверху", attempts: 10, expected: 10 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, s.backoff(tt.attempts))
+		})
+	}
+}
+
+func TestService_Redeliver(t *testing.T) {
+	now := time.Now()
+	id := uuid.New()
+
+	tests := []struct {
+		name          string
+		delivery      *webhook.Delivery
+		getErr        error
+		expectedError error
+	}{
+		{
+			name:     "доставка из dead-letter возвращается в очередь",
+			delivery: &webhook.Delivery{ID: id, Status: webhook.DeliveryDead, Attempts: 8, LastStatusCode: 500, LastError: "unexpected status 500"},
+		},
+		{
+			name:          "успешную доставку нельзя повторить",
+			delivery:      &webhook.Delivery{ID: id, Status: webhook.DeliverySucceeded},
+			expectedError: ErrDeliveryNotRetrying,
+		},
+		{
+			name:          "доставка не найдена",
+			getErr:        webhook.ErrDeliveryNotFound,
+			expectedError: webhook.ErrDeliveryNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockRepository)
+			if tt.getErr != nil {
+				repo.On("GetDelivery", mock.Anything, id).Return(nil, tt.getErr)
+			} else {
+				repo.On("GetDelivery", mock.Anything, id).Return(tt.delivery, nil)
+			}
+			repo.On("UpdateDelivery", mock.Anything, mock.Anything).Return(nil).Maybe()
+			s := newTestService(repo, now)
+
+			d, err := s.Redeliver(context.Background(), id)
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				repo.AssertNotCalled(t, "UpdateDelivery", mock.Anything, mock.Anything)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, webhook.DeliveryPending, d.Status)
+			assert.Equal(t, 0, d.Attempts)
+			assert.Equal(t, now, d.NextAttemptAt)
+			assert.Empty(t, d.LastError)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_ListDeliveries(t *testing.T) {
+	subID := uuid.New()
+	page := listing.Page{Limit: 10}
+
+	tests := []struct {
+		name          string
+		filter        webhook.DeliveryFilter
+		getErr        error
+		expectedError error
+	}{
+		{
+			name:   "история подписки",
+			filter: webhook.DeliveryFilter{SubscriptionID: subID, Status: webhook.DeliveryDead, Page: page},
+		},
+		{
+			name:          "неизвестный статус",
+			filter:        webhook.DeliveryFilter{SubscriptionID: subID, Status: "lost", Page: page},
+			expectedError: ErrInvalidStatus,
+		},
+		{
+			name:          "неверная пагинация",
+			filter:        webhook.DeliveryFilter{SubscriptionID: subID, Page: listing.Page{Limit: 1000}},
+			expectedError: listing.ErrInvalidPage,
+		},
+		{
+			name:          "подписка не найдена",
+			filter:        webhook.DeliveryFilter{SubscriptionID: subID, Page: page},
+			getErr:        webhook.ErrNotFound,
+			expectedError: webhook.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockRepository)
+			if tt.getErr != nil {
+				repo.On("GetSubscription", mock.Anything, subID).Return(nil, tt.getErr)
+			} else {
+				repo.On("GetSubscription", mock.Anything, subID).Return(&webhook.Subscription{ID: subID}, nil).Maybe()
+			}
+			expected := []*webhook.Delivery{{ID: uuid.New(), SubscriptionID: subID}}
+			repo.On("ListDeliveries", mock.Anything, tt.filter).Return(expected, nil).Maybe()
+			s := newTestService(repo, time.Now())
+
+			deliveries, err := s.ListDeliveries(context.Background(), tt.filter)
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, expected, deliveries)
+		})
+	}
+}
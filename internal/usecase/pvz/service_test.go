@@ -39,6 +39,18 @@ func (m *MockPVZRepository) GetWithReceptions(ctx context.Context, startDate, en
 	return args.Get(0).([]*pvz.PVZWithReceptions), args.Error(1)
 }
 
+func (m *MockPVZRepository) GetWithReceptionsByCursor(ctx context.Context, startDate, endDate time.Time, cursor *pvz.Cursor, limit int) ([]*pvz.PVZWithReceptions, *pvz.Cursor, error) {
+	args := m.Called(ctx, startDate, endDate, cursor, limit)
+	var next *pvz.Cursor
+	if c, ok := args.Get(1).(*pvz.Cursor); ok {
+		next = c
+	}
+	if args.Get(0) == nil {
+		return nil, next, args.Error(2)
+	}
+	return args.Get(0).([]*pvz.PVZWithReceptions), next, args.Error(2)
+}
+
 func (m *MockPVZRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
@@ -62,6 +74,14 @@ func (m *MockPVZRepository) List(ctx context.Context, offset, limit int) ([]*pvz
 	return args.Get(0).([]*pvz.PVZ), args.Error(1)
 }
 
+func (m *MockPVZRepository) ListByFilter(ctx context.Context, filter pvz.ListFilter) ([]*pvz.PVZ, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*pvz.PVZ), args.Error(1)
+}
+
 func (m *MockPVZRepository) Update(ctx context.Context, pvz *pvz.PVZ) error {
 	args := m.Called(ctx, pvz)
 	return args.Error(0)
@@ -98,6 +118,14 @@ func (m *MockReceptionRepository) GetByID(ctx context.Context, id uuid.UUID) (*r
 	return args.Get(0).(*reception.Reception), args.Error(1)
 }
 
+func (m *MockReceptionRepository) GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*reception.Reception, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*reception.Reception), args.Error(1)
+}
+
 func (m *MockReceptionRepository) GetLastOpen(ctx context.Context, pvzID uuid.UUID) (*reception.Reception, error) {
 	args := m.Called(ctx, pvzID)
 	if args.Get(0) == nil {
@@ -116,6 +144,14 @@ func (m *MockReceptionRepository) List(ctx context.Context, offset, limit int) (
 	return args.Get(0).([]*reception.Reception), args.Error(1)
 }
 
+func (m *MockReceptionRepository) ListByFilter(ctx context.Context, filter reception.ListFilter) ([]*reception.Reception, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*reception.Reception), args.Error(1)
+}
+
 func (m *MockReceptionRepository) Update(ctx context.Context, reception *reception.Reception) error {
 	args := m.Called(ctx, reception)
 	return args.Error(0)
@@ -2,7 +2,6 @@ package auth
 
 import (
 	"errors"
-	"time"
 
 	"github.com/avito/pvz/internal/domain/user"
 	"github.com/golang-jwt/jwt/v5"
@@ -10,11 +9,6 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
-const (
-	// SecretKey используется для подписи JWT токенов
-	SecretKey = "your-secret-key"
-)
-
 var (
 	// ErrInvalidToken возвращается при невалидном токене
 	ErrInvalidToken = errors.New("invalid token")
@@ -27,6 +21,9 @@ var (
 type Claims struct {
 	UserID uuid.UUID `json:"user_id"`
 	Role   user.Role `json:"role"`
+	// SessionID сессия входа, к которой относится токен. uuid.Nil у токенов,
+	// выпущенных без сессии, например тестовым входом.
+	SessionID uuid.UUID `json:"sid"`
 	jwt.RegisteredClaims
 }
 
@@ -41,36 +38,3 @@ func CheckPasswordHash(password, hash string) bool {
 	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
 	return err == nil
 }
-
-// GenerateToken генерирует JWT токен
-func GenerateToken(userID uuid.UUID, role user.Role) (string, error) {
-	claims := &Claims{
-		UserID: userID,
-		Role:   role,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-		},
-	}
-
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(SecretKey))
-}
-
-// ValidateToken проверяет валидность JWT токена
-func ValidateToken(tokenString string) (*Claims, error) {
-	claims := &Claims{}
-	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		return []byte(SecretKey), nil
-	})
-
-	if err != nil {
-		return nil, err
-	}
-
-	if !token.Valid {
-		return nil, ErrInvalidToken
-	}
-
-	return claims, nil
-}
@@ -91,7 +91,7 @@ func TestGenerateToken(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			token, err := GenerateToken(tt.userID, tt.role)
+			token, err := newTestTokenService(t).GenerateToken(tt.userID, tt.role)
 			if tt.wantErr {
 				assert.Error(t, err)
 				return
@@ -106,7 +106,8 @@ func TestValidateToken(t *testing.T) {
 	userID := uuid.New()
 	role := user.Role("admin")
 
-	validToken, _ := GenerateToken(userID, role)
+	tokens := newTestTokenService(t)
+	validToken, _ := tokens.GenerateToken(userID, role)
 	invalidToken := "invalid.token.string"
 
 	tests := []struct {
@@ -137,7 +138,7 @@ func TestValidateToken(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			claims, err := ValidateToken(tt.token)
+			claims, err := tokens.ValidateToken(tt.token)
 			if tt.wantErr {
 				assert.Error(t, err)
 				return
@@ -165,10 +166,10 @@ func TestTokenExpiration(t *testing.T) {
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	expiredToken, _ := token.SignedString([]byte(SecretKey))
+	expiredToken, _ := token.SignedString([]byte(DefaultSecret))
 
 	t.Run("проверка истекшего токена", func(t *testing.T) {
-		claims, err := ValidateToken(expiredToken)
+		claims, err := newTestTokenService(t).ValidateToken(expiredToken)
 		assert.Error(t, err)
 		assert.Nil(t, claims)
 	})
@@ -189,7 +190,7 @@ func TestValidateTokenWithInvalidSignature(t *testing.T) {
 	invalidToken, _ := token.SignedString([]byte("wrong-secret-key"))
 
 	// Проверяем токен
-	claims, err := ValidateToken(invalidToken)
+	claims, err := newTestTokenService(t).ValidateToken(invalidToken)
 	assert.Error(t, err)
 	assert.Nil(t, claims)
 	assert.Contains(t, err.Error(), "token signature is invalid")
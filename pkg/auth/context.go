@@ -12,6 +12,7 @@ type contextKey string
 const (
 	userIDKey   contextKey = "user_id"
 	userRoleKey contextKey = "user_role"
+	clientIPKey contextKey = "client_ip"
 )
 
 // WithUserID добавляет ID пользователя в контекст
@@ -35,3 +36,14 @@ func GetUserRole(ctx context.Context) (user.Role, bool) {
 	role, ok := ctx.Value(userRoleKey).(user.Role)
 	return role, ok
 }
+
+// WithClientIP добавляет IP-адрес клиента в контекст
+func WithClientIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, clientIPKey, ip)
+}
+
+// GetClientIP получает IP-адрес клиента из контекста
+func GetClientIP(ctx context.Context) (string, bool) {
+	ip, ok := ctx.Value(clientIPKey).(string)
+	return ip, ok && ip != ""
+}
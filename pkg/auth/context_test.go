@@ -45,6 +45,18 @@ func TestWithUserRole(t *testing.T) {
 	assert.False(t, ok)
 }
 
+func TestWithClientIP(t *testing.T) {
+	ctx := WithClientIP(context.Background(), "10.0.0.1")
+
+	ip, ok := GetClientIP(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, "10.0.0.1", ip)
+
+	// Пустой адрес считается отсутствующим
+	_, ok = GetClientIP(WithClientIP(context.Background(), ""))
+	assert.False(t, ok)
+}
+
 func TestContextWithBothValues(t *testing.T) {
 	ctx := context.Background()
 	userID := uuid.New()
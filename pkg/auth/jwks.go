@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+	"net/http"
+
+	"github.com/avito/pvz/pkg/httpresponse"
+)
+
+// JWKSPath путь, по которому публикуются открытые ключи
+const JWKSPath = "/.well-known/jwks.json"
+
+// JWK открытый ключ в формате RFC 7517
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	// N и E модуль и экспонента ключа RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// Curve и X кривая и открытый ключ Ed25519 (RFC 8037)
+	Curve string `json:"crv,omitempty"`
+	X     string `json:"x,omitempty"`
+}
+
+// JWKS набор открытых ключей
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// newJWK описывает открытый ключ набора
+func newJWK(key *Key) JWK {
+	jwk := JWK{
+		KeyID:     key.ID,
+		Use:       "sig",
+		Algorithm: key.Method.Alg(),
+	}
+
+	switch pub := key.Public().(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Curve = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+	}
+
+	return jwk
+}
+
+// JWKS возвращает открытые ключи, которыми проверяются токены. При подписи
+// общим секретом набор пуст: секрет не публикуется.
+func (s *TokenService) JWKS() JWKS {
+	set := JWKS{Keys: []JWK{}}
+	if s.keys == nil {
+		return set
+	}
+
+	for _, key := range s.keys.PublicKeys(s.now()) {
+		set.Keys = append(set.Keys, newJWK(key))
+	}
+	return set
+}
+
+// JWKSHandler отдает открытые ключи для сервисов, проверяющих токены
+func (s *TokenService) JWKSHandler(w http.ResponseWriter, r *http.Request) {
+	// Проверяющие сервисы кэшируют набор, новый ключ публикуется заранее
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	httpresponse.JSON(w, http.StatusOK, s.JWKS())
+}
@@ -0,0 +1,52 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenService_JWKSHandler(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	writeKey(t, dir, "rsa", rsaKey, time.Time{})
+	// Запланированный ключ публикуется до вступления в действие
+	writeKey(t, dir, "ed", newEd25519Key(t), time.Now().Add(time.Hour))
+
+	cfg := DefaultTokenConfig
+	cfg.KeysDir = dir
+	tokens, err := NewTokenService(cfg)
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	tokens.JWKSHandler(rec, httptest.NewRequest(http.MethodGet, JWKSPath, nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var set JWKS
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&set))
+	require.Len(t, set.Keys, 2)
+
+	byID := map[string]JWK{}
+	for _, key := range set.Keys {
+		byID[key.KeyID] = key
+	}
+	assert.Equal(t, "RSA", byID["rsa"].KeyType)
+	assert.Equal(t, "RS256", byID["rsa"].Algorithm)
+	assert.Equal(t, "AQAB", byID["rsa"].E)
+	assert.Equal(t, "OKP", byID["ed"].KeyType)
+	assert.Equal(t, "Ed25519", byID["ed"].Curve)
+	assert.NotEmpty(t, byID["ed"].X)
+}
+
+func TestTokenService_JWKS_Secret(t *testing.T) {
+	assert.Empty(t, newTestTokenService(t).JWKS().Keys)
+}
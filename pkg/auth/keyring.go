@@ -0,0 +1,215 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// keyFileExt расширение файлов ключей в каталоге набора
+	keyFileExt = ".pem"
+	// activatesAtHeader заголовок PEM со временем начала подписи ключом в формате RFC 3339
+	activatesAtHeader = "Activates-At"
+	// minRSAKeyBits минимальная длина ключа RSA
+	minRSAKeyBits = 2048
+)
+
+var (
+	// ErrNoSigningKey возвращается, если в наборе нет действующего ключа подписи
+	ErrNoSigningKey = errors.New("no active signing key")
+	// ErrUnknownKey возвращается, если ключ из заголовка kid не найден или выведен из оборота
+	ErrUnknownKey = errors.New("unknown signing key")
+	// ErrUnsupportedKey возвращается для ключей, отличных от RSA и Ed25519
+	ErrUnsupportedKey = errors.New("unsupported key type")
+)
+
+// Key ключ подписи из набора
+type Key struct {
+	// ID значение kid, совпадает с именем файла без расширения
+	ID string
+	// Method алгоритм подписи: RS256 для RSA, EdDSA для Ed25519
+	Method jwt.SigningMethod
+	// ActivatesAt время, с которого ключ подписывает токены
+	ActivatesAt time.Time
+
+	private crypto.Signer
+}
+
+// Public возвращает открытый ключ
+func (k *Key) Public() crypto.PublicKey {
+	return k.private.Public()
+}
+
+// KeyRing набор ключей подписи из каталога. Каждый файл *.pem содержит закрытый
+// ключ RSA или Ed25519 в PKCS #8 (или RSA в PKCS #1). Необязательный заголовок
+// PEM Activates-At задает время, с которого ключ подписывает токены, поэтому
+// смену ключа можно запланировать заранее. Подписывает последний вступивший в
+// действие ключ; предыдущий продолжает проверять токены еще GracePeriod после
+// вступления преемника, а затем выводится из оборота.
+type KeyRing struct {
+	dir   string
+	grace time.Duration
+
+	mu sync.RWMutex
+	// keys ключи в порядке вступления в действие
+	keys []*Key
+}
+
+// LoadKeyRing загружает набор ключей из каталога dir
+func LoadKeyRing(dir string, grace time.Duration) (*KeyRing, error) {
+	r := &KeyRing{dir: dir, grace: grace}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload перечитывает каталог ключей. При ошибке набор не меняется.
+func (r *KeyRing) Reload() error {
+	paths, err := filepath.Glob(filepath.Join(r.dir, "*"+keyFileExt))
+	if err != nil {
+		return err
+	}
+
+	keys := make([]*Key, 0, len(paths))
+	for _, path := range paths {
+		key, err := loadKey(path)
+		if err != nil {
+			return fmt.Errorf("failed to load key %s: %w", path, err)
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return fmt.Errorf("%w in %s", ErrNoSigningKey, r.dir)
+	}
+
+	// При одинаковом времени вступления позже вступает ключ с большим ID
+	sort.Slice(keys, func(i, j int) bool {
+		if !keys[i].ActivatesAt.Equal(keys[j].ActivatesAt) {
+			return keys[i].ActivatesAt.Before(keys[j].ActivatesAt)
+		}
+		return keys[i].ID < keys[j].ID
+	})
+
+	r.mu.Lock()
+	r.keys = keys
+	r.mu.Unlock()
+	return nil
+}
+
+// SigningKey возвращает ключ, которым подписываются токены в момент now
+func (r *KeyRing) SigningKey(now time.Time) (*Key, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for i := len(r.keys) - 1; i >= 0; i-- {
+		if !now.Before(r.keys[i].ActivatesAt) {
+			return r.keys[i], nil
+		}
+	}
+	return nil, ErrNoSigningKey
+}
+
+// VerificationKey возвращает ключ kid, если в момент now он еще проверяет токены
+func (r *KeyRing) VerificationKey(kid string, now time.Time) (*Key, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for i, key := range r.keys {
+		if key.ID != kid {
+			continue
+		}
+		if r.retired(i, now) {
+			return nil, ErrUnknownKey
+		}
+		return key, nil
+	}
+	return nil, ErrUnknownKey
+}
+
+// PublicKeys возвращает ключи, которые в момент now проверяют токены, включая
+// запланированные: проверяющие сервисы получают их заранее
+func (r *KeyRing) PublicKeys(now time.Time) []*Key {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	keys := make([]*Key, 0, len(r.keys))
+	for i, key := range r.keys {
+		if !r.retired(i, now) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// retired сообщает, выведен ли i-й ключ из оборота: его преемник подписывает
+// токены дольше GracePeriod
+func (r *KeyRing) retired(i int, now time.Time) bool {
+	if i == len(r.keys)-1 {
+		return false
+	}
+	return !now.Before(r.keys[i+1].ActivatesAt.Add(r.grace))
+}
+
+// loadKey читает закрытый ключ из PEM-файла
+func loadKey(path string) (*Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	var parsed interface{}
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("%w: PEM block %q", ErrUnsupportedKey, block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	key := &Key{ID: strings.TrimSuffix(filepath.Base(path), keyFileExt)}
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		if k.N.BitLen() < minRSAKeyBits {
+			return nil, fmt.Errorf("%w: RSA key shorter than %d bits", ErrUnsupportedKey, minRSAKeyBits)
+		}
+		key.Method = jwt.SigningMethodRS256
+		key.private = k
+	case ed25519.PrivateKey:
+		key.Method = jwt.SigningMethodEdDSA
+		key.private = k
+	default:
+		return nil, fmt.Errorf("%w: %T", ErrUnsupportedKey, parsed)
+	}
+
+	if value, ok := block.Headers[activatesAtHeader]; ok {
+		key.ActivatesAt, err = time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s header: %w", activatesAtHeader, err)
+		}
+	}
+
+	return key, nil
+}
@@ -0,0 +1,179 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/user"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeKey сохраняет закрытый ключ в каталог набора
+func writeKey(t *testing.T, dir, id string, private interface{}, activatesAt time.Time) {
+	t.Helper()
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	require.NoError(t, err)
+
+	block := &pem.Block{Type: "PRIVATE KEY", Bytes: der}
+	if !activatesAt.IsZero() {
+		block.Headers = map[string]string{activatesAtHeader: activatesAt.Format(time.RFC3339)}
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, id+keyFileExt), pem.EncodeToMemory(block), 0o600))
+}
+
+func newEd25519Key(t *testing.T) ed25519.PrivateKey {
+	t.Helper()
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	return private
+}
+
+func TestLoadKeyRing(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	shortKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	t.Run("RSA и Ed25519", func(t *testing.T) {
+		dir := t.TempDir()
+		writeKey(t, dir, "rsa", rsaKey, time.Time{})
+		writeKey(t, dir, "ed", newEd25519Key(t), time.Now().Add(time.Hour))
+
+		ring, err := LoadKeyRing(dir, time.Hour)
+		require.NoError(t, err)
+
+		key, err := ring.VerificationKey("rsa", time.Now())
+		require.NoError(t, err)
+		assert.Equal(t, jwt.SigningMethodRS256, key.Method)
+
+		key, err = ring.VerificationKey("ed", time.Now())
+		require.NoError(t, err)
+		assert.Equal(t, jwt.SigningMethodEdDSA, key.Method)
+	})
+
+	t.Run("пустой каталог", func(t *testing.T) {
+		_, err := LoadKeyRing(t.TempDir(), time.Hour)
+		assert.ErrorIs(t, err, ErrNoSigningKey)
+	})
+
+	t.Run("короткий ключ RSA", func(t *testing.T) {
+		dir := t.TempDir()
+		writeKey(t, dir, "short", shortKey, time.Time{})
+
+		_, err := LoadKeyRing(dir, time.Hour)
+		assert.ErrorIs(t, err, ErrUnsupportedKey)
+	})
+}
+
+func TestKeyRing_Rotation(t *testing.T) {
+	rotateAt := time.Date(2026, time.October, 1, 12, 0, 0, 0, time.UTC)
+	grace := time.Hour
+
+	dir := t.TempDir()
+	writeKey(t, dir, "2026-09", newEd25519Key(t), time.Time{})
+	writeKey(t, dir, "2026-10", newEd25519Key(t), rotateAt)
+
+	ring, err := LoadKeyRing(dir, grace)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		now        time.Time
+		signing    string
+		oldValid   bool
+		publicKeys int
+	}{
+		{name: "до смены", now: rotateAt.Add(-time.Minute), signing: "2026-09", oldValid: true, publicKeys: 2},
+		{name: "в льготный период", now: rotateAt.Add(grace - time.Minute), signing: "2026-10", oldValid: true, publicKeys: 2},
+		{name: "после льготного периода", now: rotateAt.Add(grace), signing: "2026-10", oldValid: false, publicKeys: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := ring.SigningKey(tt.now)
+			require.NoError(t, err)
+			assert.Equal(t, tt.signing, key.ID)
+
+			_, err = ring.VerificationKey("2026-09", tt.now)
+			if tt.oldValid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrUnknownKey)
+			}
+			assert.Len(t, ring.PublicKeys(tt.now), tt.publicKeys)
+		})
+	}
+}
+
+func TestTokenService_KeyRing(t *testing.T) {
+	rotateAt := time.Date(2026, time.October, 1, 12, 0, 0, 0, time.UTC)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	writeKey(t, dir, "old", rsaKey, time.Time{})
+	writeKey(t, dir, "new", newEd25519Key(t), rotateAt)
+
+	cfg := DefaultTokenConfig
+	cfg.Secret = ""
+	cfg.KeysDir = dir
+	tokens, err := NewTokenService(cfg)
+	require.NoError(t, err)
+	assert.True(t, tokens.Asymmetric())
+
+	// Токен, подписанный старым ключом незадолго до смены
+	tokens.now = func() time.Time { return rotateAt.Add(-time.Minute) }
+	oldToken, err := tokens.GenerateToken(uuid.New(), user.RoleEmployee)
+	require.NoError(t, err)
+
+	tokens.now = func() time.Time { return rotateAt.Add(time.Minute) }
+	newToken, err := tokens.GenerateToken(uuid.New(), user.RoleEmployee)
+	require.NoError(t, err)
+
+	parsed, _, err := jwt.NewParser().ParseUnverified(newToken, &Claims{})
+	require.NoError(t, err)
+	assert.Equal(t, "new", parsed.Header["kid"])
+	assert.Equal(t, jwt.SigningMethodEdDSA.Alg(), parsed.Method.Alg())
+
+	_, err = tokens.ValidateToken(oldToken)
+	assert.NoError(t, err, "старый ключ действует в льготный период")
+	_, err = tokens.ValidateToken(newToken)
+	assert.NoError(t, err)
+
+	t.Run("подмена алгоритма", func(t *testing.T) {
+		forged := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "x"})
+		forged.Header["kid"] = "new"
+		signed, err := forged.SignedString([]byte("secret"))
+		require.NoError(t, err)
+
+		_, err = tokens.ValidateToken(signed)
+		assert.Error(t, err)
+	})
+
+	t.Run("неизвестный kid", func(t *testing.T) {
+		forged := jwt.NewWithClaims(jwt.SigningMethodEdDSA, &Claims{})
+		forged.Header["kid"] = "missing"
+		signed, err := forged.SignedString(newEd25519Key(t))
+		require.NoError(t, err)
+
+		_, err = tokens.ValidateToken(signed)
+		assert.Error(t, err)
+	})
+
+	t.Run("короткий льготный период", func(t *testing.T) {
+		short := cfg
+		short.KeyGracePeriod = cfg.TTL
+		_, err := NewTokenService(short)
+		assert.ErrorIs(t, err, ErrShortGracePeriod)
+	})
+}
@@ -8,7 +8,7 @@ import (
 )
 
 // Middleware проверяет JWT токен в заголовке Authorization
-func Middleware(next http.Handler) http.Handler {
+func (s *TokenService) Middleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Получаем токен из заголовка
 		authHeader := r.Header.Get("Authorization")
@@ -20,14 +20,14 @@ func Middleware(next http.Handler) http.Handler {
 		// Проверяем формат заголовка
 		parts := strings.Split(authHeader, " ")
 		if len(parts) != 2 || parts[0] != "Bearer" {
-			httpresponse.Error(w, http.StatusUnauthorized, "invalid authorization header format")
+			httpresponse.Problem(w, http.StatusUnauthorized, "invalid_token", "invalid authorization header format")
 			return
 		}
 
 		// Проверяем токен
-		claims, err := ValidateToken(parts[1])
+		claims, err := s.ValidateToken(parts[1])
 		if err != nil {
-			httpresponse.Error(w, http.StatusUnauthorized, "invalid token")
+			httpresponse.Problem(w, http.StatusUnauthorized, "invalid_token", "invalid token")
 			return
 		}
 
@@ -24,19 +24,19 @@ func TestMiddleware(t *testing.T) {
 			name:           "отсутствует заголовок авторизации",
 			authHeader:     "",
 			expectedStatus: http.StatusUnauthorized,
-			expectedBody:   `{"error":"missing authorization header"}`,
+			expectedBody:   `{"type":"about:blank","title":"Unauthorized","status":401,"detail":"missing authorization header","code":"unauthorized"}`,
 		},
 		{
 			name:           "неверный формат заголовка",
 			authHeader:     "InvalidFormat",
 			expectedStatus: http.StatusUnauthorized,
-			expectedBody:   `{"error":"invalid authorization header format"}`,
+			expectedBody:   `{"type":"about:blank","title":"Unauthorized","status":401,"detail":"invalid authorization header format","code":"invalid_token"}`,
 		},
 		{
 			name:           "неверный формат токена",
 			authHeader:     "Bearer invalid.token",
 			expectedStatus: http.StatusUnauthorized,
-			expectedBody:   `{"error":"invalid token"}`,
+			expectedBody:   `{"type":"about:blank","title":"Unauthorized","status":401,"detail":"invalid token","code":"invalid_token"}`,
 		},
 	}
 
@@ -66,7 +66,7 @@ func TestMiddleware(t *testing.T) {
 			rr := httptest.NewRecorder()
 
 			// Создаем middleware с тестовым обработчиком
-			middleware := Middleware(handler)
+			middleware := newTestTokenService(t).Middleware(handler)
 
 			// Выполняем запрос
 			middleware.ServeHTTP(rr, req)
@@ -81,8 +81,10 @@ func TestMiddleware(t *testing.T) {
 
 	// Тест с валидным токеном
 	t.Run("валидный токен", func(t *testing.T) {
+		tokens := newTestTokenService(t)
+
 		// Генерируем валидный токен
-		token, err := GenerateToken(userID, role)
+		token, err := tokens.GenerateToken(userID, role)
 		assert.NoError(t, err)
 
 		// Создаем тестовый обработчик
@@ -107,7 +109,7 @@ func TestMiddleware(t *testing.T) {
 		rr := httptest.NewRecorder()
 
 		// Создаем middleware с тестовым обработчиком
-		middleware := Middleware(handler)
+		middleware := tokens.Middleware(handler)
 
 		// Выполняем запрос
 		middleware.ServeHTTP(rr, req)
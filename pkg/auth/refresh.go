@@ -0,0 +1,38 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// opaqueTokenBytes число случайных байт в непрозрачном токене
+const opaqueTokenBytes = 32
+
+// NewOpaqueToken генерирует случайный непрозрачный токен: токен обновления
+// или одноразовый токен из письма пользователю
+func NewOpaqueToken() (string, error) {
+	b := make([]byte, opaqueTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashOpaqueToken возвращает SHA-256 непрозрачного токена в hex. В базе
+// хранится только хеш, поэтому утечка таблицы не позволяет предъявить токены.
+func HashOpaqueToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// NewRefreshToken генерирует непрозрачный токен обновления
+func NewRefreshToken() (string, error) {
+	return NewOpaqueToken()
+}
+
+// HashRefreshToken возвращает SHA-256 токена обновления в hex
+func HashRefreshToken(token string) string {
+	return HashOpaqueToken(token)
+}
@@ -0,0 +1,25 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRefreshToken(t *testing.T) {
+	first, err := NewRefreshToken()
+	require.NoError(t, err)
+	second, err := NewRefreshToken()
+	require.NoError(t, err)
+
+	assert.Len(t, first, 43)
+	assert.NotEqual(t, first, second)
+}
+
+func TestHashRefreshToken(t *testing.T) {
+	hash := HashRefreshToken("token")
+	assert.Len(t, hash, 64)
+	assert.Equal(t, hash, HashRefreshToken("token"))
+	assert.NotEqual(t, hash, HashRefreshToken("other"))
+}
@@ -0,0 +1,221 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/user"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// DefaultSecret секрет подписи из примеров конфигурации. Он известен всем,
+// поэтому в production с ним приложение не запускается.
+const DefaultSecret = "your-secret-key"
+
+var (
+	// ErrEmptySecret возвращается, если секрет подписи не задан
+	ErrEmptySecret = errors.New("jwt secret is empty")
+	// ErrInvalidTTL возвращается, если время жизни токена не положительное
+	ErrInvalidTTL = errors.New("jwt ttl must be positive")
+	// ErrShortGracePeriod возвращается, если старый ключ выводится из оборота
+	// раньше, чем истекут подписанные им токены
+	ErrShortGracePeriod = errors.New("jwt key grace period is shorter than token ttl")
+)
+
+// TokenConfig параметры выпуска и проверки токенов
+type TokenConfig struct {
+	// Secret ключ подписи HS256. Не используется, если задан KeysDir.
+	Secret string
+	// KeysDir каталог закрытых ключей RS256 и EdDSA (см. KeyRing). Если задан,
+	// токены подписываются асимметрично, а открытые ключи публикуются в JWKS.
+	KeysDir string
+	// KeyGracePeriod время, в течение которого ключ проверяет токены после
+	// вступления в действие преемника. Не меньше TTL с учетом ClockSkew.
+	KeyGracePeriod time.Duration
+	// KeyReloadInterval период перечитывания каталога ключей
+	KeyReloadInterval time.Duration
+	// Issuer значение iss. Если задано, токены с другим издателем отклоняются.
+	Issuer string
+	// Audience значение aud. Если задано, токены для другой аудитории отклоняются.
+	Audience string
+	// TTL время жизни токена. Токены доступа короткоживущие: сессия
+	// продлевается токеном обновления.
+	TTL time.Duration
+	// ClockSkew допустимое расхождение часов при проверке exp, nbf и iat
+	ClockSkew time.Duration
+}
+
+// DefaultTokenConfig параметры токенов по умолчанию
+var DefaultTokenConfig = TokenConfig{
+	Secret:            DefaultSecret,
+	Issuer:            "avito-pvz",
+	Audience:          "avito-pvz",
+	TTL:               15 * time.Minute,
+	ClockSkew:         30 * time.Second,
+	KeyGracePeriod:    time.Hour,
+	KeyReloadInterval: time.Minute,
+}
+
+// TokenService выпускает и проверяет JWT токены доступа
+type TokenService struct {
+	cfg TokenConfig
+	// keys набор ключей асимметричной подписи, nil при подписи секретом
+	keys *KeyRing
+	now  func() time.Time
+}
+
+// NewTokenService создает новый экземпляр TokenService
+func NewTokenService(cfg TokenConfig) (*TokenService, error) {
+	if cfg.KeysDir == "" && cfg.Secret == "" {
+		return nil, ErrEmptySecret
+	}
+	if cfg.TTL <= 0 {
+		return nil, ErrInvalidTTL
+	}
+
+	s := &TokenService{
+		cfg: cfg,
+		now: time.Now,
+	}
+
+	if cfg.KeysDir != "" {
+		if cfg.KeyGracePeriod < cfg.TTL+cfg.ClockSkew {
+			return nil, ErrShortGracePeriod
+		}
+		keys, err := LoadKeyRing(cfg.KeysDir, cfg.KeyGracePeriod)
+		if err != nil {
+			return nil, err
+		}
+		s.keys = keys
+	}
+
+	return s, nil
+}
+
+// Asymmetric сообщает, подписываются ли токены ключами из набора
+func (s *TokenService) Asymmetric() bool {
+	return s.keys != nil
+}
+
+// Run перечитывает каталог ключей до отмены ctx, чтобы новые ключи
+// подхватывались без перезапуска. При подписи секретом сразу завершается.
+func (s *TokenService) Run(ctx context.Context) {
+	if s.keys == nil || s.cfg.KeyReloadInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(s.cfg.KeyReloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.keys.Reload(); err != nil {
+				log.Printf("Failed to reload jwt keys: %v", err)
+			}
+		}
+	}
+}
+
+// GenerateToken генерирует JWT токен без сессии
+func (s *TokenService) GenerateToken(userID uuid.UUID, role user.Role) (string, error) {
+	return s.GenerateSessionToken(userID, role, uuid.Nil)
+}
+
+// GenerateSessionToken генерирует JWT токен сессии sessionID. Каждый токен
+// получает уникальный jti.
+func (s *TokenService) GenerateSessionToken(userID uuid.UUID, role user.Role, sessionID uuid.UUID) (string, error) {
+	now := s.now()
+	claims := &Claims{
+		UserID:    userID,
+		Role:      role,
+		SessionID: sessionID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Issuer:    s.cfg.Issuer,
+			ExpiresAt: jwt.NewNumericDate(now.Add(s.cfg.TTL)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	}
+	if s.cfg.Audience != "" {
+		claims.Audience = jwt.ClaimStrings{s.cfg.Audience}
+	}
+
+	if s.keys == nil {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		return token.SignedString([]byte(s.cfg.Secret))
+	}
+
+	key, err := s.keys.SigningKey(now)
+	if err != nil {
+		return "", err
+	}
+	token := jwt.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.private)
+}
+
+// MaxTokenAge время, в течение которого выпущенный токен проходит проверку:
+// время жизни с учетом допустимого расхождения часов
+func (s *TokenService) MaxTokenAge() time.Duration {
+	return s.cfg.TTL + s.cfg.ClockSkew
+}
+
+// ValidateToken проверяет подпись, срок действия, издателя и аудиторию токена
+func (s *TokenService) ValidateToken(tokenString string) (*Claims, error) {
+	methods := []string{jwt.SigningMethodHS256.Alg()}
+	if s.keys != nil {
+		methods = []string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg()}
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods(methods),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(s.cfg.ClockSkew),
+		jwt.WithTimeFunc(s.now),
+	}
+	if s.cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(s.cfg.Issuer))
+	}
+	if s.cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(s.cfg.Audience))
+	}
+
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, s.verificationKey, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if !token.Valid {
+		return nil, ErrInvalidToken
+	}
+
+	return claims, nil
+}
+
+// verificationKey возвращает ключ проверки подписи токена: секрет или
+// открытый ключ из набора по заголовку kid
+func (s *TokenService) verificationKey(token *jwt.Token) (interface{}, error) {
+	if s.keys == nil {
+		return []byte(s.cfg.Secret), nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	key, err := s.keys.VerificationKey(kid, s.now())
+	if err != nil {
+		return nil, err
+	}
+	// Алгоритм из заголовка должен совпадать с алгоритмом ключа
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("%w: algorithm %s does not match key %s", ErrInvalidToken, token.Method.Alg(), kid)
+	}
+	return key.Public(), nil
+}
@@ -0,0 +1,174 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/avito/pvz/internal/domain/user"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestTokenService создает сервис токенов с параметрами по умолчанию
+func newTestTokenService(t *testing.T) *TokenService {
+	t.Helper()
+	tokens, err := NewTokenService(DefaultTokenConfig)
+	require.NoError(t, err)
+	return tokens
+}
+
+func TestNewTokenService(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(cfg *TokenConfig)
+		wantErr error
+	}{
+		{name: "параметры по умолчанию", modify: func(cfg *TokenConfig) {}},
+		{name: "пустой секрет", modify: func(cfg *TokenConfig) { cfg.Secret = "" }, wantErr: ErrEmptySecret},
+		{name: "нулевое время жизни", modify: func(cfg *TokenConfig) { cfg.TTL = 0 }, wantErr: ErrInvalidTTL},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultTokenConfig
+			tt.modify(&cfg)
+
+			_, err := NewTokenService(cfg)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestTokenService_Claims(t *testing.T) {
+	now := time.Date(2026, time.October, 1, 12, 0, 0, 0, time.UTC)
+	tokens := newTestTokenService(t)
+	tokens.now = func() time.Time { return now }
+
+	token, err := tokens.GenerateToken(uuid.New(), user.RoleEmployee)
+	require.NoError(t, err)
+
+	claims, err := tokens.ValidateToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, "avito-pvz", claims.Issuer)
+	assert.Equal(t, jwt.ClaimStrings{"avito-pvz"}, claims.Audience)
+	assert.True(t, now.Add(DefaultTokenConfig.TTL).Equal(claims.ExpiresAt.Time))
+}
+
+func TestTokenService_ValidateToken(t *testing.T) {
+	now := time.Date(2026, time.October, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		modify   func(cfg *TokenConfig)
+		checkAt  time.Time
+		wantErr  bool
+		errorMsg string
+	}{
+		{
+			name:    "в пределах расхождения часов",
+			modify:  func(cfg *TokenConfig) {},
+			checkAt: now.Add(DefaultTokenConfig.TTL + 10*time.Second),
+		},
+		{
+			name:    "истек с учетом расхождения часов",
+			modify:  func(cfg *TokenConfig) {},
+			checkAt: now.Add(DefaultTokenConfig.TTL + time.Minute),
+			wantErr: true,
+		},
+		{
+			name:     "другой издатель",
+			modify:   func(cfg *TokenConfig) { cfg.Issuer = "other" },
+			checkAt:  now,
+			wantErr:  true,
+			errorMsg: "token has invalid issuer",
+		},
+		{
+			name:     "другая аудитория",
+			modify:   func(cfg *TokenConfig) { cfg.Audience = "other" },
+			checkAt:  now,
+			wantErr:  true,
+			errorMsg: "token has invalid audience",
+		},
+		{
+			name:     "другой секрет",
+			modify:   func(cfg *TokenConfig) { cfg.Secret = "other-secret" },
+			checkAt:  now,
+			wantErr:  true,
+			errorMsg: "token signature is invalid",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issuer := newTestTokenService(t)
+			issuer.now = func() time.Time { return now }
+			token, err := issuer.GenerateToken(uuid.New(), user.RoleAdmin)
+			require.NoError(t, err)
+
+			cfg := DefaultTokenConfig
+			tt.modify(&cfg)
+			validator, err := NewTokenService(cfg)
+			require.NoError(t, err)
+			validator.now = func() time.Time { return tt.checkAt }
+
+			claims, err := validator.ValidateToken(token)
+			if !tt.wantErr {
+				require.NoError(t, err)
+				assert.Equal(t, user.RoleAdmin, claims.Role)
+				return
+			}
+			assert.Error(t, err)
+			assert.Nil(t, claims)
+			if tt.errorMsg != "" {
+				assert.Contains(t, err.Error(), tt.errorMsg)
+			}
+		})
+	}
+}
+
+func TestTokenService_RejectsOtherAlgorithms(t *testing.T) {
+	claims := &Claims{
+		UserID: uuid.New(),
+		Role:   user.RoleAdmin,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    DefaultTokenConfig.Issuer,
+			Audience:  jwt.ClaimStrings{DefaultTokenConfig.Audience},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodNone, claims).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	require.NoError(t, err)
+
+	_, err = newTestTokenService(t).ValidateToken(token)
+	assert.Error(t, err)
+}
+
+func TestTokenService_GenerateSessionToken(t *testing.T) {
+	tokens := newTestTokenService(t)
+	sessionID := uuid.New()
+
+	first, err := tokens.GenerateSessionToken(uuid.New(), user.RoleEmployee, sessionID)
+	require.NoError(t, err)
+	second, err := tokens.GenerateSessionToken(uuid.New(), user.RoleEmployee, sessionID)
+	require.NoError(t, err)
+
+	firstClaims, err := tokens.ValidateToken(first)
+	require.NoError(t, err)
+	secondClaims, err := tokens.ValidateToken(second)
+	require.NoError(t, err)
+
+	assert.Equal(t, sessionID, firstClaims.SessionID)
+	assert.NotEmpty(t, firstClaims.ID)
+	assert.NotEqual(t, firstClaims.ID, secondClaims.ID)
+
+	// Токен без сессии не содержит sid
+	token, err := tokens.GenerateToken(uuid.New(), user.RoleEmployee)
+	require.NoError(t, err)
+	claims, err := tokens.ValidateToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, uuid.Nil, claims.SessionID)
+
+	assert.Equal(t, DefaultTokenConfig.TTL+DefaultTokenConfig.ClockSkew, tokens.MaxTokenAge())
+}
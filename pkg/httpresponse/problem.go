@@ -0,0 +1,80 @@
+package httpresponse
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// ContentTypeProblem тип содержимого ответа с ошибкой (RFC 7807)
+const ContentTypeProblem = "application/problem+json"
+
+// ProblemDetails тело ответа с ошибкой в формате RFC 7807.
+// Code — стабильный машиночитаемый код ошибки, по которому клиенты
+// могут ветвиться; Detail — сообщение для человека.
+type ProblemDetails struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	Code     string `json:"code"`
+}
+
+// Problem отправляет ошибку с кодом и сообщением в формате problem+json
+func Problem(w http.ResponseWriter, status int, code, detail string) {
+	WriteProblem(w, ProblemDetails{
+		Status: status,
+		Code:   code,
+		Detail: detail,
+	})
+}
+
+// WriteProblem отправляет подготовленное описание ошибки.
+// Незаполненные Type, Title и Code берутся из статуса ответа.
+func WriteProblem(w http.ResponseWriter, p ProblemDetails) {
+	if p.Type == "" {
+		p.Type = "about:blank"
+	}
+	if p.Title == "" {
+		p.Title = http.StatusText(p.Status)
+	}
+	if p.Code == "" {
+		p.Code = StatusCode(p.Status)
+	}
+
+	w.Header().Set("Content-Type", ContentTypeProblem)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(p.Status)
+	json.NewEncoder(w).Encode(p)
+}
+
+// StatusCode возвращает общий код ошибки для HTTP-статуса
+func StatusCode(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return "invalid_request"
+	case http.StatusUnauthorized:
+		return "unauthorized"
+	case http.StatusForbidden:
+		return "forbidden"
+	case http.StatusNotFound:
+		return "not_found"
+	case http.StatusMethodNotAllowed:
+		return "method_not_allowed"
+	case http.StatusConflict:
+		return "conflict"
+	case http.StatusPreconditionFailed:
+		return "precondition_failed"
+	case http.StatusRequestEntityTooLarge:
+		return "payload_too_large"
+	case http.StatusUnprocessableEntity:
+		return "unprocessable_entity"
+	case http.StatusTooManyRequests:
+		return "too_many_requests"
+	default:
+		if status >= http.StatusInternalServerError {
+			return "internal_error"
+		}
+		return "invalid_request"
+	}
+}
@@ -0,0 +1,41 @@
+package httpresponse
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProblem(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		code     string
+		wantBody string
+	}{
+		{
+			name:     "код домена",
+			status:   http.StatusBadRequest,
+			code:     "reception_already_open",
+			wantBody: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"d","code":"reception_already_open"}`,
+		},
+		{
+			name:     "код по статусу",
+			status:   http.StatusInternalServerError,
+			wantBody: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"d","code":"internal_error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			Problem(recorder, tt.status, tt.code, "d")
+
+			require.Equal(t, tt.status, recorder.Code)
+			require.Equal(t, ContentTypeProblem, recorder.Header().Get("Content-Type"))
+			require.JSONEq(t, tt.wantBody, recorder.Body.String())
+		})
+	}
+}
@@ -14,9 +14,12 @@ func JSON(w http.ResponseWriter, status int, data interface{}) {
 	}
 }
 
-// Error отправляет JSON-ответ с ошибкой
+// Error отправляет ошибку в формате problem+json с общим кодом для статуса
 func Error(w http.ResponseWriter, status int, message string) {
-	JSON(w, status, map[string]string{
-		"error": message,
-	})
+	Problem(w, status, StatusCode(status), message)
+}
+
+// NoStore запрещает кэшировать и сохранять ответ, содержащий токены или секреты
+func NoStore(w http.ResponseWriter) {
+	w.Header().Set("Cache-Control", "no-store")
 }
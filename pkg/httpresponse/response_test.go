@@ -67,19 +67,19 @@ func TestError(t *testing.T) {
 			name:       "error with message",
 			statusCode: http.StatusBadRequest,
 			message:    "invalid input",
-			wantBody:   `{"error":"invalid input"}`,
+			wantBody:   `{"type":"about:blank","title":"Bad Request","status":400,"detail":"invalid input","code":"invalid_request"}`,
 		},
 		{
 			name:       "error with empty message",
 			statusCode: http.StatusInternalServerError,
 			message:    "",
-			wantBody:   `{"error":""}`,
+			wantBody:   `{"type":"about:blank","title":"Internal Server Error","status":500,"code":"internal_error"}`,
 		},
 		{
 			name:       "not found error",
 			statusCode: http.StatusNotFound,
 			message:    "resource not found",
-			wantBody:   `{"error":"resource not found"}`,
+			wantBody:   `{"type":"about:blank","title":"Not Found","status":404,"detail":"resource not found","code":"not_found"}`,
 		},
 	}
 
@@ -89,7 +89,7 @@ func TestError(t *testing.T) {
 			Error(recorder, tt.statusCode, tt.message)
 
 			require.Equal(t, tt.statusCode, recorder.Code)
-			require.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+			require.Equal(t, ContentTypeProblem, recorder.Header().Get("Content-Type"))
 			require.JSONEq(t, tt.wantBody, recorder.Body.String())
 		})
 	}
@@ -5,7 +5,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/avito/pvz/internal/domain/audit"
+	"github.com/avito/pvz/internal/domain/event"
 	domainPVZ "github.com/avito/pvz/internal/domain/pvz"
+	"github.com/avito/pvz/internal/domain/rbac"
 	domainUser "github.com/avito/pvz/internal/domain/user"
 	"github.com/avito/pvz/internal/service/pvz"
 	"github.com/avito/pvz/test/unit/mocks"
@@ -14,38 +17,43 @@ import (
 	"github.com/stretchr/testify/mock"
 )
 
-// MockAuditLog реализует интерфейс audit.AuditLog
-type MockAuditLog struct {
+// MockBus реализует интерфейс event.Bus
+type MockBus struct {
 	mock.Mock
 }
 
-func (m *MockAuditLog) LogPVZCreation(ctx context.Context, pvzID, userID uuid.UUID) error {
-	args := m.Called(ctx, pvzID, userID)
-	return args.Error(0)
+func (m *MockBus) Raise(ctx context.Context, events ...event.Domain) {
+	m.Called(ctx, events)
+}
+
+// moderatorCtx возвращает контекст с разрешениями модератора, как после аутентификации
+func moderatorCtx() context.Context {
+	permissions := rbac.MustNewAuthorizer(rbac.DefaultPolicy).Permissions(domainUser.RoleAdmin)
+	return rbac.WithPermissions(context.Background(), permissions)
 }
 
 func TestPVZService_Create(t *testing.T) {
 	tests := []struct {
 		name    string
 		city    string
-		mock    func(*mocks.MockPVZRepository, *mocks.MockUserRepository, *mocks.MockTransactionManager, *MockAuditLog)
+		mock    func(*mocks.MockPVZRepository, *mocks.MockUserRepository, *mocks.MockTransactionManager, *MockBus)
 		wantErr bool
 	}{
 		{
 			name: "successful creation",
 			city: "Moscow",
-			mock: func(pvzRepo *mocks.MockPVZRepository, userRepo *mocks.MockUserRepository, txManager *mocks.MockTransactionManager, auditLog *MockAuditLog) {
+			mock: func(pvzRepo *mocks.MockPVZRepository, userRepo *mocks.MockUserRepository, txManager *mocks.MockTransactionManager, bus *MockBus) {
 				pvzRepo.On("Create", mock.Anything, mock.AnythingOfType("*domainPVZ.PVZ")).Return(nil)
 				userRepo.On("GetByID", mock.Anything, mock.Anything).Return(&domainUser.User{Role: domainUser.RoleAdmin}, nil)
 				txManager.On("WithinTransaction", mock.Anything, mock.Anything).Return(nil)
-				auditLog.On("LogPVZCreation", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+				bus.On("Raise", mock.Anything, mock.Anything).Return()
 			},
 			wantErr: false,
 		},
 		{
 			name: "empty city",
 			city: "",
-			mock: func(pvzRepo *mocks.MockPVZRepository, userRepo *mocks.MockUserRepository, txManager *mocks.MockTransactionManager, auditLog *MockAuditLog) {
+			mock: func(pvzRepo *mocks.MockPVZRepository, userRepo *mocks.MockUserRepository, txManager *mocks.MockTransactionManager, bus *MockBus) {
 				txManager.On("WithinTransaction", mock.Anything, mock.Anything).Return(pvz.ErrInvalidCity)
 			},
 			wantErr: true,
@@ -53,7 +61,7 @@ func TestPVZService_Create(t *testing.T) {
 		{
 			name: "repository error",
 			city: "Moscow",
-			mock: func(pvzRepo *mocks.MockPVZRepository, userRepo *mocks.MockUserRepository, txManager *mocks.MockTransactionManager, auditLog *MockAuditLog) {
+			mock: func(pvzRepo *mocks.MockPVZRepository, userRepo *mocks.MockUserRepository, txManager *mocks.MockTransactionManager, bus *MockBus) {
 				pvzRepo.On("Create", mock.Anything, mock.AnythingOfType("*domainPVZ.PVZ")).Return(assert.AnError)
 				userRepo.On("GetByID", mock.Anything, mock.Anything).Return(&domainUser.User{Role: domainUser.RoleAdmin}, nil)
 				txManager.On("WithinTransaction", mock.Anything, mock.Anything).Return(assert.AnError)
@@ -67,12 +75,12 @@ func TestPVZService_Create(t *testing.T) {
 			pvzRepo := new(mocks.MockPVZRepository)
 			userRepo := new(mocks.MockUserRepository)
 			txManager := new(mocks.MockTransactionManager)
-			auditLog := new(MockAuditLog)
-			tt.mock(pvzRepo, userRepo, txManager, auditLog)
+			bus := new(MockBus)
+			tt.mock(pvzRepo, userRepo, txManager, bus)
 
 			defaultUser := &domainUser.User{Role: domainUser.RoleAdmin}
-			service := pvz.New(pvzRepo, userRepo, txManager, auditLog, defaultUser)
-			_, err := service.Create(context.Background(), tt.city, uuid.New())
+			service := pvz.New(pvzRepo, userRepo, txManager, bus, audit.Discard, defaultUser)
+			_, err := service.Create(moderatorCtx(), tt.city, uuid.New())
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -94,14 +102,14 @@ func TestPVZService_GetByID(t *testing.T) {
 	tests := []struct {
 		name    string
 		id      uuid.UUID
-		mock    func(*mocks.MockPVZRepository, *mocks.MockUserRepository, *mocks.MockTransactionManager, *MockAuditLog)
+		mock    func(*mocks.MockPVZRepository, *mocks.MockUserRepository, *mocks.MockTransactionManager, *MockBus)
 		want    *domainPVZ.PVZ
 		wantErr bool
 	}{
 		{
 			name: "successful get",
 			id:   id,
-			mock: func(pvzRepo *mocks.MockPVZRepository, userRepo *mocks.MockUserRepository, txManager *mocks.MockTransactionManager, auditLog *MockAuditLog) {
+			mock: func(pvzRepo *mocks.MockPVZRepository, userRepo *mocks.MockUserRepository, txManager *mocks.MockTransactionManager, bus *MockBus) {
 				pvzRepo.On("GetByID", mock.Anything, id).Return(expectedPVZ, nil)
 				userRepo.On("GetByID", mock.Anything, mock.Anything).Return(&domainUser.User{Role: domainUser.RoleAdmin}, nil)
 			},
@@ -111,7 +119,7 @@ func TestPVZService_GetByID(t *testing.T) {
 		{
 			name: "not found",
 			id:   uuid.New(),
-			mock: func(pvzRepo *mocks.MockPVZRepository, userRepo *mocks.MockUserRepository, txManager *mocks.MockTransactionManager, auditLog *MockAuditLog) {
+			mock: func(pvzRepo *mocks.MockPVZRepository, userRepo *mocks.MockUserRepository, txManager *mocks.MockTransactionManager, bus *MockBus) {
 				pvzRepo.On("GetByID", mock.Anything, mock.Anything).Return(nil, domainPVZ.ErrNotFound)
 				userRepo.On("GetByID", mock.Anything, mock.Anything).Return(&domainUser.User{Role: domainUser.RoleAdmin}, nil)
 			},
@@ -125,11 +133,11 @@ func TestPVZService_GetByID(t *testing.T) {
 			pvzRepo := new(mocks.MockPVZRepository)
 			userRepo := new(mocks.MockUserRepository)
 			txManager := new(mocks.MockTransactionManager)
-			auditLog := new(MockAuditLog)
-			tt.mock(pvzRepo, userRepo, txManager, auditLog)
+			bus := new(MockBus)
+			tt.mock(pvzRepo, userRepo, txManager, bus)
 
 			defaultUser := &domainUser.User{Role: domainUser.RoleAdmin}
-			service := pvz.New(pvzRepo, userRepo, txManager, auditLog, defaultUser)
+			service := pvz.New(pvzRepo, userRepo, txManager, bus, audit.Discard, defaultUser)
 			got, err := service.GetByID(context.Background(), tt.id)
 
 			if tt.wantErr {
@@ -153,24 +161,25 @@ func TestPVZService_Update(t *testing.T) {
 	tests := []struct {
 		name    string
 		pvz     *domainPVZ.PVZ
-		mock    func(*mocks.MockPVZRepository, *mocks.MockUserRepository, *mocks.MockTransactionManager, *MockAuditLog)
+		mock    func(*mocks.MockPVZRepository, *mocks.MockUserRepository, *mocks.MockTransactionManager, *MockBus)
 		wantErr bool
 	}{
 		{
 			name: "successful update",
 			pvz:  p,
-			mock: func(pvzRepo *mocks.MockPVZRepository, userRepo *mocks.MockUserRepository, txManager *mocks.MockTransactionManager, auditLog *MockAuditLog) {
+			mock: func(pvzRepo *mocks.MockPVZRepository, userRepo *mocks.MockUserRepository, txManager *mocks.MockTransactionManager, bus *MockBus) {
 				pvzRepo.On("Update", mock.Anything, p).Return(nil)
 				pvzRepo.On("GetByID", mock.Anything, p.ID).Return(p, nil)
 				userRepo.On("GetByID", mock.Anything, mock.Anything).Return(&domainUser.User{Role: domainUser.RoleAdmin}, nil)
 				txManager.On("WithinTransaction", mock.Anything, mock.Anything).Return(nil)
+				bus.On("Raise", mock.Anything, mock.Anything).Return()
 			},
 			wantErr: false,
 		},
 		{
 			name: "not found",
 			pvz:  p,
-			mock: func(pvzRepo *mocks.MockPVZRepository, userRepo *mocks.MockUserRepository, txManager *mocks.MockTransactionManager, auditLog *MockAuditLog) {
+			mock: func(pvzRepo *mocks.MockPVZRepository, userRepo *mocks.MockUserRepository, txManager *mocks.MockTransactionManager, bus *MockBus) {
 				pvzRepo.On("GetByID", mock.Anything, p.ID).Return(nil, domainPVZ.ErrNotFound)
 				userRepo.On("GetByID", mock.Anything, mock.Anything).Return(&domainUser.User{Role: domainUser.RoleAdmin}, nil)
 				txManager.On("WithinTransaction", mock.Anything, mock.Anything).Return(domainPVZ.ErrNotFound)
@@ -184,12 +193,12 @@ func TestPVZService_Update(t *testing.T) {
 			pvzRepo := new(mocks.MockPVZRepository)
 			userRepo := new(mocks.MockUserRepository)
 			txManager := new(mocks.MockTransactionManager)
-			auditLog := new(MockAuditLog)
-			tt.mock(pvzRepo, userRepo, txManager, auditLog)
+			bus := new(MockBus)
+			tt.mock(pvzRepo, userRepo, txManager, bus)
 
 			defaultUser := &domainUser.User{Role: domainUser.RoleAdmin}
-			service := pvz.New(pvzRepo, userRepo, txManager, auditLog, defaultUser)
-			err := service.Update(context.Background(), tt.pvz, uuid.New())
+			service := pvz.New(pvzRepo, userRepo, txManager, bus, audit.Discard, defaultUser)
+			err := service.Update(moderatorCtx(), tt.pvz, uuid.New())
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -206,24 +215,25 @@ func TestPVZService_Delete(t *testing.T) {
 	tests := []struct {
 		name    string
 		id      uuid.UUID
-		mock    func(*mocks.MockPVZRepository, *mocks.MockUserRepository, *mocks.MockTransactionManager, *MockAuditLog)
+		mock    func(*mocks.MockPVZRepository, *mocks.MockUserRepository, *mocks.MockTransactionManager, *MockBus)
 		wantErr bool
 	}{
 		{
 			name: "successful delete",
 			id:   id,
-			mock: func(pvzRepo *mocks.MockPVZRepository, userRepo *mocks.MockUserRepository, txManager *mocks.MockTransactionManager, auditLog *MockAuditLog) {
+			mock: func(pvzRepo *mocks.MockPVZRepository, userRepo *mocks.MockUserRepository, txManager *mocks.MockTransactionManager, bus *MockBus) {
 				pvzRepo.On("Delete", mock.Anything, id).Return(nil)
 				pvzRepo.On("GetByID", mock.Anything, id).Return(&domainPVZ.PVZ{ID: id}, nil)
 				userRepo.On("GetByID", mock.Anything, mock.Anything).Return(&domainUser.User{Role: domainUser.RoleAdmin}, nil)
 				txManager.On("WithinTransaction", mock.Anything, mock.Anything).Return(nil)
+				bus.On("Raise", mock.Anything, mock.Anything).Return()
 			},
 			wantErr: false,
 		},
 		{
 			name: "not found",
 			id:   id,
-			mock: func(pvzRepo *mocks.MockPVZRepository, userRepo *mocks.MockUserRepository, txManager *mocks.MockTransactionManager, auditLog *MockAuditLog) {
+			mock: func(pvzRepo *mocks.MockPVZRepository, userRepo *mocks.MockUserRepository, txManager *mocks.MockTransactionManager, bus *MockBus) {
 				pvzRepo.On("GetByID", mock.Anything, id).Return(nil, domainPVZ.ErrNotFound)
 				userRepo.On("GetByID", mock.Anything, mock.Anything).Return(&domainUser.User{Role: domainUser.RoleAdmin}, nil)
 				txManager.On("WithinTransaction", mock.Anything, mock.Anything).Return(domainPVZ.ErrNotFound)
@@ -237,12 +247,12 @@ func TestPVZService_Delete(t *testing.T) {
 			pvzRepo := new(mocks.MockPVZRepository)
 			userRepo := new(mocks.MockUserRepository)
 			txManager := new(mocks.MockTransactionManager)
-			auditLog := new(MockAuditLog)
-			tt.mock(pvzRepo, userRepo, txManager, auditLog)
+			bus := new(MockBus)
+			tt.mock(pvzRepo, userRepo, txManager, bus)
 
 			defaultUser := &domainUser.User{Role: domainUser.RoleAdmin}
-			service := pvz.New(pvzRepo, userRepo, txManager, auditLog, defaultUser)
-			err := service.Delete(context.Background(), tt.id, uuid.New())
+			service := pvz.New(pvzRepo, userRepo, txManager, bus, audit.Discard, defaultUser)
+			err := service.Delete(moderatorCtx(), tt.id, uuid.New())
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -271,7 +281,7 @@ func TestPVZService_List(t *testing.T) {
 		name    string
 		offset  int
 		limit   int
-		mock    func(*mocks.MockPVZRepository, *mocks.MockUserRepository, *mocks.MockTransactionManager, *MockAuditLog)
+		mock    func(*mocks.MockPVZRepository, *mocks.MockUserRepository, *mocks.MockTransactionManager, *MockBus)
 		want    []*domainPVZ.PVZ
 		wantErr bool
 	}{
@@ -279,7 +289,7 @@ func TestPVZService_List(t *testing.T) {
 			name:   "successful list",
 			offset: 0,
 			limit:  10,
-			mock: func(pvzRepo *mocks.MockPVZRepository, userRepo *mocks.MockUserRepository, txManager *mocks.MockTransactionManager, auditLog *MockAuditLog) {
+			mock: func(pvzRepo *mocks.MockPVZRepository, userRepo *mocks.MockUserRepository, txManager *mocks.MockTransactionManager, bus *MockBus) {
 				pvzRepo.On("List", mock.Anything, 0, 10).Return(expectedPVZs, nil)
 				userRepo.On("GetByID", mock.Anything, mock.Anything).Return(&domainUser.User{Role: domainUser.RoleAdmin}, nil)
 			},
@@ -290,7 +300,7 @@ func TestPVZService_List(t *testing.T) {
 			name:   "repository error",
 			offset: 0,
 			limit:  10,
-			mock: func(pvzRepo *mocks.MockPVZRepository, userRepo *mocks.MockUserRepository, txManager *mocks.MockTransactionManager, auditLog *MockAuditLog) {
+			mock: func(pvzRepo *mocks.MockPVZRepository, userRepo *mocks.MockUserRepository, txManager *mocks.MockTransactionManager, bus *MockBus) {
 				pvzRepo.On("List", mock.Anything, 0, 10).Return([]*domainPVZ.PVZ(nil), assert.AnError)
 				userRepo.On("GetByID", mock.Anything, mock.Anything).Return(&domainUser.User{Role: domainUser.RoleAdmin}, nil)
 			},
@@ -304,11 +314,11 @@ func TestPVZService_List(t *testing.T) {
 			pvzRepo := new(mocks.MockPVZRepository)
 			userRepo := new(mocks.MockUserRepository)
 			txManager := new(mocks.MockTransactionManager)
-			auditLog := new(MockAuditLog)
-			tt.mock(pvzRepo, userRepo, txManager, auditLog)
+			bus := new(MockBus)
+			tt.mock(pvzRepo, userRepo, txManager, bus)
 
 			defaultUser := &domainUser.User{Role: domainUser.RoleAdmin}
-			service := pvz.New(pvzRepo, userRepo, txManager, auditLog, defaultUser)
+			service := pvz.New(pvzRepo, userRepo, txManager, bus, audit.Discard, defaultUser)
 			got, err := service.List(context.Background(), tt.offset, tt.limit)
 
 			if tt.wantErr {